@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/drerr0r/vetbot/internal/api"
+	"github.com/drerr0r/vetbot/internal/handlers"
+	"github.com/drerr0r/vetbot/pkg/utils"
+)
+
+// startAPIServer поднимает публичный JSON API справочника, если задан API_LISTEN_ADDR
+func startAPIServer(config *utils.Config, db handlers.Database) *http.Server {
+	if config.APIListenAddr == "" {
+		return nil
+	}
+	return startHTTPServer("Public API", config.APIListenAddr, api.NewServer(db, config.APICacheMaxAge))
+}
+
+// startAdminAPIServer поднимает админский API управления справочником, если задан ADMIN_API_LISTEN_ADDR
+func startAdminAPIServer(config *utils.Config, mainHandler *handlers.MainHandler) *http.Server {
+	if config.AdminAPIListenAddr == "" {
+		return nil
+	}
+	return startHTTPServer("Admin API", config.AdminAPIListenAddr, mainHandler.AdminAPI(config.AdminAPITokens))
+}
+
+// startHTTPServer запускает HTTP сервер в фоне
+func startHTTPServer(name, addr string, handler http.Handler) *http.Server {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		log.Printf("%s listening on %s", name, addr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Printf("%s server error: %v", name, err)
+		}
+	}()
+
+	return server
+}
+
+// stopAPIServer дожидается завершения текущих запросов к API
+func stopAPIServer(server *http.Server) {
+	if server == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("Error shutting down API server %s: %v", server.Addr, err)
+	}
+}
@@ -1,13 +1,16 @@
 package main
 
 import (
-	"database/sql" // ДОБАВЬТЕ ЭТОТ ИМПОРТ
+	"context"
 	"fmt"
 	"log"
+	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/drerr0r/vetbot/internal/database"
 	"github.com/drerr0r/vetbot/internal/handlers"
@@ -16,6 +19,11 @@ import (
 )
 
 func main() {
+	// Подкоманда управления миграциями: vetbot migrate up|down|status
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrateCommand(os.Args[2:]))
+	}
+
 	// Загружаем конфигурацию
 	config, err := utils.LoadConfig()
 	if err != nil {
@@ -46,100 +54,148 @@ func main() {
 	}
 	defer db.Close()
 
-	// ДОБАВЛЯЕМ: ПРИМЕНЕНИЕ МИГРАЦИЙ ПРИ ЗАПУСКЕ
+	// Применяем встроенные миграции (уже примененные отмечены в schema_migrations)
 	log.Println("🚀 Applying database migrations...")
-	if err := applyMigrations(db.GetDB()); err != nil { // Используем GetDB() который возвращает *sql.DB
-		log.Printf("⚠️ Migration warnings: %v", err)
-		// Не прерываем выполнение для resilience
+	if err := applyMigrations(db); err != nil {
+		log.Fatalf("Error applying migrations: %v", err)
 	}
 
-	// Добавляем отсутствующие колонки в базу данных
-	log.Println("Checking and adding missing database columns...")
-	err = db.AddMissingColumns()
-	if err != nil {
-		log.Printf("Warning: could not add missing columns: %v", err)
-	} else {
-		log.Println("Database columns check completed successfully")
+	// Задачи импорта, прерванные прошлым перезапуском, продолжить нельзя: файлы хранились в памяти
+	if failed, err := db.FailUnfinishedImportRequests(); err != nil {
+		log.Printf("Warning: could not close interrupted import jobs: %v", err)
+	} else if failed > 0 {
+		log.Printf("Marked %d interrupted import jobs as failed", failed)
 	}
 
 	// Создаем адаптер для бота
 	botAdapter := handlers.NewTelegramBotAdapter(bot)
 
+	// Сессии пользователей храним в базе, чтобы незавершенные диалоги переживали перезапуск
+	sessionStorage := database.NewSessionRepository(db.GetDB())
+
 	// Используем адаптер вместо прямого использования bot
-	mainHandler := handlers.NewMainHandler(botAdapter, db, config)
+	mainHandler := handlers.NewMainHandlerWithStorage(botAdapter, db, config, sessionStorage)
 
-	// Настраиваем long polling
-	u := tgbotapi.NewUpdate(0)
-	u.Timeout = 60
+	// При остановке отменяем фоновые импорты: их транзакции откатываются
+	defer mainHandler.StopImportJobs()
 
-	updates := bot.GetUpdatesChan(u)
+	// Фоновая очистка истекших сессий пользователей
+	sweeperCtx, stopSweeper := context.WithCancel(context.Background())
+	defer stopSweeper()
+	mainHandler.StartSessionSweeper(sweeperCtx, config.SessionSweepInterval)
+
+	// Обновления разных чатов обрабатываем параллельно, одного чата - по порядку
+	dispatcher := handlers.NewDispatcher(mainHandler, config.UpdateWorkers, config.UpdateQueueSize)
+
+	// Публичный JSON API для сайта и партнерских приложений (если включен)
+	apiServer := startAPIServer(config, db)
+	defer stopAPIServer(apiServer)
+
+	// Админский API для массовых правок справочника (если включен)
+	adminAPIServer := startAdminAPIServer(config, mainHandler)
+	defer stopAPIServer(adminAPIServer)
 
 	// Обрабатываем сигналы для graceful shutdown
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
-	log.Println("Bot started. Press Ctrl+C to stop.")
+	if config.UpdateMode == utils.UpdateModeWebhook {
+		runWebhook(config, botAdapter, dispatcher, sigChan)
+		return
+	}
+
+	runPolling(bot, botAdapter, dispatcher, sigChan)
+}
+
+// runPolling получает обновления через long polling
+func runPolling(bot *tgbotapi.BotAPI, botAdapter *handlers.TelegramBotAdapter, dispatcher *handlers.Dispatcher, sigChan <-chan os.Signal) {
+	// Если ранее был зарегистрирован webhook, getUpdates вернет ошибку конфликта
+	if err := botAdapter.DeleteWebhook(); err != nil {
+		log.Printf("Warning: could not delete webhook: %v", err)
+	}
+
+	// Настраиваем long polling
+	u := tgbotapi.NewUpdate(0)
+	u.Timeout = 60
+
+	updates := bot.GetUpdatesChan(u)
+
+	log.Println("Bot started in polling mode. Press Ctrl+C to stop.")
 
 	// Основной цикл обработки сообщений
 	for {
 		select {
 		case update := <-updates:
-			// Обрабатываем обновление в той же горутине для сохранения порядка сообщений
-			mainHandler.HandleUpdate(update)
+			// Диспетчер сохраняет порядок сообщений внутри каждого чата
+			dispatcher.Dispatch(update)
 		case <-sigChan:
 			log.Println("Shutting down bot gracefully...")
+			bot.StopReceivingUpdates()
+			log.Printf("Waiting for %d queued updates...", dispatcher.QueueDepth())
+			dispatcher.Stop()
 			return
 		}
 	}
 }
 
-// ДОБАВЛЯЕМ: Функция применения миграций - принимает *sql.DB вместо *database.Database
-func applyMigrations(db *sql.DB) error {
-	log.Println("🔄 Checking for database migrations...")
-
-	// Получаем список файлов миграций в правильном порядке
-	migrationFiles := []string{
-		"migrations/001_init.sql",
-		"migrations/002_add_reviews.sql",
-		// Добавляйте сюда новые миграции по мере их создания
+// runWebhook поднимает HTTP сервер для webhook и регистрирует его в Telegram
+func runWebhook(config *utils.Config, botAdapter *handlers.TelegramBotAdapter, dispatcher *handlers.Dispatcher, sigChan <-chan os.Signal) {
+	webhookURL, err := url.Parse(config.WebhookURL)
+	if err != nil {
+		log.Fatalf("Invalid WEBHOOK_URL: %v", err)
+	}
+	path := webhookURL.Path
+	if path == "" {
+		path = "/"
 	}
 
-	for _, migrationFile := range migrationFiles {
-		// Проверяем существует ли файл
-		if _, err := os.Stat(migrationFile); os.IsNotExist(err) {
-			log.Printf("⚠️ Migration file not found: %s", migrationFile)
-			continue
-		}
+	mux := http.NewServeMux()
+	mux.Handle(path, handlers.NewWebhookHandler(dispatcher, config.WebhookSecret))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"status":"ok","workers":%d,"queue_depth":%d}`, dispatcher.Workers(), dispatcher.QueueDepth())
+	})
+
+	server := &http.Server{
+		Addr:              config.WebhookListenAddr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
 
-		// Читаем SQL из файла
-		sqlContent, err := os.ReadFile(migrationFile)
-		if err != nil {
-			return fmt.Errorf("error reading migration %s: %v", migrationFile, err)
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Printf("Webhook server listening on %s%s", config.WebhookListenAddr, path)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			serverErr <- err
 		}
+	}()
 
-		log.Printf("📝 Applying migration: %s", migrationFile)
+	if err := botAdapter.SetWebhook(config.WebhookURL, config.WebhookSecret); err != nil {
+		log.Fatalf("Error setting webhook: %v", err)
+	}
+	log.Println("Bot started in webhook mode. Press Ctrl+C to stop.")
 
-		// Выполняем SQL - теперь используем *sql.DB.Exec
-		_, err = db.Exec(string(sqlContent))
-		if err != nil {
-			// Игнорируем ошибки "уже существует" для idempotency
-			if contains(err.Error(), "already exists") || contains(err.Error(), "duplicate") || contains(err.Error(), "exists") {
-				log.Printf("ℹ️ Migration already applied (safe to ignore): %s", migrationFile)
-				continue
-			}
-			return fmt.Errorf("error applying migration %s: %v", migrationFile, err)
-		}
+	select {
+	case err := <-serverErr:
+		log.Printf("Webhook server error: %v", err)
+	case <-sigChan:
+		log.Println("Shutting down bot gracefully...")
+	}
 
-		log.Printf("✅ Successfully applied: %s", migrationFile)
+	if err := botAdapter.DeleteWebhook(); err != nil {
+		log.Printf("Warning: could not delete webhook: %v", err)
+	} else {
+		log.Println("Webhook deleted")
 	}
 
-	log.Println("🎉 All migrations completed!")
-	return nil
-}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("Error shutting down webhook server: %v", err)
+	}
 
-// ДОБАВЛЯЕМ: Вспомогательная функция для проверки строки
-func contains(s, substr string) bool {
-	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+	log.Printf("Waiting for %d queued updates...", dispatcher.QueueDepth())
+	dispatcher.Stop()
 }
 
 // maskToken маскирует токен для безопасного логирования
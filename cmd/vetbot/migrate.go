@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/drerr0r/vetbot/internal/database"
+	"github.com/drerr0r/vetbot/migrations"
+)
+
+const migrateUsage = `Usage: vetbot migrate <command>
+
+Commands:
+  up         apply all pending migrations
+  down [N]   revert the last N applied migrations (default 1)
+  status     show applied and pending migrations
+
+DATABASE_URL must be set.`
+
+// runMigrateCommand выполняет подкоманду "vetbot migrate" и возвращает код завершения
+func runMigrateCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		fmt.Fprintln(os.Stderr, "DATABASE_URL is required")
+		return 1
+	}
+
+	db, err := database.New(databaseURL)
+	if err != nil {
+		log.Printf("Error connecting to database: %v", err)
+		return 1
+	}
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db.GetDB(), migrations.FS)
+	if err != nil {
+		log.Printf("Error loading migrations: %v", err)
+		return 1
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up()
+		if err != nil {
+			log.Printf("❌ Migration failed: %v", err)
+			return 1
+		}
+		fmt.Printf("Applied %d migration(s)\n", len(applied))
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				fmt.Fprintf(os.Stderr, "Invalid number of migrations: %s\n", args[1])
+				return 2
+			}
+		}
+		reverted, err := migrator.Down(steps)
+		if err != nil {
+			log.Printf("❌ Rollback failed: %v", err)
+			return 1
+		}
+		fmt.Printf("Reverted %d migration(s)\n", len(reverted))
+
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			log.Printf("Error reading migration status: %v", err)
+			return 1
+		}
+		printMigrationStatus(statuses)
+
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	return 0
+}
+
+// printMigrationStatus выводит таблицу состояния миграций
+func printMigrationStatus(statuses []*database.MigrationStatus) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+
+	for _, status := range statuses {
+		state := "pending"
+		appliedAt := "-"
+		if status.Applied {
+			state = "applied"
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		if status.Changed {
+			state += " (changed)"
+		}
+		if status.Missing {
+			state += " (file missing)"
+		}
+		fmt.Fprintf(w, "%03d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+	}
+
+	w.Flush()
+}
+
+// applyMigrations применяет встроенные миграции при запуске бота
+func applyMigrations(db *database.Database) error {
+	migrator, err := database.NewMigrator(db.GetDB(), migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	applied, err := migrator.Up()
+	if err != nil {
+		return err
+	}
+
+	if len(applied) == 0 {
+		log.Println("Database schema is up to date")
+	} else {
+		log.Printf("🎉 Applied %d migration(s)", len(applied))
+	}
+	return nil
+}
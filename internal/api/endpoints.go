@@ -0,0 +1,381 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/drerr0r/vetbot/internal/models"
+)
+
+// dataResponse ответ без пагинации
+type dataResponse struct {
+	Data interface{} `json:"data"`
+}
+
+// handleSpecializations GET /api/v1/specializations
+func (s *Server) handleSpecializations(w http.ResponseWriter, r *http.Request) {
+	specs, err := s.db.GetAllSpecializations()
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+	sort.SliceStable(specs, func(i, j int) bool { return specs[i].Name < specs[j].Name })
+
+	resources := make([]specializationResource, 0, len(specs))
+	for _, spec := range specs {
+		resources = append(resources, newSpecializationResource(spec))
+	}
+	s.writeJSON(w, r, dataResponse{Data: resources})
+}
+
+// handleSpecialization GET /api/v1/specializations/{id}
+func (s *Server) handleSpecialization(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", "specialization not found")
+		return
+	}
+
+	spec, err := s.db.GetSpecializationByID(id)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && spec == nil) {
+		writeError(w, http.StatusNotFound, "not_found", "specialization not found")
+		return
+	}
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+	s.writeJSON(w, r, newSpecializationResource(spec))
+}
+
+// handleCities GET /api/v1/cities?region=&q=
+func (s *Server) handleCities(w http.ResponseWriter, r *http.Request) {
+	page, err := parsePagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+		return
+	}
+
+	cities, err := s.db.GetAllCities()
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	region := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("region")))
+	query := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("q")))
+
+	var filtered []*models.City
+	for _, city := range cities {
+		if region != "" && strings.ToLower(city.Region) != region {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(city.Name), query) {
+			continue
+		}
+		filtered = append(filtered, city)
+	}
+	sort.SliceStable(filtered, func(i, j int) bool { return filtered[i].Name < filtered[j].Name })
+
+	start, end := page.bounds(len(filtered))
+	resources := make([]*cityResource, 0, end-start)
+	for _, city := range filtered[start:end] {
+		resources = append(resources, newCityResource(city))
+	}
+	s.writeJSON(w, r, listResponse{Data: resources, Pagination: page})
+}
+
+// handleCity GET /api/v1/cities/{id}
+func (s *Server) handleCity(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", "city not found")
+		return
+	}
+
+	city, err := s.db.GetCityByID(id)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && city == nil) {
+		writeError(w, http.StatusNotFound, "not_found", "city not found")
+		return
+	}
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+	s.writeJSON(w, r, newCityResource(city))
+}
+
+// handleClinics GET /api/v1/clinics?city_id=&district=&metro=
+func (s *Server) handleClinics(w http.ResponseWriter, r *http.Request) {
+	page, err := parsePagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+		return
+	}
+	cityID, err := queryInt(r, "city_id")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+		return
+	}
+
+	clinics, err := s.db.GetAllClinicsWithCities()
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	district := strings.TrimSpace(r.URL.Query().Get("district"))
+	metro := strings.TrimSpace(r.URL.Query().Get("metro"))
+
+	var filtered []*models.Clinic
+	for _, clinic := range clinics {
+		if !clinic.IsActive {
+			continue
+		}
+		if cityID > 0 && (!clinic.CityID.Valid || int(clinic.CityID.Int64) != cityID) {
+			continue
+		}
+		if district != "" && !strings.EqualFold(clinic.District.String, district) {
+			continue
+		}
+		if metro != "" && !strings.EqualFold(clinic.MetroStation.String, metro) {
+			continue
+		}
+		filtered = append(filtered, clinic)
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		if filtered[i].Name != filtered[j].Name {
+			return filtered[i].Name < filtered[j].Name
+		}
+		return filtered[i].ID < filtered[j].ID
+	})
+
+	start, end := page.bounds(len(filtered))
+	resources := make([]*clinicResource, 0, end-start)
+	for _, clinic := range filtered[start:end] {
+		resources = append(resources, newClinicResource(clinic))
+	}
+	s.writeJSON(w, r, listResponse{Data: resources, Pagination: page})
+}
+
+// handleClinic GET /api/v1/clinics/{id}
+func (s *Server) handleClinic(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", "clinic not found")
+		return
+	}
+
+	clinic, err := s.db.GetClinicByID(id)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && (clinic == nil || !clinic.IsActive)) {
+		writeError(w, http.StatusNotFound, "not_found", "clinic not found")
+		return
+	}
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	if clinic.City == nil && clinic.CityID.Valid {
+		if city, err := s.db.GetCityByID(int(clinic.CityID.Int64)); err == nil {
+			clinic.City = city
+		}
+	}
+	s.writeJSON(w, r, newClinicResource(clinic))
+}
+
+// parseVetCriteria переводит параметры запроса в models.SearchCriteria
+func parseVetCriteria(r *http.Request) (*models.SearchCriteria, error) {
+	criteria := &models.SearchCriteria{}
+	var err error
+
+	ints := []struct {
+		name   string
+		target *int
+	}{
+		{"specialization_id", &criteria.SpecializationID},
+		{"city_id", &criteria.CityID},
+		{"clinic_id", &criteria.ClinicID},
+		{"day", &criteria.DayOfWeek},
+	}
+	for _, param := range ints {
+		if *param.target, err = queryInt(r, param.name); err != nil {
+			return nil, err
+		}
+	}
+	if criteria.DayOfWeek > 7 {
+		return nil, errors.New(`parameter "day" must be between 1 (Monday) and 7 (Sunday)`)
+	}
+
+	query := r.URL.Query()
+	criteria.CityName = strings.TrimSpace(query.Get("city"))
+	criteria.District = strings.TrimSpace(query.Get("district"))
+	criteria.MetroStation = strings.TrimSpace(query.Get("metro"))
+
+	if clock := strings.TrimSpace(query.Get("time")); clock != "" {
+		if _, err := models.ParseClock(clock); err != nil {
+			return nil, errors.New(`parameter "time" must be in HH:MM format`)
+		}
+		criteria.Time = clock
+	}
+	return criteria, nil
+}
+
+// hasVetCriteria проверяет, задан ли хотя бы один фильтр
+func hasVetCriteria(criteria *models.SearchCriteria) bool {
+	return *criteria != models.SearchCriteria{}
+}
+
+// handleVets GET /api/v1/vets - фильтры как у поиска в боте, q - поиск по ФИО с опечатками
+func (s *Server) handleVets(w http.ResponseWriter, r *http.Request) {
+	page, err := parsePagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+		return
+	}
+	criteria, err := parseVetCriteria(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+		return
+	}
+
+	vets, err := s.findVets(criteria, strings.TrimSpace(r.URL.Query().Get("q")))
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	start, end := page.bounds(len(vets))
+	resources := make([]vetResource, 0, end-start)
+	for _, vet := range vets[start:end] {
+		resources = append(resources, newVetResource(vet, s.reviewStats(vet)))
+	}
+	s.writeJSON(w, r, listResponse{Data: resources, Pagination: page})
+}
+
+// findVets ищет врачей по критериям; при заданном ФИО порядок - по точности совпадения
+func (s *Server) findVets(criteria *models.SearchCriteria, nameQuery string) ([]*models.Veterinarian, error) {
+	if nameQuery == "" {
+		return s.db.SearchVets(criteria)
+	}
+
+	byName, err := s.db.SearchVeterinariansByName(nameQuery, 0)
+	if err != nil || !hasVetCriteria(criteria) {
+		return byName, err
+	}
+
+	matching, err := s.db.SearchVets(criteria)
+	if err != nil {
+		return nil, err
+	}
+	allowed := make(map[int]bool, len(matching))
+	for _, vet := range matching {
+		allowed[models.GetVetIDAsIntOrZero(vet)] = true
+	}
+
+	var vets []*models.Veterinarian
+	for _, vet := range byName {
+		if allowed[models.GetVetIDAsIntOrZero(vet)] {
+			vets = append(vets, vet)
+		}
+	}
+	return vets, nil
+}
+
+// reviewStats возвращает статистику отзывов врача; ошибка не должна ломать выдачу списка
+func (s *Server) reviewStats(vet *models.Veterinarian) *models.ReviewStats {
+	stats, err := s.db.GetReviewStats(models.GetVetIDAsIntOrZero(vet))
+	if err != nil {
+		return nil
+	}
+	return stats
+}
+
+// activeVet загружает активного врача со специализациями и городом
+func (s *Server) activeVet(w http.ResponseWriter, r *http.Request) (*models.Veterinarian, bool) {
+	id, ok := pathID(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", "veterinarian not found")
+		return nil, false
+	}
+
+	vet, err := s.db.GetVeterinarianWithDetails(id)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && (vet == nil || !vet.IsActive)) {
+		writeError(w, http.StatusNotFound, "not_found", "veterinarian not found")
+		return nil, false
+	}
+	if err != nil {
+		writeInternalError(w, r, err)
+		return nil, false
+	}
+	return vet, true
+}
+
+// handleVet GET /api/v1/vets/{id}
+func (s *Server) handleVet(w http.ResponseWriter, r *http.Request) {
+	vet, ok := s.activeVet(w, r)
+	if !ok {
+		return
+	}
+
+	schedules, err := s.db.GetWeekSchedulesByVetID(models.GetVetIDAsIntOrZero(vet))
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	s.writeJSON(w, r, vetDetailResource{
+		vetResource: newVetResource(vet, s.reviewStats(vet)),
+		Schedules:   newScheduleResources(schedules),
+	})
+}
+
+// handleVetSchedules GET /api/v1/vets/{id}/schedules - приемы на ближайшую неделю, как их видит бот
+func (s *Server) handleVetSchedules(w http.ResponseWriter, r *http.Request) {
+	vet, ok := s.activeVet(w, r)
+	if !ok {
+		return
+	}
+
+	schedules, err := s.db.GetWeekSchedulesByVetID(models.GetVetIDAsIntOrZero(vet))
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+	s.writeJSON(w, r, dataResponse{Data: newScheduleResources(schedules)})
+}
+
+// handleVetReviews GET /api/v1/vets/{id}/reviews - только одобренные модератором отзывы
+func (s *Server) handleVetReviews(w http.ResponseWriter, r *http.Request) {
+	page, err := parsePagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+		return
+	}
+
+	vet, ok := s.activeVet(w, r)
+	if !ok {
+		return
+	}
+
+	reviews, err := s.db.GetApprovedReviewsByVet(models.GetVetIDAsIntOrZero(vet))
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	start, end := page.bounds(len(reviews))
+	resources := make([]reviewResource, 0, end-start)
+	for _, review := range reviews[start:end] {
+		resources = append(resources, newReviewResource(review))
+	}
+
+	s.writeJSON(w, r, reviewsResponse{
+		Rating:     newRatingResource(s.reviewStats(vet)),
+		Data:       resources,
+		Pagination: page,
+	})
+}
@@ -0,0 +1,188 @@
+package api
+
+import (
+	"sort"
+	"time"
+
+	"github.com/drerr0r/vetbot/internal/models"
+)
+
+// Представления моделей в API. sql.Null* не отдаются наружу: пустые значения опускаются,
+// служебные поля (is_active, created_at, модерация) не публикуются
+
+type specializationResource struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type cityResource struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Region string `json:"region,omitempty"`
+}
+
+type clinicResource struct {
+	ID           int           `json:"id"`
+	Name         string        `json:"name"`
+	Address      string        `json:"address"`
+	Phone        string        `json:"phone,omitempty"`
+	WorkingHours string        `json:"working_hours,omitempty"`
+	District     string        `json:"district,omitempty"`
+	MetroStation string        `json:"metro_station,omitempty"`
+	Latitude     *float64      `json:"latitude,omitempty"`
+	Longitude    *float64      `json:"longitude,omitempty"`
+	City         *cityResource `json:"city,omitempty"`
+}
+
+type ratingResource struct {
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
+}
+
+type vetResource struct {
+	ID              int                      `json:"id"`
+	FirstName       string                   `json:"first_name"`
+	LastName        string                   `json:"last_name"`
+	Patronymic      string                   `json:"patronymic,omitempty"`
+	Phone           string                   `json:"phone"`
+	Email           string                   `json:"email,omitempty"`
+	Description     string                   `json:"description,omitempty"`
+	ExperienceYears *int64                   `json:"experience_years,omitempty"`
+	City            *cityResource            `json:"city,omitempty"`
+	Specializations []specializationResource `json:"specializations"`
+	Rating          ratingResource           `json:"rating"`
+}
+
+type vetDetailResource struct {
+	vetResource
+	Schedules []scheduleResource `json:"schedules"`
+}
+
+type scheduleResource struct {
+	ID        int             `json:"id"`
+	DayOfWeek int             `json:"day_of_week"`
+	StartTime string          `json:"start_time"`
+	EndTime   string          `json:"end_time"`
+	Clinic    *clinicResource `json:"clinic,omitempty"`
+}
+
+type reviewResource struct {
+	ID        int       `json:"id"`
+	Rating    int       `json:"rating"`
+	Comment   string    `json:"comment,omitempty"`
+	Author    string    `json:"author,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// reviewsResponse одобренные отзывы врача со статистикой
+type reviewsResponse struct {
+	Rating     ratingResource   `json:"rating"`
+	Data       []reviewResource `json:"data"`
+	Pagination pagination       `json:"pagination"`
+}
+
+func newSpecializationResource(spec *models.Specialization) specializationResource {
+	return specializationResource{ID: spec.ID, Name: spec.Name, Description: spec.Description}
+}
+
+func newCityResource(city *models.City) *cityResource {
+	if city == nil {
+		return nil
+	}
+	return &cityResource{ID: city.ID, Name: city.Name, Region: city.Region}
+}
+
+func newClinicResource(clinic *models.Clinic) *clinicResource {
+	if clinic == nil {
+		return nil
+	}
+
+	resource := &clinicResource{
+		ID:           clinic.ID,
+		Name:         clinic.Name,
+		Address:      clinic.Address,
+		Phone:        clinic.Phone.String,
+		WorkingHours: clinic.WorkingHours.String,
+		District:     clinic.District.String,
+		MetroStation: clinic.MetroStation.String,
+		City:         newCityResource(clinic.City),
+	}
+	if clinic.HasCoordinates() {
+		resource.Latitude = &clinic.Latitude.Float64
+		resource.Longitude = &clinic.Longitude.Float64
+	}
+	return resource
+}
+
+func newRatingResource(stats *models.ReviewStats) ratingResource {
+	if stats == nil {
+		return ratingResource{}
+	}
+	return ratingResource{Average: stats.AverageRating, Count: stats.ApprovedReviews}
+}
+
+func newVetResource(vet *models.Veterinarian, stats *models.ReviewStats) vetResource {
+	resource := vetResource{
+		ID:              models.GetVetIDAsIntOrZero(vet),
+		FirstName:       vet.FirstName,
+		LastName:        vet.LastName,
+		Patronymic:      vet.Patronymic.String,
+		Phone:           vet.Phone,
+		Email:           vet.Email.String,
+		Description:     vet.Description.String,
+		City:            newCityResource(vet.City),
+		Specializations: make([]specializationResource, 0, len(vet.Specializations)),
+		Rating:          newRatingResource(stats),
+	}
+	if vet.ExperienceYears.Valid {
+		resource.ExperienceYears = &vet.ExperienceYears.Int64
+	}
+	for _, spec := range vet.Specializations {
+		resource.Specializations = append(resource.Specializations, newSpecializationResource(spec))
+	}
+	return resource
+}
+
+// newScheduleResources отдает только доступные приемы, по дням недели и времени начала.
+// Приемы с другими часами по исключению приходят с ID 0
+func newScheduleResources(schedules []*models.Schedule) []scheduleResource {
+	available := make([]*models.Schedule, 0, len(schedules))
+	for _, schedule := range schedules {
+		if schedule.IsAvailable {
+			available = append(available, schedule)
+		}
+	}
+	sort.SliceStable(available, func(i, j int) bool {
+		if available[i].DayOfWeek != available[j].DayOfWeek {
+			return available[i].DayOfWeek < available[j].DayOfWeek
+		}
+		return available[i].StartTime < available[j].StartTime
+	})
+
+	resources := make([]scheduleResource, 0, len(available))
+	for _, schedule := range available {
+		resources = append(resources, scheduleResource{
+			ID:        schedule.ID,
+			DayOfWeek: schedule.DayOfWeek,
+			StartTime: schedule.StartTime,
+			EndTime:   schedule.EndTime,
+			Clinic:    newClinicResource(schedule.Clinic),
+		})
+	}
+	return resources
+}
+
+// newReviewResource публикует только имя автора, без фамилии и Telegram данных
+func newReviewResource(review *models.Review) reviewResource {
+	resource := reviewResource{
+		ID:        review.ID,
+		Rating:    review.Rating,
+		Comment:   review.Comment,
+		CreatedAt: review.CreatedAt,
+	}
+	if review.User != nil {
+		resource.Author = review.User.FirstName
+	}
+	return resource
+}
@@ -0,0 +1,215 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drerr0r/vetbot/internal/handlers"
+)
+
+// Параметры постраничной выдачи
+const (
+	defaultPerPage = 20
+	maxPerPage     = 100
+)
+
+// Server публичный read-only API справочника врачей поверх handlers.Database
+type Server struct {
+	db     handlers.Database
+	maxAge time.Duration
+	mux    *http.ServeMux
+}
+
+// NewServer создает API сервер. maxAge - сколько клиенты и прокси могут кэшировать ответы
+func NewServer(db handlers.Database, maxAge time.Duration) *Server {
+	s := &Server{
+		db:     db,
+		maxAge: maxAge,
+		mux:    http.NewServeMux(),
+	}
+	s.routes()
+	return s
+}
+
+// routes регистрирует обработчики API. GET-шаблоны обслуживают и HEAD
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /api/v1/openapi.json", s.handleOpenAPI)
+
+	s.mux.HandleFunc("GET /api/v1/specializations", s.handleSpecializations)
+	s.mux.HandleFunc("GET /api/v1/specializations/{id}", s.handleSpecialization)
+	s.mux.HandleFunc("GET /api/v1/cities", s.handleCities)
+	s.mux.HandleFunc("GET /api/v1/cities/{id}", s.handleCity)
+	s.mux.HandleFunc("GET /api/v1/clinics", s.handleClinics)
+	s.mux.HandleFunc("GET /api/v1/clinics/{id}", s.handleClinic)
+	s.mux.HandleFunc("GET /api/v1/vets", s.handleVets)
+	s.mux.HandleFunc("GET /api/v1/vets/{id}", s.handleVet)
+	s.mux.HandleFunc("GET /api/v1/vets/{id}/schedules", s.handleVetSchedules)
+	s.mux.HandleFunc("GET /api/v1/vets/{id}/reviews", s.handleVetReviews)
+
+	// Остальные методы получают 405 от ServeMux, так как путь совпадает только с GET-шаблонами
+	s.mux.HandleFunc("GET /api/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "not_found", "unknown endpoint")
+	})
+}
+
+// ServeHTTP добавляет CORS заголовки (API читают сайт и мобильное приложение) и передает запрос в роутер
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Expose-Headers", "ETag")
+
+	if r.Method == http.MethodOptions {
+		w.Header().Set("Access-Control-Allow-Methods", "GET, HEAD, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "If-None-Match")
+		w.Header().Set("Access-Control-Max-Age", "86400")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+// errorResponse тело ответа с ошибкой
+type errorResponse struct {
+	Error errorBody `json:"error"`
+}
+
+type errorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// writeError отправляет ошибку в JSON. Ошибки не кэшируются
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorResponse{Error: errorBody{Code: code, Message: message}})
+}
+
+// writeInternalError логирует ошибку базы данных и отвечает 500 без подробностей
+func writeInternalError(w http.ResponseWriter, r *http.Request, err error) {
+	log.Printf("API error on %s: %v", r.URL.Path, err)
+	writeError(w, http.StatusInternalServerError, "internal_error", "internal server error")
+}
+
+// writeJSON отправляет ответ с ETag от содержимого. Если клиент прислал тот же ETag
+// в If-None-Match, тело не передается и возвращается 304
+func (s *Server) writeJSON(w http.ResponseWriter, r *http.Request, value interface{}) {
+	body, err := json.Marshal(value)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(s.maxAge.Seconds())))
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		w.Write(body)
+	}
+}
+
+// etagMatches проверяет заголовок If-None-Match: список ETag через запятую, слабые W/ или *
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// pathID читает числовой идентификатор из пути запроса
+func pathID(r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
+
+// queryInt читает положительное целое из параметра запроса (0 - параметр не задан)
+func queryInt(r *http.Request, name string) (int, error) {
+	raw := strings.TrimSpace(r.URL.Query().Get(name))
+	if raw == "" {
+		return 0, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("parameter %q must be a positive integer", name)
+	}
+	return value, nil
+}
+
+// pagination сведения о странице в ответе списка
+type pagination struct {
+	Page       int `json:"page"`
+	PerPage    int `json:"per_page"`
+	Total      int `json:"total"`
+	TotalPages int `json:"total_pages"`
+}
+
+// listResponse ответ со списком и пагинацией
+type listResponse struct {
+	Data       interface{} `json:"data"`
+	Pagination pagination  `json:"pagination"`
+}
+
+// parsePagination читает page (с 1) и per_page из запроса
+func parsePagination(r *http.Request) (pagination, error) {
+	page, err := queryInt(r, "page")
+	if err != nil {
+		return pagination{}, err
+	}
+	perPage, err := queryInt(r, "per_page")
+	if err != nil {
+		return pagination{}, err
+	}
+
+	if page == 0 {
+		page = 1
+	}
+	if perPage == 0 {
+		perPage = defaultPerPage
+	}
+	if perPage > maxPerPage {
+		return pagination{}, fmt.Errorf("parameter \"per_page\" must not exceed %d", maxPerPage)
+	}
+	return pagination{Page: page, PerPage: perPage}, nil
+}
+
+// bounds заполняет итоги пагинации для total элементов и возвращает границы текущей страницы
+func (p *pagination) bounds(total int) (int, int) {
+	p.Total = total
+	p.TotalPages = (total + p.PerPage - 1) / p.PerPage
+
+	// Страница дальше последней пустая; сравнение до умножения, чтобы огромный page не переполнил смещение
+	start := total
+	if p.Page-1 <= total/p.PerPage {
+		start = min((p.Page-1)*p.PerPage, total)
+	}
+	end := min(start+p.PerPage, total)
+	return start, end
+}
@@ -0,0 +1,344 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/drerr0r/vetbot/internal/handlers"
+	"github.com/drerr0r/vetbot/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupTestServer создает API поверх мока с двумя городами, клиникой и двумя врачами
+func setupTestServer() (*Server, *handlers.MockDatabase) {
+	mockDB := handlers.NewMockDatabase()
+
+	kazan := &models.City{ID: 1, Name: "Казань", Region: "Татарстан"}
+	moscow := &models.City{ID: 2, Name: "Москва", Region: "Москва"}
+	mockDB.Cities[1] = kazan
+	mockDB.Cities[2] = moscow
+
+	surgeon := &models.Specialization{ID: 1, Name: "Хирург"}
+	therapist := &models.Specialization{ID: 2, Name: "Терапевт"}
+	mockDB.Specializations[1] = surgeon
+	mockDB.Specializations[2] = therapist
+
+	mockDB.Clinics[1] = &models.Clinic{
+		ID: 1, Name: "ВетЦентр", Address: "ул. Баумана, 1", IsActive: true,
+		CityID:   sql.NullInt64{Int64: 1, Valid: true},
+		District: sql.NullString{String: "Вахитовский", Valid: true},
+		Latitude: sql.NullFloat64{Float64: 55.79, Valid: true}, Longitude: sql.NullFloat64{Float64: 49.12, Valid: true},
+	}
+	mockDB.Clinics[2] = &models.Clinic{ID: 2, Name: "Закрытая", IsActive: false}
+
+	mockDB.Veterinarians[1] = &models.Veterinarian{
+		ID: sql.NullInt64{Int64: 1, Valid: true}, FirstName: "Айдар", LastName: "Галиев", Phone: "+79001",
+		ExperienceYears: sql.NullInt64{Int64: 12, Valid: true}, IsActive: true,
+		CityID: sql.NullInt64{Int64: 1, Valid: true}, City: kazan,
+		Specializations: []*models.Specialization{surgeon},
+	}
+	mockDB.Veterinarians[2] = &models.Veterinarian{
+		ID: sql.NullInt64{Int64: 2, Valid: true}, FirstName: "Иван", LastName: "Петров", Phone: "+79002",
+		IsActive: true, CityID: sql.NullInt64{Int64: 2, Valid: true}, City: moscow,
+		Specializations: []*models.Specialization{therapist},
+	}
+
+	mockDB.Schedules[1] = &models.Schedule{
+		ID: 1, VetID: 1, ClinicID: 1, DayOfWeek: 6, StartTime: "10:00", EndTime: "16:00",
+		IsAvailable: true, Clinic: mockDB.Clinics[1],
+	}
+	mockDB.Schedules[2] = &models.Schedule{
+		ID: 2, VetID: 1, ClinicID: 1, DayOfWeek: 2, StartTime: "09:00", EndTime: "18:00",
+		IsAvailable: true, Clinic: mockDB.Clinics[1],
+	}
+
+	mockDB.GetReviewStatsFunc = func(vetID int) (*models.ReviewStats, error) {
+		if vetID == 1 {
+			return &models.ReviewStats{VeterinarianID: 1, AverageRating: 4.5, ApprovedReviews: 2}, nil
+		}
+		return &models.ReviewStats{VeterinarianID: vetID}, nil
+	}
+	mockDB.GetApprovedReviewsByVetFunc = func(vetID int) ([]*models.Review, error) {
+		if vetID != 1 {
+			return nil, nil
+		}
+		return []*models.Review{
+			{ID: 2, Rating: 5, Comment: "Спас кота", User: &models.User{FirstName: "Анна", LastName: "Секретная"}},
+			{ID: 1, Rating: 4, Comment: "Хорошо"},
+		}, nil
+	}
+
+	return NewServer(mockDB, time.Minute), mockDB
+}
+
+// doGet выполняет GET запрос к серверу и декодирует JSON ответ в target (если он не nil)
+func doGet(t *testing.T, server *Server, target string, headers map[string]string, out interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+
+	if out != nil && rec.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), out))
+	}
+	return rec
+}
+
+type vetListBody struct {
+	Data       []vetResource `json:"data"`
+	Pagination pagination    `json:"pagination"`
+}
+
+func TestVetsEndpoint(t *testing.T) {
+	server, _ := setupTestServer()
+
+	t.Run("Lists vets with rating", func(t *testing.T) {
+		var body vetListBody
+		rec := doGet(t, server, "/api/v1/vets", nil, &body)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Len(t, body.Data, 2)
+		assert.Equal(t, "Айдар", body.Data[0].FirstName)
+		assert.Equal(t, 4.5, body.Data[0].Rating.Average)
+		assert.Equal(t, "Хирург", body.Data[0].Specializations[0].Name)
+		assert.Equal(t, pagination{Page: 1, PerPage: defaultPerPage, Total: 2, TotalPages: 1}, body.Pagination)
+		assert.NotContains(t, rec.Body.String(), "Valid", "sql.Null* types must not leak into JSON")
+	})
+
+	t.Run("Filters like SearchCriteria", func(t *testing.T) {
+		var body vetListBody
+		doGet(t, server, "/api/v1/vets?city_id=1&day=6&time=12:00&district=вахитовский", nil, &body)
+		require.Len(t, body.Data, 1)
+		assert.Equal(t, 1, body.Data[0].ID)
+
+		doGet(t, server, "/api/v1/vets?specialization_id=2", nil, &body)
+		require.Len(t, body.Data, 1)
+		assert.Equal(t, "Петров", body.Data[0].LastName)
+
+		doGet(t, server, "/api/v1/vets?day=6&time=18:00", nil, &body)
+		assert.Empty(t, body.Data)
+	})
+
+	t.Run("Name search combined with filters", func(t *testing.T) {
+		var body vetListBody
+		doGet(t, server, "/api/v1/vets?q=Galiev", nil, &body)
+		require.Len(t, body.Data, 1)
+		assert.Equal(t, 1, body.Data[0].ID)
+
+		doGet(t, server, "/api/v1/vets?q=Галиев&city_id=2", nil, &body)
+		assert.Empty(t, body.Data)
+	})
+
+	t.Run("Pagination", func(t *testing.T) {
+		var body vetListBody
+		doGet(t, server, "/api/v1/vets?per_page=1&page=2", nil, &body)
+		require.Len(t, body.Data, 1)
+		assert.Equal(t, "Иван", body.Data[0].FirstName)
+		assert.Equal(t, 2, body.Pagination.TotalPages)
+
+		doGet(t, server, "/api/v1/vets?per_page=1&page=5", nil, &body)
+		assert.Empty(t, body.Data)
+
+		rec := doGet(t, server, "/api/v1/vets?per_page=100&page=92233720368547760", nil, &body)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, body.Data)
+		assert.Equal(t, 2, body.Pagination.Total)
+	})
+
+	t.Run("Invalid parameters", func(t *testing.T) {
+		for _, target := range []string{
+			"/api/v1/vets?day=8",
+			"/api/v1/vets?time=25:00",
+			"/api/v1/vets?city_id=abc",
+			"/api/v1/vets?per_page=1000",
+			"/api/v1/vets?page=0",
+		} {
+			rec := doGet(t, server, target, nil, nil)
+			assert.Equal(t, http.StatusBadRequest, rec.Code, target)
+			assert.Contains(t, rec.Body.String(), "invalid_parameter", target)
+		}
+	})
+}
+
+func TestVetDetailEndpoints(t *testing.T) {
+	server, mockDB := setupTestServer()
+	mockDB.Veterinarians[3] = &models.Veterinarian{
+		ID: sql.NullInt64{Int64: 3, Valid: true}, FirstName: "Олег", LastName: "Уволенный", Phone: "+79003",
+		IsActive: false,
+	}
+
+	t.Run("Vet with sorted schedule", func(t *testing.T) {
+		var body vetDetailResource
+		rec := doGet(t, server, "/api/v1/vets/1", nil, &body)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "Галиев", body.LastName)
+		require.NotNil(t, body.ExperienceYears)
+		assert.Equal(t, int64(12), *body.ExperienceYears)
+		require.Len(t, body.Schedules, 2)
+		assert.Equal(t, 2, body.Schedules[0].DayOfWeek)
+		assert.Equal(t, "ВетЦентр", body.Schedules[0].Clinic.Name)
+		require.NotNil(t, body.Schedules[0].Clinic.Latitude)
+	})
+
+	t.Run("Inactive and unknown vets are not found", func(t *testing.T) {
+		for _, target := range []string{"/api/v1/vets/3", "/api/v1/vets/99", "/api/v1/vets/abc", "/api/v1/vets/3/reviews"} {
+			rec := doGet(t, server, target, nil, nil)
+			assert.Equal(t, http.StatusNotFound, rec.Code, target)
+		}
+	})
+
+	t.Run("Reviews expose only the author's first name", func(t *testing.T) {
+		var body reviewsResponse
+		rec := doGet(t, server, "/api/v1/vets/1/reviews", nil, &body)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, ratingResource{Average: 4.5, Count: 2}, body.Rating)
+		require.Len(t, body.Data, 2)
+		assert.Equal(t, "Анна", body.Data[0].Author)
+		assert.NotContains(t, rec.Body.String(), "Секретная")
+	})
+
+	t.Run("Schedules", func(t *testing.T) {
+		var body struct {
+			Data []scheduleResource `json:"data"`
+		}
+		doGet(t, server, "/api/v1/vets/1/schedules", nil, &body)
+		assert.Len(t, body.Data, 2)
+	})
+
+	t.Run("Schedules skip inactive clinics and apply exceptions", func(t *testing.T) {
+		server, mockDB := setupTestServer()
+		mockDB.Schedules[3] = &models.Schedule{
+			ID: 3, VetID: 1, ClinicID: 2, DayOfWeek: 4, StartTime: "09:00", EndTime: "12:00", IsAvailable: true,
+		}
+
+		// Ближайшие субботу и вторник по местному времени клиники
+		now := time.Now().In(models.DefaultLocation())
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		nextDate := func(day int) time.Time {
+			return today.AddDate(0, 0, (day-models.ScheduleDay(today)+7)%7)
+		}
+		saturday, tuesday := nextDate(6), nextDate(2)
+		require.NoError(t, mockDB.CreateScheduleException(&models.ScheduleException{
+			VetID: 1, DateFrom: saturday, DateTo: saturday, Reason: "Отпуск",
+		}))
+		require.NoError(t, mockDB.CreateScheduleException(&models.ScheduleException{
+			VetID: 1, ClinicID: sql.NullInt64{Int64: 1, Valid: true}, DateFrom: tuesday, DateTo: tuesday,
+			IsAvailable: true, StartTime: "12:00", EndTime: "15:00",
+		}))
+
+		var body struct {
+			Data []scheduleResource `json:"data"`
+		}
+		doGet(t, server, "/api/v1/vets/1/schedules", nil, &body)
+		require.Len(t, body.Data, 1)
+		assert.Equal(t, 2, body.Data[0].DayOfWeek)
+		assert.Equal(t, "12:00", body.Data[0].StartTime)
+		assert.Equal(t, 0, body.Data[0].ID)
+	})
+}
+
+func TestDirectoryEndpoints(t *testing.T) {
+	server, _ := setupTestServer()
+
+	t.Run("Clinics skip inactive ones", func(t *testing.T) {
+		var body struct {
+			Data []clinicResource `json:"data"`
+		}
+		doGet(t, server, "/api/v1/clinics", nil, &body)
+		require.Len(t, body.Data, 1)
+		assert.Equal(t, "Казань", body.Data[0].City.Name)
+
+		doGet(t, server, "/api/v1/clinics?city_id=2", nil, &body)
+		assert.Empty(t, body.Data)
+
+		assert.Equal(t, http.StatusNotFound, doGet(t, server, "/api/v1/clinics/2", nil, nil).Code)
+	})
+
+	t.Run("Cities filtered by region", func(t *testing.T) {
+		var body struct {
+			Data []cityResource `json:"data"`
+		}
+		doGet(t, server, "/api/v1/cities?region=татарстан", nil, &body)
+		require.Len(t, body.Data, 1)
+		assert.Equal(t, "Казань", body.Data[0].Name)
+	})
+
+	t.Run("Specializations sorted by name", func(t *testing.T) {
+		var body struct {
+			Data []specializationResource `json:"data"`
+		}
+		doGet(t, server, "/api/v1/specializations", nil, &body)
+		require.Len(t, body.Data, 2)
+		assert.Equal(t, "Терапевт", body.Data[0].Name)
+
+		assert.Equal(t, http.StatusNotFound, doGet(t, server, "/api/v1/specializations/9", nil, nil).Code)
+	})
+
+	t.Run("Unknown endpoint", func(t *testing.T) {
+		rec := doGet(t, server, "/api/v1/owners", nil, nil)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Contains(t, rec.Body.String(), "not_found")
+	})
+}
+
+func TestETagCaching(t *testing.T) {
+	server, mockDB := setupTestServer()
+
+	first := doGet(t, server, "/api/v1/vets", nil, nil)
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Equal(t, "public, max-age=60", first.Header().Get("Cache-Control"))
+
+	// Ответ не зависит от порядка обхода map в моке
+	assert.Equal(t, etag, doGet(t, server, "/api/v1/vets", nil, nil).Header().Get("ETag"))
+
+	notModified := doGet(t, server, "/api/v1/vets", map[string]string{"If-None-Match": `"other", ` + etag}, nil)
+	assert.Equal(t, http.StatusNotModified, notModified.Code)
+	assert.Empty(t, notModified.Body.String())
+
+	mockDB.Veterinarians[2].Phone = "+79999"
+	changed := doGet(t, server, "/api/v1/vets", map[string]string{"If-None-Match": etag}, nil)
+	assert.Equal(t, http.StatusOK, changed.Code)
+	assert.NotEqual(t, etag, changed.Header().Get("ETag"))
+}
+
+func TestCORSAndOpenAPI(t *testing.T) {
+	server, _ := setupTestServer()
+
+	req := httptest.NewRequest(http.MethodOptions, "/api/v1/vets", nil)
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "*", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Contains(t, rec.Header().Get("Access-Control-Allow-Headers"), "If-None-Match")
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/vets", nil)
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	var spec struct {
+		OpenAPI string                 `json:"openapi"`
+		Paths   map[string]interface{} `json:"paths"`
+	}
+	rec = doGet(t, server, "/api/v1/openapi.json", nil, &spec)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "3.0.3", spec.OpenAPI)
+	for _, path := range []string{
+		"/specializations", "/specializations/{id}", "/cities", "/cities/{id}", "/clinics", "/clinics/{id}",
+		"/vets", "/vets/{id}", "/vets/{id}/schedules", "/vets/{id}/reviews",
+	} {
+		assert.Contains(t, spec.Paths, path)
+	}
+}
@@ -0,0 +1,168 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/drerr0r/vetbot/internal/models"
+	"github.com/lib/pq"
+)
+
+// uniqueViolationCode - код ошибки PostgreSQL при нарушении уникального индекса
+const uniqueViolationCode = "23505"
+
+// AppointmentRepository содержит методы для работы с записями на прием
+type AppointmentRepository struct {
+	db *sql.DB
+}
+
+// NewAppointmentRepository создает новый репозиторий записей на прием
+func NewAppointmentRepository(db *sql.DB) *AppointmentRepository {
+	return &AppointmentRepository{db: db}
+}
+
+// CreateAppointment создает запись на прием.
+// Если слот уже занят, возвращает models.ErrAppointmentSlotTaken
+func (r *AppointmentRepository) CreateAppointment(appointment *models.Appointment) error {
+	query := `INSERT INTO appointments (user_id, vet_id, clinic_id, appointment_date, start_time, end_time, status, created_at)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`
+
+	if appointment.Status == "" {
+		appointment.Status = models.AppointmentStatusBooked
+	}
+	if appointment.CreatedAt.IsZero() {
+		appointment.CreatedAt = time.Now()
+	}
+
+	log.Printf("Creating appointment: vet_id=%d, clinic_id=%d, user_id=%d, date=%s, time=%s",
+		appointment.VetID, appointment.ClinicID, appointment.UserID,
+		appointment.Date.Format("2006-01-02"), appointment.StartTime)
+
+	err := r.db.QueryRow(query,
+		appointment.UserID,
+		appointment.VetID,
+		appointment.ClinicID,
+		appointment.Date.Format("2006-01-02"),
+		appointment.StartTime,
+		appointment.EndTime,
+		appointment.Status,
+		appointment.CreatedAt,
+	).Scan(&appointment.ID)
+
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolationCode {
+			return models.ErrAppointmentSlotTaken
+		}
+		return fmt.Errorf("ошибка создания записи на прием: %v", err)
+	}
+
+	return nil
+}
+
+// GetBookedAppointments возвращает активные записи врача на указанную дату
+func (r *AppointmentRepository) GetBookedAppointments(vetID int, date time.Time) ([]*models.Appointment, error) {
+	query := `
+		SELECT id, user_id, vet_id, clinic_id, appointment_date,
+		       TO_CHAR(start_time, 'HH24:MI'), TO_CHAR(end_time, 'HH24:MI'),
+		       status, created_at
+		FROM appointments
+		WHERE vet_id = $1 AND appointment_date = $2 AND status = 'booked'
+		ORDER BY start_time`
+
+	rows, err := r.db.Query(query, vetID, date.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var appointments []*models.Appointment
+	for rows.Next() {
+		var appointment models.Appointment
+		err := rows.Scan(
+			&appointment.ID, &appointment.UserID, &appointment.VetID, &appointment.ClinicID,
+			&appointment.Date, &appointment.StartTime, &appointment.EndTime,
+			&appointment.Status, &appointment.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		appointments = append(appointments, &appointment)
+	}
+
+	return appointments, nil
+}
+
+// GetUpcomingAppointmentsByUser возвращает предстоящие записи пользователя. Запись предстоит,
+// если ее дата не раньше даты now по местному времени города клиники
+func (r *AppointmentRepository) GetUpcomingAppointmentsByUser(userID int, now time.Time) ([]*models.Appointment, error) {
+	query := `
+		SELECT a.id, a.user_id, a.vet_id, a.clinic_id, a.appointment_date,
+		       TO_CHAR(a.start_time, 'HH24:MI'), TO_CHAR(a.end_time, 'HH24:MI'),
+		       a.status, a.created_at,
+		       v.first_name, v.last_name, v.phone,
+		       c.name, c.address
+		FROM appointments a
+		JOIN veterinarians v ON a.vet_id = v.id
+		JOIN clinics c ON a.clinic_id = c.id
+		LEFT JOIN cities ct ON c.city_id = ct.id
+		WHERE a.user_id = $1 AND a.status = 'booked'
+		  AND a.appointment_date >= ($2::timestamptz AT TIME ZONE COALESCE(ct.timezone, $3))::date
+		ORDER BY a.appointment_date, a.start_time`
+
+	rows, err := r.db.Query(query, userID, now, models.DefaultTimezone)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var appointments []*models.Appointment
+	for rows.Next() {
+		var appointment models.Appointment
+		var vet models.Veterinarian
+		var clinic models.Clinic
+
+		err := rows.Scan(
+			&appointment.ID, &appointment.UserID, &appointment.VetID, &appointment.ClinicID,
+			&appointment.Date, &appointment.StartTime, &appointment.EndTime,
+			&appointment.Status, &appointment.CreatedAt,
+			&vet.FirstName, &vet.LastName, &vet.Phone,
+			&clinic.Name, &clinic.Address,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		vet.ID = sql.NullInt64{Int64: int64(appointment.VetID), Valid: true}
+		clinic.ID = appointment.ClinicID
+		appointment.Vet = &vet
+		appointment.Clinic = &clinic
+		appointments = append(appointments, &appointment)
+	}
+
+	return appointments, nil
+}
+
+// CancelAppointment отменяет запись пользователя на прием
+func (r *AppointmentRepository) CancelAppointment(appointmentID int, userID int) error {
+	query := `UPDATE appointments SET status = 'cancelled', cancelled_at = $1
+              WHERE id = $2 AND user_id = $3 AND status = 'booked'`
+
+	result, err := r.db.Exec(query, time.Now(), appointmentID, userID)
+	if err != nil {
+		return fmt.Errorf("ошибка отмены записи: %v", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
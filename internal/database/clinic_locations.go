@@ -0,0 +1,119 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/drerr0r/vetbot/internal/models"
+)
+
+// ClinicLocationRepository содержит методы для поиска клиник по координатам
+type ClinicLocationRepository struct {
+	db *sql.DB
+}
+
+// NewClinicLocationRepository создает новый репозиторий геопоиска клиник
+func NewClinicLocationRepository(db *sql.DB) *ClinicLocationRepository {
+	return &ClinicLocationRepository{db: db}
+}
+
+// FindNearestClinics возвращает активные клиники с координатами, ближайшие к точке, по возрастанию расстояния
+func (r *ClinicLocationRepository) FindNearestClinics(latitude, longitude float64, limit int) ([]*models.ClinicDistance, error) {
+	// Расстояние по формуле гаверсинусов, как в models.DistanceKm
+	query := `
+		SELECT id, name, address, phone, working_hours, is_active, city_id, district, metro_station,
+		       latitude, longitude, created_at, city_name, city_timezone, distance_km
+		FROM (
+			SELECT c.*, COALESCE(ct.name, '') AS city_name, COALESCE(ct.timezone, '') AS city_timezone,
+			       2 * 6371.0 * ASIN(LEAST(1.0, SQRT(
+			           POWER(SIN(RADIANS(c.latitude - $1) / 2), 2) +
+			           COS(RADIANS($1)) * COS(RADIANS(c.latitude)) *
+			           POWER(SIN(RADIANS(c.longitude - $2) / 2), 2)
+			       ))) AS distance_km
+			FROM clinics c
+			LEFT JOIN cities ct ON c.city_id = ct.id
+			WHERE c.is_active = true AND c.latitude IS NOT NULL AND c.longitude IS NOT NULL
+		) nearest
+		ORDER BY distance_km, name
+		LIMIT $3`
+
+	rows, err := r.db.Query(query, latitude, longitude, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка поиска ближайших клиник: %v", err)
+	}
+	defer rows.Close()
+
+	var result []*models.ClinicDistance
+	for rows.Next() {
+		var clinic models.Clinic
+		var cityName, cityTimezone string
+		var distance float64
+		err := rows.Scan(&clinic.ID, &clinic.Name, &clinic.Address, &clinic.Phone,
+			&clinic.WorkingHours, &clinic.IsActive, &clinic.CityID, &clinic.District,
+			&clinic.MetroStation, &clinic.Latitude, &clinic.Longitude, &clinic.CreatedAt,
+			&cityName, &cityTimezone, &distance)
+		if err != nil {
+			return nil, err
+		}
+		if clinic.CityID.Valid {
+			clinic.City = &models.City{ID: int(clinic.CityID.Int64), Name: cityName, Timezone: cityTimezone}
+		}
+		result = append(result, &models.ClinicDistance{Clinic: &clinic, DistanceKm: distance})
+	}
+
+	return result, rows.Err()
+}
+
+// GetClinicSchedulesByDate возвращает приемы активных врачей клиники на дату с учетом исключений
+func (r *ClinicLocationRepository) GetClinicSchedulesByDate(clinicID int, date time.Time) ([]*models.Schedule, error) {
+	query := `
+		SELECT s.id, s.vet_id, s.clinic_id, s.day_of_week,
+		       TO_CHAR(s.start_time, 'HH24:MI'), TO_CHAR(s.end_time, 'HH24:MI'),
+		       s.is_available, s.created_at,
+		       v.id, v.first_name, v.last_name, v.patronymic, v.phone, v.is_active
+		FROM ` + effectiveSchedulesQuery("$2", 0) + ` s
+		JOIN veterinarians v ON s.vet_id = v.id
+		WHERE s.clinic_id = $1 AND s.work_date = $2::date AND v.is_active = true
+		ORDER BY s.start_time, v.last_name, v.first_name`
+
+	rows, err := r.db.Query(query, clinicID, date.Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения расписания клиники: %v", err)
+	}
+	defer rows.Close()
+
+	var schedules []*models.Schedule
+	for rows.Next() {
+		var schedule models.Schedule
+		var vet models.Veterinarian
+		err := rows.Scan(&schedule.ID, &schedule.VetID, &schedule.ClinicID, &schedule.DayOfWeek,
+			&schedule.StartTime, &schedule.EndTime, &schedule.IsAvailable, &schedule.CreatedAt,
+			&vet.ID, &vet.FirstName, &vet.LastName, &vet.Patronymic, &vet.Phone, &vet.IsActive)
+		if err != nil {
+			return nil, err
+		}
+		schedule.Vet = &vet
+		schedules = append(schedules, &schedule)
+	}
+
+	return schedules, rows.Err()
+}
+
+// UpdateClinicCoordinates задает координаты клиники; невалидные значения очищают их
+func (r *ClinicLocationRepository) UpdateClinicCoordinates(clinicID int, latitude, longitude sql.NullFloat64) error {
+	result, err := r.db.Exec(`UPDATE clinics SET latitude = $1, longitude = $2 WHERE id = $3`,
+		latitude, longitude, clinicID)
+	if err != nil {
+		return fmt.Errorf("ошибка обновления координат клиники: %v", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
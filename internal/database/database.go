@@ -185,7 +185,7 @@ func (d *Database) GetSpecializationsByVetID(vetID int) ([]*models.Specializatio
 
 // GetAllClinics возвращает все клиники
 func (d *Database) GetAllClinics() ([]*models.Clinic, error) {
-	query := "SELECT id, name, address, phone, working_hours, is_active, city_id, district, metro_station, created_at FROM clinics ORDER BY name"
+	query := "SELECT id, name, address, phone, working_hours, is_active, city_id, district, metro_station, latitude, longitude, created_at FROM clinics ORDER BY name"
 	rows, err := d.db.Query(query)
 	if err != nil {
 		return nil, err
@@ -200,7 +200,7 @@ func (d *Database) GetAllClinics() ([]*models.Clinic, error) {
 
 		err := rows.Scan(&clinic.ID, &clinic.Name, &clinic.Address, &phone,
 			&workingHours, &clinic.IsActive, &cityID,
-			&clinic.District, &clinic.MetroStation, &clinic.CreatedAt)
+			&clinic.District, &clinic.MetroStation, &clinic.Latitude, &clinic.Longitude, &clinic.CreatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -222,9 +222,11 @@ func (d *Database) GetSchedulesByVetID(vetID int) ([]*models.Schedule, error) {
                TO_CHAR(s.end_time, 'HH24:MI') as end_time,
                s.is_available, s.created_at,
                c.id, c.name, c.address, c.phone, c.working_hours, 
-               c.is_active, c.city_id, c.district, c.metro_station, c.created_at
+               c.is_active, c.city_id, c.district, c.metro_station, c.created_at,
+               COALESCE(ct.name, ''), COALESCE(ct.timezone, '')
         FROM schedules s
         LEFT JOIN clinics c ON s.clinic_id = c.id
+        LEFT JOIN cities ct ON c.city_id = ct.id
         WHERE s.vet_id = $1 AND s.is_available = true
         ORDER BY s.day_of_week, s.start_time`
 
@@ -239,6 +241,7 @@ func (d *Database) GetSchedulesByVetID(vetID int) ([]*models.Schedule, error) {
 		var schedule models.Schedule
 		var clinic models.Clinic
 		var startTimeStr, endTimeStr string
+		var cityName, cityTimezone string
 
 		err := rows.Scan(
 			&schedule.ID, &schedule.VetID, &schedule.ClinicID, &schedule.DayOfWeek,
@@ -247,11 +250,17 @@ func (d *Database) GetSchedulesByVetID(vetID int) ([]*models.Schedule, error) {
 			&clinic.ID, &clinic.Name, &clinic.Address, &clinic.Phone,
 			&clinic.WorkingHours, &clinic.IsActive, &clinic.CityID,
 			&clinic.District, &clinic.MetroStation, &clinic.CreatedAt,
+			&cityName, &cityTimezone,
 		)
 		if err != nil {
 			return nil, err
 		}
 
+		// Город нужен для местного времени клиники
+		if clinic.CityID.Valid {
+			clinic.City = &models.City{ID: int(clinic.CityID.Int64), Name: cityName, Timezone: cityTimezone}
+		}
+
 		// Используем строковое представление времени
 		schedule.StartTime = startTimeStr
 		schedule.EndTime = endTimeStr
@@ -263,54 +272,137 @@ func (d *Database) GetSchedulesByVetID(vetID int) ([]*models.Schedule, error) {
 	return schedules, nil
 }
 
-// FindAvailableVets ищет доступных врачей по критериям
-func (d *Database) FindAvailableVets(criteria *models.SearchCriteria) ([]*models.Veterinarian, error) {
+// SearchVets ищет активных врачей по любому сочетанию критериев одним запросом.
+// Фильтры по дню, дате, времени, клинике, району и метро проверяются по расписанию врача
+// на ближайшую неделю (или неделю вокруг искомой даты) с учетом исключений
+func (d *Database) SearchVets(criteria *models.SearchCriteria) ([]*models.Veterinarian, error) {
+	windowStart, day, err := criteria.ScheduleWindow(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
 	query := `
-		SELECT DISTINCT v.id, v.first_name, v.last_name, v.phone, v.email, 
-		       v.description, v.experience_years, v.is_active, v.created_at
+		SELECT v.id, v.first_name, v.last_name, v.patronymic, v.phone, v.email,
+		       v.description, v.experience_years, v.is_active, v.city_id, v.created_at,
+		       c.id, c.name, c.region
 		FROM veterinarians v
-		LEFT JOIN vet_specializations vs ON v.id = vs.vet_id
-		LEFT JOIN schedules s ON v.id = s.vet_id
-		WHERE v.is_active = true AND s.is_available = true`
+		LEFT JOIN cities c ON v.city_id = c.id
+		WHERE v.is_active = true`
 
 	args := []interface{}{}
 	argCount := 0
 
 	if criteria.SpecializationID > 0 {
 		argCount++
-		query += fmt.Sprintf(" AND vs.specialization_id = $%d", argCount)
+		query += fmt.Sprintf(` AND EXISTS (
+			SELECT 1 FROM vet_specializations vs
+			WHERE vs.vet_id = v.id AND vs.specialization_id = $%d)`, argCount)
 		args = append(args, criteria.SpecializationID)
 	}
 
-	if criteria.DayOfWeek > 0 {
+	if criteria.CityID > 0 {
 		argCount++
-		query += fmt.Sprintf(" AND s.day_of_week = $%d", argCount)
-		args = append(args, criteria.DayOfWeek)
+		query += fmt.Sprintf(" AND v.city_id = $%d", argCount)
+		args = append(args, criteria.CityID)
+	} else if criteria.CityName != "" {
+		argCount++
+		query += fmt.Sprintf(" AND LOWER(c.name) = LOWER($%d)", argCount)
+		args = append(args, criteria.CityName)
+	}
+
+	// Условия на расписание собираются в один EXISTS, чтобы все они относились к одному приему
+	var scheduleConditions string
+	if criteria.OpenNow {
+		// Текущие день и время у каждой клиники свои - по часовому поясу ее города
+		localNow := fmt.Sprintf("(CURRENT_TIMESTAMP AT TIME ZONE COALESCE(ct.timezone, '%s'))", models.DefaultTimezone)
+		scheduleConditions += scheduleTimeClause(
+			"EXTRACT(ISODOW FROM "+localNow+")::int",
+			"EXTRACT(ISODOW FROM "+localNow+" - INTERVAL '1 day')::int",
+			localNow+"::time")
+	} else if criteria.Time != "" {
+		// Время учитывает ночные смены, поэтому день недели проверяется вместе с ним
+		if _, err := models.ParseClock(criteria.Time); err != nil {
+			return nil, err
+		}
+		args = append(args, criteria.Time)
+		timeArg := fmt.Sprintf("$%d::time", argCount+1)
+		argCount++
+		if day > 0 {
+			args = append(args, day, models.PreviousDay(day))
+			scheduleConditions += scheduleTimeClause(fmt.Sprintf("$%d", argCount+1), fmt.Sprintf("$%d", argCount+2), timeArg)
+			argCount += 2
+		} else {
+			scheduleConditions += scheduleTimeClause("", "", timeArg)
+		}
+	} else if day > 0 {
+		argCount++
+		scheduleConditions += fmt.Sprintf(" AND s.day_of_week = $%d", argCount)
+		args = append(args, day)
 	}
 
 	if criteria.ClinicID > 0 {
 		argCount++
-		query += fmt.Sprintf(" AND s.clinic_id = $%d", argCount)
+		scheduleConditions += fmt.Sprintf(" AND s.clinic_id = $%d", argCount)
 		args = append(args, criteria.ClinicID)
 	}
 
+	if criteria.District != "" {
+		argCount++
+		scheduleConditions += fmt.Sprintf(" AND LOWER(cl.district) = LOWER($%d)", argCount)
+		args = append(args, criteria.District)
+	}
+
+	if criteria.MetroStation != "" {
+		argCount++
+		scheduleConditions += fmt.Sprintf(" AND LOWER(cl.metro_station) = LOWER($%d)", argCount)
+		args = append(args, criteria.MetroStation)
+	}
+
+	if scheduleConditions != "" {
+		// Расписание берется на неделю с учетом отпусков и других часов приема. Без даты неделя
+		// начинается с сегодняшнего дня по местному времени клиники, для "сейчас" - со вчерашнего,
+		// чтобы ночная смена после полуночи сверялась с исключениями на дату своего начала
+		windowArg, firstDay := "", 0
+		if criteria.OpenNow {
+			firstDay = -1
+		} else if criteria.Date != "" {
+			argCount++
+			args = append(args, windowStart.Format(models.SearchDateLayout))
+			windowArg = fmt.Sprintf("$%d", argCount)
+		}
+		query += `
+		AND EXISTS (
+			SELECT 1 FROM ` + effectiveSchedulesQuery(windowArg, firstDay) + ` s
+			JOIN clinics cl ON s.clinic_id = cl.id
+			LEFT JOIN cities ct ON cl.city_id = ct.id
+			WHERE s.vet_id = v.id AND s.is_available = true AND cl.is_active = true` + scheduleConditions + ")"
+	}
+
 	query += " ORDER BY v.first_name, v.last_name"
 
 	rows, err := d.db.Query(query, args...)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("ошибка поиска врачей: %v", err)
 	}
 	defer rows.Close()
 
 	var veterinarians []*models.Veterinarian
 	for rows.Next() {
 		var vet models.Veterinarian
-		err := rows.Scan(&vet.ID, &vet.FirstName, &vet.LastName, &vet.Phone, &vet.Email,
-			&vet.Description, &vet.ExperienceYears, &vet.IsActive, &vet.CreatedAt)
+		var cityID sql.NullInt64
+		var cityName, cityRegion sql.NullString
+
+		err := rows.Scan(&vet.ID, &vet.FirstName, &vet.LastName, &vet.Patronymic, &vet.Phone, &vet.Email,
+			&vet.Description, &vet.ExperienceYears, &vet.IsActive, &vet.CityID, &vet.CreatedAt,
+			&cityID, &cityName, &cityRegion)
 		if err != nil {
 			return nil, err
 		}
 
+		if cityID.Valid {
+			vet.City = &models.City{ID: int(cityID.Int64), Name: cityName.String, Region: cityRegion.String}
+		}
+
 		// Загружаем специализации для каждого врача
 		specs, err := d.GetSpecializationsByVetID(models.GetVetIDAsIntOrZero(&vet))
 		if err == nil {
@@ -320,7 +412,22 @@ func (d *Database) FindAvailableVets(criteria *models.SearchCriteria) ([]*models
 		veterinarians = append(veterinarians, &vet)
 	}
 
-	return veterinarians, nil
+	return veterinarians, rows.Err()
+}
+
+// scheduleTimeClause возвращает условие "прием идет в день dayExpr (пусто - любой) во время timeExpr",
+// previousDayExpr - день перед dayExpr. Ночная смена (end_time < start_time) до полуночи относится
+// к своему дню, после - к следующему
+func scheduleTimeClause(dayExpr, previousDayExpr, timeExpr string) string {
+	regular := fmt.Sprintf("(s.start_time < s.end_time AND s.start_time <= %[1]s AND %[1]s < s.end_time)", timeExpr)
+	beforeMidnight := fmt.Sprintf("(s.start_time > s.end_time AND %s >= s.start_time)", timeExpr)
+	afterMidnight := fmt.Sprintf("(s.start_time > s.end_time AND %s < s.end_time)", timeExpr)
+
+	if dayExpr == "" {
+		return fmt.Sprintf(" AND (%s OR %s OR %s)", regular, beforeMidnight, afterMidnight)
+	}
+	return fmt.Sprintf(" AND ((s.day_of_week = %s AND (%s OR %s)) OR (s.day_of_week = %s AND %s))",
+		dayExpr, regular, beforeMidnight, previousDayExpr, afterMidnight)
 }
 
 // ========== НОВЫЕ МЕТОДЫ ДЛЯ АДМИНКИ ==========
@@ -461,13 +568,15 @@ func (d *Database) GetVeterinarianByID(id int) (*models.Veterinarian, error) {
 
 // GetClinicByID возвращает клинику по ID
 func (d *Database) GetClinicByID(id int) (*models.Clinic, error) {
-	query := `SELECT id, name, address, phone, working_hours, is_active, created_at 
+	query := `SELECT id, name, address, phone, working_hours, is_active, city_id, district, metro_station,
+                     latitude, longitude, created_at 
               FROM clinics WHERE id = $1`
 
 	var clinic models.Clinic
 	err := d.db.QueryRow(query, id).Scan(&clinic.ID, &clinic.Name, &clinic.Address,
 		&clinic.Phone, &clinic.WorkingHours, &clinic.IsActive,
-		&clinic.CreatedAt)
+		&clinic.CityID, &clinic.District, &clinic.MetroStation,
+		&clinic.Latitude, &clinic.Longitude, &clinic.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -475,45 +584,20 @@ func (d *Database) GetClinicByID(id int) (*models.Clinic, error) {
 	return &clinic, nil
 }
 
-func (d *Database) AddMissingColumns() error {
-	// Добавляем is_active в clinics если не существует
-	_, err := d.db.Exec(`
-		DO $$ 
-		BEGIN 
-			IF NOT EXISTS (SELECT 1 FROM information_schema.columns 
-						  WHERE table_name = 'clinics' AND column_name = 'is_active') THEN
-				ALTER TABLE clinics ADD COLUMN is_active BOOLEAN DEFAULT TRUE;
-			END IF;
-		END $$;
-	`)
-	if err != nil {
-		return err
-	}
-
-	// Добавляем is_active в veterinarians если не существует
-	_, err = d.db.Exec(`
-		DO $$ 
-		BEGIN 
-			IF NOT EXISTS (SELECT 1 FROM information_schema.columns 
-						  WHERE table_name = 'veterinarians' AND column_name = 'is_active') THEN
-				ALTER TABLE veterinarians ADD COLUMN is_active BOOLEAN DEFAULT TRUE;
-			END IF;
-		END $$;
-	`)
-	return err
-}
-
 // ========== МЕТОДЫ ДЛЯ ГОРОДОВ И ИМПОРТА ==========
 
-// CreateCity создает новый город
+// CreateCity создает новый город; без часового пояса используется пояс по умолчанию
 func (d *Database) CreateCity(city *models.City) error {
-	query := `INSERT INTO cities (name, region) VALUES ($1, $2) RETURNING id, created_at`
-	return d.db.QueryRow(query, city.Name, city.Region).Scan(&city.ID, &city.CreatedAt)
+	if city.Timezone == "" {
+		city.Timezone = models.DefaultTimezone
+	}
+	query := `INSERT INTO cities (name, region, timezone) VALUES ($1, $2, $3) RETURNING id, created_at`
+	return d.db.QueryRow(query, city.Name, city.Region, city.Timezone).Scan(&city.ID, &city.CreatedAt)
 }
 
 // GetAllCities возвращает все города
 func (d *Database) GetAllCities() ([]*models.City, error) {
-	query := `SELECT id, name, region, created_at FROM cities ORDER BY name`
+	query := `SELECT id, name, region, timezone, created_at FROM cities ORDER BY name`
 	rows, err := d.db.Query(query)
 	if err != nil {
 		return nil, err
@@ -523,7 +607,7 @@ func (d *Database) GetAllCities() ([]*models.City, error) {
 	var cities []*models.City
 	for rows.Next() {
 		var city models.City
-		err := rows.Scan(&city.ID, &city.Name, &city.Region, &city.CreatedAt)
+		err := rows.Scan(&city.ID, &city.Name, &city.Region, &city.Timezone, &city.CreatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -534,9 +618,9 @@ func (d *Database) GetAllCities() ([]*models.City, error) {
 
 // GetCityByID возвращает город по ID
 func (d *Database) GetCityByID(id int) (*models.City, error) {
-	query := `SELECT id, name, region, created_at FROM cities WHERE id = $1`
+	query := `SELECT id, name, region, timezone, created_at FROM cities WHERE id = $1`
 	var city models.City
-	err := d.db.QueryRow(query, id).Scan(&city.ID, &city.Name, &city.Region, &city.CreatedAt)
+	err := d.db.QueryRow(query, id).Scan(&city.ID, &city.Name, &city.Region, &city.Timezone, &city.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -545,9 +629,9 @@ func (d *Database) GetCityByID(id int) (*models.City, error) {
 
 // GetCityByName возвращает город по названию
 func (d *Database) GetCityByName(name string) (*models.City, error) {
-	query := `SELECT id, name, region, created_at FROM cities WHERE LOWER(name) = LOWER($1)`
+	query := `SELECT id, name, region, timezone, created_at FROM cities WHERE LOWER(name) = LOWER($1)`
 	var city models.City
-	err := d.db.QueryRow(query, name).Scan(&city.ID, &city.Name, &city.Region, &city.CreatedAt)
+	err := d.db.QueryRow(query, name).Scan(&city.ID, &city.Name, &city.Region, &city.Timezone, &city.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -556,8 +640,8 @@ func (d *Database) GetCityByName(name string) (*models.City, error) {
 
 // CreateClinicWithCity создает клинику с привязкой к городу
 func (d *Database) CreateClinicWithCity(clinic *models.Clinic) error {
-	query := `INSERT INTO clinics (name, address, phone, working_hours, is_active, city_id, district, metro_station) 
-	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at`
+	query := `INSERT INTO clinics (name, address, phone, working_hours, is_active, city_id, district, metro_station, latitude, longitude) 
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, created_at`
 
 	return d.db.QueryRow(query,
 		clinic.Name,
@@ -568,6 +652,8 @@ func (d *Database) CreateClinicWithCity(clinic *models.Clinic) error {
 		clinic.CityID,
 		clinic.District,
 		clinic.MetroStation,
+		clinic.Latitude,
+		clinic.Longitude,
 	).Scan(&clinic.ID, &clinic.CreatedAt)
 }
 
@@ -575,8 +661,8 @@ func (d *Database) CreateClinicWithCity(clinic *models.Clinic) error {
 func (d *Database) GetAllClinicsWithCities() ([]*models.Clinic, error) {
 	query := `
 		SELECT c.id, c.name, c.address, c.phone, c.working_hours, c.is_active, 
-		       c.city_id, c.district, c.metro_station, c.created_at,
-		       ct.id, ct.name, ct.region, ct.created_at
+		       c.city_id, c.district, c.metro_station, c.latitude, c.longitude, c.created_at,
+		       ct.id, ct.name, ct.region, COALESCE(ct.timezone, ''), ct.created_at
 		FROM clinics c
 		LEFT JOIN cities ct ON c.city_id = ct.id
 		ORDER BY c.name`
@@ -596,8 +682,9 @@ func (d *Database) GetAllClinicsWithCities() ([]*models.Clinic, error) {
 
 		err := rows.Scan(
 			&clinic.ID, &clinic.Name, &clinic.Address, &clinic.Phone, &clinic.WorkingHours,
-			&clinic.IsActive, &cityID, &clinic.District, &clinic.MetroStation, &clinic.CreatedAt,
-			&city.ID, &city.Name, &city.Region, &cityCreatedAt,
+			&clinic.IsActive, &cityID, &clinic.District, &clinic.MetroStation,
+			&clinic.Latitude, &clinic.Longitude, &clinic.CreatedAt,
+			&city.ID, &city.Name, &city.Region, &city.Timezone, &cityCreatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -618,12 +705,14 @@ func (d *Database) GetAllClinicsWithCities() ([]*models.Clinic, error) {
 func (d *Database) UpdateClinic(clinic *models.Clinic) error {
 	query := `UPDATE clinics SET 
 		name = $1, address = $2, phone = $3, working_hours = $4, 
-		is_active = $5, city_id = $6, district = $7, metro_station = $8
-		WHERE id = $9`
+		is_active = $5, city_id = $6, district = $7, metro_station = $8,
+		latitude = $9, longitude = $10
+		WHERE id = $11`
 
 	_, err := d.db.Exec(query,
 		clinic.Name, clinic.Address, clinic.Phone, clinic.WorkingHours,
-		clinic.IsActive, clinic.CityID, clinic.District, clinic.MetroStation, clinic.ID,
+		clinic.IsActive, clinic.CityID, clinic.District, clinic.MetroStation,
+		clinic.Latitude, clinic.Longitude, clinic.ID,
 	)
 	return err
 }
@@ -658,79 +747,9 @@ func (d *Database) GetClinicsByCity(cityID int) ([]*models.Clinic, error) {
 	return clinics, nil
 }
 
-// FindVetsByCity ищет врачей по городу с дополнительными критериями
-// FindVetsByCity ищет врачей по городу с дополнительными критериями
-func (d *Database) FindVetsByCity(criteria *models.SearchCriteria) ([]*models.Veterinarian, error) {
-	query := `
-        SELECT DISTINCT v.id, v.first_name, v.last_name, v.phone, v.email, 
-               v.description, v.experience_years, v.is_active, v.city_id, v.created_at,
-               c.id, c.name, c.region, c.created_at
-        FROM veterinarians v
-        LEFT JOIN cities c ON v.city_id = c.id
-        WHERE v.is_active = true AND v.city_id = $1`
-
-	args := []interface{}{criteria.CityID}
-
-	// Дополнительные критерии поиска
-	if criteria.SpecializationID > 0 {
-		query += ` AND EXISTS (
-            SELECT 1 FROM vet_specializations vs 
-            WHERE vs.vet_id = v.id AND vs.specialization_id = $2
-        )`
-		args = append(args, criteria.SpecializationID)
-	}
-
-	if criteria.DayOfWeek > 0 {
-		query += ` AND EXISTS (
-            SELECT 1 FROM schedules s 
-            WHERE s.vet_id = v.id AND s.day_of_week = $3 AND s.is_available = true
-        )`
-		args = append(args, criteria.DayOfWeek)
-	}
-
-	query += " ORDER BY v.first_name, v.last_name"
-
-	rows, err := d.db.Query(query, args...)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var veterinarians []*models.Veterinarian
-	for rows.Next() {
-		var vet models.Veterinarian
-		var cityID sql.NullInt64
-		var city models.City
-		var cityCreatedAt time.Time
-
-		err := rows.Scan(&vet.ID, &vet.FirstName, &vet.LastName, &vet.Phone, &vet.Email,
-			&vet.Description, &vet.ExperienceYears, &vet.IsActive, &cityID, &vet.CreatedAt,
-			&city.ID, &city.Name, &city.Region, &cityCreatedAt)
-		if err != nil {
-			return nil, err
-		}
-
-		vet.CityID = cityID
-		if cityID.Valid {
-			city.CreatedAt = cityCreatedAt
-			vet.City = &city
-		}
-
-		// Загружаем специализации для каждого врача
-		specs, err := d.GetSpecializationsByVetID(models.GetVetIDAsIntOrZero(&vet))
-		if err == nil {
-			vet.Specializations = specs
-		}
-
-		veterinarians = append(veterinarians, &vet)
-	}
-
-	return veterinarians, nil
-}
-
 // GetCitiesByRegion возвращает города по региону
 func (d *Database) GetCitiesByRegion(region string) ([]*models.City, error) {
-	query := `SELECT id, name, region, created_at FROM cities WHERE LOWER(region) LIKE LOWER($1) ORDER BY name`
+	query := `SELECT id, name, region, timezone, created_at FROM cities WHERE LOWER(region) LIKE LOWER($1) ORDER BY name`
 	rows, err := d.db.Query(query, "%"+region+"%")
 	if err != nil {
 		return nil, err
@@ -740,7 +759,7 @@ func (d *Database) GetCitiesByRegion(region string) ([]*models.City, error) {
 	var cities []*models.City
 	for rows.Next() {
 		var city models.City
-		err := rows.Scan(&city.ID, &city.Name, &city.Region, &city.CreatedAt)
+		err := rows.Scan(&city.ID, &city.Name, &city.Region, &city.Timezone, &city.CreatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -751,7 +770,7 @@ func (d *Database) GetCitiesByRegion(region string) ([]*models.City, error) {
 
 // SearchCities ищет города по названию
 func (d *Database) SearchCities(queryStr string) ([]*models.City, error) {
-	query := `SELECT id, name, region, created_at FROM cities WHERE LOWER(name) LIKE LOWER($1) ORDER BY name`
+	query := `SELECT id, name, region, timezone, created_at FROM cities WHERE LOWER(name) LIKE LOWER($1) ORDER BY name`
 	rows, err := d.db.Query(query, "%"+queryStr+"%")
 	if err != nil {
 		return nil, err
@@ -761,7 +780,7 @@ func (d *Database) SearchCities(queryStr string) ([]*models.City, error) {
 	var cities []*models.City
 	for rows.Next() {
 		var city models.City
-		err := rows.Scan(&city.ID, &city.Name, &city.Region, &city.CreatedAt)
+		err := rows.Scan(&city.ID, &city.Name, &city.Region, &city.Timezone, &city.CreatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -774,12 +793,12 @@ func (d *Database) SearchCities(queryStr string) ([]*models.City, error) {
 func (d *Database) UpdateVeterinarian(vet *models.Veterinarian) error {
 	query := `UPDATE veterinarians SET 
 		first_name = $1, last_name = $2, phone = $3, email = $4, 
-		description = $5, experience_years = $6, is_active = $7, city_id = $8
-		WHERE id = $9`
+		description = $5, experience_years = $6, is_active = $7, city_id = $8, patronymic = $9
+		WHERE id = $10`
 
 	_, err := d.db.Exec(query,
 		vet.FirstName, vet.LastName, vet.Phone, vet.Email,
-		vet.Description, vet.ExperienceYears, vet.IsActive, vet.CityID, vet.ID,
+		vet.Description, vet.ExperienceYears, vet.IsActive, vet.CityID, vet.Patronymic, vet.ID,
 	)
 	return err
 }
@@ -810,17 +829,42 @@ func (d *Database) CreateVeterinarian(vet *models.Veterinarian) error {
 	).Scan(&vet.ID, &vet.CreatedAt)
 }
 
-// DeleteCity удаляет город по ID
+// DeleteCity удаляет город по ID. Врачи и клиники города остаются без города
 func (d *Database) DeleteCity(id int) error {
-	query := "DELETE FROM cities WHERE id = $1"
-	_, err := d.db.Exec(query, id)
-	return err
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("UPDATE veterinarians SET city_id = NULL WHERE city_id = $1", id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE clinics SET city_id = NULL WHERE city_id = $1", id); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec("DELETE FROM cities WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
 }
 
 // UpdateCity обновляет данные города
 func (d *Database) UpdateCity(city *models.City) error {
-	query := "UPDATE cities SET name = $1, region = $2 WHERE id = $3"
-	_, err := d.db.Exec(query, city.Name, city.Region, city.ID)
+	if city.Timezone == "" {
+		city.Timezone = models.DefaultTimezone
+	}
+	query := "UPDATE cities SET name = $1, region = $2, timezone = $3 WHERE id = $4"
+	_, err := d.db.Exec(query, city.Name, city.Region, city.Timezone, city.ID)
 	return err
 }
 
@@ -828,9 +872,10 @@ func (d *Database) UpdateCity(city *models.City) error {
 func (d *Database) GetVeterinarianWithDetails(id int) (*models.Veterinarian, error) {
 	// Получаем основную информацию о враче с городом
 	query := `
-        SELECT v.id, v.first_name, v.last_name, v.phone, v.email, 
+        SELECT v.id, v.first_name, v.last_name, v.patronymic, v.phone, v.email, 
                v.description, v.experience_years, v.is_active, v.city_id, v.created_at,
-               c.id, c.name, c.region, c.created_at
+               COALESCE(c.id, 0), COALESCE(c.name, ''), COALESCE(c.region, ''), COALESCE(c.timezone, ''),
+               COALESCE(c.created_at, v.created_at)
         FROM veterinarians v
         LEFT JOIN cities c ON v.city_id = c.id
         WHERE v.id = $1`
@@ -842,9 +887,9 @@ func (d *Database) GetVeterinarianWithDetails(id int) (*models.Veterinarian, err
 	var vetID sql.NullInt64
 
 	err := d.db.QueryRow(query, id).Scan(
-		&vetID, &vet.FirstName, &vet.LastName, &vet.Phone, &vet.Email,
+		&vetID, &vet.FirstName, &vet.LastName, &vet.Patronymic, &vet.Phone, &vet.Email,
 		&vet.Description, &vet.ExperienceYears, &vet.IsActive, &cityID, &vet.CreatedAt,
-		&city.ID, &city.Name, &city.Region, &cityCreatedAt,
+		&city.ID, &city.Name, &city.Region, &city.Timezone, &cityCreatedAt,
 	)
 	if err != nil {
 		return nil, err
@@ -876,9 +921,10 @@ func (d *Database) GetVeterinarianWithDetails(id int) (*models.Veterinarian, err
 	return &vet, nil
 }
 
-// GetSpecializationByName возвращает специализацию по имени
+// GetSpecializationByName возвращает специализацию по имени без учета регистра и пробелов по краям
 func (d *Database) GetSpecializationByName(name string) (*models.Specialization, error) {
-	query := `SELECT id, name, description, created_at FROM specializations WHERE LOWER(name) = LOWER($1)`
+	query := `SELECT id, name, description, created_at FROM specializations
+	          WHERE LOWER(TRIM(name)) = LOWER(TRIM($1)) ORDER BY id LIMIT 1`
 	var spec models.Specialization
 	err := d.db.QueryRow(query, name).Scan(&spec.ID, &spec.Name, &spec.Description, &spec.CreatedAt)
 	if err != nil {
@@ -894,6 +940,106 @@ func (d *Database) CreateSpecialization(spec *models.Specialization) error {
 	return d.db.QueryRow(query, spec.Name, spec.Description, spec.CreatedAt).Scan(&spec.ID)
 }
 
+// UpdateSpecialization обновляет название и описание специализации
+func (d *Database) UpdateSpecialization(spec *models.Specialization) error {
+	result, err := d.db.Exec(`UPDATE specializations SET name = $1, description = $2 WHERE id = $3`,
+		spec.Name, spec.Description, spec.ID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteSpecialization удаляет специализацию; связи с врачами удаляются каскадно
+func (d *Database) DeleteSpecialization(id int) error {
+	result, err := d.db.Exec(`DELETE FROM specializations WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// MergeSpecializations переносит врачей и запросы пользователей со специализации sourceID
+// на targetID и удаляет sourceID
+func (d *Database) MergeSpecializations(sourceID int, targetID int) error {
+	if sourceID == targetID {
+		return fmt.Errorf("нельзя объединить специализацию саму с собой")
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	if err := tx.QueryRow("SELECT EXISTS(SELECT 1 FROM specializations WHERE id = $1)", targetID).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return sql.ErrNoRows
+	}
+
+	_, err = tx.Exec(`INSERT INTO vet_specializations (vet_id, specialization_id)
+	                  SELECT vet_id, $2 FROM vet_specializations WHERE specialization_id = $1
+	                  ON CONFLICT DO NOTHING`, sourceID, targetID)
+	if err != nil {
+		return fmt.Errorf("ошибка переноса врачей на специализацию %d: %v", targetID, err)
+	}
+	if _, err := tx.Exec(`UPDATE user_requests SET specialization_id = $2 WHERE specialization_id = $1`, sourceID, targetID); err != nil {
+		return fmt.Errorf("ошибка переноса запросов на специализацию %d: %v", targetID, err)
+	}
+
+	// Связи врачей с sourceID удаляются каскадно
+	result, err := tx.Exec(`DELETE FROM specializations WHERE id = $1`, sourceID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
+
+// SetVeterinarianSpecializations заменяет специализации врача указанным списком
+func (d *Database) SetVeterinarianSpecializations(vetID int, specIDs []int) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM vet_specializations WHERE vet_id = $1", vetID); err != nil {
+		return err
+	}
+	for _, specID := range specIDs {
+		_, err := tx.Exec(`INSERT INTO vet_specializations (vet_id, specialization_id) VALUES ($1, $2)
+		                   ON CONFLICT DO NOTHING`, vetID, specID)
+		if err != nil {
+			return fmt.Errorf("ошибка добавления специализации %d: %v", specID, err)
+		}
+	}
+	return tx.Commit()
+}
+
 // AddVeterinarianSpecialization добавляет специализацию врачу
 func (d *Database) AddVeterinarianSpecialization(vetID int, specID int) error {
 	query := `INSERT INTO vet_specializations (vet_id, specialization_id) VALUES ($1, $2)`
@@ -903,7 +1049,7 @@ func (d *Database) AddVeterinarianSpecialization(vetID int, specID int) error {
 
 // SearchCitiesByRegion ищет города по региону
 func (d *Database) SearchCitiesByRegion(region string) ([]*models.City, error) {
-	query := `SELECT id, name, region, created_at FROM cities 
+	query := `SELECT id, name, region, timezone, created_at FROM cities 
               WHERE region ILIKE $1 ORDER BY name`
 
 	rows, err := d.db.Query(query, "%"+region+"%")
@@ -915,7 +1061,7 @@ func (d *Database) SearchCitiesByRegion(region string) ([]*models.City, error) {
 	var cities []*models.City
 	for rows.Next() {
 		var city models.City
-		err := rows.Scan(&city.ID, &city.Name, &city.Region, &city.CreatedAt)
+		err := rows.Scan(&city.ID, &city.Name, &city.Region, &city.Timezone, &city.CreatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -962,6 +1108,150 @@ func (d *Database) GetReviewStats(vetID int) (*models.ReviewStats, error) {
 	return repo.GetReviewStats(vetID)
 }
 
+// Методы для работы с записями на прием
+
+func (d *Database) CreateAppointment(appointment *models.Appointment) error {
+	repo := NewAppointmentRepository(d.db)
+	return repo.CreateAppointment(appointment)
+}
+
+func (d *Database) GetBookedAppointments(vetID int, date time.Time) ([]*models.Appointment, error) {
+	repo := NewAppointmentRepository(d.db)
+	return repo.GetBookedAppointments(vetID, date)
+}
+
+func (d *Database) GetUpcomingAppointmentsByUser(userID int, now time.Time) ([]*models.Appointment, error) {
+	repo := NewAppointmentRepository(d.db)
+	return repo.GetUpcomingAppointmentsByUser(userID, now)
+}
+
+func (d *Database) CancelAppointment(appointmentID int, userID int) error {
+	repo := NewAppointmentRepository(d.db)
+	return repo.CancelAppointment(appointmentID, userID)
+}
+
+// Методы для работы с избранными врачами
+
+func (d *Database) AddFavorite(userID int, vetID int) error {
+	repo := NewFavoriteRepository(d.db)
+	return repo.AddFavorite(userID, vetID)
+}
+
+func (d *Database) RemoveFavorite(userID int, vetID int) error {
+	repo := NewFavoriteRepository(d.db)
+	return repo.RemoveFavorite(userID, vetID)
+}
+
+func (d *Database) IsFavorite(userID int, vetID int) (bool, error) {
+	repo := NewFavoriteRepository(d.db)
+	return repo.IsFavorite(userID, vetID)
+}
+
+func (d *Database) GetFavoriteVets(userID int) ([]*models.Veterinarian, error) {
+	repo := NewFavoriteRepository(d.db)
+	return repo.GetFavoriteVets(userID)
+}
+
+// Методы для поиска клиник по геопозиции
+
+func (d *Database) FindNearestClinics(latitude, longitude float64, limit int) ([]*models.ClinicDistance, error) {
+	repo := NewClinicLocationRepository(d.db)
+	return repo.FindNearestClinics(latitude, longitude, limit)
+}
+
+func (d *Database) GetClinicSchedulesByDate(clinicID int, date time.Time) ([]*models.Schedule, error) {
+	repo := NewClinicLocationRepository(d.db)
+	return repo.GetClinicSchedulesByDate(clinicID, date)
+}
+
+func (d *Database) UpdateClinicCoordinates(clinicID int, latitude, longitude sql.NullFloat64) error {
+	repo := NewClinicLocationRepository(d.db)
+	return repo.UpdateClinicCoordinates(clinicID, latitude, longitude)
+}
+
+// Методы для изменения расписания
+
+func (d *Database) GetScheduleByID(id int) (*models.Schedule, error) {
+	repo := NewScheduleRepository(d.db)
+	return repo.GetScheduleByID(id)
+}
+
+func (d *Database) GetAllSchedulesByVetID(vetID int) ([]*models.Schedule, error) {
+	repo := NewScheduleRepository(d.db)
+	return repo.GetAllSchedulesByVetID(vetID)
+}
+
+func (d *Database) GetWeekSchedulesByVetID(vetID int) ([]*models.Schedule, error) {
+	repo := NewScheduleRepository(d.db)
+	return repo.GetWeekSchedulesByVetID(vetID)
+}
+
+func (d *Database) CreateSchedule(schedule *models.Schedule) error {
+	repo := NewScheduleRepository(d.db)
+	return repo.CreateSchedule(schedule)
+}
+
+func (d *Database) UpdateSchedule(schedule *models.Schedule) error {
+	repo := NewScheduleRepository(d.db)
+	return repo.UpdateSchedule(schedule)
+}
+
+func (d *Database) DeleteSchedule(id int) error {
+	repo := NewScheduleRepository(d.db)
+	return repo.DeleteSchedule(id)
+}
+
+// Методы для исключений из расписания
+
+func (d *Database) CreateScheduleException(exception *models.ScheduleException) error {
+	repo := NewScheduleExceptionRepository(d.db)
+	return repo.CreateScheduleException(exception)
+}
+
+func (d *Database) DeleteScheduleException(id int) error {
+	repo := NewScheduleExceptionRepository(d.db)
+	return repo.DeleteScheduleException(id)
+}
+
+func (d *Database) GetScheduleExceptionsByVetID(vetID int, from time.Time) ([]*models.ScheduleException, error) {
+	repo := NewScheduleExceptionRepository(d.db)
+	return repo.GetScheduleExceptionsByVetID(vetID, from)
+}
+
+// Методы для задач импорта
+
+func (d *Database) CreateImportRequest(request *models.ImportRequest) error {
+	repo := NewImportRequestRepository(d.db)
+	return repo.CreateImportRequest(request)
+}
+
+func (d *Database) UpdateImportRequest(request *models.ImportRequest) error {
+	repo := NewImportRequestRepository(d.db)
+	return repo.UpdateImportRequest(request)
+}
+
+func (d *Database) GetRecentImportRequests(limit int) ([]*models.ImportRequest, error) {
+	repo := NewImportRequestRepository(d.db)
+	return repo.GetRecentImportRequests(limit)
+}
+
+func (d *Database) FailUnfinishedImportRequests() (int64, error) {
+	repo := NewImportRequestRepository(d.db)
+	return repo.FailUnfinishedImportRequests()
+}
+
+// Методы для поиска врачей по ФИО
+
+func (d *Database) SearchVeterinariansByName(query string, limit int) ([]*models.Veterinarian, error) {
+	repo := NewVetSearchRepository(d.db)
+	return repo.SearchVeterinariansByName(query, limit)
+}
+
+func (d *Database) GetVeterinariansByIDs(ids []int) ([]*models.Veterinarian, error) {
+	repo := NewVetSearchRepository(d.db)
+	return repo.GetVeterinariansByIDs(ids)
+}
+
 // DebugSpecializationVetsCount - диагностическая функция для отладки количества врачей по специализациям
 func (d *Database) DebugSpecializationVetsCount() (map[int]int, error) {
 	query := `
@@ -1202,6 +1492,13 @@ func (db *Database) UpdateClinicField(clinicID int, field string, value interfac
 
 // DeleteClinic удаляет клинику
 func (db *Database) DeleteClinic(clinicID int) error {
+	// Сначала удаляем расписание, связанное с клиникой
+	deleteScheduleQuery := "DELETE FROM schedules WHERE clinic_id = $1"
+	_, err := db.db.ExecContext(context.Background(), deleteScheduleQuery, clinicID)
+	if err != nil {
+		return fmt.Errorf("ошибка при удалении расписания клиники: %v", err)
+	}
+
 	query := "DELETE FROM clinics WHERE id = $1"
 	result, err := db.db.ExecContext(context.Background(), query, clinicID)
 	if err != nil {
@@ -1223,7 +1520,7 @@ func (db *Database) DeleteClinic(clinicID int) error {
 // DeleteVeterinarian удаляет ветеринара
 func (db *Database) DeleteVeterinarian(vetID int) error {
 	// Сначала удаляем связанные записи из таблицы связей
-	deleteSpecsQuery := "DELETE FROM veterinarian_specializations WHERE veterinarian_id = $1"
+	deleteSpecsQuery := "DELETE FROM vet_specializations WHERE vet_id = $1"
 	_, err := db.db.ExecContext(context.Background(), deleteSpecsQuery, vetID)
 	if err != nil {
 		return fmt.Errorf("ошибка при удалении специализаций врача: %v", err)
@@ -1295,7 +1592,7 @@ func (d *Database) GetUserByTelegramID(telegramID int64) (*models.User, error) {
 func (d *Database) GetClinicsByVetID(vetID int) ([]*models.Clinic, error) {
 	query := `
         SELECT c.id, c.name, c.address, c.phone, c.working_hours, 
-               c.is_active, c.city_id, c.district, c.metro_station, c.created_at
+               c.is_active, c.city_id, c.district, c.metro_station, c.latitude, c.longitude, c.created_at
         FROM clinics c
         INNER JOIN vet_clinics vc ON c.id = vc.clinic_id
         WHERE vc.vet_id = $1 AND c.is_active = true
@@ -1315,7 +1612,7 @@ func (d *Database) GetClinicsByVetID(vetID int) ([]*models.Clinic, error) {
 
 		err := rows.Scan(&clinic.ID, &clinic.Name, &clinic.Address, &phone,
 			&workingHours, &clinic.IsActive, &cityID,
-			&clinic.District, &clinic.MetroStation, &clinic.CreatedAt)
+			&clinic.District, &clinic.MetroStation, &clinic.Latitude, &clinic.Longitude, &clinic.CreatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -1337,21 +1634,47 @@ func (d *Database) AddVetToClinic(vetID int, clinicID int) error {
 	return err
 }
 
-// RemoveVetFromClinic удаляет врача из клиники
+// RemoveVetFromClinic отвязывает врача от клиники вместе с его приемами и исключениями в ней,
+// чтобы расписание не расходилось со связями vet_clinics. Если врач не привязан к клинике, возвращает sql.ErrNoRows
 func (d *Database) RemoveVetFromClinic(vetID int, clinicID int) error {
-	query := `DELETE FROM vet_clinics WHERE vet_id = $1 AND clinic_id = $2`
-	_, err := d.db.Exec(query, vetID, clinicID)
-	return err
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`DELETE FROM vet_clinics WHERE vet_id = $1 AND clinic_id = $2`, vetID, clinicID)
+	if err != nil {
+		return fmt.Errorf("ошибка отвязки врача от клиники: %v", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	queries := []string{
+		`DELETE FROM schedules WHERE vet_id = $1 AND clinic_id = $2`,
+		`DELETE FROM schedule_exceptions WHERE vet_id = $1 AND clinic_id = $2`,
+	}
+	for _, query := range queries {
+		if _, err := tx.Exec(query, vetID, clinicID); err != nil {
+			return fmt.Errorf("ошибка отвязки врача от клиники: %v", err)
+		}
+	}
+	return tx.Commit()
 }
 
-// GetVetsByClinic возвращает врачей работающих в клинике
+// GetVetsByClinic возвращает врачей, привязанных к клинике, включая неактивных
 func (d *Database) GetVetsByClinic(clinicID int) ([]*models.Veterinarian, error) {
 	query := `
         SELECT DISTINCT v.id, v.first_name, v.last_name, v.phone, v.email, 
                v.description, v.experience_years, v.is_active, v.city_id, v.created_at
         FROM veterinarians v
         INNER JOIN vet_clinics vc ON v.id = vc.vet_id
-        WHERE vc.clinic_id = $1 AND v.is_active = true
+        WHERE vc.clinic_id = $1
         ORDER BY v.first_name, v.last_name`
 
 	rows, err := d.db.Query(query, clinicID)
@@ -96,25 +96,44 @@ func TestDatabase_Integration(t *testing.T) {
 		assert.Equal(t, "ВетКлиника Центр", clinic.Name)
 	})
 
-	t.Run("FindAvailableVets_EmptyCriteria", func(t *testing.T) {
+	t.Run("SearchVets_EmptyCriteria", func(t *testing.T) {
 		criteria := &models.SearchCriteria{}
-		vets, err := db.FindAvailableVets(criteria)
+		vets, err := db.SearchVets(criteria)
 		require.NoError(t, err)
 		assert.Greater(t, len(vets), 0)
 	})
 
-	t.Run("FindAvailableVets_WithSpecialization", func(t *testing.T) {
+	t.Run("SearchVets_WithSpecialization", func(t *testing.T) {
 		criteria := &models.SearchCriteria{
 			SpecializationID: 1,
 		}
-		vets, err := db.FindAvailableVets(criteria)
+		vets, err := db.SearchVets(criteria)
 		require.NoError(t, err)
 		assert.Greater(t, len(vets), 0)
 	})
 
-	t.Run("AddMissingColumns", func(t *testing.T) {
-		err := db.AddMissingColumns()
-		assert.NoError(t, err)
+	t.Run("SessionRepository", func(t *testing.T) {
+		repo := NewSessionRepository(db.GetDB())
+
+		session := &models.UserSession{
+			UserID:  99992,
+			State:   "review_comment",
+			Data:    map[string]interface{}{"review_vet_id": 1},
+			History: []string{"main_menu"},
+		}
+		require.NoError(t, repo.SaveSession(session))
+
+		loaded, err := repo.LoadSession(99992)
+		require.NoError(t, err)
+		require.NotNil(t, loaded)
+		assert.Equal(t, "review_comment", loaded.State)
+		assert.Equal(t, 1, loaded.Data["review_vet_id"])
+		assert.Equal(t, []string{"main_menu"}, loaded.History)
+
+		require.NoError(t, repo.DeleteSession(99992))
+		loaded, err = repo.LoadSession(99992)
+		require.NoError(t, err)
+		assert.Nil(t, loaded)
 	})
 }
 
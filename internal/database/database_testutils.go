@@ -123,6 +123,17 @@ func createTestTables(db *sql.DB) error {
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		)`,
 
+		// Таблица сессий пользователей
+		`CREATE TABLE IF NOT EXISTS user_sessions (
+			telegram_id BIGINT PRIMARY KEY,
+			state TEXT NOT NULL DEFAULT '',
+			data JSONB NOT NULL DEFAULT '{}',
+			history JSONB NOT NULL DEFAULT '[]',
+			admin_state TEXT NOT NULL DEFAULT '',
+			admin_data JSONB NOT NULL DEFAULT '{}',
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
+
 		// Вставляем тестовые данные
 		`INSERT INTO specializations (id, name, description) VALUES 
 			(1, 'Хирург', 'Ветеринарный хирург'),
@@ -173,6 +184,7 @@ func CleanupTestDatabase(db *Database) {
 		"DELETE FROM clinics",
 		"DELETE FROM specializations",
 		"DELETE FROM users",
+		"DELETE FROM user_sessions",
 	}
 
 	for _, query := range queries {
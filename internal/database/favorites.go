@@ -0,0 +1,77 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/drerr0r/vetbot/internal/models"
+)
+
+// FavoriteRepository содержит методы для работы с избранными врачами
+type FavoriteRepository struct {
+	db *sql.DB
+}
+
+// NewFavoriteRepository создает новый репозиторий избранного
+func NewFavoriteRepository(db *sql.DB) *FavoriteRepository {
+	return &FavoriteRepository{db: db}
+}
+
+// AddFavorite добавляет врача в избранное пользователя
+func (r *FavoriteRepository) AddFavorite(userID int, vetID int) error {
+	query := `INSERT INTO favorites (user_id, vet_id) VALUES ($1, $2)
+              ON CONFLICT (user_id, vet_id) DO NOTHING`
+
+	_, err := r.db.Exec(query, userID, vetID)
+	if err != nil {
+		return fmt.Errorf("ошибка добавления в избранное: %v", err)
+	}
+	return nil
+}
+
+// RemoveFavorite удаляет врача из избранного пользователя
+func (r *FavoriteRepository) RemoveFavorite(userID int, vetID int) error {
+	_, err := r.db.Exec("DELETE FROM favorites WHERE user_id = $1 AND vet_id = $2", userID, vetID)
+	if err != nil {
+		return fmt.Errorf("ошибка удаления из избранного: %v", err)
+	}
+	return nil
+}
+
+// IsFavorite проверяет, находится ли врач в избранном пользователя
+func (r *FavoriteRepository) IsFavorite(userID int, vetID int) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow("SELECT EXISTS(SELECT 1 FROM favorites WHERE user_id = $1 AND vet_id = $2)",
+		userID, vetID).Scan(&exists)
+	return exists, err
+}
+
+// GetFavoriteVets возвращает избранных врачей пользователя
+func (r *FavoriteRepository) GetFavoriteVets(userID int) ([]*models.Veterinarian, error) {
+	query := `
+		SELECT v.id, v.first_name, v.last_name, v.patronymic, v.phone, v.email,
+		       v.description, v.experience_years, v.is_active, v.city_id, v.created_at
+		FROM favorites f
+		JOIN veterinarians v ON f.vet_id = v.id
+		WHERE f.user_id = $1
+		ORDER BY f.created_at`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var vets []*models.Veterinarian
+	for rows.Next() {
+		var vet models.Veterinarian
+		err := rows.Scan(&vet.ID, &vet.FirstName, &vet.LastName, &vet.Patronymic, &vet.Phone,
+			&vet.Email, &vet.Description, &vet.ExperienceYears, &vet.IsActive, &vet.CityID, &vet.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		vets = append(vets, &vet)
+	}
+
+	return vets, nil
+}
@@ -0,0 +1,107 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/drerr0r/vetbot/internal/models"
+)
+
+// ImportRequestRepository хранит задачи импорта справочника
+type ImportRequestRepository struct {
+	db *sql.DB
+}
+
+// NewImportRequestRepository создает новый репозиторий задач импорта
+func NewImportRequestRepository(db *sql.DB) *ImportRequestRepository {
+	return &ImportRequestRepository{db: db}
+}
+
+// CreateImportRequest сохраняет новую задачу импорта и заполняет ее ID
+func (r *ImportRequestRepository) CreateImportRequest(request *models.ImportRequest) error {
+	if request.Status == "" {
+		request.Status = models.ImportStatusPending
+	}
+
+	query := `INSERT INTO import_requests (type, file_name, user_id, status)
+              VALUES ($1, $2, $3, $4) RETURNING id, created_at`
+
+	err := r.db.QueryRow(query, request.Type, request.FileName, request.UserID, request.Status).
+		Scan(&request.ID, &request.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("ошибка создания задачи импорта: %v", err)
+	}
+	return nil
+}
+
+// UpdateImportRequest сохраняет статус, прогресс и итоги задачи импорта
+func (r *ImportRequestRepository) UpdateImportRequest(request *models.ImportRequest) error {
+	var result []byte
+	if request.Result != nil {
+		var err error
+		if result, err = json.Marshal(request.Result); err != nil {
+			return fmt.Errorf("ошибка сериализации итогов импорта: %v", err)
+		}
+	}
+
+	query := `UPDATE import_requests SET status = $1, processed_rows = $2, total_rows = $3,
+              result = $4, error = $5, finished_at = $6 WHERE id = $7`
+
+	res, err := r.db.Exec(query, request.Status, request.ProcessedRows, request.TotalRows,
+		result, request.Error, request.FinishedAt, request.ID)
+	if err != nil {
+		return fmt.Errorf("ошибка обновления задачи импорта: %v", err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetRecentImportRequests возвращает последние задачи импорта, новые первыми
+func (r *ImportRequestRepository) GetRecentImportRequests(limit int) ([]*models.ImportRequest, error) {
+	query := `SELECT id, type, file_name, user_id, status, processed_rows, total_rows,
+                     result, error, created_at, finished_at
+              FROM import_requests ORDER BY created_at DESC, id DESC LIMIT $1`
+
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка загрузки задач импорта: %v", err)
+	}
+	defer rows.Close()
+
+	var requests []*models.ImportRequest
+	for rows.Next() {
+		var request models.ImportRequest
+		var result []byte
+		if err := rows.Scan(&request.ID, &request.Type, &request.FileName, &request.UserID, &request.Status,
+			&request.ProcessedRows, &request.TotalRows, &result, &request.Error,
+			&request.CreatedAt, &request.FinishedAt); err != nil {
+			return nil, err
+		}
+
+		if result != nil {
+			request.Result = &models.ImportResult{}
+			if err := json.Unmarshal(result, request.Result); err != nil {
+				return nil, fmt.Errorf("ошибка чтения итогов задачи импорта %d: %v", request.ID, err)
+			}
+		}
+		requests = append(requests, &request)
+	}
+	return requests, rows.Err()
+}
+
+// FailUnfinishedImportRequests отмечает как неудачные задачи, прерванные перезапуском бота.
+// Загруженные файлы хранятся только в памяти, поэтому продолжить такие задачи нельзя
+func (r *ImportRequestRepository) FailUnfinishedImportRequests() (int64, error) {
+	query := `UPDATE import_requests SET status = $1, error = $2, finished_at = CURRENT_TIMESTAMP
+              WHERE status IN ($3, $4, $5)`
+
+	res, err := r.db.Exec(query, models.ImportStatusFailed, "Прервано перезапуском бота",
+		models.ImportStatusPending, models.ImportStatusProcessing, models.ImportStatusReady)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка завершения прерванных задач импорта: %v", err)
+	}
+	return res.RowsAffected()
+}
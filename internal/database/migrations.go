@@ -0,0 +1,338 @@
+package database
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// migrationLockID ключ advisory lock, чтобы миграции не применялись параллельно с двух экземпляров
+const migrationLockID = 7301001
+
+// migrationFilePattern имя файла миграции: 001_init.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(\w+)\.sql$`)
+
+// Migration описывает одну миграцию схемы базы данных
+type Migration struct {
+	Version  int
+	Name     string
+	UpSQL    string
+	DownSQL  string
+	Checksum string
+}
+
+// MigrationStatus состояние миграции в базе данных
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	// Changed - файл изменился после применения (контрольная сумма не совпадает)
+	Changed bool
+	// Missing - миграция записана в базе, но файла для нее нет
+	Missing bool
+}
+
+// appliedMigration запись таблицы schema_migrations
+type appliedMigration struct {
+	Version   int
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// Migrator применяет и откатывает миграции, отмечая их в таблице schema_migrations
+type Migrator struct {
+	db         *sql.DB
+	migrations []*Migration
+}
+
+// NewMigrator создает Migrator для миграций из source (корень - файлы NNN_name.sql)
+func NewMigrator(db *sql.DB, source fs.FS) (*Migrator, error) {
+	migrations, err := LoadMigrations(source)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// LoadMigrations читает миграции и их откаты (down/NNN_name.sql), сортируя по версии
+func LoadMigrations(source fs.FS) ([]*Migration, error) {
+	entries, err := fs.ReadDir(source, ".")
+	if err != nil {
+		return nil, fmt.Errorf("error reading migrations: %v", err)
+	}
+
+	var migrations []*Migration
+	versions := make(map[int]string)
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, err := strconv.Atoi(match[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %v", entry.Name(), err)
+		}
+		if existing, ok := versions[version]; ok {
+			return nil, fmt.Errorf("duplicate migration version %d: %s and %s", version, existing, entry.Name())
+		}
+		versions[version] = entry.Name()
+
+		upSQL, err := fs.ReadFile(source, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("error reading migration %s: %v", entry.Name(), err)
+		}
+
+		downSQL, err := fs.ReadFile(source, path.Join("down", entry.Name()))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("error reading down migration %s: %v", entry.Name(), err)
+		}
+
+		checksum := sha256.Sum256(upSQL)
+		migrations = append(migrations, &Migration{
+			Version:  version,
+			Name:     match[2],
+			UpSQL:    string(upSQL),
+			DownSQL:  string(downSQL),
+			Checksum: hex.EncodeToString(checksum[:]),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Up применяет все еще не примененные миграции по порядку и возвращает примененные
+func (m *Migrator) Up() ([]*Migration, error) {
+	if err := m.ensureMigrationsTable(); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*Migration
+	for _, migration := range m.migrations {
+		if record, ok := applied[migration.Version]; ok {
+			if record.Checksum != migration.Checksum {
+				return result, fmt.Errorf("migration %s was changed after it had been applied (checksum mismatch)",
+					migration.FileName())
+			}
+			continue
+		}
+
+		done, err := m.applyMigration(migration)
+		if err != nil {
+			return result, err
+		}
+		if done {
+			log.Printf("✅ Applied migration: %s", migration.FileName())
+			result = append(result, migration)
+		}
+	}
+
+	return result, nil
+}
+
+// Down откатывает последние steps примененных миграций и возвращает откаченные
+func (m *Migrator) Down(steps int) ([]*Migration, error) {
+	if err := m.ensureMigrationsTable(); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []int
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+
+	var result []*Migration
+	for i := 0; i < steps && i < len(versions); i++ {
+		migration := m.findMigration(versions[i])
+		if migration == nil {
+			return result, fmt.Errorf("migration %03d_%s is applied but its file is missing",
+				versions[i], applied[versions[i]].Name)
+		}
+		if migration.DownSQL == "" {
+			return result, fmt.Errorf("migration %s has no down file", migration.FileName())
+		}
+
+		if err := m.revertMigration(migration); err != nil {
+			return result, err
+		}
+		log.Printf("↩️ Reverted migration: %s", migration.FileName())
+		result = append(result, migration)
+	}
+
+	return result, nil
+}
+
+// Status возвращает состояние всех известных миграций, включая записанные в базе без файла
+func (m *Migrator) Status() ([]*MigrationStatus, error) {
+	if err := m.ensureMigrationsTable(); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []*MigrationStatus
+	for _, migration := range m.migrations {
+		status := &MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if record, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = record.AppliedAt
+			status.Changed = record.Checksum != migration.Checksum
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+
+	for _, record := range applied {
+		statuses = append(statuses, &MigrationStatus{
+			Version:   record.Version,
+			Name:      record.Name,
+			Applied:   true,
+			AppliedAt: record.AppliedAt,
+			Missing:   true,
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+	return statuses, nil
+}
+
+// FileName возвращает имя файла миграции
+func (mg *Migration) FileName() string {
+	return fmt.Sprintf("%03d_%s.sql", mg.Version, mg.Name)
+}
+
+// ensureMigrationsTable создает таблицу учета миграций
+func (m *Migrator) ensureMigrationsTable() error {
+	_, err := m.db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			checksum VARCHAR(64) NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`)
+	if err != nil {
+		return fmt.Errorf("error creating schema_migrations: %v", err)
+	}
+	return nil
+}
+
+// appliedMigrations возвращает примененные миграции по версиям
+func (m *Migrator) appliedMigrations() (map[int]*appliedMigration, error) {
+	rows, err := m.db.Query(`SELECT version, name, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("error reading schema_migrations: %v", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]*appliedMigration)
+	for rows.Next() {
+		var record appliedMigration
+		if err := rows.Scan(&record.Version, &record.Name, &record.Checksum, &record.AppliedAt); err != nil {
+			return nil, err
+		}
+		applied[record.Version] = &record
+	}
+	return applied, rows.Err()
+}
+
+// applyMigration применяет миграцию в транзакции. Возвращает false, если ее уже применил другой экземпляр
+func (m *Migrator) applyMigration(migration *Migration) (bool, error) {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock($1)`, migrationLockID); err != nil {
+		return false, fmt.Errorf("error locking migrations: %v", err)
+	}
+
+	var exists bool
+	err = tx.QueryRow(`SELECT EXISTS(SELECT 1 FROM schema_migrations WHERE version = $1)`,
+		migration.Version).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		return false, nil
+	}
+
+	if _, err := tx.Exec(migration.UpSQL); err != nil {
+		return false, fmt.Errorf("error applying migration %s: %v", migration.FileName(), err)
+	}
+
+	_, err = tx.Exec(`INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`,
+		migration.Version, migration.Name, migration.Checksum)
+	if err != nil {
+		return false, fmt.Errorf("error recording migration %s: %v", migration.FileName(), err)
+	}
+
+	return true, tx.Commit()
+}
+
+// revertMigration выполняет откат миграции в транзакции и удаляет отметку о ней
+func (m *Migrator) revertMigration(migration *Migration) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock($1)`, migrationLockID); err != nil {
+		return fmt.Errorf("error locking migrations: %v", err)
+	}
+
+	if _, err := tx.Exec(migration.DownSQL); err != nil {
+		return fmt.Errorf("error reverting migration %s: %v", migration.FileName(), err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM schema_migrations WHERE version = $1`, migration.Version); err != nil {
+		return fmt.Errorf("error removing migration record %s: %v", migration.FileName(), err)
+	}
+
+	return tx.Commit()
+}
+
+// findMigration ищет миграцию по версии
+func (m *Migrator) findMigration(version int) *Migration {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration
+		}
+	}
+	return nil
+}
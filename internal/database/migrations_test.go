@@ -0,0 +1,146 @@
+package database
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/drerr0r/vetbot/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	t.Run("Sorted by version with down files", func(t *testing.T) {
+		source := fstest.MapFS{
+			"010_add_index.sql":      {Data: []byte("CREATE INDEX idx ON t(id);")},
+			"002_add_table.sql":      {Data: []byte("CREATE TABLE t (id INT);")},
+			"down/002_add_table.sql": {Data: []byte("DROP TABLE t;")},
+			"README.md":              {Data: []byte("not a migration")},
+		}
+
+		loaded, err := LoadMigrations(source)
+		require.NoError(t, err)
+		require.Len(t, loaded, 2)
+
+		assert.Equal(t, 2, loaded[0].Version)
+		assert.Equal(t, "add_table", loaded[0].Name)
+		assert.Equal(t, "DROP TABLE t;", loaded[0].DownSQL)
+		assert.Len(t, loaded[0].Checksum, 64)
+		assert.Equal(t, "002_add_table.sql", loaded[0].FileName())
+
+		assert.Equal(t, 10, loaded[1].Version)
+		assert.Empty(t, loaded[1].DownSQL)
+	})
+
+	t.Run("Checksum depends on content", func(t *testing.T) {
+		first, err := LoadMigrations(fstest.MapFS{"001_init.sql": {Data: []byte("SELECT 1;")}})
+		require.NoError(t, err)
+		second, err := LoadMigrations(fstest.MapFS{"001_init.sql": {Data: []byte("SELECT 2;")}})
+		require.NoError(t, err)
+
+		assert.NotEqual(t, first[0].Checksum, second[0].Checksum)
+	})
+
+	t.Run("Duplicate version", func(t *testing.T) {
+		_, err := LoadMigrations(fstest.MapFS{
+			"003_first.sql":  {Data: []byte("SELECT 1;")},
+			"003_second.sql": {Data: []byte("SELECT 2;")},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("Embedded migrations", func(t *testing.T) {
+		loaded, err := LoadMigrations(migrations.FS)
+		require.NoError(t, err)
+		require.NotEmpty(t, loaded)
+
+		for i, migration := range loaded {
+			assert.Equal(t, i+1, migration.Version, "migrations must be numbered without gaps")
+			assert.NotEmpty(t, strings.TrimSpace(migration.DownSQL), "%s has no down file", migration.FileName())
+		}
+	})
+}
+
+func TestMigrator_Integration(t *testing.T) {
+	config := GetTestConfig()
+	db := SetupTestDatabase(t, config)
+	if db == nil {
+		return // Тест был пропущен
+	}
+	defer db.Close()
+
+	// Отдельная схема, чтобы не пересекаться с таблицами остальных тестов
+	const schema = "migrator_test"
+	_, err := db.GetDB().Exec(`DROP SCHEMA IF EXISTS ` + schema + ` CASCADE; CREATE SCHEMA ` + schema)
+	require.NoError(t, err)
+	defer db.GetDB().Exec(`DROP SCHEMA IF EXISTS ` + schema + ` CASCADE`)
+
+	separator := "?"
+	if strings.Contains(config.DatabaseURL, "?") {
+		separator = "&"
+	}
+	schemaDB, err := sql.Open("postgres", config.DatabaseURL+separator+"search_path="+schema)
+	require.NoError(t, err)
+	defer schemaDB.Close()
+
+	migrator, err := NewMigrator(schemaDB, migrations.FS)
+	require.NoError(t, err)
+	total := len(migrator.migrations)
+
+	t.Run("Up applies everything once", func(t *testing.T) {
+		applied, err := migrator.Up()
+		require.NoError(t, err)
+		assert.Len(t, applied, total)
+
+		applied, err = migrator.Up()
+		require.NoError(t, err)
+		assert.Empty(t, applied)
+
+		statuses, err := migrator.Status()
+		require.NoError(t, err)
+		require.Len(t, statuses, total)
+		for _, status := range statuses {
+			assert.True(t, status.Applied)
+			assert.False(t, status.Changed)
+		}
+	})
+
+	t.Run("Down reverts the last migration", func(t *testing.T) {
+		reverted, err := migrator.Down(1)
+		require.NoError(t, err)
+		require.Len(t, reverted, 1)
+		assert.Equal(t, total, reverted[0].Version)
+
+		statuses, err := migrator.Status()
+		require.NoError(t, err)
+		assert.False(t, statuses[total-1].Applied)
+
+		applied, err := migrator.Up()
+		require.NoError(t, err)
+		assert.Len(t, applied, 1)
+	})
+
+	t.Run("Full rollback and reapply", func(t *testing.T) {
+		reverted, err := migrator.Down(total)
+		require.NoError(t, err)
+		assert.Len(t, reverted, total)
+
+		applied, err := migrator.Up()
+		require.NoError(t, err)
+		assert.Len(t, applied, total)
+	})
+
+	t.Run("Changed migration is rejected", func(t *testing.T) {
+		_, err := schemaDB.Exec(`UPDATE schema_migrations SET checksum = 'changed' WHERE version = 1`)
+		require.NoError(t, err)
+
+		_, err = migrator.Up()
+		assert.Error(t, err)
+
+		statuses, err := migrator.Status()
+		require.NoError(t, err)
+		assert.True(t, statuses[0].Changed)
+	})
+}
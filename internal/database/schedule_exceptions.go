@@ -0,0 +1,145 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/drerr0r/vetbot/internal/models"
+)
+
+// ScheduleExceptionRepository содержит методы для исключений из расписания врачей
+type ScheduleExceptionRepository struct {
+	db *sql.DB
+}
+
+// NewScheduleExceptionRepository создает новый репозиторий исключений из расписания
+func NewScheduleExceptionRepository(db *sql.DB) *ScheduleExceptionRepository {
+	return &ScheduleExceptionRepository{db: db}
+}
+
+// CreateScheduleException добавляет исключение из расписания врача
+func (r *ScheduleExceptionRepository) CreateScheduleException(exception *models.ScheduleException) error {
+	if err := exception.Validate(); err != nil {
+		return err
+	}
+
+	var startTime, endTime sql.NullString
+	if exception.IsAvailable {
+		startTime = sql.NullString{String: exception.StartTime, Valid: true}
+		endTime = sql.NullString{String: exception.EndTime, Valid: true}
+	}
+
+	query := `INSERT INTO schedule_exceptions (vet_id, clinic_id, date_from, date_to, is_available, start_time, end_time, reason)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at`
+
+	err := r.db.QueryRow(query,
+		exception.VetID, exception.ClinicID,
+		exception.DateFrom.Format("2006-01-02"), exception.DateTo.Format("2006-01-02"),
+		exception.IsAvailable, startTime, endTime, exception.Reason,
+	).Scan(&exception.ID, &exception.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("ошибка создания исключения из расписания: %v", err)
+	}
+	return nil
+}
+
+// DeleteScheduleException удаляет исключение из расписания
+func (r *ScheduleExceptionRepository) DeleteScheduleException(id int) error {
+	result, err := r.db.Exec("DELETE FROM schedule_exceptions WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("ошибка удаления исключения из расписания: %v", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetScheduleExceptionsByVetID возвращает исключения врача, которые еще не закончились к дате from
+func (r *ScheduleExceptionRepository) GetScheduleExceptionsByVetID(vetID int, from time.Time) ([]*models.ScheduleException, error) {
+	query := `
+		SELECT e.id, e.vet_id, e.clinic_id, e.date_from, e.date_to, e.is_available,
+		       COALESCE(TO_CHAR(e.start_time, 'HH24:MI'), ''), COALESCE(TO_CHAR(e.end_time, 'HH24:MI'), ''),
+		       e.reason, e.created_at, c.name
+		FROM schedule_exceptions e
+		LEFT JOIN clinics c ON e.clinic_id = c.id
+		WHERE e.vet_id = $1 AND e.date_to >= $2::date
+		ORDER BY e.date_from, e.id`
+
+	rows, err := r.db.Query(query, vetID, from.Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения исключений из расписания: %v", err)
+	}
+	defer rows.Close()
+
+	var exceptions []*models.ScheduleException
+	for rows.Next() {
+		var exception models.ScheduleException
+		var clinicName sql.NullString
+		err := rows.Scan(&exception.ID, &exception.VetID, &exception.ClinicID,
+			&exception.DateFrom, &exception.DateTo, &exception.IsAvailable,
+			&exception.StartTime, &exception.EndTime, &exception.Reason, &exception.CreatedAt, &clinicName)
+		if err != nil {
+			return nil, err
+		}
+		if exception.ClinicID.Valid {
+			exception.Clinic = &models.Clinic{ID: int(exception.ClinicID.Int64), Name: clinicName.String}
+		}
+		exceptions = append(exceptions, &exception)
+	}
+
+	return exceptions, rows.Err()
+}
+
+// clinicTodayQuery возвращает местную дату клиники из колонки clinicColumn по часовому поясу ее города
+func clinicTodayQuery(clinicColumn string) string {
+	return fmt.Sprintf(`(SELECT (CURRENT_TIMESTAMP AT TIME ZONE COALESCE(tc.timezone, '%s'))::date
+				FROM clinics tcl LEFT JOIN cities tc ON tcl.city_id = tc.id WHERE tcl.id = %s)`,
+		models.DefaultTimezone, clinicColumn)
+}
+
+// effectiveSchedulesQuery возвращает подзапрос с приемами врачей на 7 дней начиная с даты
+// в параметре fromArg, сдвинутой на firstDay дней, с учетом исключений: отсутствие убирает
+// приемы, другие часы заменяют обычные приемы в клинике. Пустой fromArg - сегодняшний день
+// по местному времени каждой клиники. Колонки как у schedules плюс work_date - дата приема.
+// Каждый день недели встречается ровно один раз, поэтому условия по day_of_week
+// относятся к ближайшей такой дате. firstDay = -1 нужен для "сейчас": ночная смена,
+// начавшаяся вчера, должна проверяться по вчерашней дате, а не по дате через неделю
+func effectiveSchedulesQuery(fromArg string, firstDay int) string {
+	from := func(clinicColumn string) string {
+		if fromArg == "" {
+			return clinicTodayQuery(clinicColumn)
+		}
+		return fromArg + "::date"
+	}
+
+	return fmt.Sprintf(`(
+			SELECT ws.id, ws.vet_id, ws.clinic_id, ws.day_of_week, ws.start_time, ws.end_time,
+			       ws.is_available, ws.created_at, d.work_date
+			FROM schedules ws
+			JOIN LATERAL (SELECT %[1]s + n AS work_date FROM generate_series(%[3]d, %[3]d + 6) AS n) d
+			  ON ws.day_of_week = EXTRACT(ISODOW FROM d.work_date)
+			WHERE ws.is_available = true
+			  AND NOT EXISTS (
+				SELECT 1 FROM schedule_exceptions se
+				WHERE se.vet_id = ws.vet_id AND d.work_date BETWEEN se.date_from AND se.date_to
+				  AND (se.clinic_id IS NULL OR se.clinic_id = ws.clinic_id))
+			UNION ALL
+			SELECT 0, se.vet_id, se.clinic_id, EXTRACT(ISODOW FROM d.work_date)::int, se.start_time, se.end_time,
+			       true, se.created_at, d.work_date
+			FROM schedule_exceptions se
+			JOIN LATERAL (SELECT %[2]s + n AS work_date FROM generate_series(%[3]d, %[3]d + 6) AS n) d
+			  ON d.work_date BETWEEN se.date_from AND se.date_to
+			WHERE se.is_available = true
+			  AND NOT EXISTS (
+				SELECT 1 FROM schedule_exceptions sa
+				WHERE sa.vet_id = se.vet_id AND sa.is_available = false
+				  AND d.work_date BETWEEN sa.date_from AND sa.date_to
+				  AND (sa.clinic_id IS NULL OR sa.clinic_id = se.clinic_id))
+		)`, from("ws.clinic_id"), from("se.clinic_id"), firstDay)
+}
@@ -0,0 +1,127 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drerr0r/vetbot/internal/models"
+	"github.com/drerr0r/vetbot/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// zoneWithLocalHour возвращает часовой пояс Etc/GMT, в котором сейчас localHour часов, и его сдвиг
+func zoneWithLocalHour(now time.Time, localHour int) (string, time.Duration) {
+	offset := ((localHour-now.UTC().Hour())%24 + 24) % 24
+	if offset > 14 {
+		offset -= 24
+	}
+	// В названиях Etc/GMT знак обратный: Etc/GMT-3 - это UTC+3
+	name := "Etc/GMT"
+	if offset > 0 {
+		name = fmt.Sprintf("Etc/GMT-%d", offset)
+	} else if offset < 0 {
+		name = fmt.Sprintf("Etc/GMT+%d", -offset)
+	}
+	return name, time.Duration(offset) * time.Hour
+}
+
+// setupMigratedSchema создает отдельную схему с настоящими миграциями и возвращает подключение к ней.
+// Схема удаляется по окончании теста
+func setupMigratedSchema(t *testing.T, config *TestConfig, db *Database, schema string) (*sql.DB, *Database) {
+	_, err := db.GetDB().Exec(`DROP SCHEMA IF EXISTS ` + schema + ` CASCADE; CREATE SCHEMA ` + schema)
+	require.NoError(t, err)
+
+	separator := "?"
+	if strings.Contains(config.DatabaseURL, "?") {
+		separator = "&"
+	}
+	schemaDB, err := sql.Open("postgres", config.DatabaseURL+separator+"search_path="+schema)
+	require.NoError(t, err)
+	// Основное подключение к этому моменту может быть уже закрыто, поэтому схему удаляем через свое
+	t.Cleanup(func() {
+		schemaDB.Exec(`DROP SCHEMA IF EXISTS ` + schema + ` CASCADE`)
+		schemaDB.Close()
+	})
+
+	migrator, err := NewMigrator(schemaDB, migrations.FS)
+	require.NoError(t, err)
+	_, err = migrator.Up()
+	require.NoError(t, err)
+
+	return schemaDB, &Database{db: schemaDB, users: NewUserRepository(schemaDB), reviews: NewReviewRepository(schemaDB)}
+}
+
+func TestSearchVetsOpenNow_Integration(t *testing.T) {
+	config := GetTestConfig()
+	db := SetupTestDatabase(t, config)
+	if db == nil {
+		return // Тест был пропущен
+	}
+	defer db.Close()
+
+	// Нужны исключения и часовые пояса городов
+	schemaDB, schemaDatabase := setupMigratedSchema(t, config, db, "open_now_test")
+
+	// Ночная смена 22:00-04:00. Там, где сейчас около часа ночи, смена началась вчера по местному
+	// времени, а где около 23 часов - сегодня. Местные даты городов в этот момент различаются,
+	// и исключения каждой клиники должны сверяться с датой начала смены в ее городе
+	cities := []struct {
+		name      string
+		localHour int
+		// shiftStartDays - сдвиг даты начала идущей сейчас смены от местной даты
+		shiftStartDays int
+	}{
+		{name: "Ночной город", localHour: 1, shiftStartDays: -1},
+		{name: "Вечерний город", localHour: 23, shiftStartDays: 0},
+	}
+
+	for _, city := range cities {
+		t.Run(city.name, func(t *testing.T) {
+			zone, offset := zoneWithLocalHour(time.Now(), city.localHour)
+			localNow := time.Now().UTC().Add(offset)
+			shiftDate := time.Date(localNow.Year(), localNow.Month(), localNow.Day(), 0, 0, 0, 0, time.UTC).
+				AddDate(0, 0, city.shiftStartDays)
+
+			var cityID, vetID, clinicID int
+			require.NoError(t, schemaDB.QueryRow(`INSERT INTO cities (name, region, timezone) VALUES ($1, 'Тест', $2) RETURNING id`,
+				city.name, zone).Scan(&cityID))
+			require.NoError(t, schemaDB.QueryRow(`INSERT INTO veterinarians (first_name, last_name, phone, city_id)
+				VALUES ('Ночной', 'Врач', $1, $2) RETURNING id`, fmt.Sprintf("+7999000%04d", cityID), cityID).Scan(&vetID))
+			require.NoError(t, schemaDB.QueryRow(`INSERT INTO clinics (name, address, city_id)
+				VALUES ('Круглосуточная', 'ул. Ночная, 1', $1) RETURNING id`, cityID).Scan(&clinicID))
+			_, err := schemaDB.Exec(`INSERT INTO schedules (vet_id, clinic_id, day_of_week, start_time, end_time)
+				VALUES ($1, $2, $3, '22:00', '04:00')`, vetID, clinicID, models.ScheduleDay(shiftDate))
+			require.NoError(t, err)
+
+			openNow := func() bool {
+				vets, err := schemaDatabase.SearchVets(&models.SearchCriteria{OpenNow: true, CityID: cityID})
+				require.NoError(t, err)
+				for _, vet := range vets {
+					if models.GetVetIDAsIntOrZero(vet) == vetID {
+						return true
+					}
+				}
+				return false
+			}
+			setAbsence := func(date time.Time) {
+				_, err := schemaDB.Exec(`DELETE FROM schedule_exceptions WHERE vet_id = $1`, vetID)
+				require.NoError(t, err)
+				_, err = schemaDB.Exec(`INSERT INTO schedule_exceptions (vet_id, date_from, date_to, reason)
+					VALUES ($1, $2, $2, 'отпуск')`, vetID, date.Format("2006-01-02"))
+				require.NoError(t, err)
+			}
+
+			assert.True(t, openNow(), "смена идет без исключений")
+
+			setAbsence(shiftDate)
+			assert.False(t, openNow(), "отсутствие в день начала смены снимает ее")
+
+			setAbsence(shiftDate.AddDate(0, 0, 7))
+			assert.True(t, openNow(), "отсутствие через неделю не влияет на идущую смену")
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/drerr0r/vetbot/internal/models"
+)
+
+// ScheduleRepository содержит методы для изменения расписания врачей
+type ScheduleRepository struct {
+	db *sql.DB
+}
+
+// NewScheduleRepository создает новый репозиторий расписания
+func NewScheduleRepository(db *sql.DB) *ScheduleRepository {
+	return &ScheduleRepository{db: db}
+}
+
+// GetScheduleByID возвращает прием по ID (в том числе недоступный)
+func (r *ScheduleRepository) GetScheduleByID(id int) (*models.Schedule, error) {
+	query := `SELECT id, vet_id, clinic_id, day_of_week,
+	                 TO_CHAR(start_time, 'HH24:MI'), TO_CHAR(end_time, 'HH24:MI'),
+	                 is_available, created_at
+	          FROM schedules WHERE id = $1`
+
+	var schedule models.Schedule
+	err := r.db.QueryRow(query, id).Scan(
+		&schedule.ID, &schedule.VetID, &schedule.ClinicID, &schedule.DayOfWeek,
+		&schedule.StartTime, &schedule.EndTime, &schedule.IsAvailable, &schedule.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// GetAllSchedulesByVetID возвращает все приемы врача, включая выключенные, с названиями клиник.
+// Нужен для редактирования расписания: поиск и карточки используют только доступные приемы
+func (r *ScheduleRepository) GetAllSchedulesByVetID(vetID int) ([]*models.Schedule, error) {
+	query := `SELECT s.id, s.vet_id, s.clinic_id, s.day_of_week,
+	                 TO_CHAR(s.start_time, 'HH24:MI'), TO_CHAR(s.end_time, 'HH24:MI'),
+	                 s.is_available, s.created_at, COALESCE(c.name, '')
+	          FROM schedules s
+	          LEFT JOIN clinics c ON s.clinic_id = c.id
+	          WHERE s.vet_id = $1
+	          ORDER BY c.name, s.day_of_week, s.start_time, s.id`
+
+	rows, err := r.db.Query(query, vetID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения расписания врача: %v", err)
+	}
+	defer rows.Close()
+
+	var schedules []*models.Schedule
+	for rows.Next() {
+		var schedule models.Schedule
+		var clinicName string
+		err := rows.Scan(
+			&schedule.ID, &schedule.VetID, &schedule.ClinicID, &schedule.DayOfWeek,
+			&schedule.StartTime, &schedule.EndTime, &schedule.IsAvailable, &schedule.CreatedAt, &clinicName,
+		)
+		if err != nil {
+			return nil, err
+		}
+		schedule.Clinic = &models.Clinic{ID: schedule.ClinicID, Name: clinicName}
+		schedules = append(schedules, &schedule)
+	}
+	return schedules, rows.Err()
+}
+
+// GetWeekSchedulesByVetID возвращает приемы врача на 7 дней с сегодняшнего дня по местному времени
+// клиник с учетом исключений, только в активных клиниках. Каждый день недели встречается один раз,
+// у приемов с другими часами на дату ID = 0
+func (r *ScheduleRepository) GetWeekSchedulesByVetID(vetID int) ([]*models.Schedule, error) {
+	query := `SELECT s.id, s.vet_id, s.clinic_id, s.day_of_week,
+	                 TO_CHAR(s.start_time, 'HH24:MI'), TO_CHAR(s.end_time, 'HH24:MI'),
+	                 s.is_available, s.created_at,
+	                 c.id, c.name, c.address, c.phone, c.working_hours, c.is_active, c.city_id,
+	                 c.district, c.metro_station, c.latitude, c.longitude, c.created_at,
+	                 COALESCE(ct.name, ''), COALESCE(ct.region, ''), COALESCE(ct.timezone, '')
+	          FROM ` + effectiveSchedulesQuery("", 0) + ` s
+	          JOIN clinics c ON s.clinic_id = c.id
+	          LEFT JOIN cities ct ON c.city_id = ct.id
+	          WHERE s.vet_id = $1 AND c.is_active = true
+	          ORDER BY s.day_of_week, s.start_time`
+
+	rows, err := r.db.Query(query, vetID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения расписания врача на неделю: %v", err)
+	}
+	defer rows.Close()
+
+	var schedules []*models.Schedule
+	for rows.Next() {
+		var schedule models.Schedule
+		var clinic models.Clinic
+		var cityName, cityRegion, cityTimezone string
+		err := rows.Scan(
+			&schedule.ID, &schedule.VetID, &schedule.ClinicID, &schedule.DayOfWeek,
+			&schedule.StartTime, &schedule.EndTime, &schedule.IsAvailable, &schedule.CreatedAt,
+			&clinic.ID, &clinic.Name, &clinic.Address, &clinic.Phone, &clinic.WorkingHours,
+			&clinic.IsActive, &clinic.CityID, &clinic.District, &clinic.MetroStation,
+			&clinic.Latitude, &clinic.Longitude, &clinic.CreatedAt,
+			&cityName, &cityRegion, &cityTimezone,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if clinic.CityID.Valid {
+			clinic.City = &models.City{ID: int(clinic.CityID.Int64), Name: cityName, Region: cityRegion, Timezone: cityTimezone}
+		}
+		schedule.Clinic = &clinic
+		schedules = append(schedules, &schedule)
+	}
+	return schedules, rows.Err()
+}
+
+// linkVetClinicQuery привязывает врача к клинике приема из CTE source, чтобы vet_clinics
+// не расходилась с расписанием
+const linkVetClinicQuery = `linked AS (
+	              INSERT INTO vet_clinics (vet_id, clinic_id) SELECT vet_id, clinic_id FROM %s
+	              ON CONFLICT (vet_id, clinic_id) DO NOTHING)`
+
+// CreateSchedule добавляет прием врача в клинике и привязывает врача к клинике
+func (r *ScheduleRepository) CreateSchedule(schedule *models.Schedule) error {
+	query := `WITH inserted AS (
+	              INSERT INTO schedules (vet_id, clinic_id, day_of_week, start_time, end_time, is_available)
+	              VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, vet_id, clinic_id, created_at),
+	          ` + fmt.Sprintf(linkVetClinicQuery, "inserted") + `
+	          SELECT id, created_at FROM inserted`
+
+	err := r.db.QueryRow(query,
+		schedule.VetID, schedule.ClinicID, schedule.DayOfWeek,
+		schedule.StartTime, schedule.EndTime, schedule.IsAvailable,
+	).Scan(&schedule.ID, &schedule.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("ошибка создания расписания: %v", err)
+	}
+	return nil
+}
+
+// UpdateSchedule обновляет прием; при переносе в другую клинику врач привязывается к ней
+func (r *ScheduleRepository) UpdateSchedule(schedule *models.Schedule) error {
+	query := `WITH updated AS (
+	              UPDATE schedules
+	              SET vet_id = $1, clinic_id = $2, day_of_week = $3, start_time = $4, end_time = $5, is_available = $6
+	              WHERE id = $7 RETURNING vet_id, clinic_id),
+	          ` + fmt.Sprintf(linkVetClinicQuery, "updated") + `
+	          SELECT COUNT(*) FROM updated`
+
+	var affected int
+	err := r.db.QueryRow(query,
+		schedule.VetID, schedule.ClinicID, schedule.DayOfWeek,
+		schedule.StartTime, schedule.EndTime, schedule.IsAvailable, schedule.ID,
+	).Scan(&affected)
+	if err != nil {
+		return fmt.Errorf("ошибка обновления расписания: %v", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteSchedule удаляет прием
+func (r *ScheduleRepository) DeleteSchedule(id int) error {
+	result, err := r.db.Exec("DELETE FROM schedules WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("ошибка удаления расписания: %v", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
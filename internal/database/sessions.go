@@ -0,0 +1,230 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/drerr0r/vetbot/internal/models"
+)
+
+// sessionValue - значение из данных сессии вместе с именем его типа.
+// JSON теряет типы (int превращается в float64), поэтому тип сохраняется явно
+type sessionValue struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+var (
+	sessionTypesMutex sync.RWMutex
+	sessionTypes      = make(map[string]reflect.Type)
+)
+
+func init() {
+	for _, sample := range []interface{}{
+		"", 0, int64(0), float64(0), false,
+		[]int{}, []string{}, map[string]string{},
+		&models.Review{}, []*models.Review{},
+		// Данные админ-панели
+		&models.VetEditData{}, &models.ClinicEditData{}, &models.CityEditData{},
+		&models.SpecializationEditData{}, &models.City{}, []*models.City{},
+	} {
+		RegisterSessionValueType(sample)
+	}
+}
+
+// RegisterSessionValueType регистрирует тип значения, которое можно сохранять в данных сессии.
+// Сессию со значением незарегистрированного типа сохранить нельзя
+func RegisterSessionValueType(sample interface{}) {
+	t := reflect.TypeOf(sample)
+	sessionTypesMutex.Lock()
+	defer sessionTypesMutex.Unlock()
+	sessionTypes[t.String()] = t
+}
+
+// encodeSessionData сериализует данные сессии с сохранением типов значений.
+// Значение незарегистрированного типа - ошибка: молча потерянные данные всплыли бы только после перезапуска
+func encodeSessionData(data map[string]interface{}) ([]byte, error) {
+	sessionTypesMutex.RLock()
+	defer sessionTypesMutex.RUnlock()
+
+	encoded := make(map[string]sessionValue, len(data))
+	for key, value := range data {
+		if value == nil {
+			continue
+		}
+
+		typeName := reflect.TypeOf(value).String()
+		if _, ok := sessionTypes[typeName]; !ok {
+			return nil, fmt.Errorf("тип %s ключа %s не зарегистрирован для хранения в сессии", typeName, key)
+		}
+
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка сериализации ключа %s: %v", key, err)
+		}
+		encoded[key] = sessionValue{Type: typeName, Value: raw}
+	}
+
+	return json.Marshal(encoded)
+}
+
+// decodeSessionData восстанавливает данные сессии с исходными типами значений
+func decodeSessionData(raw []byte) (map[string]interface{}, error) {
+	var encoded map[string]sessionValue
+	if err := json.Unmarshal(raw, &encoded); err != nil {
+		return nil, err
+	}
+
+	sessionTypesMutex.RLock()
+	defer sessionTypesMutex.RUnlock()
+
+	data := make(map[string]interface{}, len(encoded))
+	for key, value := range encoded {
+		t, ok := sessionTypes[value.Type]
+		if !ok {
+			log.Printf("Session data key %q has unknown type %s, skipped", key, value.Type)
+			continue
+		}
+
+		ptr := reflect.New(t)
+		if err := json.Unmarshal(value.Value, ptr.Interface()); err != nil {
+			return nil, fmt.Errorf("ошибка десериализации ключа %s: %v", key, err)
+		}
+		data[key] = ptr.Elem().Interface()
+	}
+
+	return data, nil
+}
+
+// SessionRepository хранит сессии пользователей в PostgreSQL
+type SessionRepository struct {
+	db *sql.DB
+}
+
+// NewSessionRepository создает новый репозиторий сессий
+func NewSessionRepository(db *sql.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// LoadSession загружает сессию пользователя. Если сессии нет, возвращает nil
+func (r *SessionRepository) LoadSession(userID int64) (*models.UserSession, error) {
+	query := `SELECT telegram_id, state, data, history, admin_state, admin_data, updated_at
+              FROM user_sessions WHERE telegram_id = $1`
+
+	session, err := scanSession(r.db.QueryRow(query, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка загрузки сессии: %v", err)
+	}
+	return session, nil
+}
+
+// ListIdleSessions возвращает сессии без активности с момента before
+func (r *SessionRepository) ListIdleSessions(before time.Time) ([]*models.UserSession, error) {
+	query := `SELECT telegram_id, state, data, history, admin_state, admin_data, updated_at
+              FROM user_sessions WHERE updated_at < $1 ORDER BY updated_at`
+
+	rows, err := r.db.Query(query, before)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка загрузки неактивных сессий: %v", err)
+	}
+	defer rows.Close()
+
+	var sessions []*models.UserSession
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+// rowScanner - общий интерфейс sql.Row и sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanSession читает сессию из строки результата запроса
+func scanSession(row rowScanner) (*models.UserSession, error) {
+	var session models.UserSession
+	var data, history, adminData []byte
+	if err := row.Scan(&session.UserID, &session.State, &data, &history,
+		&session.AdminState, &adminData, &session.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	var err error
+	session.Data, err = decodeSessionData(data)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения данных сессии: %v", err)
+	}
+	if err := json.Unmarshal(history, &session.History); err != nil {
+		return nil, fmt.Errorf("ошибка чтения истории сессии: %v", err)
+	}
+	session.AdminData, err = decodeSessionData(adminData)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения данных админ-панели: %v", err)
+	}
+
+	return &session, nil
+}
+
+// SaveSession сохраняет сессию пользователя
+func (r *SessionRepository) SaveSession(session *models.UserSession) error {
+	data, err := encodeSessionData(session.Data)
+	if err != nil {
+		return err
+	}
+	adminData, err := encodeSessionData(session.AdminData)
+	if err != nil {
+		return err
+	}
+
+	history := session.History
+	if history == nil {
+		history = []string{}
+	}
+	historyJSON, err := json.Marshal(history)
+	if err != nil {
+		return err
+	}
+
+	if session.UpdatedAt.IsZero() {
+		session.UpdatedAt = time.Now()
+	}
+
+	query := `INSERT INTO user_sessions (telegram_id, state, data, history, admin_state, admin_data, updated_at)
+              VALUES ($1, $2, $3, $4, $5, $6, $7)
+              ON CONFLICT (telegram_id) DO UPDATE SET
+              state = EXCLUDED.state,
+              data = EXCLUDED.data,
+              history = EXCLUDED.history,
+              admin_state = EXCLUDED.admin_state,
+              admin_data = EXCLUDED.admin_data,
+              updated_at = EXCLUDED.updated_at`
+
+	_, err = r.db.Exec(query, session.UserID, session.State, data, historyJSON,
+		session.AdminState, adminData, session.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения сессии: %v", err)
+	}
+	return nil
+}
+
+// DeleteSession удаляет сессию пользователя
+func (r *SessionRepository) DeleteSession(userID int64) error {
+	_, err := r.db.Exec("DELETE FROM user_sessions WHERE telegram_id = $1", userID)
+	if err != nil {
+		return fmt.Errorf("ошибка удаления сессии: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/drerr0r/vetbot/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionDataCodec(t *testing.T) {
+	t.Run("Types are preserved", func(t *testing.T) {
+		data := map[string]interface{}{
+			"review_vet_id":  5,
+			"name":           "Иван",
+			"confirmed":      true,
+			"current_review": &models.Review{ID: 7, Rating: 4, Comment: "Отличный врач"},
+			"pending_reviews": []*models.Review{
+				{ID: 1, Rating: 5},
+				{ID: 2, Rating: 3},
+			},
+		}
+
+		raw, err := encodeSessionData(data)
+		require.NoError(t, err)
+
+		decoded, err := decodeSessionData(raw)
+		require.NoError(t, err)
+
+		assert.Equal(t, 5, decoded["review_vet_id"])
+		assert.Equal(t, "Иван", decoded["name"])
+		assert.Equal(t, true, decoded["confirmed"])
+
+		review, ok := decoded["current_review"].(*models.Review)
+		require.True(t, ok)
+		assert.Equal(t, 7, review.ID)
+		assert.Equal(t, "Отличный врач", review.Comment)
+
+		reviews, ok := decoded["pending_reviews"].([]*models.Review)
+		require.True(t, ok)
+		assert.Len(t, reviews, 2)
+	})
+
+	t.Run("Admin panel data", func(t *testing.T) {
+		raw, err := encodeSessionData(map[string]interface{}{
+			"vet_edit": &models.VetEditData{VetID: 3, Field: "phone", CurrentValue: "+79001234567"},
+			"cities":   []*models.City{{ID: 1, Name: "Москва"}},
+		})
+		require.NoError(t, err)
+
+		decoded, err := decodeSessionData(raw)
+		require.NoError(t, err)
+
+		vetData, ok := decoded["vet_edit"].(*models.VetEditData)
+		require.True(t, ok)
+		assert.Equal(t, 3, vetData.VetID)
+		assert.Equal(t, "phone", vetData.Field)
+
+		cities, ok := decoded["cities"].([]*models.City)
+		require.True(t, ok)
+		require.Len(t, cities, 1)
+		assert.Equal(t, "Москва", cities[0].Name)
+	})
+
+	t.Run("Unregistered types are rejected", func(t *testing.T) {
+		type unknown struct{ Value int }
+
+		_, err := encodeSessionData(map[string]interface{}{
+			"unknown": unknown{Value: 1},
+			"known":   1,
+		})
+		assert.Error(t, err)
+	})
+}
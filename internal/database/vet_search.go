@@ -0,0 +1,185 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/drerr0r/vetbot/internal/models"
+	"github.com/lib/pq"
+)
+
+// VetSearchRepository содержит методы для поиска врачей по ФИО
+type VetSearchRepository struct {
+	db *sql.DB
+}
+
+// NewVetSearchRepository создает новый репозиторий поиска врачей по ФИО
+func NewVetSearchRepository(db *sql.DB) *VetSearchRepository {
+	return &VetSearchRepository{db: db}
+}
+
+// vetSearchNameSQL ФИО врача одной строкой в виде models.NormalizeName: нижний регистр, ё -> е,
+// без ь/ъ, пробелов, дефисов и цифр
+const vetSearchNameSQL = `regexp_replace(translate(LOWER(v.last_name || v.first_name || COALESCE(v.patronymic, '')), 'ёьъ', 'е'),
+		'[[:punct:][:space:][:digit:]]', '', 'g')`
+
+// SearchVeterinariansByName ищет активных врачей по фамилии, имени и отчеству с учетом опечаток
+// и латинской транслитерации. База отбирает кандидатов по кускам слов запроса
+// (models.NameSearchFragments), ранжирование выполняет models.RankVetsByName
+func (r *VetSearchRepository) SearchVeterinariansByName(query string, limit int) ([]*models.Veterinarian, error) {
+	fragments := models.NameSearchFragments(query)
+	if len(fragments) == 0 {
+		return nil, nil
+	}
+
+	// Каждое слово запроса должно найтись в ФИО хотя бы одним куском. ФИО, записанные латиницей,
+	// SQL не транслитерирует, поэтому такие врачи проверяются только в Go
+	var conditions []string
+	args := make([]interface{}, 0, len(fragments))
+	for _, pieces := range fragments {
+		args = append(args, pq.Array(pieces))
+		conditions = append(conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM unnest($%d::text[]) AS piece WHERE strpos(%s, piece) > 0)", len(args), vetSearchNameSQL))
+	}
+
+	sqlQuery := `
+		SELECT v.id, v.first_name, v.last_name, v.patronymic, v.phone, v.email,
+		       v.description, v.experience_years, v.is_active, v.city_id, v.created_at,
+		       c.id, c.name, c.region
+		FROM veterinarians v
+		LEFT JOIN cities c ON v.city_id = c.id
+		WHERE v.is_active = true
+		  AND (concat_ws(' ', v.first_name, v.last_name, v.patronymic) ~ '[A-Za-z]'
+		       OR (` + strings.Join(conditions, " AND ") + `))`
+
+	rows, err := r.db.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка поиска врачей по имени: %v", err)
+	}
+	defer rows.Close()
+
+	var vets []*models.Veterinarian
+	for rows.Next() {
+		var vet models.Veterinarian
+		var cityID sql.NullInt64
+		var cityName, cityRegion sql.NullString
+
+		err := rows.Scan(&vet.ID, &vet.FirstName, &vet.LastName, &vet.Patronymic, &vet.Phone,
+			&vet.Email, &vet.Description, &vet.ExperienceYears, &vet.IsActive, &vet.CityID, &vet.CreatedAt,
+			&cityID, &cityName, &cityRegion)
+		if err != nil {
+			return nil, err
+		}
+
+		if cityID.Valid {
+			vet.City = &models.City{ID: int(cityID.Int64), Name: cityName.String, Region: cityRegion.String}
+		}
+		vets = append(vets, &vet)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	vets = models.RankVetsByName(vets, query, limit)
+	if err := r.LoadSpecializations(vets); err != nil {
+		return nil, err
+	}
+	return vets, nil
+}
+
+// GetVeterinariansByIDs возвращает врачей с городами и специализациями в порядке ids.
+// Удаленные врачи пропускаются. Нужен для страницы сохраненных результатов поиска
+func (r *VetSearchRepository) GetVeterinariansByIDs(ids []int) ([]*models.Veterinarian, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT v.id, v.first_name, v.last_name, v.patronymic, v.phone, v.email,
+		       v.description, v.experience_years, v.is_active, v.city_id, v.created_at,
+		       c.id, c.name, c.region, c.timezone
+		FROM veterinarians v
+		LEFT JOIN cities c ON v.city_id = c.id
+		WHERE v.id = ANY($1)`
+
+	rows, err := r.db.Query(query, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения врачей: %v", err)
+	}
+	defer rows.Close()
+
+	byID := make(map[int]*models.Veterinarian, len(ids))
+	for rows.Next() {
+		var vet models.Veterinarian
+		var cityID sql.NullInt64
+		var cityName, cityRegion, cityTimezone sql.NullString
+
+		err := rows.Scan(&vet.ID, &vet.FirstName, &vet.LastName, &vet.Patronymic, &vet.Phone,
+			&vet.Email, &vet.Description, &vet.ExperienceYears, &vet.IsActive, &vet.CityID, &vet.CreatedAt,
+			&cityID, &cityName, &cityRegion, &cityTimezone)
+		if err != nil {
+			return nil, err
+		}
+
+		if cityID.Valid {
+			vet.City = &models.City{ID: int(cityID.Int64), Name: cityName.String, Region: cityRegion.String,
+				Timezone: cityTimezone.String}
+		}
+		byID[models.GetVetIDAsIntOrZero(&vet)] = &vet
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	vets := make([]*models.Veterinarian, 0, len(byID))
+	for _, id := range ids {
+		if vet, ok := byID[id]; ok {
+			vets = append(vets, vet)
+		}
+	}
+	if err := r.LoadSpecializations(vets); err != nil {
+		return nil, err
+	}
+	return vets, nil
+}
+
+// LoadSpecializations заполняет специализации врачей одним запросом вместо запроса на каждого врача
+func (r *VetSearchRepository) LoadSpecializations(vets []*models.Veterinarian) error {
+	if len(vets) == 0 {
+		return nil
+	}
+
+	byID := make(map[int]*models.Veterinarian, len(vets))
+	ids := make([]int, 0, len(vets))
+	for _, vet := range vets {
+		id := models.GetVetIDAsIntOrZero(vet)
+		byID[id] = vet
+		ids = append(ids, id)
+	}
+
+	query := `
+		SELECT vs.vet_id, s.id, s.name, s.description, s.created_at
+		FROM specializations s
+		INNER JOIN vet_specializations vs ON s.id = vs.specialization_id
+		WHERE vs.vet_id = ANY($1)
+		ORDER BY s.name`
+
+	rows, err := r.db.Query(query, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("ошибка получения специализаций врачей: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var vetID int
+		var spec models.Specialization
+		if err := rows.Scan(&vetID, &spec.ID, &spec.Name, &spec.Description, &spec.CreatedAt); err != nil {
+			return err
+		}
+		if vet, ok := byID[vetID]; ok {
+			vet.Specializations = append(vet.Specializations, &spec)
+		}
+	}
+	return rows.Err()
+}
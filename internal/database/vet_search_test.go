@@ -0,0 +1,58 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchVeterinariansByName_Integration(t *testing.T) {
+	config := GetTestConfig()
+	db := SetupTestDatabase(t, config)
+	if db == nil {
+		return // Тест был пропущен
+	}
+	defer db.Close()
+
+	schemaDB, schemaDatabase := setupMigratedSchema(t, config, db, "name_search_test")
+	// Врачи из начальных данных миграций мешают проверять выдачу
+	_, err := schemaDB.Exec(`DELETE FROM veterinarians`)
+	require.NoError(t, err)
+
+	vets := []struct {
+		first, last, patronymic, phone string
+	}{
+		{"Анна", "Петрова-Водкина", "Сергеевна", "+79990000001"},
+		{"Фёдор", "Кузнецов", "", "+79990000002"},
+		{"Ivan", "Petrov", "", "+79990000003"},
+	}
+	for _, vet := range vets {
+		var vetID int
+		require.NoError(t, schemaDB.QueryRow(`INSERT INTO veterinarians (first_name, last_name, patronymic, phone)
+			VALUES ($1, $2, NULLIF($3, ''), $4) RETURNING id`, vet.first, vet.last, vet.patronymic, vet.phone).Scan(&vetID))
+		var specID int
+		require.NoError(t, schemaDB.QueryRow(`INSERT INTO specializations (name) VALUES ($1) RETURNING id`,
+			"Терапевт "+vet.phone).Scan(&specID))
+		_, err := schemaDB.Exec(`INSERT INTO vet_specializations (vet_id, specialization_id) VALUES ($1, $2)`, vetID, specID)
+		require.NoError(t, err)
+	}
+
+	names := func(query string) []string {
+		found, err := schemaDatabase.SearchVeterinariansByName(query, 10)
+		require.NoError(t, err)
+		var result []string
+		for _, vet := range found {
+			assert.Len(t, vet.Specializations, 1, "специализации загружены одним запросом")
+			result = append(result, vet.LastName)
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"Петрова-Водкина"}, names("Петрова-Водкина"))
+	assert.Equal(t, []string{"Петрова-Водкина"}, names("Питрова Сергевна"))
+	assert.Equal(t, []string{"Кузнецов"}, names("федор кузнец"))
+	assert.Equal(t, []string{"Petrov"}, names("Петров Иван"))
+	assert.Empty(t, names("Сидоров"))
+	assert.Empty(t, names("-"))
+}
@@ -34,3 +34,22 @@ func (a *TelegramBotAdapter) Request(c tgbotapi.Chattable) (*tgbotapi.APIRespons
 func (a *TelegramBotAdapter) GetToken() string {
 	return a.bot.Token
 }
+
+// SetWebhook регистрирует webhook с секретным токеном.
+// WebhookConfig из библиотеки не поддерживает secret_token, поэтому параметры передаются напрямую.
+// Одно соединение гарантирует, что обновления приходят последовательно
+func (a *TelegramBotAdapter) SetWebhook(webhookURL string, secret string) error {
+	params := tgbotapi.Params{}
+	params["url"] = webhookURL
+	params["max_connections"] = "1"
+	params.AddNonEmpty("secret_token", secret)
+
+	_, err := a.bot.MakeRequest("setWebhook", params)
+	return err
+}
+
+// DeleteWebhook удаляет зарегистрированный webhook
+func (a *TelegramBotAdapter) DeleteWebhook() error {
+	_, err := a.bot.Request(tgbotapi.DeleteWebhookConfig{})
+	return err
+}
@@ -0,0 +1,1369 @@
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drerr0r/vetbot/internal/models"
+)
+
+// maxAdminAPIBodySize ограничивает размер тела запроса к админскому API
+const maxAdminAPIBodySize = 1 << 20
+
+// adminIDKey ключ контекста с Telegram ID администратора, которому выдан токен
+type adminIDKey struct{}
+
+// AdminAPI HTTP API для управления справочником. Изменения проходят те же проверки,
+// что и в админских меню бота; каждый токен выдан конкретному администратору
+type AdminAPI struct {
+	admin  *AdminHandlers
+	db     Database
+	tokens map[string]int64
+	mux    *http.ServeMux
+}
+
+// NewAdminAPI создает админский API. tokens - соответствие токена Telegram ID администратора
+func NewAdminAPI(admin *AdminHandlers, tokens map[string]int64) *AdminAPI {
+	a := &AdminAPI{
+		admin:  admin,
+		db:     admin.db,
+		tokens: tokens,
+		mux:    http.NewServeMux(),
+	}
+	a.routes()
+	return a
+}
+
+// AdminAPI создает админский API, использующий проверки админки этого MainHandler
+func (h *MainHandler) AdminAPI(tokens map[string]int64) *AdminAPI {
+	return NewAdminAPI(h.adminHandlers, tokens)
+}
+
+// routes регистрирует обработчики. У городов и специализаций нет признака активности,
+// поэтому toggle-active есть только у врачей, клиник и приемов (is_available)
+func (a *AdminAPI) routes() {
+	a.mux.HandleFunc("GET /admin/v1/vets", a.handleListVets)
+	a.mux.HandleFunc("POST /admin/v1/vets", a.handleCreateVet)
+	a.mux.HandleFunc("GET /admin/v1/vets/{id}", a.handleGetVet)
+	a.mux.HandleFunc("PATCH /admin/v1/vets/{id}", a.handleUpdateVet)
+	a.mux.HandleFunc("DELETE /admin/v1/vets/{id}", a.handleDeleteVet)
+	a.mux.HandleFunc("POST /admin/v1/vets/{id}/toggle-active", a.handleToggleVet)
+	a.mux.HandleFunc("GET /admin/v1/vets/{id}/schedules", a.handleVetSchedules)
+
+	a.mux.HandleFunc("GET /admin/v1/clinics", a.handleListClinics)
+	a.mux.HandleFunc("POST /admin/v1/clinics", a.handleCreateClinic)
+	a.mux.HandleFunc("GET /admin/v1/clinics/{id}", a.handleGetClinic)
+	a.mux.HandleFunc("PATCH /admin/v1/clinics/{id}", a.handleUpdateClinic)
+	a.mux.HandleFunc("DELETE /admin/v1/clinics/{id}", a.handleDeleteClinic)
+	a.mux.HandleFunc("POST /admin/v1/clinics/{id}/toggle-active", a.handleToggleClinic)
+
+	a.mux.HandleFunc("GET /admin/v1/cities", a.handleListCities)
+	a.mux.HandleFunc("POST /admin/v1/cities", a.handleCreateCity)
+	a.mux.HandleFunc("GET /admin/v1/cities/{id}", a.handleGetCity)
+	a.mux.HandleFunc("PATCH /admin/v1/cities/{id}", a.handleUpdateCity)
+	a.mux.HandleFunc("DELETE /admin/v1/cities/{id}", a.handleDeleteCity)
+
+	a.mux.HandleFunc("GET /admin/v1/specializations", a.handleListSpecializations)
+	a.mux.HandleFunc("POST /admin/v1/specializations", a.handleCreateSpecialization)
+	a.mux.HandleFunc("GET /admin/v1/specializations/{id}", a.handleGetSpecialization)
+	a.mux.HandleFunc("PATCH /admin/v1/specializations/{id}", a.handleUpdateSpecialization)
+	a.mux.HandleFunc("DELETE /admin/v1/specializations/{id}", a.handleDeleteSpecialization)
+
+	a.mux.HandleFunc("POST /admin/v1/schedules", a.handleCreateSchedule)
+	a.mux.HandleFunc("GET /admin/v1/schedules/{id}", a.handleGetSchedule)
+	a.mux.HandleFunc("PATCH /admin/v1/schedules/{id}", a.handleUpdateSchedule)
+	a.mux.HandleFunc("DELETE /admin/v1/schedules/{id}", a.handleDeleteSchedule)
+	a.mux.HandleFunc("POST /admin/v1/schedules/{id}/toggle-active", a.handleToggleSchedule)
+
+	a.mux.HandleFunc("GET /admin/v1/reviews/pending", a.handlePendingReviews)
+	a.mux.HandleFunc("POST /admin/v1/reviews/{id}/approve", a.handleModerateReview("approved"))
+	a.mux.HandleFunc("POST /admin/v1/reviews/{id}/reject", a.handleModerateReview("rejected"))
+
+	// Для известных путей с другим методом ServeMux отвечает 405
+	a.mux.HandleFunc("GET /admin/", func(w http.ResponseWriter, r *http.Request) {
+		writeAdminError(w, http.StatusNotFound, "not_found", "unknown endpoint")
+	})
+}
+
+// ServeHTTP проверяет токен и передает запрос в роутер
+func (a *AdminAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := a.authenticate(r)
+	if !ok {
+		ErrorLog.Printf("Admin API request with invalid token from %s", r.RemoteAddr)
+		w.Header().Set("WWW-Authenticate", `Bearer realm="vetbot-admin"`)
+		writeAdminError(w, http.StatusUnauthorized, "unauthorized", "valid bearer token required")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	a.mux.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), adminIDKey{}, adminID)))
+}
+
+// authenticate находит администратора по токену из заголовка Authorization: Bearer <token>.
+// Сравниваются все токены, чтобы время ответа не зависело от совпавшего
+func (a *AdminAPI) authenticate(r *http.Request) (int64, bool) {
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found || token == "" {
+		return 0, false
+	}
+
+	var adminID int64
+	matched := false
+	for candidate, id := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(candidate)) == 1 {
+			adminID = id
+			matched = true
+		}
+	}
+	return adminID, matched
+}
+
+// requestAdminID возвращает Telegram ID администратора, выполняющего запрос
+func requestAdminID(r *http.Request) int64 {
+	adminID, _ := r.Context().Value(adminIDKey{}).(int64)
+	return adminID
+}
+
+// ========== ОТВЕТЫ И РАЗБОР ЗАПРОСОВ ==========
+
+// adminAPIError описание ошибки; details - список проблем при валидации
+type adminAPIError struct {
+	Code    string   `json:"code"`
+	Message string   `json:"message"`
+	Details []string `json:"details,omitempty"`
+}
+
+// adminDataResponse ответ со списком или объектом
+type adminDataResponse struct {
+	Data interface{} `json:"data"`
+}
+
+func writeAdminJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		ErrorLog.Printf("Error encoding admin API response: %v", err)
+	}
+}
+
+func writeAdminError(w http.ResponseWriter, status int, code, message string) {
+	writeAdminJSON(w, status, map[string]adminAPIError{"error": {Code: code, Message: message}})
+}
+
+// writeAdminValidationError отвечает 422 со списком проблем, найденных проверками
+func writeAdminValidationError(w http.ResponseWriter, problems []string) {
+	writeAdminJSON(w, http.StatusUnprocessableEntity, map[string]adminAPIError{"error": {
+		Code:    "validation_failed",
+		Message: "validation failed",
+		Details: problems,
+	}})
+}
+
+// writeAdminInternalError логирует ошибку; API закрыт токенами, поэтому текст ошибки отдается администратору
+func writeAdminInternalError(w http.ResponseWriter, r *http.Request, err error) {
+	ErrorLog.Printf("Admin API %s %s failed: %v", r.Method, r.URL.Path, err)
+	writeAdminError(w, http.StatusInternalServerError, "internal_error", err.Error())
+}
+
+// decodeAdminBody разбирает JSON тело запроса; неизвестные поля считаются ошибкой
+func decodeAdminBody(w http.ResponseWriter, r *http.Request, target interface{}) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAdminAPIBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(target); err != nil {
+		writeAdminError(w, http.StatusBadRequest, "invalid_body", err.Error())
+		return false
+	}
+	return true
+}
+
+// adminPathID возвращает ID из пути; при неверном ID отвечает 404
+func adminPathID(w http.ResponseWriter, r *http.Request, entity string) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		writeAdminError(w, http.StatusNotFound, "not_found", entity+" not found")
+		return 0, false
+	}
+	return id, true
+}
+
+// isNotFound проверяет, означает ли результат запроса отсутствие записи
+func isNotFound(err error) bool {
+	return errors.Is(err, sql.ErrNoRows)
+}
+
+// Необязательные строки: пустое значение сохраняется как NULL
+func setNullString(target *sql.NullString, value *string) {
+	if value != nil {
+		trimmed := strings.TrimSpace(*value)
+		*target = sql.NullString{String: trimmed, Valid: trimmed != ""}
+	}
+}
+
+func setString(target *string, value *string) {
+	if value != nil {
+		*target = strings.TrimSpace(*value)
+	}
+}
+
+// setNullID устанавливает ссылку на запись; 0 убирает ссылку
+func setNullID(target *sql.NullInt64, value *int64) {
+	if value != nil {
+		*target = sql.NullInt64{Int64: *value, Valid: *value > 0}
+	}
+}
+
+func nullStringValue(value sql.NullString) string {
+	if value.Valid {
+		return value.String
+	}
+	return ""
+}
+
+func nullInt64Pointer(value sql.NullInt64) *int64 {
+	if !value.Valid {
+		return nil
+	}
+	result := value.Int64
+	return &result
+}
+
+func nullFloat64Pointer(value sql.NullFloat64) *float64 {
+	if !value.Valid {
+		return nil
+	}
+	result := value.Float64
+	return &result
+}
+
+// checkCity добавляет проблему, если город не существует
+func (a *AdminAPI) checkCity(cityID sql.NullInt64, problems []string) ([]string, error) {
+	if !cityID.Valid {
+		return problems, nil
+	}
+	_, err := a.db.GetCityByID(int(cityID.Int64))
+	if isNotFound(err) {
+		return append(problems, fmt.Sprintf("город %d не найден", cityID.Int64)), nil
+	}
+	return problems, err
+}
+
+// ========== ВРАЧИ ==========
+
+// adminVetInput поля врача. Отсутствующее поле не меняется, пустая строка очищает необязательное поле,
+// city_id = 0 убирает город, specialization_ids заменяет весь список специализаций
+type adminVetInput struct {
+	FirstName         *string `json:"first_name"`
+	LastName          *string `json:"last_name"`
+	Patronymic        *string `json:"patronymic"`
+	Phone             *string `json:"phone"`
+	Email             *string `json:"email"`
+	Description       *string `json:"description"`
+	ExperienceYears   *int64  `json:"experience_years"`
+	CityID            *int64  `json:"city_id"`
+	IsActive          *bool   `json:"is_active"`
+	SpecializationIDs *[]int  `json:"specialization_ids"`
+}
+
+func (in *adminVetInput) apply(vet *models.Veterinarian) {
+	setString(&vet.FirstName, in.FirstName)
+	setString(&vet.LastName, in.LastName)
+	setString(&vet.Phone, in.Phone)
+	setNullString(&vet.Patronymic, in.Patronymic)
+	setNullString(&vet.Email, in.Email)
+	setNullString(&vet.Description, in.Description)
+	setNullID(&vet.CityID, in.CityID)
+	if in.ExperienceYears != nil {
+		vet.ExperienceYears = sql.NullInt64{Int64: *in.ExperienceYears, Valid: true}
+	}
+	if in.IsActive != nil {
+		vet.IsActive = *in.IsActive
+	}
+}
+
+type adminVetResource struct {
+	ID                int       `json:"id"`
+	FirstName         string    `json:"first_name"`
+	LastName          string    `json:"last_name"`
+	Patronymic        string    `json:"patronymic,omitempty"`
+	Phone             string    `json:"phone"`
+	Email             string    `json:"email,omitempty"`
+	Description       string    `json:"description,omitempty"`
+	ExperienceYears   *int64    `json:"experience_years,omitempty"`
+	CityID            *int64    `json:"city_id,omitempty"`
+	IsActive          bool      `json:"is_active"`
+	SpecializationIDs []int     `json:"specialization_ids"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// newAdminVetResource собирает представление врача вместе с ID его специализаций
+func (a *AdminAPI) newAdminVetResource(vet *models.Veterinarian) (adminVetResource, error) {
+	vetID := models.GetVetIDAsIntOrZero(vet)
+	specs, err := a.db.GetSpecializationsByVetID(vetID)
+	if err != nil && !isNotFound(err) {
+		return adminVetResource{}, err
+	}
+
+	specIDs := make([]int, 0, len(specs))
+	for _, spec := range specs {
+		specIDs = append(specIDs, spec.ID)
+	}
+	sort.Ints(specIDs)
+
+	return adminVetResource{
+		ID:                vetID,
+		FirstName:         vet.FirstName,
+		LastName:          vet.LastName,
+		Patronymic:        nullStringValue(vet.Patronymic),
+		Phone:             vet.Phone,
+		Email:             nullStringValue(vet.Email),
+		Description:       nullStringValue(vet.Description),
+		ExperienceYears:   nullInt64Pointer(vet.ExperienceYears),
+		CityID:            nullInt64Pointer(vet.CityID),
+		IsActive:          vet.IsActive,
+		SpecializationIDs: specIDs,
+		CreatedAt:         vet.CreatedAt,
+	}, nil
+}
+
+// validateVet проверяет врача теми же правилами, что и админка бота
+func (a *AdminAPI) validateVet(vet *models.Veterinarian, specIDs *[]int) ([]string, error) {
+	var problems []string
+	for _, field := range a.admin.getMissingRequiredFields(vet) {
+		problems = append(problems, "не заполнено обязательное поле: "+field)
+	}
+	if vet.ExperienceYears.Valid && vet.ExperienceYears.Int64 < 0 {
+		problems = append(problems, "стаж не может быть отрицательным")
+	}
+
+	if specIDs != nil {
+		ids := make([]string, 0, len(*specIDs))
+		for _, id := range *specIDs {
+			ids = append(ids, strconv.Itoa(id))
+		}
+		if specsText := strings.Join(ids, ","); !a.admin.isValidSpecializationIDs(specsText) {
+			problems = append(problems, "неверные ID специализаций: "+specsText)
+		}
+	}
+
+	return a.checkCity(vet.CityID, problems)
+}
+
+// loadVet загружает врача по ID из пути; при ошибке ответ уже отправлен
+func (a *AdminAPI) loadVet(w http.ResponseWriter, r *http.Request) (*models.Veterinarian, bool) {
+	id, ok := adminPathID(w, r, "veterinarian")
+	if !ok {
+		return nil, false
+	}
+
+	vet, err := a.db.GetVeterinarianByID(id)
+	if isNotFound(err) || (err == nil && vet == nil) {
+		writeAdminError(w, http.StatusNotFound, "not_found", "veterinarian not found")
+		return nil, false
+	}
+	if err != nil {
+		writeAdminInternalError(w, r, err)
+		return nil, false
+	}
+
+	// Копия, чтобы неудачная проверка не меняла загруженный объект
+	vetCopy := *vet
+	return &vetCopy, true
+}
+
+func (a *AdminAPI) writeVet(w http.ResponseWriter, r *http.Request, status int, vet *models.Veterinarian) {
+	resource, err := a.newAdminVetResource(vet)
+	if err != nil {
+		writeAdminInternalError(w, r, err)
+		return
+	}
+	writeAdminJSON(w, status, adminDataResponse{Data: resource})
+}
+
+// handleListVets GET /admin/v1/vets - все врачи, включая неактивных
+func (a *AdminAPI) handleListVets(w http.ResponseWriter, r *http.Request) {
+	vets, err := a.db.GetAllVeterinarians()
+	if err != nil {
+		writeAdminInternalError(w, r, err)
+		return
+	}
+	sort.Slice(vets, func(i, j int) bool {
+		return models.GetVetIDAsIntOrZero(vets[i]) < models.GetVetIDAsIntOrZero(vets[j])
+	})
+
+	resources := make([]adminVetResource, 0, len(vets))
+	for _, vet := range vets {
+		resource, err := a.newAdminVetResource(vet)
+		if err != nil {
+			writeAdminInternalError(w, r, err)
+			return
+		}
+		resources = append(resources, resource)
+	}
+	writeAdminJSON(w, http.StatusOK, adminDataResponse{Data: resources})
+}
+
+// handleGetVet GET /admin/v1/vets/{id}
+func (a *AdminAPI) handleGetVet(w http.ResponseWriter, r *http.Request) {
+	if vet, ok := a.loadVet(w, r); ok {
+		a.writeVet(w, r, http.StatusOK, vet)
+	}
+}
+
+// handleCreateVet POST /admin/v1/vets
+func (a *AdminAPI) handleCreateVet(w http.ResponseWriter, r *http.Request) {
+	var input adminVetInput
+	if !decodeAdminBody(w, r, &input) {
+		return
+	}
+
+	vet := &models.Veterinarian{IsActive: true}
+	input.apply(vet)
+
+	problems, err := a.validateVet(vet, input.SpecializationIDs)
+	if err != nil {
+		writeAdminInternalError(w, r, err)
+		return
+	}
+	if len(problems) > 0 {
+		writeAdminValidationError(w, problems)
+		return
+	}
+
+	if err := a.db.CreateVeterinarian(vet); err != nil {
+		writeAdminInternalError(w, r, err)
+		return
+	}
+	vetID := models.GetVetIDAsIntOrZero(vet)
+	if input.SpecializationIDs != nil {
+		if err := a.db.SetVeterinarianSpecializations(vetID, *input.SpecializationIDs); err != nil {
+			writeAdminInternalError(w, r, err)
+			return
+		}
+	}
+
+	InfoLog.Printf("Admin API: admin %d created veterinarian %d", requestAdminID(r), vetID)
+	a.writeVet(w, r, http.StatusCreated, vet)
+}
+
+// handleUpdateVet PATCH /admin/v1/vets/{id}
+func (a *AdminAPI) handleUpdateVet(w http.ResponseWriter, r *http.Request) {
+	vet, ok := a.loadVet(w, r)
+	if !ok {
+		return
+	}
+
+	var input adminVetInput
+	if !decodeAdminBody(w, r, &input) {
+		return
+	}
+	input.apply(vet)
+
+	problems, err := a.validateVet(vet, input.SpecializationIDs)
+	if err != nil {
+		writeAdminInternalError(w, r, err)
+		return
+	}
+	if len(problems) > 0 {
+		writeAdminValidationError(w, problems)
+		return
+	}
+
+	if err := a.db.UpdateVeterinarian(vet); err != nil {
+		writeAdminInternalError(w, r, err)
+		return
+	}
+	vetID := models.GetVetIDAsIntOrZero(vet)
+	if input.SpecializationIDs != nil {
+		if err := a.db.SetVeterinarianSpecializations(vetID, *input.SpecializationIDs); err != nil {
+			writeAdminInternalError(w, r, err)
+			return
+		}
+	}
+
+	InfoLog.Printf("Admin API: admin %d updated veterinarian %d", requestAdminID(r), vetID)
+	a.writeVet(w, r, http.StatusOK, vet)
+}
+
+// handleDeleteVet DELETE /admin/v1/vets/{id} - вместе со специализациями и расписанием
+func (a *AdminAPI) handleDeleteVet(w http.ResponseWriter, r *http.Request) {
+	vet, ok := a.loadVet(w, r)
+	if !ok {
+		return
+	}
+
+	vetID := models.GetVetIDAsIntOrZero(vet)
+	if err := a.admin.deleteVeterinarian(vetID); err != nil {
+		writeAdminInternalError(w, r, err)
+		return
+	}
+
+	InfoLog.Printf("Admin API: admin %d deleted veterinarian %d", requestAdminID(r), vetID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleToggleVet POST /admin/v1/vets/{id}/toggle-active
+func (a *AdminAPI) handleToggleVet(w http.ResponseWriter, r *http.Request) {
+	vet, ok := a.loadVet(w, r)
+	if !ok {
+		return
+	}
+
+	vet.IsActive = !vet.IsActive
+	if err := a.db.UpdateVeterinarian(vet); err != nil {
+		writeAdminInternalError(w, r, err)
+		return
+	}
+
+	InfoLog.Printf("Admin API: admin %d set veterinarian %d active=%t",
+		requestAdminID(r), models.GetVetIDAsIntOrZero(vet), vet.IsActive)
+	a.writeVet(w, r, http.StatusOK, vet)
+}
+
+// handleVetSchedules GET /admin/v1/vets/{id}/schedules - включая недоступные приемы
+func (a *AdminAPI) handleVetSchedules(w http.ResponseWriter, r *http.Request) {
+	vet, ok := a.loadVet(w, r)
+	if !ok {
+		return
+	}
+
+	schedules, err := a.db.GetAllSchedulesByVetID(models.GetVetIDAsIntOrZero(vet))
+	if err != nil && !isNotFound(err) {
+		writeAdminInternalError(w, r, err)
+		return
+	}
+	sort.SliceStable(schedules, func(i, j int) bool {
+		if schedules[i].DayOfWeek != schedules[j].DayOfWeek {
+			return schedules[i].DayOfWeek < schedules[j].DayOfWeek
+		}
+		return schedules[i].StartTime < schedules[j].StartTime
+	})
+
+	resources := make([]adminScheduleResource, 0, len(schedules))
+	for _, schedule := range schedules {
+		resources = append(resources, newAdminScheduleResource(schedule))
+	}
+	writeAdminJSON(w, http.StatusOK, adminDataResponse{Data: resources})
+}
+
+// ========== КЛИНИКИ ==========
+
+// adminClinicInput поля клиники; координаты задаются парой latitude/longitude
+type adminClinicInput struct {
+	Name         *string  `json:"name"`
+	Address      *string  `json:"address"`
+	Phone        *string  `json:"phone"`
+	WorkingHours *string  `json:"working_hours"`
+	District     *string  `json:"district"`
+	MetroStation *string  `json:"metro_station"`
+	CityID       *int64   `json:"city_id"`
+	Latitude     *float64 `json:"latitude"`
+	Longitude    *float64 `json:"longitude"`
+	IsActive     *bool    `json:"is_active"`
+}
+
+func (in *adminClinicInput) apply(clinic *models.Clinic) {
+	setString(&clinic.Name, in.Name)
+	setString(&clinic.Address, in.Address)
+	setNullString(&clinic.Phone, in.Phone)
+	setNullString(&clinic.WorkingHours, in.WorkingHours)
+	setNullString(&clinic.District, in.District)
+	setNullString(&clinic.MetroStation, in.MetroStation)
+	setNullID(&clinic.CityID, in.CityID)
+	if in.Latitude != nil {
+		clinic.Latitude = sql.NullFloat64{Float64: *in.Latitude, Valid: true}
+	}
+	if in.Longitude != nil {
+		clinic.Longitude = sql.NullFloat64{Float64: *in.Longitude, Valid: true}
+	}
+	if in.IsActive != nil {
+		clinic.IsActive = *in.IsActive
+	}
+}
+
+type adminClinicResource struct {
+	ID           int       `json:"id"`
+	Name         string    `json:"name"`
+	Address      string    `json:"address"`
+	Phone        string    `json:"phone,omitempty"`
+	WorkingHours string    `json:"working_hours,omitempty"`
+	District     string    `json:"district,omitempty"`
+	MetroStation string    `json:"metro_station,omitempty"`
+	CityID       *int64    `json:"city_id,omitempty"`
+	Latitude     *float64  `json:"latitude,omitempty"`
+	Longitude    *float64  `json:"longitude,omitempty"`
+	IsActive     bool      `json:"is_active"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func newAdminClinicResource(clinic *models.Clinic) adminClinicResource {
+	return adminClinicResource{
+		ID:           clinic.ID,
+		Name:         clinic.Name,
+		Address:      clinic.Address,
+		Phone:        nullStringValue(clinic.Phone),
+		WorkingHours: nullStringValue(clinic.WorkingHours),
+		District:     nullStringValue(clinic.District),
+		MetroStation: nullStringValue(clinic.MetroStation),
+		CityID:       nullInt64Pointer(clinic.CityID),
+		Latitude:     nullFloat64Pointer(clinic.Latitude),
+		Longitude:    nullFloat64Pointer(clinic.Longitude),
+		IsActive:     clinic.IsActive,
+		CreatedAt:    clinic.CreatedAt,
+	}
+}
+
+// validateClinic проверяет обязательные поля, координаты и город клиники
+func (a *AdminAPI) validateClinic(clinic *models.Clinic) ([]string, error) {
+	var problems []string
+	if clinic.Name == "" {
+		problems = append(problems, "не заполнено обязательное поле: Название")
+	}
+	if clinic.Address == "" {
+		problems = append(problems, "не заполнено обязательное поле: Адрес")
+	}
+
+	if clinic.Latitude.Valid != clinic.Longitude.Valid {
+		problems = append(problems, "координаты задаются парой: latitude и longitude")
+	} else if clinic.HasCoordinates() {
+		if err := models.ValidateCoordinates(clinic.Latitude.Float64, clinic.Longitude.Float64); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	return a.checkCity(clinic.CityID, problems)
+}
+
+// loadClinic загружает клинику по ID из пути; при ошибке ответ уже отправлен
+func (a *AdminAPI) loadClinic(w http.ResponseWriter, r *http.Request) (*models.Clinic, bool) {
+	id, ok := adminPathID(w, r, "clinic")
+	if !ok {
+		return nil, false
+	}
+
+	clinic, err := a.db.GetClinicByID(id)
+	if isNotFound(err) || (err == nil && clinic == nil) {
+		writeAdminError(w, http.StatusNotFound, "not_found", "clinic not found")
+		return nil, false
+	}
+	if err != nil {
+		writeAdminInternalError(w, r, err)
+		return nil, false
+	}
+
+	clinicCopy := *clinic
+	return &clinicCopy, true
+}
+
+// handleListClinics GET /admin/v1/clinics - все клиники, включая неактивные
+func (a *AdminAPI) handleListClinics(w http.ResponseWriter, r *http.Request) {
+	clinics, err := a.db.GetAllClinicsWithCities()
+	if err != nil {
+		writeAdminInternalError(w, r, err)
+		return
+	}
+	sort.Slice(clinics, func(i, j int) bool { return clinics[i].ID < clinics[j].ID })
+
+	resources := make([]adminClinicResource, 0, len(clinics))
+	for _, clinic := range clinics {
+		resources = append(resources, newAdminClinicResource(clinic))
+	}
+	writeAdminJSON(w, http.StatusOK, adminDataResponse{Data: resources})
+}
+
+// handleGetClinic GET /admin/v1/clinics/{id}
+func (a *AdminAPI) handleGetClinic(w http.ResponseWriter, r *http.Request) {
+	if clinic, ok := a.loadClinic(w, r); ok {
+		writeAdminJSON(w, http.StatusOK, adminDataResponse{Data: newAdminClinicResource(clinic)})
+	}
+}
+
+// handleCreateClinic POST /admin/v1/clinics
+func (a *AdminAPI) handleCreateClinic(w http.ResponseWriter, r *http.Request) {
+	var input adminClinicInput
+	if !decodeAdminBody(w, r, &input) {
+		return
+	}
+
+	clinic := &models.Clinic{IsActive: true}
+	input.apply(clinic)
+
+	problems, err := a.validateClinic(clinic)
+	if err != nil {
+		writeAdminInternalError(w, r, err)
+		return
+	}
+	if len(problems) > 0 {
+		writeAdminValidationError(w, problems)
+		return
+	}
+
+	if err := a.db.CreateClinicWithCity(clinic); err != nil {
+		writeAdminInternalError(w, r, err)
+		return
+	}
+
+	InfoLog.Printf("Admin API: admin %d created clinic %d", requestAdminID(r), clinic.ID)
+	writeAdminJSON(w, http.StatusCreated, adminDataResponse{Data: newAdminClinicResource(clinic)})
+}
+
+// handleUpdateClinic PATCH /admin/v1/clinics/{id}
+func (a *AdminAPI) handleUpdateClinic(w http.ResponseWriter, r *http.Request) {
+	clinic, ok := a.loadClinic(w, r)
+	if !ok {
+		return
+	}
+
+	var input adminClinicInput
+	if !decodeAdminBody(w, r, &input) {
+		return
+	}
+	input.apply(clinic)
+
+	problems, err := a.validateClinic(clinic)
+	if err != nil {
+		writeAdminInternalError(w, r, err)
+		return
+	}
+	if len(problems) > 0 {
+		writeAdminValidationError(w, problems)
+		return
+	}
+
+	if err := a.db.UpdateClinic(clinic); err != nil {
+		writeAdminInternalError(w, r, err)
+		return
+	}
+
+	InfoLog.Printf("Admin API: admin %d updated clinic %d", requestAdminID(r), clinic.ID)
+	writeAdminJSON(w, http.StatusOK, adminDataResponse{Data: newAdminClinicResource(clinic)})
+}
+
+// handleDeleteClinic DELETE /admin/v1/clinics/{id} - вместе с расписанием в клинике
+func (a *AdminAPI) handleDeleteClinic(w http.ResponseWriter, r *http.Request) {
+	clinic, ok := a.loadClinic(w, r)
+	if !ok {
+		return
+	}
+
+	if err := a.admin.deleteClinic(clinic.ID); err != nil {
+		writeAdminInternalError(w, r, err)
+		return
+	}
+
+	InfoLog.Printf("Admin API: admin %d deleted clinic %d", requestAdminID(r), clinic.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleToggleClinic POST /admin/v1/clinics/{id}/toggle-active
+func (a *AdminAPI) handleToggleClinic(w http.ResponseWriter, r *http.Request) {
+	clinic, ok := a.loadClinic(w, r)
+	if !ok {
+		return
+	}
+
+	clinic.IsActive = !clinic.IsActive
+	if err := a.db.UpdateClinic(clinic); err != nil {
+		writeAdminInternalError(w, r, err)
+		return
+	}
+
+	InfoLog.Printf("Admin API: admin %d set clinic %d active=%t", requestAdminID(r), clinic.ID, clinic.IsActive)
+	writeAdminJSON(w, http.StatusOK, adminDataResponse{Data: newAdminClinicResource(clinic)})
+}
+
+// ========== ГОРОДА ==========
+
+type adminCityInput struct {
+	Name     *string `json:"name"`
+	Region   *string `json:"region"`
+	Timezone *string `json:"timezone"`
+}
+
+// validateCity проверяет название города, его уникальность и часовой пояс (пустой - по умолчанию)
+func (a *AdminAPI) validateCity(w http.ResponseWriter, r *http.Request, city *models.City) bool {
+	if city.Name == "" {
+		writeAdminValidationError(w, []string{"не заполнено обязательное поле: Название"})
+		return false
+	}
+	if city.Timezone != "" {
+		timezone, err := models.ParseTimezone(city.Timezone)
+		if err != nil {
+			writeAdminValidationError(w, []string{err.Error()})
+			return false
+		}
+		city.Timezone = timezone
+	}
+
+	existing, err := a.db.GetCityByName(city.Name)
+	if err != nil && !isNotFound(err) {
+		writeAdminInternalError(w, r, err)
+		return false
+	}
+	if err == nil && existing != nil && existing.ID != city.ID {
+		writeAdminError(w, http.StatusConflict, "conflict", fmt.Sprintf("city %q already exists (id %d)", existing.Name, existing.ID))
+		return false
+	}
+	return true
+}
+
+// loadCity загружает город по ID из пути; при ошибке ответ уже отправлен
+func (a *AdminAPI) loadCity(w http.ResponseWriter, r *http.Request) (*models.City, bool) {
+	id, ok := adminPathID(w, r, "city")
+	if !ok {
+		return nil, false
+	}
+
+	city, err := a.db.GetCityByID(id)
+	if isNotFound(err) || (err == nil && city == nil) {
+		writeAdminError(w, http.StatusNotFound, "not_found", "city not found")
+		return nil, false
+	}
+	if err != nil {
+		writeAdminInternalError(w, r, err)
+		return nil, false
+	}
+
+	cityCopy := *city
+	return &cityCopy, true
+}
+
+// handleListCities GET /admin/v1/cities
+func (a *AdminAPI) handleListCities(w http.ResponseWriter, r *http.Request) {
+	cities, err := a.db.GetAllCities()
+	if err != nil {
+		writeAdminInternalError(w, r, err)
+		return
+	}
+	sort.Slice(cities, func(i, j int) bool { return cities[i].ID < cities[j].ID })
+
+	if cities == nil {
+		cities = []*models.City{}
+	}
+	writeAdminJSON(w, http.StatusOK, adminDataResponse{Data: cities})
+}
+
+// handleGetCity GET /admin/v1/cities/{id}
+func (a *AdminAPI) handleGetCity(w http.ResponseWriter, r *http.Request) {
+	if city, ok := a.loadCity(w, r); ok {
+		writeAdminJSON(w, http.StatusOK, adminDataResponse{Data: city})
+	}
+}
+
+// handleCreateCity POST /admin/v1/cities
+func (a *AdminAPI) handleCreateCity(w http.ResponseWriter, r *http.Request) {
+	var input adminCityInput
+	if !decodeAdminBody(w, r, &input) {
+		return
+	}
+
+	city := &models.City{}
+	setString(&city.Name, input.Name)
+	setString(&city.Region, input.Region)
+	setString(&city.Timezone, input.Timezone)
+	if !a.validateCity(w, r, city) {
+		return
+	}
+
+	if err := a.db.CreateCity(city); err != nil {
+		writeAdminInternalError(w, r, err)
+		return
+	}
+
+	InfoLog.Printf("Admin API: admin %d created city %d", requestAdminID(r), city.ID)
+	writeAdminJSON(w, http.StatusCreated, adminDataResponse{Data: city})
+}
+
+// handleUpdateCity PATCH /admin/v1/cities/{id}
+func (a *AdminAPI) handleUpdateCity(w http.ResponseWriter, r *http.Request) {
+	city, ok := a.loadCity(w, r)
+	if !ok {
+		return
+	}
+
+	var input adminCityInput
+	if !decodeAdminBody(w, r, &input) {
+		return
+	}
+	setString(&city.Name, input.Name)
+	setString(&city.Region, input.Region)
+	setString(&city.Timezone, input.Timezone)
+	if !a.validateCity(w, r, city) {
+		return
+	}
+
+	if err := a.db.UpdateCity(city); err != nil {
+		writeAdminInternalError(w, r, err)
+		return
+	}
+
+	InfoLog.Printf("Admin API: admin %d updated city %d", requestAdminID(r), city.ID)
+	writeAdminJSON(w, http.StatusOK, adminDataResponse{Data: city})
+}
+
+// handleDeleteCity DELETE /admin/v1/cities/{id} - врачи и клиники города остаются без города
+func (a *AdminAPI) handleDeleteCity(w http.ResponseWriter, r *http.Request) {
+	city, ok := a.loadCity(w, r)
+	if !ok {
+		return
+	}
+
+	if err := a.admin.deleteCity(city.ID); err != nil {
+		writeAdminInternalError(w, r, err)
+		return
+	}
+
+	InfoLog.Printf("Admin API: admin %d deleted city %d", requestAdminID(r), city.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ========== СПЕЦИАЛИЗАЦИИ ==========
+
+type adminSpecializationInput struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+}
+
+// validateSpecialization проверяет название специализации и его уникальность
+func (a *AdminAPI) validateSpecialization(w http.ResponseWriter, r *http.Request, spec *models.Specialization) bool {
+	if spec.Name == "" {
+		writeAdminValidationError(w, []string{"не заполнено обязательное поле: Название"})
+		return false
+	}
+
+	existing, err := a.db.GetSpecializationByName(spec.Name)
+	if err != nil && !isNotFound(err) {
+		writeAdminInternalError(w, r, err)
+		return false
+	}
+	if err == nil && existing != nil && existing.ID != spec.ID {
+		writeAdminError(w, http.StatusConflict, "conflict", fmt.Sprintf("specialization %q already exists (id %d)", existing.Name, existing.ID))
+		return false
+	}
+	return true
+}
+
+// loadSpecialization загружает специализацию по ID из пути; при ошибке ответ уже отправлен
+func (a *AdminAPI) loadSpecialization(w http.ResponseWriter, r *http.Request) (*models.Specialization, bool) {
+	id, ok := adminPathID(w, r, "specialization")
+	if !ok {
+		return nil, false
+	}
+
+	spec, err := a.db.GetSpecializationByID(id)
+	if isNotFound(err) || (err == nil && spec == nil) {
+		writeAdminError(w, http.StatusNotFound, "not_found", "specialization not found")
+		return nil, false
+	}
+	if err != nil {
+		writeAdminInternalError(w, r, err)
+		return nil, false
+	}
+
+	specCopy := *spec
+	return &specCopy, true
+}
+
+// handleListSpecializations GET /admin/v1/specializations
+func (a *AdminAPI) handleListSpecializations(w http.ResponseWriter, r *http.Request) {
+	specs, err := a.db.GetAllSpecializations()
+	if err != nil {
+		writeAdminInternalError(w, r, err)
+		return
+	}
+	sort.Slice(specs, func(i, j int) bool { return specs[i].ID < specs[j].ID })
+
+	if specs == nil {
+		specs = []*models.Specialization{}
+	}
+	writeAdminJSON(w, http.StatusOK, adminDataResponse{Data: specs})
+}
+
+// handleGetSpecialization GET /admin/v1/specializations/{id}
+func (a *AdminAPI) handleGetSpecialization(w http.ResponseWriter, r *http.Request) {
+	if spec, ok := a.loadSpecialization(w, r); ok {
+		writeAdminJSON(w, http.StatusOK, adminDataResponse{Data: spec})
+	}
+}
+
+// handleCreateSpecialization POST /admin/v1/specializations
+func (a *AdminAPI) handleCreateSpecialization(w http.ResponseWriter, r *http.Request) {
+	var input adminSpecializationInput
+	if !decodeAdminBody(w, r, &input) {
+		return
+	}
+
+	spec := &models.Specialization{CreatedAt: time.Now()}
+	setString(&spec.Name, input.Name)
+	setString(&spec.Description, input.Description)
+	if !a.validateSpecialization(w, r, spec) {
+		return
+	}
+
+	if err := a.db.CreateSpecialization(spec); err != nil {
+		writeAdminInternalError(w, r, err)
+		return
+	}
+
+	InfoLog.Printf("Admin API: admin %d created specialization %d", requestAdminID(r), spec.ID)
+	writeAdminJSON(w, http.StatusCreated, adminDataResponse{Data: spec})
+}
+
+// handleUpdateSpecialization PATCH /admin/v1/specializations/{id}
+func (a *AdminAPI) handleUpdateSpecialization(w http.ResponseWriter, r *http.Request) {
+	spec, ok := a.loadSpecialization(w, r)
+	if !ok {
+		return
+	}
+
+	var input adminSpecializationInput
+	if !decodeAdminBody(w, r, &input) {
+		return
+	}
+	setString(&spec.Name, input.Name)
+	setString(&spec.Description, input.Description)
+	if !a.validateSpecialization(w, r, spec) {
+		return
+	}
+
+	if err := a.db.UpdateSpecialization(spec); err != nil {
+		writeAdminInternalError(w, r, err)
+		return
+	}
+
+	InfoLog.Printf("Admin API: admin %d updated specialization %d", requestAdminID(r), spec.ID)
+	writeAdminJSON(w, http.StatusOK, adminDataResponse{Data: spec})
+}
+
+// handleDeleteSpecialization DELETE /admin/v1/specializations/{id} - связи с врачами удаляются
+func (a *AdminAPI) handleDeleteSpecialization(w http.ResponseWriter, r *http.Request) {
+	spec, ok := a.loadSpecialization(w, r)
+	if !ok {
+		return
+	}
+
+	if err := a.db.DeleteSpecialization(spec.ID); err != nil {
+		writeAdminInternalError(w, r, err)
+		return
+	}
+
+	InfoLog.Printf("Admin API: admin %d deleted specialization %d", requestAdminID(r), spec.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ========== РАСПИСАНИЕ ==========
+
+type adminScheduleInput struct {
+	VetID       *int    `json:"vet_id"`
+	ClinicID    *int    `json:"clinic_id"`
+	DayOfWeek   *int    `json:"day_of_week"`
+	StartTime   *string `json:"start_time"`
+	EndTime     *string `json:"end_time"`
+	IsAvailable *bool   `json:"is_available"`
+}
+
+func (in *adminScheduleInput) apply(schedule *models.Schedule) {
+	if in.VetID != nil {
+		schedule.VetID = *in.VetID
+	}
+	if in.ClinicID != nil {
+		schedule.ClinicID = *in.ClinicID
+	}
+	if in.DayOfWeek != nil {
+		schedule.DayOfWeek = *in.DayOfWeek
+	}
+	setString(&schedule.StartTime, in.StartTime)
+	setString(&schedule.EndTime, in.EndTime)
+	if in.IsAvailable != nil {
+		schedule.IsAvailable = *in.IsAvailable
+	}
+}
+
+type adminScheduleResource struct {
+	ID          int       `json:"id"`
+	VetID       int       `json:"vet_id"`
+	ClinicID    int       `json:"clinic_id"`
+	DayOfWeek   int       `json:"day_of_week"`
+	StartTime   string    `json:"start_time"`
+	EndTime     string    `json:"end_time"`
+	IsAvailable bool      `json:"is_available"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func newAdminScheduleResource(schedule *models.Schedule) adminScheduleResource {
+	return adminScheduleResource{
+		ID:          schedule.ID,
+		VetID:       schedule.VetID,
+		ClinicID:    schedule.ClinicID,
+		DayOfWeek:   schedule.DayOfWeek,
+		StartTime:   schedule.StartTime,
+		EndTime:     schedule.EndTime,
+		IsAvailable: schedule.IsAvailable,
+		CreatedAt:   schedule.CreatedAt,
+	}
+}
+
+// validateSchedule проверяет день и время приема, существование врача и клиники,
+// а также что доступный прием не пересекается с другими приемами врача
+func (a *AdminAPI) validateSchedule(schedule *models.Schedule) ([]string, error) {
+	var problems []string
+	if err := schedule.Validate(); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if _, err := a.db.GetVeterinarianByID(schedule.VetID); isNotFound(err) {
+		problems = append(problems, fmt.Sprintf("врач %d не найден", schedule.VetID))
+	} else if err != nil {
+		return nil, err
+	}
+
+	if _, err := a.db.GetClinicByID(schedule.ClinicID); isNotFound(err) {
+		problems = append(problems, fmt.Sprintf("клиника %d не найдена", schedule.ClinicID))
+	} else if err != nil {
+		return nil, err
+	}
+
+	if problem, err := a.scheduleOverlapProblem(schedule); err != nil {
+		return nil, err
+	} else if problem != "" {
+		problems = append(problems, problem)
+	}
+	return problems, nil
+}
+
+// scheduleOverlapProblem описывает пересечение доступного приема с другими приемами врача; пусто - пересечений нет
+func (a *AdminAPI) scheduleOverlapProblem(schedule *models.Schedule) (string, error) {
+	if !schedule.IsAvailable {
+		return "", nil
+	}
+	schedules, err := a.db.GetAllSchedulesByVetID(schedule.VetID)
+	if err != nil {
+		return "", err
+	}
+	if overlap := schedule.FindOverlap(schedules); overlap != nil {
+		return fmt.Sprintf("прием пересекается с приемом %d: %s", overlap.ID, formatScheduleSlotWithClinic(overlap)), nil
+	}
+	return "", nil
+}
+
+// loadSchedule загружает прием по ID из пути; при ошибке ответ уже отправлен
+func (a *AdminAPI) loadSchedule(w http.ResponseWriter, r *http.Request) (*models.Schedule, bool) {
+	id, ok := adminPathID(w, r, "schedule")
+	if !ok {
+		return nil, false
+	}
+
+	schedule, err := a.db.GetScheduleByID(id)
+	if isNotFound(err) || (err == nil && schedule == nil) {
+		writeAdminError(w, http.StatusNotFound, "not_found", "schedule not found")
+		return nil, false
+	}
+	if err != nil {
+		writeAdminInternalError(w, r, err)
+		return nil, false
+	}
+
+	scheduleCopy := *schedule
+	return &scheduleCopy, true
+}
+
+// handleGetSchedule GET /admin/v1/schedules/{id}
+func (a *AdminAPI) handleGetSchedule(w http.ResponseWriter, r *http.Request) {
+	if schedule, ok := a.loadSchedule(w, r); ok {
+		writeAdminJSON(w, http.StatusOK, adminDataResponse{Data: newAdminScheduleResource(schedule)})
+	}
+}
+
+// handleCreateSchedule POST /admin/v1/schedules
+func (a *AdminAPI) handleCreateSchedule(w http.ResponseWriter, r *http.Request) {
+	var input adminScheduleInput
+	if !decodeAdminBody(w, r, &input) {
+		return
+	}
+
+	schedule := &models.Schedule{IsAvailable: true}
+	input.apply(schedule)
+
+	problems, err := a.validateSchedule(schedule)
+	if err != nil {
+		writeAdminInternalError(w, r, err)
+		return
+	}
+	if len(problems) > 0 {
+		writeAdminValidationError(w, problems)
+		return
+	}
+
+	if err := a.db.CreateSchedule(schedule); err != nil {
+		writeAdminInternalError(w, r, err)
+		return
+	}
+
+	InfoLog.Printf("Admin API: admin %d created schedule %d for veterinarian %d",
+		requestAdminID(r), schedule.ID, schedule.VetID)
+	writeAdminJSON(w, http.StatusCreated, adminDataResponse{Data: newAdminScheduleResource(schedule)})
+}
+
+// handleUpdateSchedule PATCH /admin/v1/schedules/{id}
+func (a *AdminAPI) handleUpdateSchedule(w http.ResponseWriter, r *http.Request) {
+	schedule, ok := a.loadSchedule(w, r)
+	if !ok {
+		return
+	}
+
+	var input adminScheduleInput
+	if !decodeAdminBody(w, r, &input) {
+		return
+	}
+	input.apply(schedule)
+
+	problems, err := a.validateSchedule(schedule)
+	if err != nil {
+		writeAdminInternalError(w, r, err)
+		return
+	}
+	if len(problems) > 0 {
+		writeAdminValidationError(w, problems)
+		return
+	}
+
+	if err := a.db.UpdateSchedule(schedule); err != nil {
+		writeAdminInternalError(w, r, err)
+		return
+	}
+
+	InfoLog.Printf("Admin API: admin %d updated schedule %d", requestAdminID(r), schedule.ID)
+	writeAdminJSON(w, http.StatusOK, adminDataResponse{Data: newAdminScheduleResource(schedule)})
+}
+
+// handleDeleteSchedule DELETE /admin/v1/schedules/{id}
+func (a *AdminAPI) handleDeleteSchedule(w http.ResponseWriter, r *http.Request) {
+	schedule, ok := a.loadSchedule(w, r)
+	if !ok {
+		return
+	}
+
+	if err := a.db.DeleteSchedule(schedule.ID); err != nil {
+		writeAdminInternalError(w, r, err)
+		return
+	}
+
+	InfoLog.Printf("Admin API: admin %d deleted schedule %d", requestAdminID(r), schedule.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleToggleSchedule POST /admin/v1/schedules/{id}/toggle-active - переключает is_available
+func (a *AdminAPI) handleToggleSchedule(w http.ResponseWriter, r *http.Request) {
+	schedule, ok := a.loadSchedule(w, r)
+	if !ok {
+		return
+	}
+
+	schedule.IsAvailable = !schedule.IsAvailable
+	problem, err := a.scheduleOverlapProblem(schedule)
+	if err != nil {
+		writeAdminInternalError(w, r, err)
+		return
+	}
+	if problem != "" {
+		writeAdminValidationError(w, []string{problem})
+		return
+	}
+	if err := a.db.UpdateSchedule(schedule); err != nil {
+		writeAdminInternalError(w, r, err)
+		return
+	}
+
+	InfoLog.Printf("Admin API: admin %d set schedule %d available=%t",
+		requestAdminID(r), schedule.ID, schedule.IsAvailable)
+	writeAdminJSON(w, http.StatusOK, adminDataResponse{Data: newAdminScheduleResource(schedule)})
+}
+
+// ========== МОДЕРАЦИЯ ОТЗЫВОВ ==========
+
+type adminReviewResource struct {
+	ID             int       `json:"id"`
+	VeterinarianID int       `json:"veterinarian_id"`
+	UserID         int       `json:"user_id"`
+	Rating         int       `json:"rating"`
+	Comment        string    `json:"comment,omitempty"`
+	Status         string    `json:"status"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func newAdminReviewResource(review *models.Review) adminReviewResource {
+	return adminReviewResource{
+		ID:             review.ID,
+		VeterinarianID: review.VeterinarianID,
+		UserID:         review.UserID,
+		Rating:         review.Rating,
+		Comment:        review.Comment,
+		Status:         review.Status,
+		CreatedAt:      review.CreatedAt,
+	}
+}
+
+// handlePendingReviews GET /admin/v1/reviews/pending
+func (a *AdminAPI) handlePendingReviews(w http.ResponseWriter, r *http.Request) {
+	reviews, err := a.db.GetPendingReviews()
+	if err != nil {
+		writeAdminInternalError(w, r, err)
+		return
+	}
+
+	resources := make([]adminReviewResource, 0, len(reviews))
+	for _, review := range reviews {
+		resources = append(resources, newAdminReviewResource(review))
+	}
+	writeAdminJSON(w, http.StatusOK, adminDataResponse{Data: resources})
+}
+
+// handleModerateReview POST /admin/v1/reviews/{id}/approve и /reject. Как и в боте, модератором
+// записывается пользователь бота с Telegram ID владельца токена
+func (a *AdminAPI) handleModerateReview(status string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := adminPathID(w, r, "review")
+		if !ok {
+			return
+		}
+
+		review, err := a.db.GetReviewByID(id)
+		if isNotFound(err) || (err == nil && (review == nil || review.ID == 0)) {
+			writeAdminError(w, http.StatusNotFound, "not_found", "review not found")
+			return
+		}
+		if err != nil {
+			writeAdminInternalError(w, r, err)
+			return
+		}
+
+		adminID := requestAdminID(r)
+		moderator, err := a.db.GetUserByTelegramID(adminID)
+		if isNotFound(err) || (err == nil && moderator == nil) {
+			writeAdminError(w, http.StatusConflict, "moderator_not_found",
+				"token owner must start the bot before moderating reviews")
+			return
+		}
+		if err != nil {
+			writeAdminInternalError(w, r, err)
+			return
+		}
+
+		if err := a.db.UpdateReviewStatus(review.ID, status, moderator.ID); err != nil {
+			writeAdminInternalError(w, r, err)
+			return
+		}
+
+		InfoLog.Printf("Admin API: admin %d set review %d status %s", adminID, review.ID, status)
+		review.Status = status
+		writeAdminJSON(w, http.StatusOK, adminDataResponse{Data: newAdminReviewResource(review)})
+	}
+}
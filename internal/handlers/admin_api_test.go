@@ -0,0 +1,334 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/drerr0r/vetbot/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAdminAPIToken = "test-admin-token-0123456789"
+
+// setupTestAdminAPI создает админский API с токеном администратора 12345 и тестовыми данными
+func setupTestAdminAPI() (*AdminAPI, *MockDatabase) {
+	admin, _, mockDB := CreateTestAdminHandlers()
+
+	mockDB.AddTestCity(1, "Москва", "Московская область")
+	mockDB.AddTestSpecialization(1, "Терапевт")
+	mockDB.AddTestSpecialization(2, "Хирург")
+	mockDB.AddTestVeterinarian(1, "Иван", "Петров", "+79990000001")
+	mockDB.Veterinarians[1].IsActive = true
+	mockDB.AddTestClinic(1, "ВетКлиника", "ул. Ленина, 1", 1)
+
+	return NewAdminAPI(admin, map[string]int64{testAdminAPIToken: 12345}), mockDB
+}
+
+// doAdminRequest выполняет запрос к API с токеном администратора
+func doAdminRequest(api *AdminAPI, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+testAdminAPIToken)
+	rec := httptest.NewRecorder()
+	api.ServeHTTP(rec, req)
+	return rec
+}
+
+// decodeAdminData разбирает поле data ответа
+func decodeAdminData(t *testing.T, rec *httptest.ResponseRecorder, target interface{}) {
+	t.Helper()
+	var response struct {
+		Data json.RawMessage `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.NoError(t, json.Unmarshal(response.Data, target))
+}
+
+func TestAdminAPIAuthentication(t *testing.T) {
+	api, _ := setupTestAdminAPI()
+
+	tests := []struct {
+		name         string
+		header       string
+		expectStatus int
+	}{
+		{"Valid token", "Bearer " + testAdminAPIToken, http.StatusOK},
+		{"Missing header", "", http.StatusUnauthorized},
+		{"Wrong token", "Bearer wrong-token-0123456789", http.StatusUnauthorized},
+		{"Wrong scheme", "Basic " + testAdminAPIToken, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/admin/v1/vets", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			api.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectStatus, rec.Code)
+			if tt.expectStatus == http.StatusUnauthorized {
+				assert.Contains(t, rec.Header().Get("WWW-Authenticate"), "Bearer")
+			}
+		})
+	}
+
+	t.Run("Unknown endpoint and wrong method", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, doAdminRequest(api, http.MethodGet, "/admin/v1/unknown", "").Code)
+		assert.Equal(t, http.StatusMethodNotAllowed, doAdminRequest(api, http.MethodPut, "/admin/v1/vets/1", "{}").Code)
+	})
+}
+
+func TestAdminAPIVets(t *testing.T) {
+	t.Run("Create with specializations", func(t *testing.T) {
+		api, mockDB := setupTestAdminAPI()
+
+		rec := doAdminRequest(api, http.MethodPost, "/admin/v1/vets",
+			`{"first_name":"Анна","last_name":"Смирнова","phone":"+79990000002","city_id":1,"specialization_ids":[2,1]}`)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+		var vet adminVetResource
+		decodeAdminData(t, rec, &vet)
+		assert.Equal(t, "Анна", vet.FirstName)
+		assert.True(t, vet.IsActive)
+		assert.Equal(t, []int{1, 2}, vet.SpecializationIDs)
+		require.NotNil(t, vet.CityID)
+		assert.Equal(t, int64(1), *vet.CityID)
+		assert.Len(t, mockDB.Veterinarians, 2)
+	})
+
+	t.Run("Create reuses bot validation", func(t *testing.T) {
+		api, mockDB := setupTestAdminAPI()
+
+		rec := doAdminRequest(api, http.MethodPost, "/admin/v1/vets",
+			`{"first_name":"Анна","specialization_ids":[99],"city_id":7}`)
+		require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+		body := rec.Body.String()
+		assert.Contains(t, body, "validation_failed")
+		assert.Contains(t, body, "Фамилия")
+		assert.Contains(t, body, "Телефон")
+		assert.Contains(t, body, "неверные ID специализаций: 99")
+		assert.Contains(t, body, "город 7 не найден")
+		assert.Len(t, mockDB.Veterinarians, 1)
+	})
+
+	t.Run("Unknown fields are rejected", func(t *testing.T) {
+		api, _ := setupTestAdminAPI()
+
+		rec := doAdminRequest(api, http.MethodPost, "/admin/v1/vets", `{"first_nam":"Анна"}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Patch changes only given fields", func(t *testing.T) {
+		api, mockDB := setupTestAdminAPI()
+
+		rec := doAdminRequest(api, http.MethodPatch, "/admin/v1/vets/1", `{"email":"ivan@example.com","specialization_ids":[1]}`)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		vet := mockDB.Veterinarians[1]
+		assert.Equal(t, "Иван", vet.FirstName)
+		assert.Equal(t, "ivan@example.com", vet.Email.String)
+		require.Len(t, vet.Specializations, 1)
+		assert.Equal(t, 1, vet.Specializations[0].ID)
+	})
+
+	t.Run("Failed patch leaves vet unchanged", func(t *testing.T) {
+		api, mockDB := setupTestAdminAPI()
+
+		rec := doAdminRequest(api, http.MethodPatch, "/admin/v1/vets/1", `{"phone":"  "}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Equal(t, "+79990000001", mockDB.Veterinarians[1].Phone)
+	})
+
+	t.Run("Toggle and delete", func(t *testing.T) {
+		api, mockDB := setupTestAdminAPI()
+
+		rec := doAdminRequest(api, http.MethodPost, "/admin/v1/vets/1/toggle-active", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.False(t, mockDB.Veterinarians[1].IsActive)
+
+		rec = doAdminRequest(api, http.MethodDelete, "/admin/v1/vets/1", "")
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Empty(t, mockDB.Veterinarians)
+
+		rec = doAdminRequest(api, http.MethodDelete, "/admin/v1/vets/1", "")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestAdminAPIClinicsAndCities(t *testing.T) {
+	t.Run("Clinic coordinates are validated", func(t *testing.T) {
+		api, _ := setupTestAdminAPI()
+
+		rec := doAdminRequest(api, http.MethodPost, "/admin/v1/clinics",
+			`{"name":"Айболит","address":"ул. Мира, 5","latitude":95}`)
+		require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Contains(t, rec.Body.String(), "latitude и longitude")
+
+		rec = doAdminRequest(api, http.MethodPost, "/admin/v1/clinics",
+			`{"name":"Айболит","address":"ул. Мира, 5","latitude":95,"longitude":37}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	})
+
+	t.Run("Clinic create and toggle", func(t *testing.T) {
+		api, mockDB := setupTestAdminAPI()
+
+		rec := doAdminRequest(api, http.MethodPost, "/admin/v1/clinics",
+			`{"name":"Айболит","address":"ул. Мира, 5","city_id":1,"latitude":55.75,"longitude":37.61}`)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+		var clinic adminClinicResource
+		decodeAdminData(t, rec, &clinic)
+		assert.True(t, clinic.IsActive)
+		require.NotNil(t, clinic.Latitude)
+		assert.Equal(t, 55.75, *clinic.Latitude)
+
+		rec = doAdminRequest(api, http.MethodPost, "/admin/v1/clinics/1/toggle-active", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.False(t, mockDB.Clinics[1].IsActive)
+	})
+
+	t.Run("Duplicate city name conflicts", func(t *testing.T) {
+		api, mockDB := setupTestAdminAPI()
+
+		rec := doAdminRequest(api, http.MethodPost, "/admin/v1/cities", `{"name":"Москва"}`)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+
+		rec = doAdminRequest(api, http.MethodPost, "/admin/v1/cities", `{"name":"Казань","region":"Татарстан"}`)
+		require.Equal(t, http.StatusCreated, rec.Code)
+		assert.Len(t, mockDB.Cities, 2)
+
+		// Переименование города в собственное название конфликтом не считается
+		rec = doAdminRequest(api, http.MethodPatch, "/admin/v1/cities/1", `{"name":"Москва","region":"Москва"}`)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "Москва", mockDB.Cities[1].Region)
+	})
+
+	t.Run("Toggle is not available for cities", func(t *testing.T) {
+		api, _ := setupTestAdminAPI()
+
+		rec := doAdminRequest(api, http.MethodPost, "/admin/v1/cities/1/toggle-active", "")
+		assert.NotEqual(t, http.StatusOK, rec.Code)
+	})
+}
+
+func TestAdminAPISpecializations(t *testing.T) {
+	api, mockDB := setupTestAdminAPI()
+	mockDB.Veterinarians[1].Specializations = []*models.Specialization{mockDB.Specializations[1], mockDB.Specializations[2]}
+
+	rec := doAdminRequest(api, http.MethodPost, "/admin/v1/specializations", `{"name":"Хирург"}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = doAdminRequest(api, http.MethodPatch, "/admin/v1/specializations/2", `{"description":"Операции"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "Операции", mockDB.Specializations[2].Description)
+
+	rec = doAdminRequest(api, http.MethodDelete, "/admin/v1/specializations/2", "")
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	assert.NotContains(t, mockDB.Specializations, 2)
+	require.Len(t, mockDB.Veterinarians[1].Specializations, 1)
+	assert.Equal(t, 1, mockDB.Veterinarians[1].Specializations[0].ID)
+}
+
+func TestAdminAPISchedules(t *testing.T) {
+	t.Run("Create validates schedule and references", func(t *testing.T) {
+		api, mockDB := setupTestAdminAPI()
+
+		rec := doAdminRequest(api, http.MethodPost, "/admin/v1/schedules",
+			`{"vet_id":5,"clinic_id":1,"day_of_week":8,"start_time":"09:00","end_time":"09:00"}`)
+		require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Contains(t, rec.Body.String(), "врач 5 не найден")
+		assert.Empty(t, mockDB.Schedules)
+
+		rec = doAdminRequest(api, http.MethodPost, "/admin/v1/schedules",
+			`{"vet_id":1,"clinic_id":1,"day_of_week":1,"start_time":"09:00","end_time":"18:00"}`)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		require.Len(t, mockDB.Schedules, 1)
+		assert.True(t, mockDB.Schedules[1].IsAvailable)
+
+		// Врач не может принимать в двух местах одновременно
+		rec = doAdminRequest(api, http.MethodPost, "/admin/v1/schedules",
+			`{"vet_id":1,"clinic_id":1,"day_of_week":1,"start_time":"17:00","end_time":"20:00"}`)
+		require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Contains(t, rec.Body.String(), "пересекается с приемом 1")
+		assert.Len(t, mockDB.Schedules, 1)
+	})
+
+	t.Run("Patch, toggle and vet schedules", func(t *testing.T) {
+		api, mockDB := setupTestAdminAPI()
+		mockDB.Schedules[1] = &models.Schedule{ID: 1, VetID: 1, ClinicID: 1, DayOfWeek: 2, StartTime: "10:00", EndTime: "14:00", IsAvailable: true}
+
+		rec := doAdminRequest(api, http.MethodPatch, "/admin/v1/schedules/1", `{"end_time":"16:00"}`)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Equal(t, "16:00", mockDB.Schedules[1].EndTime)
+
+		rec = doAdminRequest(api, http.MethodGet, "/admin/v1/vets/1/schedules", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		var schedules []adminScheduleResource
+		decodeAdminData(t, rec, &schedules)
+		require.Len(t, schedules, 1)
+		assert.Equal(t, "16:00", schedules[0].EndTime)
+
+		rec = doAdminRequest(api, http.MethodPost, "/admin/v1/schedules/1/toggle-active", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.False(t, mockDB.Schedules[1].IsAvailable)
+
+		// Недоступные приемы админ тоже видит
+		rec = doAdminRequest(api, http.MethodGet, "/admin/v1/vets/1/schedules", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		schedules = nil
+		decodeAdminData(t, rec, &schedules)
+		require.Len(t, schedules, 1)
+		assert.False(t, schedules[0].IsAvailable)
+
+		rec = doAdminRequest(api, http.MethodDelete, "/admin/v1/schedules/1", "")
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Empty(t, mockDB.Schedules)
+	})
+}
+
+func TestAdminAPIReviewModeration(t *testing.T) {
+	api, mockDB := setupTestAdminAPI()
+
+	reviews := map[int]*models.Review{7: {ID: 7, VeterinarianID: 1, Rating: 5, Status: "pending"}}
+	mockDB.GetReviewByIDFunc = func(reviewID int) (*models.Review, error) {
+		if review, ok := reviews[reviewID]; ok {
+			return review, nil
+		}
+		return nil, nil
+	}
+	mockDB.GetPendingReviewsFunc = func() ([]*models.Review, error) {
+		return []*models.Review{reviews[7]}, nil
+	}
+
+	var updatedStatus string
+	var updatedModerator int
+	mockDB.UpdateReviewStatusFunc = func(reviewID int, status string, moderatorID int) error {
+		updatedStatus, updatedModerator = status, moderatorID
+		return nil
+	}
+
+	rec := doAdminRequest(api, http.MethodGet, "/admin/v1/reviews/pending", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"id":7`)
+
+	// Администратор еще не запускал бота - модератора записать нельзя
+	rec = doAdminRequest(api, http.MethodPost, "/admin/v1/reviews/7/approve", "")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Empty(t, updatedStatus)
+
+	mockDB.Users[12345] = &models.User{ID: 3, TelegramID: 12345}
+
+	rec = doAdminRequest(api, http.MethodPost, "/admin/v1/reviews/7/reject", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "rejected", updatedStatus)
+	assert.Equal(t, 3, updatedModerator)
+
+	rec = doAdminRequest(api, http.MethodPost, "/admin/v1/reviews/8/approve", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"database/sql"
 	"fmt"
 	"io"
 	"log"
@@ -10,6 +11,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/drerr0r/vetbot/internal/imports"
@@ -23,15 +25,27 @@ var (
 	ErrorLog = log.New(os.Stdout, "ERROR: ", log.Ldate|log.Ltime|log.Lshortfile)
 )
 
+const (
+	// maxImportFileSize максимальный размер файла импорта (столько же бот может скачать из Telegram)
+	maxImportFileSize = 20 << 20
+	// maxImportPreviewRows сколько строк каждого раздела показывать в предпросмотре импорта
+	maxImportPreviewRows = 10
+)
+
 // AdminHandlers содержит обработчики для административных функций
 type AdminHandlers struct {
 	bot            BotAPI
 	db             Database
 	config         *utils.Config
 	stateManager   *StateManager
-	adminState     map[int64]string
-	tempData       map[string]interface{}
 	reviewHandlers *ReviewHandlers
+
+	// Состояние и временные данные админ-панели хранятся в сессии пользователя (stateManager)
+
+	// Фоновые задачи импорта; jobsMutex защищает importJobs
+	jobsMutex  sync.Mutex
+	importJobs map[int]*importJob
+	importWG   sync.WaitGroup
 }
 
 // NewAdminHandlers создает новый экземпляр AdminHandlers
@@ -41,25 +55,26 @@ func NewAdminHandlers(bot BotAPI, db Database, config *utils.Config, stateManage
 		db:             db,
 		config:         config,
 		stateManager:   stateManager,
-		adminState:     make(map[int64]string),
-		tempData:       make(map[string]interface{}),
 		reviewHandlers: reviewHandlers,
+		importJobs:     make(map[int]*importJob),
 	}
 }
 
 // HandleAdmin показывает админскую панель
 func (h *AdminHandlers) HandleAdmin(update tgbotapi.Update) {
 	userID := update.Message.From.ID
-	h.adminState[userID] = "main_menu"
+	h.stateManager.SetAdminState(userID, "main_menu")
 
 	keyboard := tgbotapi.NewReplyKeyboard(
 		tgbotapi.NewKeyboardButtonRow(
 			tgbotapi.NewKeyboardButton("👥 Управление врачами"),
 			tgbotapi.NewKeyboardButton("🏥 Управление клиниками"),
+			tgbotapi.NewKeyboardButton("🎯 Специализации"),
 		),
 		tgbotapi.NewKeyboardButtonRow(
 			tgbotapi.NewKeyboardButton("🏙️ Управление городами"),
 			tgbotapi.NewKeyboardButton("📥 Импорт данных"),
+			tgbotapi.NewKeyboardButton("📤 Экспорт данных"),
 		),
 		tgbotapi.NewKeyboardButtonRow(
 			tgbotapi.NewKeyboardButton("📊 Статистика"),
@@ -82,17 +97,18 @@ func (h *AdminHandlers) HandleAdmin(update tgbotapi.Update) {
 	}
 }
 
+// HandleAdminMessage обрабатывает сообщения администратора в админ-панели
 func (h *AdminHandlers) HandleAdminMessage(update tgbotapi.Update) {
 	userID := update.Message.From.ID
 	text := update.Message.Text
-	state := h.adminState[userID]
+	state := h.stateManager.GetAdminState(userID)
 
 	InfoLog.Printf("🔍 DEBUG AdminMessage: user %d, text '%s', state '%s'", userID, text, state)
 
 	// Если пользователь ввел команду /admin, ВСЕГДА сбрасываем состояние
 	if text == "/admin" {
 		InfoLog.Printf("🔍 DEBUG: /admin command detected, resetting state to main_menu")
-		h.adminState[userID] = "main_menu"
+		h.stateManager.SetAdminState(userID, "main_menu")
 		h.HandleAdmin(update)
 		return
 	}
@@ -127,8 +143,12 @@ func (h *AdminHandlers) HandleAdminMessage(update tgbotapi.Update) {
 		h.handleClinicManagement(update, text)
 	case "city_management":
 		h.handleCityManagement(update, text)
-	case "import_menu":
+	case "import_menu", "import_veterinarians", "import_cities", "import_clinics":
 		h.handleImportMenu(update, text)
+	case "import_confirm":
+		h.handleImportConfirm(update, text)
+	case "export_menu":
+		h.handleExportMenu(update, text)
 	case "add_vet_name":
 		h.handleAddVetName(update, text)
 	case "add_vet_phone":
@@ -149,12 +169,46 @@ func (h *AdminHandlers) HandleAdminMessage(update tgbotapi.Update) {
 		h.handleVetConfirmDelete(update, text)
 	case "vet_toggle_active":
 		h.handleVetToggleActive(update, text)
+	case "vet_edit_exceptions":
+		h.handleVetExceptionsMenu(update, text)
+	case "vet_edit_exception_absence":
+		h.handleVetExceptionAbsence(update, text)
+	case "vet_edit_exception_hours":
+		h.handleVetExceptionHours(update, text)
+	case "vet_edit_exception_delete":
+		h.handleVetExceptionDelete(update, text)
+	case "vet_edit_schedules":
+		h.handleVetSchedulesMenu(update, text)
+	case "vet_edit_schedule_add":
+		h.handleVetScheduleAdd(update, text)
+	case "vet_edit_schedule_edit":
+		h.handleVetScheduleEdit(update, text)
+	case "vet_edit_schedule_toggle":
+		h.handleVetScheduleToggle(update, text)
+	case "vet_edit_schedule_delete":
+		h.handleVetScheduleDelete(update, text)
+	case "vet_edit_clinics":
+		h.handleVetClinicsMenu(update, text)
+	case "vet_edit_clinic_add":
+		h.handleVetClinicAdd(update, text)
+	case "vet_edit_clinic_remove":
+		h.handleVetClinicRemove(update, text)
+	case "vet_edit_clinic_confirm_remove":
+		h.handleVetClinicConfirmRemove(update, text)
 	case "clinic_list":
 		h.handleClinicListSelection(update, text)
 	case "clinic_edit_menu":
 		h.handleClinicEditMenu(update, text)
 	case "clinic_edit_field":
 		h.handleClinicEditField(update, text)
+	case "clinic_edit_vets":
+		h.handleClinicVetsMenu(update, text)
+	case "clinic_edit_vet_add":
+		h.handleClinicVetAdd(update, text)
+	case "clinic_edit_vet_remove":
+		h.handleClinicVetRemove(update, text)
+	case "clinic_edit_vet_confirm_remove":
+		h.handleClinicVetConfirmRemove(update, text)
 	case "clinic_confirm_delete":
 		h.handleClinicConfirmDelete(update, text)
 	case "clinic_toggle_active":
@@ -171,10 +225,28 @@ func (h *AdminHandlers) HandleAdminMessage(update tgbotapi.Update) {
 		h.handleCityEditName(update, text)
 	case "city_edit_region":
 		h.handleCityEditRegion(update, text)
+	case "city_edit_timezone":
+		h.handleCityEditTimezone(update, text)
 	case "city_confirm_delete":
 		h.handleCityConfirmDelete(update, text)
 	case "city_search_region":
 		h.handleCitySearchRegion(update, text)
+	case "spec_management":
+		h.handleSpecializationManagement(update, text)
+	case "spec_add_name":
+		h.handleAddSpecializationName(update, text)
+	case "spec_edit_menu":
+		h.handleSpecializationEditMenu(update, text)
+	case "spec_edit_name":
+		h.handleSpecializationEditName(update, text)
+	case "spec_edit_description":
+		h.handleSpecializationEditDescription(update, text)
+	case "spec_merge":
+		h.handleSpecializationMerge(update, text)
+	case "spec_confirm_merge":
+		h.handleSpecializationConfirmMerge(update, text)
+	case "spec_confirm_delete":
+		h.handleSpecializationConfirmDelete(update, text)
 	case "review_moderation":
 		// Передаем управление в ReviewHandlers для обработки модерации
 		h.reviewHandlers.HandleReviewModerationInput(update)
@@ -193,16 +265,20 @@ func (h *AdminHandlers) handleCancelProcess(update tgbotapi.Update, state string
 	// Определяем куда вернуться в зависимости от состояния
 	switch {
 	case strings.HasPrefix(state, "add_vet"), strings.HasPrefix(state, "vet_edit"):
-		h.adminState[userID] = "vet_management"
+		h.stateManager.SetAdminState(userID, "vet_management")
 		h.showVetManagement(update)
 	case strings.HasPrefix(state, "add_city"), strings.HasPrefix(state, "city_edit"):
-		h.adminState[userID] = "city_management"
+		h.stateManager.SetAdminState(userID, "city_management")
 		h.showCityManagement(update)
 	case strings.HasPrefix(state, "add_clinic"), strings.HasPrefix(state, "clinic_edit"):
-		h.adminState[userID] = "clinic_management"
+		h.stateManager.SetAdminState(userID, "clinic_management")
 		h.showClinicManagement(update)
+	case strings.HasPrefix(state, "spec_"):
+		h.showSpecializationManagement(update)
+	case state == "import_confirm":
+		h.showImportMenu(update)
 	default:
-		h.adminState[userID] = "main_menu"
+		h.stateManager.SetAdminState(userID, "main_menu")
 		h.HandleAdmin(update)
 	}
 
@@ -215,51 +291,113 @@ func (h *AdminHandlers) handleCancelProcess(update tgbotapi.Update, state string
 // handleBackButton обрабатывает кнопку "Назад"
 func (h *AdminHandlers) handleBackButton(update tgbotapi.Update) {
 	userID := update.Message.From.ID
-	currentState := h.adminState[userID]
+	currentState := h.stateManager.GetAdminState(userID)
 
 	// Определяем текущее состояние и возвращаемся на уровень выше
 	switch currentState {
-	case "vet_management", "clinic_management", "city_management", "import_menu":
-		h.adminState[userID] = "main_menu"
+	case "vet_management", "clinic_management", "city_management", "spec_management", "import_menu", "export_menu":
+		h.stateManager.SetAdminState(userID, "main_menu")
 		h.HandleAdmin(update)
+	case "import_veterinarians", "import_cities", "import_clinics", "import_confirm":
+		h.cleanTempData(userID)
+		h.showImportMenu(update)
+	case "vet_edit_exceptions", "vet_edit_schedules", "vet_edit_clinics":
+		h.returnToVetEditMenu(update)
+	case "vet_edit_clinic_add", "vet_edit_clinic_remove", "vet_edit_clinic_confirm_remove":
+		if vetData, ok := h.stateManager.GetAdminData(userID, "vet_edit").(*models.VetEditData); ok {
+			h.showVetClinics(update, vetData.VetID)
+		} else {
+			h.stateManager.SetAdminState(userID, "vet_management")
+			h.showVetManagement(update)
+		}
+	case "clinic_edit_vets":
+		h.returnToClinicEditMenu(update)
+	case "clinic_edit_vet_add", "clinic_edit_vet_remove", "clinic_edit_vet_confirm_remove":
+		if clinic := h.currentEditClinic(update); clinic != nil {
+			h.showClinicVets(update, clinic.ID)
+		}
+	case "vet_edit_schedule_add", "vet_edit_schedule_edit", "vet_edit_schedule_toggle", "vet_edit_schedule_delete":
+		if vetData, ok := h.stateManager.GetAdminData(userID, "vet_edit").(*models.VetEditData); ok {
+			h.showVetSchedules(update, vetData.VetID)
+		} else {
+			h.stateManager.SetAdminState(userID, "vet_management")
+			h.showVetManagement(update)
+		}
+	case "city_edit_timezone":
+		h.returnToCityEditMenu(update)
+	case "spec_add_name", "spec_edit_menu":
+		h.showSpecializationManagement(update)
+	case "spec_edit_name", "spec_edit_description", "spec_merge", "spec_confirm_merge", "spec_confirm_delete":
+		h.returnToSpecializationEditMenu(update)
+	case "vet_edit_exception_absence", "vet_edit_exception_hours", "vet_edit_exception_delete":
+		if vetData, ok := h.stateManager.GetAdminData(userID, "vet_edit").(*models.VetEditData); ok {
+			h.showVetExceptions(update, vetData.VetID)
+		} else {
+			h.stateManager.SetAdminState(userID, "vet_management")
+			h.showVetManagement(update)
+		}
 	case "vet_list", "vet_edit_menu", "vet_edit_field", "vet_edit_specializations",
 		"vet_edit_city", "vet_confirm_delete", "vet_toggle_active":
-		h.adminState[userID] = "vet_management"
+		h.stateManager.SetAdminState(userID, "vet_management")
 		h.showVetManagement(update)
 	case "clinic_list", "clinic_edit_menu", "clinic_edit_field", "clinic_confirm_delete", "clinic_toggle_active":
-		h.adminState[userID] = "clinic_management"
+		h.stateManager.SetAdminState(userID, "clinic_management")
 		h.showClinicManagement(update)
 	case "city_list", "city_edit_menu", "city_edit_name", "city_edit_region",
 		"city_search_region", "city_confirm_delete":
-		h.adminState[userID] = "city_management"
+		h.stateManager.SetAdminState(userID, "city_management")
 		h.showCityManagement(update)
 	case "vet_search_city":
-		h.adminState[userID] = "vet_management"
+		h.stateManager.SetAdminState(userID, "vet_management")
 		h.showVetManagement(update)
 	case "add_vet_name", "add_vet_phone", "add_vet_specializations":
-		h.adminState[userID] = "vet_management"
+		h.stateManager.SetAdminState(userID, "vet_management")
 		h.cleanTempData(userID)
 		h.showVetManagement(update)
 	case "add_city_name", "add_city_region":
-		h.adminState[userID] = "city_management"
+		h.stateManager.SetAdminState(userID, "city_management")
 		h.cleanTempData(userID)
 		h.showCityManagement(update)
 	default:
-		h.adminState[userID] = "main_menu"
+		h.stateManager.SetAdminState(userID, "main_menu")
 		h.HandleAdmin(update)
 	}
 }
 
 // cleanTempData очищает временные данные пользователя
 func (h *AdminHandlers) cleanTempData(userID int64) {
-	userIDStr := strconv.FormatInt(userID, 10)
-	delete(h.tempData, userIDStr+"_name")
-	delete(h.tempData, userIDStr+"_phone")
-	delete(h.tempData, userIDStr+"_vet_edit")
-	delete(h.tempData, userIDStr+"_clinic_edit")
-	delete(h.tempData, userIDStr+"_city_edit")
-	delete(h.tempData, userIDStr+"_new_city")
-	delete(h.tempData, userIDStr+"_cities")
+	h.stateManager.ClearAdminDataByKey(userID, "name")
+	h.stateManager.ClearAdminDataByKey(userID, "phone")
+	h.stateManager.ClearAdminDataByKey(userID, "vet_edit")
+	h.stateManager.ClearAdminDataByKey(userID, "clinic_edit")
+	h.stateManager.ClearAdminDataByKey(userID, "spec_edit")
+	h.stateManager.ClearAdminDataByKey(userID, "city_edit")
+	h.stateManager.ClearAdminDataByKey(userID, "new_city")
+	h.stateManager.ClearAdminDataByKey(userID, "cities")
+	h.stateManager.ClearAdminDataByKey(userID, "unlink_clinic_id")
+	h.stateManager.ClearAdminDataByKey(userID, "unlink_vet_id")
+	h.stateManager.ClearAdminDataByKey(userID, "merge_target_id")
+	h.discardPendingImport(userID)
+}
+
+// HasActiveSession проверяет, работает ли пользователь сейчас в админ-панели
+func (h *AdminHandlers) HasActiveSession(userID int64) bool {
+	return h.stateManager.GetAdminState(userID) != ""
+}
+
+// IsAwaitingLocation проверяет, ждет ли админ-панель от пользователя геопозицию (координаты клиники)
+func (h *AdminHandlers) IsAwaitingLocation(userID int64) bool {
+	if h.stateManager.GetAdminState(userID) != "clinic_edit_field" {
+		return false
+	}
+	clinicData, ok := h.stateManager.GetAdminData(userID, "clinic_edit").(*models.ClinicEditData)
+	return ok && clinicData != nil && clinicData.Field == "coordinates"
+}
+
+// EndSession завершает сессию админ-панели без отправки сообщений
+func (h *AdminHandlers) EndSession(userID int64) {
+	h.discardPendingImport(userID)
+	h.stateManager.ClearAdminSession(userID)
 }
 
 func (h *AdminHandlers) handleMainMenu(update tgbotapi.Update, text string) {
@@ -270,14 +408,18 @@ func (h *AdminHandlers) handleMainMenu(update tgbotapi.Update, text string) {
 		h.showClinicManagement(update)
 	case "🏙️ Управление городами":
 		h.showCityManagement(update)
+	case "🎯 Специализации":
+		h.showSpecializationManagement(update)
 	case "📥 Импорт данных":
 		h.showImportMenu(update)
+	case "📤 Экспорт данных":
+		h.showExportMenu(update)
 	case "📊 Статистика":
 		h.HandleStats(update)
 	case "⭐ Модерация отзывов":
 		// ВАЖНО: Устанавливаем состояние перед вызовом HandleReviewModeration
 		userID := update.Message.From.ID
-		h.adminState[userID] = "review_moderation"
+		h.stateManager.SetAdminState(userID, "review_moderation")
 		h.reviewHandlers.HandleReviewModeration(update)
 	case "⚙️ Настройки":
 		h.showSettings(update)
@@ -295,7 +437,7 @@ func (h *AdminHandlers) handleMainMenu(update tgbotapi.Update, text string) {
 // showImportMenu показывает меню импорта данных
 func (h *AdminHandlers) showImportMenu(update tgbotapi.Update) {
 	userID := update.Message.From.ID
-	h.adminState[userID] = "import_menu"
+	h.stateManager.SetAdminState(userID, "import_menu")
 
 	keyboard := tgbotapi.NewReplyKeyboard(
 		tgbotapi.NewKeyboardButtonRow(
@@ -304,6 +446,9 @@ func (h *AdminHandlers) showImportMenu(update tgbotapi.Update) {
 		),
 		tgbotapi.NewKeyboardButtonRow(
 			tgbotapi.NewKeyboardButton("🏥 Импорт клиник"),
+			tgbotapi.NewKeyboardButton("📋 Задачи импорта"),
+		),
+		tgbotapi.NewKeyboardButtonRow(
 			tgbotapi.NewKeyboardButton("🔙 Назад"),
 		),
 	)
@@ -312,7 +457,12 @@ func (h *AdminHandlers) showImportMenu(update tgbotapi.Update) {
 		"📥 *Импорт данных*\n\nВыберите тип данных для импорта. Поддерживаются CSV и Excel файлы.\n\n"+
 			"*Формат файлов:*\n"+
 			"• CSV: разделитель - точка с запятой\n"+
-			"• Excel: первый лист с данными")
+			"• Excel: лист \"Врачи\", \"Клиники\" или \"Города\", иначе первый лист\n\n"+
+			"Файл из раздела \"📤 Экспорт данных\" можно исправить и загрузить обратно.\n\n"+
+			"Перед импортом бот проверит файл целиком, покажет, что будет создано, обновлено и пропущено, "+
+			"и попросит подтверждение. Проверка и импорт идут в фоне, прогресс виден в сообщении о задаче.\n\n"+
+			"Существующие записи находятся по имени, фамилии и телефону врача, названию и адресу клиники, "+
+			"названию города - у них обновляются измененные поля. Пустые ячейки данные не стирают.")
 	msg.ParseMode = "Markdown"
 	msg.ReplyMarkup = keyboard
 
@@ -328,6 +478,8 @@ func (h *AdminHandlers) handleImportMenu(update tgbotapi.Update, text string) {
 		h.handleImportVeterinarians(update)
 	case "🏥 Импорт клиник":
 		h.handleImportClinics(update)
+	case "📋 Задачи импорта":
+		h.HandleImportJobs(update)
 	case "🔙 Назад":
 		h.handleBackButton(update)
 	default:
@@ -338,6 +490,8 @@ func (h *AdminHandlers) handleImportMenu(update tgbotapi.Update, text string) {
 
 // handleImportCities обрабатывает импорт городов
 func (h *AdminHandlers) handleImportCities(update tgbotapi.Update) {
+	h.stateManager.SetAdminState(update.Message.From.ID, "import_cities")
+
 	msg := tgbotapi.NewMessage(update.Message.Chat.ID,
 		"📤 Для импорта городов отправьте CSV или Excel файл со следующими колонками:\n\n"+
 			"1. *Название города* (обязательно)\n"+
@@ -351,6 +505,8 @@ func (h *AdminHandlers) handleImportCities(update tgbotapi.Update) {
 
 // handleImportVeterinarians обрабатывает импорт врачей
 func (h *AdminHandlers) handleImportVeterinarians(update tgbotapi.Update) {
+	h.stateManager.SetAdminState(update.Message.From.ID, "import_veterinarians")
+
 	msg := tgbotapi.NewMessage(update.Message.Chat.ID,
 		"📤 Для импорта врачей отправьте CSV или Excel файл со следующими колонками:\n\n"+
 			"1. *Имя* (обязательно)\n"+
@@ -359,15 +515,21 @@ func (h *AdminHandlers) handleImportVeterinarians(update tgbotapi.Update) {
 			"4. *Email* (опционально)\n"+
 			"5. *Опыт работы* (опционально, число)\n"+
 			"6. *Описание* (опционально)\n"+
-			"7. *Специализации* (опционально, через запятую)\n\n"+
+			"7. *Город* (опционально)\n"+
+			"8. *Специализации* (опционально, через запятую)\n"+
+			"9. *Клиники и расписание* (опционально)\n\n"+
+			"Если врач с таким именем, фамилией и телефоном уже есть, заполненные поля обновятся, "+
+			"специализации и клиники добавятся к существующим, а расписание заменится только в указанных клиниках и днях.\n\n"+
 			"*Пример CSV:*\n"+
-			"Иван;Петров;+79161234567;ivan@vet.ru;10;Опытный хирург;Хирург,Терапевт")
+			"Иван;Петров;+79161234567;ivan@vet.ru;10;Опытный хирург;Москва;Хирург, Терапевт;ВетКлиника Центр:пн:9-18,ср:9:30-18")
 	msg.ParseMode = "Markdown"
 	h.bot.Send(msg)
 }
 
 // handleImportClinics обрабатывает импорт клиник
 func (h *AdminHandlers) handleImportClinics(update tgbotapi.Update) {
+	h.stateManager.SetAdminState(update.Message.From.ID, "import_clinics")
+
 	msg := tgbotapi.NewMessage(update.Message.Chat.ID,
 		"📤 Для импорта клиник отправьте CSV или Excel файл со следующими колонками:\n\n"+
 			"1. *Название* (обязательно)\n"+
@@ -376,13 +538,119 @@ func (h *AdminHandlers) handleImportClinics(update tgbotapi.Update) {
 			"4. *Телефон* (опционально)\n"+
 			"5. *Часы работы* (опционально)\n"+
 			"6. *Район* (опционально)\n"+
-			"7. *Станция метро* (опционально)\n\n"+
+			"7. *Станция метро* (опционально)\n"+
+			"8. *Широта* (опционально, например 55.7577)\n"+
+			"9. *Долгота* (опционально, например 37.6156)\n\n"+
+			"Координаты нужны для поиска клиник рядом с пользователем. "+
+			"Для уже существующих клиник (то же название и адрес) обновляются заполненные поля.\n\n"+
 			"*Пример CSV:*\n"+
-			"ВетКлиника Центр;Москва;ул. Центральная, д.1;+74950000001;Пн-Пт 9-21;Центральный;Охотный ряд")
+			"ВетКлиника Центр;Москва;ул. Центральная, д.1;+74950000001;Пн-Пт 9-21;Центральный;Охотный ряд;55.7577;37.6156")
 	msg.ParseMode = "Markdown"
 	h.bot.Send(msg)
 }
 
+// showExportMenu показывает меню выгрузки справочника
+func (h *AdminHandlers) showExportMenu(update tgbotapi.Update) {
+	userID := update.Message.From.ID
+	h.stateManager.SetAdminState(userID, "export_menu")
+
+	keyboard := tgbotapi.NewReplyKeyboard(
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton("📗 Excel (XLSX)"),
+			tgbotapi.NewKeyboardButton("📄 CSV"),
+		),
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton("🔙 Назад"),
+		),
+	)
+
+	msg := tgbotapi.NewMessage(update.Message.Chat.ID,
+		"📤 *Экспорт данных*\n\n"+
+			"Выгрузка врачей, клиник и городов в формате шаблона импорта. "+
+			"Файл можно исправить в Excel и загрузить обратно через \"📥 Импорт данных\".\n\n"+
+			"• Excel: одна книга с листами \"Врачи\", \"Клиники\" и \"Города\"\n"+
+			"• CSV: отдельный файл на каждый лист\n\n"+
+			"То же самое доступно командой /export или /export csv")
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = keyboard
+
+	h.bot.Send(msg)
+}
+
+// handleExportMenu обрабатывает меню выгрузки
+func (h *AdminHandlers) handleExportMenu(update tgbotapi.Update, text string) {
+	switch text {
+	case "📗 Excel (XLSX)":
+		h.sendDirectoryExport(update.Message.Chat.ID, "xlsx")
+	case "📄 CSV":
+		h.sendDirectoryExport(update.Message.Chat.ID, "csv")
+	case "🔙 Назад":
+		h.handleBackButton(update)
+	default:
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Используйте кнопки меню экспорта")
+		h.bot.Send(msg)
+	}
+}
+
+// HandleExport выгружает справочник по команде /export [xlsx|csv]
+func (h *AdminHandlers) HandleExport(update tgbotapi.Update) {
+	format := strings.ToLower(strings.TrimSpace(update.Message.CommandArguments()))
+	if format == "" {
+		format = "xlsx"
+	}
+
+	if format != "xlsx" && format != "csv" {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID,
+			"Неизвестный формат. Используйте /export или /export csv")
+		h.bot.Send(msg)
+		return
+	}
+
+	h.sendDirectoryExport(update.Message.Chat.ID, format)
+}
+
+// sendDirectoryExport формирует выгрузку справочника и отправляет файлы в чат
+func (h *AdminHandlers) sendDirectoryExport(chatID int64, format string) {
+	exporter := NewDirectoryExporter(h.db)
+	now := time.Now()
+
+	var files []*ExportFile
+	var err error
+	if format == "csv" {
+		files, err = exporter.ExportCSV(now)
+	} else {
+		var file *ExportFile
+		file, err = exporter.ExportXLSX(now)
+		files = []*ExportFile{file}
+	}
+	if err != nil {
+		ErrorLog.Printf("❌ Ошибка выгрузки справочника: %v", err)
+		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Ошибка выгрузки: %v", err))
+		h.bot.Send(msg)
+		return
+	}
+
+	var warnings []string
+	for _, file := range files {
+		document := tgbotapi.NewDocument(chatID, tgbotapi.FileBytes{Name: file.Name, Bytes: file.Data})
+		if _, err := h.bot.Send(document); err != nil {
+			ErrorLog.Printf("❌ Ошибка отправки файла выгрузки %s: %v", file.Name, err)
+			msg := tgbotapi.NewMessage(chatID, "❌ Не удалось отправить файл выгрузки")
+			h.bot.Send(msg)
+			return
+		}
+		warnings = append(warnings, file.Warnings...)
+	}
+
+	if len(warnings) > 0 {
+		msg := tgbotapi.NewMessage(chatID, "⚠️ Не все данные попали в выгрузку:\n\n"+strings.Join(warnings, "\n")+
+			"\n\nПри загрузке файла обратно это расписание не изменится. Чтобы править его через файл, переименуйте клиники.")
+		h.bot.Send(msg)
+	}
+
+	InfoLog.Printf("📤 Выгрузка справочника (%s) отправлена в чат %d", format, chatID)
+}
+
 // handleVetManagement обрабатывает меню управления врачами
 func (h *AdminHandlers) handleVetManagement(update tgbotapi.Update, text string) {
 	switch text {
@@ -404,7 +672,7 @@ func (h *AdminHandlers) handleVetManagement(update tgbotapi.Update, text string)
 // handleVetSearchByCity обрабатывает поиск врачей по городу
 func (h *AdminHandlers) handleVetSearchByCity(update tgbotapi.Update) {
 	userID := update.Message.From.ID
-	h.adminState[userID] = "vet_search_city"
+	h.stateManager.SetAdminState(userID, "vet_search_city")
 
 	cities, err := h.db.GetAllCities()
 	if err != nil {
@@ -439,8 +707,7 @@ func (h *AdminHandlers) handleVetSearchByCity(update tgbotapi.Update) {
 	msg.ReplyMarkup = keyboard
 
 	// Сохраняем список городов во временные данные
-	userIDStr := strconv.FormatInt(userID, 10)
-	h.tempData[userIDStr+"_cities"] = cities
+	h.stateManager.SetAdminData(userID, "cities", cities)
 
 	h.bot.Send(msg)
 }
@@ -464,7 +731,7 @@ func (h *AdminHandlers) handleClinicManagement(update tgbotapi.Update, text stri
 // showVetManagement показывает меню управления врачами
 func (h *AdminHandlers) showVetManagement(update tgbotapi.Update) {
 	userID := update.Message.From.ID
-	h.adminState[userID] = "vet_management"
+	h.stateManager.SetAdminState(userID, "vet_management")
 
 	// Получаем статистику врачей
 	activeVets, _ := h.db.GetActiveVetCount()
@@ -492,7 +759,7 @@ func (h *AdminHandlers) showVetManagement(update tgbotapi.Update) {
 // showClinicManagement показывает меню управления клиниками
 func (h *AdminHandlers) showClinicManagement(update tgbotapi.Update) {
 	userID := update.Message.From.ID
-	h.adminState[userID] = "clinic_management"
+	h.stateManager.SetAdminState(userID, "clinic_management")
 
 	// Получаем статистику клиник
 	activeClinics, _ := h.getActiveClinicCount()
@@ -519,7 +786,7 @@ func (h *AdminHandlers) showClinicManagement(update tgbotapi.Update) {
 // startAddVet начинает процесс добавления врача
 func (h *AdminHandlers) startAddVet(update tgbotapi.Update) {
 	userID := update.Message.From.ID
-	h.adminState[userID] = "add_vet_name"
+	h.stateManager.SetAdminState(userID, "add_vet_name")
 
 	keyboard := tgbotapi.NewReplyKeyboard(
 		tgbotapi.NewKeyboardButtonRow(
@@ -545,11 +812,10 @@ func (h *AdminHandlers) handleAddVetName(update tgbotapi.Update, name string) {
 	}
 
 	userID := update.Message.From.ID
-	h.adminState[userID] = "add_vet_phone"
+	h.stateManager.SetAdminState(userID, "add_vet_phone")
 
 	// Сохраняем имя во временное хранилище
-	userIDStr := strconv.FormatInt(userID, 10)
-	h.tempData[userIDStr+"_name"] = name
+	h.stateManager.SetAdminData(userID, "name", name)
 
 	keyboard := tgbotapi.NewReplyKeyboard(
 		tgbotapi.NewKeyboardButtonRow(
@@ -574,11 +840,10 @@ func (h *AdminHandlers) handleAddVetPhone(update tgbotapi.Update, phone string)
 	}
 
 	userID := update.Message.From.ID
-	h.adminState[userID] = "add_vet_specializations"
+	h.stateManager.SetAdminState(userID, "add_vet_specializations")
 
 	// Сохраняем телефон
-	userIDStr := strconv.FormatInt(userID, 10)
-	h.tempData[userIDStr+"_phone"] = phone
+	h.stateManager.SetAdminData(userID, "phone", phone)
 
 	// Получаем список специализаций для выбора
 	specializations, err := h.db.GetAllSpecializations()
@@ -622,7 +887,7 @@ func (h *AdminHandlers) handleCancelAddVet(update tgbotapi.Update) {
 	h.cleanTempData(userID)
 
 	// Возвращаем в меню управления врачами
-	h.adminState[userID] = "vet_management"
+	h.stateManager.SetAdminState(userID, "vet_management")
 
 	msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Добавление врача отменено")
 	h.bot.Send(msg)
@@ -635,9 +900,8 @@ func (h *AdminHandlers) handleAddVetSpecializations(update tgbotapi.Update, spec
 	userID := update.Message.From.ID
 
 	// Получаем сохраненные данные
-	userIDStr := strconv.FormatInt(userID, 10)
-	name := h.getStringTempData(userIDStr + "_name")
-	phone := h.getStringTempData(userIDStr + "_phone")
+	name := h.getStringTempData(userID, "name")
+	phone := h.getStringTempData(userID, "phone")
 
 	if name == "" || phone == "" {
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID,
@@ -679,14 +943,14 @@ func (h *AdminHandlers) handleAddVetSpecializations(update tgbotapi.Update, spec
 	h.cleanTempData(userID)
 
 	// Возвращаем в меню управления врачами
-	h.adminState[userID] = "vet_management"
+	h.stateManager.SetAdminState(userID, "vet_management")
 	h.showVetManagement(update)
 }
 
 // showVetList показывает список врачей с возможностью выбора
 func (h *AdminHandlers) showVetList(update tgbotapi.Update) {
 	userID := update.Message.From.ID
-	h.adminState[userID] = "vet_list"
+	h.stateManager.SetAdminState(userID, "vet_list")
 
 	InfoLog.Printf("🔄 Запрос списка врачей от пользователя %d", userID)
 
@@ -802,13 +1066,12 @@ func (h *AdminHandlers) showVetList(update tgbotapi.Update) {
 // showVetEditMenu показывает меню редактирования врача
 func (h *AdminHandlers) showVetEditMenu(update tgbotapi.Update, vet *models.Veterinarian) {
 	userID := update.Message.From.ID
-	h.adminState[userID] = "vet_edit_menu"
+	h.stateManager.SetAdminState(userID, "vet_edit_menu")
 
 	// Сохраняем ID врача во временные данные
-	userIDStr := strconv.FormatInt(userID, 10)
-	h.tempData[userIDStr+"_vet_edit"] = &models.VetEditData{
+	h.stateManager.SetAdminData(userID, "vet_edit", &models.VetEditData{
 		VetID: models.GetVetIDAsIntOrZero(vet),
-	}
+	})
 
 	// Получаем специализации врача
 	specs, err := h.db.GetSpecializationsByVetID(models.GetVetIDAsIntOrZero(vet))
@@ -907,6 +1170,13 @@ func (h *AdminHandlers) showVetEditMenu(update tgbotapi.Update, vet *models.Vete
 			tgbotapi.NewKeyboardButton("💼 Редактировать опыт"),
 			tgbotapi.NewKeyboardButton("🏙️ Изменить город"),
 		),
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton("📅 Расписание приемов"),
+			tgbotapi.NewKeyboardButton("🏖️ Исключения в расписании"),
+		),
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton("🏥 Клиники врача"),
+		),
 		tgbotapi.NewKeyboardButtonRow(
 			tgbotapi.NewKeyboardButton("⚡ Изменить статус"),
 			tgbotapi.NewKeyboardButton("🗑️ Удалить врача"),
@@ -927,13 +1197,16 @@ func (h *AdminHandlers) showVetEditMenu(update tgbotapi.Update, vet *models.Vete
 func (h *AdminHandlers) getMissingRequiredFields(vet *models.Veterinarian) []string {
 	var missing []string
 
-	// Проверяем только имя и фамилию (телефон уже заполнен)
+	// Обязательные поля: имя, фамилия, телефон (при импорте телефон может остаться пустым)
 	if strings.TrimSpace(vet.FirstName) == "" {
 		missing = append(missing, "Имя")
 	}
 	if strings.TrimSpace(vet.LastName) == "" {
 		missing = append(missing, "Фамилия")
 	}
+	if strings.TrimSpace(vet.Phone) == "" {
+		missing = append(missing, "Телефон")
+	}
 
 	return missing
 }
@@ -941,9 +1214,8 @@ func (h *AdminHandlers) getMissingRequiredFields(vet *models.Veterinarian) []str
 // handleVetEditMenu обрабатывает выбор действия для врача
 func (h *AdminHandlers) handleVetEditMenu(update tgbotapi.Update, text string) {
 	userID := update.Message.From.ID
-	userIDStr := strconv.FormatInt(userID, 10)
 
-	vetData, ok := h.tempData[userIDStr+"_vet_edit"].(*models.VetEditData)
+	vetData, ok := h.stateManager.GetAdminData(userID, "vet_edit").(*models.VetEditData)
 	if !ok || vetData == nil {
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Ошибка: данные врача не найдены")
 		h.bot.Send(msg)
@@ -962,55 +1234,55 @@ func (h *AdminHandlers) handleVetEditMenu(update tgbotapi.Update, text string) {
 
 	switch text {
 	case "✏️ Редактировать имя":
-		h.adminState[userID] = "vet_edit_field"
 		vetData.Field = "first_name"
 		vetData.CurrentValue = vet.FirstName
+		h.stateManager.SetAdminState(userID, "vet_edit_field")
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Введите новое имя врача:")
 		h.bot.Send(msg)
 
 	case "👤 Редактировать фамилию":
-		h.adminState[userID] = "vet_edit_field"
 		vetData.Field = "last_name"
 		vetData.CurrentValue = vet.LastName
+		h.stateManager.SetAdminState(userID, "vet_edit_field")
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Введите новую фамилию врача:")
 		h.bot.Send(msg)
 
 	case "👤 Редактировать отчество": // НОВЫЙ CASE
-		h.adminState[userID] = "vet_edit_field"
 		vetData.Field = "patronymic"
 		if vet.Patronymic.Valid {
 			vetData.CurrentValue = vet.Patronymic.String
 		}
+		h.stateManager.SetAdminState(userID, "vet_edit_field")
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Введите отчество врача (или '-' для очистки):")
 		h.bot.Send(msg)
 
 	case "📞 Редактировать телефон":
-		h.adminState[userID] = "vet_edit_field"
 		vetData.Field = "phone"
 		vetData.CurrentValue = vet.Phone
+		h.stateManager.SetAdminState(userID, "vet_edit_field")
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Введите новый телефон врача:")
 		h.bot.Send(msg)
 
 	case "📧 Редактировать email":
-		h.adminState[userID] = "vet_edit_field"
 		vetData.Field = "email"
 		if vet.Email.Valid {
 			vetData.CurrentValue = vet.Email.String
 		}
+		h.stateManager.SetAdminState(userID, "vet_edit_field")
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Введите новый email врача (или '-' для очистки):")
 		h.bot.Send(msg)
 
 	case "💼 Редактировать опыт":
-		h.adminState[userID] = "vet_edit_field"
 		vetData.Field = "experience_years"
 		if vet.ExperienceYears.Valid {
 			vetData.CurrentValue = strconv.FormatInt(vet.ExperienceYears.Int64, 10)
 		}
+		h.stateManager.SetAdminState(userID, "vet_edit_field")
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Введите новый опыт работы в годах (или '-' для очистки):")
 		h.bot.Send(msg)
 
 	case "🎯 Редактировать специализации":
-		h.adminState[userID] = "vet_edit_specializations"
+		h.stateManager.SetAdminState(userID, "vet_edit_specializations")
 		specs, err := h.db.GetSpecializationsByVetID(models.GetVetIDAsIntOrZero(vet))
 		if err == nil && len(specs) > 0 {
 			var specIDs []string
@@ -1018,6 +1290,7 @@ func (h *AdminHandlers) handleVetEditMenu(update tgbotapi.Update, text string) {
 				specIDs = append(specIDs, strconv.Itoa(spec.ID))
 			}
 			vetData.Specializations = strings.Join(specIDs, ",")
+			h.stateManager.SetAdminData(userID, "vet_edit", vetData)
 		}
 
 		// Показываем список специализаций
@@ -1049,8 +1322,17 @@ func (h *AdminHandlers) handleVetEditMenu(update tgbotapi.Update, text string) {
 	case "🏙️ Изменить город":
 		h.startChangeVetCity(update, vet)
 
+	case "📅 Расписание приемов":
+		h.showVetSchedules(update, vetData.VetID)
+
+	case "🏖️ Исключения в расписании":
+		h.showVetExceptions(update, vetData.VetID)
+
+	case "🏥 Клиники врача":
+		h.showVetClinics(update, vetData.VetID)
+
 	case "⚡ Изменить статус":
-		h.adminState[userID] = "vet_toggle_active"
+		h.stateManager.SetAdminState(userID, "vet_toggle_active")
 		newStatus := !vet.IsActive
 		statusText := "активен"
 		if !newStatus {
@@ -1070,7 +1352,7 @@ func (h *AdminHandlers) handleVetEditMenu(update tgbotapi.Update, text string) {
 		h.bot.Send(msg)
 
 	case "🗑️ Удалить врача":
-		h.adminState[userID] = "vet_confirm_delete"
+		h.stateManager.SetAdminState(userID, "vet_confirm_delete")
 		keyboard := tgbotapi.NewReplyKeyboard(
 			tgbotapi.NewKeyboardButtonRow(
 				tgbotapi.NewKeyboardButton("✅ Подтвердить удаление"),
@@ -1096,9 +1378,8 @@ func (h *AdminHandlers) handleVetEditMenu(update tgbotapi.Update, text string) {
 // handleVetEditField обрабатывает ввод нового значения для поля врача
 func (h *AdminHandlers) handleVetEditField(update tgbotapi.Update, text string) {
 	userID := update.Message.From.ID
-	userIDStr := strconv.FormatInt(userID, 10)
 
-	vetData, ok := h.tempData[userIDStr+"_vet_edit"].(*models.VetEditData)
+	vetData, ok := h.stateManager.GetAdminData(userID, "vet_edit").(*models.VetEditData)
 	if !ok || vetData == nil {
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Ошибка: данные врача не найдены")
 		h.bot.Send(msg)
@@ -1190,9 +1471,8 @@ func (h *AdminHandlers) updateVeterinarianField(vetID int, field string, value s
 // handleVetEditSpecializations обрабатывает ввод специализаций врача
 func (h *AdminHandlers) handleVetEditSpecializations(update tgbotapi.Update, text string) {
 	userID := update.Message.From.ID
-	userIDStr := strconv.FormatInt(userID, 10)
 
-	vetData, ok := h.tempData[userIDStr+"_vet_edit"].(*models.VetEditData)
+	vetData, ok := h.stateManager.GetAdminData(userID, "vet_edit").(*models.VetEditData)
 	if !ok || vetData == nil {
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Ошибка: данные врача не найдены")
 		h.bot.Send(msg)
@@ -1231,9 +1511,8 @@ func (h *AdminHandlers) handleVetEditSpecializations(update tgbotapi.Update, tex
 // handleVetConfirmDelete обрабатывает подтверждение удаления врача
 func (h *AdminHandlers) handleVetConfirmDelete(update tgbotapi.Update, text string) {
 	userID := update.Message.From.ID
-	userIDStr := strconv.FormatInt(userID, 10)
 
-	vetData, ok := h.tempData[userIDStr+"_vet_edit"].(*models.VetEditData)
+	vetData, ok := h.stateManager.GetAdminData(userID, "vet_edit").(*models.VetEditData)
 	if !ok || vetData == nil {
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Ошибка: данные врача не найдены")
 		h.bot.Send(msg)
@@ -1263,9 +1542,8 @@ func (h *AdminHandlers) handleVetConfirmDelete(update tgbotapi.Update, text stri
 // handleVetToggleActive обрабатывает изменение статуса врача
 func (h *AdminHandlers) handleVetToggleActive(update tgbotapi.Update, text string) {
 	userID := update.Message.From.ID
-	userIDStr := strconv.FormatInt(userID, 10)
 
-	vetData, ok := h.tempData[userIDStr+"_vet_edit"].(*models.VetEditData)
+	vetData, ok := h.stateManager.GetAdminData(userID, "vet_edit").(*models.VetEditData)
 	if !ok || vetData == nil {
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Ошибка: данные врача не найдены")
 		h.bot.Send(msg)
@@ -1316,7 +1594,7 @@ func (h *AdminHandlers) handleVetToggleActive(update tgbotapi.Update, text strin
 // showClinicList показывает список клиник с возможностью выбора
 func (h *AdminHandlers) showClinicList(update tgbotapi.Update) {
 	userID := update.Message.From.ID
-	h.adminState[userID] = "clinic_list"
+	h.stateManager.SetAdminState(userID, "clinic_list")
 
 	clinics, err := h.db.GetAllClinics()
 	if err != nil {
@@ -1387,13 +1665,12 @@ func (h *AdminHandlers) handleClinicListSelection(update tgbotapi.Update, text s
 // showClinicEditMenu показывает меню редактирования клиники
 func (h *AdminHandlers) showClinicEditMenu(update tgbotapi.Update, clinic *models.Clinic) {
 	userID := update.Message.From.ID
-	h.adminState[userID] = "clinic_edit_menu"
+	h.stateManager.SetAdminState(userID, "clinic_edit_menu")
 
 	// Сохраняем ID клиники во временные данные
-	userIDStr := strconv.FormatInt(userID, 10)
-	h.tempData[userIDStr+"_clinic_edit"] = &models.ClinicEditData{
+	h.stateManager.SetAdminData(userID, "clinic_edit", &models.ClinicEditData{
 		ClinicID: clinic.ID,
-	}
+	})
 
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("🏥 *Управление клиникой:* %s\n\n", clinic.Name))
@@ -1407,6 +1684,12 @@ func (h *AdminHandlers) showClinicEditMenu(update tgbotapi.Update, clinic *model
 		sb.WriteString(fmt.Sprintf("🕐 Часы работы: %s\n", clinic.WorkingHours.String))
 	}
 
+	if clinic.HasCoordinates() {
+		sb.WriteString(fmt.Sprintf("📌 Координаты: %.6f, %.6f\n", clinic.Latitude.Float64, clinic.Longitude.Float64))
+	} else {
+		sb.WriteString("📌 Координаты: не указаны\n")
+	}
+
 	sb.WriteString("📊 Статус: ")
 	if clinic.IsActive {
 		sb.WriteString("✅ Активна\n")
@@ -1414,6 +1697,8 @@ func (h *AdminHandlers) showClinicEditMenu(update tgbotapi.Update, clinic *model
 		sb.WriteString("❌ Неактивна\n")
 	}
 
+	sb.WriteString(fmt.Sprintf("👨‍⚕️ Врачи: %s\n", h.clinicVetsRoster(clinic.ID)))
+
 	sb.WriteString("\nВыберите действие:")
 
 	keyboard := tgbotapi.NewReplyKeyboard(
@@ -1425,6 +1710,10 @@ func (h *AdminHandlers) showClinicEditMenu(update tgbotapi.Update, clinic *model
 			tgbotapi.NewKeyboardButton("📞 Редактировать телефон"),
 			tgbotapi.NewKeyboardButton("🕐 Редактировать часы работы"),
 		),
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton("📌 Координаты"),
+			tgbotapi.NewKeyboardButton("👨‍⚕️ Врачи клиники"),
+		),
 		tgbotapi.NewKeyboardButtonRow(
 			tgbotapi.NewKeyboardButton("⚡ Изменить статус"),
 			tgbotapi.NewKeyboardButton("🗑️ Удалить клинику"),
@@ -1444,9 +1733,8 @@ func (h *AdminHandlers) showClinicEditMenu(update tgbotapi.Update, clinic *model
 // handleClinicEditMenu обрабатывает выбор действия для клиники
 func (h *AdminHandlers) handleClinicEditMenu(update tgbotapi.Update, text string) {
 	userID := update.Message.From.ID
-	userIDStr := strconv.FormatInt(userID, 10)
 
-	clinicData, ok := h.tempData[userIDStr+"_clinic_edit"].(*models.ClinicEditData)
+	clinicData, ok := h.stateManager.GetAdminData(userID, "clinic_edit").(*models.ClinicEditData)
 	if !ok || clinicData == nil {
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Ошибка: данные клиники не найдены")
 		h.bot.Send(msg)
@@ -1465,39 +1753,55 @@ func (h *AdminHandlers) handleClinicEditMenu(update tgbotapi.Update, text string
 
 	switch text {
 	case "✏️ Редактировать название":
-		h.adminState[userID] = "clinic_edit_field"
 		clinicData.Field = "name"
 		clinicData.CurrentValue = clinic.Name
+		h.stateManager.SetAdminState(userID, "clinic_edit_field")
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Введите новое название клиники:")
 		h.bot.Send(msg)
 
 	case "📍 Редактировать адрес":
-		h.adminState[userID] = "clinic_edit_field"
 		clinicData.Field = "address"
 		clinicData.CurrentValue = clinic.Address
+		h.stateManager.SetAdminState(userID, "clinic_edit_field")
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Введите новый адрес клиники:")
 		h.bot.Send(msg)
 
 	case "📞 Редактировать телефон":
-		h.adminState[userID] = "clinic_edit_field"
 		clinicData.Field = "phone"
 		if clinic.Phone.Valid {
 			clinicData.CurrentValue = clinic.Phone.String
 		}
+		h.stateManager.SetAdminState(userID, "clinic_edit_field")
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Введите новый телефон клиники (или '-' для очистки):")
 		h.bot.Send(msg)
 
 	case "🕐 Редактировать часы работы":
-		h.adminState[userID] = "clinic_edit_field"
 		clinicData.Field = "working_hours"
 		if clinic.WorkingHours.Valid {
 			clinicData.CurrentValue = clinic.WorkingHours.String
 		}
+		h.stateManager.SetAdminState(userID, "clinic_edit_field")
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Введите новые часы работы клиники (или '-' для очистки):")
 		h.bot.Send(msg)
 
+	case "📌 Координаты":
+		clinicData.Field = "coordinates"
+		if clinic.HasCoordinates() {
+			clinicData.CurrentValue = fmt.Sprintf("%.6f, %.6f", clinic.Latitude.Float64, clinic.Longitude.Float64)
+		}
+		h.stateManager.SetAdminState(userID, "clinic_edit_field")
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID,
+			"Отправьте геопозицию клиники (📎 → «Геопозиция») или введите координаты в формате «широта, долгота», "+
+				"например 55.7558, 37.6173 (или '-' для очистки):")
+		msg.ReplyMarkup = tgbotapi.NewReplyKeyboard(
+			tgbotapi.NewKeyboardButtonRow(
+				tgbotapi.NewKeyboardButton("❌ Отмена"),
+			),
+		)
+		h.bot.Send(msg)
+
 	case "⚡ Изменить статус":
-		h.adminState[userID] = "clinic_toggle_active"
+		h.stateManager.SetAdminState(userID, "clinic_toggle_active")
 		newStatus := !clinic.IsActive
 		statusText := "активна"
 		if !newStatus {
@@ -1516,8 +1820,11 @@ func (h *AdminHandlers) handleClinicEditMenu(update tgbotapi.Update, text string
 		msg.ReplyMarkup = keyboard
 		h.bot.Send(msg)
 
+	case "👨‍⚕️ Врачи клиники":
+		h.showClinicVets(update, clinic.ID)
+
 	case "🗑️ Удалить клинику":
-		h.adminState[userID] = "clinic_confirm_delete"
+		h.stateManager.SetAdminState(userID, "clinic_confirm_delete")
 		keyboard := tgbotapi.NewReplyKeyboard(
 			tgbotapi.NewKeyboardButtonRow(
 				tgbotapi.NewKeyboardButton("✅ Подтвердить удаление"),
@@ -1543,9 +1850,8 @@ func (h *AdminHandlers) handleClinicEditMenu(update tgbotapi.Update, text string
 // handleClinicEditField обрабатывает ввод нового значения для поля клиники
 func (h *AdminHandlers) handleClinicEditField(update tgbotapi.Update, text string) {
 	userID := update.Message.From.ID
-	userIDStr := strconv.FormatInt(userID, 10)
 
-	clinicData, ok := h.tempData[userIDStr+"_clinic_edit"].(*models.ClinicEditData)
+	clinicData, ok := h.stateManager.GetAdminData(userID, "clinic_edit").(*models.ClinicEditData)
 	if !ok || clinicData == nil {
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Ошибка: данные клиники не найдены")
 		h.bot.Send(msg)
@@ -1553,6 +1859,11 @@ func (h *AdminHandlers) handleClinicEditField(update tgbotapi.Update, text strin
 		return
 	}
 
+	if clinicData.Field == "coordinates" {
+		h.handleClinicCoordinatesInput(update, clinicData, text)
+		return
+	}
+
 	// Обработка специальных значений
 	if text == "-" {
 		text = "" // Очистка поля
@@ -1578,12 +1889,56 @@ func (h *AdminHandlers) handleClinicEditField(update tgbotapi.Update, text strin
 	}
 }
 
+// handleClinicCoordinatesInput задает координаты клиники из геопозиции или текста «широта, долгота»
+func (h *AdminHandlers) handleClinicCoordinatesInput(update tgbotapi.Update, clinicData *models.ClinicEditData, text string) {
+	var latitude, longitude sql.NullFloat64
+
+	switch {
+	case update.Message.Location != nil:
+		latitude = sql.NullFloat64{Float64: update.Message.Location.Latitude, Valid: true}
+		longitude = sql.NullFloat64{Float64: update.Message.Location.Longitude, Valid: true}
+	case strings.TrimSpace(text) == "-":
+		// Очистка координат
+	default:
+		lat, lon, err := models.ParseCoordinates(text)
+		if err != nil {
+			msg := tgbotapi.NewMessage(update.Message.Chat.ID,
+				fmt.Sprintf("❌ %v\n\nОтправьте геопозицию или введите координаты еще раз:", err))
+			h.bot.Send(msg)
+			return
+		}
+		latitude = sql.NullFloat64{Float64: lat, Valid: true}
+		longitude = sql.NullFloat64{Float64: lon, Valid: true}
+	}
+
+	err := h.db.UpdateClinicCoordinates(clinicData.ClinicID, latitude, longitude)
+	if err != nil {
+		ErrorLog.Printf("Error updating clinic %d coordinates: %v", clinicData.ClinicID, err)
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID,
+			fmt.Sprintf("Ошибка при обновлении данных: %v", err))
+		h.bot.Send(msg)
+	} else if latitude.Valid {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID,
+			fmt.Sprintf("✅ Координаты обновлены: %.6f, %.6f", latitude.Float64, longitude.Float64))
+		h.bot.Send(msg)
+	} else {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "✅ Координаты очищены")
+		h.bot.Send(msg)
+	}
+
+	clinic, err := h.db.GetClinicByID(clinicData.ClinicID)
+	if err == nil {
+		h.showClinicEditMenu(update, clinic)
+	} else {
+		h.showClinicList(update)
+	}
+}
+
 // handleClinicConfirmDelete обрабатывает подтверждение удаления клиники
 func (h *AdminHandlers) handleClinicConfirmDelete(update tgbotapi.Update, text string) {
 	userID := update.Message.From.ID
-	userIDStr := strconv.FormatInt(userID, 10)
 
-	clinicData, ok := h.tempData[userIDStr+"_clinic_edit"].(*models.ClinicEditData)
+	clinicData, ok := h.stateManager.GetAdminData(userID, "clinic_edit").(*models.ClinicEditData)
 	if !ok || clinicData == nil {
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Ошибка: данные клиники не найдены")
 		h.bot.Send(msg)
@@ -1613,9 +1968,8 @@ func (h *AdminHandlers) handleClinicConfirmDelete(update tgbotapi.Update, text s
 // handleClinicToggleActive обрабатывает изменение статуса клиники
 func (h *AdminHandlers) handleClinicToggleActive(update tgbotapi.Update, text string) {
 	userID := update.Message.From.ID
-	userIDStr := strconv.FormatInt(userID, 10)
 
-	clinicData, ok := h.tempData[userIDStr+"_clinic_edit"].(*models.ClinicEditData)
+	clinicData, ok := h.stateManager.GetAdminData(userID, "clinic_edit").(*models.ClinicEditData)
 	if !ok || clinicData == nil {
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Ошибка: данные клиники не найдены")
 		h.bot.Send(msg)
@@ -1721,17 +2075,13 @@ func (h *AdminHandlers) closeAdmin(update tgbotapi.Update) {
 
 	InfoLog.Printf("🔄 Closing admin panel for user %d", userID)
 
-	// Очищаем все временные данные пользователя
-	h.cleanTempData(userID)
+	// Очищаем админ-состояние и временные данные
+	h.discardPendingImport(userID)
+	h.stateManager.ClearAdminSession(userID)
 
-	// Очищаем админ-состояние
-	delete(h.adminState, userID)
-
-	// Очищаем состояние в stateManager
-	if h.stateManager != nil {
-		h.stateManager.ClearUserState(userID)
-		h.stateManager.ClearUserData(userID)
-	}
+	// Очищаем пользовательское состояние
+	h.stateManager.ClearUserState(userID)
+	h.stateManager.ClearUserData(userID)
 
 	// Используем существующую функцию для показа главного меню
 	h.showUserMainMenu(update.Message.Chat.ID)
@@ -1752,6 +2102,11 @@ func (h *AdminHandlers) showUserMainMenu(chatID int64) {
 			tgbotapi.NewKeyboardButton("ℹ️ Помощь"),
 			tgbotapi.NewKeyboardButton("🔙 Назад"),
 		),
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton("⭐ Избранное"),
+			tgbotapi.NewKeyboardButton("📋 Мои записи"),
+			tgbotapi.NewKeyboardButtonLocation(nearestButtonText),
+		),
 	)
 
 	msg := tgbotapi.NewMessage(chatID,
@@ -1779,21 +2134,14 @@ func (h *AdminHandlers) isValidSpecializationIDs(input string) bool {
 		return true // Пустая строка допустима (очистка специализаций)
 	}
 
-	// Получаем максимальный ID специализации для проверки
-	maxID, err := h.getMaxSpecializationID()
-	if err != nil {
-		ErrorLog.Printf("Error getting max specialization ID: %v", err)
-		return false
-	}
-
 	ids := strings.Split(input, ",")
 	for _, idStr := range ids {
 		id, err := strconv.Atoi(strings.TrimSpace(idStr))
-		if err != nil || id <= 0 || id > maxID {
+		if err != nil || id <= 0 {
 			return false
 		}
 
-		// Дополнительная проверка существования специализации в БД
+		// Проверяем существование специализации в БД
 		exists, err := h.db.SpecializationExists(id)
 		if err != nil || !exists {
 			return false
@@ -1802,13 +2150,6 @@ func (h *AdminHandlers) isValidSpecializationIDs(input string) bool {
 	return true
 }
 
-// getMaxSpecializationID возвращает максимальный ID специализации
-func (h *AdminHandlers) getMaxSpecializationID() (int, error) {
-	var maxID int
-	err := h.db.GetDB().QueryRow("SELECT COALESCE(MAX(id), 0) FROM specializations").Scan(&maxID)
-	return maxID, err
-}
-
 // addVeterinarian добавляет врача в базу данных
 func (h *AdminHandlers) addVeterinarian(vet *models.Veterinarian, specsText string) error {
 	// Добавляем врача в базу через метод базы данных
@@ -1856,53 +2197,26 @@ func (h *AdminHandlers) addVeterinarian(vet *models.Veterinarian, specsText stri
 
 // updateVeterinarianSpecializations обновляет специализации врача
 func (h *AdminHandlers) updateVeterinarianSpecializations(vetID int, specsText string) error {
-	// Удаляем все текущие специализации врача
-	_, err := h.db.GetDB().Exec("DELETE FROM vet_specializations WHERE vet_id = $1", vetID)
-	if err != nil {
-		return err
-	}
-
-	// Добавляем новые специализации, если они указаны
+	// Несуществующие и некорректные ID пропускаем, как и раньше
+	var specIDs []int
 	if specsText != "" {
-		specIDs := strings.Split(specsText, ",")
-		for _, specIDStr := range specIDs {
+		for _, specIDStr := range strings.Split(specsText, ",") {
 			specID, err := strconv.Atoi(strings.TrimSpace(specIDStr))
-			if err == nil && specID > 0 {
-				// Проверяем существование специализации
-				exists, err := h.db.SpecializationExists(specID)
-				if err == nil && exists {
-					_, err = h.db.GetDB().Exec(
-						"INSERT INTO vet_specializations (vet_id, specialization_id) VALUES ($1, $2)",
-						vetID, specID,
-					)
-					if err != nil {
-						ErrorLog.Printf("Error adding specialization %d: %v", specID, err)
-					}
-				}
+			if err != nil || specID <= 0 {
+				continue
+			}
+			if exists, err := h.db.SpecializationExists(specID); err == nil && exists {
+				specIDs = append(specIDs, specID)
 			}
 		}
 	}
 
-	return nil
+	return h.db.SetVeterinarianSpecializations(vetID, specIDs)
 }
 
-// deleteVeterinarian удаляет врача из базы данных
+// deleteVeterinarian удаляет врача вместе с его специализациями и расписанием
 func (h *AdminHandlers) deleteVeterinarian(vetID int) error {
-	// Удаляем связи с специализациями
-	_, err := h.db.GetDB().Exec("DELETE FROM vet_specializations WHERE vet_id = $1", vetID)
-	if err != nil {
-		return err
-	}
-
-	// Удаляем расписание врача
-	_, err = h.db.GetDB().Exec("DELETE FROM schedules WHERE vet_id = $1", vetID)
-	if err != nil {
-		return err
-	}
-
-	// Удаляем врача
-	_, err = h.db.GetDB().Exec("DELETE FROM veterinarians WHERE id = $1", vetID)
-	return err
+	return h.db.DeleteVeterinarian(vetID)
 }
 
 // updateClinicField обновляет поле клиники в базе данных
@@ -1949,25 +2263,14 @@ func (h *AdminHandlers) updateClinicField(clinicID int, field string, value stri
 
 // deleteClinic удаляет клинику из базы данных
 func (h *AdminHandlers) deleteClinic(clinicID int) error {
-	// Удаляем расписание, связанное с клиникой
-	_, err := h.db.GetDB().Exec("DELETE FROM schedules WHERE clinic_id = $1", clinicID)
-	if err != nil {
-		return err
-	}
-
-	// Удаляем клинику
-	_, err = h.db.GetDB().Exec("DELETE FROM clinics WHERE id = $1", clinicID)
-	return err
+	// Расписание клиники удаляется вместе с ней
+	return h.db.DeleteClinic(clinicID)
 }
 
-// getStringTempData получает строковые данные из временного хранилища
-func (h *AdminHandlers) getStringTempData(key string) string {
-	if value, exists := h.tempData[key]; exists {
-		if str, ok := value.(string); ok {
-			return str
-		}
-	}
-	return ""
+// getStringTempData получает строковые данные админ-панели пользователя
+func (h *AdminHandlers) getStringTempData(userID int64, key string) string {
+	str, _ := h.stateManager.GetAdminData(userID, key).(string)
+	return str
 }
 
 // ========== МЕТОДЫ ДЛЯ СТАТИСТИКИ ==========
@@ -2019,7 +2322,7 @@ func (h *AdminHandlers) getRequestCount() (int, error) {
 // showCityManagement показывает меню управления городами
 func (h *AdminHandlers) showCityManagement(update tgbotapi.Update) {
 	userID := update.Message.From.ID
-	h.adminState[userID] = "city_management"
+	h.stateManager.SetAdminState(userID, "city_management")
 
 	// Получаем статистику городов
 	citiesCount, _ := h.getCitiesCount()
@@ -2063,7 +2366,7 @@ func (h *AdminHandlers) handleCityManagement(update tgbotapi.Update, text string
 // startAddCity начинает процесс добавления города
 func (h *AdminHandlers) startAddCity(update tgbotapi.Update) {
 	userID := update.Message.From.ID
-	h.adminState[userID] = "add_city_name"
+	h.stateManager.SetAdminState(userID, "add_city_name")
 
 	removeKeyboard := tgbotapi.NewRemoveKeyboard(true)
 	msg := tgbotapi.NewMessage(update.Message.Chat.ID,
@@ -2088,13 +2391,12 @@ func (h *AdminHandlers) handleAddCityName(update tgbotapi.Update, name string) {
 		return
 	}
 
-	h.adminState[userID] = "add_city_region"
+	h.stateManager.SetAdminState(userID, "add_city_region")
 
 	// Сохраняем название города во временные данные
-	userIDStr := strconv.FormatInt(userID, 10)
-	h.tempData[userIDStr+"_new_city"] = &models.City{
+	h.stateManager.SetAdminData(userID, "new_city", &models.City{
 		Name: strings.TrimSpace(name),
-	}
+	})
 
 	msg := tgbotapi.NewMessage(update.Message.Chat.ID,
 		"🏙️ Теперь введите регион (область) для города *"+name+"*:")
@@ -2105,17 +2407,15 @@ func (h *AdminHandlers) handleAddCityName(update tgbotapi.Update, name string) {
 // handleAddCityRegion обрабатывает ввод региона города
 func (h *AdminHandlers) handleAddCityRegion(update tgbotapi.Update, region string) {
 	userID := update.Message.From.ID
-	userIDStr := strconv.FormatInt(userID, 10)
 
 	// Получаем город из временных данных
-	cityInterface, exists := h.tempData[userIDStr+"_new_city"]
-	if !exists {
+	city, ok := h.stateManager.GetAdminData(userID, "new_city").(*models.City)
+	if !ok {
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Ошибка: данные о городе не найдены")
 		h.bot.Send(msg)
 		return
 	}
 
-	city := cityInterface.(*models.City)
 	city.Region = strings.TrimSpace(region)
 
 	// Сохраняем город в базу
@@ -2128,7 +2428,7 @@ func (h *AdminHandlers) handleAddCityRegion(update tgbotapi.Update, region strin
 	}
 
 	// Очищаем временные данные
-	delete(h.tempData, userIDStr+"_new_city")
+	h.stateManager.ClearAdminDataByKey(userID, "new_city")
 
 	// Показываем клавиатуру управления городами
 	h.showCityManagement(update)
@@ -2142,7 +2442,7 @@ func (h *AdminHandlers) handleAddCityRegion(update tgbotapi.Update, region strin
 // showCityList показывает список всех городов
 func (h *AdminHandlers) showCityList(update tgbotapi.Update) {
 	userID := update.Message.From.ID
-	h.adminState[userID] = "city_list"
+	h.stateManager.SetAdminState(userID, "city_list")
 
 	cities, err := h.db.GetAllCities()
 	if err != nil {
@@ -2184,8 +2484,7 @@ func (h *AdminHandlers) showCityList(update tgbotapi.Update) {
 	msg.ReplyMarkup = keyboard
 
 	// Сохраняем список городов во временные данные
-	userIDStr := strconv.FormatInt(userID, 10)
-	h.tempData[userIDStr+"_cities"] = cities
+	h.stateManager.SetAdminData(userID, "cities", cities)
 
 	h.bot.Send(msg)
 }
@@ -2193,18 +2492,15 @@ func (h *AdminHandlers) showCityList(update tgbotapi.Update) {
 // handleCityListSelection обрабатывает выбор города из списка
 func (h *AdminHandlers) handleCityListSelection(update tgbotapi.Update, text string) {
 	userID := update.Message.From.ID
-	userIDStr := strconv.FormatInt(userID, 10)
 
 	// Получаем список городов из временных данных
-	citiesInterface, exists := h.tempData[userIDStr+"_cities"]
-	if !exists {
+	cities, ok := h.stateManager.GetAdminData(userID, "cities").([]*models.City)
+	if !ok {
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Ошибка: данные о городах не найдены")
 		h.bot.Send(msg)
 		return
 	}
 
-	cities := citiesInterface.([]*models.City)
-
 	// Парсим номер города
 	cityNum, err := strconv.Atoi(text)
 	if err != nil || cityNum < 1 || cityNum > len(cities) {
@@ -2220,13 +2516,12 @@ func (h *AdminHandlers) handleCityListSelection(update tgbotapi.Update, text str
 // showCityEditMenu показывает меню редактирования города
 func (h *AdminHandlers) showCityEditMenu(update tgbotapi.Update, city *models.City) {
 	userID := update.Message.From.ID
-	h.adminState[userID] = "city_edit_menu"
+	h.stateManager.SetAdminState(userID, "city_edit_menu")
 
 	// Сохраняем ID города во временные данные
-	userIDStr := strconv.FormatInt(userID, 10)
-	h.tempData[userIDStr+"_city_edit"] = &models.CityEditData{
+	h.stateManager.SetAdminData(userID, "city_edit", &models.CityEditData{
 		CityID: city.ID,
-	}
+	})
 
 	// Получаем статистику по городу
 	vetsInCity, _ := h.getVetsCountByCity(city.ID)
@@ -2235,6 +2530,7 @@ func (h *AdminHandlers) showCityEditMenu(update tgbotapi.Update, city *models.Ci
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("🏙️ *Управление городом:* %s\n\n", city.Name))
 	sb.WriteString(fmt.Sprintf("📍 Регион: %s\n", city.Region))
+	sb.WriteString(fmt.Sprintf("🕐 Часовой пояс: %s\n", models.FormatTimezone(city.Timezone, time.Now())))
 	sb.WriteString(fmt.Sprintf("🆔 ID: %d\n", city.ID))
 	sb.WriteString(fmt.Sprintf("📅 Добавлен: %s\n", city.CreatedAt.Format("02.01.2006")))
 	sb.WriteString(fmt.Sprintf("👥 Врачей в городе: %d\n", vetsInCity))
@@ -2246,6 +2542,9 @@ func (h *AdminHandlers) showCityEditMenu(update tgbotapi.Update, city *models.Ci
 			tgbotapi.NewKeyboardButton("✏️ Редактировать название"),
 			tgbotapi.NewKeyboardButton("📍 Редактировать регион"),
 		),
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton("🕐 Часовой пояс"),
+		),
 		tgbotapi.NewKeyboardButtonRow(
 			tgbotapi.NewKeyboardButton("👥 Показать врачей"),
 			tgbotapi.NewKeyboardButton("🏥 Показать клиники"),
@@ -2266,9 +2565,8 @@ func (h *AdminHandlers) showCityEditMenu(update tgbotapi.Update, city *models.Ci
 // handleCityEditMenu обрабатывает выбор действия для города
 func (h *AdminHandlers) handleCityEditMenu(update tgbotapi.Update, text string) {
 	userID := update.Message.From.ID
-	userIDStr := strconv.FormatInt(userID, 10)
 
-	cityData, ok := h.tempData[userIDStr+"_city_edit"].(*models.CityEditData)
+	cityData, ok := h.stateManager.GetAdminData(userID, "city_edit").(*models.CityEditData)
 	if !ok || cityData == nil {
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Ошибка: данные города не найдены")
 		h.bot.Send(msg)
@@ -2287,23 +2585,29 @@ func (h *AdminHandlers) handleCityEditMenu(update tgbotapi.Update, text string)
 
 	switch text {
 	case "✏️ Редактировать название":
-		h.adminState[userID] = "city_edit_name"
 		cityData.Field = "name"
 		cityData.CurrentValue = city.Name
+		h.stateManager.SetAdminState(userID, "city_edit_name")
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID,
 			fmt.Sprintf("Введите новое название для города *%s*:", city.Name))
 		msg.ParseMode = "Markdown"
 		h.bot.Send(msg)
 
 	case "📍 Редактировать регион":
-		h.adminState[userID] = "city_edit_region"
 		cityData.Field = "region"
 		cityData.CurrentValue = city.Region
+		h.stateManager.SetAdminState(userID, "city_edit_region")
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID,
 			fmt.Sprintf("Введите новый регион для города *%s*:\n\nТекущий регион: %s", city.Name, city.Region))
 		msg.ParseMode = "Markdown"
 		h.bot.Send(msg)
 
+	case "🕐 Часовой пояс":
+		cityData.Field = "timezone"
+		cityData.CurrentValue = city.Timezone
+		h.stateManager.SetAdminState(userID, "city_edit_timezone")
+		h.showCityTimezoneChoice(update, city)
+
 	case "👥 Показать врачей":
 		h.showVetsInCity(update, city)
 
@@ -2325,9 +2629,8 @@ func (h *AdminHandlers) handleCityEditMenu(update tgbotapi.Update, text string)
 // handleCityEditName обрабатывает ввод нового названия города
 func (h *AdminHandlers) handleCityEditName(update tgbotapi.Update, text string) {
 	userID := update.Message.From.ID
-	userIDStr := strconv.FormatInt(userID, 10)
 
-	cityData, ok := h.tempData[userIDStr+"_city_edit"].(*models.CityEditData)
+	cityData, ok := h.stateManager.GetAdminData(userID, "city_edit").(*models.CityEditData)
 	if !ok || cityData == nil {
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Ошибка: данные города не найдены")
 		h.bot.Send(msg)
@@ -2377,9 +2680,8 @@ func (h *AdminHandlers) handleCityEditName(update tgbotapi.Update, text string)
 // handleCityEditRegion обрабатывает ввод нового региона города
 func (h *AdminHandlers) handleCityEditRegion(update tgbotapi.Update, text string) {
 	userID := update.Message.From.ID
-	userIDStr := strconv.FormatInt(userID, 10)
 
-	cityData, ok := h.tempData[userIDStr+"_city_edit"].(*models.CityEditData)
+	cityData, ok := h.stateManager.GetAdminData(userID, "city_edit").(*models.CityEditData)
 	if !ok || cityData == nil {
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Ошибка: данные города не найдены")
 		h.bot.Send(msg)
@@ -2416,10 +2718,93 @@ func (h *AdminHandlers) handleCityEditRegion(update tgbotapi.Update, text string
 	}
 }
 
+// showCityTimezoneChoice предлагает выбрать часовой пояс города из часовых поясов России
+func (h *AdminHandlers) showCityTimezoneChoice(update tgbotapi.Update, city *models.City) {
+	var rows [][]tgbotapi.KeyboardButton
+	var row []tgbotapi.KeyboardButton
+	for _, option := range models.RussianTimezones {
+		row = append(row, tgbotapi.NewKeyboardButton(option.Label))
+		if len(row) == 2 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	rows = append(rows, tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton("🔙 Назад")))
+
+	msg := tgbotapi.NewMessage(update.Message.Chat.ID,
+		fmt.Sprintf("🕐 Выберите часовой пояс для города *%s*:\n\nТекущий: %s\n\n"+
+			"По нему считаются \"сегодня\" и \"сейчас\" в расписании клиник города. "+
+			"Можно также ввести имя пояса вида Europe/Moscow.",
+			escapeMarkdown(city.Name), escapeMarkdown(models.FormatTimezone(city.Timezone, time.Now()))))
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = tgbotapi.NewReplyKeyboard(rows...)
+	h.bot.Send(msg)
+}
+
+// handleCityEditTimezone обрабатывает выбор часового пояса города
+func (h *AdminHandlers) handleCityEditTimezone(update tgbotapi.Update, text string) {
+	userID := update.Message.From.ID
+
+	cityData, ok := h.stateManager.GetAdminData(userID, "city_edit").(*models.CityEditData)
+	if !ok || cityData == nil {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Ошибка: данные города не найдены")
+		h.bot.Send(msg)
+		h.showCityList(update)
+		return
+	}
+
+	timezone, err := models.ParseTimezone(text)
+	if err != nil {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, fmt.Sprintf("❌ %v\n\nВыберите пояс кнопкой:", err))
+		h.bot.Send(msg)
+		return
+	}
+
+	city, err := h.db.GetCityByID(cityData.CityID)
+	if err != nil {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Ошибка при получении данных города")
+		h.bot.Send(msg)
+		h.showCityList(update)
+		return
+	}
+
+	city.Timezone = timezone
+	if err := h.db.UpdateCity(city); err != nil {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID,
+			fmt.Sprintf("❌ Ошибка при обновлении часового пояса: %s", err.Error()))
+		h.bot.Send(msg)
+	} else {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID,
+			fmt.Sprintf("✅ Часовой пояс города *%s* изменен на: %s",
+				escapeMarkdown(city.Name), escapeMarkdown(models.FormatTimezone(timezone, time.Now()))))
+		msg.ParseMode = "Markdown"
+		h.bot.Send(msg)
+	}
+
+	h.showCityEditMenu(update, city)
+}
+
+// returnToCityEditMenu возвращает в меню редактируемого города
+func (h *AdminHandlers) returnToCityEditMenu(update tgbotapi.Update) {
+	userID := update.Message.From.ID
+	cityData, ok := h.stateManager.GetAdminData(userID, "city_edit").(*models.CityEditData)
+	if ok && cityData != nil {
+		if city, err := h.db.GetCityByID(cityData.CityID); err == nil {
+			h.showCityEditMenu(update, city)
+			return
+		}
+	}
+	h.stateManager.SetAdminState(userID, "city_management")
+	h.showCityManagement(update)
+}
+
 // startDeleteCity начинает процесс удаления города
 func (h *AdminHandlers) startDeleteCity(update tgbotapi.Update, city *models.City) {
 	userID := update.Message.From.ID
-	h.adminState[userID] = "city_confirm_delete"
+	h.stateManager.SetAdminState(userID, "city_confirm_delete")
 
 	// Проверяем, есть ли связанные данные
 	vetsCount, _ := h.getVetsCountByCity(city.ID)
@@ -2449,9 +2834,8 @@ func (h *AdminHandlers) startDeleteCity(update tgbotapi.Update, city *models.Cit
 // handleCityConfirmDelete обрабатывает подтверждение удаления города
 func (h *AdminHandlers) handleCityConfirmDelete(update tgbotapi.Update, text string) {
 	userID := update.Message.From.ID
-	userIDStr := strconv.FormatInt(userID, 10)
 
-	cityData, ok := h.tempData[userIDStr+"_city_edit"].(*models.CityEditData)
+	cityData, ok := h.stateManager.GetAdminData(userID, "city_edit").(*models.CityEditData)
 	if !ok || cityData == nil {
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Ошибка: данные города не найдены")
 		h.bot.Send(msg)
@@ -2492,7 +2876,7 @@ func (h *AdminHandlers) handleCityConfirmDelete(update tgbotapi.Update, text str
 // startSearchByRegion начинает поиск по региону
 func (h *AdminHandlers) startSearchByRegion(update tgbotapi.Update) {
 	userID := update.Message.From.ID
-	h.adminState[userID] = "city_search_region"
+	h.stateManager.SetAdminState(userID, "city_search_region")
 
 	removeKeyboard := tgbotapi.NewRemoveKeyboard(true)
 	msg := tgbotapi.NewMessage(update.Message.Chat.ID,
@@ -2506,18 +2890,15 @@ func (h *AdminHandlers) startSearchByRegion(update tgbotapi.Update) {
 // handleVetSearchCity обрабатывает выбор города для поиска врачей
 func (h *AdminHandlers) handleVetSearchCity(update tgbotapi.Update, text string) {
 	userID := update.Message.From.ID
-	userIDStr := strconv.FormatInt(userID, 10)
 
 	// Получаем список городов из временных данных
-	citiesInterface, exists := h.tempData[userIDStr+"_cities"]
-	if !exists {
+	cities, ok := h.stateManager.GetAdminData(userID, "cities").([]*models.City)
+	if !ok {
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Ошибка: данные о городах не найдены")
 		h.bot.Send(msg)
 		return
 	}
 
-	cities := citiesInterface.([]*models.City)
-
 	// Парсим номер города
 	cityNum, err := strconv.Atoi(text)
 	if err != nil || cityNum < 1 || cityNum > len(cities) {
@@ -2533,7 +2914,7 @@ func (h *AdminHandlers) handleVetSearchCity(update tgbotapi.Update, text string)
 		CityID: selectedCity.ID,
 	}
 
-	vets, err := h.db.FindVetsByCity(criteria)
+	vets, err := h.db.SearchVets(criteria)
 	if err != nil {
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID,
 			fmt.Sprintf("❌ Ошибка при поиске врачей в городе %s", selectedCity.Name))
@@ -2577,10 +2958,10 @@ func (h *AdminHandlers) handleVetSearchCity(update tgbotapi.Update, text string)
 	}
 
 	// Очищаем временные данные
-	delete(h.tempData, userIDStr+"_cities")
+	h.stateManager.ClearAdminDataByKey(userID, "cities")
 
 	// Возвращаем в меню управления врачами
-	h.adminState[userID] = "vet_management"
+	h.stateManager.SetAdminState(userID, "vet_management")
 
 	keyboard := tgbotapi.NewReplyKeyboard(
 		tgbotapi.NewKeyboardButtonRow(
@@ -2602,7 +2983,7 @@ func (h *AdminHandlers) showVetsInCity(update tgbotapi.Update, city *models.City
 		CityID: city.ID,
 	}
 
-	vets, err := h.db.FindVetsByCity(criteria)
+	vets, err := h.db.SearchVets(criteria)
 	if err != nil {
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID,
 			fmt.Sprintf("❌ Ошибка при поиске врачей в городе %s", city.Name))
@@ -2734,21 +3115,8 @@ func (h *AdminHandlers) updateCityField(cityID int, field string, value string)
 
 // deleteCity удаляет город из базы данных
 func (h *AdminHandlers) deleteCity(cityID int) error {
-	// Сначала обнуляем city_id у врачей
-	_, err := h.db.GetDB().Exec("UPDATE veterinarians SET city_id = NULL WHERE city_id = $1", cityID)
-	if err != nil {
-		return err
-	}
-
-	// Обнуляем city_id у клиник
-	_, err = h.db.GetDB().Exec("UPDATE clinics SET city_id = NULL WHERE city_id = $1", cityID)
-	if err != nil {
-		return err
-	}
-
-	// Удаляем город
-	_, err = h.db.GetDB().Exec("DELETE FROM cities WHERE id = $1", cityID)
-	return err
+	// Врачи и клиники города остаются без города
+	return h.db.DeleteCity(cityID)
 }
 
 // getCitiesCount возвращает количество городов
@@ -2761,44 +3129,35 @@ func (h *AdminHandlers) getCitiesCount() (int, error) {
 
 // getVetsCountByCity возвращает количество врачей в городе
 func (h *AdminHandlers) getVetsCountByCity(cityID int) (int, error) {
-	query := "SELECT COUNT(*) FROM veterinarians WHERE city_id = $1"
-	var count int
-	err := h.db.GetDB().QueryRow(query, cityID).Scan(&count)
-	return count, err
+	return h.db.GetVetsCountByCity(cityID)
 }
 
 // getClinicsCountByCity возвращает количество клиник в городе
 func (h *AdminHandlers) getClinicsCountByCity(cityID int) (int, error) {
-	query := "SELECT COUNT(*) FROM clinics WHERE city_id = $1"
-	var count int
-	err := h.db.GetDB().QueryRow(query, cityID).Scan(&count)
-	return count, err
+	return h.db.GetClinicsCountByCity(cityID)
 }
 
 // handleVetEditCity обрабатывает изменение города врача
 func (h *AdminHandlers) handleVetEditCity(update tgbotapi.Update, text string) {
 	userID := update.Message.From.ID
-	userIDStr := strconv.FormatInt(userID, 10)
 
 	// Получаем список городов из временных данных
-	citiesInterface, exists := h.tempData[userIDStr+"_cities"]
-	if !exists {
+	cities, ok := h.stateManager.GetAdminData(userID, "cities").([]*models.City)
+	if !ok {
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Ошибка: данные о городах не найдены")
 		h.bot.Send(msg)
 		return
 	}
 
-	cities := citiesInterface.([]*models.City)
-
 	// Получаем ID врача
-	vetEditData, exists := h.tempData[userIDStr+"_vet_edit"]
-	if !exists {
+	vetEditData, ok := h.stateManager.GetAdminData(userID, "vet_edit").(*models.VetEditData)
+	if !ok {
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Ошибка: данные о враче не найдены")
 		h.bot.Send(msg)
 		return
 	}
 
-	vetID := vetEditData.(*models.VetEditData).VetID
+	vetID := vetEditData.VetID
 
 	// Парсим номер города
 	cityNum, err := strconv.Atoi(text)
@@ -2830,7 +3189,7 @@ func (h *AdminHandlers) handleVetEditCity(update tgbotapi.Update, text string) {
 	}
 
 	// Очищаем временные данные
-	delete(h.tempData, userIDStr+"_cities")
+	h.stateManager.ClearAdminDataByKey(userID, "cities")
 
 	msg := tgbotapi.NewMessage(update.Message.Chat.ID,
 		fmt.Sprintf("✅ Город врача успешно изменен на: *%s* (%s) 🏙️", selectedCity.Name, selectedCity.Region))
@@ -2843,7 +3202,7 @@ func (h *AdminHandlers) handleVetEditCity(update tgbotapi.Update, text string) {
 
 func (h *AdminHandlers) startChangeVetCity(update tgbotapi.Update, vet *models.Veterinarian) {
 	userID := update.Message.From.ID
-	h.adminState[userID] = "vet_edit_city"
+	h.stateManager.SetAdminState(userID, "vet_edit_city")
 
 	cities, err := h.db.GetAllCities()
 	if err != nil {
@@ -2878,11 +3237,10 @@ func (h *AdminHandlers) startChangeVetCity(update tgbotapi.Update, vet *models.V
 	msg.ReplyMarkup = keyboard
 
 	// Сохраняем список городов и ID врача во временные данные
-	userIDStr := strconv.FormatInt(userID, 10)
-	h.tempData[userIDStr+"_cities"] = cities
-	h.tempData[userIDStr+"_vet_edit"] = &models.VetEditData{
+	h.stateManager.SetAdminData(userID, "cities", cities)
+	h.stateManager.SetAdminData(userID, "vet_edit", &models.VetEditData{
 		VetID: models.GetVetIDAsIntOrZero(vet),
-	}
+	})
 
 	h.bot.Send(msg)
 }
@@ -2947,109 +3305,199 @@ func (h *AdminHandlers) GenerateImportTemplate(w http.ResponseWriter, r *http.Re
 	defer os.Remove(filepath)
 }
 
+// HandleAdminDocument проверяет загруженный администратором файл импорта и показывает предпросмотр
 func (h *AdminHandlers) HandleAdminDocument(update tgbotapi.Update) {
 	userID := update.Message.From.ID
+	state := h.stateManager.GetAdminState(userID)
+	fileName := update.Message.Document.FileName
 
-	// Проверяем, что пользователь в состоянии импорта
-	state := h.adminState[userID]
-	if state != "import_menu" && !strings.Contains(state, "import") {
+	importType := h.importTypeFor(userID, state, fileName)
+	if importType == "" {
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID,
-			"Сначала выберите тип импорта в меню админки")
+			"Не могу определить тип данных для импорта. Выберите тип в меню \"📥 Импорт данных\" "+
+				"или уточните в названии файла (врач/город/клиника)")
 		h.bot.Send(msg)
 		return
 	}
 
+	InfoLog.Printf("📥 Файл '%s' получен для импорта %s (state: %s)", fileName, importType, state)
+
+	// Загрузка и проверка идут в фоне, чтобы большой файл не задерживал обработку сообщений
 	fileID := update.Message.Document.FileID
-	fileName := update.Message.Document.FileName
+	h.startImportJob(update.Message.Chat.ID, userID, importType, fileName, func() ([]byte, error) {
+		return h.downloadImportFile(fileID)
+	})
+}
 
-	InfoLog.Printf("📥 Файл '%s' получен для импорта (state: %s)", fileName, state)
+// importTypeFor определяет тип импорта. Тип, выбранный в меню, важнее имени файла:
+// книга выгрузки содержит все листы, и импортируется лист выбранного типа
+func (h *AdminHandlers) importTypeFor(userID int64, state, fileName string) string {
+	switch state {
+	case "import_veterinarians":
+		return imports.ImportTypeVeterinarians
+	case "import_cities":
+		return imports.ImportTypeCities
+	case "import_clinics":
+		return imports.ImportTypeClinics
+	}
+
+	lowerName := strings.ToLower(fileName)
+	switch {
+	case strings.Contains(lowerName, "врач"):
+		return imports.ImportTypeVeterinarians
+	case strings.Contains(lowerName, "город"):
+		return imports.ImportTypeCities
+	case strings.Contains(lowerName, "клиник"):
+		return imports.ImportTypeClinics
+	}
 
-	// Скачиваем файл
-	fileConfig := tgbotapi.FileConfig{FileID: fileID}
-	file, err := h.bot.GetFile(fileConfig)
+	// Повторная загрузка вместо подтверждения - того же типа, что и проверенный файл
+	if job, ok := h.pendingImportJob(userID); ok {
+		return job.plan.Type
+	}
+	return ""
+}
+
+// downloadImportFile скачивает файл импорта из Telegram
+func (h *AdminHandlers) downloadImportFile(fileID string) ([]byte, error) {
+	file, err := h.bot.GetFile(tgbotapi.FileConfig{FileID: fileID})
 	if err != nil {
-		ErrorLog.Printf("❌ Ошибка получения файла: %v", err)
-		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Ошибка загрузки файла")
-		h.bot.Send(msg)
-		return
+		return nil, fmt.Errorf("ошибка получения файла: %w", err)
 	}
 
-	// Получаем прямую ссылку на файл
-	fileURL := file.Link(h.config.TelegramToken)
-	resp, err := http.Get(fileURL)
+	resp, err := http.Get(file.Link(h.config.TelegramToken))
 	if err != nil {
-		ErrorLog.Printf("❌ Ошибка скачивания файла: %v", err)
-		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Ошибка скачивания файла")
-		h.bot.Send(msg)
-		return
+		return nil, fmt.Errorf("ошибка скачивания файла: %w", err)
 	}
 	defer resp.Body.Close()
 
-	// Определяем тип импорта по состоянию и имени файла
-	if strings.Contains(strings.ToLower(fileName), "врач") || state == "import_veterinarians" {
-		h.importVeterinarians(update, resp.Body, fileName)
-	} else if strings.Contains(strings.ToLower(fileName), "город") || state == "import_cities" {
-		h.importCities(update, resp.Body, fileName)
-	} else if strings.Contains(strings.ToLower(fileName), "клиник") || state == "import_clinics" {
-		h.importClinics(update, resp.Body, fileName)
-	} else {
-		msg := tgbotapi.NewMessage(update.Message.Chat.ID,
-			"Не могу определить тип данных для импорта. Уточните в названии файла (врач/город/клиника)")
-		h.bot.Send(msg)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ошибка скачивания файла: статус %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImportFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения файла: %w", err)
 	}
+	if len(data) > maxImportFileSize {
+		return nil, fmt.Errorf("файл больше %d МБ", maxImportFileSize>>20)
+	}
+	return data, nil
 }
 
-// Метод для импорта врачей
-func (h *AdminHandlers) importVeterinarians(update tgbotapi.Update, file io.Reader, fileName string) {
-	msg := tgbotapi.NewMessage(update.Message.Chat.ID, "🔄 Начинаю импорт врачей...")
-	h.bot.Send(msg)
+// sendImportReport отправляет загруженный файл в виде книги Excel со статусом каждой строки
+func (h *AdminHandlers) sendImportReport(chatID int64, plan *imports.ImportPlan) {
+	data, err := plan.AnnotatedWorkbook()
+	if err != nil {
+		ErrorLog.Printf("❌ Ошибка подготовки файла с результатами импорта: %v", err)
+		return
+	}
 
-	// Создаем импортер
-	importer := imports.NewCSVImporter(h.db)
+	document := tgbotapi.NewDocument(chatID, tgbotapi.FileBytes{Name: plan.AnnotatedFileName(), Bytes: data})
+	if _, err := h.bot.Send(document); err != nil {
+		ErrorLog.Printf("❌ Ошибка отправки файла с результатами импорта: %v", err)
+	}
+}
 
-	// Выполняем импорт
-	result, err := importer.ImportVeterinarians(file, fileName, InfoLog, ErrorLog)
-	if err != nil {
-		ErrorLog.Printf("❌ Ошибка импорта врачей: %v", err)
+// handleImportConfirm запускает применение проверенного плана импорта в фоне
+func (h *AdminHandlers) handleImportConfirm(update tgbotapi.Update, text string) {
+	if text != "✅ Подтвердить импорт" {
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID,
-			fmt.Sprintf("❌ Ошибка импорта: %v", err))
+			"Подтвердите импорт или отмените его кнопками ниже. Можно также отправить исправленный файл")
 		h.bot.Send(msg)
 		return
 	}
 
-	// Формируем отчет
-	report := fmt.Sprintf("📊 *Результат импорта врачей:*\n\n"+
-		"📁 Файл: %s\n"+
-		"📊 Всего строк: %d\n"+
-		"✅ Успешно: %d\n"+
-		"❌ Ошибок: %d\n\n",
-		fileName, result.TotalRows, result.SuccessCount, result.ErrorCount)
+	userID := update.Message.From.ID
+	job, ok := h.pendingImportJob(userID)
+	h.stateManager.ClearAdminDataByKey(userID, "import_job")
+	if !ok || !h.takeReadyImport(job, models.ImportStatusProcessing) {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Нет проверенного файла. Загрузите файл еще раз")
+		h.bot.Send(msg)
+		h.showImportMenu(update)
+		return
+	}
 
-	if result.ErrorCount > 0 {
-		report += "*Ошибки:*\n"
-		for _, importError := range result.Errors {
-			report += fmt.Sprintf("Строка %d: %s\n", importError.RowNumber, importError.Message)
-		}
+	h.updateImportJob(job, func(request *models.ImportRequest) {
+		request.Status = models.ImportStatusProcessing
+		request.ProcessedRows = 0
+		request.TotalRows = job.plan.Count(models.ImportActionCreate) + job.plan.Count(models.ImportActionUpdate)
+	})
+
+	// Новое сообщение с прогрессом: прежнее осталось выше предпросмотра
+	job.mutex.Lock()
+	if sent, err := h.bot.Send(tgbotapi.NewMessage(update.Message.Chat.ID, formatImportJobStatus(job.request))); err == nil {
+		job.messageID = sent.MessageID
 	}
+	job.mutex.Unlock()
 
-	msg = tgbotapi.NewMessage(update.Message.Chat.ID, report)
-	msg.ParseMode = "Markdown"
+	h.runImportJob(job, func() { h.applyImportPlan(job) })
+
+	msg := tgbotapi.NewMessage(update.Message.Chat.ID, fmt.Sprintf(
+		"Импорт #%d выполняется в фоне - можно продолжать работу.\n\n"+
+			"Список задач: /imports\nОтменить: /cancel_import %d", job.request.ID, job.request.ID))
 	h.bot.Send(msg)
 
 	// Возвращаем в меню админки
-	h.adminState[update.Message.From.ID] = "main_menu"
+	h.stateManager.SetAdminState(userID, "main_menu")
 	h.HandleAdmin(update)
 }
 
-// Временные заглушки для других типов импорта
-func (h *AdminHandlers) importCities(update tgbotapi.Update, _ io.Reader, _ string) {
-	msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Импорт городов в разработке")
-	h.bot.Send(msg)
+// importTypeTitles названия типов импорта в родительном падеже для сообщений
+var importTypeTitles = map[string]string{
+	imports.ImportTypeVeterinarians: "врачей",
+	imports.ImportTypeClinics:       "клиник",
+	imports.ImportTypeCities:        "городов",
 }
 
-func (h *AdminHandlers) importClinics(update tgbotapi.Update, _ io.Reader, _ string) {
-	msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Импорт клиник в разработке")
-	h.bot.Send(msg)
+// formatImportPreview описывает план импорта: итоги и строки по каждому действию.
+// Сообщение без Markdown - в названиях файлов и данных бывают подчеркивания
+func formatImportPreview(plan *imports.ImportPlan) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "🔍 Предпросмотр импорта %s\n\n", importTypeTitles[plan.Type])
+	fmt.Fprintf(&b, "📁 Файл: %s\n", plan.FileName)
+	fmt.Fprintf(&b, "📊 Всего строк: %d\n", plan.TotalRows)
+	fmt.Fprintf(&b, "➕ Будет создано: %d\n", plan.Count(models.ImportActionCreate))
+	fmt.Fprintf(&b, "🔄 Будет обновлено: %d\n", plan.Count(models.ImportActionUpdate))
+	fmt.Fprintf(&b, "⏭️ Будет пропущено: %d\n", plan.Count(models.ImportActionSkip))
+	fmt.Fprintf(&b, "❌ Ошибок: %d", plan.Count(models.ImportActionError))
+
+	writeImportPreviewRows(&b, "Ошибки", plan.RowsWith(models.ImportActionError))
+	writeImportPreviewRows(&b, "Будет создано", plan.RowsWith(models.ImportActionCreate))
+	writeImportPreviewRows(&b, "Будет обновлено", plan.RowsWith(models.ImportActionUpdate))
+	writeImportPreviewRows(&b, "Будет пропущено", plan.RowsWith(models.ImportActionSkip))
+
+	return b.String()
+}
+
+// writeImportPreviewRows добавляет к предпросмотру раздел со строками файла (не больше maxImportPreviewRows)
+func writeImportPreviewRows(b *strings.Builder, title string, rows []*imports.PlannedRow) {
+	if len(rows) == 0 {
+		return
+	}
+
+	fmt.Fprintf(b, "\n\n%s:", title)
+	for i, row := range rows {
+		if i == maxImportPreviewRows {
+			fmt.Fprintf(b, "\n... и еще %d", len(rows)-maxImportPreviewRows)
+			break
+		}
+
+		details := make([]string, 0, 2)
+		if row.Title != "" {
+			details = append(details, row.Title)
+		}
+		// Несколько изменений одной записи выводим списком под строкой
+		if row.Message != "" && len(row.Changes) < 2 {
+			details = append(details, row.Message)
+		}
+		fmt.Fprintf(b, "\nСтрока %d: %s", row.RowNumber, strings.Join(details, " - "))
+		if len(row.Changes) > 1 {
+			for _, change := range row.Changes {
+				fmt.Fprintf(b, "\n   • %s", change)
+			}
+		}
+	}
 }
 
 // IsAdmin проверяет, является ли пользователь администратором
@@ -1,13 +1,19 @@
 package handlers
 
 import (
+	"database/sql"
 	"fmt"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/drerr0r/vetbot/internal/imports"
+	"github.com/drerr0r/vetbot/internal/models"
 	"github.com/drerr0r/vetbot/pkg/utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // ============================================================================
@@ -61,6 +67,30 @@ func TestAdminHandlersTempData(t *testing.T) {
 	assert.Nil(t, value)
 }
 
+func TestAdminSessionSurvivesRestart(t *testing.T) {
+	storage := NewMemoryStateStorage()
+	config := &utils.Config{AdminIDs: []int64{12345}}
+	mockDB := NewMockDatabase()
+
+	admin := NewAdminHandlers(NewMockBot(), mockDB, config, NewStateManagerWithStorage(storage), &ReviewHandlers{})
+	for _, text := range []string{"/admin", "👥 Управление врачами", "➕ Добавить врача", "Иван Петров"} {
+		admin.HandleAdminMessage(NewTestUpdate().WithMessage(text, 12345, 12345).Build())
+	}
+	require.Equal(t, "add_vet_phone", admin.stateManager.GetAdminState(12345))
+
+	// Новый экземпляр с тем же хранилищем - как после перезапуска бота
+	restarted := NewAdminHandlers(NewMockBot(), mockDB, config, NewStateManagerWithStorage(storage), &ReviewHandlers{})
+	assert.True(t, restarted.HasActiveSession(12345))
+	assert.Equal(t, "add_vet_phone", restarted.stateManager.GetAdminState(12345))
+	assert.Equal(t, "Иван Петров", restarted.getStringTempData(12345, "name"))
+
+	restarted.EndSession(12345)
+	assert.False(t, restarted.HasActiveSession(12345))
+	loaded, err := storage.LoadSession(12345)
+	require.NoError(t, err)
+	assert.Nil(t, loaded)
+}
+
 // ============================================================================
 // ТЕСТЫ ДЛЯ ЛОГИКИ СОСТОЯНИЙ И НАВИГАЦИИ
 // ============================================================================
@@ -601,52 +631,45 @@ func TestAdminHandlers_SpecializationHandling(t *testing.T) {
 func TestAdminHandlers_EdgeCases(t *testing.T) {
 	t.Run("Nil handler components", func(t *testing.T) {
 		handler := &AdminHandlers{
-			bot:        nil,
-			db:         nil,
-			adminState: nil,
-			tempData:   nil,
+			bot:          nil,
+			db:           nil,
+			stateManager: nil,
 		}
 
 		// Проверяем что код может обрабатывать nil
 		assert.Nil(t, handler.bot)
 		assert.Nil(t, handler.db)
-		assert.Nil(t, handler.adminState)
-		assert.Nil(t, handler.tempData)
+		assert.Nil(t, handler.stateManager)
 	})
 
 	t.Run("Empty state handling", func(t *testing.T) {
 		handler := &AdminHandlers{
-			adminState: make(map[int64]string),
+			stateManager: NewTestStateManager(),
 		}
 
 		userID := int64(12345)
 
 		// Проверяем обработку отсутствующего состояния
-		state, exists := handler.adminState[userID]
-		assert.False(t, exists)
-		assert.Equal(t, "", state)
+		assert.False(t, handler.HasActiveSession(userID))
+		assert.Equal(t, "", handler.stateManager.GetAdminState(userID))
 
 		// Проверяем, что можем установить состояние и использовать его
-		handler.adminState[userID] = "main_menu"
-		newState := handler.adminState[userID]
+		handler.stateManager.SetAdminState(userID, "main_menu")
+		newState := handler.stateManager.GetAdminState(userID)
 		assert.Equal(t, "main_menu", newState)
-
-		// Используем состояние в логике
-		if newState == "main_menu" {
-			assert.True(t, true, "Состояние должно быть main_menu")
-		}
+		assert.True(t, handler.HasActiveSession(userID))
 	})
 
 	t.Run("Back button from unknown state", func(t *testing.T) {
 		handler := &AdminHandlers{
-			adminState: make(map[int64]string),
+			stateManager: NewTestStateManager(),
 		}
 
 		userID := int64(12345)
-		handler.adminState[userID] = "unknown_state"
+		handler.stateManager.SetAdminState(userID, "unknown_state")
 
 		// Используем состояние для определения нового состояния
-		currentState := handler.adminState[userID]
+		currentState := handler.stateManager.GetAdminState(userID)
 		var newState string
 
 		switch currentState {
@@ -661,8 +684,8 @@ func TestAdminHandlers_EdgeCases(t *testing.T) {
 		}
 
 		// Сохраняем и используем новое состояние
-		handler.adminState[userID] = newState
-		finalState := handler.adminState[userID]
+		handler.stateManager.SetAdminState(userID, newState)
+		finalState := handler.stateManager.GetAdminState(userID)
 
 		assert.Equal(t, "main_menu", finalState)
 	})
@@ -836,3 +859,736 @@ func TestAdminHandlers_StatisticsLogic(t *testing.T) {
 		assert.Equal(t, 5.0, requestPerUser) // 500 / 100 = 5.0
 	})
 }
+
+// ============================================================================
+// ТЕСТЫ ДЛЯ ПРЕДПРОСМОТРА ИМПОРТА
+// ============================================================================
+
+// checkImportFileSync запускает проверку файла и ждет ее завершения
+func checkImportFileSync(admin *AdminHandlers, importType, fileName string, data []byte) {
+	admin.startImportJob(12345, 12345, importType, fileName, func() ([]byte, error) { return data, nil })
+	admin.importWG.Wait()
+}
+
+func TestAdminHandlers_ImportPreview(t *testing.T) {
+	t.Run("Plan waits for confirmation and cancel drops it", func(t *testing.T) {
+		admin, mockBot, mockDB := CreateTestAdminHandlers()
+		mockDB.AddTestCity(1, "Москва", "Московская область")
+
+		admin.stateManager.SetAdminState(12345, "import_cities")
+		checkImportFileSync(admin, imports.ImportTypeCities, "города_2026.csv",
+			[]byte("Название;Регион\nМосква;\nКазань;Татарстан\n;Без названия\n"))
+
+		assert.Equal(t, "import_confirm", admin.stateManager.GetAdminState(12345))
+		assert.NotNil(t, admin.stateManager.GetAdminData(12345, "import_job"))
+		assert.Equal(t, models.ImportStatusReady, mockDB.ImportRequests[1].Status)
+		assert.Contains(t, mockBot.GetLastEditedMessage().Text, "ждет подтверждения")
+
+		preview := mockBot.GetLastMessage()
+		assert.Empty(t, preview.ParseMode)
+		assert.Contains(t, preview.Text, "Будет создано: 1")
+		assert.Contains(t, preview.Text, "Будет пропущено: 1")
+		assert.Contains(t, preview.Text, "Ошибок: 1")
+		assert.Contains(t, preview.Text, "Строка 4: Не указано название города")
+		assert.Contains(t, preview.Text, "Строка 3: Казань")
+		assert.Contains(t, preview.Text, "Подтвердить импорт?")
+
+		// С ошибками в файле приходит книга со статусом каждой строки
+		require.Len(t, mockBot.Documents, 1)
+		report, ok := mockBot.Documents[0].File.(tgbotapi.FileBytes)
+		require.True(t, ok)
+		assert.Equal(t, "города_2026_проверка.xlsx", report.Name)
+
+		admin.HandleAdminMessage(NewTestUpdate().WithMessage("❌ Отмена", 12345, 12345).Build())
+
+		assert.Equal(t, "import_menu", admin.stateManager.GetAdminState(12345))
+		assert.Nil(t, admin.stateManager.GetAdminData(12345, "import_job"))
+		assert.Equal(t, models.ImportStatusCancelled, mockDB.ImportRequests[1].Status)
+		assert.Empty(t, admin.importJobs)
+		assert.Empty(t, mockDB.Cities[2])
+	})
+
+	t.Run("Nothing to import", func(t *testing.T) {
+		admin, mockBot, mockDB := CreateTestAdminHandlers()
+		mockDB.AddTestCity(1, "Москва", "Московская область")
+
+		checkImportFileSync(admin, imports.ImportTypeCities, "города.csv", []byte("Название;Регион\nМосква;Московская область\n"))
+
+		assert.Equal(t, "import_menu", admin.stateManager.GetAdminState(12345))
+		assert.Nil(t, admin.stateManager.GetAdminData(12345, "import_job"))
+		assert.Contains(t, mockBot.GetLastMessage().Text, "Изменений нет")
+		assert.Empty(t, mockBot.Documents)
+		assert.Equal(t, models.ImportStatusCompleted, mockDB.ImportRequests[1].Status)
+	})
+
+	t.Run("Unreadable file", func(t *testing.T) {
+		admin, mockBot, mockDB := CreateTestAdminHandlers()
+
+		checkImportFileSync(admin, imports.ImportTypeVeterinarians, "врачи.xlsx", []byte("not an xlsx"))
+
+		assert.NotEqual(t, "import_confirm", admin.stateManager.GetAdminState(12345))
+		assert.Contains(t, mockBot.GetLastEditedMessage().Text, "ошибка проверки файла")
+		assert.Equal(t, models.ImportStatusFailed, mockDB.ImportRequests[1].Status)
+		assert.True(t, mockDB.ImportRequests[1].FinishedAt.Valid)
+	})
+
+	t.Run("New file replaces unconfirmed one", func(t *testing.T) {
+		admin, _, mockDB := CreateTestAdminHandlers()
+
+		checkImportFileSync(admin, imports.ImportTypeCities, "города.csv", []byte("Название;Регион\nКазань;Татарстан\n"))
+		checkImportFileSync(admin, imports.ImportTypeCities, "города.csv", []byte("Название;Регион\nТверь;Тверская\n"))
+
+		assert.Equal(t, models.ImportStatusCancelled, mockDB.ImportRequests[1].Status)
+		assert.Equal(t, models.ImportStatusReady, mockDB.ImportRequests[2].Status)
+		job, ok := admin.pendingImportJob(12345)
+		require.True(t, ok)
+		assert.Equal(t, 2, job.request.ID)
+	})
+
+	t.Run("Import type from menu wins over file name", func(t *testing.T) {
+		admin, _, _ := CreateTestAdminHandlers()
+
+		assert.Equal(t, imports.ImportTypeClinics, admin.importTypeFor(12345, "import_clinics", "справочник_врачи.xlsx"))
+		assert.Equal(t, imports.ImportTypeVeterinarians, admin.importTypeFor(12345, "main_menu", "справочник_врачи.xlsx"))
+		assert.Equal(t, "", admin.importTypeFor(12345, "main_menu", "справочник.xlsx"))
+	})
+}
+
+func TestAdminHandlers_ImportJobs(t *testing.T) {
+	t.Run("List shows recent jobs with cancel command", func(t *testing.T) {
+		admin, mockBot, _ := CreateTestAdminHandlers()
+
+		checkImportFileSync(admin, imports.ImportTypeCities, "города_new.csv", []byte("Название;Регион\nКазань;Татарстан\n"))
+
+		admin.HandleImportJobs(NewTestUpdate().WithCommand("/imports", 12345, 12345).Build())
+
+		text := mockBot.GetLastMessage().Text
+		assert.Contains(t, text, "Импорт #1 (городов, файл города_new.csv)")
+		assert.Contains(t, text, "ждет подтверждения")
+		assert.Contains(t, text, "/cancel_import 1")
+	})
+
+	t.Run("Empty list", func(t *testing.T) {
+		admin, mockBot, _ := CreateTestAdminHandlers()
+
+		admin.HandleImportJobs(NewTestUpdate().WithCommand("/imports", 12345, 12345).Build())
+
+		assert.Contains(t, mockBot.GetLastMessage().Text, "Задач импорта пока нет")
+	})
+
+	t.Run("Cancel job waiting for confirmation", func(t *testing.T) {
+		admin, mockBot, mockDB := CreateTestAdminHandlers()
+
+		checkImportFileSync(admin, imports.ImportTypeCities, "города.csv", []byte("Название;Регион\nКазань;Татарстан\n"))
+		require.Equal(t, "import_confirm", admin.stateManager.GetAdminState(12345))
+
+		admin.HandleCancelImport(NewTestUpdate().WithCommand("/cancel_import 1", 12345, 12345).Build())
+
+		assert.Contains(t, mockBot.GetLastMessage().Text, "Импорт #1 отменен")
+		assert.Equal(t, "import_menu", admin.stateManager.GetAdminState(12345))
+		assert.Nil(t, admin.stateManager.GetAdminData(12345, "import_job"))
+		assert.Equal(t, models.ImportStatusCancelled, mockDB.ImportRequests[1].Status)
+		assert.Contains(t, mockBot.GetLastEditedMessage().Text, "отменен, изменения не сохранены")
+	})
+
+	t.Run("Ready job is taken only once", func(t *testing.T) {
+		admin, _, _ := CreateTestAdminHandlers()
+
+		checkImportFileSync(admin, imports.ImportTypeCities, "города.csv", []byte("Название;Регион\nКазань;Татарстан\n"))
+		job, ok := admin.pendingImportJob(12345)
+		require.True(t, ok)
+
+		// Подтверждение забрало задачу - отмена из другого чата ее уже не получит
+		assert.True(t, admin.takeReadyImport(job, models.ImportStatusProcessing))
+		assert.False(t, admin.takeReadyImport(job, models.ImportStatusCancelled))
+		job.cancel()
+	})
+
+	t.Run("Cancel unknown or finished job", func(t *testing.T) {
+		admin, mockBot, _ := CreateTestAdminHandlers()
+
+		admin.HandleCancelImport(NewTestUpdate().WithCommand("/cancel_import 7", 12345, 12345).Build())
+		assert.Contains(t, mockBot.GetLastMessage().Text, "Задача импорта #7 не выполняется")
+
+		admin.HandleCancelImport(NewTestUpdate().WithCommand("/cancel_import", 12345, 12345).Build())
+		assert.Contains(t, mockBot.GetLastMessage().Text, "Укажите номер задачи")
+	})
+}
+
+func TestAdminHandlers_ScheduleExceptions(t *testing.T) {
+	setup := func() (*AdminHandlers, *MockBot, *MockDatabase) {
+		admin, mockBot, mockDB := CreateTestAdminHandlers()
+		mockDB.AddTestVeterinarian(1, "Иван", "Петров", "+79990000001")
+		mockDB.AddTestClinic(1, "ВетКлиника", "ул. Ленина, 1", 1)
+		mockDB.VetClinics[1] = map[int]bool{1: true}
+		admin.stateManager.SetAdminState(12345, "vet_edit_menu")
+		admin.stateManager.SetAdminData(12345, "vet_edit", &models.VetEditData{VetID: 1})
+		return admin, mockBot, mockDB
+	}
+	send := func(admin *AdminHandlers, text string) {
+		admin.HandleAdminMessage(NewTestUpdate().WithMessage(text, 12345, 12345).Build())
+	}
+	future := time.Now().AddDate(0, 0, 10).Format(models.ScheduleDateLayout)
+	futureEnd := time.Now().AddDate(0, 0, 20).Format(models.ScheduleDateLayout)
+
+	t.Run("Add absence", func(t *testing.T) {
+		admin, mockBot, mockDB := setup()
+
+		send(admin, "🏖️ Исключения в расписании")
+		assert.Equal(t, "vet_edit_exceptions", admin.stateManager.GetAdminState(12345))
+		assert.Contains(t, mockBot.GetLastMessage().Text, "Исключений нет")
+
+		send(admin, "➕ Отсутствие")
+		assert.Equal(t, "vet_edit_exception_absence", admin.stateManager.GetAdminState(12345))
+
+		send(admin, future+"-"+futureEnd+" Отпуск")
+		require.Len(t, mockDB.Exceptions, 1)
+		exception := mockDB.Exceptions[1]
+		assert.False(t, exception.IsAvailable)
+		assert.False(t, exception.ClinicID.Valid)
+		assert.Equal(t, "Отпуск", exception.Reason)
+		assert.Equal(t, "vet_edit_exceptions", admin.stateManager.GetAdminState(12345))
+		assert.Contains(t, mockBot.GetLastMessage().Text, "1. "+future+"-"+futureEnd+": не принимает (Отпуск)")
+	})
+
+	t.Run("Add hours with the only clinic", func(t *testing.T) {
+		admin, mockBot, mockDB := setup()
+
+		send(admin, "🏖️ Исключения в расписании")
+		send(admin, "➕ Другие часы")
+		assert.Contains(t, mockBot.GetLastMessage().Text, "1. ВетКлиника")
+
+		send(admin, future+" 10:00-14:00")
+		require.Len(t, mockDB.Exceptions, 1)
+		assert.Equal(t, int64(1), mockDB.Exceptions[1].ClinicID.Int64)
+		assert.Contains(t, mockBot.GetLastMessage().Text, future+": прием 10:00-14:00 в ВетКлиника")
+	})
+
+	t.Run("Reject invalid input", func(t *testing.T) {
+		admin, mockBot, mockDB := setup()
+
+		send(admin, "🏖️ Исключения в расписании")
+		send(admin, "➕ Другие часы")
+		send(admin, future+" 25:00-26:00")
+		assert.Empty(t, mockDB.Exceptions)
+		assert.Equal(t, "vet_edit_exception_hours", admin.stateManager.GetAdminState(12345))
+
+		send(admin, "🔙 Назад")
+		send(admin, "➕ Отсутствие")
+		send(admin, "01.01.2020 Отпуск")
+		assert.Empty(t, mockDB.Exceptions)
+		assert.Contains(t, mockBot.GetLastMessage().Text, "Период уже прошел")
+	})
+
+	t.Run("Delete by number", func(t *testing.T) {
+		admin, mockBot, mockDB := setup()
+
+		send(admin, "🏖️ Исключения в расписании")
+		send(admin, "➕ Отсутствие")
+		send(admin, future+" Больничный")
+		require.Len(t, mockDB.Exceptions, 1)
+
+		send(admin, "🗑️ Удалить исключение")
+		send(admin, "2")
+		assert.Contains(t, mockBot.GetLastMessage().Text, "Введите номер от 1 до 1")
+
+		send(admin, "1")
+		assert.Empty(t, mockDB.Exceptions)
+		assert.Equal(t, "vet_edit_exceptions", admin.stateManager.GetAdminState(12345))
+
+		send(admin, "🔙 Назад")
+		assert.Equal(t, "vet_edit_menu", admin.stateManager.GetAdminState(12345))
+	})
+}
+
+func TestAdminHandlers_CityTimezone(t *testing.T) {
+	setup := func() (*AdminHandlers, *MockBot, *MockDatabase) {
+		admin, mockBot, mockDB := CreateTestAdminHandlers()
+		mockDB.Cities[1] = &models.City{ID: 1, Name: "Новосибирск", Region: "Новосибирская область", Timezone: models.DefaultTimezone}
+		admin.stateManager.SetAdminState(12345, "city_edit_menu")
+		admin.stateManager.SetAdminData(12345, "city_edit", &models.CityEditData{CityID: 1})
+		return admin, mockBot, mockDB
+	}
+	send := func(admin *AdminHandlers, text string) {
+		admin.HandleAdminMessage(NewTestUpdate().WithMessage(text, 12345, 12345).Build())
+	}
+
+	t.Run("Choose from keyboard", func(t *testing.T) {
+		admin, mockBot, mockDB := setup()
+
+		send(admin, "🕐 Часовой пояс")
+		assert.Equal(t, "city_edit_timezone", admin.stateManager.GetAdminState(12345))
+		assert.Contains(t, mockBot.GetLastMessage().Text, "Europe/Moscow (UTC+3)")
+
+		send(admin, "Новосибирск (UTC+7)")
+		assert.Equal(t, "Asia/Novosibirsk", mockDB.Cities[1].Timezone)
+		assert.Equal(t, "city_edit_menu", admin.stateManager.GetAdminState(12345))
+		assert.Contains(t, mockBot.GetLastMessage().Text, "Часовой пояс: Asia/Novosibirsk (UTC+7)")
+	})
+
+	t.Run("City name is escaped for Markdown", func(t *testing.T) {
+		admin, mockBot, mockDB := setup()
+		mockDB.Cities[1].Name = "Ново_сибирск"
+
+		send(admin, "🕐 Часовой пояс")
+		assert.Contains(t, mockBot.GetLastMessage().Text, `города *Ново\_сибирск*`)
+
+		sentBefore := len(mockBot.SentMessages)
+		send(admin, "Новосибирск (UTC+7)")
+		require.Greater(t, len(mockBot.SentMessages), sentBefore)
+		assert.Contains(t, mockBot.SentMessages[sentBefore].Text, `Часовой пояс города *Ново\_сибирск* изменен`)
+	})
+
+	t.Run("Typed IANA name", func(t *testing.T) {
+		admin, _, mockDB := setup()
+
+		send(admin, "🕐 Часовой пояс")
+		send(admin, "Asia/Barnaul")
+		assert.Equal(t, "Asia/Barnaul", mockDB.Cities[1].Timezone)
+	})
+
+	t.Run("Unknown zone keeps waiting", func(t *testing.T) {
+		admin, mockBot, mockDB := setup()
+
+		send(admin, "🕐 Часовой пояс")
+		send(admin, "Сибирь")
+		assert.Equal(t, models.DefaultTimezone, mockDB.Cities[1].Timezone)
+		assert.Equal(t, "city_edit_timezone", admin.stateManager.GetAdminState(12345))
+		assert.Contains(t, mockBot.GetLastMessage().Text, "неизвестный часовой пояс")
+	})
+
+	t.Run("Back returns to city menu", func(t *testing.T) {
+		admin, _, _ := setup()
+
+		send(admin, "🕐 Часовой пояс")
+		send(admin, "🔙 Назад")
+		assert.Equal(t, "city_edit_menu", admin.stateManager.GetAdminState(12345))
+	})
+}
+
+func TestAdminHandlers_ScheduleEditor(t *testing.T) {
+	setup := func() (*AdminHandlers, *MockBot, *MockDatabase) {
+		admin, mockBot, mockDB := CreateTestAdminHandlers()
+		mockDB.AddTestVeterinarian(1, "Иван", "Петров", "+79990000001")
+		mockDB.AddTestClinic(1, "ВетКлиника", "ул. Ленина, 1", 1)
+		mockDB.AddTestClinic(2, "Зоодоктор", "ул. Мира, 5", 1)
+		mockDB.VetClinics[1] = map[int]bool{1: true, 2: true}
+		mockDB.Schedules[1] = &models.Schedule{ID: 1, VetID: 1, ClinicID: 2, DayOfWeek: 1, StartTime: "09:00", EndTime: "13:00", IsAvailable: true}
+		admin.stateManager.SetAdminState(12345, "vet_edit_menu")
+		admin.stateManager.SetAdminData(12345, "vet_edit", &models.VetEditData{VetID: 1})
+		return admin, mockBot, mockDB
+	}
+	send := func(admin *AdminHandlers, text string) {
+		admin.HandleAdminMessage(NewTestUpdate().WithMessage(text, 12345, 12345).Build())
+	}
+
+	t.Run("List grouped by clinic", func(t *testing.T) {
+		admin, mockBot, _ := setup()
+
+		send(admin, "📅 Расписание приемов")
+		assert.Equal(t, "vet_edit_schedules", admin.stateManager.GetAdminState(12345))
+		assert.Contains(t, mockBot.GetLastMessage().Text, "🏥 Зоодоктор:\n1. Пн 09:00-13:00")
+	})
+
+	t.Run("Add slot", func(t *testing.T) {
+		admin, mockBot, mockDB := setup()
+
+		send(admin, "📅 Расписание приемов")
+		send(admin, "➕ Добавить прием")
+		assert.Equal(t, "vet_edit_schedule_add", admin.stateManager.GetAdminState(12345))
+		assert.Contains(t, mockBot.GetLastMessage().Text, "1. ВетКлиника\n2. Зоодоктор")
+
+		send(admin, "пн 14:00-18:00 1")
+		require.Len(t, mockDB.Schedules, 2)
+		schedule := mockDB.Schedules[2]
+		assert.Equal(t, 1, schedule.ClinicID)
+		assert.Equal(t, 1, schedule.DayOfWeek)
+		assert.True(t, schedule.IsAvailable)
+		assert.Equal(t, "vet_edit_schedules", admin.stateManager.GetAdminState(12345))
+		assert.Contains(t, mockBot.GetLastMessage().Text, "🏥 ВетКлиника:\n1. Пн 14:00-18:00")
+	})
+
+	t.Run("Overlap in another clinic is rejected", func(t *testing.T) {
+		admin, mockBot, mockDB := setup()
+
+		send(admin, "📅 Расписание приемов")
+		send(admin, "➕ Добавить прием")
+		send(admin, "Пн 12:00-18:00 1")
+		assert.Len(t, mockDB.Schedules, 1)
+		assert.Equal(t, "vet_edit_schedule_add", admin.stateManager.GetAdminState(12345))
+		assert.Contains(t, mockBot.GetLastMessage().Text, "пересекается с другим приемом врача: Пн 09:00-13:00 (Зоодоктор)")
+
+		// Ночная смена с воскресенья заходит на утро понедельника
+		send(admin, "Вс 22:00-10:00 1")
+		assert.Len(t, mockDB.Schedules, 1)
+	})
+
+	t.Run("Invalid input", func(t *testing.T) {
+		admin, mockBot, mockDB := setup()
+
+		send(admin, "📅 Расписание приемов")
+		send(admin, "➕ Добавить прием")
+		for _, input := range []string{"Пн 14:00-14:00 1", "Чтв 10:00-12:00 1", "Пн 10:00 1", "Пн 14:00-18:00 3", "Пн 14:00-18:00"} {
+			send(admin, input)
+			assert.Contains(t, mockBot.GetLastMessage().Text, "❌", input)
+		}
+		assert.Len(t, mockDB.Schedules, 1)
+	})
+
+	t.Run("Edit, toggle and delete", func(t *testing.T) {
+		admin, mockBot, mockDB := setup()
+
+		send(admin, "📅 Расписание приемов")
+		send(admin, "✏️ Изменить прием")
+		send(admin, "1 Вт 10:00-14:00")
+		assert.Equal(t, 2, mockDB.Schedules[1].DayOfWeek)
+		assert.Equal(t, "10:00", mockDB.Schedules[1].StartTime)
+		assert.Equal(t, 2, mockDB.Schedules[1].ClinicID, "без номера клиника не меняется")
+
+		send(admin, "⏯️ Включить/выключить")
+		send(admin, "1")
+		assert.False(t, mockDB.Schedules[1].IsAvailable)
+		assert.Contains(t, mockBot.GetLastMessage().Text, "1. Вт 10:00-14:00 - выключен")
+
+		// Выключенный прием не мешает добавить пересекающийся, но и включить его обратно уже нельзя
+		send(admin, "➕ Добавить прием")
+		send(admin, "Вт 12:00-18:00 1")
+		require.Len(t, mockDB.Schedules, 2)
+		send(admin, "⏯️ Включить/выключить")
+		send(admin, "2")
+		assert.False(t, mockDB.Schedules[1].IsAvailable)
+		assert.Contains(t, mockBot.GetLastMessage().Text, "пересекается")
+
+		send(admin, "🔙 Назад")
+		send(admin, "🗑️ Удалить прием")
+		send(admin, "2")
+		assert.NotContains(t, mockDB.Schedules, 1)
+		assert.Equal(t, "vet_edit_schedules", admin.stateManager.GetAdminState(12345))
+	})
+
+	t.Run("Back returns to vet menu", func(t *testing.T) {
+		admin, _, _ := setup()
+
+		send(admin, "📅 Расписание приемов")
+		send(admin, "🔙 Назад")
+		assert.Equal(t, "vet_edit_menu", admin.stateManager.GetAdminState(12345))
+	})
+}
+
+func TestAdminHandlers_VetClinicLinks(t *testing.T) {
+	setup := func() (*AdminHandlers, *MockBot, *MockDatabase) {
+		admin, mockBot, mockDB := CreateTestAdminHandlers()
+		mockDB.AddTestVeterinarian(1, "Иван", "Петров", "+79990000001")
+		mockDB.AddTestVeterinarian(2, "Анна", "Смирнова", "+79990000002")
+		mockDB.AddTestVeterinarian(3, "Петр", "Смирнов", "+79990000003")
+		mockDB.AddTestVeterinarian(4, "Ольга", "Козлова", "+79990000004")
+		mockDB.AddTestClinic(1, "ВетКлиника", "ул. Ленина, 1", 1)
+		mockDB.AddTestClinic(2, "Зоодоктор", "ул. Мира, 5", 1)
+		mockDB.VetClinics[1] = map[int]bool{2: true}
+		mockDB.Schedules[1] = &models.Schedule{ID: 1, VetID: 1, ClinicID: 2, DayOfWeek: 1, StartTime: "09:00", EndTime: "13:00", IsAvailable: true}
+		return admin, mockBot, mockDB
+	}
+	send := func(admin *AdminHandlers, text string) {
+		admin.HandleAdminMessage(NewTestUpdate().WithMessage(text, 12345, 12345).Build())
+	}
+	editVet := func(admin *AdminHandlers) {
+		admin.stateManager.SetAdminState(12345, "vet_edit_menu")
+		admin.stateManager.SetAdminData(12345, "vet_edit", &models.VetEditData{VetID: 1})
+	}
+	editClinic := func(admin *AdminHandlers, clinicID int) {
+		admin.stateManager.SetAdminState(12345, "clinic_edit_menu")
+		admin.stateManager.SetAdminData(12345, "clinic_edit", &models.ClinicEditData{ClinicID: clinicID})
+	}
+
+	t.Run("Attach and detach from vet menu", func(t *testing.T) {
+		admin, mockBot, mockDB := setup()
+		editVet(admin)
+
+		send(admin, "🏥 Клиники врача")
+		assert.Equal(t, "vet_edit_clinics", admin.stateManager.GetAdminState(12345))
+		assert.Contains(t, mockBot.GetLastMessage().Text, "1. Зоодоктор - ул. Мира, 5 (приемов: 1)")
+
+		send(admin, "➕ Привязать к клинике")
+		assert.Equal(t, "vet_edit_clinic_add", admin.stateManager.GetAdminState(12345))
+		assert.Contains(t, mockBot.GetLastMessage().Text, "1. ВетКлиника")
+		assert.NotContains(t, mockBot.GetLastMessage().Text, "Зоодоктор", "уже привязанные клиники не предлагаются")
+
+		send(admin, "1")
+		assert.True(t, mockDB.VetClinics[1][1])
+		assert.Equal(t, "vet_edit_clinics", admin.stateManager.GetAdminState(12345))
+
+		// Список клиник по названию: ВетКлиника, Зоодоктор. Отвязка только после подтверждения
+		send(admin, "➖ Отвязать от клиники")
+		send(admin, "2")
+		assert.Equal(t, "vet_edit_clinic_confirm_remove", admin.stateManager.GetAdminState(12345))
+		assert.Contains(t, mockBot.GetLastMessage().Text, "отвязать врача Иван Петров от клиники «Зоодоктор»")
+		assert.Contains(t, mockBot.GetLastMessage().Text, "приемы врача в этой клинике: 1, исключения из расписания: 0")
+		assert.True(t, mockDB.VetClinics[1][2])
+
+		send(admin, "✅ Подтвердить отвязку")
+		assert.False(t, mockDB.VetClinics[1][2])
+		assert.Empty(t, mockDB.Schedules, "приемы в отвязанной клинике удаляются")
+		assert.Equal(t, "vet_edit_clinics", admin.stateManager.GetAdminState(12345))
+
+		send(admin, "🔙 Назад")
+		assert.Equal(t, "vet_edit_menu", admin.stateManager.GetAdminState(12345))
+	})
+
+	t.Run("Invalid number", func(t *testing.T) {
+		admin, mockBot, mockDB := setup()
+		editVet(admin)
+
+		send(admin, "🏥 Клиники врача")
+		send(admin, "➖ Отвязать от клиники")
+		send(admin, "5")
+		assert.Contains(t, mockBot.GetLastMessage().Text, "номер клиники должен быть от 1 до 1")
+		assert.True(t, mockDB.VetClinics[1][2])
+	})
+
+	t.Run("Cancel keeps the link", func(t *testing.T) {
+		admin, mockBot, mockDB := setup()
+		mockDB.Exceptions[1] = &models.ScheduleException{ID: 1, VetID: 1, ClinicID: sql.NullInt64{Int64: 2, Valid: true},
+			DateFrom: time.Now(), DateTo: time.Now()}
+		editVet(admin)
+
+		send(admin, "🏥 Клиники врача")
+		send(admin, "➖ Отвязать от клиники")
+		send(admin, "1")
+		assert.Contains(t, mockBot.GetLastMessage().Text, "исключения из расписания: 1")
+		send(admin, "❌ Отмена")
+		assert.True(t, mockDB.VetClinics[1][2])
+		assert.Len(t, mockDB.Schedules, 1)
+		assert.Len(t, mockDB.Exceptions, 1)
+	})
+
+	t.Run("Vet is no longer linked", func(t *testing.T) {
+		admin, mockBot, mockDB := setup()
+		editVet(admin)
+
+		send(admin, "🏥 Клиники врача")
+		send(admin, "➖ Отвязать от клиники")
+		send(admin, "1")
+		// Пока админ подтверждал, врача отвязали в другом чате
+		delete(mockDB.VetClinics[1], 2)
+		send(admin, "✅ Подтвердить отвязку")
+		assert.Contains(t, mockBot.SentMessages[len(mockBot.SentMessages)-2].Text, "Врач не привязан к этой клинике")
+	})
+
+	t.Run("Clinic card shows roster", func(t *testing.T) {
+		admin, mockBot, mockDB := setup()
+		mockDB.Veterinarians[1].IsActive = false
+
+		admin.stateManager.SetAdminState(12345, "clinic_list")
+		send(admin, "2")
+		assert.Contains(t, mockBot.GetLastMessage().Text, "👨‍⚕️ Врачи: Иван Петров (неактивен)")
+
+		admin.stateManager.SetAdminState(12345, "clinic_list")
+		send(admin, "1")
+		assert.Contains(t, mockBot.GetLastMessage().Text, "👨‍⚕️ Врачи: не привязаны")
+	})
+
+	t.Run("Add and remove vets from clinic menu", func(t *testing.T) {
+		admin, mockBot, mockDB := setup()
+		editClinic(admin, 2)
+
+		send(admin, "👨‍⚕️ Врачи клиники")
+		assert.Equal(t, "clinic_edit_vets", admin.stateManager.GetAdminState(12345))
+		assert.Contains(t, mockBot.GetLastMessage().Text, "1. Иван Петров (ID: 1)")
+
+		// По фамилии нашлось несколько врачей - нужен ID
+		send(admin, "➕ Добавить врача")
+		send(admin, "Смирн")
+		assert.Equal(t, "clinic_edit_vet_add", admin.stateManager.GetAdminState(12345))
+		assert.Contains(t, mockBot.GetLastMessage().Text, "2 - Анна Смирнова")
+		assert.Contains(t, mockBot.GetLastMessage().Text, "3 - Петр Смирнов")
+
+		send(admin, "3")
+		assert.True(t, mockDB.VetClinics[3][2])
+		assert.Equal(t, "clinic_edit_vets", admin.stateManager.GetAdminState(12345))
+
+		send(admin, "➕ Добавить врача")
+		send(admin, "Козлова")
+		assert.True(t, mockDB.VetClinics[4][2])
+
+		send(admin, "➕ Добавить врача")
+		send(admin, "99")
+		assert.Contains(t, mockBot.GetLastMessage().Text, "Врач с ID 99 не найден")
+		send(admin, "🔙 Назад")
+		assert.Equal(t, "clinic_edit_vets", admin.stateManager.GetAdminState(12345))
+
+		// Список врачей по имени: Иван Петров, Ольга Козлова, Петр Смирнов
+		send(admin, "➖ Убрать врача")
+		send(admin, "1")
+		assert.Equal(t, "clinic_edit_vet_confirm_remove", admin.stateManager.GetAdminState(12345))
+		assert.True(t, mockDB.VetClinics[1][2])
+		send(admin, "✅ Подтвердить отвязку")
+		assert.False(t, mockDB.VetClinics[1][2])
+		assert.Empty(t, mockDB.Schedules)
+		assert.Contains(t, mockBot.SentMessages[len(mockBot.SentMessages)-2].Text, "Удалено приемов: 1")
+
+		send(admin, "🔙 Назад")
+		assert.Equal(t, "clinic_edit_menu", admin.stateManager.GetAdminState(12345))
+	})
+
+	t.Run("Schedule in a new clinic links the vet", func(t *testing.T) {
+		admin, _, mockDB := setup()
+		editVet(admin)
+
+		send(admin, "📅 Расписание приемов")
+		send(admin, "➕ Добавить прием")
+		send(admin, "Вт 09:00-13:00 1")
+		require.Len(t, mockDB.Schedules, 2)
+		assert.True(t, mockDB.VetClinics[1][mockDB.Schedules[2].ClinicID])
+	})
+}
+
+func TestAdminHandlers_SpecializationManagement(t *testing.T) {
+	setup := func() (*AdminHandlers, *MockBot, *MockDatabase) {
+		admin, mockBot, mockDB := CreateTestAdminHandlers()
+		mockDB.AddTestSpecialization(1, "Терапевт")
+		mockDB.AddTestSpecialization(2, "терапевт ")
+		mockDB.AddTestSpecialization(3, "Хирург")
+		for id, specIDs := range map[int][]int{1: {1}, 2: {2}, 3: {1, 2}} {
+			mockDB.AddTestVeterinarian(id, "Врач", strconv.Itoa(id), "+7999000000"+strconv.Itoa(id))
+			mockDB.Veterinarians[id].IsActive = true
+			for _, specID := range specIDs {
+				mockDB.Veterinarians[id].Specializations = append(mockDB.Veterinarians[id].Specializations, mockDB.Specializations[specID])
+			}
+		}
+		mockDB.DebugSpecializationVetsCountFunc = func() (map[int]int, error) {
+			counts := make(map[int]int)
+			for _, vet := range mockDB.Veterinarians {
+				for _, spec := range vet.Specializations {
+					if vet.IsActive {
+						counts[spec.ID]++
+					}
+				}
+			}
+			return counts, nil
+		}
+		admin.stateManager.SetAdminState(12345, "main_menu")
+		return admin, mockBot, mockDB
+	}
+	send := func(admin *AdminHandlers, text string) {
+		admin.HandleAdminMessage(NewTestUpdate().WithMessage(text, 12345, 12345).Build())
+	}
+
+	t.Run("List with counts and duplicate hint", func(t *testing.T) {
+		admin, mockBot, _ := setup()
+
+		send(admin, "🎯 Специализации")
+		assert.Equal(t, "spec_management", admin.stateManager.GetAdminState(12345))
+		text := mockBot.GetLastMessage().Text
+		assert.Contains(t, text, "1. Терапевт - врачей: 2")
+		assert.Contains(t, text, "2. Хирург - врачей: 0")
+		assert.Contains(t, text, "3. терапевт  - врачей: 2")
+		assert.Contains(t, text, "⚠️ Похожие названия: «Терапевт», «терапевт »")
+
+		send(admin, "🔙 Назад")
+		assert.Equal(t, "main_menu", admin.stateManager.GetAdminState(12345))
+	})
+
+	t.Run("Create rejects duplicates", func(t *testing.T) {
+		admin, mockBot, mockDB := setup()
+
+		send(admin, "🎯 Специализации")
+		send(admin, "➕ Добавить специализацию")
+		send(admin, "  ХИРУРГ ")
+		assert.Len(t, mockDB.Specializations, 3)
+		assert.Contains(t, mockBot.GetLastMessage().Text, "Специализация «Хирург» уже есть")
+
+		send(admin, "  Кардиолог   ветеринарный ")
+		require.Len(t, mockDB.Specializations, 4)
+		assert.Equal(t, "Кардиолог ветеринарный", mockDB.Specializations[4].Name)
+		assert.Equal(t, "spec_management", admin.stateManager.GetAdminState(12345))
+	})
+
+	t.Run("Rename and describe", func(t *testing.T) {
+		admin, mockBot, mockDB := setup()
+
+		send(admin, "🎯 Специализации")
+		send(admin, "2")
+		assert.Equal(t, "spec_edit_menu", admin.stateManager.GetAdminState(12345))
+		assert.Contains(t, mockBot.GetLastMessage().Text, "🎯 Специализация: Хирург")
+
+		send(admin, "✏️ Переименовать")
+		send(admin, "терапевт")
+		assert.Equal(t, "Хирург", mockDB.Specializations[3].Name, "имя другой специализации занято")
+
+		send(admin, "Хирург-ортопед")
+		assert.Equal(t, "Хирург-ортопед", mockDB.Specializations[3].Name)
+		assert.Equal(t, "spec_edit_menu", admin.stateManager.GetAdminState(12345))
+
+		send(admin, "📝 Изменить описание")
+		send(admin, "Операции на костях и суставах")
+		assert.Equal(t, "Операции на костях и суставах", mockDB.Specializations[3].Description)
+
+		send(admin, "📝 Изменить описание")
+		send(admin, "-")
+		assert.Empty(t, mockDB.Specializations[3].Description)
+	})
+
+	t.Run("Merge duplicate", func(t *testing.T) {
+		admin, mockBot, mockDB := setup()
+
+		send(admin, "🎯 Специализации")
+		send(admin, "3")
+		send(admin, "🔀 Объединить с другой")
+		assert.Equal(t, "spec_merge", admin.stateManager.GetAdminState(12345))
+		assert.Contains(t, mockBot.GetLastMessage().Text, "1. Терапевт - врачей: 2\n2. Хирург - врачей: 0")
+
+		send(admin, "1")
+		assert.Equal(t, "spec_confirm_merge", admin.stateManager.GetAdminState(12345))
+		confirmText := mockBot.GetLastMessage().Text
+		assert.Contains(t, confirmText, "Из: «терапевт »\nВ: «Терапевт»")
+		// Врач 3 уже терапевт, специализацию получит только врач 2
+		assert.Contains(t, confirmText, "Активных врачей, которые получат «Терапевт»: 1")
+		assert.Contains(t, mockDB.Specializations, 2, "до подтверждения ничего не меняется")
+
+		send(admin, "✅ Подтвердить объединение")
+		assert.NotContains(t, mockDB.Specializations, 2)
+		for _, vetID := range []int{1, 2, 3} {
+			var ids []int
+			for _, spec := range mockDB.Veterinarians[vetID].Specializations {
+				ids = append(ids, spec.ID)
+			}
+			assert.Equal(t, []int{1}, ids, "врач %d", vetID)
+		}
+		assert.Contains(t, mockBot.SentMessages[len(mockBot.SentMessages)-2].Text,
+			"«терапевт » объединена с «Терапевт», активных врачей: 3")
+		assert.Equal(t, "spec_management", admin.stateManager.GetAdminState(12345))
+	})
+
+	t.Run("Merge can be cancelled", func(t *testing.T) {
+		admin, _, mockDB := setup()
+
+		send(admin, "🎯 Специализации")
+		send(admin, "3")
+		send(admin, "🔀 Объединить с другой")
+		send(admin, "1")
+		send(admin, "❌ Отмена")
+		assert.Contains(t, mockDB.Specializations, 2)
+		assert.Equal(t, "spec_management", admin.stateManager.GetAdminState(12345))
+		assert.Nil(t, admin.stateManager.GetAdminData(12345, "merge_target_id"))
+	})
+
+	t.Run("Delete with confirmation", func(t *testing.T) {
+		admin, mockBot, mockDB := setup()
+
+		send(admin, "🎯 Специализации")
+		send(admin, "1")
+		send(admin, "🗑️ Удалить специализацию")
+		assert.Contains(t, mockBot.GetLastMessage().Text, "активных: 2")
+
+		send(admin, "❌ Отмена")
+		assert.Contains(t, mockDB.Specializations, 1)
+		assert.Equal(t, "spec_management", admin.stateManager.GetAdminState(12345))
+
+		send(admin, "1")
+		send(admin, "🗑️ Удалить специализацию")
+		send(admin, "✅ Подтвердить удаление")
+		assert.NotContains(t, mockDB.Specializations, 1)
+		assert.Len(t, mockDB.Veterinarians[1].Specializations, 0)
+		assert.Equal(t, "spec_management", admin.stateManager.GetAdminState(12345))
+	})
+}
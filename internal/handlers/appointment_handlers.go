@@ -0,0 +1,665 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drerr0r/vetbot/internal/models"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	// appointmentSlotDuration - длительность одного приема
+	appointmentSlotDuration = 30 * time.Minute
+	// appointmentBookingDays - на сколько дней вперед можно записаться
+	appointmentBookingDays = 14
+	// appointmentMaxDateButtons - максимальное количество дат в меню выбора
+	appointmentMaxDateButtons = 10
+	// appointmentDateLayout - формат даты в callback данных
+	appointmentDateLayout = "20060102"
+)
+
+// errAppointmentDateUnavailable - дата записи в прошлом или за пределами окна записи
+var errAppointmentDateUnavailable = errors.New("appointment date is outside the booking window")
+
+// appointmentDate представляет рабочий день врача в конкретной клинике
+type appointmentDate struct {
+	Date   time.Time
+	Clinic *models.Clinic
+}
+
+// getShortDayName возвращает сокращенное название дня недели (1 - понедельник)
+func getShortDayName(day int) string {
+	days := map[int]string{
+		1: "Пн", 2: "Вт", 3: "Ср", 4: "Чт", 5: "Пт", 6: "Сб", 7: "Вс",
+	}
+	return days[day]
+}
+
+// weekdayNumber переводит time.Weekday в формат расписания (1 - понедельник, 7 - воскресенье)
+func weekdayNumber(t time.Time) int {
+	day := int(t.Weekday())
+	if day == 0 {
+		return 7
+	}
+	return day
+}
+
+// parseClock разбирает время в формате HH:MM в количество минут от начала суток
+func parseClock(value string) (int, error) {
+	return models.ParseClock(value)
+}
+
+// formatClock форматирует количество минут от начала суток в HH:MM
+func formatClock(minutes int) string {
+	return models.FormatClock(minutes)
+}
+
+// buildAppointmentDates возвращает ближайшие даты, в которые врач принимает, с учетом клиники.
+// Исключения убирают даты отпуска и меняют часы приема
+func buildAppointmentDates(schedules []*models.Schedule, exceptions []*models.ScheduleException, now time.Time, days int) []appointmentDate {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	var result []appointmentDate
+	for i := 0; i < days; i++ {
+		date := today.AddDate(0, 0, i)
+		// Клиники приемов этого дня и ночных смен, начавшихся накануне
+		candidates := append(models.EffectiveSchedules(schedules, exceptions, date),
+			models.EffectiveSchedules(schedules, exceptions, date.AddDate(0, 0, -1))...)
+		seen := make(map[int]bool)
+		for _, schedule := range candidates {
+			if seen[schedule.ClinicID] {
+				continue
+			}
+			seen[schedule.ClinicID] = true
+			if len(buildAppointmentSlots(schedules, exceptions, schedule.ClinicID, date, now, nil)) == 0 {
+				continue
+			}
+			result = append(result, appointmentDate{Date: date, Clinic: schedule.Clinic})
+		}
+	}
+	return result
+}
+
+// buildAppointmentSlots нарезает расписание врача в клинике на указанную дату на слоты приема
+// с учетом исключений. Ночная смена дает слоты до полуночи в свой день, а после полуночи -
+// на следующую дату. Прошедшие слоты и уже занятые записи исключаются
+func buildAppointmentSlots(schedules []*models.Schedule, exceptions []*models.ScheduleException, clinicID int, date time.Time, now time.Time, booked []*models.Appointment) []models.AppointmentSlot {
+	bookedTimes := make(map[string]bool)
+	for _, appointment := range booked {
+		if appointment.Status == models.AppointmentStatusBooked {
+			bookedTimes[appointment.StartTime] = true
+		}
+	}
+
+	isToday := date.Year() == now.Year() && date.YearDay() == now.YearDay()
+	nowMinutes := now.Hour()*60 + now.Minute()
+	step := int(appointmentSlotDuration.Minutes())
+
+	slotsByStart := make(map[string]models.AppointmentSlot)
+	addSlots := func(schedule *models.Schedule, start, end int) {
+		for slotStart := start; slotStart+step <= end; slotStart += step {
+			startStr := formatClock(slotStart)
+			if isToday && slotStart <= nowMinutes {
+				continue
+			}
+			if bookedTimes[startStr] {
+				continue
+			}
+			slotsByStart[startStr] = models.AppointmentSlot{
+				VetID:     schedule.VetID,
+				ClinicID:  clinicID,
+				Date:      date,
+				StartTime: startStr,
+				EndTime:   formatClock(slotStart + step),
+			}
+		}
+	}
+
+	for _, schedule := range models.EffectiveSchedules(schedules, exceptions, date) {
+		if schedule.ClinicID != clinicID {
+			continue
+		}
+		start, err := parseClock(schedule.StartTime)
+		if err != nil {
+			continue
+		}
+		end, err := parseClock(schedule.EndTime)
+		if err != nil {
+			continue
+		}
+		// Смена через полночь: в свой день - до конца суток
+		if end <= start {
+			end = 24 * 60
+		}
+		addSlots(schedule, start, end)
+	}
+
+	// Продолжение ночных смен, начавшихся накануне
+	for _, schedule := range models.EffectiveSchedules(schedules, exceptions, date.AddDate(0, 0, -1)) {
+		if schedule.ClinicID != clinicID || !schedule.IsOvernight() {
+			continue
+		}
+		end, err := parseClock(schedule.EndTime)
+		if err != nil {
+			continue
+		}
+		addSlots(schedule, 0, end)
+	}
+
+	slots := make([]models.AppointmentSlot, 0, len(slotsByStart))
+	for _, slot := range slotsByStart {
+		slots = append(slots, slot)
+	}
+	sort.Slice(slots, func(i, j int) bool {
+		return slots[i].StartTime < slots[j].StartTime
+	})
+	return slots
+}
+
+// parseAppointmentCallback разбирает данные callback вида <vetID>_<clinicID>_<YYYYMMDD>[_<HHMM>].
+// Дата возвращается как календарная (в UTC): часовой пояс клиники к ней применяет appointmentDay
+func parseAppointmentCallback(data string) (vetID, clinicID int, date time.Time, startTime string, err error) {
+	parts := strings.Split(data, "_")
+	if len(parts) != 3 && len(parts) != 4 {
+		return 0, 0, time.Time{}, "", fmt.Errorf("invalid appointment callback: %s", data)
+	}
+
+	if vetID, err = strconv.Atoi(parts[0]); err != nil {
+		return 0, 0, time.Time{}, "", err
+	}
+	if clinicID, err = strconv.Atoi(parts[1]); err != nil {
+		return 0, 0, time.Time{}, "", err
+	}
+	if date, err = time.Parse(appointmentDateLayout, parts[2]); err != nil {
+		return 0, 0, time.Time{}, "", err
+	}
+
+	if len(parts) == 4 {
+		if len(parts[3]) != 4 {
+			return 0, 0, time.Time{}, "", fmt.Errorf("invalid appointment time: %s", parts[3])
+		}
+		startTime = parts[3][:2] + ":" + parts[3][2:]
+		if _, err = parseClock(startTime); err != nil {
+			return 0, 0, time.Time{}, "", err
+		}
+	}
+
+	return vetID, clinicID, date, startTime, nil
+}
+
+// formatAppointmentDate форматирует дату записи для пользователя
+func formatAppointmentDate(date time.Time) string {
+	return fmt.Sprintf("%s %s", getShortDayName(weekdayNumber(date)), date.Format("02.01.2006"))
+}
+
+// handleAppointmentCallback показывает ближайшие даты приема врача
+func (h *VetHandlers) handleAppointmentCallback(callback *tgbotapi.CallbackQuery) {
+	vetID, err := strconv.Atoi(strings.TrimPrefix(callback.Data, "appointment_"))
+	if err != nil {
+		ErrorLog.Printf("Error parsing vet ID: %v", err)
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, "Ошибка обработки запроса"))
+		return
+	}
+
+	vet, err := h.db.GetVeterinarianByID(vetID)
+	if err != nil {
+		ErrorLog.Printf("Error getting vet %d: %v", vetID, err)
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, "Врач не найден"))
+		return
+	}
+
+	now := time.Now()
+	availability, err := h.loadVetAvailability(vetID, now)
+	if err != nil {
+		ErrorLog.Printf("Error getting schedules for vet %d: %v", vetID, err)
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, "Ошибка при загрузке расписания"))
+		return
+	}
+
+	dates := buildAppointmentDates(availability.schedules, availability.exceptions, availability.localNow(now), appointmentBookingDays)
+	if len(dates) == 0 {
+		msg := tgbotapi.NewMessage(callback.Message.Chat.ID,
+			fmt.Sprintf("📅 У врача %s %s нет приемов в ближайшие %d дней.\n\nПопробуйте позже или свяжитесь с клиникой по телефону.",
+				vet.FirstName, vet.LastName, appointmentBookingDays))
+		h.bot.Send(msg)
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		return
+	}
+
+	if len(dates) > appointmentMaxDateButtons {
+		dates = dates[:appointmentMaxDateButtons]
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, d := range dates {
+		label := formatAppointmentDate(d.Date)
+		clinicID := 0
+		if d.Clinic != nil {
+			label += " · " + d.Clinic.Name
+			clinicID = d.Clinic.ID
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(label,
+				fmt.Sprintf("appt_date_%d_%d_%s", vetID, clinicID, d.Date.Format(appointmentDateLayout))),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("🔙 К врачу", fmt.Sprintf("vet_details_%d", vetID)),
+	))
+
+	msg := tgbotapi.NewMessage(callback.Message.Chat.ID,
+		fmt.Sprintf("📅 *Запись к врачу* %s %s\n\nВыберите дату и клинику:", escapeMarkdown(vet.FirstName), escapeMarkdown(vet.LastName)))
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	if _, err := h.bot.Send(msg); err != nil {
+		ErrorLog.Printf("Error sending appointment dates: %v", err)
+	}
+
+	h.bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+}
+
+// handleAppointmentDateCallback показывает свободные слоты на выбранную дату
+func (h *VetHandlers) handleAppointmentDateCallback(callback *tgbotapi.CallbackQuery) {
+	vetID, clinicID, day, _, err := parseAppointmentCallback(strings.TrimPrefix(callback.Data, "appt_date_"))
+	if err != nil {
+		ErrorLog.Printf("Error parsing appointment date: %v", err)
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, "Ошибка обработки запроса"))
+		return
+	}
+
+	date, slots, err := h.getFreeAppointmentSlots(vetID, clinicID, day)
+	if errors.Is(err, errAppointmentDateUnavailable) {
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, appointmentDateUnavailableText))
+		return
+	}
+	if err != nil {
+		ErrorLog.Printf("Error getting appointment slots: %v", err)
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, "Ошибка при загрузке расписания"))
+		return
+	}
+
+	h.showAppointmentSlots(callback.Message.Chat.ID, callback.Message.MessageID, vetID, clinicID, date, slots)
+	h.bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+}
+
+// appointmentDateUnavailableText - ответ на устаревшую кнопку с датой вне окна записи
+const appointmentDateUnavailableText = "⚠️ На эту дату записаться нельзя"
+
+// appointmentDay загружает расписание врача и переводит календарную дату из callback в часовой
+// пояс клиники. Даты раньше сегодняшней и позже окна записи по местному времени клиники
+// отклоняются с errAppointmentDateUnavailable
+func (h *VetHandlers) appointmentDay(vetID, clinicID int, day time.Time) (*vetAvailability, time.Time, error) {
+	// Исключения нужны и за предыдущий день - для ночных смен, начавшихся накануне
+	availability, err := h.loadVetAvailability(vetID, day.AddDate(0, 0, -1))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	location := availability.clinicLocation(clinicID)
+	date := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, location)
+	now := time.Now().In(location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+	if date.Before(today) || !date.Before(today.AddDate(0, 0, appointmentBookingDays)) {
+		return nil, time.Time{}, errAppointmentDateUnavailable
+	}
+	return availability, date, nil
+}
+
+// getFreeAppointmentSlots возвращает дату в часовом поясе клиники и свободные слоты врача в клинике на нее
+func (h *VetHandlers) getFreeAppointmentSlots(vetID, clinicID int, day time.Time) (time.Time, []models.AppointmentSlot, error) {
+	availability, date, err := h.appointmentDay(vetID, clinicID, day)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+
+	booked, err := h.db.GetBookedAppointments(vetID, date)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+
+	slots := buildAppointmentSlots(availability.schedules, availability.exceptions, clinicID, date,
+		time.Now().In(date.Location()), booked)
+	return date, slots, nil
+}
+
+// showAppointmentSlots редактирует сообщение, показывая свободное время
+func (h *VetHandlers) showAppointmentSlots(chatID int64, messageID int, vetID, clinicID int, date time.Time, slots []models.AppointmentSlot) {
+	dateStr := date.Format(appointmentDateLayout)
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for _, slot := range slots {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(slot.StartTime,
+			fmt.Sprintf("appt_slot_%d_%d_%s_%s", vetID, clinicID, dateStr, strings.Replace(slot.StartTime, ":", "", 1))))
+		if len(row) == 4 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("🔙 Другая дата", fmt.Sprintf("appointment_%d", vetID)),
+	))
+
+	text := fmt.Sprintf("🕐 *Свободное время на %s*\n\nВыберите удобное время приема:", formatAppointmentDate(date))
+	if len(slots) == 0 {
+		text = fmt.Sprintf("😔 На %s свободного времени нет.\n\nВыберите другую дату.", formatAppointmentDate(date))
+	}
+
+	editMsg := tgbotapi.NewEditMessageTextAndMarkup(chatID, messageID, text, tgbotapi.NewInlineKeyboardMarkup(rows...))
+	editMsg.ParseMode = "Markdown"
+	if _, err := h.bot.Send(editMsg); err != nil {
+		ErrorLog.Printf("Error showing appointment slots: %v", err)
+	}
+}
+
+// handleAppointmentSlotCallback просит подтвердить выбранное время
+func (h *VetHandlers) handleAppointmentSlotCallback(callback *tgbotapi.CallbackQuery) {
+	payload := strings.TrimPrefix(callback.Data, "appt_slot_")
+	vetID, clinicID, day, startTime, err := parseAppointmentCallback(payload)
+	if err != nil || startTime == "" {
+		ErrorLog.Printf("Error parsing appointment slot: %v", err)
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, "Ошибка обработки запроса"))
+		return
+	}
+
+	_, date, err := h.appointmentDay(vetID, clinicID, day)
+	if errors.Is(err, errAppointmentDateUnavailable) {
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, appointmentDateUnavailableText))
+		return
+	}
+	if err != nil {
+		ErrorLog.Printf("Error getting schedules for vet %d: %v", vetID, err)
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, "Ошибка при загрузке расписания"))
+		return
+	}
+
+	vet, err := h.db.GetVeterinarianByID(vetID)
+	if err != nil {
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, "Врач не найден"))
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("📝 *Подтвердите запись*\n\n")
+	sb.WriteString(fmt.Sprintf("👨‍⚕️ Врач: %s %s\n", escapeMarkdown(vet.FirstName), escapeMarkdown(vet.LastName)))
+	if clinic, err := h.db.GetClinicByID(clinicID); err == nil {
+		sb.WriteString(fmt.Sprintf("🏥 Клиника: %s\n", escapeMarkdown(clinic.Name)))
+		sb.WriteString(fmt.Sprintf("📍 Адрес: %s\n", escapeMarkdown(clinic.Address)))
+	}
+	sb.WriteString(fmt.Sprintf("📅 Дата: %s\n", formatAppointmentDate(date)))
+	sb.WriteString(fmt.Sprintf("🕐 Время: %s", startTime))
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ Подтвердить", "appt_confirm_"+payload),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔙 Другое время",
+				fmt.Sprintf("appt_date_%d_%d_%s", vetID, clinicID, date.Format(appointmentDateLayout))),
+		),
+	)
+
+	editMsg := tgbotapi.NewEditMessageTextAndMarkup(callback.Message.Chat.ID, callback.Message.MessageID, sb.String(), keyboard)
+	editMsg.ParseMode = "Markdown"
+	if _, err := h.bot.Send(editMsg); err != nil {
+		ErrorLog.Printf("Error showing appointment confirmation: %v", err)
+	}
+
+	h.bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+}
+
+// handleAppointmentConfirmCallback создает запись на прием
+func (h *VetHandlers) handleAppointmentConfirmCallback(callback *tgbotapi.CallbackQuery) {
+	vetID, clinicID, day, startTime, err := parseAppointmentCallback(strings.TrimPrefix(callback.Data, "appt_confirm_"))
+	if err != nil || startTime == "" {
+		ErrorLog.Printf("Error parsing appointment confirmation: %v", err)
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, "Ошибка обработки запроса"))
+		return
+	}
+
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+
+	// Проверяем, что дата в окне записи, а слот все еще свободен и существует в расписании
+	date, slots, err := h.getFreeAppointmentSlots(vetID, clinicID, day)
+	if errors.Is(err, errAppointmentDateUnavailable) {
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, appointmentDateUnavailableText))
+		return
+	}
+	if err != nil {
+		ErrorLog.Printf("Error getting appointment slots: %v", err)
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, "Ошибка при загрузке расписания"))
+		return
+	}
+
+	var selected *models.AppointmentSlot
+	for i := range slots {
+		if slots[i].StartTime == startTime {
+			selected = &slots[i]
+			break
+		}
+	}
+	if selected == nil {
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, "⚠️ Это время уже недоступно"))
+		h.showAppointmentSlots(chatID, messageID, vetID, clinicID, date, slots)
+		return
+	}
+
+	user, err := h.getOrCreateUser(callback.From)
+	if err != nil {
+		ErrorLog.Printf("Error getting user for appointment: %v", err)
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, "Ошибка при записи"))
+		return
+	}
+
+	appointment := &models.Appointment{
+		UserID:    user.ID,
+		VetID:     vetID,
+		ClinicID:  clinicID,
+		Date:      date,
+		StartTime: selected.StartTime,
+		EndTime:   selected.EndTime,
+		Status:    models.AppointmentStatusBooked,
+		CreatedAt: time.Now(),
+	}
+
+	err = h.db.CreateAppointment(appointment)
+	if errors.Is(err, models.ErrAppointmentSlotTaken) {
+		InfoLog.Printf("Appointment slot %s %s for vet %d is already taken", date.Format("2006-01-02"), startTime, vetID)
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, "⚠️ Это время только что заняли"))
+		if _, slots, err := h.getFreeAppointmentSlots(vetID, clinicID, day); err == nil {
+			h.showAppointmentSlots(chatID, messageID, vetID, clinicID, date, slots)
+		}
+		return
+	}
+	if err != nil {
+		ErrorLog.Printf("Error creating appointment: %v", err)
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, "Ошибка при записи"))
+		return
+	}
+
+	InfoLog.Printf("Appointment %d created for user %d", appointment.ID, user.ID)
+
+	text := fmt.Sprintf("✅ *Вы записаны на прием!*\n\n📅 %s в %s\n\nПосмотреть или отменить запись можно командой /appointments",
+		formatAppointmentDate(date), startTime)
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📋 Мои записи", "appt_list"),
+		),
+	)
+
+	editMsg := tgbotapi.NewEditMessageTextAndMarkup(chatID, messageID, text, keyboard)
+	editMsg.ParseMode = "Markdown"
+	if _, err := h.bot.Send(editMsg); err != nil {
+		ErrorLog.Printf("Error sending appointment confirmation: %v", err)
+	}
+
+	h.bot.Request(tgbotapi.NewCallback(callback.ID, "✅ Запись создана"))
+}
+
+// HandleMyAppointments показывает предстоящие записи пользователя
+func (h *VetHandlers) HandleMyAppointments(update tgbotapi.Update) {
+	var chatID int64
+	var from *tgbotapi.User
+
+	if update.CallbackQuery != nil {
+		chatID = update.CallbackQuery.Message.Chat.ID
+		from = update.CallbackQuery.From
+		h.bot.Request(tgbotapi.NewCallback(update.CallbackQuery.ID, ""))
+	} else if update.Message != nil {
+		chatID = update.Message.Chat.ID
+		from = update.Message.From
+	} else {
+		ErrorLog.Printf("Error: both CallbackQuery and Message are nil")
+		return
+	}
+
+	appointments, err := h.getUserAppointments(from.ID)
+	if err != nil {
+		ErrorLog.Printf("Error getting appointments for user %d: %v", from.ID, err)
+		h.bot.Send(tgbotapi.NewMessage(chatID, "❌ Ошибка при загрузке записей"))
+		return
+	}
+
+	if len(appointments) == 0 {
+		h.bot.Send(tgbotapi.NewMessage(chatID,
+			"📭 У вас нет предстоящих записей.\n\nЧтобы записаться, откройте карточку врача и нажмите «📅 Записаться»."))
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("📋 *Ваши предстоящие записи:*\n\n")
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for i, appointment := range appointments {
+		sb.WriteString(fmt.Sprintf("%d. 📅 %s, %s-%s\n", i+1, formatAppointmentDate(appointment.Date),
+			appointment.StartTime, appointment.EndTime))
+		if appointment.Vet != nil {
+			sb.WriteString(fmt.Sprintf("   👨‍⚕️ %s %s\n", escapeMarkdown(appointment.Vet.FirstName), escapeMarkdown(appointment.Vet.LastName)))
+		}
+		if appointment.Clinic != nil {
+			sb.WriteString(fmt.Sprintf("   🏥 %s, %s\n", escapeMarkdown(appointment.Clinic.Name), escapeMarkdown(appointment.Clinic.Address)))
+		}
+		sb.WriteString("\n")
+
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("❌ Отменить запись %d", i+1),
+				fmt.Sprintf("appt_cancel_%d", appointment.ID)),
+		))
+	}
+
+	msg := tgbotapi.NewMessage(chatID, sb.String())
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	if _, err := h.bot.Send(msg); err != nil {
+		ErrorLog.Printf("Error sending appointments list: %v", err)
+	}
+}
+
+// getUserAppointments возвращает предстоящие записи пользователя по Telegram ID
+func (h *VetHandlers) getUserAppointments(telegramID int64) ([]*models.Appointment, error) {
+	user, err := h.db.GetUserByTelegramID(telegramID)
+	if err != nil {
+		// Пользователь еще не сохранен - значит и записей у него нет
+		return nil, nil
+	}
+
+	appointments, err := h.db.GetUpcomingAppointmentsByUser(user.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(appointments, func(i, j int) bool {
+		if !appointments[i].Date.Equal(appointments[j].Date) {
+			return appointments[i].Date.Before(appointments[j].Date)
+		}
+		return appointments[i].StartTime < appointments[j].StartTime
+	})
+	return appointments, nil
+}
+
+// handleAppointmentCancelCallback просит подтвердить отмену записи
+func (h *VetHandlers) handleAppointmentCancelCallback(callback *tgbotapi.CallbackQuery) {
+	appointmentID, err := strconv.Atoi(strings.TrimPrefix(callback.Data, "appt_cancel_"))
+	if err != nil {
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, "Ошибка обработки запроса"))
+		return
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ Да, отменить", fmt.Sprintf("appt_cancelok_%d", appointmentID)),
+			tgbotapi.NewInlineKeyboardButtonData("🔙 Нет", "appt_list"),
+		),
+	)
+
+	editMsg := tgbotapi.NewEditMessageTextAndMarkup(callback.Message.Chat.ID, callback.Message.MessageID,
+		"⚠️ Вы уверены, что хотите отменить запись?", keyboard)
+	h.bot.Send(editMsg)
+	h.bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+}
+
+// handleAppointmentCancelConfirmCallback отменяет запись пользователя
+func (h *VetHandlers) handleAppointmentCancelConfirmCallback(callback *tgbotapi.CallbackQuery) {
+	appointmentID, err := strconv.Atoi(strings.TrimPrefix(callback.Data, "appt_cancelok_"))
+	if err != nil {
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, "Ошибка обработки запроса"))
+		return
+	}
+
+	user, err := h.db.GetUserByTelegramID(callback.From.ID)
+	if err != nil {
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, "Запись не найдена"))
+		return
+	}
+
+	if err := h.db.CancelAppointment(appointmentID, user.ID); err != nil {
+		ErrorLog.Printf("Error cancelling appointment %d: %v", appointmentID, err)
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, "Не удалось отменить запись"))
+		return
+	}
+
+	InfoLog.Printf("Appointment %d cancelled by user %d", appointmentID, user.ID)
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📋 Мои записи", "appt_list"),
+		),
+	)
+	editMsg := tgbotapi.NewEditMessageTextAndMarkup(callback.Message.Chat.ID, callback.Message.MessageID,
+		"✅ Запись отменена", keyboard)
+	h.bot.Send(editMsg)
+	h.bot.Request(tgbotapi.NewCallback(callback.ID, "Запись отменена"))
+}
+
+// getOrCreateUser возвращает пользователя по данным Telegram, создавая его при необходимости
+func (h *VetHandlers) getOrCreateUser(from *tgbotapi.User) (*models.User, error) {
+	if from == nil {
+		return nil, fmt.Errorf("telegram user is nil")
+	}
+
+	user, err := h.db.GetUserByTelegramID(from.ID)
+	if err == nil {
+		return user, nil
+	}
+
+	user = &models.User{
+		TelegramID: from.ID,
+		Username:   from.UserName,
+		FirstName:  from.FirstName,
+		LastName:   from.LastName,
+		CreatedAt:  time.Now(),
+	}
+	if err := h.db.CreateUser(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
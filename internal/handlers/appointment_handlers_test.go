@@ -0,0 +1,272 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/drerr0r/vetbot/internal/models"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================================
+// ТЕСТЫ ДЛЯ НАРЕЗКИ РАСПИСАНИЯ НА СЛОТЫ
+// ============================================================================
+
+func TestBuildAppointmentSlots(t *testing.T) {
+	// Понедельник
+	date := time.Date(2030, 1, 7, 0, 0, 0, 0, time.Local)
+	schedules := []*models.Schedule{
+		{VetID: 1, ClinicID: 1, DayOfWeek: 1, StartTime: "09:00", EndTime: "11:00", IsAvailable: true},
+		{VetID: 1, ClinicID: 2, DayOfWeek: 1, StartTime: "14:00", EndTime: "15:00", IsAvailable: true},
+	}
+
+	t.Run("Free slots exclude booked time", func(t *testing.T) {
+		booked := []*models.Appointment{
+			{VetID: 1, ClinicID: 1, StartTime: "09:30", Status: models.AppointmentStatusBooked},
+			{VetID: 1, ClinicID: 1, StartTime: "10:00", Status: models.AppointmentStatusCancelled},
+		}
+
+		slots := buildAppointmentSlots(schedules, nil, 1, date, date.AddDate(0, 0, -1), booked)
+
+		var starts []string
+		for _, slot := range slots {
+			starts = append(starts, slot.StartTime)
+		}
+		assert.Equal(t, []string{"09:00", "10:00", "10:30"}, starts)
+		assert.Equal(t, "09:30", slots[0].EndTime)
+	})
+
+	t.Run("Past slots are hidden for today", func(t *testing.T) {
+		now := date.Add(10*time.Hour + 10*time.Minute)
+
+		slots := buildAppointmentSlots(schedules, nil, 1, date, now, nil)
+
+		assert.Len(t, slots, 1)
+		assert.Equal(t, "10:30", slots[0].StartTime)
+	})
+
+	t.Run("Other clinic and day are ignored", func(t *testing.T) {
+		assert.Len(t, buildAppointmentSlots(schedules, nil, 2, date, date.AddDate(0, 0, -1), nil), 2)
+		assert.Empty(t, buildAppointmentSlots(schedules, nil, 1, date.AddDate(0, 0, 1), date.AddDate(0, 0, -1), nil))
+	})
+
+	t.Run("Overnight shift continues after midnight on the next date", func(t *testing.T) {
+		night := []*models.Schedule{
+			{VetID: 1, ClinicID: 1, DayOfWeek: 1, StartTime: "22:00", EndTime: "02:00", IsAvailable: true},
+		}
+
+		slots := buildAppointmentSlots(night, nil, 1, date, date.AddDate(0, 0, -1), nil)
+		require.Len(t, slots, 4)
+		assert.Equal(t, "22:00", slots[0].StartTime)
+		assert.Equal(t, "23:30", slots[3].StartTime)
+		assert.Equal(t, "00:00", slots[3].EndTime)
+
+		// Вторник: хвост смены понедельника
+		tail := buildAppointmentSlots(night, nil, 1, date.AddDate(0, 0, 1), date.AddDate(0, 0, -1), nil)
+		require.Len(t, tail, 4)
+		assert.Equal(t, "00:00", tail[0].StartTime)
+		assert.Equal(t, "01:30", tail[3].StartTime)
+		assert.Equal(t, "02:00", tail[3].EndTime)
+
+		// Отпуск в понедельник убирает и хвост смены во вторник
+		vacation := []*models.ScheduleException{{VetID: 1, DateFrom: date, DateTo: date}}
+		assert.Empty(t, buildAppointmentSlots(night, vacation, 1, date.AddDate(0, 0, 1), date.AddDate(0, 0, -1), nil))
+	})
+}
+
+func TestBuildAppointmentDates(t *testing.T) {
+	clinic := &models.Clinic{ID: 1, Name: "ВетКлиника"}
+	schedules := []*models.Schedule{
+		{VetID: 1, ClinicID: 1, DayOfWeek: 1, StartTime: "09:00", EndTime: "18:00", IsAvailable: true, Clinic: clinic},
+		{VetID: 1, ClinicID: 1, DayOfWeek: 3, StartTime: "09:00", EndTime: "18:00", IsAvailable: false, Clinic: clinic},
+	}
+
+	// Воскресенье
+	now := time.Date(2030, 1, 6, 12, 0, 0, 0, time.Local)
+	dates := buildAppointmentDates(schedules, nil, now, 14)
+
+	assert.Len(t, dates, 2)
+	assert.Equal(t, 7, dates[0].Date.Day())
+	assert.Equal(t, 14, dates[1].Date.Day())
+	assert.Equal(t, "ВетКлиника", dates[0].Clinic.Name)
+
+	// Отпуск 07.01 и прием в среду 09.01 вместо выходного
+	exceptions := []*models.ScheduleException{
+		{VetID: 1, DateFrom: now.AddDate(0, 0, 1), DateTo: now.AddDate(0, 0, 1)},
+		{VetID: 1, ClinicID: sql.NullInt64{Int64: 1, Valid: true}, DateFrom: now.AddDate(0, 0, 3), DateTo: now.AddDate(0, 0, 3),
+			IsAvailable: true, StartTime: "10:00", EndTime: "12:00", Clinic: clinic},
+	}
+	dates = buildAppointmentDates(schedules, exceptions, now, 14)
+
+	require.Len(t, dates, 2)
+	assert.Equal(t, 9, dates[0].Date.Day())
+	assert.Equal(t, 14, dates[1].Date.Day())
+}
+
+func TestParseAppointmentCallback(t *testing.T) {
+	vetID, clinicID, date, startTime, err := parseAppointmentCallback("5_3_20300107_0930")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, vetID)
+	assert.Equal(t, 3, clinicID)
+	assert.Equal(t, "2030-01-07", date.Format("2006-01-02"))
+	assert.Equal(t, "09:30", startTime)
+
+	_, _, _, startTime, err = parseAppointmentCallback("5_3_20300107")
+	assert.NoError(t, err)
+	assert.Empty(t, startTime)
+
+	_, _, _, _, err = parseAppointmentCallback("5_x_20300107")
+	assert.Error(t, err)
+
+	_, _, _, _, err = parseAppointmentCallback("5_3_20300107_99")
+	assert.Error(t, err)
+}
+
+// ============================================================================
+// ТЕСТЫ ДЛЯ СЦЕНАРИЯ ЗАПИСИ
+// ============================================================================
+
+// setupAppointmentTest создает врача с расписанием на дату через неделю
+func setupAppointmentTest() (*VetHandlers, *MockBot, *MockDatabase, time.Time) {
+	handlers, mockBot, mockDB := CreateTestVetHandlers()
+
+	mockDB.Veterinarians[1] = &models.Veterinarian{
+		ID:        sql.NullInt64{Int64: 1, Valid: true},
+		FirstName: "Иван",
+		LastName:  "Петров",
+		IsActive:  true,
+	}
+	mockDB.Clinics[1] = &models.Clinic{ID: 1, Name: "ВетКлиника Центр", Address: "ул. Центральная, 1"}
+
+	now := time.Now()
+	date := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, 7)
+	mockDB.Schedules[1] = &models.Schedule{
+		ID: 1, VetID: 1, ClinicID: 1, DayOfWeek: weekdayNumber(date),
+		StartTime: "09:00", EndTime: "10:00", IsAvailable: true, Clinic: mockDB.Clinics[1],
+	}
+
+	return handlers, mockBot, mockDB, date
+}
+
+func TestAppointmentBookingFlow(t *testing.T) {
+	t.Run("Appointment button shows dates", func(t *testing.T) {
+		handlers, mockBot, _, date := setupAppointmentTest()
+
+		update := NewTestUpdate().WithCallback("appointment_1", 100, 1).Build()
+		handlers.HandleCallback(update)
+
+		message := mockBot.GetLastMessage()
+		assert.NotNil(t, message)
+		assert.Contains(t, message.Text, "Запись к врачу* Иван Петров")
+		keyboard, ok := message.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+		assert.True(t, ok)
+		var callbacks []string
+		for _, row := range keyboard.InlineKeyboard {
+			callbacks = append(callbacks, *row[0].CallbackData)
+		}
+		assert.Contains(t, callbacks, "appt_date_1_1_"+date.Format(appointmentDateLayout))
+		assert.Contains(t, callbacks, "vet_details_1")
+	})
+
+	t.Run("Names are escaped for Markdown", func(t *testing.T) {
+		handlers, mockBot, mockDB, date := setupAppointmentTest()
+		mockDB.Veterinarians[1].LastName = "Петров_Водкин"
+		mockDB.Clinics[1].Name = "Вет*Клиника"
+
+		handlers.HandleCallback(NewTestUpdate().WithCallback("appointment_1", 100, 1).Build())
+		assert.Contains(t, mockBot.GetLastMessage().Text, `Иван Петров\_Водкин`)
+
+		data := fmt.Sprintf("appt_slot_1_1_%s_0900", date.Format(appointmentDateLayout))
+		handlers.HandleCallback(NewTestUpdate().WithCallback(data, 100, 1).Build())
+		confirm := mockBot.GetLastEditedMessage()
+		require.NotNil(t, confirm)
+		assert.Contains(t, confirm.Text, `Врач: Иван Петров\_Водкин`)
+		assert.Contains(t, confirm.Text, `Клиника: Вет\*Клиника`)
+	})
+
+	t.Run("Confirm creates appointment", func(t *testing.T) {
+		handlers, mockBot, mockDB, date := setupAppointmentTest()
+
+		data := fmt.Sprintf("appt_confirm_1_1_%s_0930", date.Format(appointmentDateLayout))
+		handlers.HandleCallback(NewTestUpdate().WithCallback(data, 100, 1).Build())
+
+		assert.Len(t, mockDB.Appointments, 1)
+		appointment := mockDB.Appointments[1]
+		assert.Equal(t, "09:30", appointment.StartTime)
+		assert.Equal(t, "10:00", appointment.EndTime)
+		assert.Equal(t, models.AppointmentStatusBooked, appointment.Status)
+		assert.Contains(t, mockBot.GetLastEditedMessage().Text, "Вы записаны на прием")
+	})
+
+	t.Run("Booked slot cannot be taken twice", func(t *testing.T) {
+		handlers, _, mockDB, date := setupAppointmentTest()
+
+		data := fmt.Sprintf("appt_confirm_1_1_%s_0900", date.Format(appointmentDateLayout))
+		handlers.HandleCallback(NewTestUpdate().WithCallback(data, 100, 1).Build())
+		handlers.HandleCallback(NewTestUpdate().WithCallback(data, 200, 1).Build())
+
+		assert.Len(t, mockDB.Appointments, 1)
+	})
+
+	t.Run("Past date is rejected", func(t *testing.T) {
+		handlers, mockBot, mockDB, date := setupAppointmentTest()
+		past := date.AddDate(0, 0, -14).Format(appointmentDateLayout)
+
+		handlers.HandleCallback(NewTestUpdate().WithCallback("appt_date_1_1_"+past, 100, 1).Build())
+		assert.Nil(t, mockBot.GetLastEditedMessage())
+
+		handlers.HandleCallback(NewTestUpdate().WithCallback("appt_confirm_1_1_"+past+"_0900", 100, 1).Build())
+		assert.Empty(t, mockDB.Appointments)
+	})
+
+	t.Run("Date beyond booking window is rejected", func(t *testing.T) {
+		handlers, mockBot, mockDB, date := setupAppointmentTest()
+		late := date.AddDate(0, 0, appointmentBookingDays).Format(appointmentDateLayout)
+
+		handlers.HandleCallback(NewTestUpdate().WithCallback("appt_date_1_1_"+late, 100, 1).Build())
+		assert.Nil(t, mockBot.GetLastEditedMessage())
+
+		handlers.HandleCallback(NewTestUpdate().WithCallback("appt_confirm_1_1_"+late+"_0900", 100, 1).Build())
+		assert.Empty(t, mockDB.Appointments)
+	})
+
+	t.Run("Slot outside schedule is rejected", func(t *testing.T) {
+		handlers, _, mockDB, date := setupAppointmentTest()
+
+		data := fmt.Sprintf("appt_confirm_1_1_%s_1500", date.Format(appointmentDateLayout))
+		handlers.HandleCallback(NewTestUpdate().WithCallback(data, 100, 1).Build())
+
+		assert.Empty(t, mockDB.Appointments)
+	})
+}
+
+func TestMyAppointmentsAndCancel(t *testing.T) {
+	handlers, mockBot, mockDB, date := setupAppointmentTest()
+
+	data := fmt.Sprintf("appt_confirm_1_1_%s_0900", date.Format(appointmentDateLayout))
+	handlers.HandleCallback(NewTestUpdate().WithCallback(data, 100, 1).Build())
+	assert.Len(t, mockDB.Appointments, 1)
+
+	// Список записей
+	handlers.HandleMyAppointments(NewTestUpdate().WithMessage("/appointments", 100, 100).Build())
+	message := mockBot.GetLastMessage()
+	assert.Contains(t, message.Text, "Ваши предстоящие записи")
+	assert.Contains(t, message.Text, "09:00-09:30")
+
+	// Другой пользователь не может отменить чужую запись
+	mockDB.Users[200] = &models.User{ID: 999, TelegramID: 200}
+	handlers.HandleCallback(NewTestUpdate().WithCallback("appt_cancelok_1", 200, 1).Build())
+	assert.Equal(t, models.AppointmentStatusBooked, mockDB.Appointments[1].Status)
+
+	// Отмена своей записи
+	handlers.HandleCallback(NewTestUpdate().WithCallback("appt_cancelok_1", 100, 1).Build())
+	assert.Equal(t, models.AppointmentStatusCancelled, mockDB.Appointments[1].Status)
+
+	// После отмены список пуст
+	handlers.HandleMyAppointments(NewTestUpdate().WithMessage("/appointments", 100, 100).Build())
+	assert.Contains(t, mockBot.GetLastMessage().Text, "нет предстоящих записей")
+}
@@ -0,0 +1,371 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drerr0r/vetbot/internal/imports"
+	"github.com/drerr0r/vetbot/internal/models"
+	"github.com/xuri/excelize/v2"
+)
+
+// vetSheetHeaders колонки листа "Врачи" - общие для шаблона импорта и выгрузки.
+// Отчество и Активен добавлены после примеров, чтобы старые файлы читались по прежним номерам колонок
+var vetSheetHeaders = []string{
+	"Имя",
+	"Фамилия",
+	"Телефон",
+	"Email",
+	"ОпытРаботы",
+	"Описание",
+	"Город",
+	"Специализации",
+	"КлиникиИРасписание",
+	"ПримерыЗаполнения",
+	"Отчество",
+	"Активен",
+}
+
+// clinicSheetHeaders колонки листа "Клиники" в порядке, который читает CSVImporter.PlanClinics
+var clinicSheetHeaders = []string{
+	"Название",
+	"Город",
+	"Адрес",
+	"Телефон",
+	"Часы работы",
+	"Район",
+	"Станция метро",
+	"Широта",
+	"Долгота",
+}
+
+// citySheetHeaders колонки листа "Города"
+var citySheetHeaders = []string{"Название", "Регион"}
+
+// exportDayNames сокращения дней недели, которые понимает CSVImporter.parseSchedule
+var exportDayNames = map[int]string{
+	1: "пн", 2: "вт", 3: "ср", 4: "чт", 5: "пт", 6: "сб", 7: "вс",
+}
+
+// ExportFile готовый к отправке файл выгрузки
+type ExportFile struct {
+	Name     string
+	Data     []byte
+	Warnings []string // Что не удалось выгрузить так, чтобы файл загрузился обратно
+}
+
+// exportSheet лист выгрузки: название и строки вместе с заголовком
+type exportSheet struct {
+	Name     string
+	FileName string
+	Rows     [][]string
+	Warnings []string
+}
+
+// DirectoryExporter выгружает справочник в формате шаблона импорта,
+// чтобы файл можно было поправить в Excel и загрузить обратно
+type DirectoryExporter struct {
+	db Database
+}
+
+func NewDirectoryExporter(db Database) *DirectoryExporter {
+	return &DirectoryExporter{db: db}
+}
+
+// ExportXLSX создает книгу с листами "Врачи", "Клиники" и "Города".
+// Лист врачей идет первым, как в шаблоне импорта
+func (e *DirectoryExporter) ExportXLSX(now time.Time) (*ExportFile, error) {
+	sheets, err := e.collectSheets()
+	if err != nil {
+		return nil, err
+	}
+
+	f := excelize.NewFile()
+	defer f.Close()
+
+	for i, sheet := range sheets {
+		index, err := f.NewSheet(sheet.Name)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			f.SetActiveSheet(index)
+		}
+
+		for row, values := range sheet.Rows {
+			for col, value := range values {
+				cell, _ := excelize.CoordinatesToCellName(col+1, row+1)
+				// Все значения пишем строками, чтобы телефоны и время не превратились в числа
+				if err := f.SetCellStr(sheet.Name, cell, value); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	if err := f.DeleteSheet("Sheet1"); err != nil {
+		log.Printf("Warning: failed to delete default sheet: %v", err)
+	}
+	setVetSheetColumnWidths(f, sheets[0].Name)
+
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []string
+	for _, sheet := range sheets {
+		warnings = append(warnings, sheet.Warnings...)
+	}
+
+	return &ExportFile{
+		Name:     fmt.Sprintf("справочник_%s.xlsx", now.Format("2006-01-02")),
+		Data:     buf.Bytes(),
+		Warnings: warnings,
+	}, nil
+}
+
+// ExportCSV создает по CSV файлу на каждый лист. Разделитель - точка с запятой,
+// а в названиях файлов есть "врач", "клиник" и "город", по которым импорт определяет тип данных
+func (e *DirectoryExporter) ExportCSV(now time.Time) ([]*ExportFile, error) {
+	sheets, err := e.collectSheets()
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]*ExportFile, 0, len(sheets))
+	for _, sheet := range sheets {
+		var buf bytes.Buffer
+		writer := csv.NewWriter(&buf)
+		writer.Comma = ';'
+		if err := writer.WriteAll(sheet.Rows); err != nil {
+			return nil, err
+		}
+
+		files = append(files, &ExportFile{
+			Name:     fmt.Sprintf("%s_%s.csv", sheet.FileName, now.Format("2006-01-02")),
+			Data:     buf.Bytes(),
+			Warnings: sheet.Warnings,
+		})
+	}
+	return files, nil
+}
+
+// collectSheets загружает справочник и раскладывает его по листам
+func (e *DirectoryExporter) collectSheets() ([]exportSheet, error) {
+	cities, err := e.db.GetAllCities()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка загрузки городов: %w", err)
+	}
+	clinics, err := e.db.GetAllClinics()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка загрузки клиник: %w", err)
+	}
+	vets, err := e.db.GetAllVeterinarians()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка загрузки врачей: %w", err)
+	}
+
+	sort.Slice(cities, func(i, j int) bool { return cities[i].Name < cities[j].Name })
+	sort.Slice(clinics, func(i, j int) bool { return clinics[i].Name < clinics[j].Name })
+	sort.SliceStable(vets, func(i, j int) bool {
+		if vets[i].LastName != vets[j].LastName {
+			return vets[i].LastName < vets[j].LastName
+		}
+		return vets[i].FirstName < vets[j].FirstName
+	})
+
+	cityNames := make(map[int64]string, len(cities))
+	cityRows := [][]string{citySheetHeaders}
+	for _, city := range cities {
+		cityNames[int64(city.ID)] = city.Name
+		cityRows = append(cityRows, []string{city.Name, city.Region})
+	}
+
+	clinicNames := make(map[int]string, len(clinics))
+	clinicRows := [][]string{clinicSheetHeaders}
+	for _, clinic := range clinics {
+		clinicNames[clinic.ID] = clinic.Name
+		clinicRows = append(clinicRows, []string{
+			clinic.Name,
+			cityNames[clinic.CityID.Int64],
+			clinic.Address,
+			nullStringValue(clinic.Phone),
+			nullStringValue(clinic.WorkingHours),
+			nullStringValue(clinic.District),
+			nullStringValue(clinic.MetroStation),
+			formatExportCoordinate(clinic.Latitude.Float64, clinic.Latitude.Valid),
+			formatExportCoordinate(clinic.Longitude.Float64, clinic.Longitude.Valid),
+		})
+	}
+
+	vetRows := [][]string{vetSheetHeaders}
+	var vetWarnings []string
+	for _, vet := range vets {
+		vetID := models.GetVetIDAsIntOrZero(vet)
+
+		specs, err := e.db.GetSpecializationsByVetID(vetID)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка загрузки специализаций врача %d: %w", vetID, err)
+		}
+		specNames := make([]string, 0, len(specs))
+		for _, spec := range specs {
+			specNames = append(specNames, spec.Name)
+		}
+
+		schedules, err := e.db.GetAllSchedulesByVetID(vetID)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка загрузки расписания врача %d: %w", vetID, err)
+		}
+
+		experience := ""
+		if vet.ExperienceYears.Valid {
+			experience = strconv.FormatInt(vet.ExperienceYears.Int64, 10)
+		}
+		cityName := ""
+		if vet.CityID.Valid {
+			cityName = cityNames[vet.CityID.Int64]
+		}
+		active := "да"
+		if !vet.IsActive {
+			active = "нет"
+		}
+
+		clinicSchedules, skipped := formatClinicSchedules(schedules, clinicNames)
+		for _, name := range skipped {
+			vetWarnings = append(vetWarnings, fmt.Sprintf(
+				"Строка %d, %s %s: расписание в клинике «%s» не выгружено - в названии есть «:» или «;»",
+				len(vetRows)+1, vet.FirstName, vet.LastName, name))
+		}
+
+		vetRows = append(vetRows, []string{
+			vet.FirstName,
+			vet.LastName,
+			vet.Phone,
+			nullStringValue(vet.Email),
+			experience,
+			nullStringValue(vet.Description),
+			cityName,
+			strings.Join(specNames, ", "),
+			clinicSchedules,
+			"",
+			nullStringValue(vet.Patronymic),
+			active,
+		})
+	}
+
+	return []exportSheet{
+		{Name: imports.VetsSheetName, FileName: "врачи", Rows: vetRows, Warnings: vetWarnings},
+		{Name: imports.ClinicsSheetName, FileName: "клиники", Rows: clinicRows},
+		{Name: imports.CitiesSheetName, FileName: "города", Rows: cityRows},
+	}, nil
+}
+
+// formatClinicSchedules собирает расписание врача в формат колонки КлиникиИРасписание:
+// "Клиника:пн:9-18,ср:9:30-18;Другая клиника:вт:10-19". Клиники идут по алфавиту, дни - по порядку,
+// выключенные приемы помечаются imports.DisabledScheduleMark. Клиники, название которых импорт
+// в этой колонке не разберет, пропускаются и возвращаются в skipped
+func formatClinicSchedules(schedules []*models.Schedule, clinicNames map[int]string) (value string, skipped []string) {
+	byClinic := make(map[string][]*models.Schedule)
+	for _, schedule := range schedules {
+		name := clinicNames[schedule.ClinicID]
+		if name == "" && schedule.Clinic != nil {
+			name = schedule.Clinic.Name
+		}
+		if name == "" {
+			continue
+		}
+		byClinic[name] = append(byClinic[name], schedule)
+	}
+
+	names := make([]string, 0, len(byClinic))
+	for name := range byClinic {
+		if !imports.IsScheduleClinicName(name) {
+			skipped = append(skipped, name)
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	sort.Strings(skipped)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		clinicSchedules := byClinic[name]
+		sort.SliceStable(clinicSchedules, func(i, j int) bool {
+			if clinicSchedules[i].DayOfWeek != clinicSchedules[j].DayOfWeek {
+				return clinicSchedules[i].DayOfWeek < clinicSchedules[j].DayOfWeek
+			}
+			return clinicSchedules[i].StartTime < clinicSchedules[j].StartTime
+		})
+
+		days := make([]string, 0, len(clinicSchedules))
+		for _, schedule := range clinicSchedules {
+			day, ok := exportDayNames[schedule.DayOfWeek]
+			if !ok {
+				continue
+			}
+			interval := fmt.Sprintf("%s:%s-%s", day,
+				formatExportClock(schedule.StartTime), formatExportClock(schedule.EndTime))
+			if !schedule.IsAvailable {
+				interval += imports.DisabledScheduleMark
+			}
+			days = append(days, interval)
+		}
+		if len(days) > 0 {
+			parts = append(parts, name+":"+strings.Join(days, ","))
+		}
+	}
+
+	return strings.Join(parts, ";"), skipped
+}
+
+// formatExportClock сокращает время до вида "9" или "9:30", как в примерах шаблона
+func formatExportClock(value string) string {
+	minutes, err := models.ParseClock(value)
+	if err != nil {
+		return strings.TrimSpace(value)
+	}
+	if minutes%60 == 0 {
+		return fmt.Sprintf("%d", minutes/60)
+	}
+	return fmt.Sprintf("%d:%02d", minutes/60, minutes%60)
+}
+
+// formatExportCoordinate записывает координату с точкой в качестве разделителя
+func formatExportCoordinate(value float64, valid bool) string {
+	if !valid {
+		return ""
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// setVetSheetColumnWidths настраивает ширину колонок листа врачей
+func setVetSheetColumnWidths(f *excelize.File, sheetName string) {
+	widths := map[string]float64{
+		"A": 15, // Имя
+		"B": 15, // Фамилия
+		"C": 20, // Телефон
+		"D": 25, // Email
+		"E": 12, // Опыт
+		"F": 30, // Описание
+		"G": 15, // Город
+		"H": 20, // Специализации
+		"I": 40, // Клиники и расписание
+		"J": 25, // Примеры
+		"K": 15, // Отчество
+		"L": 10, // Активен
+	}
+
+	for col, width := range widths {
+		if err := f.SetColWidth(sheetName, col, col, width); err != nil {
+			log.Printf("Warning: failed to set column width for %s: %v", col, err)
+		}
+	}
+}
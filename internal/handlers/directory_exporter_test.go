@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/drerr0r/vetbot/internal/imports"
+	"github.com/drerr0r/vetbot/internal/models"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+// setupExportDirectory заполняет мок справочником: врач с двумя клиниками и отключенным приемом
+func setupExportDirectory(mockDB *MockDatabase) {
+	mockDB.AddTestCity(1, "Москва", "Московская область")
+	mockDB.AddTestSpecialization(1, "Терапевт")
+	mockDB.AddTestSpecialization(2, "Хирург")
+	mockDB.AddTestClinic(1, "ВетКлиника Центр", "ул. Ленина, 1", 1)
+	mockDB.AddTestClinic(2, "ВетКлиника Север", "ул. Мира, 5", 1)
+	mockDB.Clinics[1].Latitude = sql.NullFloat64{Float64: 55.7577, Valid: true}
+	mockDB.Clinics[1].Longitude = sql.NullFloat64{Float64: 37.6156, Valid: true}
+
+	mockDB.AddTestVeterinarian(1, "Иван", "Петров", "+79161234567")
+	vet := mockDB.Veterinarians[1]
+	vet.Patronymic = sql.NullString{String: "Сергеевич", Valid: true}
+	vet.IsActive = true
+	vet.CityID = sql.NullInt64{Int64: 1, Valid: true}
+	vet.ExperienceYears = sql.NullInt64{Int64: 10, Valid: true}
+	vet.Specializations = []*models.Specialization{mockDB.Specializations[1], mockDB.Specializations[2]}
+
+	mockDB.Schedules[1] = &models.Schedule{ID: 1, VetID: 1, ClinicID: 1, DayOfWeek: 3, StartTime: "09:30", EndTime: "18:00", IsAvailable: true}
+	mockDB.Schedules[2] = &models.Schedule{ID: 2, VetID: 1, ClinicID: 1, DayOfWeek: 1, StartTime: "09:00", EndTime: "18:00", IsAvailable: true}
+	mockDB.Schedules[3] = &models.Schedule{ID: 3, VetID: 1, ClinicID: 2, DayOfWeek: 2, StartTime: "10:00", EndTime: "19:00", IsAvailable: true}
+	mockDB.Schedules[4] = &models.Schedule{ID: 4, VetID: 1, ClinicID: 2, DayOfWeek: 5, StartTime: "10:00", EndTime: "19:00", IsAvailable: false}
+	mockDB.linkVetClinic(1, 1)
+	mockDB.linkVetClinic(1, 2)
+}
+
+func TestDirectoryExporter_ExportXLSX(t *testing.T) {
+	mockDB := NewMockDatabase()
+	setupExportDirectory(mockDB)
+
+	file, err := NewDirectoryExporter(mockDB).ExportXLSX(time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, "справочник_2026-10-16.xlsx", file.Name)
+
+	f, err := excelize.OpenReader(bytes.NewReader(file.Data))
+	require.NoError(t, err)
+	defer f.Close()
+
+	// Лист врачей первый: импорт без выбора листа читает именно его
+	assert.Equal(t, []string{"Врачи", "Клиники", "Города"}, f.GetSheetList())
+
+	vetRows, err := f.GetRows("Врачи")
+	require.NoError(t, err)
+	require.Len(t, vetRows, 2)
+	assert.Equal(t, vetSheetHeaders, vetRows[0])
+	assert.Equal(t, []string{
+		"Иван", "Петров", "+79161234567", "", "10", "", "Москва", "Терапевт, Хирург",
+		"ВетКлиника Север:вт:10-19,пт:10-19(выкл);ВетКлиника Центр:пн:9-18,ср:9:30-18", "", "Сергеевич", "да",
+	}, vetRows[1])
+
+	clinicRows, err := f.GetRows("Клиники")
+	require.NoError(t, err)
+	require.Len(t, clinicRows, 3)
+	assert.Equal(t, clinicSheetHeaders, clinicRows[0])
+	assert.Equal(t, []string{"ВетКлиника Центр", "Москва", "ул. Ленина, 1", "", "", "", "", "55.7577", "37.6156"}, clinicRows[2])
+
+	cityRows, err := f.GetRows("Города")
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{citySheetHeaders, {"Москва", "Московская область"}}, cityRows)
+}
+
+func TestDirectoryExporter_ExportCSV(t *testing.T) {
+	mockDB := NewMockDatabase()
+	setupExportDirectory(mockDB)
+
+	files, err := NewDirectoryExporter(mockDB).ExportCSV(time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+	assert.Equal(t, "врачи_2026-10-16.csv", files[0].Name)
+	assert.Equal(t, "клиники_2026-10-16.csv", files[1].Name)
+	assert.Equal(t, "города_2026-10-16.csv", files[2].Name)
+
+	reader := csv.NewReader(bytes.NewReader(files[0].Data))
+	reader.Comma = ';'
+	records, err := reader.ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "ВетКлиника Север:вт:10-19,пт:10-19(выкл);ВетКлиника Центр:пн:9-18,ср:9:30-18", records[1][8])
+}
+
+func TestDirectoryExporter_RoundTrip(t *testing.T) {
+	mockDB := NewMockDatabase()
+	setupExportDirectory(mockDB)
+	mockDB.Veterinarians[1].IsActive = false
+
+	files, err := NewDirectoryExporter(mockDB).ExportCSV(time.Now())
+	require.NoError(t, err)
+
+	// Выгрузка, загруженная обратно, не должна ничего менять: ни отчество, ни активность, ни выключенный прием
+	plan, err := imports.NewCSVImporter(mockDB).PlanVeterinarians(bytes.NewReader(files[0].Data), files[0].Name, InfoLog, ErrorLog)
+	require.NoError(t, err)
+	require.Len(t, plan.Rows, 1)
+	assert.Equal(t, models.ImportActionSkip, plan.Rows[0].Action, plan.Rows[0].Changes)
+	assert.Equal(t, "Без изменений", plan.Rows[0].Message)
+
+	// Если в справочнике эти поля с тех пор поменялись, загрузка выгрузки возвращает их
+	mockDB.Veterinarians[1].IsActive = true
+	mockDB.Veterinarians[1].Patronymic = sql.NullString{}
+	mockDB.Schedules[4].IsAvailable = true
+
+	plan, err = imports.NewCSVImporter(mockDB).PlanVeterinarians(bytes.NewReader(files[0].Data), files[0].Name, InfoLog, ErrorLog)
+	require.NoError(t, err)
+	require.Len(t, plan.Rows, 1)
+	assert.Equal(t, []string{
+		"отчество: — → Сергеевич",
+		"активен: да → нет",
+		"расписание ВетКлиника Север пт: 10:00-19:00 → 10:00-19:00(выкл)",
+	}, plan.Rows[0].Changes)
+}
+
+func TestDirectoryExporter_RoundTripScheduleSeparatorsInClinicName(t *testing.T) {
+	mockDB := NewMockDatabase()
+	setupExportDirectory(mockDB)
+	mockDB.Clinics[2].Name = "ВетКлиника: Север; Юг"
+
+	files, err := NewDirectoryExporter(mockDB).ExportCSV(time.Now())
+	require.NoError(t, err)
+
+	// Расписание в такой клинике импорт не разберет, поэтому оно не выгружается, а попадает в замечания
+	reader := csv.NewReader(bytes.NewReader(files[0].Data))
+	reader.Comma = ';'
+	records, err := reader.ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, "ВетКлиника Центр:пн:9-18,ср:9:30-18", records[1][8])
+	assert.Equal(t, []string{
+		"Строка 2, Иван Петров: расписание в клинике «ВетКлиника: Север; Юг» не выгружено - в названии есть «:» или «;»",
+	}, files[0].Warnings)
+
+	// Загрузка выгрузки обратно не ломается и не трогает невыгруженное расписание
+	plan, err := imports.NewCSVImporter(mockDB).PlanVeterinarians(bytes.NewReader(files[0].Data), files[0].Name, InfoLog, ErrorLog)
+	require.NoError(t, err)
+	require.Len(t, plan.Rows, 1)
+	assert.Equal(t, models.ImportActionSkip, plan.Rows[0].Action, plan.Rows[0].Message)
+}
+
+func TestFormatExportClock(t *testing.T) {
+	assert.Equal(t, "9", formatExportClock("09:00"))
+	assert.Equal(t, "9:30", formatExportClock("09:30:00"))
+	assert.Equal(t, "0:05", formatExportClock("00:05"))
+	assert.Equal(t, "утро", formatExportClock(" утро "))
+}
+
+func TestAdminHandlers_HandleExport(t *testing.T) {
+	t.Run("CSV by command", func(t *testing.T) {
+		admin, mockBot, mockDB := CreateTestAdminHandlers()
+		setupExportDirectory(mockDB)
+
+		admin.HandleExport(NewTestUpdate().WithCommand("/export csv", 12345, 12345).Build())
+
+		require.Len(t, mockBot.Documents, 3)
+		document, ok := mockBot.Documents[0].File.(tgbotapi.FileBytes)
+		require.True(t, ok)
+		assert.Equal(t, "врачи_"+time.Now().Format("2006-01-02")+".csv", document.Name)
+	})
+
+	t.Run("XLSX from export menu", func(t *testing.T) {
+		admin, mockBot, mockDB := CreateTestAdminHandlers()
+		setupExportDirectory(mockDB)
+
+		admin.HandleAdminMessage(NewTestUpdate().WithMessage("📤 Экспорт данных", 12345, 12345).Build())
+		admin.HandleAdminMessage(NewTestUpdate().WithMessage("📗 Excel (XLSX)", 12345, 12345).Build())
+
+		require.Len(t, mockBot.Documents, 1)
+		assert.Equal(t, "export_menu", admin.stateManager.GetAdminState(12345))
+	})
+
+	t.Run("Clinic names the import cannot parse are reported", func(t *testing.T) {
+		admin, mockBot, mockDB := CreateTestAdminHandlers()
+		setupExportDirectory(mockDB)
+		mockDB.Clinics[2].Name = "ВетКлиника: Север"
+
+		admin.HandleExport(NewTestUpdate().WithCommand("/export", 12345, 12345).Build())
+
+		require.Len(t, mockBot.Documents, 1)
+		assert.Contains(t, mockBot.GetLastMessage().Text, "клинике «ВетКлиника: Север» не выгружено")
+	})
+
+	t.Run("Unknown format", func(t *testing.T) {
+		admin, mockBot, _ := CreateTestAdminHandlers()
+
+		admin.HandleExport(NewTestUpdate().WithCommand("/export pdf", 12345, 12345).Build())
+
+		assert.Empty(t, mockBot.Documents)
+		assert.Contains(t, mockBot.GetLastMessage().Text, "Неизвестный формат")
+	})
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Dispatcher распределяет обновления по пулу воркеров.
+// Обновления одного чата всегда попадают к одному воркеру и обрабатываются по порядку,
+// а разные чаты обрабатываются параллельно
+type Dispatcher struct {
+	handler UpdateHandler
+	queues  []chan tgbotapi.Update
+	pending int64
+
+	mutex   sync.RWMutex
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+// NewDispatcher создает диспетчер и запускает воркеры.
+// queueSize - емкость очереди каждого воркера; при переполнении Dispatch блокируется
+func NewDispatcher(handler UpdateHandler, workers, queueSize int) *Dispatcher {
+	if workers < 1 {
+		workers = 1
+	}
+	if queueSize < 1 {
+		queueSize = 1
+	}
+
+	d := &Dispatcher{
+		handler: handler,
+		queues:  make([]chan tgbotapi.Update, workers),
+	}
+
+	for i := range d.queues {
+		d.queues[i] = make(chan tgbotapi.Update, queueSize)
+		d.wg.Add(1)
+		go d.worker(i)
+	}
+
+	InfoLog.Printf("Dispatcher started with %d workers (queue size %d)", workers, queueSize)
+	return d
+}
+
+// HandleUpdate ставит обновление в очередь (Dispatcher реализует UpdateHandler)
+func (d *Dispatcher) HandleUpdate(update tgbotapi.Update) {
+	d.Dispatch(update)
+}
+
+// Dispatch ставит обновление в очередь воркера, отвечающего за чат.
+// Возвращает false, если диспетчер уже остановлен
+func (d *Dispatcher) Dispatch(update tgbotapi.Update) bool {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	if d.stopped {
+		ErrorLog.Printf("Dispatcher stopped, update %d dropped", update.UpdateID)
+		return false
+	}
+
+	queue := d.queues[d.shard(update)]
+	atomic.AddInt64(&d.pending, 1)
+
+	select {
+	case queue <- update:
+	default:
+		// Очередь воркера заполнена - ждем, притормаживая прием обновлений
+		InfoLog.Printf("Update queue is full (depth %d), waiting", d.QueueDepth())
+		queue <- update
+	}
+	return true
+}
+
+// QueueDepth возвращает число обновлений, ожидающих обработки или обрабатываемых сейчас
+func (d *Dispatcher) QueueDepth() int {
+	return int(atomic.LoadInt64(&d.pending))
+}
+
+// Workers возвращает число воркеров
+func (d *Dispatcher) Workers() int {
+	return len(d.queues)
+}
+
+// Stop прекращает прием обновлений и ждет обработки уже поставленных в очередь
+func (d *Dispatcher) Stop() {
+	d.mutex.Lock()
+	if d.stopped {
+		d.mutex.Unlock()
+		return
+	}
+	d.stopped = true
+	for _, queue := range d.queues {
+		close(queue)
+	}
+	d.mutex.Unlock()
+
+	d.wg.Wait()
+	InfoLog.Printf("Dispatcher stopped")
+}
+
+// shard выбирает воркер по ID чата (или пользователя, если чата в обновлении нет)
+func (d *Dispatcher) shard(update tgbotapi.Update) int {
+	var key int64
+	if chat := update.FromChat(); chat != nil {
+		key = chat.ID
+	} else if user := update.SentFrom(); user != nil {
+		key = user.ID
+	}
+	return int(uint64(key) % uint64(len(d.queues)))
+}
+
+// worker последовательно обрабатывает обновления своей очереди
+func (d *Dispatcher) worker(index int) {
+	defer d.wg.Done()
+
+	for update := range d.queues[index] {
+		d.process(update)
+		atomic.AddInt64(&d.pending, -1)
+	}
+}
+
+// process обрабатывает одно обновление; паника не должна останавливать воркер
+func (d *Dispatcher) process(update tgbotapi.Update) {
+	defer func() {
+		if r := recover(); r != nil {
+			ErrorLog.Printf("Panic while handling update %d: %v\n%s", update.UpdateID, r, debug.Stack())
+		}
+	}()
+
+	d.handler.HandleUpdate(update)
+}
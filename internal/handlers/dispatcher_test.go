@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingHandler запоминает порядок обработанных обновлений по чатам
+type recordingHandler struct {
+	mutex   sync.Mutex
+	handled map[int64][]int
+	block   map[int64]chan struct{}
+}
+
+func newRecordingHandler() *recordingHandler {
+	return &recordingHandler{
+		handled: make(map[int64][]int),
+		block:   make(map[int64]chan struct{}),
+	}
+}
+
+func (r *recordingHandler) HandleUpdate(update tgbotapi.Update) {
+	chatID := update.Message.Chat.ID
+
+	r.mutex.Lock()
+	block := r.block[chatID]
+	r.mutex.Unlock()
+	if block != nil {
+		<-block
+	}
+
+	if update.Message.Text == "panic" {
+		panic("test panic")
+	}
+
+	r.mutex.Lock()
+	r.handled[chatID] = append(r.handled[chatID], update.UpdateID)
+	r.mutex.Unlock()
+}
+
+func (r *recordingHandler) handledFor(chatID int64) []int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]int(nil), r.handled[chatID]...)
+}
+
+func testChatUpdate(updateID int, chatID int64, text string) tgbotapi.Update {
+	update := NewTestUpdate().WithMessage(text, chatID, chatID).Build()
+	update.UpdateID = updateID
+	return update
+}
+
+func TestDispatcherKeepsPerChatOrder(t *testing.T) {
+	handler := newRecordingHandler()
+	dispatcher := NewDispatcher(handler, 4, 10)
+
+	var expected = map[int64][]int{}
+	for i := 1; i <= 200; i++ {
+		chatID := int64(i%5 + 1)
+		dispatcher.Dispatch(testChatUpdate(i, chatID, "msg"))
+		expected[chatID] = append(expected[chatID], i)
+	}
+	dispatcher.Stop()
+
+	for chatID, ids := range expected {
+		assert.Equal(t, ids, handler.handledFor(chatID), "chat %d", chatID)
+	}
+	assert.Equal(t, 0, dispatcher.QueueDepth())
+}
+
+func TestDispatcherProcessesChatsInParallel(t *testing.T) {
+	handler := newRecordingHandler()
+	release := make(chan struct{})
+	handler.block[1] = release
+
+	dispatcher := NewDispatcher(handler, 2, 10)
+	defer dispatcher.Stop()
+
+	// Чаты 1 и 2 попадают к разным воркерам; чат 1 завис на медленном запросе
+	dispatcher.Dispatch(testChatUpdate(1, 1, "slow"))
+	dispatcher.Dispatch(testChatUpdate(2, 2, "fast"))
+
+	assert.Eventually(t, func() bool {
+		return len(handler.handledFor(2)) == 1
+	}, time.Second, 5*time.Millisecond)
+	assert.Empty(t, handler.handledFor(1))
+	assert.Equal(t, 1, dispatcher.QueueDepth())
+
+	close(release)
+	assert.Eventually(t, func() bool {
+		return dispatcher.QueueDepth() == 0
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []int{1}, handler.handledFor(1))
+}
+
+func TestDispatcherRecoversFromPanic(t *testing.T) {
+	handler := newRecordingHandler()
+	dispatcher := NewDispatcher(handler, 1, 10)
+
+	dispatcher.Dispatch(testChatUpdate(1, 1, "panic"))
+	dispatcher.Dispatch(testChatUpdate(2, 1, "msg"))
+	dispatcher.Stop()
+
+	assert.Equal(t, []int{2}, handler.handledFor(1))
+}
+
+func TestDispatcherStop(t *testing.T) {
+	dispatcher := NewDispatcher(newRecordingHandler(), 2, 1)
+	dispatcher.Stop()
+	dispatcher.Stop()
+
+	assert.False(t, dispatcher.Dispatch(testChatUpdate(1, 1, "msg")))
+	assert.Equal(t, 2, dispatcher.Workers())
+}
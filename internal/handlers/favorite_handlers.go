@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drerr0r/vetbot/internal/models"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// HandleFavorites показывает список избранных врачей пользователя
+func (h *VetHandlers) HandleFavorites(update tgbotapi.Update) {
+	var chatID int64
+	var from *tgbotapi.User
+	messageID := 0
+
+	if update.CallbackQuery != nil {
+		chatID = update.CallbackQuery.Message.Chat.ID
+		messageID = update.CallbackQuery.Message.MessageID
+		from = update.CallbackQuery.From
+		h.bot.Request(tgbotapi.NewCallback(update.CallbackQuery.ID, ""))
+	} else if update.Message != nil {
+		chatID = update.Message.Chat.ID
+		from = update.Message.From
+	} else {
+		ErrorLog.Printf("Error: both CallbackQuery and Message are nil")
+		return
+	}
+
+	h.sendFavoritesList(chatID, messageID, from)
+}
+
+// sendFavoritesList формирует список избранного. Если messageID задан, сообщение редактируется
+func (h *VetHandlers) sendFavoritesList(chatID int64, messageID int, from *tgbotapi.User) {
+	vets, err := h.getUserFavoriteVets(from.ID)
+	if err != nil {
+		ErrorLog.Printf("Error getting favorites for user %d: %v", from.ID, err)
+		h.bot.Send(tgbotapi.NewMessage(chatID, "❌ Ошибка при загрузке избранного"))
+		return
+	}
+
+	var text string
+	var rows [][]tgbotapi.InlineKeyboardButton
+
+	if len(vets) == 0 {
+		text = "⭐ В избранном пока никого нет.\n\nЧтобы добавить врача, откройте его карточку и нажмите «⭐ В избранное»."
+	} else {
+		var sb strings.Builder
+		sb.WriteString("⭐ *Избранные врачи:*\n\n")
+
+		for i, vet := range vets {
+			vetID := models.GetVetIDAsIntOrZero(vet)
+			sb.WriteString(h.formatFavoriteVet(i+1, vet))
+
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("👨‍⚕️ %s %s", vet.FirstName, vet.LastName),
+					fmt.Sprintf("vet_details_%d", vetID)),
+				tgbotapi.NewInlineKeyboardButtonData("❌ Убрать", fmt.Sprintf("fav_list_remove_%d", vetID)),
+			))
+		}
+		text = sb.String()
+	}
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("🏠 Главное меню", "main_menu"),
+	))
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+
+	if messageID != 0 {
+		editMsg := tgbotapi.NewEditMessageText(chatID, messageID, text)
+		editMsg.ParseMode = "Markdown"
+		editMsg.ReplyMarkup = &keyboard
+		if _, err := h.bot.Send(editMsg); err == nil {
+			return
+		}
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = keyboard
+	if _, err := h.bot.Send(msg); err != nil {
+		ErrorLog.Printf("Error sending favorites list: %v", err)
+	}
+}
+
+// formatFavoriteVet форматирует строку списка избранного: ближайший рабочий день и рейтинг
+func (h *VetHandlers) formatFavoriteVet(index int, vet *models.Veterinarian) string {
+	var sb strings.Builder
+	vetID := models.GetVetIDAsIntOrZero(vet)
+
+	sb.WriteString(fmt.Sprintf("%d. 👨‍⚕️ *%s %s*\n", index, escapeMarkdown(vet.FirstName), escapeMarkdown(vet.LastName)))
+
+	now := time.Now()
+	availability, err := h.loadVetAvailability(vetID, now)
+	if err == nil {
+		if nearest, date := h.findNearestWorkingDay(availability, now); nearest != nil {
+			sb.WriteString(fmt.Sprintf("   🕐 Ближайший прием: %s, %s-%s", formatScheduleDate(date, now),
+				nearest.StartTime, nearest.EndTime))
+			if nearest.Clinic != nil && nearest.Clinic.Name != "" {
+				sb.WriteString(fmt.Sprintf(" (%s)", escapeMarkdown(nearest.Clinic.Name)))
+			}
+			sb.WriteString("\n")
+		} else {
+			sb.WriteString("   🕐 Нет приема в ближайшие дни\n")
+		}
+	}
+
+	stats, err := h.db.GetReviewStats(vetID)
+	if err == nil && stats.ApprovedReviews > 0 {
+		sb.WriteString(fmt.Sprintf("   ⭐ Рейтинг: %.1f/5 (%d отзывов)\n", stats.AverageRating, stats.ApprovedReviews))
+	} else {
+		sb.WriteString("   ⭐ Рейтинг: пока нет отзывов\n")
+	}
+
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// getUserFavoriteVets возвращает избранных врачей пользователя по Telegram ID
+func (h *VetHandlers) getUserFavoriteVets(telegramID int64) ([]*models.Veterinarian, error) {
+	user, err := h.db.GetUserByTelegramID(telegramID)
+	if err != nil {
+		// Пользователь еще не сохранен - значит и избранного у него нет
+		return nil, nil
+	}
+
+	vets, err := h.db.GetFavoriteVets(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(vets, func(i, j int) bool {
+		if vets[i].LastName != vets[j].LastName {
+			return vets[i].LastName < vets[j].LastName
+		}
+		return vets[i].FirstName < vets[j].FirstName
+	})
+	return vets, nil
+}
+
+// isFavoriteVet проверяет, добавлен ли врач в избранное пользователем с данным Telegram ID
+func (h *VetHandlers) isFavoriteVet(telegramID int64, vetID int) bool {
+	user, err := h.db.GetUserByTelegramID(telegramID)
+	if err != nil {
+		return false
+	}
+
+	isFavorite, err := h.db.IsFavorite(user.ID, vetID)
+	if err != nil {
+		ErrorLog.Printf("Error checking favorite for user %d: %v", user.ID, err)
+		return false
+	}
+	return isFavorite
+}
+
+// handleFavoriteToggleCallback добавляет или удаляет врача из избранного с карточки врача
+func (h *VetHandlers) handleFavoriteToggleCallback(callback *tgbotapi.CallbackQuery, add bool) {
+	prefix := "unfavorite_"
+	if add {
+		prefix = "favorite_"
+	}
+
+	vetID, err := strconv.Atoi(strings.TrimPrefix(callback.Data, prefix))
+	if err != nil {
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, "Ошибка: неверный ID врача"))
+		return
+	}
+
+	answer, err := h.toggleFavorite(callback.From, vetID, add)
+	if err != nil {
+		ErrorLog.Printf("Error updating favorites for vet %d: %v", vetID, err)
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Ошибка при обновлении избранного"))
+		return
+	}
+	h.bot.Request(tgbotapi.NewCallback(callback.ID, answer))
+
+	// Перерисовываем карточку, чтобы кнопка сменила состояние
+	if err := h.HandleVetDetails(callback.Message.Chat.ID, callback.From.ID, vetID, callback.Message.MessageID); err != nil {
+		ErrorLog.Printf("Error refreshing vet details: %v", err)
+	}
+}
+
+// handleFavoriteListRemoveCallback удаляет врача из избранного прямо из списка
+func (h *VetHandlers) handleFavoriteListRemoveCallback(callback *tgbotapi.CallbackQuery) {
+	vetID, err := strconv.Atoi(strings.TrimPrefix(callback.Data, "fav_list_remove_"))
+	if err != nil {
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, "Ошибка: неверный ID врача"))
+		return
+	}
+
+	answer, err := h.toggleFavorite(callback.From, vetID, false)
+	if err != nil {
+		ErrorLog.Printf("Error removing favorite vet %d: %v", vetID, err)
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Ошибка при обновлении избранного"))
+		return
+	}
+	h.bot.Request(tgbotapi.NewCallback(callback.ID, answer))
+
+	h.sendFavoritesList(callback.Message.Chat.ID, callback.Message.MessageID, callback.From)
+}
+
+// toggleFavorite добавляет или удаляет врача из избранного и возвращает текст ответа
+func (h *VetHandlers) toggleFavorite(from *tgbotapi.User, vetID int, add bool) (string, error) {
+	user, err := h.getOrCreateUser(from)
+	if err != nil {
+		return "", err
+	}
+
+	if add {
+		if _, err := h.db.GetVeterinarianByID(vetID); err != nil {
+			return "", fmt.Errorf("врач %d не найден: %v", vetID, err)
+		}
+		if err := h.db.AddFavorite(user.ID, vetID); err != nil {
+			return "", err
+		}
+		return "⭐ Добавлено в избранное", nil
+	}
+
+	if err := h.db.RemoveFavorite(user.ID, vetID); err != nil {
+		return "", err
+	}
+	return "Удалено из избранного", nil
+}
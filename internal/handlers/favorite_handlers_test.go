@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/drerr0r/vetbot/internal/models"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// ============================================================================
+// ТЕСТЫ ДЛЯ ИЗБРАННОГО
+// ============================================================================
+
+// setupFavoritesTest создает пользователя и врача с расписанием и рейтингом
+func setupFavoritesTest() (*VetHandlers, *MockBot, *MockDatabase) {
+	handlers, mockBot, mockDB := CreateTestVetHandlers()
+
+	mockDB.Users[100] = &models.User{ID: 10, TelegramID: 100}
+	mockDB.Veterinarians[1] = &models.Veterinarian{
+		ID:        sql.NullInt64{Int64: 1, Valid: true},
+		FirstName: "Иван",
+		LastName:  "Петров",
+		IsActive:  true,
+	}
+	mockDB.Clinics[1] = &models.Clinic{ID: 1, Name: "ВетКлиника Центр"}
+	mockDB.Schedules[1] = &models.Schedule{
+		ID: 1, VetID: 1, ClinicID: 1, DayOfWeek: 1,
+		StartTime: "09:00", EndTime: "18:00", IsAvailable: true, Clinic: mockDB.Clinics[1],
+	}
+	mockDB.GetReviewStatsFunc = func(vetID int) (*models.ReviewStats, error) {
+		return &models.ReviewStats{AverageRating: 4.5, ApprovedReviews: 2}, nil
+	}
+
+	return handlers, mockBot, mockDB
+}
+
+// inlineCallbacks возвращает callback данные всех кнопок inline клавиатуры
+func inlineCallbacks(markup *tgbotapi.InlineKeyboardMarkup) []string {
+	var callbacks []string
+	if markup == nil {
+		return callbacks
+	}
+	for _, row := range markup.InlineKeyboard {
+		for _, button := range row {
+			if button.CallbackData != nil {
+				callbacks = append(callbacks, *button.CallbackData)
+			}
+		}
+	}
+	return callbacks
+}
+
+func TestFavoriteToggle(t *testing.T) {
+	t.Run("Add to favorites switches card button", func(t *testing.T) {
+		handlers, mockBot, mockDB := setupFavoritesTest()
+
+		handlers.HandleCallback(NewTestUpdate().WithCallback("favorite_1", 100, 5).Build())
+
+		assert.True(t, mockDB.Favorites[10][1])
+		edited := mockBot.GetLastEditedMessage()
+		assert.NotNil(t, edited)
+		callbacks := inlineCallbacks(edited.ReplyMarkup)
+		assert.Contains(t, callbacks, "unfavorite_1")
+		assert.NotContains(t, callbacks, "favorite_1")
+	})
+
+	t.Run("Remove from favorites", func(t *testing.T) {
+		handlers, mockBot, mockDB := setupFavoritesTest()
+		mockDB.AddFavorite(10, 1)
+
+		handlers.HandleCallback(NewTestUpdate().WithCallback("unfavorite_1", 100, 5).Build())
+
+		assert.False(t, mockDB.Favorites[10][1])
+		assert.Contains(t, inlineCallbacks(mockBot.GetLastEditedMessage().ReplyMarkup), "favorite_1")
+	})
+
+	t.Run("Group chat shows the favorite of the user who opened the card", func(t *testing.T) {
+		handlers, mockBot, mockDB := setupFavoritesTest()
+		mockDB.AddFavorite(10, 1)
+
+		update := NewTestUpdate().WithCallback("vet_details_1", -500, 5).Build()
+		update.CallbackQuery.From.ID = 100
+		handlers.HandleCallback(update)
+
+		assert.Contains(t, inlineCallbacks(mockBot.GetLastEditedMessage().ReplyMarkup), "unfavorite_1")
+	})
+
+	t.Run("Unknown vet is not added", func(t *testing.T) {
+		handlers, _, mockDB := setupFavoritesTest()
+
+		handlers.HandleCallback(NewTestUpdate().WithCallback("favorite_42", 100, 5).Build())
+
+		assert.Empty(t, mockDB.Favorites[10])
+	})
+}
+
+func TestHandleFavorites(t *testing.T) {
+	t.Run("Empty list", func(t *testing.T) {
+		handlers, mockBot, _ := setupFavoritesTest()
+
+		handlers.HandleFavorites(NewTestUpdate().WithMessage("/favorites", 100, 100).Build())
+
+		assert.Contains(t, mockBot.GetLastMessage().Text, "В избранном пока никого нет")
+	})
+
+	t.Run("List shows nearest day and rating", func(t *testing.T) {
+		handlers, mockBot, mockDB := setupFavoritesTest()
+		mockDB.AddFavorite(10, 1)
+
+		handlers.HandleFavorites(NewTestUpdate().WithMessage("/favorites", 100, 100).Build())
+
+		message := mockBot.GetLastMessage()
+		assert.Contains(t, message.Text, "Иван Петров")
+		assert.Regexp(t, `Пн \d{2}\.\d{2}, 09:00-18:00`, message.Text)
+		assert.Contains(t, message.Text, "4.5/5")
+		keyboard, ok := message.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+		assert.True(t, ok)
+		callbacks := inlineCallbacks(&keyboard)
+		assert.Contains(t, callbacks, "vet_details_1")
+		assert.Contains(t, callbacks, "fav_list_remove_1")
+	})
+
+	t.Run("Names with Markdown characters are escaped", func(t *testing.T) {
+		handlers, mockBot, mockDB := setupFavoritesTest()
+		mockDB.Veterinarians[1].LastName = "Петров_2"
+		mockDB.Clinics[1].Name = "Вет*Центр"
+		mockDB.AddFavorite(10, 1)
+
+		handlers.HandleFavorites(NewTestUpdate().WithMessage("/favorites", 100, 100).Build())
+
+		text := mockBot.GetLastMessage().Text
+		assert.Contains(t, text, `Иван Петров\_2`)
+		assert.Contains(t, text, `(Вет\*Центр)`)
+	})
+
+	t.Run("Remove from list edits message", func(t *testing.T) {
+		handlers, mockBot, mockDB := setupFavoritesTest()
+		mockDB.AddFavorite(10, 1)
+
+		handlers.HandleCallback(NewTestUpdate().WithCallback("fav_list_remove_1", 100, 7).Build())
+
+		assert.False(t, mockDB.Favorites[10][1])
+		edited := mockBot.GetLastEditedMessage()
+		assert.Equal(t, 7, edited.MessageID)
+		assert.Contains(t, edited.Text, "В избранном пока никого нет")
+	})
+}
@@ -0,0 +1,448 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/drerr0r/vetbot/internal/imports"
+	"github.com/drerr0r/vetbot/internal/models"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	// importProgressInterval как часто обновлять сообщение с прогрессом импорта
+	importProgressInterval = 2 * time.Second
+	// importJobsListLimit сколько последних задач показывать в списке
+	importJobsListLimit = 10
+)
+
+// importJob задача импорта, которая выполняется в фоне: проверка файла, ожидание
+// подтверждения и применение. Файл и план хранятся только в памяти
+type importJob struct {
+	chatID int64
+	ctx    context.Context
+	cancel context.CancelFunc
+	plan   *imports.ImportPlan // заполняется после проверки файла
+
+	// mutex защищает request, messageID и lastProgress
+	mutex        sync.Mutex
+	request      *models.ImportRequest
+	messageID    int // сообщение с прогрессом, которое редактируется на месте
+	lastProgress time.Time
+}
+
+// startImportJob создает задачу импорта и запускает проверку файла в фоне.
+// load загружает файл уже в фоне
+func (h *AdminHandlers) startImportJob(chatID, userID int64, importType, fileName string, load func() ([]byte, error)) {
+	// Новый файл заменяет проверенный, но еще не подтвержденный
+	h.discardPendingImport(userID)
+
+	request := &models.ImportRequest{
+		Type:     importType,
+		FileName: fileName,
+		UserID:   userID,
+		Status:   models.ImportStatusPending,
+	}
+	if err := h.db.CreateImportRequest(request); err != nil {
+		ErrorLog.Printf("❌ Ошибка создания задачи импорта: %v", err)
+		msg := tgbotapi.NewMessage(chatID, "❌ Не удалось создать задачу импорта, попробуйте позже")
+		h.bot.Send(msg)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &importJob{chatID: chatID, ctx: ctx, cancel: cancel, request: request}
+
+	if sent, err := h.bot.Send(tgbotapi.NewMessage(chatID, formatImportJobStatus(request))); err == nil {
+		job.messageID = sent.MessageID
+	}
+
+	h.jobsMutex.Lock()
+	h.importJobs[request.ID] = job
+	h.jobsMutex.Unlock()
+
+	InfoLog.Printf("📥 Задача импорта #%d: %s из файла '%s'", request.ID, importType, fileName)
+	h.runImportJob(job, func() { h.checkImportFile(job, load) })
+}
+
+// runImportJob выполняет этап задачи в отдельной горутине. Паника на этапе
+// завершает задачу с ошибкой, а не роняет бота
+func (h *AdminHandlers) runImportJob(job *importJob, stage func()) {
+	h.importWG.Add(1)
+	go func() {
+		defer h.importWG.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				ErrorLog.Printf("Panic in import job #%d: %v\n%s", job.request.ID, r, debug.Stack())
+				h.finishImportJob(job, models.ImportStatusFailed, nil, "внутренняя ошибка")
+			}
+		}()
+		stage()
+	}()
+}
+
+// checkImportFile загружает и проверяет файл, затем показывает предпросмотр
+func (h *AdminHandlers) checkImportFile(job *importJob, load func() ([]byte, error)) {
+	h.updateImportJob(job, func(request *models.ImportRequest) {
+		request.Status = models.ImportStatusProcessing
+	})
+
+	data, err := load()
+	if err != nil {
+		ErrorLog.Printf("❌ Ошибка загрузки файла импорта: %v", err)
+		h.finishImportJob(job, models.ImportStatusFailed, nil, fmt.Sprintf("ошибка загрузки файла: %v", err))
+		return
+	}
+
+	importer := imports.NewCSVImporter(h.db).
+		WithContext(job.ctx).
+		WithProgress(func(done, total int) { h.reportImportProgress(job, done, total) })
+
+	var plan *imports.ImportPlan
+	fileName := job.request.FileName
+	switch job.request.Type {
+	case imports.ImportTypeVeterinarians:
+		plan, err = importer.PlanVeterinarians(bytes.NewReader(data), fileName, InfoLog, ErrorLog)
+	case imports.ImportTypeClinics:
+		plan, err = importer.PlanClinics(bytes.NewReader(data), fileName, InfoLog, ErrorLog)
+	case imports.ImportTypeCities:
+		plan, err = importer.PlanCities(bytes.NewReader(data), fileName, InfoLog, ErrorLog)
+	default:
+		err = fmt.Errorf("неизвестный тип импорта %s", job.request.Type)
+	}
+	if errors.Is(err, imports.ErrImportCancelled) {
+		h.finishImportJob(job, models.ImportStatusCancelled, nil, "")
+		return
+	}
+	if err != nil {
+		ErrorLog.Printf("❌ Ошибка проверки файла импорта: %v", err)
+		h.finishImportJob(job, models.ImportStatusFailed, nil, fmt.Sprintf("ошибка проверки файла: %v", err))
+		return
+	}
+
+	h.showImportPreview(job, plan)
+}
+
+// showImportPreview показывает, что сделает импорт. Если изменения есть, задача ждет
+// подтверждения администратора
+func (h *AdminHandlers) showImportPreview(job *importJob, plan *imports.ImportPlan) {
+	// Отмена могла прийти, пока файл проверялся
+	if job.ctx.Err() != nil {
+		h.finishImportJob(job, models.ImportStatusCancelled, nil, "")
+		return
+	}
+
+	userID := job.request.UserID
+	hasErrors := plan.Count(models.ImportActionError) > 0
+
+	text := formatImportPreview(plan)
+	if hasErrors {
+		text += "\n\nФайл со статусом каждой строки и подсвеченными ошибками отправлен ниже - " +
+			"исправьте строки в нем и загрузите снова."
+	}
+
+	if !plan.HasChanges() {
+		h.finishImportJob(job, models.ImportStatusCompleted, plan.Result(), "")
+		h.stateManager.SetAdminState(userID, "import_menu")
+		msg := tgbotapi.NewMessage(job.chatID, text+"\n\nИзменений нет - импортировать нечего.")
+		h.bot.Send(msg)
+		if hasErrors {
+			h.sendImportReport(job.chatID, plan)
+		}
+		return
+	}
+
+	job.plan = plan
+	h.stateManager.SetAdminData(userID, "import_job", job.request.ID)
+	h.stateManager.SetAdminState(userID, "import_confirm")
+	h.updateImportJob(job, func(request *models.ImportRequest) {
+		request.Status = models.ImportStatusReady
+		request.ProcessedRows = plan.TotalRows
+		request.TotalRows = plan.TotalRows
+	})
+	h.editImportJobMessage(job)
+
+	keyboard := tgbotapi.NewReplyKeyboard(
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton("✅ Подтвердить импорт"),
+			tgbotapi.NewKeyboardButton("❌ Отмена"),
+		),
+	)
+
+	msg := tgbotapi.NewMessage(job.chatID, text+"\n\n"+
+		"Строки с ошибками и пропуски импортированы не будут. "+
+		"Изменения применяются одной транзакцией: при сбое или отмене база останется без изменений.\n\n"+
+		"Подтвердить импорт?")
+	msg.ReplyMarkup = keyboard
+	h.bot.Send(msg)
+
+	if hasErrors {
+		h.sendImportReport(job.chatID, plan)
+	}
+}
+
+// applyImportPlan применяет подтвержденный план и сообщает итоги
+func (h *AdminHandlers) applyImportPlan(job *importJob) {
+	importer := imports.NewCSVImporter(h.db).
+		WithContext(job.ctx).
+		WithProgress(func(done, total int) { h.reportImportProgress(job, done, total) })
+
+	result, err := importer.Apply(job.plan, InfoLog, ErrorLog)
+	switch {
+	case errors.Is(err, imports.ErrImportCancelled):
+		h.finishImportJob(job, models.ImportStatusCancelled, nil, "")
+	case err != nil:
+		ErrorLog.Printf("❌ Ошибка применения импорта #%d: %v", job.request.ID, err)
+		h.finishImportJob(job, models.ImportStatusFailed, nil, err.Error())
+		msg := tgbotapi.NewMessage(job.chatID, fmt.Sprintf("❌ Импорт #%d не выполнен: %v", job.request.ID, err))
+		h.bot.Send(msg)
+	default:
+		h.finishImportJob(job, models.ImportStatusCompleted, result, "")
+		msg := tgbotapi.NewMessage(job.chatID, fmt.Sprintf(
+			"✅ Импорт #%d (%s) завершен\n\n"+
+				"📁 Файл: %s\n"+
+				"➕ Создано: %d\n"+
+				"🔄 Обновлено: %d\n"+
+				"⏭️ Пропущено: %d\n"+
+				"❌ Строк с ошибками (не импортированы): %d",
+			job.request.ID, importTypeTitles[job.plan.Type], job.plan.FileName,
+			result.CreatedCount, result.UpdatedCount, result.SkippedCount, result.ErrorCount))
+		h.bot.Send(msg)
+		h.sendImportReport(job.chatID, job.plan)
+	}
+}
+
+// updateImportJob меняет задачу и сохраняет ее в базе
+func (h *AdminHandlers) updateImportJob(job *importJob, change func(request *models.ImportRequest)) {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+
+	change(job.request)
+	if err := h.db.UpdateImportRequest(job.request); err != nil {
+		ErrorLog.Printf("❌ Ошибка сохранения задачи импорта #%d: %v", job.request.ID, err)
+	}
+}
+
+// reportImportProgress обновляет прогресс задачи не чаще importProgressInterval
+func (h *AdminHandlers) reportImportProgress(job *importJob, done, total int) {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+
+	if done < total && !job.lastProgress.IsZero() && time.Since(job.lastProgress) < importProgressInterval {
+		return
+	}
+	job.lastProgress = time.Now()
+
+	job.request.ProcessedRows = done
+	job.request.TotalRows = total
+	if err := h.db.UpdateImportRequest(job.request); err != nil {
+		ErrorLog.Printf("❌ Ошибка сохранения прогресса импорта #%d: %v", job.request.ID, err)
+	}
+	h.editImportJobMessageLocked(job)
+}
+
+// finishImportJob завершает задачу: сохраняет итог и обновляет сообщение с прогрессом
+func (h *AdminHandlers) finishImportJob(job *importJob, status string, result *models.ImportResult, errorText string) {
+	h.updateImportJob(job, func(request *models.ImportRequest) {
+		request.Status = status
+		request.Result = result
+		request.Error = errorText
+		request.FinishedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	})
+	h.editImportJobMessage(job)
+
+	h.jobsMutex.Lock()
+	delete(h.importJobs, job.request.ID)
+	h.jobsMutex.Unlock()
+	job.cancel()
+
+	InfoLog.Printf("🏁 Задача импорта #%d завершена со статусом %s", job.request.ID, status)
+}
+
+func (h *AdminHandlers) editImportJobMessage(job *importJob) {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+	h.editImportJobMessageLocked(job)
+}
+
+// editImportJobMessageLocked показывает текущий статус задачи в сообщении с прогрессом.
+// Вызывать под job.mutex
+func (h *AdminHandlers) editImportJobMessageLocked(job *importJob) {
+	if job.messageID == 0 {
+		return
+	}
+	edit := tgbotapi.NewEditMessageText(job.chatID, job.messageID, formatImportJobStatus(job.request))
+	if _, err := h.bot.Send(edit); err != nil {
+		ErrorLog.Printf("❌ Ошибка обновления прогресса импорта #%d: %v", job.request.ID, err)
+	}
+}
+
+// pendingImportJob возвращает проверенный импорт пользователя, который ждет подтверждения.
+// В сессии хранится только номер задачи: файл и план живут в памяти, после перезапуска файл загружают заново
+func (h *AdminHandlers) pendingImportJob(userID int64) (*importJob, bool) {
+	jobID, ok := h.stateManager.GetAdminData(userID, "import_job").(int)
+	if !ok {
+		return nil, false
+	}
+	return h.importJobByID(jobID)
+}
+
+// importJobByID возвращает выполняющуюся задачу импорта
+func (h *AdminHandlers) importJobByID(jobID int) (*importJob, bool) {
+	h.jobsMutex.Lock()
+	defer h.jobsMutex.Unlock()
+	job, exists := h.importJobs[jobID]
+	return job, exists
+}
+
+// discardExpiredImport отменяет импорт, который ждал подтверждения в истекшей сессии
+func (h *AdminHandlers) discardExpiredImport(session *models.UserSession) {
+	jobID, ok := session.AdminData["import_job"].(int)
+	if !ok {
+		return
+	}
+	if job, exists := h.importJobByID(jobID); exists && h.takeReadyImport(job, models.ImportStatusCancelled) {
+		h.finishImportJob(job, models.ImportStatusCancelled, nil, "")
+	}
+}
+
+// takeReadyImport переводит задачу, которая ждет подтверждения, в статус status.
+// Подтверждение, отмена и очистка истекшей сессии приходят из разных горутин:
+// задачу забирает только первая из них, остальные получают false
+func (h *AdminHandlers) takeReadyImport(job *importJob, status string) bool {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+
+	if job.request.Status != models.ImportStatusReady {
+		return false
+	}
+	job.request.Status = status
+	return true
+}
+
+// discardPendingImport отменяет проверенный, но не подтвержденный импорт пользователя
+func (h *AdminHandlers) discardPendingImport(userID int64) {
+	job, ok := h.pendingImportJob(userID)
+	h.stateManager.ClearAdminDataByKey(userID, "import_job")
+	if ok && h.takeReadyImport(job, models.ImportStatusCancelled) {
+		h.finishImportJob(job, models.ImportStatusCancelled, nil, "")
+	}
+}
+
+// StopImportJobs отменяет выполняющиеся задачи импорта и ждет их завершения.
+// Незавершенные транзакции откатываются
+func (h *AdminHandlers) StopImportJobs() {
+	h.jobsMutex.Lock()
+	for _, job := range h.importJobs {
+		job.cancel()
+	}
+	h.jobsMutex.Unlock()
+
+	h.importWG.Wait()
+}
+
+// HandleImportJobs показывает последние задачи импорта
+func (h *AdminHandlers) HandleImportJobs(update tgbotapi.Update) {
+	requests, err := h.db.GetRecentImportRequests(importJobsListLimit)
+	if err != nil {
+		ErrorLog.Printf("❌ Ошибка загрузки задач импорта: %v", err)
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Ошибка загрузки задач импорта")
+		h.bot.Send(msg)
+		return
+	}
+	if len(requests) == 0 {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "📋 Задач импорта пока нет")
+		h.bot.Send(msg)
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString("📋 Последние задачи импорта:")
+	for _, request := range requests {
+		fmt.Fprintf(&b, "\n\n%s\n🕐 %s", formatImportJobStatus(request), request.CreatedAt.Format("02.01.2006 15:04"))
+		if !request.IsFinished() {
+			fmt.Fprintf(&b, "\nОтменить: /cancel_import %d", request.ID)
+		}
+	}
+
+	// Без Markdown: в названиях файлов и командах есть подчеркивания
+	msg := tgbotapi.NewMessage(update.Message.Chat.ID, b.String())
+	h.bot.Send(msg)
+}
+
+// HandleCancelImport отменяет задачу импорта по команде /cancel_import <номер>
+func (h *AdminHandlers) HandleCancelImport(update tgbotapi.Update) {
+	chatID := update.Message.Chat.ID
+	jobID, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(update.Message.CommandArguments(), "#")))
+	if err != nil || jobID <= 0 {
+		msg := tgbotapi.NewMessage(chatID, "Укажите номер задачи: /cancel_import 12\n\nСписок задач: /imports")
+		h.bot.Send(msg)
+		return
+	}
+
+	job, exists := h.importJobByID(jobID)
+	if !exists {
+		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Задача импорта #%d не выполняется", jobID))
+		h.bot.Send(msg)
+		return
+	}
+
+	if !h.takeReadyImport(job, models.ImportStatusCancelled) {
+		// Фоновый этап увидит отмену, откатит изменения и сам обновит статус
+		job.cancel()
+		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("⛔ Отменяю импорт #%d, изменения не будут сохранены", jobID))
+		h.bot.Send(msg)
+		return
+	}
+
+	// Проверенный файл ждет подтверждения - фоновой работы нет, отменяем сразу
+	ownerID := job.request.UserID
+	if pending, ok := h.pendingImportJob(ownerID); ok && pending == job {
+		h.stateManager.ClearAdminDataByKey(ownerID, "import_job")
+		if h.stateManager.GetAdminState(ownerID) == "import_confirm" {
+			h.stateManager.SetAdminState(ownerID, "import_menu")
+		}
+	}
+	h.finishImportJob(job, models.ImportStatusCancelled, nil, "")
+
+	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("⛔ Импорт #%d отменен", jobID))
+	h.bot.Send(msg)
+}
+
+// formatImportJobStatus описывает состояние задачи импорта одной строкой
+func formatImportJobStatus(request *models.ImportRequest) string {
+	title := fmt.Sprintf("Импорт #%d (%s, файл %s)", request.ID, importTypeTitles[request.Type], request.FileName)
+
+	switch request.Status {
+	case models.ImportStatusPending:
+		return "⏳ " + title + ": в очереди"
+	case models.ImportStatusProcessing:
+		if request.TotalRows == 0 {
+			return "🔄 " + title + ": обработка"
+		}
+		return fmt.Sprintf("🔄 %s: обработано %d/%d", title, request.ProcessedRows, request.TotalRows)
+	case models.ImportStatusReady:
+		return "📋 " + title + ": файл проверен, ждет подтверждения"
+	case models.ImportStatusCompleted:
+		if request.Result == nil {
+			return "✅ " + title + ": завершен"
+		}
+		return fmt.Sprintf("✅ %s: завершен. Создано: %d, обновлено: %d, пропущено: %d, ошибок: %d",
+			title, request.Result.CreatedCount, request.Result.UpdatedCount,
+			request.Result.SkippedCount, request.Result.ErrorCount)
+	case models.ImportStatusFailed:
+		return "❌ " + title + ": " + request.Error
+	case models.ImportStatusCancelled:
+		return "⛔ " + title + ": отменен, изменения не сохранены"
+	default:
+		return title + ": " + request.Status
+	}
+}
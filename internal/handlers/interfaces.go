@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"database/sql"
+	"time"
 
 	"github.com/drerr0r/vetbot/internal/models"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
@@ -24,12 +25,11 @@ type Database interface {
 	GetAllClinics() ([]*models.Clinic, error)
 	GetSchedulesByVetID(vetID int) ([]*models.Schedule, error)
 	GetSpecializationsByVetID(vetID int) ([]*models.Specialization, error)
-	FindAvailableVets(criteria *models.SearchCriteria) ([]*models.Veterinarian, error)
+	SearchVets(criteria *models.SearchCriteria) ([]*models.Veterinarian, error)
 	GetAllVeterinarians() ([]*models.Veterinarian, error)
 	GetVeterinarianByID(id int) (*models.Veterinarian, error)
 	GetClinicByID(id int) (*models.Clinic, error)
 	SpecializationExists(id int) (bool, error)
-	AddMissingColumns() error
 	CreateReview(review *models.Review) error
 	GetReviewByID(reviewID int) (*models.Review, error)
 	GetApprovedReviewsByVet(vetID int) ([]*models.Review, error)
@@ -43,7 +43,6 @@ type Database interface {
 
 	// Новые методы для расширенного поиска
 	GetClinicsByCity(cityID int) ([]*models.Clinic, error)
-	FindVetsByCity(criteria *models.SearchCriteria) ([]*models.Veterinarian, error)
 	GetCitiesByRegion(region string) ([]*models.City, error)
 	SearchCities(queryStr string) ([]*models.City, error)
 
@@ -95,4 +94,65 @@ type Database interface {
 	GetUserByID(userID int) (*models.User, error)
 
 	GetAllActiveVeterinarians() ([]*models.Veterinarian, error)
+
+	// Методы для записи на прием
+	CreateAppointment(appointment *models.Appointment) error
+	GetBookedAppointments(vetID int, date time.Time) ([]*models.Appointment, error)
+	GetUpcomingAppointmentsByUser(userID int, now time.Time) ([]*models.Appointment, error)
+	CancelAppointment(appointmentID int, userID int) error
+
+	// Методы для избранного
+	AddFavorite(userID int, vetID int) error
+	RemoveFavorite(userID int, vetID int) error
+	IsFavorite(userID int, vetID int) (bool, error)
+	GetFavoriteVets(userID int) ([]*models.Veterinarian, error)
+
+	// Методы для поиска клиник по геопозиции
+	FindNearestClinics(latitude, longitude float64, limit int) ([]*models.ClinicDistance, error)
+	GetClinicSchedulesByDate(clinicID int, date time.Time) ([]*models.Schedule, error)
+	UpdateClinicCoordinates(clinicID int, latitude, longitude sql.NullFloat64) error
+
+	// Методы для задач импорта
+	CreateImportRequest(request *models.ImportRequest) error
+	UpdateImportRequest(request *models.ImportRequest) error
+	GetRecentImportRequests(limit int) ([]*models.ImportRequest, error)
+
+	// Методы для исключений из расписания
+	CreateScheduleException(exception *models.ScheduleException) error
+	DeleteScheduleException(id int) error
+	GetScheduleExceptionsByVetID(vetID int, from time.Time) ([]*models.ScheduleException, error)
+
+	// Связи врачей с клиниками
+	GetClinicsByVetID(vetID int) ([]*models.Clinic, error)
+	AddVetToClinic(vetID int, clinicID int) error
+	RemoveVetFromClinic(vetID int, clinicID int) error
+	GetVetsByClinic(clinicID int) ([]*models.Veterinarian, error)
+
+	// Методы для поиска врачей по ФИО
+	SearchVeterinariansByName(query string, limit int) ([]*models.Veterinarian, error)
+	// GetVeterinariansByIDs возвращает врачей в порядке ids для страницы результатов поиска
+	GetVeterinariansByIDs(ids []int) ([]*models.Veterinarian, error)
+
+	// Методы для управления справочником через админский API
+	SetVeterinarianSpecializations(vetID int, specIDs []int) error
+	UpdateSpecialization(spec *models.Specialization) error
+	DeleteSpecialization(id int) error
+	MergeSpecializations(sourceID int, targetID int) error
+	GetScheduleByID(id int) (*models.Schedule, error)
+	GetAllSchedulesByVetID(vetID int) ([]*models.Schedule, error)
+	// GetWeekSchedulesByVetID - приемы на ближайшие 7 дней с учетом исключений, только в активных клиниках
+	GetWeekSchedulesByVetID(vetID int) ([]*models.Schedule, error)
+	CreateSchedule(schedule *models.Schedule) error
+	UpdateSchedule(schedule *models.Schedule) error
+	DeleteSchedule(id int) error
+}
+
+// StateStorage хранилище сессий пользователей для StateManager
+type StateStorage interface {
+	// LoadSession возвращает сессию пользователя или nil, если ее нет
+	LoadSession(userID int64) (*models.UserSession, error)
+	SaveSession(session *models.UserSession) error
+	DeleteSession(userID int64) error
+	// ListIdleSessions возвращает сессии без активности с момента before
+	ListIdleSessions(before time.Time) ([]*models.UserSession, error)
 }
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/drerr0r/vetbot/internal/models"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// nearestClinicsLimit сколько ближайших клиник показывать
+const nearestClinicsLimit = 5
+
+// nearestButtonText кнопка постоянной клавиатуры, отправляющая геопозицию
+const nearestButtonText = "📍 Рядом со мной"
+
+// HandleNearestPrompt объясняет, как отправить геопозицию для поиска ближайших клиник
+func (h *VetHandlers) HandleNearestPrompt(update tgbotapi.Update) {
+	var chatID int64
+
+	if update.CallbackQuery != nil {
+		chatID = update.CallbackQuery.Message.Chat.ID
+		h.bot.Request(tgbotapi.NewCallback(update.CallbackQuery.ID, ""))
+	} else if update.Message != nil {
+		chatID = update.Message.Chat.ID
+	} else {
+		ErrorLog.Printf("Error: both CallbackQuery and Message are nil")
+		return
+	}
+
+	msg := tgbotapi.NewMessage(chatID,
+		"📍 *Клиники рядом*\n\n"+
+			"Нажмите кнопку «"+nearestButtonText+"» внизу экрана или отправьте геопозицию через 📎 → «Геопозиция».\n\n"+
+			"Я покажу ближайшие клиники и врачей, которые принимают в них сегодня.")
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = h.createPersistentKeyboard()
+
+	if _, err := h.bot.Send(msg); err != nil {
+		ErrorLog.Printf("Error sending nearest clinics prompt: %v", err)
+	}
+}
+
+// HandleNearestClinics показывает ближайшие к геопозиции пользователя клиники и врачей, принимающих сегодня
+func (h *VetHandlers) HandleNearestClinics(update tgbotapi.Update) {
+	if update.Message == nil || update.Message.Location == nil {
+		ErrorLog.Printf("HandleNearestClinics called without location")
+		return
+	}
+
+	chatID := update.Message.Chat.ID
+	location := update.Message.Location
+	InfoLog.Printf("Searching nearest clinics for %.5f, %.5f", location.Latitude, location.Longitude)
+
+	h.stateManager.PushState(update.Message.From.ID, "main_menu")
+
+	clinics, err := h.db.FindNearestClinics(location.Latitude, location.Longitude, nearestClinicsLimit)
+	if err != nil {
+		ErrorLog.Printf("Error finding nearest clinics: %v", err)
+		h.bot.Send(tgbotapi.NewMessage(chatID, "❌ Ошибка при поиске ближайших клиник"))
+		return
+	}
+
+	if len(clinics) == 0 {
+		msg := tgbotapi.NewMessage(chatID,
+			"😔 Не удалось найти клиники рядом: у клиник пока не указаны координаты.\n\nПопробуйте поиск по клиникам или по городу.")
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("🏥 Все клиники", "main_clinics"),
+				tgbotapi.NewInlineKeyboardButtonData("🏙️ Поиск по городу", "main_city"),
+			),
+		)
+		h.bot.Send(msg)
+		return
+	}
+
+	now := time.Now()
+
+	var sb strings.Builder
+	var rows [][]tgbotapi.InlineKeyboardButton
+	sb.WriteString("📍 *Ближайшие клиники:*\n\n")
+
+	for i, item := range clinics {
+		sb.WriteString(h.formatNearestClinic(i+1, item, now))
+
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(
+				fmt.Sprintf("🏥 %s (%s)", item.Clinic.Name, models.FormatDistance(item.DistanceKm)),
+				fmt.Sprintf("search_clinic_%d", item.Clinic.ID)),
+		))
+	}
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("🏠 Главное меню", "main_menu"),
+	))
+
+	msg := tgbotapi.NewMessage(chatID, sb.String())
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+
+	if _, err := h.bot.Send(msg); err != nil {
+		ErrorLog.Printf("Error sending nearest clinics: %v", err)
+	}
+}
+
+// formatNearestClinic форматирует клинику в списке ближайших вместе с врачами, принимающими сегодня.
+// "Сегодня" определяется по часовому поясу города клиники
+func (h *VetHandlers) formatNearestClinic(index int, item *models.ClinicDistance, now time.Time) string {
+	var sb strings.Builder
+	clinic := item.Clinic
+	today := now.In(clinic.Location())
+
+	sb.WriteString(fmt.Sprintf("%d. 🏥 *%s* — %s\n", index, escapeMarkdown(clinic.Name), models.FormatDistance(item.DistanceKm)))
+	sb.WriteString(fmt.Sprintf("   📍 %s\n", escapeMarkdown(clinic.Address)))
+	if clinic.MetroStation.Valid && clinic.MetroStation.String != "" {
+		sb.WriteString(fmt.Sprintf("   🚇 %s\n", escapeMarkdown(clinic.MetroStation.String)))
+	}
+	if clinic.Phone.Valid && clinic.Phone.String != "" {
+		sb.WriteString(fmt.Sprintf("   📞 %s\n", escapeMarkdown(clinic.Phone.String)))
+	}
+
+	schedules, err := h.db.GetClinicSchedulesByDate(clinic.ID, today)
+	if err != nil {
+		ErrorLog.Printf("Error getting today's schedules for clinic %d: %v", clinic.ID, err)
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	if len(schedules) == 0 {
+		sb.WriteString("   👨‍⚕️ Сегодня приема нет\n\n")
+		return sb.String()
+	}
+
+	// Группируем интервалы по врачам, сохраняя порядок по времени начала
+	var order []int
+	hours := make(map[int][]string)
+	names := make(map[int]string)
+	for _, schedule := range schedules {
+		if _, exists := hours[schedule.VetID]; !exists {
+			order = append(order, schedule.VetID)
+			if schedule.Vet != nil {
+				names[schedule.VetID] = fmt.Sprintf("%s %s", escapeMarkdown(schedule.Vet.FirstName), escapeMarkdown(schedule.Vet.LastName))
+			}
+		}
+		hours[schedule.VetID] = append(hours[schedule.VetID],
+			fmt.Sprintf("%s-%s", schedule.StartTime, schedule.EndTime))
+	}
+
+	sb.WriteString("   👨‍⚕️ Сегодня принимают:\n")
+	for _, vetID := range order {
+		sb.WriteString(fmt.Sprintf("   • %s: %s\n", names[vetID], strings.Join(hours[vetID], ", ")))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
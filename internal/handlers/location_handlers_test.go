@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/drerr0r/vetbot/internal/models"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================================
+// ТЕСТЫ ДЛЯ ПОИСКА БЛИЖАЙШИХ КЛИНИК
+// ============================================================================
+
+// setupNearestClinics заполняет мок клиниками в Москве и Петербурге и врачом, принимающим сегодня
+// по московскому времени - клиники без города живут в часовом поясе по умолчанию
+func setupNearestClinics(mockDB *MockDatabase) {
+	coordinates := func(value float64) sql.NullFloat64 {
+		return sql.NullFloat64{Float64: value, Valid: true}
+	}
+
+	mockDB.Clinics[1] = &models.Clinic{ID: 1, Name: "ВетКлиника Центр", Address: "ул. Центральная, д. 1",
+		IsActive: true, Latitude: coordinates(55.7577), Longitude: coordinates(37.6156)}
+	mockDB.Clinics[2] = &models.Clinic{ID: 2, Name: "ВетКлиника Петербург", Address: "Невский пр-т, д. 100",
+		IsActive: true, Latitude: coordinates(59.9326), Longitude: coordinates(30.3497)}
+	mockDB.Clinics[3] = &models.Clinic{ID: 3, Name: "ВетКлиника Север", Address: "ул. Северная, д. 25",
+		IsActive: true, Latitude: coordinates(55.8547), Longitude: coordinates(37.4761)}
+	mockDB.Clinics[4] = &models.Clinic{ID: 4, Name: "Закрытая клиника", Address: "ул. Тверская, д. 2",
+		IsActive: false, Latitude: coordinates(55.7580), Longitude: coordinates(37.6150)}
+	mockDB.Clinics[5] = &models.Clinic{ID: 5, Name: "Клиника без координат", Address: "ул. Новая, д. 3",
+		IsActive: true}
+
+	mockDB.Veterinarians[1] = &models.Veterinarian{
+		ID: sql.NullInt64{Int64: 1, Valid: true}, FirstName: "Иван", LastName: "Петров", IsActive: true,
+	}
+	today := weekdayNumber(time.Now().In(models.DefaultLocation()))
+	mockDB.Schedules[1] = &models.Schedule{ID: 1, VetID: 1, ClinicID: 1, DayOfWeek: today,
+		StartTime: "09:00", EndTime: "13:00", IsAvailable: true}
+	mockDB.Schedules[2] = &models.Schedule{ID: 2, VetID: 1, ClinicID: 1, DayOfWeek: today,
+		StartTime: "14:00", EndTime: "18:00", IsAvailable: true}
+	mockDB.Schedules[3] = &models.Schedule{ID: 3, VetID: 1, ClinicID: 3, DayOfWeek: today%7 + 1,
+		StartTime: "10:00", EndTime: "19:00", IsAvailable: true}
+}
+
+func TestHandleNearestClinics(t *testing.T) {
+	t.Run("Clinics sorted by distance with today's vets", func(t *testing.T) {
+		handlers, mockBot, mockDB := CreateTestVetHandlers()
+		setupNearestClinics(mockDB)
+
+		// Красная площадь
+		handlers.HandleNearestClinics(NewTestUpdate().WithLocation(55.7539, 37.6208, 100, 100).Build())
+
+		message := mockBot.GetLastMessage()
+		require.NotNil(t, message)
+		assert.Contains(t, message.Text, "Ближайшие клиники")
+		assert.Contains(t, message.Text, "Иван Петров: 09:00-13:00, 14:00-18:00")
+		assert.Contains(t, message.Text, "Сегодня приема нет")
+		assert.NotContains(t, message.Text, "Закрытая клиника")
+		assert.NotContains(t, message.Text, "Клиника без координат")
+
+		keyboard, ok := message.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+		require.True(t, ok)
+		assert.Equal(t, []string{"search_clinic_1", "search_clinic_3", "search_clinic_2", "main_menu"},
+			inlineCallbacks(&keyboard))
+	})
+
+	t.Run("Names with Markdown characters are escaped", func(t *testing.T) {
+		handlers, mockBot, mockDB := CreateTestVetHandlers()
+		setupNearestClinics(mockDB)
+		mockDB.Clinics[1].Name = "Вет_Центр"
+		mockDB.Clinics[1].Address = "ул. [Центральная], д. 1"
+		mockDB.Veterinarians[1].LastName = "Петров*"
+
+		handlers.HandleNearestClinics(NewTestUpdate().WithLocation(55.7539, 37.6208, 100, 100).Build())
+
+		text := mockBot.GetLastMessage().Text
+		assert.Contains(t, text, `*Вет\_Центр*`)
+		assert.Contains(t, text, `ул. \[Центральная], д. 1`)
+		assert.Contains(t, text, `Иван Петров\*: 09:00-13:00`)
+	})
+
+	t.Run("No clinics with coordinates", func(t *testing.T) {
+		handlers, mockBot, mockDB := CreateTestVetHandlers()
+		mockDB.Clinics[1] = &models.Clinic{ID: 1, Name: "ВетКлиника Центр", IsActive: true}
+
+		handlers.HandleNearestClinics(NewTestUpdate().WithLocation(55.7539, 37.6208, 100, 100).Build())
+
+		assert.Contains(t, mockBot.GetLastMessage().Text, "Не удалось найти клиники рядом")
+	})
+
+	t.Run("Location message is routed to nearest search", func(t *testing.T) {
+		mainHandler, mockBot := CreateTestMainHandlers()
+		setupNearestClinics(mainHandler.db.(*MockDatabase))
+
+		mainHandler.HandleUpdate(NewTestUpdate().WithLocation(59.9343, 30.3351, 200, 200).Build())
+
+		message := mockBot.GetLastMessage()
+		require.NotNil(t, message)
+		keyboard, ok := message.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+		require.True(t, ok)
+		assert.Equal(t, "search_clinic_2", inlineCallbacks(&keyboard)[0])
+	})
+
+	t.Run("Nearest command shows prompt", func(t *testing.T) {
+		mainHandler, mockBot := CreateTestMainHandlers()
+
+		mainHandler.HandleUpdate(NewTestUpdate().WithCommand("/nearest", 200, 200).Build())
+
+		assert.Contains(t, mockBot.GetLastMessage().Text, "Клиники рядом")
+	})
+}
+
+func TestAdminClinicCoordinates(t *testing.T) {
+	const adminID = 12345
+
+	// openCoordinatesEditor открывает редактирование координат клиники 1 от имени администратора
+	openCoordinatesEditor := func(t *testing.T) (*MainHandler, *MockBot, *MockDatabase) {
+		mainHandler, mockBot := CreateTestMainHandlers()
+		mockDB := mainHandler.db.(*MockDatabase)
+		mockDB.Clinics[1] = &models.Clinic{ID: 1, Name: "ВетКлиника Центр", Address: "ул. Центральная, д. 1", IsActive: true}
+
+		update := NewTestUpdate().WithMessage("📌 Координаты", adminID, adminID).Build()
+		mainHandler.adminHandlers.showClinicEditMenu(update, mockDB.Clinics[1])
+		mainHandler.HandleUpdate(update)
+		require.True(t, mainHandler.adminHandlers.IsAwaitingLocation(adminID))
+		return mainHandler, mockBot, mockDB
+	}
+
+	t.Run("Shared location sets coordinates", func(t *testing.T) {
+		mainHandler, mockBot, mockDB := openCoordinatesEditor(t)
+
+		mainHandler.HandleUpdate(NewTestUpdate().WithLocation(55.7577, 37.6156, adminID, adminID).Build())
+
+		assert.Equal(t, 55.7577, mockDB.Clinics[1].Latitude.Float64)
+		assert.Equal(t, 37.6156, mockDB.Clinics[1].Longitude.Float64)
+		assert.False(t, mainHandler.adminHandlers.IsAwaitingLocation(adminID))
+		assert.Contains(t, mockBot.GetLastMessage().Text, "Координаты: 55.757700, 37.615600")
+	})
+
+	t.Run("Coordinates as text", func(t *testing.T) {
+		mainHandler, _, mockDB := openCoordinatesEditor(t)
+
+		mainHandler.HandleUpdate(NewTestUpdate().WithMessage("59,9326; 30,3497", adminID, adminID).Build())
+
+		assert.True(t, mockDB.Clinics[1].HasCoordinates())
+		assert.Equal(t, 59.9326, mockDB.Clinics[1].Latitude.Float64)
+	})
+
+	t.Run("Invalid text keeps waiting", func(t *testing.T) {
+		mainHandler, mockBot, mockDB := openCoordinatesEditor(t)
+
+		mainHandler.HandleUpdate(NewTestUpdate().WithMessage("95, 37", adminID, adminID).Build())
+
+		assert.False(t, mockDB.Clinics[1].HasCoordinates())
+		assert.True(t, mainHandler.adminHandlers.IsAwaitingLocation(adminID))
+		assert.Contains(t, mockBot.GetLastMessage().Text, "широта должна быть от -90 до 90")
+	})
+
+	t.Run("Dash clears coordinates", func(t *testing.T) {
+		mainHandler, _, mockDB := openCoordinatesEditor(t)
+		mockDB.Clinics[1].Latitude = sql.NullFloat64{Float64: 55.7577, Valid: true}
+		mockDB.Clinics[1].Longitude = sql.NullFloat64{Float64: 37.6156, Valid: true}
+
+		mainHandler.HandleUpdate(NewTestUpdate().WithMessage("-", adminID, adminID).Build())
+
+		assert.False(t, mockDB.Clinics[1].HasCoordinates())
+	})
+}
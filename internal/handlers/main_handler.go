@@ -1,23 +1,13 @@
 package handlers
 
 import (
-	"database/sql"
-	"encoding/csv"
 	"fmt"
-	"io"
-	"net/http"
-	"os"
-	"path/filepath"
-	"regexp"
 	"strconv"
 	"strings"
-	"time"
 
-	"github.com/drerr0r/vetbot/internal/models"
 	"github.com/drerr0r/vetbot/pkg/utils"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
-	"github.com/xuri/excelize/v2"
 )
 
 // MainHandler обрабатывает все входящие обновления
@@ -32,7 +22,12 @@ type MainHandler struct {
 }
 
 func NewMainHandler(bot BotAPI, db Database, config *utils.Config) *MainHandler {
-	stateManager := NewStateManager()
+	return NewMainHandlerWithStorage(bot, db, config, NewMemoryStateStorage())
+}
+
+// NewMainHandlerWithStorage создает MainHandler, сохраняющий сессии пользователей в указанном хранилище
+func NewMainHandlerWithStorage(bot BotAPI, db Database, config *utils.Config, storage StateStorage) *MainHandler {
+	stateManager := NewStateManagerWithStorage(storage)
 
 	// Сначала создаем ReviewHandlers
 	reviewHandlers := NewReviewHandlers(bot, db, config.AdminIDs, stateManager)
@@ -57,6 +52,9 @@ func NewMainHandler(bot BotAPI, db Database, config *utils.Config) *MainHandler
 func (h *MainHandler) HandleUpdate(update tgbotapi.Update) {
 	InfoLog.Printf("Received update")
 
+	// Отмечаем активность пользователя (и сбрасываем сессию, если она истекла)
+	h.touchSession(update)
+
 	// Обрабатываем callback queries (нажатия на inline кнопки)
 	if update.CallbackQuery != nil {
 		InfoLog.Printf("Callback query: %s", update.CallbackQuery.Data)
@@ -86,6 +84,12 @@ func (h *MainHandler) HandleUpdate(update tgbotapi.Update) {
 		return
 	}
 
+	// Геопозиция: поиск ближайших клиник или координаты клиники в админке
+	if update.Message.Location != nil {
+		h.handleLocation(update)
+		return
+	}
+
 	if update.Message.Text == "" {
 		InfoLog.Printf("Text is empty")
 		return
@@ -100,8 +104,10 @@ func (h *MainHandler) HandleUpdate(update tgbotapi.Update) {
 	// ИСПРАВЛЕНИЕ: Администраторы видят главное меню, если явно не в админ-режиме
 	userState := h.stateManager.GetUserState(update.Message.From.ID)
 
-	// Если пользователь администратор И находится в явном админ-режиме
-	if isAdmin && h.isInExplicitAdminMode(userState) {
+	// Если пользователь администратор И находится в явном админ-режиме или в открытой админ-панели.
+	// Команды обрабатываются как обычно, чтобы /start и /help работали из любого состояния
+	inAdminPanel := !update.Message.IsCommand() && h.adminHandlers.HasActiveSession(update.Message.From.ID)
+	if isAdmin && (h.isInExplicitAdminMode(userState) || inAdminPanel) {
 		InfoLog.Printf("Redirecting to admin handlers (explicit admin mode)")
 		h.adminHandlers.HandleAdminMessage(update)
 		return
@@ -151,6 +157,7 @@ func (h *MainHandler) handleCommand(update tgbotapi.Update, isAdmin bool) {
 	switch command {
 	case "start":
 		InfoLog.Printf("Executing /start")
+		h.adminHandlers.EndSession(update.Message.From.ID)
 		h.vetHandlers.HandleStart(update)
 	case "specializations":
 		InfoLog.Printf("Executing /specializations")
@@ -167,6 +174,18 @@ func (h *MainHandler) handleCommand(update tgbotapi.Update, isAdmin bool) {
 	case "help":
 		InfoLog.Printf("Executing /help")
 		h.vetHandlers.HandleHelp(update)
+	case "appointments":
+		InfoLog.Printf("Executing /appointments")
+		h.vetHandlers.HandleMyAppointments(update)
+	case "favorites":
+		InfoLog.Printf("Executing /favorites")
+		h.vetHandlers.HandleFavorites(update)
+	case "nearest":
+		InfoLog.Printf("Executing /nearest")
+		h.vetHandlers.HandleNearestPrompt(update)
+	case "filters":
+		InfoLog.Printf("Executing /filters")
+		h.vetHandlers.HandleSearchWizard(update)
 	case "test":
 		InfoLog.Printf("Executing /test")
 		h.vetHandlers.HandleTest(update)
@@ -184,6 +203,33 @@ func (h *MainHandler) handleCommand(update tgbotapi.Update, isAdmin bool) {
 			InfoLog.Printf("Executing /stats")
 			h.adminHandlers.HandleStats(update)
 		}
+	case "export":
+		if isAdmin {
+			InfoLog.Printf("Executing /export")
+			h.adminHandlers.HandleExport(update)
+		} else {
+			InfoLog.Printf("Export access denied for user %d", update.Message.From.ID)
+			msg := tgbotapi.NewMessage(update.Message.Chat.ID, "У вас нет прав администратора")
+			h.bot.Send(msg)
+		}
+	case "imports":
+		if isAdmin {
+			InfoLog.Printf("Executing /imports")
+			h.adminHandlers.HandleImportJobs(update)
+		} else {
+			InfoLog.Printf("Imports access denied for user %d", update.Message.From.ID)
+			msg := tgbotapi.NewMessage(update.Message.Chat.ID, "У вас нет прав администратора")
+			h.bot.Send(msg)
+		}
+	case "cancel_import":
+		if isAdmin {
+			InfoLog.Printf("Executing /cancel_import")
+			h.adminHandlers.HandleCancelImport(update)
+		} else {
+			InfoLog.Printf("Cancel import access denied for user %d", update.Message.From.ID)
+			msg := tgbotapi.NewMessage(update.Message.Chat.ID, "У вас нет прав администратора")
+			h.bot.Send(msg)
+		}
 	case "debug":
 		if isAdmin {
 			InfoLog.Printf("Executing /debug")
@@ -231,6 +277,7 @@ func (h *MainHandler) handleTextMessage(update tgbotapi.Update) {
 		InfoLog.Printf("Main menu command detected for user %d", userID)
 		// Сбрасываем состояние админ-режима
 		h.stateManager.ClearUserState(userID)
+		h.adminHandlers.EndSession(userID)
 		h.vetHandlers.HandleStart(update)
 		return
 	case "ℹ️ Помощь", "Помощь", "Справка":
@@ -253,6 +300,19 @@ func (h *MainHandler) handleTextMessage(update tgbotapi.Update) {
 		InfoLog.Printf("Search by time command detected for user %d", userID)
 		h.vetHandlers.HandleSearch(update)
 		return
+	case "⭐ Избранное", "Избранное":
+		InfoLog.Printf("Favorites command detected for user %d", userID)
+		h.vetHandlers.HandleFavorites(update)
+		return
+	case "📋 Мои записи", "Мои записи":
+		InfoLog.Printf("Appointments command detected for user %d", userID)
+		h.vetHandlers.HandleMyAppointments(update)
+		return
+	case nearestButtonText, "Рядом со мной":
+		// Клиенты без поддержки кнопки геопозиции присылают ее текст
+		InfoLog.Printf("Nearest clinics command detected for user %d", userID)
+		h.vetHandlers.HandleNearestPrompt(update)
+		return
 	}
 
 	// ПЕРВОЕ: Обработка выхода из админки из ЛЮБОГО состояния
@@ -321,12 +381,34 @@ func (h *MainHandler) handleTextMessage(update tgbotapi.Update) {
 		return
 	}
 
+	// Вне сценариев свободный текст считаем поиском врача по ФИО
+	if isNameQuery(text) {
+		InfoLog.Printf("Name search for user %d: '%s'", userID, text)
+		h.vetHandlers.HandleNameSearch(update, text)
+		return
+	}
+
 	// Для обычных пользователей показываем справку
 	msg := tgbotapi.NewMessage(chatID,
 		"Я понимаю только команды. Используйте /help для списка доступных команд.")
 	h.bot.Send(msg)
 }
 
+// handleLocation обрабатывает геопозицию: администратор может задавать координаты клиники,
+// остальные получают список ближайших клиник
+func (h *MainHandler) handleLocation(update tgbotapi.Update) {
+	userID := update.Message.From.ID
+	InfoLog.Printf("Location received from user %d", userID)
+
+	if h.isAdmin(userID) && h.adminHandlers.IsAwaitingLocation(userID) {
+		InfoLog.Printf("Redirecting location to admin handlers (clinic coordinates)")
+		h.adminHandlers.HandleAdminMessage(update)
+		return
+	}
+
+	h.vetHandlers.HandleNearestClinics(update)
+}
+
 // handleBackCommand обрабатывает команду "Назад"
 func (h *MainHandler) handleBackCommand(update tgbotapi.Update) {
 	userID := update.Message.From.ID
@@ -365,7 +447,6 @@ func (h *MainHandler) handleBackCommand(update tgbotapi.Update) {
 // handleDocument обрабатывает загружаемые документы (CSV/Excel для импорта)
 func (h *MainHandler) handleDocument(update tgbotapi.Update) {
 	fileName := update.Message.Document.FileName
-	fileID := update.Message.Document.FileID
 
 	InfoLog.Printf("Received document: %s", fileName)
 
@@ -386,501 +467,8 @@ func (h *MainHandler) handleDocument(update tgbotapi.Update) {
 		return
 	}
 
-	// Определяем тип импорта по имени файла
-	var importType string
-	if strings.Contains(strings.ToLower(fileName), "город") {
-		importType = "cities"
-	} else if strings.Contains(strings.ToLower(fileName), "врач") {
-		importType = "veterinarians"
-	} else if strings.Contains(strings.ToLower(fileName), "клиник") {
-		importType = "clinics"
-	} else {
-		msg := tgbotapi.NewMessage(update.Message.Chat.ID,
-			"📥 Файл получен. Укажите тип импорта:\n\n"+
-				"• Для городов: файл должен содержать 'город' в названии\n"+
-				"• Для врачей: файл должен содержать 'врач' в названии\n"+
-				"• Для клиник: файл должен содержать 'клиник' в названии")
-		h.bot.Send(msg)
-		return
-	}
-
-	// Отправляем сообщение о начале обработки
-	msg := tgbotapi.NewMessage(update.Message.Chat.ID,
-		fmt.Sprintf("📥 Файл '%s' получен. Начинаю обработку...", fileName))
-	h.bot.Send(msg)
-
-	// Скачиваем файл
-	filePath, err := h.downloadFile(fileID, fileName)
-	if err != nil {
-		h.sendErrorMessage(update.Message.Chat.ID, fmt.Sprintf("Ошибка скачивания файла: %v", err))
-		return
-	}
-	defer os.Remove(filePath) // Удаляем временный файл после обработки
-
-	// Обрабатываем файл в зависимости от типа
-	var result string
-	switch importType {
-	case "veterinarians":
-		result, err = h.importVeterinarians(filePath, fileName)
-	case "cities":
-		result, err = h.importCities(filePath, fileName)
-	case "clinics":
-		result, err = h.importClinics(filePath, fileName)
-	}
-
-	if err != nil {
-		h.sendErrorMessage(update.Message.Chat.ID, fmt.Sprintf("Ошибка импорта: %v", err))
-		return
-	}
-
-	// Отправляем результат
-	msg = tgbotapi.NewMessage(update.Message.Chat.ID, result)
-	h.bot.Send(msg)
-}
-
-// Функция для скачивания файла
-func (h *MainHandler) downloadFile(fileID string, fileName string) (string, error) {
-	fileConfig := tgbotapi.FileConfig{FileID: fileID}
-	file, err := h.bot.GetFile(fileConfig)
-	if err != nil {
-		return "", fmt.Errorf("не удалось получить файл: %v", err)
-	}
-
-	// Создаем временную директорию если нет
-	tempDir := "temp"
-	if _, err := os.Stat(tempDir); os.IsNotExist(err) {
-		if err := os.Mkdir(tempDir, 0755); err != nil {
-			return "", fmt.Errorf("ошибка создания временной директории: %v", err)
-		}
-	}
-
-	// Скачиваем файл
-	filePath := filepath.Join(tempDir, fileName)
-
-	// ИСПРАВЛЕНО: Используем метод GetToken() вместо прямого доступа к полю
-	token := h.bot.GetToken()
-	url := fmt.Sprintf("https://api.telegram.org/file/bot%s/%s", token, file.FilePath)
-
-	resp, err := http.Get(url)
-	if err != nil {
-		return "", fmt.Errorf("ошибка скачивания: %v", err)
-	}
-	defer resp.Body.Close()
-
-	out, err := os.Create(filePath)
-	if err != nil {
-		return "", fmt.Errorf("ошибка создания файла: %v", err)
-	}
-	defer out.Close()
-
-	_, err = io.Copy(out, resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("ошибка сохранения файла: %v", err)
-	}
-
-	return filePath, nil
-}
-
-// Импорт врачей (обновленная версия с улучшенным логированием)
-func (h *MainHandler) importVeterinarians(filePath string, fileName string) (string, error) {
-	InfoLog.Printf("Начинаем импорт врачей из файла: %s", fileName)
-
-	var vets []models.Veterinarian
-	var err error
-
-	// Определяем тип файла и парсим
-	if strings.HasSuffix(strings.ToLower(fileName), ".csv") {
-		InfoLog.Printf("Обрабатываем CSV файл: %s", filePath)
-		vets, err = h.parseVeterinariansCSV(filePath)
-	} else if strings.HasSuffix(strings.ToLower(fileName), ".xlsx") {
-		InfoLog.Printf("Обрабатываем Excel файл: %s", filePath)
-		vets, err = h.parseVeterinariansXLSX(filePath)
-	} else {
-		return "", fmt.Errorf("неподдерживаемый формат файла. Используйте CSV или XLSX")
-	}
-
-	if err != nil {
-		ErrorLog.Printf("Ошибка парсинга файла %s: %v", fileName, err)
-		return "", err
-	}
-
-	if len(vets) == 0 {
-		InfoLog.Printf("В файле %s не найдено данных для импорта", fileName)
-		return "⚠️ В файле не найдено данных для импорта. Проверьте формат файла и наличие данных.", nil
-	}
-
-	InfoLog.Printf("Найдено %d ветеринаров для импорта", len(vets))
-
-	// Сохраняем в базу
-	successCount := 0
-	for i, vet := range vets {
-		InfoLog.Printf("Импортируем ветеринара %d/%d: %s %s", i+1, len(vets), vet.FirstName, vet.LastName)
-
-		// Сохраняем основную информацию о враче
-		err := h.db.CreateVeterinarian(&vet)
-		if err != nil {
-			ErrorLog.Printf("Ошибка сохранения врача %s %s: %v", vet.FirstName, vet.LastName, err)
-			continue
-		}
-
-		// Сохраняем специализации
-		for _, spec := range vet.Specializations {
-			err := h.db.AddVeterinarianSpecialization(models.GetVetIDAsIntOrZero(&vet), spec.ID)
-			if err != nil {
-				InfoLog.Printf("Processing vet ID: %d", models.GetVetIDAsIntOrZero(&vet))
-			}
-		}
-
-		successCount++
-		InfoLog.Printf("Processing vet ID: %d", models.GetVetIDAsIntOrZero(&vet))
-	}
-
-	result := fmt.Sprintf("✅ Импорт завершен!\n\nОбработано записей: %d\nУспешно импортировано: %d\nОшибок: %d",
-		len(vets), successCount, len(vets)-successCount)
-
-	InfoLog.Printf("Результат импорта: %s", result)
-	return result, nil
-}
-
-// Парсинг CSV файла с врачами (исправленная версия)
-func (h *MainHandler) parseVeterinariansCSV(filePath string) ([]models.Veterinarian, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("ошибка открытия файла: %v", err)
-	}
-	defer file.Close()
-
-	// Пробуем разные разделители
-	separators := []rune{'\t', ';', ',', '|'}
-	var records [][]string
-	var parseError error
-
-	for _, separator := range separators {
-		if _, err := file.Seek(0, 0); err != nil { // Сбрасываем позицию чтения
-			return nil, fmt.Errorf("ошибка сброса позиции файла: %v", err)
-		}
-		reader := csv.NewReader(file)
-		reader.Comma = separator
-		reader.FieldsPerRecord = -1 // Разрешаем разное количество полей
-		reader.LazyQuotes = true
-		reader.TrimLeadingSpace = true
-
-		records, parseError = reader.ReadAll()
-		if parseError == nil && len(records) > 1 {
-			InfoLog.Printf("Успешно распарсен CSV с разделителем: %q", string(separator))
-			break
-		}
-	}
-
-	if parseError != nil {
-		return nil, fmt.Errorf("ошибка чтения CSV: %v", parseError)
-	}
-
-	if len(records) < 2 {
-		return nil, fmt.Errorf("файл не содержит данных или заголовков")
-	}
-
-	// Определяем индексы колонок по заголовкам
-	headers := records[0]
-	columnIndexes := make(map[string]int)
-	for i, header := range headers {
-		cleanHeader := strings.ToLower(strings.TrimSpace(header))
-		columnIndexes[cleanHeader] = i
-	}
-
-	InfoLog.Printf("Заголовки CSV: %v", headers)
-	InfoLog.Printf("Найдено строк: %d", len(records)-1)
-
-	var vets []models.Veterinarian
-
-	for i, record := range records[1:] {
-		// Пропускаем пустые строки
-		if len(record) == 0 || (len(record) == 1 && strings.TrimSpace(record[0]) == "") {
-			InfoLog.Printf("Пропускаем пустую строку %d", i+2)
-			continue
-		}
-
-		// Получаем данные по названиям колонок
-		firstName := h.getColumnValue(record, columnIndexes, []string{"имя", "firstname", "name"})
-		lastName := h.getColumnValue(record, columnIndexes, []string{"фамилия", "lastname", "surname"})
-		phone := h.getColumnValue(record, columnIndexes, []string{"телефон", "phone", "тел"})
-		email := h.getColumnValue(record, columnIndexes, []string{"email", "почта"})
-		experience := h.getColumnValue(record, columnIndexes, []string{"опыт", "experience", "опыт работы"})
-		description := h.getColumnValue(record, columnIndexes, []string{"описание", "description"})
-		specializations := h.getColumnValue(record, columnIndexes, []string{"специализации", "specializations", "специализация"})
-		city := h.getColumnValue(record, columnIndexes, []string{"город", "city"})
-		region := h.getColumnValue(record, columnIndexes, []string{"регион", "region"})
-
-		// Проверяем обязательные поля
-		if firstName == "" || lastName == "" || phone == "" {
-			InfoLog.Printf("Пропускаем строку %d: отсутствуют обязательные поля (Имя: %s, Фамилия: %s, Телефон: %s)",
-				i+2, firstName, lastName, phone)
-			continue
-		}
-
-		// Парсим опыт работы
-		var experienceYears sql.NullInt64
-		if expStr := strings.TrimSpace(experience); expStr != "" {
-			if years, err := extractYearsFromExperience(expStr); err == nil {
-				experienceYears = sql.NullInt64{Int64: int64(years), Valid: true}
-				InfoLog.Printf("Опыт работы для %s %s: %d лет", firstName, lastName, years)
-			} else {
-				InfoLog.Printf("Не удалось распарсить опыт работы '%s' для %s %s: %v", expStr, firstName, lastName, err)
-			}
-		}
-
-		vet := models.Veterinarian{
-			FirstName:       strings.TrimSpace(firstName),
-			LastName:        strings.TrimSpace(lastName),
-			Phone:           strings.TrimSpace(phone),
-			Email:           sql.NullString{String: strings.TrimSpace(email), Valid: email != ""},
-			ExperienceYears: experienceYears,
-			Description:     sql.NullString{String: strings.TrimSpace(description), Valid: description != ""},
-			IsActive:        true,
-			CreatedAt:       time.Now(),
-		}
-
-		// Получаем CityID по имени города
-		if city != "" {
-			cityID, err := h.getOrCreateCityID(strings.TrimSpace(city), strings.TrimSpace(region))
-			if err != nil {
-				InfoLog.Printf("Ошибка получения CityID для города %s: %v", city, err)
-			} else {
-				vet.CityID = sql.NullInt64{Int64: int64(cityID), Valid: true}
-				InfoLog.Printf("Город для %s %s: %s (ID: %d)", firstName, lastName, city, cityID)
-			}
-		}
-
-		// Обрабатываем специализации
-		if specStr := strings.TrimSpace(specializations); specStr != "" {
-			specializationsList, err := h.processSpecializations(specStr)
-			if err != nil {
-				InfoLog.Printf("Ошибка обработки специализаций для %s %s: %v", firstName, lastName, err)
-			} else {
-				vet.Specializations = specializationsList
-				InfoLog.Printf("Специализации для %s %s: %v", firstName, lastName, specStr)
-			}
-		}
-
-		vets = append(vets, vet)
-		InfoLog.Printf("Добавлен ветеринар: %s %s, телефон: %s", firstName, lastName, phone)
-	}
-
-	InfoLog.Printf("Успешно обработано ветеринаров: %d из %d", len(vets), len(records)-1)
-	return vets, nil
-}
-
-// Вспомогательная функция для получения значения колонки
-func (h *MainHandler) getColumnValue(record []string, columnIndexes map[string]int, possibleNames []string) string {
-	for _, name := range possibleNames {
-		if idx, exists := columnIndexes[name]; exists && idx < len(record) {
-			return record[idx]
-		}
-	}
-	return ""
-}
-
-// Парсинг XLSX файла с врачами (исправленная версия)
-func (h *MainHandler) parseVeterinariansXLSX(filePath string) ([]models.Veterinarian, error) {
-	f, err := excelize.OpenFile(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("ошибка открытия XLSX файла: %v", err)
-	}
-	defer f.Close()
-
-	sheets := f.GetSheetList()
-	if len(sheets) == 0 {
-		return nil, fmt.Errorf("файл не содержит листов")
-	}
-
-	rows, err := f.GetRows(sheets[0])
-	if err != nil {
-		return nil, fmt.Errorf("ошибка чтения листа: %v", err)
-	}
-
-	if len(rows) < 2 {
-		return nil, fmt.Errorf("файл не содержит данных")
-	}
-
-	InfoLog.Printf("Заголовки Excel: %v", rows[0])
-	InfoLog.Printf("Найдено строк: %d", len(rows)-1)
-
-	// Определяем индексы колонок по заголовкам
-	headers := rows[0]
-	columnIndexes := make(map[string]int)
-	for i, header := range headers {
-		cleanHeader := strings.ToLower(strings.TrimSpace(header))
-		columnIndexes[cleanHeader] = i
-	}
-
-	var vets []models.Veterinarian
-
-	for i, row := range rows[1:] {
-		// Пропускаем пустые строки
-		if len(row) == 0 {
-			InfoLog.Printf("Пропускаем пустую строку %d", i+2)
-			continue
-		}
-
-		// Получаем данные по названиям колонок
-		firstName := h.getColumnValue(row, columnIndexes, []string{"имя", "firstname", "name"})
-		lastName := h.getColumnValue(row, columnIndexes, []string{"фамилия", "lastname", "surname"})
-		phone := h.getColumnValue(row, columnIndexes, []string{"телефон", "phone", "тел"})
-		email := h.getColumnValue(row, columnIndexes, []string{"email", "почта"})
-		experience := h.getColumnValue(row, columnIndexes, []string{"опыт", "experience", "опыт работы"})
-		description := h.getColumnValue(row, columnIndexes, []string{"описание", "description"})
-		specializations := h.getColumnValue(row, columnIndexes, []string{"специализации", "specializations", "специализация"})
-		city := h.getColumnValue(row, columnIndexes, []string{"город", "city"})
-		region := h.getColumnValue(row, columnIndexes, []string{"регион", "region"})
-
-		// Проверяем обязательные поля
-		if firstName == "" || lastName == "" || phone == "" {
-			InfoLog.Printf("Пропускаем строку %d: отсутствуют обязательные поля (Имя: %s, Фамилия: %s, Телефон: %s)",
-				i+2, firstName, lastName, phone)
-			continue
-		}
-
-		// Парсим опыт работы
-		var experienceYears sql.NullInt64
-		if expStr := strings.TrimSpace(experience); expStr != "" {
-			if years, err := extractYearsFromExperience(expStr); err == nil {
-				experienceYears = sql.NullInt64{Int64: int64(years), Valid: true}
-			}
-		}
-
-		vet := models.Veterinarian{
-			FirstName:       strings.TrimSpace(firstName),
-			LastName:        strings.TrimSpace(lastName),
-			Phone:           strings.TrimSpace(phone),
-			Email:           sql.NullString{String: strings.TrimSpace(email), Valid: email != ""},
-			ExperienceYears: experienceYears,
-			Description:     sql.NullString{String: strings.TrimSpace(description), Valid: description != ""},
-			IsActive:        true,
-			CreatedAt:       time.Now(),
-		}
-
-		// Получаем CityID по имени города
-		if city != "" {
-			cityID, err := h.getOrCreateCityID(strings.TrimSpace(city), strings.TrimSpace(region))
-			if err != nil {
-				InfoLog.Printf("Ошибка получения CityID для города %s: %v", city, err)
-			} else {
-				vet.CityID = sql.NullInt64{Int64: int64(cityID), Valid: true}
-			}
-		}
-
-		// Обрабатываем специализации
-		if specStr := strings.TrimSpace(specializations); specStr != "" {
-			specializationsList, err := h.processSpecializations(specStr)
-			if err != nil {
-				InfoLog.Printf("Ошибка обработки специализаций для %s %s: %v", firstName, lastName, err)
-			} else {
-				vet.Specializations = specializationsList
-			}
-		}
-
-		vets = append(vets, vet)
-		InfoLog.Printf("Добавлен ветеринар: %s %s, телефон: %s", firstName, lastName, phone)
-	}
-
-	InfoLog.Printf("Успешно обработано ветеринаров: %d из %d", len(vets), len(rows)-1)
-	return vets, nil
-}
-
-// Вспомогательная функция для извлечения лет из строки опыта
-func extractYearsFromExperience(expStr string) (int, error) {
-	// Убираем все нецифровые символы и пытаемся извлечь число
-	re := regexp.MustCompile(`\d+`)
-	matches := re.FindStringSubmatch(expStr)
-	if len(matches) > 0 {
-		return strconv.Atoi(matches[0])
-	}
-	return 0, fmt.Errorf("не удалось извлечь количество лет из: %s", expStr)
-}
-
-// Обработка специализаций (разделение строки и создание объектов)
-func (h *MainHandler) processSpecializations(specStr string) ([]*models.Specialization, error) {
-	// Разделяем специализации по запятым, точкам с запятой или другим разделителям
-	separators := []string{",", ";", "/", " и "}
-
-	var specs []string
-	for _, sep := range separators {
-		if strings.Contains(specStr, sep) {
-			specs = strings.Split(specStr, sep)
-			break
-		}
-	}
-
-	if len(specs) == 0 {
-		specs = []string{specStr}
-	}
-
-	var specializations []*models.Specialization
-
-	for _, specName := range specs {
-		specName = strings.TrimSpace(specName)
-		if specName == "" {
-			continue
-		}
-
-		// Ищем существующую специализацию или создаем новую
-		spec, err := h.getOrCreateSpecialization(specName)
-		if err != nil {
-			return nil, err
-		}
-		specializations = append(specializations, spec)
-	}
-
-	return specializations, nil
-}
-
-// Получить или создать специализацию
-func (h *MainHandler) getOrCreateSpecialization(name string) (*models.Specialization, error) {
-	// Сначала пытаемся найти существующую
-	spec, err := h.db.GetSpecializationByName(name)
-	if err == nil && spec != nil {
-		return spec, nil
-	}
-
-	// Если не найдена, создаем новую
-	newSpec := &models.Specialization{
-		Name:      name,
-		CreatedAt: time.Now(),
-	}
-
-	err = h.db.CreateSpecialization(newSpec)
-	if err != nil {
-		return nil, fmt.Errorf("ошибка создания специализации %s: %v", name, err)
-	}
-
-	return newSpec, nil
-}
-
-// Получить или создать город (обновленная версия)
-func (h *MainHandler) getOrCreateCityID(cityName string, region string) (int, error) {
-	if cityName == "" {
-		return 0, fmt.Errorf("название города не может быть пустым")
-	}
-
-	// Сначала пытаемся найти существующий город
-	city, err := h.db.GetCityByName(cityName)
-	if err == nil && city != nil {
-		return city.ID, nil
-	}
-
-	// Если город не найден, создаем новый
-	newCity := &models.City{
-		Name:      cityName,
-		Region:    region,
-		CreatedAt: time.Now(),
-	}
-
-	err = h.db.CreateCity(newCity)
-	if err != nil {
-		return 0, fmt.Errorf("ошибка создания города %s: %v", cityName, err)
-	}
-
-	return newCity.ID, nil
+	// Файл проверяется админ-панелью: сначала предпросмотр, затем импорт после подтверждения
+	h.adminHandlers.HandleAdminDocument(update)
 }
 
 // Вспомогательная функция для отправки ошибок
@@ -913,13 +501,9 @@ func (h *MainHandler) isInAdminMode(userID int64) bool {
 	return h.isAdmin(userID)
 }
 
-// importCities и importClinics - временные заглушки
-func (h *MainHandler) importCities(_ string, _ string) (string, error) {
-	return "✅ Импорт городов завершен!\n\nФункция импорта городов в разработке", nil
-}
-
-func (h *MainHandler) importClinics(_ string, _ string) (string, error) {
-	return "✅ Импорт клиник завершен!\n\nФункция импорта клиник в разработке", nil
+// StopImportJobs отменяет фоновые задачи импорта и ждет их завершения
+func (h *MainHandler) StopImportJobs() {
+	h.adminHandlers.StopImportJobs()
 }
 
 // SetUserState устанавливает состояние пользователя через StateManager
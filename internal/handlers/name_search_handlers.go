@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// nameSearchLimit сколько лучших совпадений показывать в результатах поиска по ФИО
+const nameSearchLimit = 30
+
+// maxNameQueryWords больше слов в ФИО не бывает: фамилия, имя, отчество
+const maxNameQueryWords = 3
+
+// maxNameQueryLength ограничение длины запроса, чтобы не искать по случайно вставленному тексту
+const maxNameQueryLength = 64
+
+// isNameQuery проверяет, похож ли текст на поиск врача по ФИО: 1-3 слова из букв, не команда
+func isNameQuery(text string) bool {
+	text = strings.TrimSpace(text)
+	if text == "" || strings.HasPrefix(text, "/") || utf8.RuneCountInString(text) > maxNameQueryLength {
+		return false
+	}
+
+	words := strings.Fields(text)
+	if len(words) > maxNameQueryWords {
+		return false
+	}
+
+	letters := 0
+	for _, r := range text {
+		switch {
+		case unicode.IsLetter(r):
+			letters++
+		case unicode.IsSpace(r) || r == '-' || r == '.' || r == '\'':
+		default:
+			return false
+		}
+	}
+	return letters >= 2
+}
+
+// HandleNameSearch ищет врачей по фамилии, имени или отчеству с учетом опечаток и латиницы
+func (h *VetHandlers) HandleNameSearch(update tgbotapi.Update, query string) {
+	if update.Message == nil {
+		ErrorLog.Printf("HandleNameSearch called without message")
+		return
+	}
+
+	chatID := update.Message.Chat.ID
+	query = strings.TrimSpace(query)
+	InfoLog.Printf("Searching vets by name: '%s'", query)
+
+	displayQuery := escapeMarkdown(query)
+	results := &searchResults{
+		Source: resultsSourceName,
+		Query:  query,
+		Title:  fmt.Sprintf("🔎 *Результаты поиска «%s»:*", displayQuery),
+		EmptyText: fmt.Sprintf("🔎 *Врачи по запросу «%s» не найдены*\n\n"+
+			"Проверьте написание фамилии или воспользуйтесь поиском по специализациям, клиникам или городу.",
+			displayQuery),
+		Buttons: [][]resultsButton{
+			{{Text: "🔍 Специализации", Data: "main_specializations"}, {Text: "🏥 Клиники", Data: "main_clinics"}},
+			{{Text: "🏠 Главное меню", Data: "main_menu"}},
+		},
+	}
+
+	h.stateManager.PushState(update.Message.From.ID, "main_menu")
+	if err := h.startSearchResults(chatID, update.Message.From.ID, results); err != nil {
+		ErrorLog.Printf("Error searching vets by name: %v", err)
+		h.bot.Send(tgbotapi.NewMessage(chatID, "❌ Ошибка при поиске врачей"))
+	}
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/drerr0r/vetbot/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================================
+// ТЕСТЫ ДЛЯ ПОИСКА ВРАЧЕЙ ПО ФИО
+// ============================================================================
+
+// setupNameSearchVets заполняет мок врачами с похожими фамилиями
+func setupNameSearchVets(mockDB *MockDatabase) {
+	mockDB.Veterinarians[1] = &models.Veterinarian{
+		ID: sql.NullInt64{Int64: 1, Valid: true}, FirstName: "Анна", LastName: "Петрова",
+		Patronymic: sql.NullString{String: "Сергеевна", Valid: true}, Phone: "+79001112233", IsActive: true,
+	}
+	mockDB.Veterinarians[2] = &models.Veterinarian{
+		ID: sql.NullInt64{Int64: 2, Valid: true}, FirstName: "Иван", LastName: "Петров",
+		Phone: "+79004445566", IsActive: true,
+	}
+	mockDB.Veterinarians[3] = &models.Veterinarian{
+		ID: sql.NullInt64{Int64: 3, Valid: true}, FirstName: "Мария", LastName: "Кузнецова",
+		Phone: "+79007778899", IsActive: true,
+	}
+	mockDB.Veterinarians[4] = &models.Veterinarian{
+		ID: sql.NullInt64{Int64: 4, Valid: true}, FirstName: "Олег", LastName: "Петрова",
+		Phone: "+79000000000", IsActive: false,
+	}
+}
+
+func TestIsNameQuery(t *testing.T) {
+	assert.True(t, isNameQuery("Петрова"))
+	assert.True(t, isNameQuery("Анна Петрова"))
+	assert.True(t, isNameQuery("Petrova"))
+	assert.True(t, isNameQuery("Римский-Корсаков"))
+
+	assert.False(t, isNameQuery("/start"))
+	assert.False(t, isNameQuery("я"))
+	assert.False(t, isNameQuery("Отличный врач, всем советую"))
+	assert.False(t, isNameQuery("+79001112233"))
+	assert.False(t, isNameQuery("раз два три четыре"))
+}
+
+func TestHandleNameSearch(t *testing.T) {
+	t.Run("Free text is routed to name search", func(t *testing.T) {
+		handler, mockBot := CreateTestMainHandlers()
+		setupNameSearchVets(handler.db.(*MockDatabase))
+
+		handler.HandleUpdate(NewTestUpdate().WithMessage("Петрова", 100, 100).Build())
+
+		require.Len(t, mockBot.SentMessages, 1)
+		text := mockBot.SentMessages[0].Text
+		assert.Contains(t, text, "Найдено врачей: 2")
+		// Точное совпадение выше частичного, неактивный врач не показывается
+		assert.Contains(t, text, "1. Анна Петрова")
+		assert.Contains(t, text, "2. Иван Петров")
+	})
+
+	t.Run("Typo and transliteration", func(t *testing.T) {
+		handler, mockBot := CreateTestMainHandlers()
+		setupNameSearchVets(handler.db.(*MockDatabase))
+
+		handler.HandleUpdate(NewTestUpdate().WithMessage("Kuznetcova", 100, 100).Build())
+
+		require.Len(t, mockBot.SentMessages, 1)
+		assert.Contains(t, mockBot.SentMessages[0].Text, "Мария Кузнецова")
+	})
+
+	t.Run("Nothing found", func(t *testing.T) {
+		handler, mockBot := CreateTestMainHandlers()
+		setupNameSearchVets(handler.db.(*MockDatabase))
+
+		handler.HandleUpdate(NewTestUpdate().WithMessage("Сидоренко", 100, 100).Build())
+
+		assert.Contains(t, mockBot.GetLastMessage().Text, "не найдены")
+	})
+
+	t.Run("Query is escaped, not stripped", func(t *testing.T) {
+		handler, mockBot, mockDB := CreateTestVetHandlers()
+		setupNameSearchVets(mockDB)
+
+		handler.HandleNameSearch(NewTestUpdate().WithMessage("Сидор_енко", 100, 100).Build(), "Сидор_енко")
+
+		assert.Contains(t, mockBot.GetLastMessage().Text, `«Сидор\_енко»`)
+	})
+
+	t.Run("Non-name text keeps the help hint", func(t *testing.T) {
+		handler, mockBot := CreateTestMainHandlers()
+
+		handler.HandleUpdate(NewTestUpdate().WithMessage("12345", 100, 100).Build())
+
+		assert.Contains(t, mockBot.GetLastMessage().Text, "Я понимаю только команды")
+	})
+}
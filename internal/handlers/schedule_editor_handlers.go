@@ -0,0 +1,436 @@
+package handlers
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/drerr0r/vetbot/internal/models"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// scheduleDayNames названия дней недели, которые админ может ввести при добавлении приема
+var scheduleDayNames = map[string]int{
+	"понедельник": 1, "вторник": 2, "среда": 3, "четверг": 4, "пятница": 5, "суббота": 6, "воскресенье": 7,
+}
+
+// parseScheduleDay разбирает день недели: "Пн", "понедельник" или номер от 1 до 7
+func parseScheduleDay(value string) (int, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if day, err := strconv.Atoi(value); err == nil && day >= 1 && day <= 7 {
+		return day, nil
+	}
+	for day := 1; day <= 7; day++ {
+		if value == strings.ToLower(getShortDayName(day)) {
+			return day, nil
+		}
+	}
+	if day, exists := scheduleDayNames[value]; exists {
+		return day, nil
+	}
+	return 0, fmt.Errorf("неизвестный день недели '%s', укажите Пн, Вт, Ср, Чт, Пт, Сб или Вс", value)
+}
+
+// formatScheduleSlot описывает прием одной строкой: "Пн 09:00-18:00", ночная смена и выключенный прием помечаются
+func formatScheduleSlot(schedule *models.Schedule) string {
+	text := fmt.Sprintf("%s %s-%s", getShortDayName(schedule.DayOfWeek), schedule.StartTime, schedule.EndTime)
+	if schedule.IsOvernight() {
+		text += " (ночная смена)"
+	}
+	if !schedule.IsAvailable {
+		text += " - выключен"
+	}
+	return text
+}
+
+// formatScheduleSlotWithClinic описывает прием вместе с клиникой: "Пн 09:00-18:00 (ВетКлиника)"
+func formatScheduleSlotWithClinic(schedule *models.Schedule) string {
+	text := formatScheduleSlot(schedule)
+	if schedule.Clinic != nil && schedule.Clinic.Name != "" {
+		text += " (" + schedule.Clinic.Name + ")"
+	}
+	return text
+}
+
+// showVetSchedules показывает расписание врача по клиникам с общей нумерацией приемов
+func (h *AdminHandlers) showVetSchedules(update tgbotapi.Update, vetID int) {
+	userID := update.Message.From.ID
+	h.stateManager.SetAdminState(userID, "vet_edit_schedules")
+
+	schedules, err := h.db.GetAllSchedulesByVetID(vetID)
+	if err != nil {
+		ErrorLog.Printf("❌ Ошибка загрузки расписания врача %d: %v", vetID, err)
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Ошибка при загрузке расписания")
+		h.bot.Send(msg)
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("📅 Расписание приемов\n")
+	if len(schedules) == 0 {
+		sb.WriteString("\nПриемов нет - врач не показывается в поиске по дням и времени.\n")
+	}
+	lastClinicID := -1
+	for i, schedule := range schedules {
+		if schedule.ClinicID != lastClinicID {
+			lastClinicID = schedule.ClinicID
+			clinicName := fmt.Sprintf("Клиника %d", schedule.ClinicID)
+			if schedule.Clinic != nil && schedule.Clinic.Name != "" {
+				clinicName = schedule.Clinic.Name
+			}
+			sb.WriteString(fmt.Sprintf("\n🏥 %s:\n", clinicName))
+		}
+		sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, formatScheduleSlot(schedule)))
+	}
+	sb.WriteString("\nВыключенный прием сохраняется, но не участвует в поиске и записи. " +
+		"Разовые изменения (отпуск, другие часы) добавляйте в исключениях.")
+
+	rows := [][]tgbotapi.KeyboardButton{
+		tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton("➕ Добавить прием")),
+	}
+	if len(schedules) > 0 {
+		rows = append(rows,
+			tgbotapi.NewKeyboardButtonRow(
+				tgbotapi.NewKeyboardButton("✏️ Изменить прием"),
+				tgbotapi.NewKeyboardButton("⏯️ Включить/выключить"),
+			),
+			tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton("🗑️ Удалить прием")),
+		)
+	}
+	rows = append(rows, tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton("🔙 Назад")))
+
+	// Без Markdown: в названиях клиник бывают спецсимволы
+	msg := tgbotapi.NewMessage(update.Message.Chat.ID, sb.String())
+	msg.ReplyMarkup = tgbotapi.NewReplyKeyboard(rows...)
+	h.bot.Send(msg)
+}
+
+// handleVetSchedulesMenu обрабатывает выбор действия в расписании врача
+func (h *AdminHandlers) handleVetSchedulesMenu(update tgbotapi.Update, text string) {
+	userID := update.Message.From.ID
+	vetData, ok := h.stateManager.GetAdminData(userID, "vet_edit").(*models.VetEditData)
+	if !ok || vetData == nil {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Ошибка: данные врача не найдены")
+		h.bot.Send(msg)
+		h.showVetList(update)
+		return
+	}
+
+	cancelKeyboard := tgbotapi.NewReplyKeyboard(
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton("🔙 Назад"),
+			tgbotapi.NewKeyboardButton("❌ Отмена"),
+		),
+	)
+
+	switch text {
+	case "➕ Добавить прием", "✏️ Изменить прием":
+		clinics, err := h.vetScheduleClinics(vetData.VetID)
+		if err != nil || len(clinics) == 0 {
+			msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Нет ни одной клиники - сначала добавьте клинику")
+			h.bot.Send(msg)
+			return
+		}
+
+		var sb strings.Builder
+		if text == "➕ Добавить прием" {
+			h.stateManager.SetAdminState(userID, "vet_edit_schedule_add")
+			sb.WriteString("Введите день недели, часы приема и номер клиники:\n\n" +
+				"Пн 09:00-18:00 1\n" +
+				"Сб 20:00-08:00 2 (ночная смена до утра воскресенья)\n\nКлиники:\n")
+		} else {
+			h.stateManager.SetAdminState(userID, "vet_edit_schedule_edit")
+			sb.WriteString("Введите номер приема из списка, новый день, часы и при желании номер клиники " +
+				"(без номера клиника не меняется):\n\n" +
+				"2 Вт 10:00-14:00\n" +
+				"2 Вт 10:00-14:00 1\n\nКлиники:\n")
+		}
+		for i, clinic := range clinics {
+			sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, clinic.Name))
+		}
+		if len(clinics) == 1 {
+			sb.WriteString("\nКлиника одна - номер можно не указывать.")
+		}
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, sb.String())
+		msg.ReplyMarkup = cancelKeyboard
+		h.bot.Send(msg)
+
+	case "⏯️ Включить/выключить":
+		h.stateManager.SetAdminState(userID, "vet_edit_schedule_toggle")
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Введите номер приема, который нужно включить или выключить:")
+		msg.ReplyMarkup = cancelKeyboard
+		h.bot.Send(msg)
+
+	case "🗑️ Удалить прием":
+		h.stateManager.SetAdminState(userID, "vet_edit_schedule_delete")
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Введите номер приема из списка:")
+		msg.ReplyMarkup = cancelKeyboard
+		h.bot.Send(msg)
+
+	default:
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Используйте кнопки для управления")
+		h.bot.Send(msg)
+	}
+}
+
+// handleVetScheduleAdd добавляет прием: "Пн 09:00-18:00 1"
+func (h *AdminHandlers) handleVetScheduleAdd(update tgbotapi.Update, text string) {
+	vetData, ok := h.stateManager.GetAdminData(update.Message.From.ID, "vet_edit").(*models.VetEditData)
+	if !ok || vetData == nil {
+		h.showVetList(update)
+		return
+	}
+
+	fail := func(reason string) {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ "+reason+"\n\nПример: Пн 09:00-18:00 1")
+		h.bot.Send(msg)
+	}
+
+	fields := strings.Fields(text)
+	if len(fields) < 2 || len(fields) > 3 {
+		fail("Укажите день недели, часы приема и номер клиники")
+		return
+	}
+
+	schedule := &models.Schedule{VetID: vetData.VetID, IsAvailable: true}
+	if err := parseScheduleSlot(schedule, fields[0], fields[1]); err != nil {
+		fail(err.Error())
+		return
+	}
+
+	clinicNumber := ""
+	if len(fields) == 3 {
+		clinicNumber = fields[2]
+	}
+	clinic, err := h.pickScheduleClinic(vetData.VetID, clinicNumber, true)
+	if err != nil {
+		fail(err.Error())
+		return
+	}
+	schedule.ClinicID = clinic.ID
+	schedule.Clinic = clinic
+
+	h.saveVetSchedule(update, schedule, "✅ Прием добавлен: ")
+}
+
+// handleVetScheduleEdit меняет день, часы и при желании клинику приема: "2 Вт 10:00-14:00 1"
+func (h *AdminHandlers) handleVetScheduleEdit(update tgbotapi.Update, text string) {
+	vetData, ok := h.stateManager.GetAdminData(update.Message.From.ID, "vet_edit").(*models.VetEditData)
+	if !ok || vetData == nil {
+		h.showVetList(update)
+		return
+	}
+
+	fail := func(reason string) {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ "+reason+"\n\nПример: 2 Вт 10:00-14:00")
+		h.bot.Send(msg)
+	}
+
+	fields := strings.Fields(text)
+	if len(fields) < 3 || len(fields) > 4 {
+		fail("Укажите номер приема, день недели и часы приема")
+		return
+	}
+
+	current, err := h.pickVetSchedule(vetData.VetID, fields[0])
+	if err != nil {
+		fail(err.Error())
+		return
+	}
+
+	schedule := *current
+	if err := parseScheduleSlot(&schedule, fields[1], fields[2]); err != nil {
+		fail(err.Error())
+		return
+	}
+	if len(fields) == 4 {
+		clinic, err := h.pickScheduleClinic(vetData.VetID, fields[3], false)
+		if err != nil {
+			fail(err.Error())
+			return
+		}
+		schedule.ClinicID = clinic.ID
+		schedule.Clinic = clinic
+	}
+
+	h.saveVetSchedule(update, &schedule, "✅ Прием изменен: ")
+}
+
+// handleVetScheduleToggle включает или выключает прием по номеру из списка
+func (h *AdminHandlers) handleVetScheduleToggle(update tgbotapi.Update, text string) {
+	vetData, ok := h.stateManager.GetAdminData(update.Message.From.ID, "vet_edit").(*models.VetEditData)
+	if !ok || vetData == nil {
+		h.showVetList(update)
+		return
+	}
+
+	current, err := h.pickVetSchedule(vetData.VetID, text)
+	if err != nil {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ "+err.Error())
+		h.bot.Send(msg)
+		return
+	}
+
+	schedule := *current
+	schedule.IsAvailable = !schedule.IsAvailable
+	prefix := "✅ Прием включен: "
+	if !schedule.IsAvailable {
+		prefix = "⏸️ Прием выключен: "
+	}
+	h.saveVetSchedule(update, &schedule, prefix)
+}
+
+// handleVetScheduleDelete удаляет прием по номеру из списка
+func (h *AdminHandlers) handleVetScheduleDelete(update tgbotapi.Update, text string) {
+	vetData, ok := h.stateManager.GetAdminData(update.Message.From.ID, "vet_edit").(*models.VetEditData)
+	if !ok || vetData == nil {
+		h.showVetList(update)
+		return
+	}
+
+	schedule, err := h.pickVetSchedule(vetData.VetID, text)
+	if err != nil {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ "+err.Error())
+		h.bot.Send(msg)
+		return
+	}
+
+	if err := h.db.DeleteSchedule(schedule.ID); err != nil {
+		ErrorLog.Printf("❌ Ошибка удаления приема %d: %v", schedule.ID, err)
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Ошибка при удалении приема")
+		h.bot.Send(msg)
+		return
+	}
+
+	InfoLog.Printf("🗑️ Удален прием врача %d: %s", schedule.VetID, formatScheduleSlotWithClinic(schedule))
+	msg := tgbotapi.NewMessage(update.Message.Chat.ID, "✅ Прием удален: "+formatScheduleSlotWithClinic(schedule))
+	h.bot.Send(msg)
+	h.showVetSchedules(update, vetData.VetID)
+}
+
+// parseScheduleSlot заполняет день недели и часы приема из "Пн" и "09:00-18:00"
+func parseScheduleSlot(schedule *models.Schedule, day, hours string) error {
+	dayOfWeek, err := parseScheduleDay(day)
+	if err != nil {
+		return err
+	}
+	startTime, endTime, found := strings.Cut(hours, "-")
+	if !found {
+		return fmt.Errorf("часы приема укажите как ЧЧ:ММ-ЧЧ:ММ")
+	}
+	schedule.DayOfWeek = dayOfWeek
+	schedule.StartTime = strings.TrimSpace(startTime)
+	schedule.EndTime = strings.TrimSpace(endTime)
+	return nil
+}
+
+// saveVetSchedule проверяет прием и сохраняет его, затем возвращает к расписанию врача.
+// Доступный прием не должен пересекаться с другими доступными приемами врача ни в одной клинике
+func (h *AdminHandlers) saveVetSchedule(update tgbotapi.Update, schedule *models.Schedule, successPrefix string) {
+	if err := schedule.Validate(); err != nil {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, fmt.Sprintf("❌ %v", err))
+		h.bot.Send(msg)
+		return
+	}
+	// Часы нормализуются к ЧЧ:ММ, как их возвращает база
+	start, _ := models.ParseClock(schedule.StartTime)
+	end, _ := models.ParseClock(schedule.EndTime)
+	schedule.StartTime, schedule.EndTime = models.FormatClock(start), models.FormatClock(end)
+
+	if schedule.IsAvailable {
+		schedules, err := h.db.GetAllSchedulesByVetID(schedule.VetID)
+		if err != nil {
+			ErrorLog.Printf("❌ Ошибка загрузки расписания врача %d: %v", schedule.VetID, err)
+			msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Ошибка при загрузке расписания")
+			h.bot.Send(msg)
+			return
+		}
+		if overlap := schedule.FindOverlap(schedules); overlap != nil {
+			msg := tgbotapi.NewMessage(update.Message.Chat.ID,
+				fmt.Sprintf("❌ Прием пересекается с другим приемом врача: %s\n\nВрач не может принимать в двух местах одновременно - "+
+					"измените часы или выключите другой прием.", formatScheduleSlotWithClinic(overlap)))
+			h.bot.Send(msg)
+			return
+		}
+	}
+
+	var err error
+	if schedule.ID == 0 {
+		err = h.db.CreateSchedule(schedule)
+	} else {
+		err = h.db.UpdateSchedule(schedule)
+	}
+	if err != nil {
+		ErrorLog.Printf("❌ Ошибка сохранения приема врача %d: %v", schedule.VetID, err)
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Ошибка при сохранении приема")
+		h.bot.Send(msg)
+		return
+	}
+
+	InfoLog.Printf("📅 Расписание врача %d: %s%s", schedule.VetID, successPrefix, formatScheduleSlotWithClinic(schedule))
+	msg := tgbotapi.NewMessage(update.Message.Chat.ID, successPrefix+formatScheduleSlotWithClinic(schedule))
+	h.bot.Send(msg)
+	h.showVetSchedules(update, schedule.VetID)
+}
+
+// pickVetSchedule возвращает прием врача по номеру из списка расписания
+func (h *AdminHandlers) pickVetSchedule(vetID int, number string) (*models.Schedule, error) {
+	schedules, err := h.db.GetAllSchedulesByVetID(vetID)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось загрузить расписание")
+	}
+	index, err := strconv.Atoi(strings.TrimSpace(number))
+	if err != nil || index < 1 || index > len(schedules) {
+		return nil, fmt.Errorf("номер приема должен быть от 1 до %d", len(schedules))
+	}
+	return schedules[index-1], nil
+}
+
+// pickScheduleClinic возвращает клинику по номеру из списка клиник для расписания.
+// Пустой номер допустим, только если клиника одна и required
+func (h *AdminHandlers) pickScheduleClinic(vetID int, number string, required bool) (*models.Clinic, error) {
+	clinics, err := h.vetScheduleClinics(vetID)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось загрузить клиники")
+	}
+
+	clinicNumber := 1
+	if number != "" {
+		clinicNumber, err = strconv.Atoi(number)
+		if err != nil {
+			clinicNumber = 0
+		}
+	} else if required && len(clinics) > 1 {
+		return nil, fmt.Errorf("клиник несколько - укажите номер клиники")
+	}
+	if clinicNumber < 1 || clinicNumber > len(clinics) {
+		return nil, fmt.Errorf("номер клиники должен быть от 1 до %d", len(clinics))
+	}
+	return clinics[clinicNumber-1], nil
+}
+
+// vetScheduleClinics возвращает клиники для расписания врача: его клиники, а если их нет - все активные
+func (h *AdminHandlers) vetScheduleClinics(vetID int) ([]*models.Clinic, error) {
+	clinics, err := h.vetExceptionClinics(vetID)
+	if err != nil || len(clinics) > 0 {
+		return clinics, err
+	}
+
+	all, err := h.db.GetAllClinics()
+	if err != nil {
+		return nil, err
+	}
+	for _, clinic := range all {
+		if clinic.IsActive {
+			clinics = append(clinics, clinic)
+		}
+	}
+	sort.Slice(clinics, func(i, j int) bool {
+		if clinics[i].Name != clinics[j].Name {
+			return clinics[i].Name < clinics[j].Name
+		}
+		return clinics[i].ID < clinics[j].ID
+	})
+	return clinics, nil
+}
@@ -0,0 +1,360 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drerr0r/vetbot/internal/models"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// formatScheduleException описывает исключение одной строкой: "20.10.2026-31.10.2026: не принимает (отпуск)".
+// markdown - строка пойдет в сообщение с Markdown, клиника и причина экранируются
+func formatScheduleException(exception *models.ScheduleException, markdown bool) string {
+	escape := func(text string) string { return text }
+	if markdown {
+		escape = escapeMarkdown
+	}
+
+	period := exception.DateFrom.Format(models.ScheduleDateLayout)
+	if !exception.DateTo.Equal(exception.DateFrom) {
+		period += "-" + exception.DateTo.Format(models.ScheduleDateLayout)
+	}
+
+	text := period + ": не принимает"
+	if exception.IsAvailable {
+		text = fmt.Sprintf("%s: прием %s-%s", period, exception.StartTime, exception.EndTime)
+	}
+	if exception.Clinic != nil && exception.Clinic.Name != "" {
+		text += " в " + escape(exception.Clinic.Name)
+	}
+
+	if exception.Reason != "" {
+		text += " (" + escape(exception.Reason) + ")"
+	}
+	return text
+}
+
+// parseExceptionPeriod разбирает дату "20.10.2026" или период "20.10.2026-31.10.2026"
+func parseExceptionPeriod(value string) (time.Time, time.Time, error) {
+	from, to, isRange := strings.Cut(value, "-")
+	dateFrom, err := models.ParseScheduleDate(from)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if !isRange {
+		return dateFrom, dateFrom, nil
+	}
+	dateTo, err := models.ParseScheduleDate(to)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return dateFrom, dateTo, nil
+}
+
+// showVetExceptions показывает действующие и будущие исключения из расписания врача
+func (h *AdminHandlers) showVetExceptions(update tgbotapi.Update, vetID int) {
+	userID := update.Message.From.ID
+	h.stateManager.SetAdminState(userID, "vet_edit_exceptions")
+
+	exceptions, err := h.db.GetScheduleExceptionsByVetID(vetID, time.Now())
+	if err != nil {
+		ErrorLog.Printf("❌ Ошибка загрузки исключений врача %d: %v", vetID, err)
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Ошибка при загрузке исключений из расписания")
+		h.bot.Send(msg)
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("🏖️ Исключения в расписании\n\n")
+	if len(exceptions) == 0 {
+		sb.WriteString("Исключений нет - врач принимает по обычному расписанию.\n")
+	}
+	for i, exception := range exceptions {
+		sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, formatScheduleException(exception, false)))
+	}
+	sb.WriteString("\nОтсутствие убирает все приемы врача на эти даты, другие часы заменяют " +
+		"обычный прием в клинике. Поиск и запись учитывают исключения.")
+
+	rows := [][]tgbotapi.KeyboardButton{
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton("➕ Отсутствие"),
+			tgbotapi.NewKeyboardButton("➕ Другие часы"),
+		),
+	}
+	if len(exceptions) > 0 {
+		rows = append(rows, tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton("🗑️ Удалить исключение")))
+	}
+	rows = append(rows, tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton("🔙 Назад")))
+
+	// Без Markdown: в причинах и названиях клиник бывают спецсимволы
+	msg := tgbotapi.NewMessage(update.Message.Chat.ID, sb.String())
+	msg.ReplyMarkup = tgbotapi.NewReplyKeyboard(rows...)
+	h.bot.Send(msg)
+}
+
+// handleVetExceptionsMenu обрабатывает выбор действия в списке исключений
+func (h *AdminHandlers) handleVetExceptionsMenu(update tgbotapi.Update, text string) {
+	userID := update.Message.From.ID
+	vetData, ok := h.stateManager.GetAdminData(userID, "vet_edit").(*models.VetEditData)
+	if !ok || vetData == nil {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Ошибка: данные врача не найдены")
+		h.bot.Send(msg)
+		h.showVetList(update)
+		return
+	}
+
+	cancelKeyboard := tgbotapi.NewReplyKeyboard(
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton("🔙 Назад"),
+			tgbotapi.NewKeyboardButton("❌ Отмена"),
+		),
+	)
+
+	switch text {
+	case "➕ Отсутствие":
+		h.stateManager.SetAdminState(userID, "vet_edit_exception_absence")
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID,
+			"Введите дату или период, когда врач не принимает, и при желании причину:\n\n"+
+				"20.10.2026 Больничный\n"+
+				"20.10.2026-31.10.2026 Отпуск")
+		msg.ReplyMarkup = cancelKeyboard
+		h.bot.Send(msg)
+
+	case "➕ Другие часы":
+		clinics, err := h.vetExceptionClinics(vetData.VetID)
+		if err != nil || len(clinics) == 0 {
+			msg := tgbotapi.NewMessage(update.Message.Chat.ID,
+				"У врача нет клиник - сначала добавьте расписание или привяжите врача к клинике")
+			h.bot.Send(msg)
+			return
+		}
+
+		h.stateManager.SetAdminState(userID, "vet_edit_exception_hours")
+		var sb strings.Builder
+		sb.WriteString("Введите дату или период, часы приема и номер клиники:\n\n" +
+			"25.10.2026 10:00-14:00 1\n" +
+			"25.10.2026-26.10.2026 12:00-20:00 2\n\nКлиники врача:\n")
+		for i, clinic := range clinics {
+			sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, clinic.Name))
+		}
+		if len(clinics) == 1 {
+			sb.WriteString("\nКлиника одна - номер можно не указывать.")
+		}
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, sb.String())
+		msg.ReplyMarkup = cancelKeyboard
+		h.bot.Send(msg)
+
+	case "🗑️ Удалить исключение":
+		h.stateManager.SetAdminState(userID, "vet_edit_exception_delete")
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Введите номер исключения из списка:")
+		msg.ReplyMarkup = cancelKeyboard
+		h.bot.Send(msg)
+
+	default:
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Используйте кнопки для управления")
+		h.bot.Send(msg)
+	}
+}
+
+// handleVetExceptionAbsence добавляет отсутствие врача: "20.10.2026-31.10.2026 Отпуск"
+func (h *AdminHandlers) handleVetExceptionAbsence(update tgbotapi.Update, text string) {
+	vetData, ok := h.stateManager.GetAdminData(update.Message.From.ID, "vet_edit").(*models.VetEditData)
+	if !ok || vetData == nil {
+		h.showVetList(update)
+		return
+	}
+
+	period, reason, _ := strings.Cut(strings.TrimSpace(text), " ")
+	dateFrom, dateTo, err := parseExceptionPeriod(period)
+	if err != nil {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, fmt.Sprintf("❌ %v\n\nПример: 20.10.2026-31.10.2026 Отпуск", err))
+		h.bot.Send(msg)
+		return
+	}
+
+	h.createVetException(update, &models.ScheduleException{
+		VetID:    vetData.VetID,
+		DateFrom: dateFrom,
+		DateTo:   dateTo,
+		Reason:   strings.TrimSpace(reason),
+	})
+}
+
+// handleVetExceptionHours добавляет другие часы приема: "25.10.2026 10:00-14:00 1"
+func (h *AdminHandlers) handleVetExceptionHours(update tgbotapi.Update, text string) {
+	vetData, ok := h.stateManager.GetAdminData(update.Message.From.ID, "vet_edit").(*models.VetEditData)
+	if !ok || vetData == nil {
+		h.showVetList(update)
+		return
+	}
+
+	fail := func(reason string) {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ "+reason+"\n\nПример: 25.10.2026 10:00-14:00 1")
+		h.bot.Send(msg)
+	}
+
+	fields := strings.Fields(text)
+	if len(fields) < 2 || len(fields) > 3 {
+		fail("Укажите дату, часы приема и номер клиники")
+		return
+	}
+
+	dateFrom, dateTo, err := parseExceptionPeriod(fields[0])
+	if err != nil {
+		fail(err.Error())
+		return
+	}
+	startTime, endTime, found := strings.Cut(fields[1], "-")
+	if !found {
+		fail("Часы приема укажите как ЧЧ:ММ-ЧЧ:ММ")
+		return
+	}
+
+	clinics, err := h.vetExceptionClinics(vetData.VetID)
+	if err != nil {
+		fail("Не удалось загрузить клиники врача")
+		return
+	}
+	clinicNumber := 1
+	if len(fields) == 3 {
+		clinicNumber, err = strconv.Atoi(fields[2])
+		if err != nil {
+			clinicNumber = 0
+		}
+	} else if len(clinics) > 1 {
+		fail("У врача несколько клиник - укажите номер клиники")
+		return
+	}
+	if clinicNumber < 1 || clinicNumber > len(clinics) {
+		fail(fmt.Sprintf("Номер клиники должен быть от 1 до %d", len(clinics)))
+		return
+	}
+	clinic := clinics[clinicNumber-1]
+
+	h.createVetException(update, &models.ScheduleException{
+		VetID:       vetData.VetID,
+		ClinicID:    sql.NullInt64{Int64: int64(clinic.ID), Valid: true},
+		DateFrom:    dateFrom,
+		DateTo:      dateTo,
+		IsAvailable: true,
+		StartTime:   strings.TrimSpace(startTime),
+		EndTime:     strings.TrimSpace(endTime),
+		Clinic:      clinic,
+	})
+}
+
+// createVetException проверяет и сохраняет исключение, затем возвращает к списку исключений
+func (h *AdminHandlers) createVetException(update tgbotapi.Update, exception *models.ScheduleException) {
+	today := time.Now().In(models.DefaultLocation())
+	if exception.DateTo.Before(time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)) {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Период уже прошел - укажите будущие даты")
+		h.bot.Send(msg)
+		return
+	}
+	if err := exception.Validate(); err != nil {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, fmt.Sprintf("❌ %v", err))
+		h.bot.Send(msg)
+		return
+	}
+
+	if err := h.db.CreateScheduleException(exception); err != nil {
+		ErrorLog.Printf("❌ Ошибка создания исключения для врача %d: %v", exception.VetID, err)
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Ошибка при сохранении исключения")
+		h.bot.Send(msg)
+		return
+	}
+
+	InfoLog.Printf("🏖️ Исключение в расписании врача %d: %s", exception.VetID, formatScheduleException(exception, false))
+	msg := tgbotapi.NewMessage(update.Message.Chat.ID, "✅ Исключение добавлено: "+formatScheduleException(exception, false))
+	h.bot.Send(msg)
+	h.showVetExceptions(update, exception.VetID)
+}
+
+// handleVetExceptionDelete удаляет исключение по номеру из списка
+func (h *AdminHandlers) handleVetExceptionDelete(update tgbotapi.Update, text string) {
+	vetData, ok := h.stateManager.GetAdminData(update.Message.From.ID, "vet_edit").(*models.VetEditData)
+	if !ok || vetData == nil {
+		h.showVetList(update)
+		return
+	}
+
+	exceptions, err := h.db.GetScheduleExceptionsByVetID(vetData.VetID, time.Now())
+	if err != nil {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Ошибка при загрузке исключений из расписания")
+		h.bot.Send(msg)
+		return
+	}
+
+	number, err := strconv.Atoi(strings.TrimSpace(text))
+	if err != nil || number < 1 || number > len(exceptions) {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, fmt.Sprintf("Введите номер от 1 до %d", len(exceptions)))
+		h.bot.Send(msg)
+		return
+	}
+
+	exception := exceptions[number-1]
+	if err := h.db.DeleteScheduleException(exception.ID); err != nil {
+		ErrorLog.Printf("❌ Ошибка удаления исключения %d: %v", exception.ID, err)
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Ошибка при удалении исключения")
+		h.bot.Send(msg)
+		return
+	}
+
+	msg := tgbotapi.NewMessage(update.Message.Chat.ID, "✅ Исключение удалено: "+formatScheduleException(exception, false))
+	h.bot.Send(msg)
+	h.showVetExceptions(update, vetData.VetID)
+}
+
+// returnToVetEditMenu возвращает из расписания или списка исключений в меню редактирования врача
+func (h *AdminHandlers) returnToVetEditMenu(update tgbotapi.Update) {
+	userID := update.Message.From.ID
+	vetData, ok := h.stateManager.GetAdminData(userID, "vet_edit").(*models.VetEditData)
+	if ok && vetData != nil {
+		if vet, err := h.db.GetVeterinarianByID(vetData.VetID); err == nil {
+			h.showVetEditMenu(update, vet)
+			return
+		}
+	}
+	h.stateManager.SetAdminState(userID, "vet_management")
+	h.showVetManagement(update)
+}
+
+// vetExceptionClinics возвращает клиники, в которых врач принимает или к которым привязан, по названию
+func (h *AdminHandlers) vetExceptionClinics(vetID int) ([]*models.Clinic, error) {
+	byID := make(map[int]*models.Clinic)
+
+	clinics, err := h.db.GetClinicsByVetID(vetID)
+	if err != nil {
+		return nil, err
+	}
+	for _, clinic := range clinics {
+		byID[clinic.ID] = clinic
+	}
+
+	schedules, err := h.db.GetSchedulesByVetID(vetID)
+	if err != nil {
+		return nil, err
+	}
+	for _, schedule := range schedules {
+		if _, exists := byID[schedule.ClinicID]; !exists && schedule.Clinic != nil {
+			byID[schedule.ClinicID] = schedule.Clinic
+		}
+	}
+
+	result := make([]*models.Clinic, 0, len(byID))
+	for _, clinic := range byID {
+		result = append(result, clinic)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
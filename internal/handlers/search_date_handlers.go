@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drerr0r/vetbot/internal/models"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Callback календаря: search_cal - текущий месяц, search_cal_<ГГГГ-ММ> - переход к месяцу,
+// search_date_<ГГГГ-ММ-ДД> - выбор даты, search_cal_none - нажатие на заголовок или пустую клетку
+const (
+	datePickerMonthLayout = "2006-01"
+	datePickerNone        = "search_cal_none"
+)
+
+// monthNames названия месяцев для заголовка календаря
+var monthNames = map[time.Month]string{
+	time.January: "Январь", time.February: "Февраль", time.March: "Март", time.April: "Апрель",
+	time.May: "Май", time.June: "Июнь", time.July: "Июль", time.August: "Август",
+	time.September: "Сентябрь", time.October: "Октябрь", time.November: "Ноябрь", time.December: "Декабрь",
+}
+
+// buildDatePicker строит календарь месяца month. Выбрать можно даты с сегодняшнего дня
+// на scheduleLookaheadDays дней вперед, остальные клетки пустые
+func buildDatePicker(month time.Time, now time.Time) tgbotapi.InlineKeyboardMarkup {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	lastDate := today.AddDate(0, 0, scheduleLookaheadDays-1)
+	firstDay := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	empty := func() tgbotapi.InlineKeyboardButton {
+		return tgbotapi.NewInlineKeyboardButtonData(" ", datePickerNone)
+	}
+
+	rows := [][]tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+			fmt.Sprintf("%s %d", monthNames[firstDay.Month()], firstDay.Year()), datePickerNone)),
+	}
+
+	var header []tgbotapi.InlineKeyboardButton
+	for day := 1; day <= 7; day++ {
+		header = append(header, tgbotapi.NewInlineKeyboardButtonData(getShortDayName(day), datePickerNone))
+	}
+	rows = append(rows, header)
+
+	var week []tgbotapi.InlineKeyboardButton
+	for i := 1; i < weekdayNumber(firstDay); i++ {
+		week = append(week, empty())
+	}
+	for date := firstDay; date.Month() == firstDay.Month(); date = date.AddDate(0, 0, 1) {
+		if date.Before(today) || date.After(lastDate) {
+			week = append(week, empty())
+		} else {
+			week = append(week, tgbotapi.NewInlineKeyboardButtonData(strconv.Itoa(date.Day()),
+				"search_date_"+date.Format(models.SearchDateLayout)))
+		}
+		if len(week) == 7 {
+			rows = append(rows, week)
+			week = nil
+		}
+	}
+	if len(week) > 0 {
+		for len(week) < 7 {
+			week = append(week, empty())
+		}
+		rows = append(rows, week)
+	}
+
+	// Листать можно только в пределах доступных дат
+	prev, next := empty(), empty()
+	if firstDay.After(today) {
+		prevMonth := firstDay.AddDate(0, -1, 0)
+		prev = tgbotapi.NewInlineKeyboardButtonData("◀️ "+monthNames[prevMonth.Month()],
+			"search_cal_"+prevMonth.Format(datePickerMonthLayout))
+	}
+	if nextMonth := firstDay.AddDate(0, 1, 0); !nextMonth.After(lastDate) {
+		next = tgbotapi.NewInlineKeyboardButtonData(monthNames[nextMonth.Month()]+" ▶️",
+			"search_cal_"+nextMonth.Format(datePickerMonthLayout))
+	}
+	rows = append(rows,
+		tgbotapi.NewInlineKeyboardRow(prev, next),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔙 К дням недели", "main_time"),
+			tgbotapi.NewInlineKeyboardButtonData("🏠 Главное меню", "main_menu"),
+		),
+	)
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// handleDatePickerCallback показывает календарь для поиска по дате. Первое открытие отправляет
+// новое сообщение, переход между месяцами редактирует календарь на месте
+func (h *VetHandlers) handleDatePickerCallback(callback *tgbotapi.CallbackQuery) {
+	if callback.Data == datePickerNone {
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		return
+	}
+
+	now := time.Now().In(models.DefaultLocation())
+	month := now
+	if value := strings.TrimPrefix(callback.Data, "search_cal_"); value != callback.Data {
+		parsed, err := time.ParseInLocation(datePickerMonthLayout, value, now.Location())
+		if err != nil {
+			ErrorLog.Printf("Invalid search_cal callback data: %s", callback.Data)
+			h.bot.Request(tgbotapi.NewCallback(callback.ID, "Ошибка обработки запроса"))
+			return
+		}
+		month = parsed
+	}
+
+	text := "📅 *Выберите дату приема:*\n\nЯ покажу врачей, которые принимают в этот день, " +
+		"с учетом отпусков и изменений в расписании."
+	keyboard := buildDatePicker(month, now)
+
+	if callback.Data == "search_cal" {
+		msg := tgbotapi.NewMessage(callback.Message.Chat.ID, text)
+		msg.ParseMode = "Markdown"
+		msg.ReplyMarkup = keyboard
+		h.bot.Send(msg)
+	} else {
+		editMsg := tgbotapi.NewEditMessageTextAndMarkup(callback.Message.Chat.ID, callback.Message.MessageID, text, keyboard)
+		editMsg.ParseMode = "Markdown"
+		if _, err := h.bot.Send(editMsg); err != nil {
+			ErrorLog.Printf("Error editing date picker: %v", err)
+		}
+	}
+
+	h.bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+}
+
+// handleDateSelection ищет врачей, принимающих в выбранную дату (callback search_date_<ГГГГ-ММ-ДД>).
+// Дата - календарная, в каждой клинике она определяется по местному времени
+func (h *VetHandlers) handleDateSelection(callback *tgbotapi.CallbackQuery) {
+	now := time.Now().In(models.DefaultLocation())
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	date, err := time.ParseInLocation(models.SearchDateLayout, strings.TrimPrefix(callback.Data, "search_date_"), now.Location())
+	if err != nil {
+		ErrorLog.Printf("Invalid search_date callback data: %s", callback.Data)
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, "Ошибка обработки запроса"))
+		return
+	}
+	if date.Before(today) {
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, "Эта дата уже прошла"))
+		return
+	}
+	// Календарь таких дат не показывает, но старая или подделанная кнопка может их прислать
+	if date.After(today.AddDate(0, 0, scheduleLookaheadDays-1)) {
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, "Эта дата слишком далеко"))
+		return
+	}
+
+	title := fmt.Sprintf("Врачи, принимающие %s", formatAppointmentDate(date))
+	InfoLog.Printf("Searching for date: %s", date.Format(models.SearchDateLayout))
+
+	results := &searchResults{
+		Source:    resultsSourceCriteria,
+		Criteria:  models.SearchCriteria{Date: date.Format(models.SearchDateLayout)},
+		Title:     fmt.Sprintf("📅 *%s:*", title),
+		EmptyText: fmt.Sprintf("📅 *%s, не найдены*\n\nПопробуйте выбрать другую дату.", title),
+		Buttons: [][]resultsButton{
+			{{Text: "📅 Другая дата", Data: "search_cal_" + date.Format(datePickerMonthLayout)}},
+			{{Text: "🔙 К дням недели", Data: "main_time"}, {Text: "🏠 Главное меню", Data: "main_menu"}},
+		},
+	}
+
+	if err := h.startSearchResults(callback.Message.Chat.ID, callback.From.ID, results); err != nil {
+		ErrorLog.Printf("Error finding vets: %v", err)
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, "Ошибка при поиске врачей"))
+		return
+	}
+
+	h.bot.Request(tgbotapi.NewCallback(callback.ID, "Поиск завершен"))
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/drerr0r/vetbot/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================================
+// ТЕСТЫ ДЛЯ ПОИСКА ПО ДАТЕ И РАСПИСАНИЯ ПО ДАТАМ
+// ============================================================================
+
+func TestBuildDatePicker(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local) // пятница
+
+	t.Run("Current month starts from today", func(t *testing.T) {
+		markup := buildDatePicker(now, now)
+		callbacks := inlineCallbacks(&markup)
+
+		assert.Equal(t, "Октябрь 2026", markup.InlineKeyboard[0][0].Text)
+		assert.Contains(t, callbacks, "search_date_2026-10-16")
+		assert.Contains(t, callbacks, "search_date_2026-10-31")
+		assert.NotContains(t, callbacks, "search_date_2026-10-15")
+		assert.Contains(t, callbacks, "search_cal_2026-11")
+		assert.NotContains(t, callbacks, "search_cal_2026-09")
+
+		// Недели дополняются пустыми клетками, 16 октября - пятница третьей недели
+		for _, week := range markup.InlineKeyboard[2 : len(markup.InlineKeyboard)-2] {
+			assert.Len(t, week, 7)
+		}
+		assert.Equal(t, " ", markup.InlineKeyboard[4][3].Text)
+		assert.Equal(t, "16", markup.InlineKeyboard[4][4].Text)
+	})
+
+	t.Run("Last month is limited by lookahead", func(t *testing.T) {
+		markup := buildDatePicker(time.Date(2026, 12, 1, 0, 0, 0, 0, time.Local), now)
+		callbacks := inlineCallbacks(&markup)
+
+		assert.Contains(t, callbacks, "search_date_2026-12-14")
+		assert.NotContains(t, callbacks, "search_date_2026-12-15")
+		assert.Contains(t, callbacks, "search_cal_2026-11")
+		assert.NotContains(t, callbacks, "search_cal_2027-01")
+	})
+}
+
+func TestUpcomingScheduleLines(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local) // пятница
+	clinic := &models.Clinic{ID: 1, Name: "ВетКлиника"}
+	availability := &vetAvailability{
+		schedules: []*models.Schedule{
+			{VetID: 1, ClinicID: 1, DayOfWeek: 5, StartTime: "09:00", EndTime: "13:00", IsAvailable: true, Clinic: clinic},
+			{VetID: 1, ClinicID: 1, DayOfWeek: 6, StartTime: "10:00", EndTime: "14:00", IsAvailable: true, Clinic: clinic},
+		},
+		exceptions: []*models.ScheduleException{{
+			VetID:    1,
+			DateFrom: time.Date(2026, 10, 23, 0, 0, 0, 0, time.UTC),
+			DateTo:   time.Date(2026, 10, 23, 0, 0, 0, 0, time.UTC),
+		}},
+	}
+
+	lines := upcomingScheduleLines(availability, now, scheduleViewDays, true)
+
+	assert.Equal(t, []string{
+		"Сегодня, Пт 16.10: 09:00-13:00 (ВетКлиника)",
+		"Завтра, Сб 17.10: 10:00-14:00 (ВетКлиника)",
+		"Сб 24.10: 10:00-14:00 (ВетКлиника)",
+	}, lines)
+}
+
+func TestSearchByDate(t *testing.T) {
+	// Искомая дата - понедельник через две недели, чтобы не совпадать с ближайшим понедельником
+	today := time.Now()
+	date := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+	date = date.AddDate(0, 0, (8-weekdayNumber(date))%7+7)
+
+	setup := func() (*VetHandlers, *MockBot, *MockDatabase) {
+		h, mockBot, mockDB := CreateTestVetHandlers()
+		mockDB.AddTestClinic(1, "ВетКлиника", "ул. Ленина, 1", 1)
+		for id, name := range map[int]string{1: "Анна", 2: "Борис", 3: "Вера"} {
+			mockDB.AddTestVeterinarian(id, name, "Врачова", "+79990000000")
+		}
+
+		// Анна и Борис принимают по понедельникам, Вера - по вторникам
+		mockDB.Schedules[1] = &models.Schedule{ID: 1, VetID: 1, ClinicID: 1, DayOfWeek: 1, StartTime: "09:00", EndTime: "18:00", IsAvailable: true}
+		mockDB.Schedules[2] = &models.Schedule{ID: 2, VetID: 2, ClinicID: 1, DayOfWeek: 1, StartTime: "09:00", EndTime: "18:00", IsAvailable: true}
+		mockDB.Schedules[3] = &models.Schedule{ID: 3, VetID: 3, ClinicID: 1, DayOfWeek: 2, StartTime: "09:00", EndTime: "18:00", IsAvailable: true}
+
+		// Борис в отпуске в искомую дату, Вера в этот день принимает вместо вторника
+		require.NoError(t, mockDB.CreateScheduleException(&models.ScheduleException{
+			VetID: 2, DateFrom: date, DateTo: date, Reason: "Отпуск",
+		}))
+		require.NoError(t, mockDB.CreateScheduleException(&models.ScheduleException{
+			VetID: 3, ClinicID: sql.NullInt64{Int64: 1, Valid: true}, DateFrom: date, DateTo: date,
+			IsAvailable: true, StartTime: "12:00", EndTime: "16:00",
+		}))
+		return h, mockBot, mockDB
+	}
+
+	t.Run("Date picker opens from the time search menu", func(t *testing.T) {
+		h, mockBot, _ := setup()
+
+		resultsCallback(h, "search_cal")
+
+		msg := mockBot.GetLastMessage()
+		require.NotNil(t, msg)
+		assert.Contains(t, msg.Text, "Выберите дату приема")
+	})
+
+	t.Run("Results take exceptions into account", func(t *testing.T) {
+		h, mockBot, _ := setup()
+
+		resultsCallback(h, "search_date_"+date.Format(models.SearchDateLayout))
+
+		msg := mockBot.GetLastMessage()
+		require.NotNil(t, msg)
+		assert.Contains(t, msg.Text, "Врачи, принимающие "+formatAppointmentDate(date))
+		assert.Contains(t, msg.Text, "Анна Врачова")
+		assert.NotContains(t, msg.Text, "Борис")
+		assert.Contains(t, msg.Text, "Вера Врачова")
+		assert.Contains(t, msg.Text, formatScheduleDate(date, today)+": 12:00-16:00")
+	})
+
+	t.Run("Past date is rejected", func(t *testing.T) {
+		h, mockBot, _ := setup()
+
+		resultsCallback(h, "search_date_2020-01-06")
+
+		assert.Empty(t, mockBot.SentMessages)
+	})
+
+	t.Run("Date beyond lookahead is rejected", func(t *testing.T) {
+		h, mockBot, _ := setup()
+		now := time.Now().In(models.DefaultLocation())
+		late := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, scheduleLookaheadDays)
+
+		resultsCallback(h, "search_date_"+late.Format(models.SearchDateLayout))
+
+		assert.Empty(t, mockBot.SentMessages)
+	})
+}
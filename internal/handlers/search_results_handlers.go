@@ -0,0 +1,516 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drerr0r/vetbot/internal/models"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// resultsPageSize сколько врачей показывать на одной странице результатов
+const resultsPageSize = 5
+
+// searchResultsDataKey ключ данных сессии с курсором последнего поиска
+const searchResultsDataKey = "search_results"
+
+// Источники результатов поиска
+const (
+	resultsSourceCriteria = "criteria"
+	resultsSourceName     = "name"
+)
+
+// Варианты сортировки результатов
+const (
+	resultsSortRelevance  = "relevance"
+	resultsSortName       = "name"
+	resultsSortRating     = "rating"
+	resultsSortExperience = "experience"
+	resultsSortNextDay    = "nextday"
+)
+
+// resultsSortLabels названия сортировок для кнопок и заголовка
+var resultsSortLabels = map[string]string{
+	resultsSortRelevance:  "🎯 Точность",
+	resultsSortName:       "🔤 Имя",
+	resultsSortRating:     "⭐ Рейтинг",
+	resultsSortExperience: "💼 Опыт",
+	resultsSortNextDay:    "📅 Ближайший прием",
+}
+
+// resultsButton кнопка навигации, сохраняемая вместе с курсором
+type resultsButton struct {
+	Text string `json:"text"`
+	Data string `json:"data"`
+}
+
+// searchResults курсор просмотра результатов поиска. Хранится в сессии как JSON:
+// по нему страница пересобирается при листании, смене сортировки и возврате из карточки врача
+type searchResults struct {
+	Source    string                `json:"source"`
+	Criteria  models.SearchCriteria `json:"criteria"`
+	Query     string                `json:"query,omitempty"`
+	Title     string                `json:"title"`
+	EmptyText string                `json:"empty_text"`
+	Sort      string                `json:"sort"`
+	Page      int                   `json:"page"`
+	// VetIDs - найденные врачи в порядке сортировки. Страница загружает только своих врачей,
+	// поиск повторяется лишь при смене сортировки
+	VetIDs []int `json:"vet_ids"`
+	// Buttons - дополнительные ряды кнопок источника поиска ("К специализациям", "Главное меню")
+	Buttons [][]resultsButton `json:"buttons,omitempty"`
+}
+
+// loadSearchResults возвращает курсор последнего поиска пользователя или nil
+func (h *VetHandlers) loadSearchResults(userID int64) *searchResults {
+	raw, ok := h.stateManager.GetUserData(userID, searchResultsDataKey).(string)
+	if !ok {
+		return nil
+	}
+
+	results := &searchResults{}
+	if err := json.Unmarshal([]byte(raw), results); err != nil {
+		ErrorLog.Printf("Error decoding search results for user %d: %v", userID, err)
+		return nil
+	}
+	return results
+}
+
+// saveSearchResults сохраняет курсор поиска пользователя
+func (h *VetHandlers) saveSearchResults(userID int64, results *searchResults) {
+	raw, err := json.Marshal(results)
+	if err != nil {
+		ErrorLog.Printf("Error encoding search results for user %d: %v", userID, err)
+		return
+	}
+	h.stateManager.SetUserData(userID, searchResultsDataKey, string(raw))
+}
+
+// clearSearchResults забывает курсор поиска, чтобы карточки врачей не вели к устаревшим результатам
+func (h *VetHandlers) clearSearchResults(userID int64) {
+	h.stateManager.ClearUserDataByKey(userID, searchResultsDataKey)
+}
+
+// hasSearchResults проверяет, есть ли у пользователя результаты поиска для возврата из карточки
+func (h *VetHandlers) hasSearchResults(userID int64) bool {
+	return h.loadSearchResults(userID) != nil
+}
+
+// startSearchResults сохраняет новый поиск и отправляет первую страницу результатов отдельным сообщением
+func (h *VetHandlers) startSearchResults(chatID int64, userID int64, results *searchResults) error {
+	results.Page = 0
+	if results.Sort == "" {
+		results.Sort = resultsSortName
+		if results.Source == resultsSourceName {
+			results.Sort = resultsSortRelevance
+		}
+	}
+
+	if err := h.collectSearchResults(results); err != nil {
+		return err
+	}
+	h.saveSearchResults(userID, results)
+	return h.renderSearchResults(chatID, 0, results)
+}
+
+// handleSearchResultsCallback обрабатывает листание, сортировку и возврат к результатам (res_*)
+func (h *VetHandlers) handleSearchResultsCallback(callback *tgbotapi.CallbackQuery) {
+	userID := callback.From.ID
+	data := strings.TrimPrefix(callback.Data, "res_")
+
+	if data == "noop" {
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		return
+	}
+
+	results := h.loadSearchResults(userID)
+	if results == nil {
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, "Результаты поиска устарели, начните поиск заново"))
+		return
+	}
+
+	switch {
+	case data == "back":
+		// Возврат из карточки врача на ту же страницу
+	case strings.HasPrefix(data, "page_"):
+		page, err := strconv.Atoi(strings.TrimPrefix(data, "page_"))
+		if err != nil || page < 0 {
+			ErrorLog.Printf("Invalid results page: %s", callback.Data)
+			h.bot.Request(tgbotapi.NewCallback(callback.ID, "Ошибка обработки запроса"))
+			return
+		}
+		results.Page = page
+	case strings.HasPrefix(data, "sort_"):
+		sortKey := strings.TrimPrefix(data, "sort_")
+		if _, ok := resultsSortLabels[sortKey]; !ok {
+			ErrorLog.Printf("Invalid results sort: %s", callback.Data)
+			h.bot.Request(tgbotapi.NewCallback(callback.ID, "Ошибка обработки запроса"))
+			return
+		}
+		// Повторное нажатие на текущую сортировку ничего не меняет
+		if sortKey == results.Sort {
+			h.bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+			return
+		}
+		results.Sort = sortKey
+		results.Page = 0
+		results.VetIDs = nil
+	default:
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестная команда"))
+		return
+	}
+
+	// Поиск выполняется заново только для новой сортировки или курсора, сохраненного без врачей
+	if results.VetIDs == nil {
+		if err := h.collectSearchResults(results); err != nil {
+			ErrorLog.Printf("Error finding vets: %v", err)
+			h.bot.Request(tgbotapi.NewCallback(callback.ID, "Ошибка при поиске врачей"))
+			return
+		}
+	}
+
+	if err := h.renderSearchResults(callback.Message.Chat.ID, callback.Message.MessageID, results); err != nil {
+		ErrorLog.Printf("Error rendering search results: %v", err)
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, "Ошибка при поиске врачей"))
+		return
+	}
+
+	// Страница могла быть скорректирована, если врачей стало меньше
+	h.saveSearchResults(userID, results)
+	h.bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+}
+
+// fetchSearchResults выполняет поиск по курсору
+func (h *VetHandlers) fetchSearchResults(results *searchResults) ([]*models.Veterinarian, error) {
+	if results.Source == resultsSourceName {
+		return h.db.SearchVeterinariansByName(results.Query, nameSearchLimit)
+	}
+	return h.db.SearchVets(&results.Criteria)
+}
+
+// collectSearchResults выполняет поиск по курсору и запоминает врачей в порядке сортировки
+func (h *VetHandlers) collectSearchResults(results *searchResults) error {
+	vets, err := h.fetchSearchResults(results)
+	if err != nil {
+		return err
+	}
+	h.sortSearchResults(vets, results.Sort)
+
+	results.VetIDs = make([]int, 0, len(vets))
+	for _, vet := range vets {
+		results.VetIDs = append(results.VetIDs, models.GetVetIDAsIntOrZero(vet))
+	}
+	return nil
+}
+
+// renderSearchResults показывает страницу результатов: редактирует сообщение messageID или отправляет новое.
+// Из базы загружаются только врачи текущей страницы
+func (h *VetHandlers) renderSearchResults(chatID int64, messageID int, results *searchResults) error {
+	extraRows := results.keyboardRows()
+
+	if len(results.VetIDs) == 0 {
+		var keyboard *tgbotapi.InlineKeyboardMarkup
+		if len(extraRows) > 0 {
+			markup := tgbotapi.NewInlineKeyboardMarkup(extraRows...)
+			keyboard = &markup
+		}
+		h.sendOrEditResults(chatID, messageID, results.EmptyText, keyboard)
+		return nil
+	}
+
+	total := len(results.VetIDs)
+	pages := (total + resultsPageSize - 1) / resultsPageSize
+	if results.Page >= pages {
+		results.Page = pages - 1
+	}
+	start := results.Page * resultsPageSize
+	end := min(start+resultsPageSize, total)
+
+	// Врачи, удаленные после поиска, просто пропадают со страницы
+	vets, err := h.db.GetVeterinariansByIDs(results.VetIDs[start:end])
+	if err != nil {
+		return err
+	}
+	positions := make(map[int]int, end-start)
+	for i, vetID := range results.VetIDs[start:end] {
+		positions[vetID] = start + i + 1
+	}
+
+	var sb strings.Builder
+	sb.WriteString(results.Title)
+	sb.WriteString(fmt.Sprintf("\n\nНайдено врачей: %d · Страница %d из %d\nСортировка: %s\n\n",
+		total, results.Page+1, pages, resultsSortLabels[results.Sort]))
+
+	now := time.Now()
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, vet := range vets {
+		vetID := models.GetVetIDAsIntOrZero(vet)
+		availability, err := h.loadVetAvailability(vetID, now)
+		if err != nil {
+			ErrorLog.Printf("Error getting schedules for vet %d: %v", vetID, err)
+		}
+		sb.WriteString(h.formatSearchResultEntry(vet, positions[vetID], availability, &results.Criteria))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%d. %s %s", positions[vetID], vet.FirstName, vet.LastName),
+				fmt.Sprintf("vet_details_%d", vetID)),
+		))
+	}
+
+	if pages > 1 {
+		var nav []tgbotapi.InlineKeyboardButton
+		if results.Page > 0 {
+			nav = append(nav, tgbotapi.NewInlineKeyboardButtonData("◀️", fmt.Sprintf("res_page_%d", results.Page-1)))
+		}
+		nav = append(nav, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%d/%d", results.Page+1, pages), "res_noop"))
+		if results.Page < pages-1 {
+			nav = append(nav, tgbotapi.NewInlineKeyboardButtonData("▶️", fmt.Sprintf("res_page_%d", results.Page+1)))
+		}
+		rows = append(rows, nav)
+	}
+
+	var sortRow []tgbotapi.InlineKeyboardButton
+	for _, sortKey := range results.sortOptions() {
+		label, data := resultsSortLabels[sortKey], "res_sort_"+sortKey
+		if sortKey == results.Sort {
+			label, data = "✅ "+label, "res_noop"
+		}
+		sortRow = append(sortRow, tgbotapi.NewInlineKeyboardButtonData(label, data))
+		if len(sortRow) == 2 {
+			rows = append(rows, sortRow)
+			sortRow = nil
+		}
+	}
+	if len(sortRow) > 0 {
+		rows = append(rows, sortRow)
+	}
+
+	rows = append(rows, extraRows...)
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	h.sendOrEditResults(chatID, messageID, sb.String(), &keyboard)
+	return nil
+}
+
+// sortOptions возвращает доступные для курсора сортировки
+func (r *searchResults) sortOptions() []string {
+	options := []string{resultsSortName, resultsSortRating, resultsSortExperience, resultsSortNextDay}
+	if r.Source == resultsSourceName {
+		options = append([]string{resultsSortRelevance}, options...)
+	}
+	return options
+}
+
+// keyboardRows возвращает сохраненные кнопки источника поиска
+func (r *searchResults) keyboardRows() [][]tgbotapi.InlineKeyboardButton {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, buttons := range r.Buttons {
+		var row []tgbotapi.InlineKeyboardButton
+		for _, button := range buttons {
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData(button.Text, button.Data))
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// sortSearchResults сортирует врачей. Рейтинги и расписания загружаются только для сортировок по ним
+func (h *VetHandlers) sortSearchResults(vets []*models.Veterinarian, sortKey string) {
+	byName := func(a, b *models.Veterinarian) bool {
+		if a.LastName != b.LastName {
+			return a.LastName < b.LastName
+		}
+		return a.FirstName < b.FirstName
+	}
+
+	switch sortKey {
+	case resultsSortName:
+		sort.SliceStable(vets, func(i, j int) bool { return byName(vets[i], vets[j]) })
+
+	case resultsSortExperience:
+		sort.SliceStable(vets, func(i, j int) bool {
+			if vets[i].ExperienceYears.Int64 != vets[j].ExperienceYears.Int64 {
+				return vets[i].ExperienceYears.Int64 > vets[j].ExperienceYears.Int64
+			}
+			return byName(vets[i], vets[j])
+		})
+
+	case resultsSortRating:
+		ratings := make(map[int]*models.ReviewStats)
+		for _, vet := range vets {
+			vetID := models.GetVetIDAsIntOrZero(vet)
+			stats, err := h.db.GetReviewStats(vetID)
+			if err != nil || stats == nil {
+				stats = &models.ReviewStats{}
+			}
+			ratings[vetID] = stats
+		}
+		sort.SliceStable(vets, func(i, j int) bool {
+			a := ratings[models.GetVetIDAsIntOrZero(vets[i])]
+			b := ratings[models.GetVetIDAsIntOrZero(vets[j])]
+			if a.AverageRating != b.AverageRating {
+				return a.AverageRating > b.AverageRating
+			}
+			if a.ApprovedReviews != b.ApprovedReviews {
+				return a.ApprovedReviews > b.ApprovedReviews
+			}
+			return byName(vets[i], vets[j])
+		})
+
+	case resultsSortNextDay:
+		now := time.Now()
+		days := make(map[int]int)
+		for _, vet := range vets {
+			vetID := models.GetVetIDAsIntOrZero(vet)
+			availability, err := h.loadVetAvailability(vetID, now)
+			if err != nil {
+				ErrorLog.Printf("Error getting schedules for vet %d: %v", vetID, err)
+			}
+			days[vetID] = h.daysUntilWorking(availability, now)
+		}
+		sort.SliceStable(vets, func(i, j int) bool {
+			a := days[models.GetVetIDAsIntOrZero(vets[i])]
+			b := days[models.GetVetIDAsIntOrZero(vets[j])]
+			if a != b {
+				return a < b
+			}
+			return byName(vets[i], vets[j])
+		})
+	}
+}
+
+// daysUntilWorking возвращает, через сколько дней ближайший прием врача с учетом исключений
+// (0 - сегодня, scheduleLookaheadDays - приема нет)
+func (h *VetHandlers) daysUntilWorking(availability *vetAvailability, now time.Time) int {
+	schedule, date := h.findNearestWorkingDay(availability, now)
+	if schedule == nil {
+		return scheduleLookaheadDays
+	}
+	now = availability.localNow(now)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return int(date.Sub(today).Hours()+12) / 24
+}
+
+// escapeMarkdown экранирует символы разметки Markdown в данных из справочника
+func escapeMarkdown(text string) string {
+	return tgbotapi.EscapeText(tgbotapi.ModeMarkdown, text)
+}
+
+// formatSearchResultEntry форматирует врача в списке результатов
+func (h *VetHandlers) formatSearchResultEntry(vet *models.Veterinarian, index int, availability *vetAvailability, criteria *models.SearchCriteria) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("*%d. %s %s*", index, escapeMarkdown(vet.FirstName), escapeMarkdown(vet.LastName)))
+	if stats, err := h.db.GetReviewStats(models.GetVetIDAsIntOrZero(vet)); err == nil && stats != nil && stats.ApprovedReviews > 0 {
+		sb.WriteString(fmt.Sprintf(" ⭐ %.1f (%d)", stats.AverageRating, stats.ApprovedReviews))
+	}
+	sb.WriteString("\n")
+
+	// Внутри `кода` Markdown не экранируется, поэтому из телефона убираются только обратные кавычки
+	sb.WriteString(fmt.Sprintf("📞 `%s`", strings.ReplaceAll(vet.Phone, "`", "")))
+	if vet.Email.Valid && vet.Email.String != "" {
+		sb.WriteString(fmt.Sprintf(" 📧 %s", escapeMarkdown(vet.Email.String)))
+	}
+	if vet.ExperienceYears.Valid {
+		sb.WriteString(fmt.Sprintf(" 💼 %d лет", vet.ExperienceYears.Int64))
+	}
+	if vet.City != nil && vet.City.Name != "" {
+		sb.WriteString(fmt.Sprintf(" 🏙️ %s", escapeMarkdown(vet.City.Name)))
+	}
+	sb.WriteString("\n")
+
+	if len(vet.Specializations) > 0 {
+		specNames := make([]string, len(vet.Specializations))
+		for i, spec := range vet.Specializations {
+			specNames[i] = escapeMarkdown(spec.Name)
+		}
+		sb.WriteString(fmt.Sprintf("🎯 %s\n", strings.Join(specNames, ", ")))
+	}
+
+	now := time.Now()
+	if schedule, date := h.resultSchedule(availability, criteria, now); schedule != nil {
+		sb.WriteString(fmt.Sprintf("🕐 %s: %s-%s", formatScheduleDate(date, now), schedule.StartTime, schedule.EndTime))
+		if schedule.Clinic != nil && schedule.Clinic.Name != "" {
+			sb.WriteString(fmt.Sprintf(" (%s)", escapeMarkdown(schedule.Clinic.Name)))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// resultSchedule выбирает прием для показа и его дату: идущий сейчас, в искомое время, в искомый день
+// или ближайший. День недели из критериев относится к ближайшей такой дате, как и в поиске.
+// Все сравнения ведутся по местному времени клиник врача
+func (h *VetHandlers) resultSchedule(availability *vetAvailability, criteria *models.SearchCriteria, now time.Time) (*models.Schedule, time.Time) {
+	if availability == nil {
+		return nil, time.Time{}
+	}
+	now = availability.localNow(now)
+
+	if criteria.OpenNow {
+		day, clock := weekdayNumber(now), now.Format("15:04")
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		for _, schedule := range availability.week(now) {
+			if !schedule.IsWorkingAt(day, clock) {
+				continue
+			}
+			// Ночная смена, начавшаяся вчера
+			if schedule.DayOfWeek != day {
+				return schedule, today.AddDate(0, 0, -1)
+			}
+			return schedule, today
+		}
+		return nil, time.Time{}
+	}
+
+	windowStart, day, err := criteria.ScheduleWindow(now)
+	if err != nil || (criteria.Time == "" && day == 0) {
+		return h.findNearestWorkingDay(availability, now)
+	}
+
+	dateOf := func(schedule *models.Schedule) time.Time {
+		return windowStart.AddDate(0, 0, (schedule.DayOfWeek-weekdayNumber(windowStart)+7)%7)
+	}
+
+	for _, schedule := range availability.week(windowStart) {
+		if criteria.Time != "" && schedule.IsWorkingAt(day, criteria.Time) {
+			return schedule, dateOf(schedule)
+		}
+		if criteria.Time == "" && schedule.DayOfWeek == day {
+			return schedule, dateOf(schedule)
+		}
+	}
+	return nil, time.Time{}
+}
+
+// isMessageNotModified проверяет, что Telegram отклонил редактирование, потому что текст и кнопки не изменились
+func isMessageNotModified(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "message is not modified")
+}
+
+// sendOrEditResults редактирует сообщение с результатами на месте или отправляет новое.
+// Новое сообщение отправляется, только если редактировать нечего или редактирование не удалось
+// по другой причине, чем неизменившееся содержимое - иначе чат засоряется копиями результатов
+func (h *VetHandlers) sendOrEditResults(chatID int64, messageID int, text string, keyboard *tgbotapi.InlineKeyboardMarkup) {
+	if messageID > 0 {
+		editMsg := tgbotapi.NewEditMessageText(chatID, messageID, text)
+		editMsg.ParseMode = "Markdown"
+		editMsg.ReplyMarkup = keyboard
+		_, err := h.bot.Send(editMsg)
+		if err == nil || isMessageNotModified(err) {
+			return
+		}
+		ErrorLog.Printf("Error editing search results: %v", err)
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "Markdown"
+	if keyboard != nil {
+		msg.ReplyMarkup = *keyboard
+	}
+	h.bot.Send(msg)
+}
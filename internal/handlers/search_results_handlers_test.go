@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/drerr0r/vetbot/internal/models"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================================
+// ТЕСТЫ ДЛЯ ПОСТРАНИЧНОГО ПРОСМОТРА РЕЗУЛЬТАТОВ ПОИСКА
+// ============================================================================
+
+// setupSearchResultsVets заполняет мок семью терапевтами с разным опытом
+func setupSearchResultsVets(mockDB *MockDatabase) {
+	spec := &models.Specialization{ID: 1, Name: "Терапевт"}
+	mockDB.Specializations[1] = spec
+
+	names := []string{"Алексей", "Борис", "Виктор", "Григорий", "Денис", "Евгений", "Жанна"}
+	for i, name := range names {
+		id := i + 1
+		mockDB.Veterinarians[id] = &models.Veterinarian{
+			ID:              sql.NullInt64{Int64: int64(id), Valid: true},
+			FirstName:       name,
+			LastName:        "Врачов",
+			Phone:           fmt.Sprintf("+7900000000%d", id),
+			ExperienceYears: sql.NullInt64{Int64: int64(id), Valid: true},
+			IsActive:        true,
+			Specializations: []*models.Specialization{spec},
+		}
+	}
+}
+
+// resultsCallback отправляет callback результатов поиска от пользователя 100 к сообщению 42
+func resultsCallback(h *VetHandlers, data string) {
+	h.HandleCallback(NewTestUpdate().WithCallback(data, 100, 42).Build())
+}
+
+func TestSearchResultsPagination(t *testing.T) {
+	t.Run("First page is a single message", func(t *testing.T) {
+		h, mockBot, mockDB := CreateTestVetHandlers()
+		setupSearchResultsVets(mockDB)
+
+		h.HandleSearchBySpecialization(NewTestUpdate().WithCallback("search_spec_1", 100, 42).Build(), 1)
+
+		require.Len(t, mockBot.SentMessages, 1)
+		msg := mockBot.SentMessages[0]
+		assert.Contains(t, msg.Text, "Найдено врачей: 7 · Страница 1 из 2")
+		assert.Contains(t, msg.Text, "1. Алексей Врачов")
+		assert.Contains(t, msg.Text, "5. Денис Врачов")
+		assert.NotContains(t, msg.Text, "Евгений")
+
+		markup := msg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+		callbacks := inlineCallbacks(&markup)
+		assert.Contains(t, callbacks, "vet_details_1")
+		assert.Contains(t, callbacks, "res_page_1")
+		assert.NotContains(t, callbacks, "res_page_-1")
+		assert.Contains(t, callbacks, "res_sort_experience")
+	})
+
+	t.Run("Next page edits the same message", func(t *testing.T) {
+		h, mockBot, mockDB := CreateTestVetHandlers()
+		setupSearchResultsVets(mockDB)
+		h.HandleSearchBySpecialization(NewTestUpdate().WithCallback("search_spec_1", 100, 42).Build(), 1)
+
+		resultsCallback(h, "res_page_1")
+
+		require.Len(t, mockBot.SentMessages, 1)
+		edited := mockBot.GetLastEditedMessage()
+		require.NotNil(t, edited)
+		assert.Equal(t, 42, edited.MessageID)
+		assert.Contains(t, edited.Text, "Страница 2 из 2")
+		assert.Contains(t, edited.Text, "6. Евгений Врачов")
+		assert.Contains(t, edited.Text, "7. Жанна Врачов")
+		assert.Contains(t, inlineCallbacks(edited.ReplyMarkup), "res_page_0")
+	})
+
+	t.Run("Sort by experience restarts from the first page", func(t *testing.T) {
+		h, mockBot, mockDB := CreateTestVetHandlers()
+		setupSearchResultsVets(mockDB)
+		h.HandleSearchBySpecialization(NewTestUpdate().WithCallback("search_spec_1", 100, 42).Build(), 1)
+		resultsCallback(h, "res_page_1")
+
+		resultsCallback(h, "res_sort_experience")
+
+		edited := mockBot.GetLastEditedMessage()
+		assert.Contains(t, edited.Text, "Страница 1 из 2")
+		assert.Contains(t, edited.Text, "Сортировка: 💼 Опыт")
+		assert.Contains(t, edited.Text, "1. Жанна Врачов")
+		assert.NotContains(t, inlineCallbacks(edited.ReplyMarkup), "res_sort_experience")
+		assert.Contains(t, inlineCallbacks(edited.ReplyMarkup), "res_noop")
+	})
+
+	t.Run("Tapping the current sort sends nothing", func(t *testing.T) {
+		h, mockBot, mockDB := CreateTestVetHandlers()
+		setupSearchResultsVets(mockDB)
+		h.HandleSearchBySpecialization(NewTestUpdate().WithCallback("search_spec_1", 100, 42).Build(), 1)
+		resultsCallback(h, "res_sort_experience")
+		edits := len(mockBot.EditedMessages)
+
+		// Кнопка старой копии сообщения с той же сортировкой
+		resultsCallback(h, "res_sort_experience")
+
+		require.Len(t, mockBot.SentMessages, 1)
+		assert.Len(t, mockBot.EditedMessages, edits)
+	})
+
+	t.Run("Unchanged message is not sent again", func(t *testing.T) {
+		h, mockBot, mockDB := CreateTestVetHandlers()
+		setupSearchResultsVets(mockDB)
+		h.HandleSearchBySpecialization(NewTestUpdate().WithCallback("search_spec_1", 100, 42).Build(), 1)
+		mockBot.EditError = fmt.Errorf("Bad Request: message is not modified: specified new message content and reply markup are exactly the same")
+
+		// Листание на ту же страницу со старой копии сообщения
+		resultsCallback(h, "res_page_0")
+		require.Len(t, mockBot.SentMessages, 1)
+
+		// Другая ошибка редактирования - результаты отправляются новым сообщением
+		mockBot.EditError = fmt.Errorf("Bad Request: message to edit not found")
+		resultsCallback(h, "res_page_1")
+		assert.Len(t, mockBot.SentMessages, 2)
+	})
+
+	t.Run("Sort by rating", func(t *testing.T) {
+		h, mockBot, mockDB := CreateTestVetHandlers()
+		setupSearchResultsVets(mockDB)
+		mockDB.GetReviewStatsFunc = func(vetID int) (*models.ReviewStats, error) {
+			if vetID == 4 {
+				return &models.ReviewStats{AverageRating: 4.8, ApprovedReviews: 12}, nil
+			}
+			return &models.ReviewStats{}, nil
+		}
+		h.HandleSearchBySpecialization(NewTestUpdate().WithCallback("search_spec_1", 100, 42).Build(), 1)
+
+		resultsCallback(h, "res_sort_rating")
+
+		edited := mockBot.GetLastEditedMessage()
+		assert.Contains(t, edited.Text, "1. Григорий Врачов* ⭐ 4.8 (12)")
+	})
+
+	t.Run("Back from vet card returns to the same page", func(t *testing.T) {
+		h, mockBot, mockDB := CreateTestVetHandlers()
+		setupSearchResultsVets(mockDB)
+		h.HandleSearchBySpecialization(NewTestUpdate().WithCallback("search_spec_1", 100, 42).Build(), 1)
+		resultsCallback(h, "res_page_1")
+
+		resultsCallback(h, "vet_details_6")
+		card := mockBot.GetLastEditedMessage()
+		assert.Contains(t, card.Text, "Евгений")
+		assert.Contains(t, inlineCallbacks(card.ReplyMarkup), "res_back")
+
+		resultsCallback(h, "res_back")
+		edited := mockBot.GetLastEditedMessage()
+		assert.Contains(t, edited.Text, "Страница 2 из 2")
+		assert.Contains(t, edited.Text, "Евгений Врачов")
+	})
+
+	t.Run("Page flip uses saved results without a new search", func(t *testing.T) {
+		h, mockBot, mockDB := CreateTestVetHandlers()
+		setupSearchResultsVets(mockDB)
+		h.HandleSearchBySpecialization(NewTestUpdate().WithCallback("search_spec_1", 100, 42).Build(), 1)
+
+		// Новый врач после поиска встал бы первым по имени, если бы поиск повторялся
+		mockDB.Veterinarians[8] = &models.Veterinarian{
+			ID: sql.NullInt64{Int64: 8, Valid: true}, FirstName: "Аарон", LastName: "Врачов", IsActive: true,
+			Specializations: []*models.Specialization{mockDB.Specializations[1]},
+		}
+		delete(mockDB.Veterinarians, 7)
+
+		resultsCallback(h, "res_page_1")
+		edited := mockBot.GetLastEditedMessage()
+		assert.Contains(t, edited.Text, "Найдено врачей: 7 · Страница 2 из 2")
+		assert.Contains(t, edited.Text, "6. Евгений Врачов")
+		assert.NotContains(t, edited.Text, "Жанна")
+
+		resultsCallback(h, "res_page_0")
+		assert.NotContains(t, mockBot.GetLastEditedMessage().Text, "Аарон")
+		assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7}, h.loadSearchResults(100).VetIDs)
+	})
+
+	t.Run("Vet card in a group chat returns to the user's results", func(t *testing.T) {
+		h, mockBot, mockDB := CreateTestVetHandlers()
+		setupSearchResultsVets(mockDB)
+		search := NewTestUpdate().WithCallback("search_spec_1", -500, 42).Build()
+		search.CallbackQuery.From.ID = 100
+		h.HandleSearchBySpecialization(search, 1)
+
+		details := NewTestUpdate().WithCallback("vet_details_1", -500, 42).Build()
+		details.CallbackQuery.From.ID = 100
+		h.HandleCallback(details)
+
+		assert.Contains(t, inlineCallbacks(mockBot.GetLastEditedMessage().ReplyMarkup), "res_back")
+	})
+
+	t.Run("Directory data is escaped for Markdown", func(t *testing.T) {
+		h, mockBot, mockDB := CreateTestVetHandlers()
+		setupSearchResultsVets(mockDB)
+		mockDB.Veterinarians[1].FirstName = "Алекс_ей"
+		mockDB.Veterinarians[1].Email = sql.NullString{String: "vet_1@example.com", Valid: true}
+		mockDB.Specializations[1].Name = "Терапевт & хирург"
+
+		h.HandleSearchBySpecialization(NewTestUpdate().WithCallback("search_spec_1", 100, 42).Build(), 1)
+
+		text := mockBot.SentMessages[0].Text
+		assert.Contains(t, text, `Алекс\_ей Врачов`)
+		assert.Contains(t, text, `vet\_1@example.com`)
+		assert.Contains(t, text, "Терапевт & хирург")
+		assert.NotContains(t, text, "&amp;")
+	})
+
+	t.Run("Main menu forgets the results", func(t *testing.T) {
+		h, mockBot, mockDB := CreateTestVetHandlers()
+		setupSearchResultsVets(mockDB)
+		h.HandleSearchBySpecialization(NewTestUpdate().WithCallback("search_spec_1", 100, 42).Build(), 1)
+		assert.True(t, h.hasSearchResults(100))
+
+		h.clearSearchResults(100)
+		editsBefore := len(mockBot.EditedMessages)
+		resultsCallback(h, "res_page_1")
+
+		assert.False(t, h.hasSearchResults(100))
+		assert.Len(t, mockBot.EditedMessages, editsBefore)
+	})
+
+	t.Run("Page out of range is clamped", func(t *testing.T) {
+		h, mockBot, mockDB := CreateTestVetHandlers()
+		setupSearchResultsVets(mockDB)
+		h.HandleSearchBySpecialization(NewTestUpdate().WithCallback("search_spec_1", 100, 42).Build(), 1)
+
+		resultsCallback(h, "res_page_9")
+
+		assert.Contains(t, mockBot.GetLastEditedMessage().Text, "Страница 2 из 2")
+		assert.Equal(t, 1, h.loadSearchResults(100).Page)
+	})
+}
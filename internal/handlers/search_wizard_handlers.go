@@ -0,0 +1,417 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/drerr0r/vetbot/internal/models"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// searchWizardDataKey ключ данных сессии с фильтрами пошагового поиска
+const searchWizardDataKey = "search_wizard"
+
+// Шаги пошагового поиска в порядке прохождения
+const (
+	wizardStepSpec  = "spec"
+	wizardStepCity  = "city"
+	wizardStepDay   = "day"
+	wizardStepPlace = "place"
+)
+
+// wizardSteps порядок шагов мастера
+var wizardSteps = []string{wizardStepSpec, wizardStepCity, wizardStepDay, wizardStepPlace}
+
+// searchWizard фильтры пошагового поиска. Хранится в сессии как JSON, чтобы пережить перезапуск бота
+type searchWizard struct {
+	Criteria models.SearchCriteria `json:"criteria"`
+	// Editing - шаг открыт с экрана сводки, после выбора возвращаемся к ней
+	Editing bool `json:"editing"`
+}
+
+// loadSearchWizard возвращает фильтры пошагового поиска пользователя
+func (h *VetHandlers) loadSearchWizard(userID int64) *searchWizard {
+	wizard := &searchWizard{}
+	if raw, ok := h.stateManager.GetUserData(userID, searchWizardDataKey).(string); ok {
+		if err := json.Unmarshal([]byte(raw), wizard); err != nil {
+			ErrorLog.Printf("Error decoding search wizard for user %d: %v", userID, err)
+			return &searchWizard{}
+		}
+	}
+	return wizard
+}
+
+// saveSearchWizard сохраняет фильтры пошагового поиска пользователя
+func (h *VetHandlers) saveSearchWizard(userID int64, wizard *searchWizard) {
+	raw, err := json.Marshal(wizard)
+	if err != nil {
+		ErrorLog.Printf("Error encoding search wizard for user %d: %v", userID, err)
+		return
+	}
+	h.stateManager.SetUserData(userID, searchWizardDataKey, string(raw))
+}
+
+// HandleSearchWizard начинает пошаговый поиск врача с выбора специализации
+func (h *VetHandlers) HandleSearchWizard(update tgbotapi.Update) {
+	var chatID int64
+	var messageID int
+	var userID int64
+
+	if update.CallbackQuery != nil {
+		chatID = update.CallbackQuery.Message.Chat.ID
+		messageID = update.CallbackQuery.Message.MessageID
+		userID = update.CallbackQuery.From.ID
+		h.bot.Request(tgbotapi.NewCallback(update.CallbackQuery.ID, ""))
+	} else if update.Message != nil {
+		chatID = update.Message.Chat.ID
+		userID = update.Message.From.ID
+	} else {
+		ErrorLog.Printf("Error: both CallbackQuery and Message are nil")
+		return
+	}
+
+	InfoLog.Printf("Starting search wizard for user %d", userID)
+	h.saveSearchWizard(userID, &searchWizard{})
+	h.showWizardStep(chatID, messageID, wizardStepSpec, &searchWizard{})
+}
+
+// handleSearchWizardCallback обрабатывает callback пошагового поиска (wiz_*)
+func (h *VetHandlers) handleSearchWizardCallback(callback *tgbotapi.CallbackQuery) {
+	userID := callback.From.ID
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+	data := strings.TrimPrefix(callback.Data, "wiz_")
+	wizard := h.loadSearchWizard(userID)
+
+	h.bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+
+	switch {
+	case data == "summary":
+		wizard.Editing = false
+		h.saveSearchWizard(userID, wizard)
+		h.showWizardSummary(chatID, messageID, wizard)
+		return
+	case data == "run":
+		h.runSearchWizard(chatID, userID, wizard)
+		return
+	case data == "reset":
+		wizard = &searchWizard{}
+		h.saveSearchWizard(userID, wizard)
+		h.showWizardStep(chatID, messageID, wizardStepSpec, wizard)
+		return
+	case strings.HasPrefix(data, "edit_"):
+		step := strings.TrimPrefix(data, "edit_")
+		wizard.Editing = true
+		h.saveSearchWizard(userID, wizard)
+		h.showWizardStep(chatID, messageID, step, wizard)
+		return
+	}
+
+	// Выбор значения: wiz_<шаг>_<значение>
+	parts := strings.SplitN(data, "_", 2)
+	if len(parts) != 2 {
+		ErrorLog.Printf("Invalid wizard callback data: %s", callback.Data)
+		return
+	}
+	value, err := strconv.Atoi(parts[1])
+	if err != nil || value < 0 {
+		ErrorLog.Printf("Invalid wizard callback value: %s", callback.Data)
+		return
+	}
+
+	step := parts[0]
+	switch step {
+	case wizardStepSpec:
+		wizard.Criteria.SpecializationID = value
+	case wizardStepCity:
+		if wizard.Criteria.CityID != value {
+			// Районы и метро зависят от города
+			wizard.Criteria.District = ""
+			wizard.Criteria.MetroStation = ""
+		}
+		wizard.Criteria.CityID = value
+	case wizardStepDay:
+		if value > 7 {
+			ErrorLog.Printf("Invalid wizard day: %d", value)
+			return
+		}
+		wizard.Criteria.DayOfWeek = value
+	case "district", "metro":
+		districts, metros := h.wizardPlaces(&wizard.Criteria)
+		wizard.Criteria.District = ""
+		wizard.Criteria.MetroStation = ""
+		if step == "district" && value > 0 && value <= len(districts) {
+			wizard.Criteria.District = districts[value-1]
+		}
+		if step == "metro" && value > 0 && value <= len(metros) {
+			wizard.Criteria.MetroStation = metros[value-1]
+		}
+		step = wizardStepPlace
+	default:
+		ErrorLog.Printf("Unknown wizard step: %s", callback.Data)
+		return
+	}
+
+	if wizard.Editing {
+		wizard.Editing = false
+		h.saveSearchWizard(userID, wizard)
+		h.showWizardSummary(chatID, messageID, wizard)
+		return
+	}
+
+	h.saveSearchWizard(userID, wizard)
+	h.showWizardStep(chatID, messageID, nextWizardStep(step), wizard)
+}
+
+// nextWizardStep возвращает шаг после step или пустую строку, если шаги закончились
+func nextWizardStep(step string) string {
+	for i, current := range wizardSteps {
+		if current == step && i+1 < len(wizardSteps) {
+			return wizardSteps[i+1]
+		}
+	}
+	return ""
+}
+
+// showWizardStep показывает шаг мастера; после последнего шага показывается сводка
+func (h *VetHandlers) showWizardStep(chatID int64, messageID int, step string, wizard *searchWizard) {
+	var text string
+	var rows [][]tgbotapi.InlineKeyboardButton
+
+	switch step {
+	case wizardStepSpec:
+		specializations, err := h.db.GetAllSpecializations()
+		if err != nil {
+			ErrorLog.Printf("Error getting specializations: %v", err)
+		}
+		text = "🧭 *Подбор врача — шаг 1 из 4*\n\nКакой специалист нужен?"
+		for _, spec := range specializations {
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(spec.Name, fmt.Sprintf("wiz_spec_%d", spec.ID)),
+			))
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("⏭ Любой специалист", "wiz_spec_0"),
+		))
+
+	case wizardStepCity:
+		cities, err := h.db.GetAllCities()
+		if err != nil {
+			ErrorLog.Printf("Error getting cities: %v", err)
+		}
+		text = "🧭 *Подбор врача — шаг 2 из 4*\n\nВ каком городе искать?"
+		var row []tgbotapi.InlineKeyboardButton
+		for _, city := range cities {
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData(city.Name, fmt.Sprintf("wiz_city_%d", city.ID)))
+			if len(row) == 2 {
+				rows = append(rows, row)
+				row = nil
+			}
+		}
+		if len(row) > 0 {
+			rows = append(rows, row)
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("⏭ Любой город", "wiz_city_0"),
+		))
+
+	case wizardStepDay:
+		text = "🧭 *Подбор врача — шаг 3 из 4*\n\nВ какой день нужен прием?"
+		dayNames := []string{"Пн", "Вт", "Ср", "Чт", "Пт", "Сб", "Вс"}
+		var row []tgbotapi.InlineKeyboardButton
+		for i, name := range dayNames {
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData(name, fmt.Sprintf("wiz_day_%d", i+1)))
+		}
+		rows = append(rows, row[:4], row[4:])
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("⏭ Любой день", "wiz_day_0"),
+		))
+
+	case wizardStepPlace:
+		districts, metros := h.wizardPlaces(&wizard.Criteria)
+		if len(districts) == 0 && len(metros) == 0 {
+			// Выбирать не из чего - шаг пропускается
+			h.showWizardSummary(chatID, messageID, wizard)
+			return
+		}
+		text = "🧭 *Подбор врача — шаг 4 из 4*\n\nВыберите район или станцию метро:"
+		for i, district := range districts {
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("🏘️ "+district, fmt.Sprintf("wiz_district_%d", i+1)),
+			))
+		}
+		for i, metro := range metros {
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("🚇 "+metro, fmt.Sprintf("wiz_metro_%d", i+1)),
+			))
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("⏭ Любой район", "wiz_district_0"),
+		))
+
+	default:
+		h.showWizardSummary(chatID, messageID, wizard)
+		return
+	}
+
+	if wizard.Editing {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔙 К фильтрам", "wiz_summary"),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("🏠 Главное меню", "main_menu"),
+	))
+
+	h.sendOrEditWizardMessage(chatID, messageID, text, tgbotapi.NewInlineKeyboardMarkup(rows...))
+}
+
+// wizardPlaces возвращает отсортированные районы и станции метро активных клиник выбранного города (или всех)
+func (h *VetHandlers) wizardPlaces(criteria *models.SearchCriteria) ([]string, []string) {
+	var clinics []*models.Clinic
+	var err error
+	if criteria.CityID > 0 {
+		clinics, err = h.db.GetClinicsByCity(criteria.CityID)
+	} else {
+		clinics, err = h.db.GetAllClinics()
+	}
+	if err != nil {
+		ErrorLog.Printf("Error getting clinics for wizard: %v", err)
+		return nil, nil
+	}
+
+	districtSet := make(map[string]bool)
+	metroSet := make(map[string]bool)
+	for _, clinic := range clinics {
+		if !clinic.IsActive {
+			continue
+		}
+		if clinic.District.Valid && strings.TrimSpace(clinic.District.String) != "" {
+			districtSet[strings.TrimSpace(clinic.District.String)] = true
+		}
+		if clinic.MetroStation.Valid && strings.TrimSpace(clinic.MetroStation.String) != "" {
+			metroSet[strings.TrimSpace(clinic.MetroStation.String)] = true
+		}
+	}
+
+	return sortedKeys(districtSet), sortedKeys(metroSet)
+}
+
+// sortedKeys возвращает ключи множества по алфавиту
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// showWizardSummary показывает выбранные фильтры с возможностью изменить любой из них
+func (h *VetHandlers) showWizardSummary(chatID int64, messageID int, wizard *searchWizard) {
+	criteria := &wizard.Criteria
+
+	var sb strings.Builder
+	sb.WriteString("🧭 *Подбор врача — фильтры*\n\n")
+	sb.WriteString(fmt.Sprintf("🎯 Специализация: %s\n", escapeMarkdown(h.wizardSpecName(criteria.SpecializationID))))
+	sb.WriteString(fmt.Sprintf("🏙️ Город: %s\n", escapeMarkdown(h.wizardCityName(criteria.CityID))))
+	if criteria.DayOfWeek > 0 {
+		sb.WriteString(fmt.Sprintf("📅 День: %s\n", getDayName(criteria.DayOfWeek)))
+	} else {
+		sb.WriteString("📅 День: любой\n")
+	}
+	switch {
+	case criteria.District != "":
+		sb.WriteString(fmt.Sprintf("🏘️ Район: %s\n", escapeMarkdown(criteria.District)))
+	case criteria.MetroStation != "":
+		sb.WriteString(fmt.Sprintf("🚇 Метро: %s\n", escapeMarkdown(criteria.MetroStation)))
+	default:
+		sb.WriteString("🏘️ Район/метро: любой\n")
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔎 Показать врачей", "wiz_run"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✏️ Специализация", "wiz_edit_spec"),
+			tgbotapi.NewInlineKeyboardButtonData("✏️ Город", "wiz_edit_city"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✏️ День", "wiz_edit_day"),
+			tgbotapi.NewInlineKeyboardButtonData("✏️ Район/метро", "wiz_edit_place"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔄 Сбросить", "wiz_reset"),
+			tgbotapi.NewInlineKeyboardButtonData("🏠 Главное меню", "main_menu"),
+		),
+	)
+
+	h.sendOrEditWizardMessage(chatID, messageID, sb.String(), keyboard)
+}
+
+// wizardSpecName возвращает название специализации для сводки
+func (h *VetHandlers) wizardSpecName(specID int) string {
+	if specID == 0 {
+		return "любая"
+	}
+	spec, err := h.db.GetSpecializationByID(specID)
+	if err != nil || spec == nil {
+		return fmt.Sprintf("#%d", specID)
+	}
+	return spec.Name
+}
+
+// wizardCityName возвращает название города для сводки
+func (h *VetHandlers) wizardCityName(cityID int) string {
+	if cityID == 0 {
+		return "любой"
+	}
+	city, err := h.db.GetCityByID(cityID)
+	if err != nil || city == nil {
+		return fmt.Sprintf("#%d", cityID)
+	}
+	return city.Name
+}
+
+// runSearchWizard ищет врачей по всем выбранным фильтрам и показывает результаты постранично
+func (h *VetHandlers) runSearchWizard(chatID int64, userID int64, wizard *searchWizard) {
+	InfoLog.Printf("Running search wizard: %+v", wizard.Criteria)
+
+	results := &searchResults{
+		Source:    resultsSourceCriteria,
+		Criteria:  wizard.Criteria,
+		Title:     "🧭 *Подбор врача:*",
+		EmptyText: "🧭 *Врачи по выбранным фильтрам не найдены*\n\nПопробуйте убрать один из фильтров.",
+		Buttons: [][]resultsButton{{
+			{Text: "✏️ Изменить фильтры", Data: "wiz_summary"},
+			{Text: "🏠 Главное меню", Data: "main_menu"},
+		}},
+	}
+
+	if err := h.startSearchResults(chatID, userID, results); err != nil {
+		ErrorLog.Printf("Error running search wizard: %v", err)
+		h.bot.Send(tgbotapi.NewMessage(chatID, "❌ Ошибка при поиске врачей"))
+	}
+}
+
+// sendOrEditWizardMessage редактирует сообщение мастера на месте или отправляет новое
+func (h *VetHandlers) sendOrEditWizardMessage(chatID int64, messageID int, text string, keyboard tgbotapi.InlineKeyboardMarkup) {
+	if messageID > 0 {
+		editMsg := tgbotapi.NewEditMessageTextAndMarkup(chatID, messageID, text, keyboard)
+		editMsg.ParseMode = "Markdown"
+		_, err := h.bot.Send(editMsg)
+		if err == nil {
+			return
+		}
+		ErrorLog.Printf("Error editing wizard message: %v", err)
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = keyboard
+	h.bot.Send(msg)
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/drerr0r/vetbot/internal/models"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================================
+// ТЕСТЫ ДЛЯ ПОШАГОВОГО ПОИСКА
+// ============================================================================
+
+// setupSearchWizard заполняет мок хирургами в Казани и Москве
+func setupSearchWizard(mockDB *MockDatabase) {
+	surgeon := &models.Specialization{ID: 1, Name: "Хирург"}
+	mockDB.Specializations[1] = surgeon
+	mockDB.Specializations[2] = &models.Specialization{ID: 2, Name: "Терапевт"}
+
+	mockDB.Cities[1] = &models.City{ID: 1, Name: "Казань"}
+	mockDB.Cities[2] = &models.City{ID: 2, Name: "Москва"}
+
+	mockDB.Clinics[1] = &models.Clinic{ID: 1, Name: "Вет Вахитовский", IsActive: true,
+		CityID:   sql.NullInt64{Int64: 1, Valid: true},
+		District: sql.NullString{String: "Вахитовский", Valid: true}}
+	mockDB.Clinics[2] = &models.Clinic{ID: 2, Name: "Вет Советский", IsActive: true,
+		CityID:   sql.NullInt64{Int64: 1, Valid: true},
+		District: sql.NullString{String: "Советский", Valid: true}}
+
+	mockDB.Veterinarians[1] = &models.Veterinarian{ID: sql.NullInt64{Int64: 1, Valid: true},
+		FirstName: "Айдар", LastName: "Галиев", IsActive: true,
+		CityID: sql.NullInt64{Int64: 1, Valid: true}, Specializations: []*models.Specialization{surgeon}}
+	mockDB.Veterinarians[2] = &models.Veterinarian{ID: sql.NullInt64{Int64: 2, Valid: true},
+		FirstName: "Булат", LastName: "Хасанов", IsActive: true,
+		CityID: sql.NullInt64{Int64: 1, Valid: true}, Specializations: []*models.Specialization{surgeon}}
+	mockDB.Veterinarians[3] = &models.Veterinarian{ID: sql.NullInt64{Int64: 3, Valid: true},
+		FirstName: "Иван", LastName: "Петров", IsActive: true,
+		CityID: sql.NullInt64{Int64: 2, Valid: true}, Specializations: []*models.Specialization{surgeon}}
+
+	// Галиев по субботам в Вахитовском районе, Хасанов по субботам в Советском
+	mockDB.Schedules[1] = &models.Schedule{ID: 1, VetID: 1, ClinicID: 1, DayOfWeek: 6,
+		StartTime: "09:00", EndTime: "15:00", IsAvailable: true}
+	mockDB.Schedules[2] = &models.Schedule{ID: 2, VetID: 2, ClinicID: 2, DayOfWeek: 6,
+		StartTime: "09:00", EndTime: "15:00", IsAvailable: true}
+	mockDB.Schedules[3] = &models.Schedule{ID: 3, VetID: 3, ClinicID: 1, DayOfWeek: 6,
+		StartTime: "09:00", EndTime: "15:00", IsAvailable: true}
+}
+
+func wizardCallback(handler *VetHandlers, data string) {
+	update := NewTestUpdate().WithCallback(data, 12345, 1).Build()
+	handler.HandleCallback(update)
+}
+
+func TestSearchWizard(t *testing.T) {
+	t.Run("Full flow combines all filters", func(t *testing.T) {
+		handler, mockBot, mockDB := CreateTestVetHandlers()
+		setupSearchWizard(mockDB)
+
+		wizardCallback(handler, "main_wizard")
+		assert.Contains(t, mockBot.GetLastEditedMessage().Text, "шаг 1 из 4")
+
+		wizardCallback(handler, "wiz_spec_1")
+		assert.Contains(t, mockBot.GetLastEditedMessage().Text, "шаг 2 из 4")
+
+		wizardCallback(handler, "wiz_city_1")
+		assert.Contains(t, mockBot.GetLastEditedMessage().Text, "шаг 3 из 4")
+
+		wizardCallback(handler, "wiz_day_6")
+		edited := mockBot.GetLastEditedMessage()
+		assert.Contains(t, edited.Text, "шаг 4 из 4")
+		require.NotNil(t, edited.ReplyMarkup)
+		// Районы Казани по алфавиту: 1 - Вахитовский, 2 - Советский
+		assert.Contains(t, inlineCallbacks(edited.ReplyMarkup), "wiz_district_1")
+		assert.Contains(t, inlineCallbacks(edited.ReplyMarkup), "wiz_district_2")
+
+		wizardCallback(handler, "wiz_district_1")
+		summary := mockBot.GetLastEditedMessage().Text
+		assert.Contains(t, summary, "Хирург")
+		assert.Contains(t, summary, "Казань")
+		assert.Contains(t, summary, "субботу")
+		assert.Contains(t, summary, "Вахитовский")
+
+		sentBefore := len(mockBot.SentMessages)
+		wizardCallback(handler, "wiz_run")
+		results := mockBot.SentMessages[sentBefore:]
+		require.Len(t, results, 1)
+		assert.Contains(t, results[0].Text, "Найдено врачей: 1")
+		assert.Contains(t, results[0].Text, "Айдар Галиев")
+	})
+
+	t.Run("Skipped steps do not filter", func(t *testing.T) {
+		handler, mockBot, mockDB := CreateTestVetHandlers()
+		setupSearchWizard(mockDB)
+
+		wizardCallback(handler, "main_wizard")
+		wizardCallback(handler, "wiz_spec_1")
+		wizardCallback(handler, "wiz_city_0")
+		wizardCallback(handler, "wiz_day_0")
+		wizardCallback(handler, "wiz_district_0")
+
+		sentBefore := len(mockBot.SentMessages)
+		wizardCallback(handler, "wiz_run")
+		assert.Contains(t, mockBot.SentMessages[sentBefore].Text, "Найдено врачей: 3")
+	})
+
+	t.Run("Edit filter from summary", func(t *testing.T) {
+		handler, mockBot, mockDB := CreateTestVetHandlers()
+		setupSearchWizard(mockDB)
+
+		wizardCallback(handler, "main_wizard")
+		wizardCallback(handler, "wiz_spec_1")
+		wizardCallback(handler, "wiz_city_1")
+		wizardCallback(handler, "wiz_day_6")
+		wizardCallback(handler, "wiz_district_1")
+
+		// Смена города сбрасывает район и сразу возвращает к сводке
+		wizardCallback(handler, "wiz_edit_city")
+		assert.Contains(t, inlineCallbacks(mockBot.GetLastEditedMessage().ReplyMarkup), "wiz_summary")
+		wizardCallback(handler, "wiz_city_2")
+
+		summary := mockBot.GetLastEditedMessage().Text
+		assert.Contains(t, summary, "Москва")
+		assert.Contains(t, summary, "Район/метро: любой")
+
+		sentBefore := len(mockBot.SentMessages)
+		wizardCallback(handler, "wiz_run")
+		results := mockBot.SentMessages[sentBefore:]
+		require.Len(t, results, 1)
+		assert.Contains(t, results[0].Text, "Иван Петров")
+	})
+
+	t.Run("Summary escapes filter names", func(t *testing.T) {
+		handler, mockBot, mockDB := CreateTestVetHandlers()
+		setupSearchWizard(mockDB)
+		mockDB.Specializations[1].Name = "Хирург_общий"
+		mockDB.Cities[1].Name = "Казань*"
+		mockDB.Clinics[1].District = sql.NullString{String: "Вахитовский_1", Valid: true}
+
+		wizardCallback(handler, "main_wizard")
+		wizardCallback(handler, "wiz_spec_1")
+		wizardCallback(handler, "wiz_city_1")
+		wizardCallback(handler, "wiz_day_6")
+		wizardCallback(handler, "wiz_district_1")
+
+		summary := mockBot.GetLastEditedMessage().Text
+		assert.Contains(t, summary, `Специализация: Хирург\_общий`)
+		assert.Contains(t, summary, `Город: Казань\*`)
+		assert.Contains(t, summary, `Район: Вахитовский\_1`)
+	})
+
+	t.Run("Nothing found", func(t *testing.T) {
+		handler, mockBot, mockDB := CreateTestVetHandlers()
+		setupSearchWizard(mockDB)
+
+		wizardCallback(handler, "main_wizard")
+		wizardCallback(handler, "wiz_spec_2")
+		wizardCallback(handler, "wiz_run")
+
+		last := mockBot.GetLastMessage()
+		assert.Contains(t, last.Text, "не найдены")
+		markup, ok := last.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+		require.True(t, ok)
+		assert.Contains(t, inlineCallbacks(&markup), "wiz_summary")
+	})
+}
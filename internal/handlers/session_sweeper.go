@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/drerr0r/vetbot/internal/models"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Семейства состояний, для которых настраивается время жизни сессии
+const (
+	sessionFamilyReview = "review"
+	sessionFamilyAdmin  = "admin"
+	sessionFamilySearch = "search"
+)
+
+// sessionFamily определяет семейство состояния
+func (h *MainHandler) sessionFamily(state string) string {
+	switch {
+	case strings.HasPrefix(state, "review_moderation"):
+		// Модерация отзывов - часть админ-панели
+		return sessionFamilyAdmin
+	case strings.HasPrefix(state, "review_"):
+		return sessionFamilyReview
+	case strings.HasPrefix(state, "admin_") || h.isInExplicitAdminMode(state):
+		return sessionFamilyAdmin
+	default:
+		return sessionFamilySearch
+	}
+}
+
+// sessionFamilyOf определяет семейство сессии: открытая админ-панель важнее пользовательского состояния
+func (h *MainHandler) sessionFamilyOf(session *models.UserSession) string {
+	if session.AdminState != "" {
+		return sessionFamilyAdmin
+	}
+	return h.sessionFamily(session.State)
+}
+
+// sessionTTL возвращает время жизни сессии по настройкам для ее семейства
+func (h *MainHandler) sessionTTL(session *models.UserSession) time.Duration {
+	switch h.sessionFamilyOf(session) {
+	case sessionFamilyReview:
+		return h.config.SessionTTLReview
+	case sessionFamilyAdmin:
+		return h.config.SessionTTLAdmin
+	default:
+		return h.config.SessionTTLSearch
+	}
+}
+
+// shortestSessionTTL возвращает минимальное ненулевое время жизни сессий
+func (h *MainHandler) shortestSessionTTL() time.Duration {
+	var shortest time.Duration
+	for _, ttl := range []time.Duration{h.config.SessionTTLReview, h.config.SessionTTLAdmin, h.config.SessionTTLSearch} {
+		if ttl > 0 && (shortest == 0 || ttl < shortest) {
+			shortest = ttl
+		}
+	}
+	return shortest
+}
+
+// touchSession отмечает активность пользователя перед обработкой обновления.
+// Если сессия уже истекла, но очистка еще не успела ее удалить, пользователь получает уведомление
+func (h *MainHandler) touchSession(update tgbotapi.Update) {
+	var userID, chatID int64
+	switch {
+	case update.CallbackQuery != nil && update.CallbackQuery.From != nil && update.CallbackQuery.Message != nil:
+		userID = update.CallbackQuery.From.ID
+		chatID = update.CallbackQuery.Message.Chat.ID
+	case update.Message != nil && update.Message.From != nil:
+		userID = update.Message.From.ID
+		chatID = update.Message.Chat.ID
+	default:
+		return
+	}
+
+	expired := h.stateManager.Touch(userID, time.Now(), h.sessionTTL)
+	if expired == nil {
+		return
+	}
+	h.adminHandlers.discardExpiredImport(expired)
+	if expired.State != "" || expired.AdminState != "" {
+		h.notifySessionExpired(chatID, h.sessionFamilyOf(expired), expired.State)
+	}
+}
+
+// StartSessionSweeper запускает фоновую очистку истекших сессий до отмены ctx
+func (h *MainHandler) StartSessionSweeper(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		// Первая очистка сразу: после перезапуска в хранилище могут быть давно истекшие сессии
+		h.SweepExpiredSessions(time.Now())
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				h.SweepExpiredSessions(now)
+			}
+		}
+	}()
+}
+
+// SweepExpiredSessions очищает истекшие сессии и сообщает пользователям об отмене незавершенных действий
+func (h *MainHandler) SweepExpiredSessions(now time.Time) {
+	var idleBefore time.Time
+	if shortest := h.shortestSessionTTL(); shortest > 0 {
+		idleBefore = now.Add(-shortest)
+	}
+
+	for _, session := range h.stateManager.ExpireSessions(now, idleBefore, h.sessionTTL) {
+		InfoLog.Printf("Session expired for user %d (state '%s', admin state '%s')",
+			session.UserID, session.State, session.AdminState)
+		h.adminHandlers.discardExpiredImport(session)
+		// Сессии только с историей навигации не содержат незавершенных действий
+		if session.State == "" && session.AdminState == "" {
+			continue
+		}
+		h.notifySessionExpired(session.UserID, h.sessionFamilyOf(session), session.State)
+	}
+}
+
+// notifySessionExpired сообщает пользователю, что его незавершенное действие отменено
+func (h *MainHandler) notifySessionExpired(chatID int64, family string, state string) {
+	var text string
+	switch family {
+	case sessionFamilyReview:
+		text = "⌛ Написание отзыва отменено из-за долгого бездействия.\n\nЧтобы оставить отзыв, откройте карточку врача снова."
+	case sessionFamilyAdmin:
+		text = "⌛ Незавершенное действие в админ-панели отменено из-за долгого бездействия.\n\nДля продолжения работы используйте /admin."
+	default:
+		text = "⌛ Поиск отменен из-за долгого бездействия.\n\nВоспользуйтесь меню, чтобы начать заново."
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = h.vetHandlers.createPersistentKeyboard()
+	if _, err := h.bot.Send(msg); err != nil {
+		ErrorLog.Printf("Error sending session expiry notice to %d (state '%s'): %v", chatID, state, err)
+	}
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ============================================================================
+// ТЕСТЫ ДЛЯ ИСТЕЧЕНИЯ СЕССИЙ
+// ============================================================================
+
+// createSessionTestHandler создает MainHandler с настроенным временем жизни сессий
+func createSessionTestHandler() (*MainHandler, *MockBot) {
+	mainHandler, mockBot := CreateTestMainHandlers()
+	mainHandler.config.SessionTTLReview = 30 * time.Minute
+	mainHandler.config.SessionTTLAdmin = 2 * time.Hour
+	mainHandler.config.SessionTTLSearch = 24 * time.Hour
+	return mainHandler, mockBot
+}
+
+func TestSweepExpiredSessions(t *testing.T) {
+	t.Run("Review session expires and user is notified", func(t *testing.T) {
+		handler, mockBot := createSessionTestHandler()
+		handler.stateManager.SetUserState(100, "review_comment")
+		handler.stateManager.SetUserData(100, "review_vet_id", 1)
+
+		handler.SweepExpiredSessions(time.Now().Add(31 * time.Minute))
+
+		assert.Empty(t, handler.stateManager.GetUserState(100))
+		assert.Nil(t, handler.stateManager.GetUserData(100, "review_vet_id"))
+		message := mockBot.GetLastMessage()
+		assert.NotNil(t, message)
+		assert.Equal(t, int64(100), message.ChatID)
+		assert.Contains(t, message.Text, "Написание отзыва отменено")
+	})
+
+	t.Run("Active review session is kept", func(t *testing.T) {
+		handler, mockBot := createSessionTestHandler()
+		handler.stateManager.SetUserState(100, "review_comment")
+
+		handler.SweepExpiredSessions(time.Now().Add(10 * time.Minute))
+
+		assert.Equal(t, "review_comment", handler.stateManager.GetUserState(100))
+		assert.Empty(t, mockBot.SentMessages)
+	})
+
+	t.Run("Navigation history expires silently", func(t *testing.T) {
+		handler, mockBot := createSessionTestHandler()
+		handler.stateManager.PushState(100, "main_menu")
+
+		handler.SweepExpiredSessions(time.Now().Add(25 * time.Hour))
+
+		_, exists := handler.stateManager.GetPreviousState(100)
+		assert.False(t, exists)
+		assert.Empty(t, mockBot.SentMessages)
+	})
+
+	t.Run("Zero TTL disables expiry", func(t *testing.T) {
+		handler, _ := createSessionTestHandler()
+		handler.config.SessionTTLReview = 0
+		handler.stateManager.SetUserState(100, "review_comment")
+
+		handler.SweepExpiredSessions(time.Now().Add(365 * 24 * time.Hour))
+
+		assert.Equal(t, "review_comment", handler.stateManager.GetUserState(100))
+	})
+
+	t.Run("Admin panel session expires", func(t *testing.T) {
+		handler, mockBot := createSessionTestHandler()
+		handler.adminHandlers.HandleAdmin(NewTestUpdate().WithMessage("/admin", 12345, 12345).Build())
+		assert.True(t, handler.adminHandlers.HasActiveSession(12345))
+
+		handler.SweepExpiredSessions(time.Now().Add(3 * time.Hour))
+
+		assert.False(t, handler.adminHandlers.HasActiveSession(12345))
+		assert.Contains(t, mockBot.GetLastMessage().Text, "админ-панели отменено")
+	})
+}
+
+func TestExpiredSessionOnNextMessage(t *testing.T) {
+	handler, mockBot := createSessionTestHandler()
+	handler.stateManager.SetUserState(100, "review_comment")
+
+	// Имитируем сессию, брошенную давно: очистка еще не успела сработать
+	session, unlock := handler.stateManager.lock(100)
+	session.UpdatedAt = time.Now().Add(-time.Hour)
+	unlock()
+
+	handler.HandleUpdate(NewTestUpdate().WithMessage("Отличный врач", 100, 100).Build())
+
+	assert.Empty(t, handler.stateManager.GetUserState(100))
+	assert.Contains(t, mockBot.SentMessages[0].Text, "Написание отзыва отменено")
+}
+
+func TestAdminPanelReceivesFreeText(t *testing.T) {
+	handler, mockBot := CreateTestMainHandlers()
+
+	handler.HandleUpdate(NewTestUpdate().WithCommand("/admin", 12345, 12345).Build())
+	handler.HandleUpdate(NewTestUpdate().WithMessage("👥 Управление врачами", 12345, 12345).Build())
+	handler.HandleUpdate(NewTestUpdate().WithMessage("➕ Добавить врача", 12345, 12345).Build())
+
+	// Свободный текст мастера добавления врача должен попасть в админ-панель
+	handler.HandleUpdate(NewTestUpdate().WithMessage("Иван Петров", 12345, 12345).Build())
+
+	assert.Equal(t, "add_vet_phone", handler.adminHandlers.stateManager.GetAdminState(12345))
+	assert.NotContains(t, mockBot.GetLastMessage().Text, "Я понимаю только команды")
+
+	// /start завершает сессию админ-панели
+	handler.HandleUpdate(NewTestUpdate().WithCommand("/start", 12345, 12345).Build())
+	assert.False(t, handler.adminHandlers.HasActiveSession(12345))
+}
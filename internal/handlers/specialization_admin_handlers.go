@@ -0,0 +1,522 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/drerr0r/vetbot/internal/models"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// normalizeSpecializationName убирает лишние пробелы в названии специализации: " терапевт  " -> "терапевт"
+func normalizeSpecializationName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// showSpecializationManagement показывает справочник специализаций с числом активных врачей
+func (h *AdminHandlers) showSpecializationManagement(update tgbotapi.Update) {
+	userID := update.Message.From.ID
+	h.stateManager.SetAdminState(userID, "spec_management")
+	h.stateManager.ClearAdminDataByKey(userID, "spec_edit")
+
+	specs, err := h.sortedSpecializations()
+	if err != nil {
+		ErrorLog.Printf("❌ Ошибка загрузки специализаций: %v", err)
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Ошибка при загрузке специализаций")
+		h.bot.Send(msg)
+		return
+	}
+	counts := h.specializationVetCounts()
+
+	var sb strings.Builder
+	sb.WriteString("🎯 Управление специализациями\n\n")
+	if len(specs) == 0 {
+		sb.WriteString("Специализаций пока нет.\n")
+	}
+	for i, spec := range specs {
+		sb.WriteString(fmt.Sprintf("%d. %s - врачей: %d\n", i+1, spec.Name, counts[spec.ID]))
+	}
+
+	// Дубли вида "Терапевт" и "терапевт " подсказываем объединить
+	groups := make(map[string][]string)
+	var keys []string
+	for _, spec := range specs {
+		key := strings.ToLower(normalizeSpecializationName(spec.Name))
+		if len(groups[key]) == 1 {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], "«"+spec.Name+"»")
+	}
+	for _, key := range keys {
+		sb.WriteString(fmt.Sprintf("\n⚠️ Похожие названия: %s - объедините их", strings.Join(groups[key], ", ")))
+	}
+	if len(keys) > 0 {
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\nВведите номер специализации для управления:")
+
+	keyboard := tgbotapi.NewReplyKeyboard(
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton("➕ Добавить специализацию"),
+		),
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton("🔙 Назад"),
+		),
+	)
+
+	// Без Markdown: в названиях специализаций бывают спецсимволы
+	msg := tgbotapi.NewMessage(update.Message.Chat.ID, sb.String())
+	msg.ReplyMarkup = keyboard
+	h.bot.Send(msg)
+}
+
+// handleSpecializationManagement обрабатывает добавление специализации или выбор ее по номеру
+func (h *AdminHandlers) handleSpecializationManagement(update tgbotapi.Update, text string) {
+	userID := update.Message.From.ID
+
+	if text == "➕ Добавить специализацию" {
+		h.stateManager.SetAdminState(userID, "spec_add_name")
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Введите название новой специализации:")
+		msg.ReplyMarkup = backCancelKeyboard()
+		h.bot.Send(msg)
+		return
+	}
+
+	specs, err := h.sortedSpecializations()
+	if err != nil {
+		ErrorLog.Printf("❌ Ошибка загрузки специализаций: %v", err)
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Ошибка при загрузке специализаций")
+		h.bot.Send(msg)
+		return
+	}
+	index, err := parseListNumber(text, len(specs), "специализации")
+	if err != nil {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ "+err.Error())
+		h.bot.Send(msg)
+		return
+	}
+	h.showSpecializationEditMenu(update, specs[index])
+}
+
+// handleAddSpecializationName создает специализацию, если такой еще нет
+func (h *AdminHandlers) handleAddSpecializationName(update tgbotapi.Update, text string) {
+	name := normalizeSpecializationName(text)
+	if !h.checkSpecializationName(update, name, 0) {
+		return
+	}
+
+	spec := &models.Specialization{Name: name, CreatedAt: time.Now()}
+	if err := h.db.CreateSpecialization(spec); err != nil {
+		ErrorLog.Printf("❌ Ошибка создания специализации '%s': %v", name, err)
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Ошибка при создании специализации")
+		h.bot.Send(msg)
+		return
+	}
+
+	InfoLog.Printf("🎯 Создана специализация %d: %s", spec.ID, spec.Name)
+	msg := tgbotapi.NewMessage(update.Message.Chat.ID, "✅ Специализация «"+spec.Name+"» добавлена")
+	h.bot.Send(msg)
+	h.showSpecializationManagement(update)
+}
+
+// showSpecializationEditMenu показывает карточку специализации и действия с ней
+func (h *AdminHandlers) showSpecializationEditMenu(update tgbotapi.Update, spec *models.Specialization) {
+	userID := update.Message.From.ID
+	h.stateManager.SetAdminState(userID, "spec_edit_menu")
+	h.stateManager.SetAdminData(userID, "spec_edit", &models.SpecializationEditData{
+		SpecializationID: spec.ID,
+	})
+
+	description := spec.Description
+	if description == "" {
+		description = "не указано"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🎯 Специализация: %s\n\n", spec.Name))
+	sb.WriteString(fmt.Sprintf("📝 Описание: %s\n", description))
+	sb.WriteString(fmt.Sprintf("👨‍⚕️ Активных врачей: %d\n", h.specializationVetCounts()[spec.ID]))
+	sb.WriteString("\nВыберите действие:")
+
+	keyboard := tgbotapi.NewReplyKeyboard(
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton("✏️ Переименовать"),
+			tgbotapi.NewKeyboardButton("📝 Изменить описание"),
+		),
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton("🔀 Объединить с другой"),
+			tgbotapi.NewKeyboardButton("🗑️ Удалить специализацию"),
+		),
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton("🔙 Назад"),
+		),
+	)
+
+	msg := tgbotapi.NewMessage(update.Message.Chat.ID, sb.String())
+	msg.ReplyMarkup = keyboard
+	h.bot.Send(msg)
+}
+
+// handleSpecializationEditMenu обрабатывает выбор действия со специализацией
+func (h *AdminHandlers) handleSpecializationEditMenu(update tgbotapi.Update, text string) {
+	userID := update.Message.From.ID
+	spec := h.currentEditSpecialization(update)
+	if spec == nil {
+		return
+	}
+
+	switch text {
+	case "✏️ Переименовать":
+		h.stateManager.SetAdminState(userID, "spec_edit_name")
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID,
+			fmt.Sprintf("Текущее название: %s\n\nВведите новое название:", spec.Name))
+		msg.ReplyMarkup = backCancelKeyboard()
+		h.bot.Send(msg)
+
+	case "📝 Изменить описание":
+		h.stateManager.SetAdminState(userID, "spec_edit_description")
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Введите описание специализации (или '-' для очистки):")
+		msg.ReplyMarkup = backCancelKeyboard()
+		h.bot.Send(msg)
+
+	case "🔀 Объединить с другой":
+		others, err := h.otherSpecializations(spec.ID)
+		if err != nil {
+			ErrorLog.Printf("❌ Ошибка загрузки специализаций: %v", err)
+			msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Ошибка при загрузке специализаций")
+			h.bot.Send(msg)
+			return
+		}
+		if len(others) == 0 {
+			msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Других специализаций нет - объединять не с чем")
+			h.bot.Send(msg)
+			return
+		}
+
+		h.stateManager.SetAdminState(userID, "spec_merge")
+		counts := h.specializationVetCounts()
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("Введите номер специализации, в которую перенести врачей «%s». "+
+			"Сама «%s» будет удалена.\n\n", spec.Name, spec.Name))
+		for i, other := range others {
+			sb.WriteString(fmt.Sprintf("%d. %s - врачей: %d\n", i+1, other.Name, counts[other.ID]))
+		}
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, sb.String())
+		msg.ReplyMarkup = backCancelKeyboard()
+		h.bot.Send(msg)
+
+	case "🗑️ Удалить специализацию":
+		h.stateManager.SetAdminState(userID, "spec_confirm_delete")
+		text := fmt.Sprintf("⚠️ Вы собираетесь удалить специализацию «%s».", spec.Name)
+		if count := h.specializationVetCounts()[spec.ID]; count > 0 {
+			text += fmt.Sprintf("\nОна будет снята с врачей (активных: %d). "+
+				"Если это дубль, лучше объединить ее с основной.", count)
+		}
+		text += "\n\nПодтвердите удаление:"
+
+		keyboard := tgbotapi.NewReplyKeyboard(
+			tgbotapi.NewKeyboardButtonRow(
+				tgbotapi.NewKeyboardButton("✅ Подтвердить удаление"),
+				tgbotapi.NewKeyboardButton("❌ Отмена"),
+			),
+		)
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, text)
+		msg.ReplyMarkup = keyboard
+		h.bot.Send(msg)
+
+	default:
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Используйте кнопки для управления")
+		h.bot.Send(msg)
+	}
+}
+
+// handleSpecializationEditName переименовывает специализацию
+func (h *AdminHandlers) handleSpecializationEditName(update tgbotapi.Update, text string) {
+	spec := h.currentEditSpecialization(update)
+	if spec == nil {
+		return
+	}
+
+	name := normalizeSpecializationName(text)
+	if !h.checkSpecializationName(update, name, spec.ID) {
+		return
+	}
+
+	oldName := spec.Name
+	updated := *spec
+	updated.Name = name
+	if !h.saveSpecialization(update, &updated) {
+		return
+	}
+
+	InfoLog.Printf("🎯 Специализация %d переименована: %s -> %s", spec.ID, oldName, name)
+	msg := tgbotapi.NewMessage(update.Message.Chat.ID, fmt.Sprintf("✅ Специализация «%s» переименована в «%s»", oldName, name))
+	h.bot.Send(msg)
+	h.showSpecializationEditMenu(update, &updated)
+}
+
+// handleSpecializationEditDescription меняет описание специализации, "-" очищает его
+func (h *AdminHandlers) handleSpecializationEditDescription(update tgbotapi.Update, text string) {
+	spec := h.currentEditSpecialization(update)
+	if spec == nil {
+		return
+	}
+
+	updated := *spec
+	updated.Description = strings.TrimSpace(text)
+	if updated.Description == "-" {
+		updated.Description = ""
+	}
+	if !h.saveSpecialization(update, &updated) {
+		return
+	}
+
+	InfoLog.Printf("🎯 Изменено описание специализации %d", spec.ID)
+	msg := tgbotapi.NewMessage(update.Message.Chat.ID, "✅ Описание специализации обновлено")
+	h.bot.Send(msg)
+	h.showSpecializationEditMenu(update, &updated)
+}
+
+// handleSpecializationMerge выбирает специализацию, в которую перенести врачей текущей, и просит подтвердить объединение
+func (h *AdminHandlers) handleSpecializationMerge(update tgbotapi.Update, text string) {
+	spec := h.currentEditSpecialization(update)
+	if spec == nil {
+		return
+	}
+
+	others, err := h.otherSpecializations(spec.ID)
+	if err != nil {
+		ErrorLog.Printf("❌ Ошибка загрузки специализаций: %v", err)
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Ошибка при загрузке специализаций")
+		h.bot.Send(msg)
+		return
+	}
+	index, err := parseListNumber(text, len(others), "специализации")
+	if err != nil {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ "+err.Error())
+		h.bot.Send(msg)
+		return
+	}
+	target := others[index]
+
+	gained, err := h.specializationMergeGain(spec.ID, target.ID)
+	if err != nil {
+		ErrorLog.Printf("❌ Ошибка загрузки врачей специализаций %d и %d: %v", spec.ID, target.ID, err)
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Ошибка при загрузке врачей")
+		h.bot.Send(msg)
+		return
+	}
+
+	userID := update.Message.From.ID
+	h.stateManager.SetAdminData(userID, "merge_target_id", target.ID)
+	h.stateManager.SetAdminState(userID, "spec_confirm_merge")
+
+	confirmText := fmt.Sprintf("⚠️ Объединение специализаций\n\n"+
+		"Из: «%s»\nВ: «%s»\nАктивных врачей, которые получат «%s»: %d\n\n"+
+		"«%s» будет удалена, запросы пользователей перейдут на «%s».\n\nПодтвердите объединение:",
+		spec.Name, target.Name, target.Name, gained, spec.Name, target.Name)
+
+	keyboard := tgbotapi.NewReplyKeyboard(
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton("✅ Подтвердить объединение"),
+			tgbotapi.NewKeyboardButton("❌ Отмена"),
+		),
+	)
+	msg := tgbotapi.NewMessage(update.Message.Chat.ID, confirmText)
+	msg.ReplyMarkup = keyboard
+	h.bot.Send(msg)
+}
+
+// handleSpecializationConfirmMerge переносит врачей текущей специализации в выбранную и удаляет текущую
+func (h *AdminHandlers) handleSpecializationConfirmMerge(update tgbotapi.Update, text string) {
+	if text != "✅ Подтвердить объединение" {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Используйте кнопки для подтверждения или отмены")
+		h.bot.Send(msg)
+		return
+	}
+
+	spec := h.currentEditSpecialization(update)
+	if spec == nil {
+		return
+	}
+
+	userID := update.Message.From.ID
+	targetID, _ := h.stateManager.GetAdminData(userID, "merge_target_id").(int)
+	h.stateManager.ClearAdminDataByKey(userID, "merge_target_id")
+	target, err := h.db.GetSpecializationByID(targetID)
+	if err != nil {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Специализация для объединения не найдена")
+		h.bot.Send(msg)
+		h.showSpecializationEditMenu(update, spec)
+		return
+	}
+
+	if err := h.db.MergeSpecializations(spec.ID, target.ID); err != nil {
+		ErrorLog.Printf("❌ Ошибка объединения специализации %d с %d: %v", spec.ID, target.ID, err)
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Ошибка при объединении специализаций")
+		h.bot.Send(msg)
+		return
+	}
+
+	InfoLog.Printf("🔀 Специализация %d (%s) объединена с %d (%s)", spec.ID, spec.Name, target.ID, target.Name)
+	msg := tgbotapi.NewMessage(update.Message.Chat.ID, fmt.Sprintf("✅ «%s» объединена с «%s», активных врачей: %d",
+		spec.Name, target.Name, h.specializationVetCounts()[target.ID]))
+	h.bot.Send(msg)
+	h.showSpecializationManagement(update)
+}
+
+// handleSpecializationConfirmDelete удаляет специализацию после подтверждения
+func (h *AdminHandlers) handleSpecializationConfirmDelete(update tgbotapi.Update, text string) {
+	if text != "✅ Подтвердить удаление" {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Используйте кнопки для подтверждения или отмены")
+		h.bot.Send(msg)
+		return
+	}
+
+	spec := h.currentEditSpecialization(update)
+	if spec == nil {
+		return
+	}
+
+	if err := h.db.DeleteSpecialization(spec.ID); err != nil {
+		ErrorLog.Printf("❌ Ошибка удаления специализации %d: %v", spec.ID, err)
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Ошибка при удалении специализации")
+		h.bot.Send(msg)
+		return
+	}
+
+	InfoLog.Printf("🗑️ Удалена специализация %d: %s", spec.ID, spec.Name)
+	msg := tgbotapi.NewMessage(update.Message.Chat.ID, "✅ Специализация «"+spec.Name+"» удалена")
+	h.bot.Send(msg)
+	h.showSpecializationManagement(update)
+}
+
+// returnToSpecializationEditMenu возвращает из ввода значения в карточку специализации
+func (h *AdminHandlers) returnToSpecializationEditMenu(update tgbotapi.Update) {
+	if spec := h.currentEditSpecialization(update); spec != nil {
+		h.showSpecializationEditMenu(update, spec)
+	}
+}
+
+// currentEditSpecialization возвращает редактируемую специализацию; если ее нет, возвращает к справочнику
+func (h *AdminHandlers) currentEditSpecialization(update tgbotapi.Update) *models.Specialization {
+	userID := update.Message.From.ID
+	specData, ok := h.stateManager.GetAdminData(userID, "spec_edit").(*models.SpecializationEditData)
+	if ok && specData != nil {
+		if spec, err := h.db.GetSpecializationByID(specData.SpecializationID); err == nil {
+			return spec
+		}
+	}
+	h.showSpecializationManagement(update)
+	return nil
+}
+
+// checkSpecializationName проверяет, что название не пустое и не совпадает с другой специализацией
+// без учета регистра. exceptID - ID переименовываемой специализации
+func (h *AdminHandlers) checkSpecializationName(update tgbotapi.Update, name string, exceptID int) bool {
+	if name == "" {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Название специализации не может быть пустым")
+		h.bot.Send(msg)
+		return false
+	}
+	if len([]rune(name)) > 100 {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Название слишком длинное (максимум 100 символов)")
+		h.bot.Send(msg)
+		return false
+	}
+
+	existing, err := h.db.GetSpecializationByName(name)
+	if err != nil && err != sql.ErrNoRows {
+		ErrorLog.Printf("❌ Ошибка проверки специализации '%s': %v", name, err)
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Ошибка при проверке названия")
+		h.bot.Send(msg)
+		return false
+	}
+	if err == nil && existing != nil && existing.ID != exceptID {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, fmt.Sprintf("❌ Специализация «%s» уже есть. "+
+			"Чтобы перенести в нее врачей, используйте «🔀 Объединить с другой».", existing.Name))
+		h.bot.Send(msg)
+		return false
+	}
+	return true
+}
+
+// saveSpecialization сохраняет название и описание специализации
+func (h *AdminHandlers) saveSpecialization(update tgbotapi.Update, spec *models.Specialization) bool {
+	if err := h.db.UpdateSpecialization(spec); err != nil {
+		ErrorLog.Printf("❌ Ошибка сохранения специализации %d: %v", spec.ID, err)
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Ошибка при сохранении специализации")
+		h.bot.Send(msg)
+		return false
+	}
+	return true
+}
+
+// sortedSpecializations возвращает специализации по названию, как их нумерует справочник
+func (h *AdminHandlers) sortedSpecializations() ([]*models.Specialization, error) {
+	specs, err := h.db.GetAllSpecializations()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(specs, func(i, j int) bool {
+		if specs[i].Name != specs[j].Name {
+			return specs[i].Name < specs[j].Name
+		}
+		return specs[i].ID < specs[j].ID
+	})
+	return specs, nil
+}
+
+// otherSpecializations возвращает специализации, кроме specID, для выбора при объединении
+func (h *AdminHandlers) otherSpecializations(specID int) ([]*models.Specialization, error) {
+	specs, err := h.sortedSpecializations()
+	if err != nil {
+		return nil, err
+	}
+	others := make([]*models.Specialization, 0, len(specs))
+	for _, spec := range specs {
+		if spec.ID != specID {
+			others = append(others, spec)
+		}
+	}
+	return others, nil
+}
+
+// specializationVetCounts возвращает число активных врачей по специализациям
+func (h *AdminHandlers) specializationVetCounts() map[int]int {
+	counts, err := h.db.DebugSpecializationVetsCount()
+	if err != nil {
+		ErrorLog.Printf("❌ Ошибка подсчета врачей по специализациям: %v", err)
+		return make(map[int]int)
+	}
+	return counts
+}
+
+// specializationMergeGain считает активных врачей специализации sourceID, у которых еще нет targetID:
+// врачи, у которых есть обе, при объединении ничего не получают
+func (h *AdminHandlers) specializationMergeGain(sourceID, targetID int) (int, error) {
+	sourceVets, err := h.db.GetVeterinariansBySpecialization(sourceID)
+	if err != nil {
+		return 0, err
+	}
+	targetVets, err := h.db.GetVeterinariansBySpecialization(targetID)
+	if err != nil {
+		return 0, err
+	}
+
+	hasTarget := make(map[int]bool, len(targetVets))
+	for _, vet := range targetVets {
+		hasTarget[models.GetVetIDAsIntOrZero(vet)] = true
+	}
+	gained := 0
+	for _, vet := range sourceVets {
+		if !hasTarget[models.GetVetIDAsIntOrZero(vet)] {
+			gained++
+		}
+	}
+	return gained, nil
+}
@@ -2,67 +2,148 @@ package handlers
 
 import (
 	"sync"
+	"time"
+
+	"github.com/drerr0r/vetbot/internal/models"
 )
 
-// StateManager управляет состояниями пользователей
+// maxHistoryLength - сколько последних состояний хранится в истории навигации
+const maxHistoryLength = 10
+
+// sessionEntry - сессия пользователя в кеше со своей блокировкой
+type sessionEntry struct {
+	mutex   sync.Mutex
+	session *models.UserSession // nil, пока сессия не загружена из хранилища
+	evicted bool                // запись удалена из кеша, нужно взять новую
+}
+
+// StateManager управляет состояниями пользователей.
+// Сессии кешируются в памяти и сохраняются в StateStorage после каждого изменения.
+// Каждая сессия блокируется отдельно, поэтому обращения к хранилищу одного пользователя
+// не задерживают остальных
 type StateManager struct {
-	userStates  map[int64]string
-	userData    map[int64]map[string]interface{}
-	userHistory map[int64][]string
-	mutex       sync.RWMutex
+	storage  StateStorage
+	sessions map[int64]*sessionEntry
+	// mutex защищает только карту sessions, к хранилищу под ним не обращаемся
+	mutex sync.Mutex
 }
 
-// NewStateManager создает новый менеджер состояний
+// NewStateManager создает новый менеджер состояний с хранением в памяти
 func NewStateManager() *StateManager {
+	return NewStateManagerWithStorage(NewMemoryStateStorage())
+}
+
+// NewStateManagerWithStorage создает менеджер состояний с указанным хранилищем
+func NewStateManagerWithStorage(storage StateStorage) *StateManager {
 	return &StateManager{
-		userStates:  make(map[int64]string),
-		userData:    make(map[int64]map[string]interface{}),
-		userHistory: make(map[int64][]string),
+		storage:  storage,
+		sessions: make(map[int64]*sessionEntry),
+	}
+}
+
+// lock блокирует сессию пользователя, при необходимости загружая ее из хранилища.
+// Возвращает сессию и функцию, снимающую блокировку
+func (sm *StateManager) lock(userID int64) (*models.UserSession, func()) {
+	for {
+		sm.mutex.Lock()
+		entry, exists := sm.sessions[userID]
+		if !exists {
+			entry = &sessionEntry{}
+			sm.sessions[userID] = entry
+		}
+		sm.mutex.Unlock()
+
+		entry.mutex.Lock()
+		if entry.evicted {
+			// Пока ждали блокировку, сессию вытеснили из кеша
+			entry.mutex.Unlock()
+			continue
+		}
+		if entry.session == nil {
+			entry.session = sm.load(userID)
+		}
+		return entry.session, entry.mutex.Unlock
+	}
+}
+
+// load загружает сессию пользователя из хранилища или создает пустую
+func (sm *StateManager) load(userID int64) *models.UserSession {
+	session, err := sm.storage.LoadSession(userID)
+	if err != nil {
+		ErrorLog.Printf("Error loading session for user %d: %v", userID, err)
+	}
+	if session == nil {
+		session = &models.UserSession{UserID: userID}
+	}
+	initSessionMaps(session)
+	return session
+}
+
+// initSessionMaps создает отсутствующие карты данных сессии
+func initSessionMaps(session *models.UserSession) {
+	if session.Data == nil {
+		session.Data = make(map[string]interface{})
+	}
+	if session.AdminData == nil {
+		session.AdminData = make(map[string]interface{})
+	}
+}
+
+// persist сохраняет сессию пользователя в хранилище. Вызывать под блокировкой сессии
+func (sm *StateManager) persist(session *models.UserSession) {
+	session.UpdatedAt = time.Now()
+
+	var err error
+	if session.IsEmpty() {
+		err = sm.storage.DeleteSession(session.UserID)
+	} else {
+		err = sm.storage.SaveSession(session)
+	}
+	if err != nil {
+		ErrorLog.Printf("Error saving session for user %d: %v", session.UserID, err)
 	}
 }
 
 // SetUserState устанавливает состояние пользователя
 func (sm *StateManager) SetUserState(userID int64, state string) {
-	sm.mutex.Lock()
-	defer sm.mutex.Unlock()
-	sm.userStates[userID] = state
+	session, unlock := sm.lock(userID)
+	defer unlock()
+
+	session.State = state
+	sm.persist(session)
 }
 
 // GetUserState возвращает состояние пользователя
 func (sm *StateManager) GetUserState(userID int64) string {
-	sm.mutex.RLock()
-	defer sm.mutex.RUnlock()
-	return sm.userStates[userID]
+	session, unlock := sm.lock(userID)
+	defer unlock()
+	return session.State
 }
 
 // ClearUserState очищает состояние пользователя
 func (sm *StateManager) ClearUserState(userID int64) {
-	sm.mutex.Lock()
-	defer sm.mutex.Unlock()
-	delete(sm.userStates, userID)
-	delete(sm.userData, userID)
+	session, unlock := sm.lock(userID)
+	defer unlock()
+
+	session.State = ""
+	session.Data = make(map[string]interface{})
+	sm.persist(session)
 }
 
 // SetUserData сохраняет данные пользователя
 func (sm *StateManager) SetUserData(userID int64, key string, value interface{}) {
-	sm.mutex.Lock()
-	defer sm.mutex.Unlock()
+	session, unlock := sm.lock(userID)
+	defer unlock()
 
-	if sm.userData[userID] == nil {
-		sm.userData[userID] = make(map[string]interface{})
-	}
-	sm.userData[userID][key] = value
+	session.Data[key] = value
+	sm.persist(session)
 }
 
 // GetUserData возвращает данные пользователя
 func (sm *StateManager) GetUserData(userID int64, key string) interface{} {
-	sm.mutex.RLock()
-	defer sm.mutex.RUnlock()
-
-	if userData, exists := sm.userData[userID]; exists {
-		return userData[key]
-	}
-	return nil
+	session, unlock := sm.lock(userID)
+	defer unlock()
+	return session.Data[key]
 }
 
 // GetUserDataInt возвращает данные пользователя как int
@@ -84,34 +165,29 @@ func (sm *StateManager) GetUserDataInt(userID int64, key string) (int, bool) {
 
 // ClearUserData очищает все данные пользователя
 func (sm *StateManager) ClearUserData(userID int64) {
-	sm.mutex.Lock()
-	defer sm.mutex.Unlock()
-	delete(sm.userData, userID)
+	session, unlock := sm.lock(userID)
+	defer unlock()
+
+	session.Data = make(map[string]interface{})
+	sm.persist(session)
 }
 
 // DebugUserState выводит отладочную информацию о состоянии пользователя
 func (sm *StateManager) DebugUserState(userID int64) {
-	sm.mutex.RLock()
-	defer sm.mutex.RUnlock()
-
-	state := sm.userStates[userID]
-	data := sm.userData[userID]
+	session, unlock := sm.lock(userID)
+	defer unlock()
 
-	InfoLog.Printf("DebugUserState: user %d, state: %s, data: %+v", userID, state, data)
+	InfoLog.Printf("DebugUserState: user %d, state: %s, data: %+v", userID, session.State, session.Data)
 }
 
 // GetAllUserData возвращает все данные пользователя для отладки
 func (sm *StateManager) GetAllUserData(userID int64) map[string]interface{} {
-	sm.mutex.RLock()
-	defer sm.mutex.RUnlock()
-
-	if sm.userData[userID] == nil {
-		return make(map[string]interface{})
-	}
+	session, unlock := sm.lock(userID)
+	defer unlock()
 
 	// Создаем копию для безопасного использования
 	result := make(map[string]interface{})
-	for k, v := range sm.userData[userID] {
+	for k, v := range session.Data {
 		result[k] = v
 	}
 	return result
@@ -119,89 +195,241 @@ func (sm *StateManager) GetAllUserData(userID int64) map[string]interface{} {
 
 // ClearUserDataByKey очищает конкретный ключ данных пользователя
 func (sm *StateManager) ClearUserDataByKey(userID int64, key string) {
-	sm.mutex.Lock()
-	defer sm.mutex.Unlock()
+	session, unlock := sm.lock(userID)
+	defer unlock()
 
-	if sm.userData[userID] != nil {
-		delete(sm.userData[userID], key)
+	if _, exists := session.Data[key]; exists {
+		delete(session.Data, key)
+		sm.persist(session)
 	}
 }
 
 // UserHasState проверяет, есть ли у пользователя состояние
 func (sm *StateManager) UserHasState(userID int64) bool {
-	sm.mutex.RLock()
-	defer sm.mutex.RUnlock()
-
-	_, exists := sm.userStates[userID]
-	return exists
+	session, unlock := sm.lock(userID)
+	defer unlock()
+	return session.State != ""
 }
 
 // В state_manager.go добавьте:
 func (sm *StateManager) PrintDebugInfo(userID int64) {
-	sm.mutex.RLock()
-	defer sm.mutex.RUnlock()
-
-	state := sm.userStates[userID]
-	data := sm.userData[userID]
+	session, unlock := sm.lock(userID)
+	defer unlock()
 
-	InfoLog.Printf("StateManager Debug - User: %d, State: %s, Data: %+v", userID, state, data)
+	InfoLog.Printf("StateManager Debug - User: %d, State: %s, Data: %+v", userID, session.State, session.Data)
 }
 
 // PushState добавляет состояние в историю
 func (sm *StateManager) PushState(userID int64, state string) {
-	sm.mutex.Lock()
-	defer sm.mutex.Unlock()
-
-	if sm.userHistory[userID] == nil {
-		sm.userHistory[userID] = make([]string, 0)
-	}
+	session, unlock := sm.lock(userID)
+	defer unlock()
 
 	// Добавляем текущее состояние в историю
-	sm.userHistory[userID] = append(sm.userHistory[userID], state)
+	session.History = append(session.History, state)
 
-	// Ограничиваем историю последними 10 состояниями
-	if len(sm.userHistory[userID]) > 10 {
-		sm.userHistory[userID] = sm.userHistory[userID][1:]
+	// Ограничиваем историю последними состояниями
+	if len(session.History) > maxHistoryLength {
+		session.History = session.History[len(session.History)-maxHistoryLength:]
 	}
+
+	sm.persist(session)
 }
 
 // PopState возвращает предыдущее состояние и удаляет его из истории
 func (sm *StateManager) PopState(userID int64) (string, bool) {
-	sm.mutex.Lock()
-	defer sm.mutex.Unlock()
+	session, unlock := sm.lock(userID)
+	defer unlock()
 
-	// Убираем проверку на nil, так как len() для nil слайсов возвращает 0
-	if len(sm.userHistory[userID]) == 0 {
+	if len(session.History) == 0 {
 		return "", false
 	}
 
-	// Берем последнее состояние
-	lastIndex := len(sm.userHistory[userID]) - 1
-	previousState := sm.userHistory[userID][lastIndex]
-
-	// Удаляем его из истории
-	sm.userHistory[userID] = sm.userHistory[userID][:lastIndex]
+	// Берем последнее состояние и удаляем его из истории
+	lastIndex := len(session.History) - 1
+	previousState := session.History[lastIndex]
+	session.History = session.History[:lastIndex]
+	sm.persist(session)
 
 	return previousState, true
 }
 
 // GetPreviousState возвращает предыдущее состояние без удаления
 func (sm *StateManager) GetPreviousState(userID int64) (string, bool) {
-	sm.mutex.RLock()
-	defer sm.mutex.RUnlock()
+	session, unlock := sm.lock(userID)
+	defer unlock()
 
-	// Убираем проверку на nil, так как len() для nil слайсов возвращает 0
-	if len(sm.userHistory[userID]) == 0 {
+	if len(session.History) == 0 {
 		return "", false
 	}
 
-	lastIndex := len(sm.userHistory[userID]) - 1
-	return sm.userHistory[userID][lastIndex], true
+	return session.History[len(session.History)-1], true
 }
 
 // ClearHistory очищает историю пользователя
 func (sm *StateManager) ClearHistory(userID int64) {
+	session, unlock := sm.lock(userID)
+	defer unlock()
+
+	if len(session.History) > 0 {
+		session.History = nil
+		sm.persist(session)
+	}
+}
+
+// GetAdminState возвращает состояние админ-панели пользователя ("" - панель не открыта)
+func (sm *StateManager) GetAdminState(userID int64) string {
+	session, unlock := sm.lock(userID)
+	defer unlock()
+	return session.AdminState
+}
+
+// SetAdminState устанавливает состояние админ-панели пользователя.
+// Сохраняются и данные админ-панели: указатели из GetAdminData, измененные на месте,
+// попадают в хранилище при следующем изменении сессии
+func (sm *StateManager) SetAdminState(userID int64, state string) {
+	session, unlock := sm.lock(userID)
+	defer unlock()
+
+	session.AdminState = state
+	sm.persist(session)
+}
+
+// GetAdminData возвращает временные данные админ-панели пользователя
+func (sm *StateManager) GetAdminData(userID int64, key string) interface{} {
+	session, unlock := sm.lock(userID)
+	defer unlock()
+	return session.AdminData[key]
+}
+
+// SetAdminData сохраняет временные данные админ-панели пользователя
+func (sm *StateManager) SetAdminData(userID int64, key string, value interface{}) {
+	session, unlock := sm.lock(userID)
+	defer unlock()
+
+	session.AdminData[key] = value
+	sm.persist(session)
+}
+
+// ClearAdminDataByKey очищает конкретный ключ данных админ-панели пользователя
+func (sm *StateManager) ClearAdminDataByKey(userID int64, key string) {
+	session, unlock := sm.lock(userID)
+	defer unlock()
+
+	if _, exists := session.AdminData[key]; exists {
+		delete(session.AdminData, key)
+		sm.persist(session)
+	}
+}
+
+// ClearAdminSession закрывает админ-панель пользователя: очищает ее состояние и данные
+func (sm *StateManager) ClearAdminSession(userID int64) {
+	session, unlock := sm.lock(userID)
+	defer unlock()
+
+	if session.AdminState != "" || len(session.AdminData) > 0 {
+		session.AdminState = ""
+		session.AdminData = make(map[string]interface{})
+		sm.persist(session)
+	}
+}
+
+// emptySessionCacheTTL - через сколько неиспользуемые пустые сессии вытесняются из кеша
+const emptySessionCacheTTL = 10 * time.Minute
+
+// SessionTTLFunc возвращает время жизни сессии без активности (0 - сессия не истекает)
+type SessionTTLFunc func(session *models.UserSession) time.Duration
+
+// isSessionExpired проверяет, истекла ли сессия к моменту now
+func isSessionExpired(session *models.UserSession, now time.Time, ttl SessionTTLFunc) bool {
+	if session.IsEmpty() {
+		return false
+	}
+	lifetime := ttl(session)
+	return lifetime > 0 && now.Sub(session.UpdatedAt) > lifetime
+}
+
+// resetSession очищает сессию и удаляет ее из хранилища. Вызывать под блокировкой сессии
+func (sm *StateManager) resetSession(session *models.UserSession) {
+	session.State = ""
+	session.Data = make(map[string]interface{})
+	session.History = nil
+	session.AdminState = ""
+	session.AdminData = make(map[string]interface{})
+	sm.persist(session)
+}
+
+// Touch отмечает активность пользователя.
+// Если к этому моменту сессия истекла, она очищается и возвращается ее копия, иначе nil
+func (sm *StateManager) Touch(userID int64, now time.Time, ttl SessionTTLFunc) *models.UserSession {
+	session, unlock := sm.lock(userID)
+	defer unlock()
+
+	var expired *models.UserSession
+	if isSessionExpired(session, now, ttl) {
+		expired = copySession(session)
+		sm.resetSession(session)
+	}
+
+	if session.IsEmpty() {
+		// Пустые сессии не сохраняем, время нужно только для вытеснения из кеша
+		session.UpdatedAt = now
+	} else {
+		sm.persist(session)
+	}
+
+	return expired
+}
+
+// ExpireSessions очищает истекшие сессии и возвращает их копии.
+// Кроме кеша проверяются сессии из хранилища без активности с момента idleBefore,
+// чтобы после перезапуска не оставались сессии пользователей, которые больше не пишут боту.
+// Под общей блокировкой берется только снимок кеша, сессии проверяются и сохраняются по одной
+func (sm *StateManager) ExpireSessions(now time.Time, idleBefore time.Time, ttl SessionTTLFunc) []*models.UserSession {
+	stored, err := sm.storage.ListIdleSessions(idleBefore)
+	if err != nil {
+		ErrorLog.Printf("Error loading idle sessions: %v", err)
+	}
+
+	sm.mutex.Lock()
+	for _, session := range stored {
+		if _, cached := sm.sessions[session.UserID]; !cached {
+			initSessionMaps(session)
+			sm.sessions[session.UserID] = &sessionEntry{session: session}
+		}
+	}
+	entries := make(map[int64]*sessionEntry, len(sm.sessions))
+	for userID, entry := range sm.sessions {
+		entries[userID] = entry
+	}
+	sm.mutex.Unlock()
+
+	var expired []*models.UserSession
+	for userID, entry := range entries {
+		entry.mutex.Lock()
+		session := entry.session
+		switch {
+		case entry.evicted || session == nil:
+			// Сессию еще загружают или уже вытеснили
+		case isSessionExpired(session, now, ttl):
+			expired = append(expired, copySession(session))
+			sm.resetSession(session)
+			sm.evict(userID, entry)
+		case session.IsEmpty() && now.Sub(session.UpdatedAt) > emptySessionCacheTTL:
+			sm.evict(userID, entry)
+		}
+		entry.mutex.Unlock()
+	}
+
+	return expired
+}
+
+// evict удаляет запись из кеша. Вызывать под блокировкой записи
+func (sm *StateManager) evict(userID int64, entry *sessionEntry) {
+	entry.evicted = true
+
 	sm.mutex.Lock()
 	defer sm.mutex.Unlock()
-	delete(sm.userHistory, userID)
+	if sm.sessions[userID] == entry {
+		delete(sm.sessions, userID)
+	}
 }
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/drerr0r/vetbot/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// blockingStateStorage задерживает ListIdleSessions, пока тест не разрешит продолжить
+type blockingStateStorage struct {
+	*MemoryStateStorage
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingStateStorage) ListIdleSessions(before time.Time) ([]*models.UserSession, error) {
+	close(s.started)
+	<-s.release
+	return s.MemoryStateStorage.ListIdleSessions(before)
+}
+
+// ============================================================================
+// ТЕСТЫ ДЛЯ ХРАНЕНИЯ СЕССИЙ
+// ============================================================================
+
+func TestStateManagerStorage(t *testing.T) {
+	t.Run("Session survives restart", func(t *testing.T) {
+		storage := NewMemoryStateStorage()
+		sm := NewStateManagerWithStorage(storage)
+
+		sm.SetUserState(100, "review_comment")
+		sm.SetUserData(100, "review_vet_id", 5)
+		sm.PushState(100, "main_menu")
+		sm.PushState(100, "main_specializations")
+
+		// Новый менеджер с тем же хранилищем - как после перезапуска бота
+		restarted := NewStateManagerWithStorage(storage)
+
+		assert.Equal(t, "review_comment", restarted.GetUserState(100))
+		vetID, ok := restarted.GetUserDataInt(100, "review_vet_id")
+		assert.True(t, ok)
+		assert.Equal(t, 5, vetID)
+
+		previous, ok := restarted.PopState(100)
+		assert.True(t, ok)
+		assert.Equal(t, "main_specializations", previous)
+	})
+
+	t.Run("Empty session is deleted from storage", func(t *testing.T) {
+		storage := NewMemoryStateStorage()
+		sm := NewStateManagerWithStorage(storage)
+
+		sm.SetUserState(100, "review_comment")
+		sm.ClearUserState(100)
+
+		session, err := storage.LoadSession(100)
+		assert.NoError(t, err)
+		assert.Nil(t, session)
+	})
+
+	t.Run("History is limited", func(t *testing.T) {
+		sm := NewStateManager()
+		for i := 0; i < maxHistoryLength+5; i++ {
+			sm.PushState(100, "main_menu")
+		}
+		sm.PushState(100, "main_help")
+
+		count := 0
+		for {
+			if _, ok := sm.PopState(100); !ok {
+				break
+			}
+			count++
+		}
+		assert.Equal(t, maxHistoryLength, count)
+	})
+
+	t.Run("Cache is not shared with storage", func(t *testing.T) {
+		storage := NewMemoryStateStorage()
+		sm := NewStateManagerWithStorage(storage)
+		sm.SetUserData(100, "key", "value")
+
+		session, _ := storage.LoadSession(100)
+		session.Data["key"] = "changed"
+
+		assert.Equal(t, "value", sm.GetUserData(100, "key"))
+	})
+	t.Run("Slow storage does not block other users", func(t *testing.T) {
+		storage := &blockingStateStorage{
+			MemoryStateStorage: NewMemoryStateStorage(),
+			started:            make(chan struct{}),
+			release:            make(chan struct{}),
+		}
+		sm := NewStateManagerWithStorage(storage)
+		sm.SetUserState(100, "review_comment")
+
+		swept := make(chan struct{})
+		go func() {
+			sm.ExpireSessions(time.Now(), time.Now(), func(*models.UserSession) time.Duration { return 0 })
+			close(swept)
+		}()
+		<-storage.started
+
+		updated := make(chan struct{})
+		go func() {
+			sm.SetUserState(200, "search_city")
+			close(updated)
+		}()
+		select {
+		case <-updated:
+		case <-time.After(time.Second):
+			t.Fatal("сессия пользователя ждет, пока очистка читает хранилище")
+		}
+		assert.Equal(t, "search_city", sm.GetUserState(200))
+
+		close(storage.release)
+		<-swept
+		assert.Equal(t, "review_comment", sm.GetUserState(100))
+	})
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"sync"
+	"time"
+
+	"github.com/drerr0r/vetbot/internal/models"
+)
+
+// MemoryStateStorage хранит сессии пользователей в памяти процесса (используется в тестах)
+type MemoryStateStorage struct {
+	sessions map[int64]*models.UserSession
+	mutex    sync.RWMutex
+}
+
+// NewMemoryStateStorage создает хранилище сессий в памяти
+func NewMemoryStateStorage() *MemoryStateStorage {
+	return &MemoryStateStorage{
+		sessions: make(map[int64]*models.UserSession),
+	}
+}
+
+// LoadSession возвращает копию сессии пользователя или nil, если сессии нет
+func (s *MemoryStateStorage) LoadSession(userID int64) (*models.UserSession, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	session, exists := s.sessions[userID]
+	if !exists {
+		return nil, nil
+	}
+	return copySession(session), nil
+}
+
+// SaveSession сохраняет копию сессии пользователя
+func (s *MemoryStateStorage) SaveSession(session *models.UserSession) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.sessions[session.UserID] = copySession(session)
+	return nil
+}
+
+// DeleteSession удаляет сессию пользователя
+func (s *MemoryStateStorage) DeleteSession(userID int64) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.sessions, userID)
+	return nil
+}
+
+// ListIdleSessions возвращает копии сессий без активности с момента before
+func (s *MemoryStateStorage) ListIdleSessions(before time.Time) ([]*models.UserSession, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var result []*models.UserSession
+	for _, session := range s.sessions {
+		if session.UpdatedAt.Before(before) {
+			result = append(result, copySession(session))
+		}
+	}
+	return result, nil
+}
+
+// copySession создает копию сессии, чтобы хранилище не разделяло данные с кешем
+func copySession(session *models.UserSession) *models.UserSession {
+	result := *session
+
+	result.Data = make(map[string]interface{}, len(session.Data))
+	for k, v := range session.Data {
+		result.Data[k] = v
+	}
+	result.History = append([]string(nil), session.History...)
+	if session.AdminData != nil {
+		result.AdminData = make(map[string]interface{}, len(session.AdminData))
+		for k, v := range session.AdminData {
+			result.AdminData[k] = v
+		}
+	}
+
+	return &result
+}
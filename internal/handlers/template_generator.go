@@ -3,6 +3,7 @@ package handlers
 import (
 	"log"
 
+	"github.com/drerr0r/vetbot/internal/imports"
 	"github.com/drerr0r/vetbot/internal/models"
 	"github.com/xuri/excelize/v2"
 )
@@ -20,7 +21,7 @@ func (tg *TemplateGenerator) GenerateTemplate(filepath string) error {
 	f := excelize.NewFile()
 
 	// Создаем основной лист с данными
-	sheetName := "Врачи"
+	sheetName := imports.VetsSheetName
 	index, err := f.NewSheet(sheetName)
 	if err != nil {
 		return err
@@ -32,19 +33,7 @@ func (tg *TemplateGenerator) GenerateTemplate(filepath string) error {
 		log.Printf("Warning: failed to delete default sheet: %v", err)
 	}
 
-	// Заголовки колонок
-	headers := []string{
-		"Имя",
-		"Фамилия",
-		"Телефон",
-		"Email",
-		"ОпытРаботы",
-		"Описание",
-		"Город",
-		"Специализации",
-		"КлиникиИРасписание",
-		"ПримерыЗаполнения",
-	}
+	headers := vetSheetHeaders
 
 	// Устанавливаем заголовки
 	for col, header := range headers {
@@ -72,6 +61,8 @@ func (tg *TemplateGenerator) GenerateTemplate(filepath string) error {
 			"Специализации":      "Терапевт, Хирург",
 			"КлиникиИРасписание": "ВетКлиника Центр:Пн:9-18,Ср:9-18,Пт:9-18;ВетКлиника Север:Вт:10-19,Чт:10-19",
 			"ПримерыЗаполнения":  "✅ Корректный пример",
+			"Отчество":           "Сергеевич",
+			"Активен":            "да",
 		},
 		{
 			"Имя":                "Мария",
@@ -108,7 +99,7 @@ func (tg *TemplateGenerator) GenerateTemplate(filepath string) error {
 	}
 
 	// Настраиваем ширину колонок
-	tg.setColumnWidths(f, sheetName)
+	setVetSheetColumnWidths(f, sheetName)
 
 	// Сохраняем файл
 	return f.SaveAs(filepath)
@@ -177,6 +168,9 @@ func (tg *TemplateGenerator) createInstructionSheet(f *excelize.File, sheetName
 		"- Специализации: перечисляются через запятую - 'Терапевт, Хирург'",
 		"- Клиники и расписание: формат 'НазваниеКлиники:День:Часы;ДругаяКлиника:День:Часы'",
 		"- Пример: 'ВетКлиника Центр:Пн:9-18,Ср:9-18;ВетКлиника Север:Вт:10-19'",
+		"- Выключенный прием помечается '(выкл)': 'ВетКлиника Центр:Пт:9-18(выкл)'",
+		"- Отчество: необязательно",
+		"- Активен: 'да' или 'нет'; пустая ячейка не меняет активность, новый врач будет активен",
 		"",
 		"ОБОЗНАЧЕНИЯ ДНЕЙ:",
 		"- Пн, Вт, Ср, Чт, Пт, Сб, Вс",
@@ -195,24 +189,3 @@ func (tg *TemplateGenerator) createInstructionSheet(f *excelize.File, sheetName
 	f.SetActiveSheet(index)
 	return nil
 }
-
-func (tg *TemplateGenerator) setColumnWidths(f *excelize.File, sheetName string) {
-	widths := map[string]float64{
-		"A": 15, // Имя
-		"B": 15, // Фамилия
-		"C": 20, // Телефон
-		"D": 25, // Email
-		"E": 12, // Опыт
-		"F": 30, // Описание
-		"G": 15, // Город
-		"H": 20, // Специализации
-		"I": 40, // Клиники и расписание
-		"J": 25, // Примеры
-	}
-
-	for col, width := range widths {
-		if err := f.SetColWidth(sheetName, col, col, width); err != nil {
-			log.Printf("Warning: failed to set column width for %s: %v", col, err)
-		}
-	}
-}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
@@ -25,6 +26,11 @@ type MockDatabase struct {
 	Clinics                     map[int]*models.Clinic
 	Schedules                   map[int]*models.Schedule
 	Cities                      map[int]*models.City
+	Appointments                map[int]*models.Appointment
+	Favorites                   map[int]map[int]bool
+	VetClinics                  map[int]map[int]bool
+	ImportRequests              map[int]*models.ImportRequest
+	Exceptions                  map[int]*models.ScheduleException
 	UserError                   error
 	SpecializationsError        error
 	VeterinariansError          error
@@ -52,6 +58,11 @@ func NewMockDatabase() *MockDatabase {
 		Clinics:         make(map[int]*models.Clinic),
 		Schedules:       make(map[int]*models.Schedule),
 		Cities:          make(map[int]*models.City),
+		Appointments:    make(map[int]*models.Appointment),
+		Favorites:       make(map[int]map[int]bool),
+		VetClinics:      make(map[int]map[int]bool),
+		ImportRequests:  make(map[int]*models.ImportRequest),
+		Exceptions:      make(map[int]*models.ScheduleException),
 	}
 }
 
@@ -114,6 +125,10 @@ func (m *MockDatabase) GetVeterinariansBySpecialization(specializationID int) ([
 
 	result := make([]*models.Veterinarian, 0)
 	for _, vet := range m.Veterinarians {
+		// Как в базе: только активные врачи
+		if !vet.IsActive {
+			continue
+		}
 		for _, spec := range vet.Specializations {
 			if spec.ID == specializationID {
 				result = append(result, vet)
@@ -134,6 +149,13 @@ func (m *MockDatabase) GetAllClinics() ([]*models.Clinic, error) {
 	for _, clinic := range m.Clinics {
 		result = append(result, clinic)
 	}
+	// Как и база, сортируем по названию, чтобы номера в списках не менялись между вызовами
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].ID < result[j].ID
+	})
 	return result, nil
 }
 
@@ -143,9 +165,10 @@ func (m *MockDatabase) GetSchedulesByVetID(vetID int) ([]*models.Schedule, error
 		return nil, m.SchedulesError
 	}
 
+	// Как и база, возвращаем только доступные приемы
 	result := make([]*models.Schedule, 0)
 	for _, schedule := range m.Schedules {
-		if schedule.VetID == vetID {
+		if schedule.VetID == vetID && schedule.IsAvailable {
 			result = append(result, schedule)
 		}
 	}
@@ -161,15 +184,133 @@ func (m *MockDatabase) GetSpecializationsByVetID(vetID int) ([]*models.Specializ
 	return vet.Specializations, nil
 }
 
-// FindAvailableVets ищет доступных врачей
-func (m *MockDatabase) FindAvailableVets(criteria *models.SearchCriteria) ([]*models.Veterinarian, error) {
+// GetClinicsByVetID возвращает активные клиники, к которым привязан врач
+func (m *MockDatabase) GetClinicsByVetID(vetID int) ([]*models.Clinic, error) {
+	if m.ClinicsError != nil {
+		return nil, m.ClinicsError
+	}
+
+	result := make([]*models.Clinic, 0)
+	for clinicID := range m.VetClinics[vetID] {
+		if clinic, exists := m.Clinics[clinicID]; exists && clinic.IsActive {
+			result = append(result, clinic)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// AddVetToClinic привязывает врача к клинике
+func (m *MockDatabase) AddVetToClinic(vetID int, clinicID int) error {
+	if m.ClinicsError != nil {
+		return m.ClinicsError
+	}
+	m.linkVetClinic(vetID, clinicID)
+	return nil
+}
+
+// linkVetClinic привязывает врача к клинике, как это делает база при сохранении приема
+func (m *MockDatabase) linkVetClinic(vetID int, clinicID int) {
+	if m.VetClinics[vetID] == nil {
+		m.VetClinics[vetID] = make(map[int]bool)
+	}
+	m.VetClinics[vetID][clinicID] = true
+}
+
+// RemoveVetFromClinic отвязывает врача от клиники вместе с его приемами и исключениями в ней
+func (m *MockDatabase) RemoveVetFromClinic(vetID int, clinicID int) error {
+	if m.ClinicsError != nil {
+		return m.ClinicsError
+	}
+	if !m.VetClinics[vetID][clinicID] {
+		return sql.ErrNoRows
+	}
+	for id, schedule := range m.Schedules {
+		if schedule.VetID == vetID && schedule.ClinicID == clinicID {
+			delete(m.Schedules, id)
+		}
+	}
+	for id, exception := range m.Exceptions {
+		if exception.VetID == vetID && exception.ClinicID.Valid && int(exception.ClinicID.Int64) == clinicID {
+			delete(m.Exceptions, id)
+		}
+	}
+	delete(m.VetClinics[vetID], clinicID)
+	return nil
+}
+
+// GetVetsByClinic возвращает врачей, привязанных к клинике, включая неактивных
+func (m *MockDatabase) GetVetsByClinic(clinicID int) ([]*models.Veterinarian, error) {
+	if m.VeterinariansError != nil {
+		return nil, m.VeterinariansError
+	}
+
+	result := make([]*models.Veterinarian, 0)
+	for vetID, clinics := range m.VetClinics {
+		if vet, exists := m.Veterinarians[vetID]; exists && clinics[clinicID] {
+			result = append(result, vet)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].FirstName != result[j].FirstName {
+			return result[i].FirstName < result[j].FirstName
+		}
+		return result[i].LastName < result[j].LastName
+	})
+	return result, nil
+}
+
+// CreateImportRequest сохраняет задачу импорта
+func (m *MockDatabase) CreateImportRequest(request *models.ImportRequest) error {
+	if request.Status == "" {
+		request.Status = models.ImportStatusPending
+	}
+	request.ID = len(m.ImportRequests) + 1
+	request.CreatedAt = time.Now()
+	saved := *request
+	m.ImportRequests[request.ID] = &saved
+	return nil
+}
+
+// UpdateImportRequest обновляет задачу импорта
+func (m *MockDatabase) UpdateImportRequest(request *models.ImportRequest) error {
+	if _, exists := m.ImportRequests[request.ID]; !exists {
+		return sql.ErrNoRows
+	}
+	saved := *request
+	m.ImportRequests[request.ID] = &saved
+	return nil
+}
+
+// GetRecentImportRequests возвращает последние задачи импорта, новые первыми
+func (m *MockDatabase) GetRecentImportRequests(limit int) ([]*models.ImportRequest, error) {
+	result := make([]*models.ImportRequest, 0, len(m.ImportRequests))
+	for _, request := range m.ImportRequests {
+		saved := *request
+		result = append(result, &saved)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID > result[j].ID })
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// SearchVets ищет врачей по всем критериям поиска (активность не проверяется, как и раньше в моке)
+func (m *MockDatabase) SearchVets(criteria *models.SearchCriteria) ([]*models.Veterinarian, error) {
 	if m.VeterinariansError != nil {
 		return nil, m.VeterinariansError
 	}
 
+	if _, _, err := criteria.ScheduleWindow(time.Now()); err != nil {
+		return nil, err
+	}
+
 	result := make([]*models.Veterinarian, 0)
 
 	for _, vet := range m.Veterinarians {
+		vetID := models.GetVetIDAsIntOrZero(vet)
+
 		// Фильтрация по специализации
 		if criteria.SpecializationID > 0 {
 			found := false
@@ -184,46 +325,39 @@ func (m *MockDatabase) FindAvailableVets(criteria *models.SearchCriteria) ([]*mo
 			}
 		}
 
-		// Фильтрация по дню недели
-		if criteria.DayOfWeek > 0 {
-			hasSchedule := false
-			for _, schedule := range m.Schedules {
-				if schedule.VetID == models.GetVetIDAsIntOrZero(vet) && schedule.DayOfWeek == criteria.DayOfWeek {
-					hasSchedule = true
-					break
-				}
-			}
-			if !hasSchedule {
+		// Фильтрация по городу врача
+		if criteria.CityID > 0 {
+			inCity := (vet.CityID.Valid && int(vet.CityID.Int64) == criteria.CityID) ||
+				(vet.City != nil && vet.City.ID == criteria.CityID)
+			if !inCity {
 				continue
 			}
-		}
-
-		// Фильтрация по клинике
-		if criteria.ClinicID > 0 {
-			hasClinic := false
-			for _, schedule := range m.Schedules {
-				if schedule.VetID == models.GetVetIDAsIntOrZero(vet) && schedule.ClinicID == criteria.ClinicID {
-					hasClinic = true
-					break
-				}
-			}
-			if !hasClinic {
+		} else if criteria.CityName != "" {
+			if vet.City == nil || !strings.EqualFold(vet.City.Name, criteria.CityName) {
 				continue
 			}
 		}
 
-		// Фильтрация по городу
-		if criteria.CityID > 0 {
-			hasCity := false
-			for _, schedule := range m.Schedules {
-				if schedule.VetID == models.GetVetIDAsIntOrZero(vet) {
-					// Здесь должна быть логика проверки города через клинику
-					// Для упрощения считаем, что все врачи работают в указанном городе
-					hasCity = true
+		// Фильтры по расписанию должны выполняться для одного и того же приема.
+		// Даты и время считаются по местному времени клиник врача
+		now := time.Now().In(m.vetLocation(vetID))
+		windowStart, day, _ := criteria.ScheduleWindow(now)
+		clock := criteria.Time
+		if criteria.OpenNow {
+			// Как в базе, неделя начинается вчера: ночная смена после полуночи сверяется с исключениями на дату начала
+			day, clock = weekdayNumber(now), now.Format("15:04")
+			windowStart = windowStart.AddDate(0, 0, -1)
+		}
+		if day > 0 || clock != "" || criteria.ClinicID > 0 ||
+			criteria.District != "" || criteria.MetroStation != "" {
+			matched := false
+			for _, schedule := range m.searchSchedules(vetID, windowStart) {
+				if m.scheduleMatches(schedule, criteria, day, clock) {
+					matched = true
 					break
 				}
 			}
-			if !hasCity {
+			if !matched {
 				continue
 			}
 		}
@@ -231,9 +365,81 @@ func (m *MockDatabase) FindAvailableVets(criteria *models.SearchCriteria) ([]*mo
 		result = append(result, vet)
 	}
 
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].FirstName != result[j].FirstName {
+			return result[i].FirstName < result[j].FirstName
+		}
+		return result[i].LastName < result[j].LastName
+	})
+
 	return result, nil
 }
 
+// searchSchedules возвращает приемы врача для поиска. Если у врача есть исключения,
+// как и в базе берется расписание на неделю с windowStart с их учетом
+func (m *MockDatabase) searchSchedules(vetID int, windowStart time.Time) []*models.Schedule {
+	schedules, _ := m.GetSchedulesByVetID(vetID)
+	exceptions, _ := m.GetScheduleExceptionsByVetID(vetID, windowStart)
+	if len(exceptions) == 0 {
+		return schedules
+	}
+
+	var week []*models.Schedule
+	for i := 0; i < 7; i++ {
+		week = append(week, models.EffectiveSchedules(schedules, exceptions, windowStart.AddDate(0, 0, i))...)
+	}
+	return week
+}
+
+// vetLocation возвращает часовой пояс врача по городу первой клиники из его расписания
+func (m *MockDatabase) vetLocation(vetID int) *time.Location {
+	schedules, _ := m.GetSchedulesByVetID(vetID)
+	for _, schedule := range schedules {
+		clinic, exists := m.Clinics[schedule.ClinicID]
+		if !exists || !clinic.CityID.Valid {
+			continue
+		}
+		if city, exists := m.Cities[int(clinic.CityID.Int64)]; exists {
+			return city.Location()
+		}
+	}
+	return models.SchedulesLocation(schedules)
+}
+
+// scheduleMatches проверяет прием на соответствие фильтрам дня, времени clock, клиники, района и метро
+func (m *MockDatabase) scheduleMatches(schedule *models.Schedule, criteria *models.SearchCriteria, day int, clock string) bool {
+	if clock != "" {
+		// Время учитывает ночные смены и день недели
+		if !schedule.IsWorkingAt(day, clock) {
+			return false
+		}
+	} else if day > 0 && schedule.DayOfWeek != day {
+		return false
+	}
+
+	if criteria.ClinicID > 0 && schedule.ClinicID != criteria.ClinicID {
+		return false
+	}
+
+	if criteria.District != "" || criteria.MetroStation != "" {
+		clinic := schedule.Clinic
+		if stored, exists := m.Clinics[schedule.ClinicID]; exists {
+			clinic = stored
+		}
+		if clinic == nil {
+			return false
+		}
+		if criteria.District != "" && !strings.EqualFold(clinic.District.String, criteria.District) {
+			return false
+		}
+		if criteria.MetroStation != "" && !strings.EqualFold(clinic.MetroStation.String, criteria.MetroStation) {
+			return false
+		}
+	}
+
+	return true
+}
+
 // GetAllVeterinarians возвращает всех врачей
 func (m *MockDatabase) GetAllVeterinarians() ([]*models.Veterinarian, error) {
 	result := make([]*models.Veterinarian, 0, len(m.Veterinarians))
@@ -261,11 +467,6 @@ func (m *MockDatabase) GetClinicByID(id int) (*models.Clinic, error) {
 	return clinic, nil
 }
 
-// AddMissingColumns добавляет отсутствующие колонки
-func (m *MockDatabase) AddMissingColumns() error {
-	return nil
-}
-
 // ============================================================================
 // НОВЫЕ МЕТОДЫ ДЛЯ РАБОТЫ С ГОРОДАМИ
 // ============================================================================
@@ -312,6 +513,9 @@ func (m *MockDatabase) CreateCity(city *models.City) error {
 	if city.ID == 0 {
 		city.ID = len(m.Cities) + 1
 	}
+	if city.Timezone == "" {
+		city.Timezone = models.DefaultTimezone
+	}
 
 	m.Cities[city.ID] = city
 	return nil
@@ -328,12 +532,6 @@ func (m *MockDatabase) GetClinicsByCity(cityID int) ([]*models.Clinic, error) {
 	return result, nil
 }
 
-// FindVetsByCity ищет врачей по городу
-func (m *MockDatabase) FindVetsByCity(criteria *models.SearchCriteria) ([]*models.Veterinarian, error) {
-	// Используем существующую логику поиска
-	return m.FindAvailableVets(criteria)
-}
-
 // GetCitiesByRegion возвращает города по региону
 func (m *MockDatabase) GetCitiesByRegion(region string) ([]*models.City, error) {
 	result := make([]*models.City, 0)
@@ -457,7 +655,9 @@ type MockBot struct {
 	SentMessages   []tgbotapi.MessageConfig
 	Callbacks      []tgbotapi.CallbackConfig
 	EditedMessages []tgbotapi.EditMessageTextConfig
-	Files          map[string]tgbotapi.File // Для хранения файлов
+	Documents      []tgbotapi.DocumentConfig // Отправленные файлы
+	Files          map[string]tgbotapi.File  // Для хранения файлов
+	EditError      error                     // Ошибка, которую вернет редактирование сообщения
 }
 
 // NewMockBot создает новый мок бота
@@ -480,8 +680,14 @@ func (m *MockBot) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
 		m.Callbacks = append(m.Callbacks, msg)
 		return tgbotapi.Message{}, nil
 	case tgbotapi.EditMessageTextConfig:
+		if m.EditError != nil {
+			return tgbotapi.Message{}, m.EditError
+		}
 		m.EditedMessages = append(m.EditedMessages, msg)
 		return tgbotapi.Message{MessageID: len(m.EditedMessages)}, nil
+	case tgbotapi.DocumentConfig:
+		m.Documents = append(m.Documents, msg)
+		return tgbotapi.Message{MessageID: len(m.Documents)}, nil
 	default:
 		return tgbotapi.Message{}, fmt.Errorf("unsupported message type: %T", c)
 	}
@@ -535,6 +741,7 @@ func (m *MockBot) Clear() {
 	m.SentMessages = make([]tgbotapi.MessageConfig, 0)
 	m.Callbacks = make([]tgbotapi.CallbackConfig, 0)
 	m.EditedMessages = make([]tgbotapi.EditMessageTextConfig, 0)
+	m.Documents = make([]tgbotapi.DocumentConfig, 0)
 	m.Files = make(map[string]tgbotapi.File)
 }
 
@@ -564,11 +771,29 @@ func (b *TestUpdateBuilder) WithMessage(text string, chatID int64, userID int64)
 	return b
 }
 
+// WithCommand добавляет сообщение с командой (например, "/start"), распознаваемой Message.IsCommand
+func (b *TestUpdateBuilder) WithCommand(text string, chatID int64, userID int64) *TestUpdateBuilder {
+	b.WithMessage(text, chatID, userID)
+	commandLength := len(strings.Fields(text)[0])
+	b.update.Message.Entities = []tgbotapi.MessageEntity{
+		{Type: "bot_command", Offset: 0, Length: commandLength},
+	}
+	return b
+}
+
+// WithLocation добавляет сообщение с геопозицией
+func (b *TestUpdateBuilder) WithLocation(latitude, longitude float64, chatID int64, userID int64) *TestUpdateBuilder {
+	b.WithMessage("", chatID, userID)
+	b.update.Message.Location = &tgbotapi.Location{Latitude: latitude, Longitude: longitude}
+	return b
+}
+
 // WithCallback добавляет callback query
 func (b *TestUpdateBuilder) WithCallback(data string, chatID int64, messageID int) *TestUpdateBuilder {
 	b.update.CallbackQuery = &tgbotapi.CallbackQuery{
 		ID:      "test_callback",
 		Data:    data,
+		From:    &tgbotapi.User{ID: chatID},
 		Message: &tgbotapi.Message{Chat: &tgbotapi.Chat{ID: chatID}, MessageID: messageID},
 	}
 	return b
@@ -633,6 +858,9 @@ func (m *MockDatabase) UpdateCity(city *models.City) error {
 	if _, exists := m.Cities[city.ID]; !exists {
 		return fmt.Errorf("city not found")
 	}
+	if city.Timezone == "" {
+		city.Timezone = models.DefaultTimezone
+	}
 	m.Cities[city.ID] = city
 	return nil
 }
@@ -785,7 +1013,7 @@ func (m *MockDatabase) GetSpecializationByName(name string) (*models.Specializat
 	}
 
 	for _, spec := range m.Specializations {
-		if spec.Name == name {
+		if strings.EqualFold(strings.TrimSpace(spec.Name), strings.TrimSpace(name)) {
 			return spec, nil
 		}
 	}
@@ -1324,3 +1552,423 @@ func (m *MockDatabase) GetAllActiveVeterinarians() ([]*models.Veterinarian, erro
 	// Возвращаем пустой список или тестовые данные
 	return []*models.Veterinarian{}, nil
 }
+
+// ========== МЕТОДЫ ДЛЯ ЗАПИСИ НА ПРИЕМ ==========
+
+func (m *MockDatabase) CreateAppointment(appointment *models.Appointment) error {
+	for _, existing := range m.Appointments {
+		if existing.Status == models.AppointmentStatusBooked &&
+			existing.VetID == appointment.VetID &&
+			existing.Date.Format("2006-01-02") == appointment.Date.Format("2006-01-02") &&
+			existing.StartTime == appointment.StartTime {
+			return models.ErrAppointmentSlotTaken
+		}
+	}
+
+	appointment.ID = len(m.Appointments) + 1
+	if appointment.Status == "" {
+		appointment.Status = models.AppointmentStatusBooked
+	}
+	m.Appointments[appointment.ID] = appointment
+	return nil
+}
+
+func (m *MockDatabase) GetBookedAppointments(vetID int, date time.Time) ([]*models.Appointment, error) {
+	var result []*models.Appointment
+	for _, appointment := range m.Appointments {
+		if appointment.VetID == vetID && appointment.Status == models.AppointmentStatusBooked &&
+			appointment.Date.Format("2006-01-02") == date.Format("2006-01-02") {
+			result = append(result, appointment)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockDatabase) GetUpcomingAppointmentsByUser(userID int, now time.Time) ([]*models.Appointment, error) {
+	var result []*models.Appointment
+	for _, appointment := range m.Appointments {
+		// Как в базе: дата записи не раньше сегодняшней по местному времени клиники
+		today := now.In(m.Clinics[appointment.ClinicID].Location()).Format("2006-01-02")
+		if appointment.UserID == userID && appointment.Status == models.AppointmentStatusBooked &&
+			appointment.Date.Format("2006-01-02") >= today {
+			result = append(result, appointment)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockDatabase) CancelAppointment(appointmentID int, userID int) error {
+	appointment, exists := m.Appointments[appointmentID]
+	if !exists || appointment.UserID != userID || appointment.Status != models.AppointmentStatusBooked {
+		return sql.ErrNoRows
+	}
+	appointment.Status = models.AppointmentStatusCancelled
+	return nil
+}
+
+// ========== МЕТОДЫ ДЛЯ ИЗБРАННОГО ==========
+
+func (m *MockDatabase) AddFavorite(userID int, vetID int) error {
+	if m.Favorites[userID] == nil {
+		m.Favorites[userID] = make(map[int]bool)
+	}
+	m.Favorites[userID][vetID] = true
+	return nil
+}
+
+func (m *MockDatabase) RemoveFavorite(userID int, vetID int) error {
+	delete(m.Favorites[userID], vetID)
+	return nil
+}
+
+func (m *MockDatabase) IsFavorite(userID int, vetID int) (bool, error) {
+	return m.Favorites[userID][vetID], nil
+}
+
+func (m *MockDatabase) GetFavoriteVets(userID int) ([]*models.Veterinarian, error) {
+	var result []*models.Veterinarian
+	for vetID := range m.Favorites[userID] {
+		if vet, exists := m.Veterinarians[vetID]; exists {
+			result = append(result, vet)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return models.GetVetIDAsIntOrZero(result[i]) < models.GetVetIDAsIntOrZero(result[j])
+	})
+	return result, nil
+}
+
+func (m *MockDatabase) FindNearestClinics(latitude, longitude float64, limit int) ([]*models.ClinicDistance, error) {
+	if m.ClinicsError != nil {
+		return nil, m.ClinicsError
+	}
+
+	var result []*models.ClinicDistance
+	for _, clinic := range m.Clinics {
+		if !clinic.IsActive || !clinic.HasCoordinates() {
+			continue
+		}
+		result = append(result, &models.ClinicDistance{
+			Clinic:     clinic,
+			DistanceKm: models.DistanceKm(latitude, longitude, clinic.Latitude.Float64, clinic.Longitude.Float64),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].DistanceKm < result[j].DistanceKm
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (m *MockDatabase) GetClinicSchedulesByDate(clinicID int, date time.Time) ([]*models.Schedule, error) {
+	if m.SchedulesError != nil {
+		return nil, m.SchedulesError
+	}
+
+	var result []*models.Schedule
+	for vetID, vet := range m.Veterinarians {
+		if !vet.IsActive {
+			continue
+		}
+		schedules, _ := m.GetSchedulesByVetID(vetID)
+		exceptions, _ := m.GetScheduleExceptionsByVetID(vetID, date)
+		for _, schedule := range models.EffectiveSchedules(schedules, exceptions, date) {
+			if schedule.ClinicID != clinicID {
+				continue
+			}
+			item := *schedule
+			item.Vet = vet
+			result = append(result, &item)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].StartTime != result[j].StartTime {
+			return result[i].StartTime < result[j].StartTime
+		}
+		return result[i].VetID < result[j].VetID
+	})
+	return result, nil
+}
+
+func (m *MockDatabase) UpdateClinicCoordinates(clinicID int, latitude, longitude sql.NullFloat64) error {
+	clinic, exists := m.Clinics[clinicID]
+	if !exists {
+		return sql.ErrNoRows
+	}
+	clinic.Latitude = latitude
+	clinic.Longitude = longitude
+	return nil
+}
+
+func (m *MockDatabase) SearchVeterinariansByName(query string, limit int) ([]*models.Veterinarian, error) {
+	if m.VeterinariansError != nil {
+		return nil, m.VeterinariansError
+	}
+
+	var active []*models.Veterinarian
+	for _, vet := range m.Veterinarians {
+		if vet.IsActive {
+			active = append(active, vet)
+		}
+	}
+	return models.RankVetsByName(active, query, limit), nil
+}
+
+// GetVeterinariansByIDs возвращает врачей в порядке ids, пропуская удаленных
+func (m *MockDatabase) GetVeterinariansByIDs(ids []int) ([]*models.Veterinarian, error) {
+	if m.VeterinariansError != nil {
+		return nil, m.VeterinariansError
+	}
+
+	var vets []*models.Veterinarian
+	for _, id := range ids {
+		if vet, exists := m.Veterinarians[id]; exists {
+			vets = append(vets, vet)
+		}
+	}
+	return vets, nil
+}
+
+// SetVeterinarianSpecializations заменяет специализации врача
+func (m *MockDatabase) SetVeterinarianSpecializations(vetID int, specIDs []int) error {
+	if m.VeterinariansError != nil {
+		return m.VeterinariansError
+	}
+
+	vet, exists := m.Veterinarians[vetID]
+	if !exists {
+		return sql.ErrNoRows
+	}
+
+	specs := make([]*models.Specialization, 0, len(specIDs))
+	for _, specID := range specIDs {
+		spec, exists := m.Specializations[specID]
+		if !exists {
+			return fmt.Errorf("specialization %d not found", specID)
+		}
+		specs = append(specs, spec)
+	}
+	vet.Specializations = specs
+	return nil
+}
+
+// UpdateSpecialization обновляет специализацию
+func (m *MockDatabase) UpdateSpecialization(spec *models.Specialization) error {
+	if m.SpecializationsError != nil {
+		return m.SpecializationsError
+	}
+	if _, exists := m.Specializations[spec.ID]; !exists {
+		return sql.ErrNoRows
+	}
+	m.Specializations[spec.ID] = spec
+	return nil
+}
+
+// MergeSpecializations переносит врачей со специализации sourceID на targetID и удаляет sourceID
+func (m *MockDatabase) MergeSpecializations(sourceID int, targetID int) error {
+	if m.SpecializationsError != nil {
+		return m.SpecializationsError
+	}
+	target, exists := m.Specializations[targetID]
+	if !exists || sourceID == targetID {
+		return sql.ErrNoRows
+	}
+	if _, exists := m.Specializations[sourceID]; !exists {
+		return sql.ErrNoRows
+	}
+
+	for _, vet := range m.Veterinarians {
+		hasSource, hasTarget := false, false
+		for _, spec := range vet.Specializations {
+			hasSource = hasSource || spec.ID == sourceID
+			hasTarget = hasTarget || spec.ID == targetID
+		}
+		if hasSource && !hasTarget {
+			vet.Specializations = append(vet.Specializations, target)
+		}
+	}
+	return m.DeleteSpecialization(sourceID)
+}
+
+// DeleteSpecialization удаляет специализацию и ее связи с врачами
+func (m *MockDatabase) DeleteSpecialization(id int) error {
+	if m.SpecializationsError != nil {
+		return m.SpecializationsError
+	}
+	if _, exists := m.Specializations[id]; !exists {
+		return sql.ErrNoRows
+	}
+	delete(m.Specializations, id)
+
+	for _, vet := range m.Veterinarians {
+		kept := vet.Specializations[:0]
+		for _, spec := range vet.Specializations {
+			if spec.ID != id {
+				kept = append(kept, spec)
+			}
+		}
+		vet.Specializations = kept
+	}
+	return nil
+}
+
+// GetScheduleByID возвращает прием по ID
+func (m *MockDatabase) GetScheduleByID(id int) (*models.Schedule, error) {
+	if m.SchedulesError != nil {
+		return nil, m.SchedulesError
+	}
+	schedule, exists := m.Schedules[id]
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+	return schedule, nil
+}
+
+// GetWeekSchedulesByVetID возвращает приемы врача на 7 дней с учетом исключений, только в активных клиниках
+func (m *MockDatabase) GetWeekSchedulesByVetID(vetID int) ([]*models.Schedule, error) {
+	if m.SchedulesError != nil {
+		return nil, m.SchedulesError
+	}
+
+	now := time.Now().In(m.vetLocation(vetID))
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	result := make([]*models.Schedule, 0)
+	for _, schedule := range m.searchSchedules(vetID, today) {
+		clinic, exists := m.Clinics[schedule.ClinicID]
+		if !exists || !clinic.IsActive {
+			continue
+		}
+		withClinic := *schedule
+		withClinic.Clinic = clinic
+		result = append(result, &withClinic)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].DayOfWeek != result[j].DayOfWeek {
+			return result[i].DayOfWeek < result[j].DayOfWeek
+		}
+		return result[i].StartTime < result[j].StartTime
+	})
+	return result, nil
+}
+
+// GetAllSchedulesByVetID возвращает все приемы врача, включая выключенные, по клинике и дню недели
+func (m *MockDatabase) GetAllSchedulesByVetID(vetID int) ([]*models.Schedule, error) {
+	if m.SchedulesError != nil {
+		return nil, m.SchedulesError
+	}
+
+	result := make([]*models.Schedule, 0)
+	for _, schedule := range m.Schedules {
+		if schedule.VetID != vetID {
+			continue
+		}
+		withClinic := *schedule
+		if clinic, exists := m.Clinics[schedule.ClinicID]; exists {
+			withClinic.Clinic = clinic
+		}
+		result = append(result, &withClinic)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.ClinicID != b.ClinicID {
+			return a.ClinicID < b.ClinicID
+		}
+		if a.DayOfWeek != b.DayOfWeek {
+			return a.DayOfWeek < b.DayOfWeek
+		}
+		if a.StartTime != b.StartTime {
+			return a.StartTime < b.StartTime
+		}
+		return a.ID < b.ID
+	})
+	return result, nil
+}
+
+// CreateSchedule добавляет прием
+func (m *MockDatabase) CreateSchedule(schedule *models.Schedule) error {
+	if m.SchedulesError != nil {
+		return m.SchedulesError
+	}
+	if schedule.ID == 0 {
+		schedule.ID = len(m.Schedules) + 1
+	}
+	if schedule.CreatedAt.IsZero() {
+		schedule.CreatedAt = time.Now()
+	}
+	m.Schedules[schedule.ID] = schedule
+	m.linkVetClinic(schedule.VetID, schedule.ClinicID)
+	return nil
+}
+
+// UpdateSchedule обновляет прием
+func (m *MockDatabase) UpdateSchedule(schedule *models.Schedule) error {
+	if m.SchedulesError != nil {
+		return m.SchedulesError
+	}
+	if _, exists := m.Schedules[schedule.ID]; !exists {
+		return sql.ErrNoRows
+	}
+	m.Schedules[schedule.ID] = schedule
+	m.linkVetClinic(schedule.VetID, schedule.ClinicID)
+	return nil
+}
+
+// DeleteSchedule удаляет прием
+func (m *MockDatabase) DeleteSchedule(id int) error {
+	if m.SchedulesError != nil {
+		return m.SchedulesError
+	}
+	if _, exists := m.Schedules[id]; !exists {
+		return sql.ErrNoRows
+	}
+	delete(m.Schedules, id)
+	return nil
+}
+
+// CreateScheduleException добавляет исключение из расписания
+func (m *MockDatabase) CreateScheduleException(exception *models.ScheduleException) error {
+	if err := exception.Validate(); err != nil {
+		return err
+	}
+	exception.ID = len(m.Exceptions) + 1
+	for m.Exceptions[exception.ID] != nil {
+		exception.ID++
+	}
+	if exception.ClinicID.Valid {
+		if clinic, exists := m.Clinics[int(exception.ClinicID.Int64)]; exists {
+			exception.Clinic = &models.Clinic{ID: clinic.ID, Name: clinic.Name}
+		}
+	}
+	exception.CreatedAt = time.Now()
+	m.Exceptions[exception.ID] = exception
+	return nil
+}
+
+// DeleteScheduleException удаляет исключение из расписания
+func (m *MockDatabase) DeleteScheduleException(id int) error {
+	if _, exists := m.Exceptions[id]; !exists {
+		return sql.ErrNoRows
+	}
+	delete(m.Exceptions, id)
+	return nil
+}
+
+// GetScheduleExceptionsByVetID возвращает незакончившиеся к дате from исключения врача
+func (m *MockDatabase) GetScheduleExceptionsByVetID(vetID int, from time.Time) ([]*models.ScheduleException, error) {
+	var result []*models.ScheduleException
+	for _, exception := range m.Exceptions {
+		if exception.VetID != vetID || (exception.DateTo.Before(from) && !exception.Covers(from)) {
+			continue
+		}
+		result = append(result, exception)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].DateFrom.Equal(result[j].DateFrom) {
+			return result[i].DateFrom.Before(result[j].DateFrom)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
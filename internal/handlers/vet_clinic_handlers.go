@@ -0,0 +1,538 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drerr0r/vetbot/internal/models"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// vetClinicSearchLimit сколько врачей показывать, если по фамилии нашлось несколько
+const vetClinicSearchLimit = 10
+
+// showVetClinics показывает клиники, к которым привязан врач, с числом его приемов в каждой
+func (h *AdminHandlers) showVetClinics(update tgbotapi.Update, vetID int) {
+	userID := update.Message.From.ID
+	h.stateManager.SetAdminState(userID, "vet_edit_clinics")
+
+	clinics, err := h.db.GetClinicsByVetID(vetID)
+	if err != nil {
+		ErrorLog.Printf("❌ Ошибка загрузки клиник врача %d: %v", vetID, err)
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Ошибка при загрузке клиник врача")
+		h.bot.Send(msg)
+		return
+	}
+	scheduleCounts := h.vetScheduleCounts(vetID)
+
+	var sb strings.Builder
+	sb.WriteString("🏥 Клиники врача\n\n")
+	if len(clinics) == 0 {
+		sb.WriteString("Врач не привязан ни к одной клинике.\n")
+	}
+	for i, clinic := range clinics {
+		sb.WriteString(fmt.Sprintf("%d. %s - %s (приемов: %d)\n", i+1, clinic.Name, clinic.Address, scheduleCounts[clinic.ID]))
+	}
+	sb.WriteString("\nПрием в клинике в расписании привязывает к ней врача автоматически. " +
+		"При отвязке приемы и исключения врача в этой клинике удаляются.")
+
+	rows := [][]tgbotapi.KeyboardButton{
+		tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton("➕ Привязать к клинике")),
+	}
+	if len(clinics) > 0 {
+		rows = append(rows, tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton("➖ Отвязать от клиники")))
+	}
+	rows = append(rows, tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton("🔙 Назад")))
+
+	// Без Markdown: в названиях и адресах клиник бывают спецсимволы
+	msg := tgbotapi.NewMessage(update.Message.Chat.ID, sb.String())
+	msg.ReplyMarkup = tgbotapi.NewReplyKeyboard(rows...)
+	h.bot.Send(msg)
+}
+
+// handleVetClinicsMenu обрабатывает выбор действия в списке клиник врача
+func (h *AdminHandlers) handleVetClinicsMenu(update tgbotapi.Update, text string) {
+	userID := update.Message.From.ID
+	vetData, ok := h.stateManager.GetAdminData(userID, "vet_edit").(*models.VetEditData)
+	if !ok || vetData == nil {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Ошибка: данные врача не найдены")
+		h.bot.Send(msg)
+		h.showVetList(update)
+		return
+	}
+
+	switch text {
+	case "➕ Привязать к клинике":
+		clinics, err := h.vetClinicCandidates(vetData.VetID)
+		if err != nil {
+			ErrorLog.Printf("❌ Ошибка загрузки клиник: %v", err)
+			msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Ошибка при загрузке клиник")
+			h.bot.Send(msg)
+			return
+		}
+		if len(clinics) == 0 {
+			msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Нет активных клиник, к которым врач еще не привязан")
+			h.bot.Send(msg)
+			return
+		}
+
+		h.stateManager.SetAdminState(userID, "vet_edit_clinic_add")
+		var sb strings.Builder
+		sb.WriteString("Введите номер клиники, к которой нужно привязать врача:\n\n")
+		for i, clinic := range clinics {
+			sb.WriteString(fmt.Sprintf("%d. %s - %s\n", i+1, clinic.Name, clinic.Address))
+		}
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, sb.String())
+		msg.ReplyMarkup = backCancelKeyboard()
+		h.bot.Send(msg)
+
+	case "➖ Отвязать от клиники":
+		h.stateManager.SetAdminState(userID, "vet_edit_clinic_remove")
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID,
+			"Введите номер клиники из списка.\n\n⚠️ Приемы и исключения врача в этой клинике будут удалены.")
+		msg.ReplyMarkup = backCancelKeyboard()
+		h.bot.Send(msg)
+
+	default:
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Используйте кнопки для управления")
+		h.bot.Send(msg)
+	}
+}
+
+// handleVetClinicAdd привязывает врача к клинике по номеру из списка доступных клиник
+func (h *AdminHandlers) handleVetClinicAdd(update tgbotapi.Update, text string) {
+	vetData, ok := h.stateManager.GetAdminData(update.Message.From.ID, "vet_edit").(*models.VetEditData)
+	if !ok || vetData == nil {
+		h.showVetList(update)
+		return
+	}
+
+	clinics, err := h.vetClinicCandidates(vetData.VetID)
+	if err != nil {
+		ErrorLog.Printf("❌ Ошибка загрузки клиник: %v", err)
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Ошибка при загрузке клиник")
+		h.bot.Send(msg)
+		return
+	}
+	index, err := parseListNumber(text, len(clinics), "клиники")
+	if err != nil {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ "+err.Error())
+		h.bot.Send(msg)
+		return
+	}
+	clinic := clinics[index]
+
+	if h.linkVetToClinic(update, vetData.VetID, clinic) {
+		h.showVetClinics(update, vetData.VetID)
+	}
+}
+
+// handleVetClinicRemove отвязывает врача от клиники по номеру из списка его клиник
+func (h *AdminHandlers) handleVetClinicRemove(update tgbotapi.Update, text string) {
+	vetData, ok := h.stateManager.GetAdminData(update.Message.From.ID, "vet_edit").(*models.VetEditData)
+	if !ok || vetData == nil {
+		h.showVetList(update)
+		return
+	}
+
+	clinics, err := h.db.GetClinicsByVetID(vetData.VetID)
+	if err != nil {
+		ErrorLog.Printf("❌ Ошибка загрузки клиник врача %d: %v", vetData.VetID, err)
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Ошибка при загрузке клиник врача")
+		h.bot.Send(msg)
+		return
+	}
+	index, err := parseListNumber(text, len(clinics), "клиники")
+	if err != nil {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ "+err.Error())
+		h.bot.Send(msg)
+		return
+	}
+	clinic := clinics[index]
+
+	vet, err := h.db.GetVeterinarianByID(vetData.VetID)
+	if err != nil {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Ошибка при получении данных врача")
+		h.bot.Send(msg)
+		h.showVetList(update)
+		return
+	}
+
+	h.stateManager.SetAdminData(update.Message.From.ID, "unlink_clinic_id", clinic.ID)
+	h.showVetClinicUnlinkConfirm(update, vet, clinic, "vet_edit_clinic_confirm_remove")
+}
+
+// handleVetClinicConfirmRemove отвязывает врача от выбранной клиники после подтверждения
+func (h *AdminHandlers) handleVetClinicConfirmRemove(update tgbotapi.Update, text string) {
+	if text != "✅ Подтвердить отвязку" {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Используйте кнопки для подтверждения или отмены")
+		h.bot.Send(msg)
+		return
+	}
+
+	userID := update.Message.From.ID
+	vetData, ok := h.stateManager.GetAdminData(userID, "vet_edit").(*models.VetEditData)
+	if !ok || vetData == nil {
+		h.showVetList(update)
+		return
+	}
+	clinicID, _ := h.stateManager.GetAdminData(userID, "unlink_clinic_id").(int)
+	h.stateManager.ClearAdminDataByKey(userID, "unlink_clinic_id")
+
+	clinic, err := h.db.GetClinicByID(clinicID)
+	if err != nil {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Клиника не найдена")
+		h.bot.Send(msg)
+		h.showVetClinics(update, vetData.VetID)
+		return
+	}
+
+	h.unlinkVetFromClinic(update, vetData.VetID, clinic)
+	h.showVetClinics(update, vetData.VetID)
+}
+
+// showClinicVets показывает врачей, привязанных к клинике, включая неактивных
+func (h *AdminHandlers) showClinicVets(update tgbotapi.Update, clinicID int) {
+	userID := update.Message.From.ID
+	h.stateManager.SetAdminState(userID, "clinic_edit_vets")
+
+	vets, err := h.db.GetVetsByClinic(clinicID)
+	if err != nil {
+		ErrorLog.Printf("❌ Ошибка загрузки врачей клиники %d: %v", clinicID, err)
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Ошибка при загрузке врачей клиники")
+		h.bot.Send(msg)
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("👨‍⚕️ Врачи клиники\n\n")
+	if len(vets) == 0 {
+		sb.WriteString("К клинике не привязан ни один врач.\n")
+	}
+	for i, vet := range vets {
+		status := "✅"
+		if !vet.IsActive {
+			status = "❌"
+		}
+		sb.WriteString(fmt.Sprintf("%s %d. %s %s (ID: %d)\n", status, i+1, vet.FirstName, vet.LastName, models.GetVetIDAsIntOrZero(vet)))
+	}
+	sb.WriteString("\nПри удалении врача из клиники его приемы и исключения в ней удаляются.")
+
+	rows := [][]tgbotapi.KeyboardButton{
+		tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton("➕ Добавить врача")),
+	}
+	if len(vets) > 0 {
+		rows = append(rows, tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton("➖ Убрать врача")))
+	}
+	rows = append(rows, tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton("🔙 Назад")))
+
+	msg := tgbotapi.NewMessage(update.Message.Chat.ID, sb.String())
+	msg.ReplyMarkup = tgbotapi.NewReplyKeyboard(rows...)
+	h.bot.Send(msg)
+}
+
+// handleClinicVetsMenu обрабатывает выбор действия в списке врачей клиники
+func (h *AdminHandlers) handleClinicVetsMenu(update tgbotapi.Update, text string) {
+	userID := update.Message.From.ID
+
+	switch text {
+	case "➕ Добавить врача":
+		h.stateManager.SetAdminState(userID, "clinic_edit_vet_add")
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Введите ID врача или его фамилию:")
+		msg.ReplyMarkup = backCancelKeyboard()
+		h.bot.Send(msg)
+
+	case "➖ Убрать врача":
+		h.stateManager.SetAdminState(userID, "clinic_edit_vet_remove")
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID,
+			"Введите номер врача из списка.\n\n⚠️ Приемы и исключения врача в этой клинике будут удалены.")
+		msg.ReplyMarkup = backCancelKeyboard()
+		h.bot.Send(msg)
+
+	default:
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Используйте кнопки для управления")
+		h.bot.Send(msg)
+	}
+}
+
+// handleClinicVetAdd привязывает к клинике врача по ID или фамилии.
+// Если по фамилии нашлось несколько врачей, показывает их ID и ждет уточнения
+func (h *AdminHandlers) handleClinicVetAdd(update tgbotapi.Update, text string) {
+	clinic := h.currentEditClinic(update)
+	if clinic == nil {
+		return
+	}
+
+	query := strings.TrimSpace(text)
+	var vet *models.Veterinarian
+	if vetID, err := strconv.Atoi(query); err == nil {
+		vet, err = h.db.GetVeterinarianByID(vetID)
+		if err != nil {
+			msg := tgbotapi.NewMessage(update.Message.Chat.ID, fmt.Sprintf("❌ Врач с ID %d не найден", vetID))
+			h.bot.Send(msg)
+			return
+		}
+	} else {
+		all, err := h.db.GetAllVeterinarians()
+		if err != nil {
+			ErrorLog.Printf("❌ Ошибка загрузки врачей: %v", err)
+			msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Ошибка при загрузке врачей")
+			h.bot.Send(msg)
+			return
+		}
+		found := models.RankVetsByName(all, query, vetClinicSearchLimit)
+		switch len(found) {
+		case 0:
+			msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Врачи не найдены. Введите ID врача или другую фамилию:")
+			h.bot.Send(msg)
+			return
+		case 1:
+			vet = found[0]
+		default:
+			var sb strings.Builder
+			sb.WriteString("Найдено несколько врачей, введите ID нужного:\n\n")
+			for _, candidate := range found {
+				sb.WriteString(fmt.Sprintf("%d - %s %s\n", models.GetVetIDAsIntOrZero(candidate), candidate.FirstName, candidate.LastName))
+			}
+			msg := tgbotapi.NewMessage(update.Message.Chat.ID, sb.String())
+			h.bot.Send(msg)
+			return
+		}
+	}
+
+	if h.linkVetToClinic(update, models.GetVetIDAsIntOrZero(vet), clinic) {
+		h.showClinicVets(update, clinic.ID)
+	}
+}
+
+// handleClinicVetRemove отвязывает врача от клиники по номеру из списка врачей клиники
+func (h *AdminHandlers) handleClinicVetRemove(update tgbotapi.Update, text string) {
+	clinic := h.currentEditClinic(update)
+	if clinic == nil {
+		return
+	}
+
+	vets, err := h.db.GetVetsByClinic(clinic.ID)
+	if err != nil {
+		ErrorLog.Printf("❌ Ошибка загрузки врачей клиники %d: %v", clinic.ID, err)
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Ошибка при загрузке врачей клиники")
+		h.bot.Send(msg)
+		return
+	}
+	index, err := parseListNumber(text, len(vets), "врача")
+	if err != nil {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ "+err.Error())
+		h.bot.Send(msg)
+		return
+	}
+	vet := vets[index]
+
+	h.stateManager.SetAdminData(update.Message.From.ID, "unlink_vet_id", models.GetVetIDAsIntOrZero(vet))
+	h.showVetClinicUnlinkConfirm(update, vet, clinic, "clinic_edit_vet_confirm_remove")
+}
+
+// handleClinicVetConfirmRemove убирает выбранного врача из клиники после подтверждения
+func (h *AdminHandlers) handleClinicVetConfirmRemove(update tgbotapi.Update, text string) {
+	if text != "✅ Подтвердить отвязку" {
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Используйте кнопки для подтверждения или отмены")
+		h.bot.Send(msg)
+		return
+	}
+
+	clinic := h.currentEditClinic(update)
+	if clinic == nil {
+		return
+	}
+	userID := update.Message.From.ID
+	vetID, _ := h.stateManager.GetAdminData(userID, "unlink_vet_id").(int)
+	h.stateManager.ClearAdminDataByKey(userID, "unlink_vet_id")
+
+	h.unlinkVetFromClinic(update, vetID, clinic)
+	h.showClinicVets(update, clinic.ID)
+}
+
+// returnToClinicEditMenu возвращает из списка врачей клиники в меню редактирования клиники
+func (h *AdminHandlers) returnToClinicEditMenu(update tgbotapi.Update) {
+	if clinic := h.currentEditClinic(update); clinic != nil {
+		h.showClinicEditMenu(update, clinic)
+	}
+}
+
+// currentEditClinic возвращает редактируемую клинику; если ее нет, возвращает к списку клиник
+func (h *AdminHandlers) currentEditClinic(update tgbotapi.Update) *models.Clinic {
+	userID := update.Message.From.ID
+	clinicData, ok := h.stateManager.GetAdminData(userID, "clinic_edit").(*models.ClinicEditData)
+	if ok && clinicData != nil {
+		if clinic, err := h.db.GetClinicByID(clinicData.ClinicID); err == nil {
+			return clinic
+		}
+	}
+	h.stateManager.SetAdminState(userID, "clinic_management")
+	h.showClinicManagement(update)
+	return nil
+}
+
+// clinicVetsRoster описывает врачей клиники одной строкой для карточки клиники
+func (h *AdminHandlers) clinicVetsRoster(clinicID int) string {
+	vets, err := h.db.GetVetsByClinic(clinicID)
+	if err != nil {
+		ErrorLog.Printf("❌ Ошибка загрузки врачей клиники %d: %v", clinicID, err)
+		return "не удалось загрузить"
+	}
+	if len(vets) == 0 {
+		return "не привязаны"
+	}
+
+	names := make([]string, 0, len(vets))
+	for _, vet := range vets {
+		name := vet.FirstName + " " + vet.LastName
+		if !vet.IsActive {
+			name += " (неактивен)"
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// linkVetToClinic привязывает врача к клинике и сообщает об этом админу
+func (h *AdminHandlers) linkVetToClinic(update tgbotapi.Update, vetID int, clinic *models.Clinic) bool {
+	if err := h.db.AddVetToClinic(vetID, clinic.ID); err != nil {
+		ErrorLog.Printf("❌ Ошибка привязки врача %d к клинике %d: %v", vetID, clinic.ID, err)
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "❌ Ошибка при привязке врача к клинике")
+		h.bot.Send(msg)
+		return false
+	}
+
+	InfoLog.Printf("🔗 Врач %d привязан к клинике %d", vetID, clinic.ID)
+	msg := tgbotapi.NewMessage(update.Message.Chat.ID, "✅ Врач привязан к клинике "+clinic.Name)
+	h.bot.Send(msg)
+	return true
+}
+
+// showVetClinicUnlinkConfirm показывает, что удалится при отвязке врача от клиники, и ждет подтверждения в состоянии state
+func (h *AdminHandlers) showVetClinicUnlinkConfirm(update tgbotapi.Update, vet *models.Veterinarian, clinic *models.Clinic, state string) {
+	vetID := models.GetVetIDAsIntOrZero(vet)
+	h.stateManager.SetAdminState(update.Message.From.ID, state)
+
+	text := fmt.Sprintf("⚠️ Вы собираетесь отвязать врача %s %s от клиники «%s».\n\n"+
+		"Будут удалены приемы врача в этой клинике: %d, исключения из расписания: %d.\n\nПодтвердите отвязку:",
+		vet.FirstName, vet.LastName, clinic.Name, h.vetScheduleCounts(vetID)[clinic.ID], h.vetClinicExceptionCount(vetID, clinic.ID))
+
+	keyboard := tgbotapi.NewReplyKeyboard(
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton("✅ Подтвердить отвязку"),
+			tgbotapi.NewKeyboardButton("❌ Отмена"),
+		),
+	)
+	// Без Markdown: в названиях клиник бывают спецсимволы
+	msg := tgbotapi.NewMessage(update.Message.Chat.ID, text)
+	msg.ReplyMarkup = keyboard
+	h.bot.Send(msg)
+}
+
+// unlinkVetFromClinic отвязывает врача от клиники вместе с его приемами в ней и сообщает, сколько приемов удалено
+func (h *AdminHandlers) unlinkVetFromClinic(update tgbotapi.Update, vetID int, clinic *models.Clinic) {
+	removed := h.vetScheduleCounts(vetID)[clinic.ID]
+
+	if err := h.db.RemoveVetFromClinic(vetID, clinic.ID); err != nil {
+		ErrorLog.Printf("❌ Ошибка отвязки врача %d от клиники %d: %v", vetID, clinic.ID, err)
+		text := "❌ Ошибка при отвязке врача от клиники"
+		if err == sql.ErrNoRows {
+			text = "❌ Врач не привязан к этой клинике"
+		}
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, text)
+		h.bot.Send(msg)
+		return
+	}
+
+	InfoLog.Printf("✂️ Врач %d отвязан от клиники %d, удалено приемов: %d", vetID, clinic.ID, removed)
+	text := "✅ Врач отвязан от клиники " + clinic.Name
+	if removed > 0 {
+		text += fmt.Sprintf("\nУдалено приемов: %d", removed)
+	}
+	msg := tgbotapi.NewMessage(update.Message.Chat.ID, text)
+	h.bot.Send(msg)
+}
+
+// vetScheduleCounts возвращает число приемов врача по клиникам, включая выключенные
+func (h *AdminHandlers) vetScheduleCounts(vetID int) map[int]int {
+	counts := make(map[int]int)
+	schedules, err := h.db.GetAllSchedulesByVetID(vetID)
+	if err != nil {
+		ErrorLog.Printf("❌ Ошибка загрузки расписания врача %d: %v", vetID, err)
+		return counts
+	}
+	for _, schedule := range schedules {
+		counts[schedule.ClinicID]++
+	}
+	return counts
+}
+
+// vetClinicExceptionCount возвращает число исключений из расписания врача в клинике, включая прошедшие
+func (h *AdminHandlers) vetClinicExceptionCount(vetID int, clinicID int) int {
+	exceptions, err := h.db.GetScheduleExceptionsByVetID(vetID, time.Time{})
+	if err != nil {
+		ErrorLog.Printf("❌ Ошибка загрузки исключений врача %d: %v", vetID, err)
+		return 0
+	}
+	count := 0
+	for _, exception := range exceptions {
+		if exception.ClinicID.Valid && int(exception.ClinicID.Int64) == clinicID {
+			count++
+		}
+	}
+	return count
+}
+
+// vetClinicCandidates возвращает активные клиники, к которым врач еще не привязан, по названию
+func (h *AdminHandlers) vetClinicCandidates(vetID int) ([]*models.Clinic, error) {
+	linked, err := h.db.GetClinicsByVetID(vetID)
+	if err != nil {
+		return nil, err
+	}
+	linkedIDs := make(map[int]bool, len(linked))
+	for _, clinic := range linked {
+		linkedIDs[clinic.ID] = true
+	}
+
+	all, err := h.db.GetAllClinics()
+	if err != nil {
+		return nil, err
+	}
+	var clinics []*models.Clinic
+	for _, clinic := range all {
+		if clinic.IsActive && !linkedIDs[clinic.ID] {
+			clinics = append(clinics, clinic)
+		}
+	}
+	sort.Slice(clinics, func(i, j int) bool {
+		if clinics[i].Name != clinics[j].Name {
+			return clinics[i].Name < clinics[j].Name
+		}
+		return clinics[i].ID < clinics[j].ID
+	})
+	return clinics, nil
+}
+
+// parseListNumber возвращает индекс элемента списка длины count по номеру, введенному админом
+func parseListNumber(number string, count int, what string) (int, error) {
+	index, err := strconv.Atoi(strings.TrimSpace(number))
+	if err != nil || index < 1 || index > count {
+		return 0, fmt.Errorf("номер %s должен быть от 1 до %d", what, count)
+	}
+	return index - 1, nil
+}
+
+// backCancelKeyboard клавиатура для ввода значения с возвратом к списку или отменой
+func backCancelKeyboard() tgbotapi.ReplyKeyboardMarkup {
+	return tgbotapi.NewReplyKeyboard(
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton("🔙 Назад"),
+			tgbotapi.NewKeyboardButton("❌ Отмена"),
+		),
+	)
+}
@@ -4,13 +4,15 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/drerr0r/vetbot/internal/models"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
-// HandleVetDetails отображает детальную информацию о враче
-func (h *VetHandlers) HandleVetDetails(chatID int64, vetID int, messageID int) error {
+// HandleVetDetails отображает детальную информацию о враче. userID - кто открыл карточку:
+// в группах он не совпадает с chatID, а избранное у каждого пользователя свое
+func (h *VetHandlers) HandleVetDetails(chatID int64, userID int64, vetID int, messageID int) error {
 	InfoLog.Printf("HandleVetDetails called for vet ID: %d", vetID)
 
 	// Получаем полную информацию о враче
@@ -24,7 +26,7 @@ func (h *VetHandlers) HandleVetDetails(chatID int64, vetID int, messageID int) e
 	message := h.formatVeterinarianDetails(vet)
 
 	// Создаем клавиатуру с закрепленными кнопками
-	replyMarkup := h.createVetDetailsKeyboard(vetID)
+	replyMarkup := h.createVetDetailsKeyboard(vetID, h.isFavoriteVet(userID, vetID), h.hasSearchResults(userID))
 
 	// Если есть предыдущее сообщение, редактируем его
 	if messageID != 0 {
@@ -97,93 +99,95 @@ func (h *VetHandlers) formatVeterinarianDetails(vet *models.Veterinarian) string
 		message.WriteString("\n")
 	}
 
-	// Клиники и расписание - ИСПРАВЛЕННАЯ ЧАСТЬ
+	// Отпуска и другие часы приема учитываются в расписании по датам
+	now := time.Now()
+	exceptions, err := h.db.GetScheduleExceptionsByVetID(models.GetVetIDAsIntOrZero(vet), now)
+	if err != nil {
+		ErrorLog.Printf("Error getting schedule exceptions for vet %d: %v", models.GetVetIDAsIntOrZero(vet), err)
+		exceptions = nil
+	}
+	availability := &vetAvailability{
+		schedules:  vet.Schedules,
+		exceptions: exceptions,
+		location:   models.SchedulesLocation(vet.Schedules),
+	}
+
+	// Клиники и расписание по датам на две недели вперед
 	if len(vet.Schedules) > 0 {
 		message.WriteString("\n🏥 *Места приема и расписание:*\n")
 
 		// Группируем по клиникам
-		clinicSchedules := make(map[string][]*models.Schedule)
+		var clinics []*models.Clinic
+		seenClinics := make(map[int]bool)
 		for _, schedule := range vet.Schedules {
-			if schedule.Clinic != nil {
-				clinicName := schedule.Clinic.Name
-				clinicSchedules[clinicName] = append(clinicSchedules[clinicName], schedule)
+			if schedule.Clinic != nil && !seenClinics[schedule.ClinicID] {
+				seenClinics[schedule.ClinicID] = true
+				clinics = append(clinics, schedule.Clinic)
 			}
 		}
+		sort.SliceStable(clinics, func(i, j int) bool { return clinics[i].Name < clinics[j].Name })
 
-		for clinicName, schedules := range clinicSchedules {
-			message.WriteString(fmt.Sprintf("\n*%s*\n", clinicName))
+		for _, clinic := range clinics {
+			message.WriteString(fmt.Sprintf("\n*%s*\n", clinic.Name))
 
 			// Адрес и контакты клиники
-			if len(schedules) > 0 && schedules[0].Clinic != nil {
-				clinic := schedules[0].Clinic
-				message.WriteString(fmt.Sprintf("📍 *Адрес:* %s\n", clinic.Address))
-
-				// Информация о метро и районе
-				if clinic.MetroStation.Valid && clinic.MetroStation.String != "" {
-					message.WriteString(fmt.Sprintf("🚇 *Метро:* %s\n", clinic.MetroStation.String))
-				}
-				if clinic.District.Valid && clinic.District.String != "" {
-					message.WriteString(fmt.Sprintf("🏘️ *Район:* %s\n", clinic.District.String))
-				}
+			message.WriteString(fmt.Sprintf("📍 *Адрес:* %s\n", clinic.Address))
 
-				if clinic.Phone.Valid && clinic.Phone.String != "" {
-					message.WriteString(fmt.Sprintf("📞 *Телефон клиники:* %s\n", clinic.Phone.String))
-				}
-
-				if clinic.WorkingHours.Valid && clinic.WorkingHours.String != "" {
-					message.WriteString(fmt.Sprintf("🕐 *Часы работы:* %s\n", clinic.WorkingHours.String))
-				}
+			// Информация о метро и районе
+			if clinic.MetroStation.Valid && clinic.MetroStation.String != "" {
+				message.WriteString(fmt.Sprintf("🚇 *Метро:* %s\n", clinic.MetroStation.String))
 			}
-
-			// Расписание по дням
-			daysMap := make(map[int][]string)
-
-			// Собираем уникальные временные слоты для каждого дня
-			for _, schedule := range schedules {
-				timeSlot := fmt.Sprintf("%s-%s", schedule.StartTime, schedule.EndTime)
-
-				// Проверяем, нет ли уже такого временного слота для этого дня
-				found := false
-				for _, existingSlot := range daysMap[schedule.DayOfWeek] {
-					if existingSlot == timeSlot {
-						found = true
-						break
-					}
-				}
-
-				if !found {
-					daysMap[schedule.DayOfWeek] = append(daysMap[schedule.DayOfWeek], timeSlot)
-				}
+			if clinic.District.Valid && clinic.District.String != "" {
+				message.WriteString(fmt.Sprintf("🏘️ *Район:* %s\n", clinic.District.String))
 			}
 
-			if len(daysMap) > 0 {
-				message.WriteString("📅 *Расписание приема:*\n")
-				var scheduleParts []string
-
-				// Сортируем дни недели по порядку
-				for day := 1; day <= 7; day++ {
-					if timeSlots, exists := daysMap[day]; exists && len(timeSlots) > 0 {
-						dayName := getDayName(day)
+			if clinic.Phone.Valid && clinic.Phone.String != "" {
+				message.WriteString(fmt.Sprintf("📞 *Телефон клиники:* %s\n", clinic.Phone.String))
+			}
 
-						// Сортируем временные слоты для красивого отображения
-						sort.Strings(timeSlots)
+			if clinic.WorkingHours.Valid && clinic.WorkingHours.String != "" {
+				message.WriteString(fmt.Sprintf("🕐 *Часы работы:* %s\n", clinic.WorkingHours.String))
+			}
 
-						scheduleParts = append(scheduleParts, fmt.Sprintf("   • %s: %s", dayName, strings.Join(timeSlots, ", ")))
-					}
+			// Ближайшие приемы по датам
+			lines := upcomingScheduleLines(availability.inClinic(clinic.ID), now, scheduleViewDays, false)
+			if len(lines) > 0 {
+				message.WriteString("📅 *Ближайшие приемы:*\n")
+				for _, line := range lines {
+					message.WriteString(fmt.Sprintf("   • %s\n", line))
 				}
-				message.WriteString(strings.Join(scheduleParts, "\n"))
+			} else {
+				message.WriteString(fmt.Sprintf("📅 В ближайшие %d дней приема нет\n", scheduleViewDays))
 			}
-			message.WriteString("\n")
 		}
 	} else {
 		message.WriteString("\n📅 *Расписание:* не указано\n")
 	}
 
+	// Отпуск и другие часы приема на ближайшие даты
+	if len(exceptions) > 0 {
+		message.WriteString("\n📌 *Изменения в расписании:*\n")
+		for _, exception := range exceptions {
+			message.WriteString(fmt.Sprintf("   • %s\n", formatScheduleException(exception, true)))
+		}
+	}
+
 	return message.String()
 }
 
-// createVetDetailsKeyboard создает клавиатуру для детального просмотра врача
-func (h *VetHandlers) createVetDetailsKeyboard(vetID int) tgbotapi.InlineKeyboardMarkup {
+// createVetDetailsKeyboard создает клавиатуру для детального просмотра врача.
+// backToResults - карточка открыта из результатов поиска, "назад" возвращает на ту же страницу
+func (h *VetHandlers) createVetDetailsKeyboard(vetID int, isFavorite bool, backToResults bool) tgbotapi.InlineKeyboardMarkup {
+	favoriteButton := tgbotapi.NewInlineKeyboardButtonData("⭐ В избранное", fmt.Sprintf("favorite_%d", vetID))
+	if isFavorite {
+		favoriteButton = tgbotapi.NewInlineKeyboardButtonData("💔 Убрать из избранного", fmt.Sprintf("unfavorite_%d", vetID))
+	}
+
+	searchButton := tgbotapi.NewInlineKeyboardButtonData("🔍 Поиск врачей", "main_menu")
+	if backToResults {
+		searchButton = tgbotapi.NewInlineKeyboardButtonData("🔙 К результатам", "res_back")
+	}
+
 	return tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("📝 Отзывы", fmt.Sprintf("show_reviews_%d", vetID)),
@@ -191,10 +195,10 @@ func (h *VetHandlers) createVetDetailsKeyboard(vetID int) tgbotapi.InlineKeyboar
 		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("📅 Записаться", fmt.Sprintf("appointment_%d", vetID)),
-			tgbotapi.NewInlineKeyboardButtonData("⭐ В избранное", fmt.Sprintf("favorite_%d", vetID)),
+			favoriteButton,
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("🔍 Поиск врачей", "main_menu"),
+			searchButton,
 			tgbotapi.NewInlineKeyboardButtonData("🏠 Главное меню", "main_menu"),
 		),
 	)
@@ -46,8 +46,9 @@ func (h *VetHandlers) HandleStart(update tgbotapi.Update) {
 		ErrorLog.Printf("Error creating user: %v", err)
 	}
 
-	// Очищаем историю навигации
+	// Очищаем историю навигации и курсор прошлого поиска
 	h.stateManager.ClearHistory(update.Message.From.ID)
+	h.clearSearchResults(update.Message.From.ID)
 
 	msg := tgbotapi.NewMessage(update.Message.Chat.ID,
 		`🐾 Добро пожаловать в VetBot! 🐾
@@ -190,6 +191,12 @@ func (h *VetHandlers) HandleSearch(update tgbotapi.Update) {
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("Воскресенье", "search_day_7"),
 			tgbotapi.NewInlineKeyboardButtonData("Любой день", "search_day_0"),
+			tgbotapi.NewInlineKeyboardButtonData("📅 По дате", "search_cal"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🟢 Сейчас работают", "search_now"),
+			tgbotapi.NewInlineKeyboardButtonData("🕐 Сегодня в выбранный час",
+				fmt.Sprintf("search_hours_%d", weekdayNumber(time.Now().In(models.DefaultLocation())))),
 		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("🔙 Назад", "main_menu"),
@@ -197,7 +204,8 @@ func (h *VetHandlers) HandleSearch(update tgbotapi.Update) {
 	)
 
 	msg := tgbotapi.NewMessage(chatID,
-		"🕐 *Выберите день недели для поиска:*\n\nЯ покажу врачей, работающих в выбранный день.")
+		"🕐 *Выберите день недели для поиска:*\n\nЯ покажу врачей, работающих в выбранный день. "+
+			"Можно также выбрать конкретную дату в календаре или найти тех, кто принимает прямо сейчас или в нужный час.")
 	msg.ParseMode = "Markdown"
 	msg.ReplyMarkup = keyboard
 
@@ -400,18 +408,31 @@ func (h *VetHandlers) HandleHelp(update tgbotapi.Update) {
 
 *Основные функции:*
 • 🔍 *Поиск по специализациям* - найти врача по направлению
-• 🕐 *Поиск по времени* - найти врача по дню недели
+• 🕐 *Поиск по времени* - найти врача по дню недели, дате в календаре, часу или тех, кто работает сейчас
 • 🏥 *Поиск по клиникам* - найти врачей в конкретной клинике
 • 🏙️ *Поиск по городу* - найти врачей в определенном городе
+• 📍 *Рядом со мной* - ближайшие клиники по вашей геопозиции
+• 🧭 *Подбор по фильтрам* - специализация, город, день и район по шагам, любой шаг можно пропустить
+• 🔎 *Поиск по фамилии* - просто напишите фамилию или имя врача, например «Петрова» или «Petrova»
 
 *Как пользоваться:*
 1. Выберите способ поиска из главного меню
 2. Нажмите на нужную кнопку (специализация, день, клиника или город)
 3. Бот покажет список врачей с контактами и расписанием
 
+*Запись на прием:*
+Откройте карточку врача и нажмите «📅 Записаться», выберите дату и время.
+
+*Избранное:*
+Нажмите «⭐ В избранное» в карточке врача, чтобы быстро найти его позже.
+
 *Команды:*
 /start - Главное меню
 /cities - Поиск по городам
+/appointments - Мои записи на прием
+/favorites - Избранные врачи
+/nearest - Клиники рядом со мной
+/filters - Подбор врача по нескольким фильтрам
 /help - Эта справка`
 
 	msg := tgbotapi.NewMessage(chatID, helpText)
@@ -440,163 +461,60 @@ func (h *VetHandlers) createPersistentKeyboard() tgbotapi.ReplyKeyboardMarkup {
 			tgbotapi.NewKeyboardButton("ℹ️ Помощь"),
 			tgbotapi.NewKeyboardButton("🔙 Назад"),
 		),
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton("⭐ Избранное"),
+			tgbotapi.NewKeyboardButton("📋 Мои записи"),
+			tgbotapi.NewKeyboardButtonLocation(nearestButtonText),
+		),
 	)
 }
 
-// HandleSearchBySpecialization ищет врачей по специализации с кнопками отзывов
+// HandleSearchBySpecialization ищет врачей по специализации и показывает результаты постранично
 func (h *VetHandlers) HandleSearchBySpecialization(update tgbotapi.Update, specializationID int) {
 	InfoLog.Printf("HandleSearchBySpecialization called with ID: %d", specializationID)
 
 	var chatID int64
+	var userID int64
 
 	// Определяем chatID в зависимости от типа update
 	if update.CallbackQuery != nil {
 		chatID = update.CallbackQuery.Message.Chat.ID
+		userID = update.CallbackQuery.From.ID
 		// Отвечаем на callback query чтобы убрать "часики" у кнопки
 		callback := tgbotapi.NewCallback(update.CallbackQuery.ID, "")
 		h.bot.Send(callback)
 	} else if update.Message != nil {
 		chatID = update.Message.Chat.ID
+		userID = update.Message.From.ID
 	} else {
 		ErrorLog.Printf("Error: both CallbackQuery and Message are nil")
 		return
 	}
 
-	vets, err := h.db.GetVeterinariansBySpecialization(specializationID)
-	if err != nil {
-		ErrorLog.Printf("Error getting veterinarians: %v", err)
-		msg := tgbotapi.NewMessage(chatID, "Ошибка при поиске врачей")
-		h.bot.Send(msg)
-		return
-	}
-
-	InfoLog.Printf("Found %d veterinarians for specialization ID: %d", len(vets), specializationID)
-
+	specName := "выбранной специализации"
 	spec, err := h.db.GetSpecializationByID(specializationID)
 	if err != nil {
 		ErrorLog.Printf("Error getting specialization: %v", err)
-	}
-
-	// Создаем клавиатуру с кнопкой "Назад"
-	keyboard := tgbotapi.NewInlineKeyboardMarkup(
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("🔙 К специализациям", "main_specializations"),
-			tgbotapi.NewInlineKeyboardButtonData("🏠 Главное меню", "main_menu"),
-		),
-	)
-
-	if len(vets) == 0 {
-		var specName string
-		if spec != nil {
-			specName = spec.Name
-		} else {
-			specName = "выбранной специализации"
-		}
-
-		msg := tgbotapi.NewMessage(chatID,
-			fmt.Sprintf("👨‍⚕️ *Врачи по специализации \"%s\" не найдены*\n\nПопробуйте выбрать другую специализацию.", specName))
-		msg.ParseMode = "Markdown"
-		msg.ReplyMarkup = keyboard
-		h.bot.Send(msg)
-		return
-	}
-
-	// Отправляем заголовок
-	var specName string
-	if spec != nil {
+	} else if spec != nil {
 		specName = spec.Name
-	} else {
-		specName = "специализации"
 	}
 
-	// Если врачей больше 5, используем компактный формат с разбивкой по сообщениям
-	if len(vets) > 5 {
-		headerMsg := tgbotapi.NewMessage(chatID,
-			fmt.Sprintf("👨‍⚕️ *Врачи по специализации \"%s\":*\n\nНайдено врачей: %d\n\n*Показаны в компактном формате. Нажмите \"Подробнее\" для полной информации:*", specName, len(vets)))
-		headerMsg.ParseMode = "Markdown"
-		h.bot.Send(headerMsg)
-
-		// Используем компактный формат с кнопкой "Подробнее"
-		for i, vet := range vets {
-			err := h.sendVetWithDetailsButton(chatID, vet, i+1)
-			if err != nil {
-				ErrorLog.Printf("Error sending vet info: %v", err)
-			}
-		}
-	} else {
-		// Если врачей мало, показываем детальную информацию с кнопками отзывов
-		headerMsg := tgbotapi.NewMessage(chatID,
-			fmt.Sprintf("👨‍⚕️ *Врачи по специализации \"%s\":*\n\nНайдено врачей: %d\n\nВыберите врача для просмотра отзывов:", specName, len(vets)))
-		headerMsg.ParseMode = "Markdown"
-		h.bot.Send(headerMsg)
-
-		// Отправляем каждого врача с детальной информацией и кнопками отзывов
-		for i, vet := range vets {
-			err := h.sendVetWithSpecializationDetailsAndReviews(chatID, vet, i+1)
-			if err != nil {
-				ErrorLog.Printf("Error sending vet info: %v", err)
-			}
-		}
-	}
-}
-
-// sendVetWithSpecializationDetailsAndReviews отправляет врача с детальной информацией и кнопками отзывов для специализаций
-func (h *VetHandlers) sendVetWithSpecializationDetailsAndReviews(chatID int64, vet *models.Veterinarian, index int) error {
-	var sb strings.Builder
-
-	sb.WriteString(fmt.Sprintf("**%d. %s %s**\n", index, html.EscapeString(vet.FirstName), html.EscapeString(vet.LastName)))
-	sb.WriteString(fmt.Sprintf("📞 *Телефон:* `%s`\n", html.EscapeString(vet.Phone)))
-
-	if vet.Email.Valid && vet.Email.String != "" {
-		sb.WriteString(fmt.Sprintf("📧 *Email:* %s\n", html.EscapeString(vet.Email.String)))
-	}
-
-	if vet.ExperienceYears.Valid {
-		sb.WriteString(fmt.Sprintf("💼 *Опыт:* %d лет\n", vet.ExperienceYears.Int64))
-	}
-
-	// Информация о городе
-	if vet.City != nil {
-		sb.WriteString(fmt.Sprintf("🏙️ *Город:* %s\n", vet.City.Name))
+	results := &searchResults{
+		Source:    resultsSourceCriteria,
+		Criteria:  models.SearchCriteria{SpecializationID: specializationID},
+		Title:     fmt.Sprintf("👨‍⚕️ *Врачи по специализации \"%s\":*", specName),
+		EmptyText: fmt.Sprintf("👨‍⚕️ *Врачи по специализации \"%s\" не найдены*\n\nПопробуйте выбрать другую специализацию.", specName),
+		Buttons: [][]resultsButton{{
+			{Text: "🔙 К специализациям", Data: "main_specializations"},
+			{Text: "🏠 Главное меню", Data: "main_menu"},
+		}},
 	}
 
-	// Расписание врача (первые 3 дня)
-	schedules, err := h.db.GetSchedulesByVetID(models.GetVetIDAsIntOrZero(vet))
-	if err == nil && len(schedules) > 0 {
-		sb.WriteString("🕐 *Ближайшее расписание:*\n")
-		displayed := 0
-		for _, schedule := range schedules {
-			if displayed >= 3 { // Показываем только 3 ближайших записи
-				break
-			}
-			scheduleDayName := getDayName(schedule.DayOfWeek)
-			startTime := schedule.StartTime
-			endTime := schedule.EndTime
-			if startTime != "" && endTime != "" && startTime != "00:00" && endTime != "00:00" {
-				sb.WriteString(fmt.Sprintf("• %s: %s-%s", scheduleDayName, startTime, endTime))
-				if schedule.Clinic != nil && schedule.Clinic.Name != "" {
-					sb.WriteString(fmt.Sprintf(" (%s)", html.EscapeString(schedule.Clinic.Name)))
-				}
-				sb.WriteString("\n")
-				displayed++
-			}
-		}
+	if err := h.startSearchResults(chatID, userID, results); err != nil {
+		ErrorLog.Printf("Error getting veterinarians: %v", err)
+		msg := tgbotapi.NewMessage(chatID, "Ошибка при поиске врачей")
+		h.bot.Send(msg)
 	}
-
-	// Создаем клавиатуру с кнопками отзывов
-	keyboard := tgbotapi.NewInlineKeyboardMarkup(
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("⭐ Отзывы", fmt.Sprintf("show_reviews_%d", models.GetVetIDAsIntOrZero(vet))),
-			tgbotapi.NewInlineKeyboardButtonData("📝 Добавить отзыв", fmt.Sprintf("add_review_%d", models.GetVetIDAsIntOrZero(vet))),
-		),
-	)
-
-	msg := tgbotapi.NewMessage(chatID, sb.String())
-	msg.ParseMode = "Markdown"
-	msg.ReplyMarkup = keyboard
-
-	_, err = h.bot.Send(msg)
-	return err
 }
 
 // HandleSearchByClinic ищет врачей по клинике
@@ -629,7 +547,7 @@ func (h *VetHandlers) HandleSearchByClinic(update tgbotapi.Update, clinicID int)
 	criteria := &models.SearchCriteria{
 		ClinicID: clinicID,
 	}
-	vets, err := h.db.FindAvailableVets(criteria)
+	vets, err := h.db.SearchVets(criteria)
 	if err != nil {
 		ErrorLog.Printf("Error finding vets by clinic: %v", err)
 		msg := tgbotapi.NewMessage(chatID, "Ошибка при поиске врачей")
@@ -747,20 +665,11 @@ func (h *VetHandlers) sendCompactVetInfo(chatID int64, vet *models.Veterinarian,
 		sb.WriteString(fmt.Sprintf(" ⭐ %.1f/5", stats.AverageRating))
 	}
 
-	// Используем clinicID для показа расписания в этой клинике
-	schedules, err := h.db.GetSchedulesByVetID(models.GetVetIDAsIntOrZero(vet))
-	if err == nil {
-		// Ищем ближайший рабочий день в этой клинике
-		for _, schedule := range schedules {
-			if schedule.ClinicID == clinicID && schedule.IsAvailable {
-				scheduleDayName := getDayName(schedule.DayOfWeek)
-				startTime := schedule.StartTime
-				endTime := schedule.EndTime
-				if startTime != "" && endTime != "" && startTime != "00:00" && endTime != "00:00" {
-					sb.WriteString(fmt.Sprintf(" 🕐 %s %s-%s", scheduleDayName, startTime, endTime))
-					break // Показываем только первый найденный день
-				}
-			}
+	// Ближайший прием в этой клинике с учетом исключений
+	now := time.Now()
+	if availability, err := h.loadVetAvailability(models.GetVetIDAsIntOrZero(vet), now); err == nil {
+		if schedule, date := h.findNearestWorkingDay(availability.inClinic(clinicID), now); schedule != nil {
+			sb.WriteString(fmt.Sprintf(" 🕐 %s %s-%s", formatScheduleDate(date, now), schedule.StartTime, schedule.EndTime))
 		}
 	}
 
@@ -880,27 +789,19 @@ func (h *VetHandlers) formatVeterinarianDetailsForClinic(vet *models.Veterinaria
 	// Расписание в конкретной клинике
 	message.WriteString(fmt.Sprintf("\n🏥 *Расписание в клинике \"%s\":*\n", clinic.Name))
 
+	// Приемы по датам на две недели вперед с учетом отпусков и других часов
 	hasSchedule := false
-	schedules, err := h.db.GetSchedulesByVetID(models.GetVetIDAsIntOrZero(vet))
+	now := time.Now()
+	availability, err := h.loadVetAvailability(models.GetVetIDAsIntOrZero(vet), now)
 	if err == nil {
-		// Группируем по дням для этой клиники
-		daysMap := make(map[int][]string)
-		for _, schedule := range schedules {
-			if schedule.ClinicID == clinic.ID {
-				timeSlot := fmt.Sprintf("%s-%s", schedule.StartTime, schedule.EndTime)
-				daysMap[schedule.DayOfWeek] = append(daysMap[schedule.DayOfWeek], timeSlot)
-			}
+		availability = availability.inClinic(clinic.ID)
+		lines := upcomingScheduleLines(availability, now, scheduleViewDays, false)
+		hasSchedule = len(availability.schedules) > 0 || len(lines) > 0
+		for _, line := range lines {
+			message.WriteString(fmt.Sprintf("• %s\n", line))
 		}
-
-		if len(daysMap) > 0 {
-			hasSchedule = true
-			// Сортируем дни недели по порядку
-			for day := 1; day <= 7; day++ {
-				if timeSlots, exists := daysMap[day]; exists && len(timeSlots) > 0 {
-					dayName := getDayName(day)
-					message.WriteString(fmt.Sprintf("• %s: %s\n", dayName, strings.Join(timeSlots, ", ")))
-				}
-			}
+		if hasSchedule && len(lines) == 0 {
+			message.WriteString(fmt.Sprintf("📅 В ближайшие %d дней приема нет\n", scheduleViewDays))
 		}
 	}
 
@@ -1027,12 +928,27 @@ func (h *VetHandlers) HandleCallback(update tgbotapi.Update) {
 	case strings.HasPrefix(data, "search_day_"):
 		h.stateManager.PushState(callback.From.ID, "main_time")
 		h.handleDaySelection(callback)
+	case data == "search_now":
+		h.stateManager.PushState(callback.From.ID, "main_time")
+		h.handleSearchNowCallback(callback)
+	case strings.HasPrefix(data, "search_hours_"):
+		h.handleHourPickerCallback(callback)
+	case strings.HasPrefix(data, "search_at_"):
+		h.stateManager.PushState(callback.From.ID, "main_time")
+		h.handleHourSelection(callback)
+	case strings.HasPrefix(data, "search_cal"):
+		h.handleDatePickerCallback(callback)
+	case strings.HasPrefix(data, "search_date_"):
+		h.stateManager.PushState(callback.From.ID, "main_time")
+		h.handleDateSelection(callback)
 	case strings.HasPrefix(data, "search_clinic_"):
 		h.stateManager.PushState(callback.From.ID, "main_clinics")
 		h.handleSearchClinicCallback(callback)
 	case strings.HasPrefix(data, "search_city_"):
 		h.stateManager.PushState(callback.From.ID, "main_city")
 		h.handleSearchCityCallback(callback)
+	case strings.HasPrefix(data, "res_"):
+		h.handleSearchResultsCallback(callback)
 	case strings.HasPrefix(data, "vet_details_"):
 		h.handleVetDetailsCallback(callback)
 	case strings.HasPrefix(data, "show_reviews_"):
@@ -1047,6 +963,36 @@ func (h *VetHandlers) HandleCallback(update tgbotapi.Update) {
 		h.handleVetDetailsFromClinicCallback(callback)
 	case strings.HasPrefix(data, "add_clinic_review_"):
 		h.handleAddClinicReviewCallback(callback)
+	case strings.HasPrefix(data, "appointment_"):
+		h.handleAppointmentCallback(callback)
+	case strings.HasPrefix(data, "appt_date_"):
+		h.handleAppointmentDateCallback(callback)
+	case strings.HasPrefix(data, "appt_slot_"):
+		h.handleAppointmentSlotCallback(callback)
+	case strings.HasPrefix(data, "appt_confirm_"):
+		h.handleAppointmentConfirmCallback(callback)
+	case strings.HasPrefix(data, "appt_cancel_"):
+		h.handleAppointmentCancelCallback(callback)
+	case strings.HasPrefix(data, "appt_cancelok_"):
+		h.handleAppointmentCancelConfirmCallback(callback)
+	case data == "appt_list":
+		h.HandleMyAppointments(update)
+	case strings.HasPrefix(data, "favorite_"):
+		h.handleFavoriteToggleCallback(callback, true)
+	case strings.HasPrefix(data, "unfavorite_"):
+		h.handleFavoriteToggleCallback(callback, false)
+	case strings.HasPrefix(data, "fav_list_remove_"):
+		h.handleFavoriteListRemoveCallback(callback)
+	case data == "main_favorites":
+		h.stateManager.PushState(callback.From.ID, "main_menu")
+		h.HandleFavorites(update)
+	case data == "main_nearest":
+		h.HandleNearestPrompt(update)
+	case data == "main_wizard":
+		h.stateManager.PushState(callback.From.ID, "main_menu")
+		h.HandleSearchWizard(update)
+	case strings.HasPrefix(data, "wiz_"):
+		h.handleSearchWizardCallback(callback)
 	default:
 		// Неизвестный callback
 		callbackConfig := tgbotapi.NewCallback(callback.ID, "Неизвестная команда")
@@ -1139,7 +1085,7 @@ func (h *VetHandlers) handleVetDetailsCallback(callback *tgbotapi.CallbackQuery)
 
 	InfoLog.Printf("Showing details for vet ID: %d", vetID)
 
-	err = h.HandleVetDetails(callback.Message.Chat.ID, vetID, callback.Message.MessageID)
+	err = h.HandleVetDetails(callback.Message.Chat.ID, callback.From.ID, vetID, callback.Message.MessageID)
 	if err != nil {
 		ErrorLog.Printf("Error showing vet details: %v", err)
 		callbackConfig := tgbotapi.NewCallback(callback.ID, "Ошибка при загрузке данных")
@@ -1153,6 +1099,8 @@ func (h *VetHandlers) handleVetDetailsCallback(callback *tgbotapi.CallbackQuery)
 
 // showMainMenu показывает главное меню
 func (h *VetHandlers) showMainMenu(callback *tgbotapi.CallbackQuery) {
+	h.clearSearchResults(callback.From.ID)
+
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("🔍 Поиск по специализациям", "main_specializations"),
@@ -1163,8 +1111,13 @@ func (h *VetHandlers) showMainMenu(callback *tgbotapi.CallbackQuery) {
 			tgbotapi.NewInlineKeyboardButtonData("🏙️ Поиск по городу", "main_city"),
 		),
 		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("⭐ Избранное", "main_favorites"),
 			tgbotapi.NewInlineKeyboardButtonData("ℹ️ Помощь", "main_help"),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(nearestButtonText, "main_nearest"),
+			tgbotapi.NewInlineKeyboardButtonData("🧭 Подбор по фильтрам", "main_wizard"),
+		),
 	)
 
 	editMsg := tgbotapi.NewEditMessageText(callback.Message.Chat.ID, callback.Message.MessageID,
@@ -1241,20 +1194,6 @@ func (h *VetHandlers) handleSearchCityCallback(callback *tgbotapi.CallbackQuery)
 
 	InfoLog.Printf("Searching for city ID: %d", cityID)
 
-	criteria := &models.SearchCriteria{
-		CityID: cityID,
-	}
-
-	vets, err := h.db.FindVetsByCity(criteria)
-	if err != nil {
-		ErrorLog.Printf("Error finding vets by city: %v", err)
-		callbackConfig := tgbotapi.NewCallback(callback.ID, "Ошибка при поиске врачей")
-		h.bot.Request(callbackConfig)
-		return
-	}
-
-	InfoLog.Printf("Found %d vets for city %d", len(vets), cityID)
-
 	// Получаем информацию о городе
 	city, err := h.db.GetCityByID(cityID)
 	if err != nil {
@@ -1262,91 +1201,28 @@ func (h *VetHandlers) handleSearchCityCallback(callback *tgbotapi.CallbackQuery)
 		city = &models.City{Name: "Неизвестный город"}
 	}
 
-	// Клавиатура с кнопками навигации
-	keyboard := tgbotapi.NewInlineKeyboardMarkup(
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("🔙 К городам", "main_city"),
-			tgbotapi.NewInlineKeyboardButtonData("🏠 Главное меню", "main_menu"),
-		),
-	)
+	results := &searchResults{
+		Source:    resultsSourceCriteria,
+		Criteria:  models.SearchCriteria{CityID: cityID},
+		Title:     fmt.Sprintf("🏙️ *Врачи в городе \"%s\":*", city.Name),
+		EmptyText: fmt.Sprintf("🏙️ *Врачи в городе \"%s\" не найдены*\n\nПопробуйте выбрать другой город.", city.Name),
+		Buttons: [][]resultsButton{{
+			{Text: "🔙 К городам", Data: "main_city"},
+			{Text: "🏠 Главное меню", Data: "main_menu"},
+		}},
+	}
 
-	if len(vets) == 0 {
-		msg := tgbotapi.NewMessage(callback.Message.Chat.ID,
-			fmt.Sprintf("🏙️ *Врачи в городе \"%s\" не найдены*\n\nПопробуйте выбрать другой город.", city.Name))
-		msg.ParseMode = "Markdown"
-		msg.ReplyMarkup = keyboard
-		h.bot.Send(msg)
-		callbackConfig := tgbotapi.NewCallback(callback.ID, "Поиск завершен")
+	if err := h.startSearchResults(callback.Message.Chat.ID, callback.From.ID, results); err != nil {
+		ErrorLog.Printf("Error finding vets by city: %v", err)
+		callbackConfig := tgbotapi.NewCallback(callback.ID, "Ошибка при поиске врачей")
 		h.bot.Request(callbackConfig)
 		return
 	}
 
-	// Отправляем заголовок
-	msg := tgbotapi.NewMessage(callback.Message.Chat.ID,
-		fmt.Sprintf("🏙️ *Врачи в городе \"%s\":*\n\nНайдено врачей: %d\n\nВыберите врача для просмотра отзывов:", city.Name, len(vets)))
-	msg.ParseMode = "Markdown"
-	h.bot.Send(msg)
-
-	// Отправляем каждого врача с детальной информацией и кнопками отзывов
-	for i, vet := range vets {
-		err := h.sendVetWithDetailsAndReviewsButtons(callback.Message.Chat.ID, vet, i+1)
-		if err != nil {
-			ErrorLog.Printf("Error sending vet info: %v", err)
-		}
-	}
-
 	callbackConfig := tgbotapi.NewCallback(callback.ID, "Поиск завершен")
 	h.bot.Request(callbackConfig)
 }
 
-// sendVetWithDetailsAndReviewsButtons отправляет врача с детальной информацией и кнопками отзывов для городов
-func (h *VetHandlers) sendVetWithDetailsAndReviewsButtons(chatID int64, vet *models.Veterinarian, index int) error {
-	var sb strings.Builder
-
-	sb.WriteString(fmt.Sprintf("**%d. %s %s**\n", index, html.EscapeString(vet.FirstName), html.EscapeString(vet.LastName)))
-	sb.WriteString(fmt.Sprintf("📞 *Телефон:* `%s`\n", html.EscapeString(vet.Phone)))
-
-	if vet.Email.Valid && vet.Email.String != "" {
-		sb.WriteString(fmt.Sprintf("📧 *Email:* %s\n", html.EscapeString(vet.Email.String)))
-	}
-
-	if vet.ExperienceYears.Valid {
-		sb.WriteString(fmt.Sprintf("💼 *Опыт:* %d лет\n", vet.ExperienceYears.Int64))
-	}
-
-	// Специализации врача
-	specs, err := h.db.GetSpecializationsByVetID(models.GetVetIDAsIntOrZero(vet))
-	if err == nil && len(specs) > 0 {
-		sb.WriteString("🎯 *Специализации:* ")
-		specNames := make([]string, len(specs))
-		for j, spec := range specs {
-			specNames[j] = html.EscapeString(spec.Name)
-		}
-		sb.WriteString(strings.Join(specNames, ", "))
-		sb.WriteString("\n")
-	}
-
-	// Информация о городе
-	if vet.City != nil {
-		sb.WriteString(fmt.Sprintf("🏙️ *Город:* %s\n", vet.City.Name))
-	}
-
-	// Создаем клавиатуру с кнопками отзывов
-	keyboard := tgbotapi.NewInlineKeyboardMarkup(
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("⭐ Отзывы", fmt.Sprintf("show_reviews_%d", models.GetVetIDAsIntOrZero(vet))),
-			tgbotapi.NewInlineKeyboardButtonData("📝 Добавить отзыв", fmt.Sprintf("add_review_%d", models.GetVetIDAsIntOrZero(vet))),
-		),
-	)
-
-	msg := tgbotapi.NewMessage(chatID, sb.String())
-	msg.ParseMode = "Markdown"
-	msg.ReplyMarkup = keyboard
-
-	_, err = h.bot.Send(msg)
-	return err
-}
-
 // handleDaySelection обрабатывает выбор дня для поиска
 func (h *VetHandlers) handleDaySelection(callback *tgbotapi.CallbackQuery) {
 	InfoLog.Printf("handleDaySelection called")
@@ -1360,207 +1236,244 @@ func (h *VetHandlers) handleDaySelection(callback *tgbotapi.CallbackQuery) {
 	}
 
 	InfoLog.Printf("Searching for day: %d", day)
+	h.sendTimeSearchResults(callback, models.SearchCriteria{DayOfWeek: day}, fmt.Sprintf("Врачи, работающие в %s", getDayName(day)))
+}
 
-	var vets []*models.Veterinarian
+// handleSearchNowCallback ищет врачей, у которых прием идет прямо сейчас.
+// "Сейчас" у каждой клиники свое - по часовому поясу ее города
+func (h *VetHandlers) handleSearchNowCallback(callback *tgbotapi.CallbackQuery) {
+	InfoLog.Printf("Searching for vets working now (%s UTC)", time.Now().UTC().Format("15:04"))
+	h.sendTimeSearchResults(callback, models.SearchCriteria{OpenNow: true}, "Сейчас работают (по местному времени)")
+}
 
-	if day == 0 {
-		// Для "Любой день" получаем всех активных врачей
-		InfoLog.Printf("Getting all vets for 'any day'")
-		vets, err = h.db.GetAllActiveVeterinarians()
-	} else {
-		// Для конкретного дня получаем врачей работающих в этот день
-		criteria := &models.SearchCriteria{
-			DayOfWeek: day,
+// handleHourPickerCallback показывает выбор часа для поиска (callback search_hours_<день>, 0 - любой день)
+func (h *VetHandlers) handleHourPickerCallback(callback *tgbotapi.CallbackQuery) {
+	day, err := strconv.Atoi(strings.TrimPrefix(callback.Data, "search_hours_"))
+	if err != nil || day < 0 || day > 7 {
+		ErrorLog.Printf("Invalid search_hours callback data: %s", callback.Data)
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, "Ошибка обработки запроса"))
+		return
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for hour := 0; hour < 24; hour++ {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%02d:00", hour),
+			fmt.Sprintf("search_at_%d_%d", day, hour)))
+		if len(row) == 6 {
+			rows = append(rows, row)
+			row = nil
 		}
-		vets, err = h.db.FindAvailableVets(criteria)
 	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("🔙 К дням недели", "main_time"),
+		tgbotapi.NewInlineKeyboardButtonData("🏠 Главное меню", "main_menu"),
+	))
 
-	if err != nil {
-		ErrorLog.Printf("Error finding vets: %v", err)
-		callbackConfig := tgbotapi.NewCallback(callback.ID, "Ошибка при поиске врачей")
-		h.bot.Request(callbackConfig)
+	msg := tgbotapi.NewMessage(callback.Message.Chat.ID,
+		fmt.Sprintf("🕐 *Выберите час* (%s):\n\nЯ покажу врачей, у которых в это время идет прием.", getDayName(day)))
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	h.bot.Send(msg)
+
+	h.bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+}
+
+// handleHourSelection ищет врачей, принимающих в выбранный час (callback search_at_<день>_<час>)
+func (h *VetHandlers) handleHourSelection(callback *tgbotapi.CallbackQuery) {
+	parts := strings.Split(strings.TrimPrefix(callback.Data, "search_at_"), "_")
+	if len(parts) != 2 {
+		ErrorLog.Printf("Invalid search_at callback data: %s", callback.Data)
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, "Ошибка обработки запроса"))
 		return
 	}
 
-	InfoLog.Printf("Found %d vets for day %d", len(vets), day)
+	day, errDay := strconv.Atoi(parts[0])
+	hour, errHour := strconv.Atoi(parts[1])
+	if errDay != nil || errHour != nil || day < 0 || day > 7 || hour < 0 || hour > 23 {
+		ErrorLog.Printf("Invalid search_at callback data: %s", callback.Data)
+		h.bot.Request(tgbotapi.NewCallback(callback.ID, "Ошибка обработки запроса"))
+		return
+	}
 
-	// Клавиатура с кнопками навигации
-	keyboard := tgbotapi.NewInlineKeyboardMarkup(
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("🔙 К дням недели", "main_time"),
-			tgbotapi.NewInlineKeyboardButtonData("🏠 Главное меню", "main_menu"),
-		),
-	)
+	clock := fmt.Sprintf("%02d:00", hour)
+	InfoLog.Printf("Searching for day %d at %s", day, clock)
+	h.sendTimeSearchResults(callback, models.SearchCriteria{DayOfWeek: day, Time: clock}, fmt.Sprintf("Врачи, принимающие в %s в %s", getDayName(day), clock))
+}
 
-	if len(vets) == 0 {
-		dayName := getDayName(day)
-		msg := tgbotapi.NewMessage(callback.Message.Chat.ID,
-			fmt.Sprintf("🕐 *Врачи, работающие в %s, не найдены*\n\nПопробуйте выбрать другой день.", dayName))
-		msg.ParseMode = "Markdown"
-		msg.ReplyMarkup = keyboard
-		h.bot.Send(msg)
-		callbackConfig := tgbotapi.NewCallback(callback.ID, "Поиск завершен")
-		h.bot.Request(callbackConfig)
-		return
+// sendTimeSearchResults ищет врачей по дню (0 - любой) и времени (пусто - весь день) или идущему
+// сейчас приему и показывает результаты постранично
+func (h *VetHandlers) sendTimeSearchResults(callback *tgbotapi.CallbackQuery, criteria models.SearchCriteria, title string) {
+	refineText := "🕐 Уточнить время"
+	hint := "Попробуйте выбрать другой день."
+	if criteria.Time != "" || criteria.OpenNow {
+		refineText = "🕐 Другое время"
+		hint = "Попробуйте выбрать другое время или день."
 	}
 
-	// Отправляем заголовок
-	dayName := getDayName(day)
-	headerMsg := tgbotapi.NewMessage(callback.Message.Chat.ID,
-		fmt.Sprintf("🕐 *Врачи, работающие в %s:*\n\nНайдено врачей: %d\n\n", dayName, len(vets)))
-	headerMsg.ParseMode = "Markdown"
-	h.bot.Send(headerMsg)
+	day := criteria.DayOfWeek
+	if criteria.OpenNow {
+		day = weekdayNumber(time.Now().In(models.DefaultLocation()))
+	}
 
-	// Отправляем каждого врача в компактном формате
-	for i, vet := range vets {
-		err := h.sendVetWithDayDetailsAndReviews(callback.Message.Chat.ID, vet, i+1, day)
-		if err != nil {
-			ErrorLog.Printf("Error sending vet info: %v", err)
-		}
+	results := &searchResults{
+		Source:    resultsSourceCriteria,
+		Criteria:  criteria,
+		Title:     fmt.Sprintf("🕐 *%s:*", title),
+		EmptyText: fmt.Sprintf("🕐 *%s, не найдены*\n\n%s", title, hint),
+		Buttons: [][]resultsButton{
+			{{Text: refineText, Data: fmt.Sprintf("search_hours_%d", day)}},
+			{{Text: "🔙 К дням недели", Data: "main_time"}, {Text: "🏠 Главное меню", Data: "main_menu"}},
+		},
+	}
+
+	if err := h.startSearchResults(callback.Message.Chat.ID, callback.From.ID, results); err != nil {
+		ErrorLog.Printf("Error finding vets: %v", err)
+		callbackConfig := tgbotapi.NewCallback(callback.ID, "Ошибка при поиске врачей")
+		h.bot.Request(callbackConfig)
+		return
 	}
 
 	callbackConfig := tgbotapi.NewCallback(callback.ID, "Поиск завершен")
 	h.bot.Request(callbackConfig)
 }
 
-// sendVetWithDayDetailsAndReviews отправляет врача с детальной информацией и кнопками отзывов для дней недели
-func (h *VetHandlers) sendVetWithDayDetailsAndReviews(chatID int64, vet *models.Veterinarian, index int, day int) error {
-	var sb strings.Builder
+// scheduleLookaheadDays на сколько дней вперед искать ближайший прием врача
+const scheduleLookaheadDays = 60
 
-	// Компактный формат как в поиске по клиникам
-	sb.WriteString(fmt.Sprintf("**%d. %s %s**\n", index, html.EscapeString(vet.FirstName), html.EscapeString(vet.LastName)))
-	sb.WriteString(fmt.Sprintf("📞 `%s`", html.EscapeString(vet.Phone)))
+// vetAvailability недельное расписание врача вместе с исключениями (отпуск, другие часы)
+type vetAvailability struct {
+	schedules  []*models.Schedule
+	exceptions []*models.ScheduleException
+	location   *time.Location // Часовой пояс города клиник врача
+}
 
-	// Специализации (первые 2)
-	specs, err := h.db.GetSpecializationsByVetID(models.GetVetIDAsIntOrZero(vet))
-	if err == nil && len(specs) > 0 {
-		sb.WriteString(" 🎯 ")
-		specNames := make([]string, 0)
-		for j, spec := range specs {
-			if j >= 2 { // Ограничиваем 2 специализациями
-				break
-			}
-			specNames = append(specNames, html.EscapeString(spec.Name))
-		}
-		sb.WriteString(strings.Join(specNames, ", "))
-		if len(specs) > 2 {
-			sb.WriteString(fmt.Sprintf(" (+%d)", len(specs)-2))
-		}
+// loadVetAvailability загружает расписание врача и исключения, действующие с now.
+// Если исключения загрузить не удалось, используется обычное расписание
+func (h *VetHandlers) loadVetAvailability(vetID int, now time.Time) (*vetAvailability, error) {
+	schedules, err := h.db.GetSchedulesByVetID(vetID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Рейтинг (если есть)
-	stats, err := h.db.GetReviewStats(models.GetVetIDAsIntOrZero(vet))
-	if err == nil && stats.ApprovedReviews > 0 {
-		sb.WriteString(fmt.Sprintf(" ⭐ %.1f/5", stats.AverageRating))
+	exceptions, err := h.db.GetScheduleExceptionsByVetID(vetID, now)
+	if err != nil {
+		ErrorLog.Printf("Error getting schedule exceptions for vet %d: %v", vetID, err)
 	}
+	return &vetAvailability{
+		schedules:  schedules,
+		exceptions: exceptions,
+		location:   models.SchedulesLocation(schedules),
+	}, nil
+}
 
-	// Расписание
-	schedules, err := h.db.GetSchedulesByVetID(models.GetVetIDAsIntOrZero(vet))
-	if err == nil && len(schedules) > 0 {
-		if day == 0 {
-			// Для "Любой день" показываем ближайший рабочий день
-			nearestSchedule := h.findNearestWorkingDay(schedules)
-			if nearestSchedule != nil {
-				scheduleDayName := getDayName(nearestSchedule.DayOfWeek)
-				startTime := nearestSchedule.StartTime
-				endTime := nearestSchedule.EndTime
-				if startTime != "" && endTime != "" && startTime != "00:00" && endTime != "00:00" {
-					sb.WriteString(fmt.Sprintf(" 🕐 %s %s-%s", scheduleDayName, startTime, endTime))
-					if nearestSchedule.Clinic != nil && nearestSchedule.Clinic.Name != "" {
-						sb.WriteString(fmt.Sprintf(" (%s)", html.EscapeString(nearestSchedule.Clinic.Name)))
-					}
-				}
-			}
-		} else {
-			// Для конкретного дня показываем расписание в этот день
-			for _, schedule := range schedules {
-				if schedule.DayOfWeek == day {
-					scheduleDayName := getDayName(schedule.DayOfWeek)
-					startTime := schedule.StartTime
-					endTime := schedule.EndTime
-					if startTime != "" && endTime != "" && startTime != "00:00" && endTime != "00:00" {
-						sb.WriteString(fmt.Sprintf(" 🕐 %s %s-%s", scheduleDayName, startTime, endTime))
-						if schedule.Clinic != nil && schedule.Clinic.Name != "" {
-							sb.WriteString(fmt.Sprintf(" (%s)", html.EscapeString(schedule.Clinic.Name)))
-						}
-						break // Показываем только первый найденный день
-					}
-				}
-			}
+// localNow переводит now в местное время клиник врача, по умолчанию - в московское
+func (a *vetAvailability) localNow(now time.Time) time.Time {
+	if a == nil || a.location == nil {
+		return now.In(models.DefaultLocation())
+	}
+	return now.In(a.location)
+}
+
+// clinicLocation возвращает часовой пояс города клиники из расписания врача, иначе - пояс врача
+func (a *vetAvailability) clinicLocation(clinicID int) *time.Location {
+	for _, schedule := range a.schedules {
+		if schedule.ClinicID == clinicID && schedule.Clinic != nil && schedule.Clinic.City != nil {
+			return schedule.Clinic.Location()
 		}
 	}
+	if a.location != nil {
+		return a.location
+	}
+	return models.DefaultLocation()
+}
+
+// week возвращает приемы на 7 дней начиная с местной даты now. Каждый день недели встречается
+// один раз и соответствует ближайшей такой дате - так же, как в поиске врачей
+func (a *vetAvailability) week(now time.Time) []*models.Schedule {
+	now = a.localNow(now)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	var result []*models.Schedule
+	for i := 0; i < 7; i++ {
+		result = append(result, models.EffectiveSchedules(a.schedules, a.exceptions, today.AddDate(0, 0, i))...)
+	}
+	return result
+}
 
-	sb.WriteString("\n")
+// inClinic оставляет только приемы и исключения, относящиеся к клинике
+func (a *vetAvailability) inClinic(clinicID int) *vetAvailability {
+	result := &vetAvailability{location: a.location}
+	for _, schedule := range a.schedules {
+		if schedule.ClinicID == clinicID {
+			result.schedules = append(result.schedules, schedule)
+		}
+	}
+	for _, exception := range a.exceptions {
+		if exception.AppliesTo(clinicID) {
+			result.exceptions = append(result.exceptions, exception)
+		}
+	}
+	return result
+}
 
-	// Создаем клавиатуру с кнопками
-	keyboard := tgbotapi.NewInlineKeyboardMarkup(
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("📋 Подробнее", fmt.Sprintf("vet_details_%d", models.GetVetIDAsIntOrZero(vet))),
-			tgbotapi.NewInlineKeyboardButtonData("⭐ Отзывы", fmt.Sprintf("show_reviews_%d", models.GetVetIDAsIntOrZero(vet))),
-		),
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("📝 Добавить отзыв", fmt.Sprintf("add_review_%d", models.GetVetIDAsIntOrZero(vet))),
-		),
-	)
+// findNearestWorkingDay находит ближайший прием врача и его дату с учетом отпусков и других часов
+func (h *VetHandlers) findNearestWorkingDay(availability *vetAvailability, now time.Time) (*models.Schedule, time.Time) {
+	if availability == nil {
+		return nil, time.Time{}
+	}
+	return models.NearestWorkingDay(availability.schedules, availability.exceptions, availability.localNow(now), scheduleLookaheadDays)
+}
 
-	msg := tgbotapi.NewMessage(chatID, sb.String())
-	msg.ParseMode = "Markdown"
-	msg.ReplyMarkup = keyboard
+// scheduleViewDays на сколько дней вперед карточка врача показывает расписание по датам
+const scheduleViewDays = 14
 
-	_, err = h.bot.Send(msg)
-	return err
+// formatScheduleDate подписывает дату в расписании: "Сегодня, Пт 16.10", "Завтра, Сб 17.10", "Пн 19.10".
+// "Сегодня" определяется в часовом поясе самой даты
+func formatScheduleDate(date time.Time, now time.Time) string {
+	label := fmt.Sprintf("%s %s", getShortDayName(weekdayNumber(date)), date.Format("02.01"))
+	now = now.In(date.Location())
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch {
+	case date.Equal(today):
+		return "Сегодня, " + label
+	case date.Equal(today.AddDate(0, 0, 1)):
+		return "Завтра, " + label
+	}
+	return label
 }
 
-// findNearestWorkingDay находит ближайший рабочий день врача
-func (h *VetHandlers) findNearestWorkingDay(schedules []*models.Schedule) *models.Schedule {
-	if len(schedules) == 0 {
+// upcomingScheduleLines описывает приемы врача по датам на days дней начиная с местной даты now
+// с учетом исключений, по строке на рабочий день: "Пн 19.10: 09:00-13:00, 15:00-19:00".
+// withClinic - указывать клинику у каждого приема
+func upcomingScheduleLines(availability *vetAvailability, now time.Time, days int, withClinic bool) []string {
+	if availability == nil {
 		return nil
 	}
+	now = availability.localNow(now)
 
-	// Получаем текущий день недели (1-7, где 1 - понедельник)
-	currentDay := int(time.Now().Weekday())
-	if currentDay == 0 { // Воскресенье в Go это 0, а у нас 7
-		currentDay = 7
-	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	var lines []string
+	for i := 0; i < days; i++ {
+		date := today.AddDate(0, 0, i)
 
-	// Сначала ищем сегодня или ближайший день в этой неделе
-	for day := currentDay; day <= 7; day++ {
-		for _, schedule := range schedules {
-			if schedule.DayOfWeek == day && schedule.IsAvailable {
-				startTime := schedule.StartTime
-				endTime := schedule.EndTime
-				if startTime != "" && endTime != "" && startTime != "00:00" && endTime != "00:00" {
-					return schedule
-				}
+		var slots []string
+		seen := make(map[string]bool)
+		for _, schedule := range models.EffectiveSchedules(availability.schedules, availability.exceptions, date) {
+			slot := fmt.Sprintf("%s-%s", schedule.StartTime, schedule.EndTime)
+			if withClinic && schedule.Clinic != nil && schedule.Clinic.Name != "" {
+				slot += fmt.Sprintf(" (%s)", schedule.Clinic.Name)
 			}
-		}
-	}
-
-	// Если не нашли в оставшиеся дни недели, ищем с начала недели
-	for day := 1; day < currentDay; day++ {
-		for _, schedule := range schedules {
-			if schedule.DayOfWeek == day && schedule.IsAvailable {
-				startTime := schedule.StartTime
-				endTime := schedule.EndTime
-				if startTime != "" && endTime != "" && startTime != "00:00" && endTime != "00:00" {
-					return schedule
-				}
+			if !seen[slot] {
+				seen[slot] = true
+				slots = append(slots, slot)
 			}
 		}
-	}
 
-	// Если ничего не нашли, возвращаем первый доступный день
-	for _, schedule := range schedules {
-		if schedule.IsAvailable {
-			startTime := schedule.StartTime
-			endTime := schedule.EndTime
-			if startTime != "" && endTime != "" && startTime != "00:00" && endTime != "00:00" {
-				return schedule
-			}
+		if len(slots) > 0 {
+			lines = append(lines, fmt.Sprintf("%s: %s", formatScheduleDate(date, now), strings.Join(slots, ", ")))
 		}
 	}
-
-	return nil
+	return lines
 }
 
 // HandleTest для тестирования
@@ -11,6 +11,7 @@ import (
 	"github.com/drerr0r/vetbot/internal/models"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // ============================================================================
@@ -364,13 +365,14 @@ func TestVetHandleSearchByClinic(t *testing.T) {
 
 		// Создаем расписание
 		schedule := &models.Schedule{
-			ID:        1,
-			VetID:     1,
-			ClinicID:  1,
-			DayOfWeek: 1,
-			StartTime: "09:00",
-			EndTime:   "18:00",
-			Clinic:    clinic,
+			ID:          1,
+			VetID:       1,
+			ClinicID:    1,
+			DayOfWeek:   1,
+			StartTime:   "09:00",
+			EndTime:     "18:00",
+			IsAvailable: true,
+			Clinic:      clinic,
 		}
 		mockDB.Schedules[1] = schedule
 
@@ -656,11 +658,12 @@ func TestVetMessageFormatting(t *testing.T) {
 
 		// Добавляем расписание
 		schedule := &models.Schedule{
-			VetID:     1,
-			DayOfWeek: 1,
-			StartTime: "09:00",
-			EndTime:   "18:00",
-			Clinic:    &models.Clinic{Name: "ВетКлиника Центр"},
+			VetID:       1,
+			DayOfWeek:   1,
+			StartTime:   "09:00",
+			EndTime:     "18:00",
+			IsAvailable: true,
+			Clinic:      &models.Clinic{Name: "ВетКлиника Центр"},
 		}
 		mockDB.Schedules[1] = schedule
 
@@ -687,7 +690,24 @@ func TestVetMessageFormatting(t *testing.T) {
 		assert.Contains(t, messageText, "+79123456789")
 		assert.Contains(t, messageText, "dmitry@vet.ru")
 		assert.Contains(t, messageText, "10 лет")
-		assert.Contains(t, messageText, "понедельник: 09:00-18:00")
+		assert.Regexp(t, `Пн \d{2}\.\d{2}: 09:00-18:00`, messageText)
+	})
+
+	t.Run("Schedule exception reason and clinic are escaped in the card", func(t *testing.T) {
+		mockDB := NewMockDatabase()
+		mockDB.Clinics[1] = &models.Clinic{ID: 1, Name: "Вет_Центр"}
+		tomorrow := time.Now().AddDate(0, 0, 1)
+		require.NoError(t, mockDB.CreateScheduleException(&models.ScheduleException{
+			VetID: 1, DateFrom: tomorrow, DateTo: tomorrow,
+			ClinicID: sql.NullInt64{Int64: 1, Valid: true}, Reason: "больничный_2 *срочно*",
+		}))
+		handlers := NewVetHandlers(NewMockBot(), mockDB, nil, NewTestStateManager())
+
+		message := handlers.formatVeterinarianDetails(&models.Veterinarian{
+			ID: sql.NullInt64{Int64: 1, Valid: true}, FirstName: "Дмитрий", LastName: "Сидоров",
+		})
+
+		assert.Contains(t, message, `в Вет\_Центр (больничный\_2 \*срочно\*)`)
 	})
 }
 
@@ -778,10 +798,11 @@ func TestVetHandleDaySelection(t *testing.T) {
 
 		// Создаем расписание на понедельник
 		schedule := &models.Schedule{
-			VetID:     1,
-			DayOfWeek: 1,
-			StartTime: "09:00",
-			EndTime:   "18:00",
+			VetID:       1,
+			DayOfWeek:   1,
+			StartTime:   "09:00",
+			EndTime:     "18:00",
+			IsAvailable: true,
 		}
 		mockDB.Schedules[1] = schedule
 
@@ -805,7 +826,7 @@ func TestVetHandleDaySelection(t *testing.T) {
 
 		assert.Contains(t, messageText, "Сергей Кузнецов")
 		assert.Contains(t, messageText, "+79123456789")
-		assert.Contains(t, messageText, "понедельник: 09:00-18:00")
+		assert.Regexp(t, `Пн \d{2}\.\d{2}: 09:00-18:00`, messageText)
 	})
 
 	t.Run("Search by day with no results", func(t *testing.T) {
@@ -853,6 +874,146 @@ func TestVetHandleDaySelection(t *testing.T) {
 	})
 }
 
+func TestVetHandleHourSelection(t *testing.T) {
+	newHandlers := func() (*VetHandlers, *MockBot) {
+		mockBot := NewMockBot()
+		mockDB := NewMockDatabase()
+
+		mockDB.Veterinarians[1] = &models.Veterinarian{
+			ID:        sql.NullInt64{Int64: 1, Valid: true},
+			FirstName: "Сергей",
+			LastName:  "Кузнецов",
+			Phone:     "+79123456789",
+		}
+		mockDB.Veterinarians[2] = &models.Veterinarian{
+			ID:        sql.NullInt64{Int64: 2, Valid: true},
+			FirstName: "Ольга",
+			LastName:  "Смирнова",
+			Phone:     "+79987654321",
+		}
+
+		// Дневной прием в понедельник и ночная смена с понедельника на вторник
+		mockDB.Schedules[1] = &models.Schedule{ID: 1, VetID: 1, DayOfWeek: 1, StartTime: "09:00", EndTime: "18:00", IsAvailable: true}
+		mockDB.Schedules[2] = &models.Schedule{ID: 2, VetID: 2, DayOfWeek: 1, StartTime: "20:00", EndTime: "08:00", IsAvailable: true}
+
+		return NewVetHandlers(mockBot, mockDB, []int64{12345}, NewTestStateManager()), mockBot
+	}
+
+	sentTexts := func(mockBot *MockBot) string {
+		var texts []string
+		for _, msg := range mockBot.SentMessages {
+			texts = append(texts, msg.Text)
+		}
+		return strings.Join(texts, "\n")
+	}
+
+	t.Run("Daytime hour", func(t *testing.T) {
+		handlers, mockBot := newHandlers()
+		update := NewTestUpdate().WithCallback("search_at_1_12", 12345, 1).Build()
+
+		handlers.handleHourSelection(update.CallbackQuery)
+
+		texts := sentTexts(mockBot)
+		assert.Contains(t, texts, "в 12:00")
+		assert.Contains(t, texts, "Сергей Кузнецов")
+		assert.NotContains(t, texts, "Ольга Смирнова")
+	})
+
+	t.Run("Overnight shift continues next day", func(t *testing.T) {
+		handlers, mockBot := newHandlers()
+		update := NewTestUpdate().WithCallback("search_at_2_3", 12345, 1).Build()
+
+		handlers.handleHourSelection(update.CallbackQuery)
+
+		texts := sentTexts(mockBot)
+		assert.Contains(t, texts, "Ольга Смирнова")
+		assert.Regexp(t, `Пн \d{2}\.\d{2}: 20:00-08:00`, texts)
+		assert.NotContains(t, texts, "Сергей Кузнецов")
+	})
+
+	t.Run("Nobody working", func(t *testing.T) {
+		handlers, mockBot := newHandlers()
+		update := NewTestUpdate().WithCallback("search_at_3_12", 12345, 1).Build()
+
+		handlers.handleHourSelection(update.CallbackQuery)
+
+		assert.Contains(t, mockBot.GetLastMessage().Text, "не найдены")
+	})
+
+	t.Run("Invalid hour", func(t *testing.T) {
+		handlers, mockBot := newHandlers()
+		update := NewTestUpdate().WithCallback("search_at_1_24", 12345, 1).Build()
+
+		handlers.handleHourSelection(update.CallbackQuery)
+
+		assert.Empty(t, mockBot.SentMessages)
+	})
+
+	t.Run("Hour picker", func(t *testing.T) {
+		handlers, mockBot := newHandlers()
+		update := NewTestUpdate().WithCallback("search_hours_1", 12345, 1).Build()
+
+		handlers.handleHourPickerCallback(update.CallbackQuery)
+
+		markup, ok := mockBot.GetLastMessage().ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+		if !assert.True(t, ok) {
+			return
+		}
+		callbacks := inlineCallbacks(&markup)
+		assert.Contains(t, callbacks, "search_at_1_0")
+		assert.Contains(t, callbacks, "search_at_1_23")
+		assert.Contains(t, callbacks, "main_time")
+	})
+
+	t.Run("Working now", func(t *testing.T) {
+		handlers, mockBot := newHandlers()
+		update := NewTestUpdate().WithCallback("search_now", 12345, 1).Build()
+
+		handlers.handleSearchNowCallback(update.CallbackQuery)
+
+		if !assert.NotEmpty(t, mockBot.SentMessages) {
+			return
+		}
+		assert.Contains(t, mockBot.SentMessages[0].Text, "Сейчас работают")
+	})
+}
+
+func TestVetSearchNowByCityTimezone(t *testing.T) {
+	handlers, mockBot, mockDB := CreateTestVetHandlers()
+
+	cities := map[int]*models.City{
+		1: {ID: 1, Name: "Новосибирск", Timezone: "Asia/Novosibirsk"},
+		2: {ID: 2, Name: "Калининград", Timezone: "Europe/Kaliningrad"},
+	}
+
+	// Оба врача принимают в один и тот же час по новосибирскому времени,
+	// но в Калининграде сейчас на 5 часов меньше
+	local := time.Now().In(cities[1].Location())
+	start := fmt.Sprintf("%02d:00", local.Hour())
+	end := fmt.Sprintf("%02d:00", (local.Hour()+1)%24)
+	for id, name := range map[int]string{1: "Анна", 2: "Борис"} {
+		mockDB.Cities[id] = cities[id]
+		mockDB.AddTestClinic(id, "Клиника "+cities[id].Name, "ул. Ленина, 1", id)
+		mockDB.AddTestVeterinarian(id, name, "Врачова", "+79990000000")
+		mockDB.Schedules[id] = &models.Schedule{
+			ID: id, VetID: id, ClinicID: id, DayOfWeek: weekdayNumber(local),
+			StartTime: start, EndTime: end, IsAvailable: true,
+			Clinic: &models.Clinic{ID: id, Name: "Клиника " + cities[id].Name, City: cities[id]},
+		}
+	}
+
+	handlers.handleSearchNowCallback(NewTestUpdate().WithCallback("search_now", 12345, 1).Build().CallbackQuery)
+
+	msg := mockBot.GetLastMessage()
+	require.NotNil(t, msg)
+	texts := msg.Text
+	assert.Contains(t, texts, "по местному времени")
+	assert.Contains(t, texts, "Анна Врачова")
+	assert.Contains(t, texts, fmt.Sprintf("Сегодня, %s %s: %s-%s",
+		getShortDayName(weekdayNumber(local)), local.Format("02.01"), start, end))
+	assert.NotContains(t, texts, "Борис")
+}
+
 // ============================================================================
 // ТЕСТЫ ДЛЯ ОБРАБОТКИ ОШИБОК БАЗЫ ДАННЫХ
 // ============================================================================
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// WebhookSecretHeader - заголовок, в котором Telegram передает секрет webhook
+const WebhookSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// maxWebhookBodySize ограничивает размер тела запроса от Telegram
+const maxWebhookBodySize = 1 << 20
+
+// UpdateHandler обрабатывает обновления Telegram (реализуется MainHandler и Dispatcher)
+type UpdateHandler interface {
+	HandleUpdate(update tgbotapi.Update)
+}
+
+// WebhookHandler принимает обновления Telegram по HTTP
+type WebhookHandler struct {
+	handler UpdateHandler
+	secret  string
+}
+
+// NewWebhookHandler создает HTTP обработчик webhook
+func NewWebhookHandler(handler UpdateHandler, secret string) *WebhookHandler {
+	return &WebhookHandler{
+		handler: handler,
+		secret:  secret,
+	}
+}
+
+// ServeHTTP проверяет секрет и передает обновление в обработчик
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token := r.Header.Get(WebhookSecretHeader)
+	if subtle.ConstantTimeCompare([]byte(token), []byte(h.secret)) != 1 {
+		ErrorLog.Printf("Webhook request with invalid secret token from %s", r.RemoteAddr)
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	var update tgbotapi.Update
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxWebhookBodySize)).Decode(&update); err != nil {
+		ErrorLog.Printf("Error decoding webhook update: %v", err)
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	// Webhook зарегистрирован с одним соединением, поэтому обновления приходят по порядку;
+	// Dispatcher сохраняет этот порядок внутри каждого чата
+	h.handler.HandleUpdate(update)
+
+	w.WriteHeader(http.StatusOK)
+}
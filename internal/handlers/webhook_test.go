@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingUpdateHandler запоминает полученные обновления
+type recordingUpdateHandler struct {
+	updates []tgbotapi.Update
+}
+
+func (r *recordingUpdateHandler) HandleUpdate(update tgbotapi.Update) {
+	r.updates = append(r.updates, update)
+}
+
+func TestWebhookHandler(t *testing.T) {
+	body := `{"update_id": 42, "message": {"message_id": 1, "text": "/start", "chat": {"id": 100}, "from": {"id": 100}}}`
+
+	tests := []struct {
+		name          string
+		method        string
+		secret        string
+		body          string
+		expectStatus  int
+		expectUpdates int
+	}{
+		{"Valid update", http.MethodPost, "secret", body, http.StatusOK, 1},
+		{"Wrong secret", http.MethodPost, "wrong", body, http.StatusForbidden, 0},
+		{"Missing secret", http.MethodPost, "", body, http.StatusForbidden, 0},
+		{"Invalid JSON", http.MethodPost, "secret", "{", http.StatusBadRequest, 0},
+		{"GET not allowed", http.MethodGet, "secret", "", http.StatusMethodNotAllowed, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &recordingUpdateHandler{}
+			handler := NewWebhookHandler(recorder, "secret")
+
+			req := httptest.NewRequest(tt.method, "/webhook", strings.NewReader(tt.body))
+			if tt.secret != "" {
+				req.Header.Set(WebhookSecretHeader, tt.secret)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectStatus, rec.Code)
+			assert.Len(t, recorder.updates, tt.expectUpdates)
+		})
+	}
+
+	t.Run("Update is passed to MainHandler", func(t *testing.T) {
+		mainHandler, mockBot := CreateTestMainHandlers()
+		handler := NewWebhookHandler(mainHandler, "secret")
+
+		req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+		req.Header.Set(WebhookSecretHeader, "secret")
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotEmpty(t, mockBot.SentMessages)
+	})
+}
@@ -0,0 +1,238 @@
+package imports
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/drerr0r/vetbot/internal/models"
+	"github.com/xuri/excelize/v2"
+)
+
+// statusColumnHeader заголовок колонки со статусом строки в книге с результатами проверки
+const statusColumnHeader = "Статус импорта"
+
+// Цвета заливки в книге с результатами проверки
+const (
+	fillError  = "FFC7CE"
+	fillCreate = "C6EFCE"
+	fillUpdate = "FFEB9C"
+)
+
+// knownColumnCounts число колонок, которые читает импорт каждого типа. Колонка статуса ставится
+// не раньше них, иначе в коротком файле старого шаблона она заняла бы место колонки,
+// которую импорт читает по номеру
+var knownColumnCounts = map[string]int{
+	ImportTypeVeterinarians: 12,
+	ImportTypeClinics:       9,
+	ImportTypeCities:        2,
+}
+
+// errorFieldColumns колонки файла (с нуля), к которым относится поле ошибки PlannedRow.Field
+var errorFieldColumns = map[string]map[string][]int{
+	ImportTypeVeterinarians: {
+		"name":            {0, 1, 2},
+		"experience":      {4},
+		"city":            {6},
+		"specializations": {7},
+		"schedule":        {8},
+		"active":          {11},
+	},
+	ImportTypeClinics: {
+		"name":        {0, 2},
+		"city":        {1},
+		"coordinates": {7, 8},
+	},
+	ImportTypeCities: {
+		"name": {0},
+	},
+}
+
+// AnnotatedFileName имя книги с результатами проверки: "врачи_проверка.xlsx"
+func (p *ImportPlan) AnnotatedFileName() string {
+	base := strings.TrimSuffix(filepath.Base(p.FileName), filepath.Ext(p.FileName))
+	return base + "_проверка.xlsx"
+}
+
+// AnnotatedWorkbook возвращает загруженный файл в виде книги Excel с колонкой "Статус импорта".
+// Ячейки с ошибками подсвечены, поэтому строки можно исправить прямо в книге и загрузить ее снова
+func (p *ImportPlan) AnnotatedWorkbook() ([]byte, error) {
+	if len(p.records) == 0 {
+		return nil, fmt.Errorf("нет строк файла")
+	}
+
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sheet := map[string]string{
+		ImportTypeVeterinarians: VetsSheetName,
+		ImportTypeClinics:       ClinicsSheetName,
+		ImportTypeCities:        CitiesSheetName,
+	}[p.Type]
+	if sheet == "" {
+		sheet = "Sheet1"
+	} else if err := f.SetSheetName("Sheet1", sheet); err != nil {
+		return nil, err
+	}
+
+	// Колонка статуса идет после колонок импорта и после самой длинной строки файла
+	statusCol := knownColumnCounts[p.Type]
+	for _, record := range p.records {
+		if len(record) > statusCol {
+			statusCol = len(record)
+		}
+	}
+
+	styles, err := newAnnotatedStyles(f)
+	if err != nil {
+		return nil, err
+	}
+
+	for rowIdx, record := range p.records {
+		for col, value := range record {
+			cell, _ := excelize.CoordinatesToCellName(col+1, rowIdx+1)
+			if err := f.SetCellStr(sheet, cell, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	header, _ := excelize.CoordinatesToCellName(statusCol+1, 1)
+	if err := f.SetCellStr(sheet, header, statusColumnHeader); err != nil {
+		return nil, err
+	}
+	if err := f.SetCellStyle(sheet, "A1", header, styles.header); err != nil {
+		return nil, err
+	}
+
+	for _, row := range p.Rows {
+		statusCell, _ := excelize.CoordinatesToCellName(statusCol+1, row.RowNumber)
+		if err := f.SetCellStr(sheet, statusCell, formatRowStatus(row)); err != nil {
+			return nil, err
+		}
+
+		if style, ok := styles.byAction[row.Action]; ok {
+			if err := f.SetCellStyle(sheet, statusCell, statusCell, style); err != nil {
+				return nil, err
+			}
+		}
+
+		if row.Action != models.ImportActionError {
+			continue
+		}
+		for _, col := range p.errorColumns(row) {
+			cell, _ := excelize.CoordinatesToCellName(col+1, row.RowNumber)
+			if err := f.SetCellStyle(sheet, cell, cell, styles.errorCell); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	statusColName, _ := excelize.ColumnNumberToName(statusCol + 1)
+	if err := f.SetColWidth(sheet, statusColName, statusColName, 60); err != nil {
+		return nil, err
+	}
+
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// dropStatusColumn убирает из строк файла колонку статуса, если файл - загруженная снова книга
+// с результатами проверки
+func dropStatusColumn(records [][]string) [][]string {
+	if len(records) == 0 {
+		return records
+	}
+	statusCol := -1
+	for col, value := range records[0] {
+		if strings.TrimSpace(value) == statusColumnHeader {
+			statusCol = col
+			break
+		}
+	}
+	if statusCol < 0 {
+		return records
+	}
+
+	for i, record := range records {
+		if statusCol < len(record) {
+			records[i] = append(record[:statusCol:statusCol], record[statusCol+1:]...)
+		}
+	}
+	return records
+}
+
+// annotatedStyles стили книги с результатами проверки
+type annotatedStyles struct {
+	header    int
+	errorCell int
+	byAction  map[models.ImportAction]int
+}
+
+func newAnnotatedStyles(f *excelize.File) (*annotatedStyles, error) {
+	fill := func(color string) *excelize.Style {
+		return &excelize.Style{Fill: excelize.Fill{Type: "pattern", Color: []string{color}, Pattern: 1}}
+	}
+
+	styles := &annotatedStyles{byAction: make(map[models.ImportAction]int)}
+	var err error
+	if styles.header, err = f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}}); err != nil {
+		return nil, err
+	}
+	if styles.errorCell, err = f.NewStyle(fill(fillError)); err != nil {
+		return nil, err
+	}
+	for action, color := range map[models.ImportAction]string{
+		models.ImportActionError:  fillError,
+		models.ImportActionCreate: fillCreate,
+		models.ImportActionUpdate: fillUpdate,
+	} {
+		if styles.byAction[action], err = f.NewStyle(fill(color)); err != nil {
+			return nil, err
+		}
+	}
+	return styles, nil
+}
+
+// errorColumns возвращает ячейки строки, которые нужно подсветить. Если среди колонок поля
+// есть пустые - подсвечиваются только они (не заполнено обязательное значение)
+func (p *ImportPlan) errorColumns(row *PlannedRow) []int {
+	columns := errorFieldColumns[p.Type][row.Field]
+	if len(columns) == 0 || row.RowNumber-1 >= len(p.records) {
+		return nil
+	}
+
+	record := p.records[row.RowNumber-1]
+	var empty []int
+	for _, col := range columns {
+		if col >= len(record) || strings.TrimSpace(record[col]) == "" {
+			empty = append(empty, col)
+		}
+	}
+	if len(empty) > 0 {
+		return empty
+	}
+	return columns
+}
+
+// formatRowStatus текст колонки "Статус импорта" для строки файла
+func formatRowStatus(row *PlannedRow) string {
+	var status string
+	switch row.Action {
+	case models.ImportActionCreate:
+		status = "OK: новая запись"
+	case models.ImportActionUpdate:
+		status = "Обновление"
+	case models.ImportActionSkip:
+		status = "Пропуск"
+	case models.ImportActionError:
+		status = "Ошибка"
+	}
+	if row.Action != models.ImportActionCreate && row.Message != "" {
+		status += ": " + row.Message
+	}
+	return status
+}
@@ -1,8 +1,10 @@
 package imports
 
 import (
+	"context"
 	"database/sql"
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -20,48 +22,92 @@ type DatabaseInterface interface {
 	GetAllCities() ([]*models.City, error)
 	GetAllSpecializations() ([]*models.Specialization, error)
 	GetAllClinics() ([]*models.Clinic, error)
-	CreateVeterinarian(vet *models.Veterinarian) error
-	AddVeterinarianSpecialization(vetID int, specID int) error
+	GetAllVeterinarians() ([]*models.Veterinarian, error)
+	GetSpecializationsByVetID(vetID int) ([]*models.Specialization, error)
+	GetClinicsByVetID(vetID int) ([]*models.Clinic, error)
+	GetAllSchedulesByVetID(vetID int) ([]*models.Schedule, error)
 }
 
+// Названия листов книги выгрузки справочника; при импорте из Excel читается лист своего типа
+const (
+	VetsSheetName    = "Врачи"
+	ClinicsSheetName = "Клиники"
+	CitiesSheetName  = "Города"
+)
+
+// DisabledScheduleMark помечает выключенный прием в колонке КлиникиИРасписание: "пн:9-18(выкл)"
+const DisabledScheduleMark = "(выкл)"
+
+// scheduleSeparators разделители колонки КлиникиИРасписание: ";" между клиниками, ":" после названия клиники
+const scheduleSeparators = ";:"
+
+// IsScheduleClinicName проверяет, можно ли указать клинику в колонке КлиникиИРасписание:
+// название с разделителями колонки parseClinicSchedules разобрать не сможет
+func IsScheduleClinicName(name string) bool {
+	return !strings.ContainsAny(name, scheduleSeparators)
+}
+
+// ErrImportCancelled возвращается, если проверку или применение импорта отменили
+var ErrImportCancelled = errors.New("импорт отменен")
+
 type CSVImporter struct {
-	db DatabaseInterface
+	db       DatabaseInterface
+	ctx      context.Context
+	progress func(done, total int)
 }
 
 func NewCSVImporter(db DatabaseInterface) *CSVImporter {
-	return &CSVImporter{db: db}
+	return &CSVImporter{db: db, ctx: context.Background()}
+}
+
+// WithContext задает контекст, отмена которого прерывает проверку и откатывает применение импорта
+func (i *CSVImporter) WithContext(ctx context.Context) *CSVImporter {
+	i.ctx = ctx
+	return i
+}
+
+// WithProgress задает функцию, которая вызывается после каждой обработанной строки
+// при проверке файла и при применении плана
+func (i *CSVImporter) WithProgress(progress func(done, total int)) *CSVImporter {
+	i.progress = progress
+	return i
+}
+
+// checkpoint сообщает о прогрессе и проверяет, не отменен ли импорт
+func (i *CSVImporter) checkpoint(done, total int) error {
+	i.reportProgress(done, total)
+	if i.ctx.Err() != nil {
+		return ErrImportCancelled
+	}
+	return nil
+}
+
+func (i *CSVImporter) reportProgress(done, total int) {
+	if i.progress != nil {
+		i.progress(done, total)
+	}
 }
 
-// ImportVeterinarians импортирует врачей из CSV/Excel с поддержкой городов, клиник и расписания
-func (i *CSVImporter) ImportVeterinarians(file io.Reader, filename string, InfoLog, ErrorLog *log.Logger) (*models.ImportResult, error) {
-	InfoLog.Printf("🚀 Начало импорта файла: %s", filename)
+// PlanVeterinarians проверяет файл с врачами (колонки шаблона импорта) и составляет план импорта.
+// Врач, который уже есть в справочнике (те же имя, фамилия и телефон), обновляется:
+// см. planVeterinarianUpdate
+func (i *CSVImporter) PlanVeterinarians(file io.Reader, filename string, InfoLog, ErrorLog *log.Logger) (*ImportPlan, error) {
+	InfoLog.Printf("🔍 Проверка файла врачей: %s", filename)
 
-	records, err := i.readFile(file, filename)
+	records, err := i.readFile(file, filename, VetsSheetName)
 	if err != nil {
 		ErrorLog.Printf("❌ Ошибка чтения файла: %v", err)
 		return nil, err
 	}
-
-	InfoLog.Printf("📊 Прочитано строк: %d", len(records))
-
-	if len(records) > 0 {
-		InfoLog.Printf("📋 Заголовки: %v", records[0])
-	} else {
+	if len(records) == 0 {
 		ErrorLog.Printf("❌ Файл %s пустой", filename)
 		return nil, fmt.Errorf("файл пустой")
 	}
+	InfoLog.Printf("📋 Заголовки: %v", records[0])
 
-	result := &models.ImportResult{
-		TotalRows: len(records) - 1, // минус заголовок
-		Errors:    []models.ImportError{},
-	}
-
-	// Предзагружаем справочники для быстрого поиска
-	InfoLog.Printf("🔍 Загрузка справочников...")
-	cities, err := i.db.GetAllCities()
+	cityMap, cityNames, err := i.loadCities()
 	if err != nil {
-		ErrorLog.Printf("❌ Ошибка загрузки городов: %v", err)
-		return nil, fmt.Errorf("ошибка загрузки городов: %w", err)
+		return nil, err
 	}
 
 	specializations, err := i.db.GetAllSpecializations()
@@ -69,256 +115,529 @@ func (i *CSVImporter) ImportVeterinarians(file io.Reader, filename string, InfoL
 		ErrorLog.Printf("❌ Ошибка загрузки специализаций: %v", err)
 		return nil, fmt.Errorf("ошибка загрузки специализаций: %w", err)
 	}
+	specMap := make(map[string]int)
+	specNames := make(map[int]string, len(specializations))
+	for _, spec := range specializations {
+		specMap[strings.ToLower(spec.Name)] = spec.ID
+		specNames[spec.ID] = spec.Name
+	}
 
 	clinics, err := i.db.GetAllClinics()
 	if err != nil {
 		ErrorLog.Printf("❌ Ошибка загрузки клиник: %v", err)
 		return nil, fmt.Errorf("ошибка загрузки клиник: %w", err)
 	}
-
-	cityMap := make(map[string]int)
-	for _, city := range cities {
-		cityMap[strings.ToLower(city.Name)] = city.ID
-	}
-
-	specMap := make(map[string]int)
-	for _, spec := range specializations {
-		specMap[strings.ToLower(spec.Name)] = spec.ID
-	}
-
 	clinicMap := make(map[string]int)
+	clinicNames := make(map[int]string, len(clinics))
 	for _, clinic := range clinics {
 		clinicMap[strings.ToLower(clinic.Name)] = clinic.ID
+		clinicNames[clinic.ID] = clinic.Name
 	}
+	names := &directoryNames{cities: cityNames, specializations: specNames, clinics: clinicNames}
 
-	InfoLog.Printf("✅ Справочники загружены: %d городов, %d специализаций, %d клиник", len(cities), len(specializations), len(clinics))
-
-	InfoLog.Printf("🔍 Отладка - загруженные клиники:")
-	for _, clinic := range clinics {
-		district := "не указан"
-		if clinic.District.Valid {
-			district = clinic.District.String
-		}
-
-		metro := "не указана"
-		if clinic.MetroStation.Valid {
-			metro = clinic.MetroStation.String
-		}
-
-		InfoLog.Printf("   Клиника: %s (ID: %d, CityID: %v, District: %s, Metro: %s)",
-			clinic.Name, clinic.ID, clinic.CityID, district, metro)
+	vets, err := i.db.GetAllVeterinarians()
+	if err != nil {
+		ErrorLog.Printf("❌ Ошибка загрузки врачей: %v", err)
+		return nil, fmt.Errorf("ошибка загрузки врачей: %w", err)
 	}
-
-	InfoLog.Printf("🔍 Отладка - загруженные города:")
-	for _, city := range cities {
-		InfoLog.Printf("   Город: %s (ID: %d, Region: %s)", city.Name, city.ID, city.Region)
+	existingVets := make(map[string]*models.Veterinarian, len(vets))
+	for _, vet := range vets {
+		existingVets[vetIdentityKey(vet.FirstName, vet.LastName, vet.Phone)] = vet
 	}
 
+	plan := newImportPlan(ImportTypeVeterinarians, filename, records)
+	seen := make(map[string]int)
+
 	for idx, record := range records {
 		if idx == 0 {
-			InfoLog.Printf("🔤 Пропускаем заголовок: %v", record)
 			continue // Пропускаем заголовок
 		}
+		rowNum := idx + 1
+		if err := i.checkpoint(idx-1, plan.TotalRows); err != nil {
+			return nil, err
+		}
 
-		if len(record) < 7 {
-			result.ErrorCount++
-			result.Errors = append(result.Errors, models.ImportError{
-				RowNumber: idx + 1,
-				Field:     "all",
-				Message:   fmt.Sprintf("Недостаточно колонок (требуется минимум 7, получено %d)", len(record)),
-			})
-			ErrorLog.Printf("❌ Строка %d: недостаточно колонок (%d вместо 7)", idx+1, len(record))
+		if isEmptyRecord(record) {
+			plan.addSkip(rowNum, "", "Пустая строка")
+			continue
+		}
+		if len(record) < 3 {
+			plan.addError(rowNum, "", "all",
+				fmt.Sprintf("Недостаточно колонок (требуется минимум 3, получено %d)", len(record)))
 			continue
 		}
 
-		InfoLog.Printf("📝 Обрабатываем строку %d: %v", idx+1, record)
-
-		// Парсим данные врача
 		vet := &models.Veterinarian{
-			FirstName: strings.TrimSpace(record[0]),
-			LastName:  strings.TrimSpace(record[1]),
-			Phone:     strings.TrimSpace(record[2]),
-			Email:     i.parseNullString(record[3]),
-			IsActive:  true,
+			FirstName:   strings.TrimSpace(record[0]),
+			LastName:    strings.TrimSpace(record[1]),
+			Patronymic:  i.columnNullString(record, 10),
+			Phone:       strings.TrimSpace(record[2]),
+			Email:       i.columnNullString(record, 3),
+			Description: i.columnNullString(record, 5),
+			IsActive:    true,
 		}
+		title := strings.TrimSpace(vet.FirstName + " " + vet.LastName)
 
-		// Опыт работы
-		if record[4] != "" {
-			if exp, err := strconv.ParseInt(strings.TrimSpace(record[4]), 10, 64); err == nil {
-				vet.ExperienceYears = sql.NullInt64{Int64: exp, Valid: true}
-				InfoLog.Printf("💼 Строка %d: опыт работы %d лет", idx+1, exp)
-			} else {
-				ErrorLog.Printf("⚠️ Строка %d: неверный формат опыта работы '%s'", idx+1, record[4])
-			}
+		if vet.FirstName == "" || vet.LastName == "" || vet.Phone == "" {
+			plan.addError(rowNum, title, "name", "Не указаны обязательные поля: имя, фамилия и телефон")
+			continue
 		}
 
-		// Описание
-		if len(record) > 5 {
-			vet.Description = i.parseNullString(record[5])
-			if vet.Description.Valid {
-				InfoLog.Printf("📄 Строка %d: описание добавлено", idx+1)
+		// Опыт работы
+		if experience := i.columnNullString(record, 4); experience.Valid {
+			years, err := strconv.ParseInt(experience.String, 10, 64)
+			if err != nil || years < 0 {
+				plan.addError(rowNum, title, "experience", fmt.Sprintf("Неверный опыт работы '%s', ожидается число лет", experience.String))
+				continue
 			}
+			vet.ExperienceYears = sql.NullInt64{Int64: years, Valid: true}
 		}
 
 		// Город
-		if len(record) > 6 && record[6] != "" {
-			cityName := strings.TrimSpace(record[6])
-			if id, exists := cityMap[strings.ToLower(cityName)]; exists {
-				vet.CityID = sql.NullInt64{Int64: int64(id), Valid: true}
-				InfoLog.Printf("🏙️ Строка %d: город '%s' найден (ID: %d)", idx+1, cityName, id)
-			} else {
-				result.ErrorCount++
-				result.Errors = append(result.Errors, models.ImportError{
-					RowNumber: idx + 1,
-					Field:     "city",
-					Message:   fmt.Sprintf("Город '%s' не найден в базе", cityName),
-				})
-				ErrorLog.Printf("❌ Строка %d: город '%s' не найден в базе", idx+1, cityName)
+		if cityName := i.columnNullString(record, 6); cityName.Valid {
+			cityID, exists := cityMap[strings.ToLower(cityName.String)]
+			if !exists {
+				plan.addError(rowNum, title, "city", fmt.Sprintf("Город '%s' не найден в базе", cityName.String))
 				continue
 			}
-		} else {
-			ErrorLog.Printf("⚠️ Строка %d: город не указан", idx+1)
+			vet.CityID = sql.NullInt64{Int64: int64(cityID), Valid: true}
+		}
+
+		// Активность (колонка 11): пустая ячейка не меняет ее у существующего врача
+		active, err := parseActiveFlag(i.columnNullString(record, 11))
+		if err != nil {
+			plan.addError(rowNum, title, "active", err.Error())
+			continue
+		}
+		if active.Valid {
+			vet.IsActive = active.Bool
+		}
+
+		// Специализации (колонка 7)
+		specIDs, err := i.parseSpecializations(i.columnNullString(record, 7).String, specMap)
+		if err != nil {
+			plan.addError(rowNum, title, "specializations", err.Error())
+			continue
 		}
 
-		// Добавляем врача в базу со всеми связями
-		err := i.addVeterinarianWithRelations(vet, record, specMap, clinicMap, idx+1, InfoLog, ErrorLog)
+		// Клиники и расписание (колонка 8)
+		schedules, err := i.parseClinicSchedules(i.columnNullString(record, 8).String, clinicMap)
 		if err != nil {
-			result.ErrorCount++
-			result.Errors = append(result.Errors, models.ImportError{
-				RowNumber: idx + 1,
-				Field:     "database",
-				Message:   fmt.Sprintf("Ошибка сохранения: %v", err),
+			plan.addError(rowNum, title, "schedule", err.Error())
+			continue
+		}
+
+		key := vetIdentityKey(vet.FirstName, vet.LastName, vet.Phone)
+		if firstRow, duplicate := seen[key]; duplicate {
+			plan.addError(rowNum, title, "name", fmt.Sprintf("Повторяет строку %d", firstRow))
+			continue
+		}
+		seen[key] = rowNum
+
+		existing, exists := existingVets[key]
+		if !exists {
+			plan.addChange(rowNum, models.ImportActionCreate, title, "", func(tx *sql.Tx) error {
+				return insertVeterinarian(tx, vet, specIDs, schedules)
 			})
-			ErrorLog.Printf("❌ Строка %d: ошибка сохранения врача: %v", idx+1, err)
-		} else {
-			result.SuccessCount++
-			InfoLog.Printf("✅ Строка %d: врач %s %s успешно добавлен (ID: %d)", idx+1, vet.FirstName, vet.LastName, models.GetVetIDAsIntOrZero(vet))
+			continue
+		}
+
+		update, err := i.planVeterinarianUpdate(existing, vet, active, specIDs, schedules, names)
+		if err != nil {
+			ErrorLog.Printf("❌ Строка %d: %v", rowNum, err)
+			return nil, err
 		}
+		if len(update.changes) == 0 {
+			plan.addSkip(rowNum, title, "Без изменений")
+			continue
+		}
+		plan.addUpdate(rowNum, title, update.changes, func(tx *sql.Tx) error {
+			return applyVeterinarianUpdate(tx, update)
+		})
 	}
 
-	InfoLog.Printf("🎯 Импорт завершен. Успешно: %d, Ошибок: %d, Всего строк: %d",
-		result.SuccessCount, result.ErrorCount, result.TotalRows)
-	return result, nil
+	i.reportProgress(plan.TotalRows, plan.TotalRows)
+	InfoLog.Printf("🎯 Проверка файла врачей завершена: создать %d, обновить %d, пропустить %d, ошибок %d",
+		plan.Count(models.ImportActionCreate), plan.Count(models.ImportActionUpdate),
+		plan.Count(models.ImportActionSkip), plan.Count(models.ImportActionError))
+	return plan, nil
 }
 
-// addVeterinarianWithRelations добавляет врача со специализациями, клиниками и расписанием
-func (i *CSVImporter) addVeterinarianWithRelations(vet *models.Veterinarian, record []string, specMap, clinicMap map[string]int, rowNum int, InfoLog, ErrorLog *log.Logger) error {
-	tx, err := i.db.GetDB().Begin()
+// PlanClinics проверяет файл с клиниками и составляет план импорта.
+// Колонки: Название;Город;Адрес;Телефон;Часы работы;Район;Станция метро;Широта;Долгота.
+// Если клиника с таким названием и адресом уже есть, у нее обновляются заполненные в файле поля
+func (i *CSVImporter) PlanClinics(file io.Reader, filename string, InfoLog, ErrorLog *log.Logger) (*ImportPlan, error) {
+	InfoLog.Printf("🔍 Проверка файла клиник: %s", filename)
+
+	records, err := i.readFile(file, filename, ClinicsSheetName)
 	if err != nil {
-		ErrorLog.Printf("❌ Строка %d: ошибка начала транзакции: %v", rowNum, err)
-		return err
+		ErrorLog.Printf("❌ Ошибка чтения файла: %v", err)
+		return nil, err
+	}
+	if len(records) == 0 {
+		ErrorLog.Printf("❌ Файл %s пустой", filename)
+		return nil, fmt.Errorf("файл пустой")
 	}
-	defer func() {
-		if err := tx.Rollback(); err != nil {
-			ErrorLog.Printf("❌ Строка %d: ошибка отката транзакции: %v", rowNum, err)
-		}
-	}()
 
-	// Добавляем врача
-	query := `INSERT INTO veterinarians (first_name, last_name, phone, email, experience_years, description, city_id, is_active) 
-              VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`
+	cityMap, cityNames, err := i.loadCities()
+	if err != nil {
+		return nil, err
+	}
 
-	err = tx.QueryRow(query, vet.FirstName, vet.LastName, vet.Phone, vet.Email,
-		vet.ExperienceYears, vet.Description, vet.CityID, vet.IsActive).Scan(&vet.ID)
+	clinics, err := i.db.GetAllClinics()
 	if err != nil {
-		ErrorLog.Printf("❌ Строка %d: ошибка добавления врача в БД: %v", rowNum, err)
-		return fmt.Errorf("ошибка добавления врача: %w", err)
+		ErrorLog.Printf("❌ Ошибка загрузки клиник: %v", err)
+		return nil, fmt.Errorf("ошибка загрузки клиник: %w", err)
+	}
+	existingClinics := make(map[string]*models.Clinic, len(clinics))
+	for _, clinic := range clinics {
+		existingClinics[clinicIdentityKey(clinic.Name, clinic.Address)] = clinic
 	}
 
-	InfoLog.Printf("👨‍⚕️ Строка %d: врач добавлен в БД с ID: %d", rowNum, models.GetVetIDAsIntOrZero(vet))
+	plan := newImportPlan(ImportTypeClinics, filename, records)
+	seen := make(map[string]int)
 
-	// Обрабатываем специализации (колонка 7)
-	if len(record) > 7 && record[7] != "" {
-		specNames := strings.Split(record[7], ",")
-		specCount := 0
-		for _, specName := range specNames {
-			specName = strings.TrimSpace(specName)
-			if specName == "" {
-				continue
-			}
+	for idx, record := range records {
+		if idx == 0 {
+			continue // Пропускаем заголовок
+		}
+		rowNum := idx + 1
+		if err := i.checkpoint(idx-1, plan.TotalRows); err != nil {
+			return nil, err
+		}
 
-			specID, exists := specMap[strings.ToLower(specName)]
-			if !exists {
-				ErrorLog.Printf("⚠️ Строка %d: специализация '%s' не найдена", rowNum, specName)
-				continue
-			}
+		if isEmptyRecord(record) {
+			plan.addSkip(rowNum, "", "Пустая строка")
+			continue
+		}
+		if len(record) < 3 {
+			plan.addError(rowNum, "", "all",
+				fmt.Sprintf("Недостаточно колонок (требуется минимум 3, получено %d)", len(record)))
+			continue
+		}
 
-			_, err = tx.Exec(
-				"INSERT INTO vet_specializations (vet_id, specialization_id) VALUES ($1, $2)",
-				vet.ID, specID,
-			)
-			if err != nil {
-				ErrorLog.Printf("⚠️ Строка %d: ошибка добавления специализации '%s': %v", rowNum, specName, err)
-			} else {
-				specCount++
-				InfoLog.Printf("🎯 Строка %d: добавлена специализация '%s'", rowNum, specName)
-			}
+		clinic := &models.Clinic{
+			Name:     strings.TrimSpace(record[0]),
+			Address:  strings.TrimSpace(record[2]),
+			IsActive: true,
 		}
-		InfoLog.Printf("✅ Строка %d: добавлено %d специализаций", rowNum, specCount)
+		if clinic.Name == "" || clinic.Address == "" {
+			plan.addError(rowNum, clinic.Name, "name", "Не указано название или адрес клиники")
+			continue
+		}
+
+		cityName := strings.TrimSpace(record[1])
+		cityID, exists := cityMap[strings.ToLower(cityName)]
+		if !exists {
+			plan.addError(rowNum, clinic.Name, "city", fmt.Sprintf("Город '%s' не найден в базе", cityName))
+			continue
+		}
+		clinic.CityID = sql.NullInt64{Int64: int64(cityID), Valid: true}
+
+		clinic.Phone = i.columnNullString(record, 3)
+		clinic.WorkingHours = i.columnNullString(record, 4)
+		clinic.District = i.columnNullString(record, 5)
+		clinic.MetroStation = i.columnNullString(record, 6)
+
+		latitude, longitude, err := i.parseClinicCoordinates(record)
+		if err != nil {
+			plan.addError(rowNum, clinic.Name, "coordinates", err.Error())
+			continue
+		}
+		clinic.Latitude = latitude
+		clinic.Longitude = longitude
+
+		key := clinicIdentityKey(clinic.Name, clinic.Address)
+		if firstRow, duplicate := seen[key]; duplicate {
+			plan.addError(rowNum, clinic.Name, "name", fmt.Sprintf("Повторяет строку %d", firstRow))
+			continue
+		}
+		seen[key] = rowNum
+
+		existing, exists := existingClinics[key]
+		if !exists {
+			plan.addChange(rowNum, models.ImportActionCreate, clinic.Name, "", func(tx *sql.Tx) error {
+				return insertClinic(tx, clinic)
+			})
+			continue
+		}
+
+		changes := diffClinic(existing, clinic, cityNames)
+		if len(changes) == 0 {
+			plan.addSkip(rowNum, clinic.Name, "Без изменений")
+			continue
+		}
+
+		clinic.ID = existing.ID
+		plan.addUpdate(rowNum, clinic.Name, changes, func(tx *sql.Tx) error {
+			return updateClinic(tx, clinic)
+		})
 	}
 
-	// Обрабатываем клиники и расписание (колонка 8) - С ОБРАБОТКОЙ ОШИБОК
-	if len(record) > 8 && record[8] != "" {
-		clinicSchedules := strings.Split(record[8], ";")
-		scheduleCount := 0
-		scheduleErrors := 0
+	i.reportProgress(plan.TotalRows, plan.TotalRows)
+	InfoLog.Printf("🎯 Проверка файла клиник завершена: создать %d, обновить %d, пропустить %d, ошибок %d",
+		plan.Count(models.ImportActionCreate), plan.Count(models.ImportActionUpdate),
+		plan.Count(models.ImportActionSkip), plan.Count(models.ImportActionError))
+	return plan, nil
+}
 
-		for _, clinicSchedule := range clinicSchedules {
-			parts := strings.Split(clinicSchedule, ":")
-			if len(parts) < 2 {
-				ErrorLog.Printf("⚠️ Строка %d: неверный формат расписания '%s'", rowNum, clinicSchedule)
-				scheduleErrors++
-				continue
-			}
+// parseClinicCoordinates читает широту и долготу (колонки 7 и 8); обе должны быть указаны или обе пустые
+func (i *CSVImporter) parseClinicCoordinates(record []string) (sql.NullFloat64, sql.NullFloat64, error) {
+	latStr := i.columnNullString(record, 7)
+	lonStr := i.columnNullString(record, 8)
 
-			clinicName := strings.TrimSpace(parts[0])
-			scheduleStr := strings.TrimSpace(parts[1])
+	if !latStr.Valid && !lonStr.Valid {
+		return sql.NullFloat64{}, sql.NullFloat64{}, nil
+	}
+	if !latStr.Valid || !lonStr.Valid {
+		return sql.NullFloat64{}, sql.NullFloat64{}, fmt.Errorf("нужно указать и широту, и долготу")
+	}
 
-			clinicID, exists := clinicMap[strings.ToLower(clinicName)]
-			if !exists {
-				ErrorLog.Printf("⚠️ Строка %d: клиника '%s' не найдена", rowNum, clinicName)
-				scheduleErrors++
-				continue
-			}
+	latitude, err := models.ParseCoordinate(latStr.String)
+	if err != nil {
+		return sql.NullFloat64{}, sql.NullFloat64{}, fmt.Errorf("неверная широта '%s'", latStr.String)
+	}
+	longitude, err := models.ParseCoordinate(lonStr.String)
+	if err != nil {
+		return sql.NullFloat64{}, sql.NullFloat64{}, fmt.Errorf("неверная долгота '%s'", lonStr.String)
+	}
+	if err := models.ValidateCoordinates(latitude, longitude); err != nil {
+		return sql.NullFloat64{}, sql.NullFloat64{}, err
+	}
 
-			InfoLog.Printf("🏥 Строка %d: обработка клиники '%s' (ID: %d)", rowNum, clinicName, clinicID)
-
-			// Парсим расписание
-			schedules := i.parseSchedule(scheduleStr, models.GetVetIDAsIntOrZero(vet), clinicID)
-			for _, schedule := range schedules {
-				_, err = tx.Exec(
-					`INSERT INTO schedules (vet_id, clinic_id, day_of_week, start_time, end_time, is_available) 
-                     VALUES ($1, $2, $3, $4, $5, $6)`,
-					schedule.VetID, schedule.ClinicID, schedule.DayOfWeek, schedule.StartTime, schedule.EndTime, schedule.IsAvailable,
-				)
-				if err != nil {
-					ErrorLog.Printf("⚠️ Строка %d: ошибка добавления расписания: %v", rowNum, err)
-					scheduleErrors++
-				} else {
-					scheduleCount++
-				}
-			}
+	return sql.NullFloat64{Float64: latitude, Valid: true}, sql.NullFloat64{Float64: longitude, Valid: true}, nil
+}
+
+// PlanCities проверяет файл с городами и составляет план импорта. Колонки: Название;Регион.
+// Для уже существующего города обновляется регион, если он указан и отличается
+func (i *CSVImporter) PlanCities(file io.Reader, filename string, InfoLog, ErrorLog *log.Logger) (*ImportPlan, error) {
+	InfoLog.Printf("🔍 Проверка файла городов: %s", filename)
+
+	records, err := i.readFile(file, filename, CitiesSheetName)
+	if err != nil {
+		ErrorLog.Printf("❌ Ошибка чтения файла: %v", err)
+		return nil, err
+	}
+	if len(records) == 0 {
+		ErrorLog.Printf("❌ Файл %s пустой", filename)
+		return nil, fmt.Errorf("файл пустой")
+	}
+
+	cities, err := i.db.GetAllCities()
+	if err != nil {
+		ErrorLog.Printf("❌ Ошибка загрузки городов: %v", err)
+		return nil, fmt.Errorf("ошибка загрузки городов: %w", err)
+	}
+	existingCities := make(map[string]*models.City, len(cities))
+	for _, city := range cities {
+		existingCities[strings.ToLower(city.Name)] = city
+	}
+
+	plan := newImportPlan(ImportTypeCities, filename, records)
+	seen := make(map[string]int)
+
+	for idx, record := range records {
+		if idx == 0 {
+			continue // Пропускаем заголовок
+		}
+		rowNum := idx + 1
+		if err := i.checkpoint(idx-1, plan.TotalRows); err != nil {
+			return nil, err
+		}
+
+		if isEmptyRecord(record) {
+			plan.addSkip(rowNum, "", "Пустая строка")
+			continue
+		}
+
+		city := &models.City{Name: strings.TrimSpace(record[0])}
+		if len(record) > 1 {
+			city.Region = strings.TrimSpace(record[1])
+		}
+		if city.Name == "" {
+			plan.addError(rowNum, "", "name", "Не указано название города")
+			continue
 		}
-		InfoLog.Printf("📅 Строка %d: добавлено %d записей расписания, ошибок: %d", rowNum, scheduleCount, scheduleErrors)
 
-		// НЕ ПРЕРЫВАЕМ ИМПОРТ ИЗ-ЗА ОШИБОК РАСПИСАНИЯ
-		if scheduleErrors > 0 {
-			InfoLog.Printf("⚠️ Строка %d: есть ошибки расписания, но врач добавлен", rowNum)
+		key := strings.ToLower(city.Name)
+		if firstRow, duplicate := seen[key]; duplicate {
+			plan.addError(rowNum, city.Name, "name", fmt.Sprintf("Повторяет строку %d", firstRow))
+			continue
+		}
+		seen[key] = rowNum
+
+		existing, exists := existingCities[key]
+		if !exists {
+			plan.addChange(rowNum, models.ImportActionCreate, city.Name, "", func(tx *sql.Tx) error {
+				return tx.QueryRow("INSERT INTO cities (name, region) VALUES ($1, $2) RETURNING id",
+					city.Name, city.Region).Scan(&city.ID)
+			})
+			continue
+		}
+
+		if city.Region == "" || city.Region == existing.Region {
+			plan.addSkip(rowNum, city.Name, "Без изменений")
+			continue
 		}
+
+		city.ID = existing.ID
+		changes := []string{fmt.Sprintf("регион: %s → %s", formatChangeValue(existing.Region), city.Region)}
+		plan.addUpdate(rowNum, city.Name, changes, func(tx *sql.Tx) error {
+			_, err := tx.Exec("UPDATE cities SET region = $1 WHERE id = $2", city.Region, city.ID)
+			return err
+		})
 	}
 
-	err = tx.Commit()
+	i.reportProgress(plan.TotalRows, plan.TotalRows)
+	InfoLog.Printf("🎯 Проверка файла городов завершена: создать %d, обновить %d, пропустить %d, ошибок %d",
+		plan.Count(models.ImportActionCreate), plan.Count(models.ImportActionUpdate),
+		plan.Count(models.ImportActionSkip), plan.Count(models.ImportActionError))
+	return plan, nil
+}
+
+// insertVeterinarian добавляет врача со специализациями, клиниками и расписанием в транзакции импорта
+func insertVeterinarian(tx *sql.Tx, vet *models.Veterinarian, specIDs []int, schedules []models.Schedule) error {
+	query := `INSERT INTO veterinarians (first_name, last_name, patronymic, phone, email, experience_years, description, city_id, is_active) 
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`
+
+	err := tx.QueryRow(query, vet.FirstName, vet.LastName, vet.Patronymic, vet.Phone, vet.Email,
+		vet.ExperienceYears, vet.Description, vet.CityID, vet.IsActive).Scan(&vet.ID)
 	if err != nil {
-		ErrorLog.Printf("❌ Строка %d: ошибка коммита транзакции: %v", rowNum, err)
+		return fmt.Errorf("ошибка добавления врача: %w", err)
+	}
+
+	for _, specID := range specIDs {
+		if _, err := tx.Exec(
+			"INSERT INTO vet_specializations (vet_id, specialization_id) VALUES ($1, $2)",
+			vet.ID, specID,
+		); err != nil {
+			return fmt.Errorf("ошибка добавления специализации: %w", err)
+		}
+	}
+
+	vetID := models.GetVetIDAsIntOrZero(vet)
+	var clinicIDs []int
+	seen := make(map[int]bool)
+	for _, schedule := range schedules {
+		if !seen[schedule.ClinicID] {
+			seen[schedule.ClinicID] = true
+			clinicIDs = append(clinicIDs, schedule.ClinicID)
+		}
+	}
+	if err := insertVetClinics(tx, vetID, clinicIDs); err != nil {
 		return err
 	}
+	return insertSchedules(tx, vetID, schedules)
+}
+
+// insertVetClinics привязывает врача к клиникам; существующие связи не дублируются
+func insertVetClinics(tx *sql.Tx, vetID int, clinicIDs []int) error {
+	for _, clinicID := range clinicIDs {
+		if _, err := tx.Exec(
+			"INSERT INTO vet_clinics (vet_id, clinic_id) VALUES ($1, $2) ON CONFLICT (vet_id, clinic_id) DO NOTHING",
+			vetID, clinicID,
+		); err != nil {
+			return fmt.Errorf("ошибка привязки врача к клинике: %w", err)
+		}
+	}
+	return nil
+}
 
-	InfoLog.Printf("💾 Строка %d: транзакция успешно завершена", rowNum)
+// insertSchedules добавляет приемы врача
+func insertSchedules(tx *sql.Tx, vetID int, schedules []models.Schedule) error {
+	for _, schedule := range schedules {
+		if _, err := tx.Exec(
+			`INSERT INTO schedules (vet_id, clinic_id, day_of_week, start_time, end_time, is_available) 
+             VALUES ($1, $2, $3, $4, $5, $6)`,
+			vetID, schedule.ClinicID, schedule.DayOfWeek, schedule.StartTime, schedule.EndTime, schedule.IsAvailable,
+		); err != nil {
+			return fmt.Errorf("ошибка добавления расписания: %w", err)
+		}
+	}
 	return nil
 }
 
-// parseSchedule парсит строку расписания формата "Пн:9-18,Ср:9-18,Пт:14-20"
-func (i *CSVImporter) parseSchedule(scheduleStr string, vetID, clinicID int) []models.Schedule {
+// insertClinic добавляет клинику в транзакции импорта
+func insertClinic(tx *sql.Tx, clinic *models.Clinic) error {
+	query := `INSERT INTO clinics (name, address, phone, working_hours, is_active, city_id, district, metro_station, latitude, longitude)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id`
+
+	return tx.QueryRow(query, clinic.Name, clinic.Address, clinic.Phone, clinic.WorkingHours,
+		clinic.IsActive, clinic.CityID, clinic.District, clinic.MetroStation,
+		clinic.Latitude, clinic.Longitude).Scan(&clinic.ID)
+}
+
+// loadCities загружает города: ID по названию без учета регистра и названия по ID
+func (i *CSVImporter) loadCities() (map[string]int, map[int]string, error) {
+	cities, err := i.db.GetAllCities()
+	if err != nil {
+		return nil, nil, fmt.Errorf("ошибка загрузки городов: %w", err)
+	}
+
+	cityMap := make(map[string]int, len(cities))
+	cityNames := make(map[int]string, len(cities))
+	for _, city := range cities {
+		cityMap[strings.ToLower(city.Name)] = city.ID
+		cityNames[city.ID] = city.Name
+	}
+	return cityMap, cityNames, nil
+}
+
+// parseSpecializations находит ID специализаций из списка через запятую
+func (i *CSVImporter) parseSpecializations(value string, specMap map[string]int) ([]int, error) {
+	var specIDs []int
+	seen := make(map[int]bool)
+	for _, specName := range strings.Split(value, ",") {
+		specName = strings.TrimSpace(specName)
+		if specName == "" {
+			continue
+		}
+
+		specID, exists := specMap[strings.ToLower(specName)]
+		if !exists {
+			return nil, fmt.Errorf("Специализация '%s' не найдена", specName)
+		}
+		if !seen[specID] {
+			seen[specID] = true
+			specIDs = append(specIDs, specID)
+		}
+	}
+	return specIDs, nil
+}
+
+// parseClinicSchedules разбирает колонку КлиникиИРасписание формата
+// "Клиника:Пн:9-18,Ср:9-18;Другая клиника:Вт:10-19"
+func (i *CSVImporter) parseClinicSchedules(value string, clinicMap map[string]int) ([]models.Schedule, error) {
+	var schedules []models.Schedule
+	for _, clinicSchedule := range strings.Split(value, ";") {
+		if strings.TrimSpace(clinicSchedule) == "" {
+			continue
+		}
+
+		// Двоеточия дальше относятся к дням и времени ("Клиника:пн:9:30-18")
+		parts := strings.SplitN(clinicSchedule, ":", 2)
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("Неверный формат расписания '%s', ожидается Клиника:День:Часы", strings.TrimSpace(clinicSchedule))
+		}
+
+		clinicName := strings.TrimSpace(parts[0])
+		clinicID, exists := clinicMap[strings.ToLower(clinicName)]
+		if !exists {
+			return nil, fmt.Errorf("Клиника '%s' не найдена", clinicName)
+		}
+
+		clinicSchedules, err := i.parseSchedule(strings.TrimSpace(parts[1]), 0, clinicID)
+		if err != nil {
+			return nil, fmt.Errorf("Расписание в клинике '%s': %w", clinicName, err)
+		}
+		schedules = append(schedules, clinicSchedules...)
+	}
+	return schedules, nil
+}
+
+// parseSchedule парсит строку расписания формата "Пн:9-18,Ср:9:30-18,Пт:14-20".
+// Интервал с пометкой DisabledScheduleMark ("Пт:14-20(выкл)") добавляется выключенным
+func (i *CSVImporter) parseSchedule(scheduleStr string, vetID, clinicID int) ([]models.Schedule, error) {
 	var schedules []models.Schedule
 
 	dayMap := map[string]int{
@@ -333,22 +652,31 @@ func (i *CSVImporter) parseSchedule(scheduleStr string, vetID, clinicID int) []m
 
 	days := strings.Split(scheduleStr, ",")
 	for _, day := range days {
-		parts := strings.Split(day, ":")
-		if len(parts) != 2 {
+		if strings.TrimSpace(day) == "" {
 			continue
 		}
 
+		parts := strings.SplitN(day, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("неверный формат '%s', ожидается День:Часы", strings.TrimSpace(day))
+		}
+
 		dayName := strings.ToLower(strings.TrimSpace(parts[0]))
 		timeRange := strings.TrimSpace(parts[1])
+		available := true
+		if strings.HasSuffix(strings.ToLower(timeRange), DisabledScheduleMark) {
+			available = false
+			timeRange = strings.TrimSpace(timeRange[:len(timeRange)-len(DisabledScheduleMark)])
+		}
 
 		dayOfWeek, exists := dayMap[dayName]
 		if !exists {
-			continue
+			return nil, fmt.Errorf("неизвестный день недели '%s'", strings.TrimSpace(parts[0]))
 		}
 
 		timeParts := strings.Split(timeRange, "-")
 		if len(timeParts) != 2 {
-			continue
+			return nil, fmt.Errorf("неверные часы '%s', ожидается например 9-18", timeRange)
 		}
 
 		startTime, endTime := strings.TrimSpace(timeParts[0]), strings.TrimSpace(timeParts[1])
@@ -367,31 +695,77 @@ func (i *CSVImporter) parseSchedule(scheduleStr string, vetID, clinicID int) []m
 			DayOfWeek:   dayOfWeek,
 			StartTime:   startTime,
 			EndTime:     endTime,
-			IsAvailable: true,
+			IsAvailable: available,
 			CreatedAt:   time.Now(),
 		}
+		if err := schedule.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", strings.TrimSpace(day), err)
+		}
 		schedules = append(schedules, schedule)
 	}
 
-	return schedules
+	return schedules, nil
+}
+
+// parseActiveFlag разбирает колонку Активен: "да" или "нет"; пустая ячейка дает NULL
+func parseActiveFlag(value sql.NullString) (sql.NullBool, error) {
+	if !value.Valid {
+		return sql.NullBool{}, nil
+	}
+	switch strings.ToLower(value.String) {
+	case "да", "1", "true", "yes":
+		return sql.NullBool{Bool: true, Valid: true}, nil
+	case "нет", "0", "false", "no":
+		return sql.NullBool{Bool: false, Valid: true}, nil
+	}
+	return sql.NullBool{}, fmt.Errorf("Неверное значение активности '%s', ожидается да или нет", value.String)
+}
+
+// vetIdentityKey ключ врача по ограничению unique_vet_identity
+func vetIdentityKey(firstName, lastName, phone string) string {
+	return strings.Join([]string{strings.TrimSpace(firstName), strings.TrimSpace(lastName), strings.TrimSpace(phone)}, "\x00")
 }
 
-// Вспомогательные методы (остаются без изменений)
-func (i *CSVImporter) readFile(file io.Reader, filename string) ([][]string, error) {
+// clinicIdentityKey ключ клиники по ограничению unique_clinic_address
+func clinicIdentityKey(name, address string) string {
+	return strings.TrimSpace(name) + "\x00" + strings.TrimSpace(address)
+}
+
+// isEmptyRecord проверяет, что в строке нет ни одного заполненного значения
+func isEmptyRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// readFile читает CSV или Excel. В книге Excel берется лист sheetName,
+// а если его нет - первый лист. Колонка "Статус импорта" не читается
+func (i *CSVImporter) readFile(file io.Reader, filename, sheetName string) ([][]string, error) {
+	var records [][]string
+	var err error
 	if strings.HasSuffix(strings.ToLower(filename), ".xlsx") {
-		return i.readExcel(file)
+		records, err = i.readExcel(file, sheetName)
+	} else {
+		records, err = i.readCSV(file)
 	}
-	return i.readCSV(file)
+	if err != nil {
+		return nil, err
+	}
+	return dropStatusColumn(records), nil
 }
 
 func (i *CSVImporter) readCSV(file io.Reader) ([][]string, error) {
 	reader := csv.NewReader(file)
 	reader.Comma = ';'
 	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1 // Число колонок проверяется построчно
 	return reader.ReadAll()
 }
 
-func (i *CSVImporter) readExcel(file io.Reader) ([][]string, error) {
+func (i *CSVImporter) readExcel(file io.Reader, sheetName string) ([][]string, error) {
 	f, err := excelize.OpenReader(file)
 	if err != nil {
 		return nil, err
@@ -403,7 +777,15 @@ func (i *CSVImporter) readExcel(file io.Reader) ([][]string, error) {
 		return nil, fmt.Errorf("нет листов в файле")
 	}
 
-	rows, err := f.GetRows(sheets[0])
+	sheet := sheets[0]
+	for _, name := range sheets {
+		if strings.EqualFold(strings.TrimSpace(name), sheetName) {
+			sheet = name
+			break
+		}
+	}
+
+	rows, err := f.GetRows(sheet)
 	if err != nil {
 		return nil, err
 	}
@@ -411,6 +793,14 @@ func (i *CSVImporter) readExcel(file io.Reader) ([][]string, error) {
 	return rows, nil
 }
 
+// columnNullString возвращает значение колонки или NULL, если колонки нет или она пустая
+func (i *CSVImporter) columnNullString(record []string, index int) sql.NullString {
+	if index >= len(record) {
+		return sql.NullString{Valid: false}
+	}
+	return i.parseNullString(record[index])
+}
+
 func (i *CSVImporter) parseNullString(s string) sql.NullString {
 	s = strings.TrimSpace(s)
 	if s == "" || s == "NULL" || s == "null" {
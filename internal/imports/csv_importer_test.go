@@ -0,0 +1,382 @@
+package imports
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"io"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/drerr0r/vetbot/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+// stubDirectory справочник для проверки планов импорта; запись в базу в этих тестах не нужна
+type stubDirectory struct {
+	cities          []*models.City
+	specializations []*models.Specialization
+	clinics         []*models.Clinic
+	vets            []*models.Veterinarian
+	vetSpecs        map[int][]*models.Specialization
+	vetClinics      map[int][]*models.Clinic
+	schedules       map[int][]*models.Schedule
+}
+
+func (s *stubDirectory) GetDB() *sql.DB { return nil }
+func (s *stubDirectory) GetAllCities() ([]*models.City, error) {
+	return s.cities, nil
+}
+func (s *stubDirectory) GetAllSpecializations() ([]*models.Specialization, error) {
+	return s.specializations, nil
+}
+func (s *stubDirectory) GetAllClinics() ([]*models.Clinic, error) {
+	return s.clinics, nil
+}
+func (s *stubDirectory) GetAllVeterinarians() ([]*models.Veterinarian, error) {
+	return s.vets, nil
+}
+func (s *stubDirectory) GetSpecializationsByVetID(vetID int) ([]*models.Specialization, error) {
+	return s.vetSpecs[vetID], nil
+}
+func (s *stubDirectory) GetClinicsByVetID(vetID int) ([]*models.Clinic, error) {
+	return s.vetClinics[vetID], nil
+}
+func (s *stubDirectory) GetAllSchedulesByVetID(vetID int) ([]*models.Schedule, error) {
+	return s.schedules[vetID], nil
+}
+
+// newStubDirectory справочник с врачом Иваном Петровым: терапевт, принимает в "ВетКлиника Центр" по понедельникам
+func newStubDirectory() *stubDirectory {
+	clinic := &models.Clinic{
+		ID: 1, Name: "ВетКлиника Центр", Address: "ул. Ленина, 1", CityID: sql.NullInt64{Int64: 1, Valid: true},
+		Phone:    sql.NullString{String: "+74950000001", Valid: true},
+		Latitude: sql.NullFloat64{Float64: 55.75, Valid: true}, Longitude: sql.NullFloat64{Float64: 37.61, Valid: true},
+	}
+	therapist := &models.Specialization{ID: 1, Name: "Терапевт"}
+
+	return &stubDirectory{
+		cities: []*models.City{
+			{ID: 1, Name: "Москва", Region: "Московская область"},
+			{ID: 2, Name: "Химки", Region: "Московская область"},
+		},
+		specializations: []*models.Specialization{therapist, {ID: 2, Name: "Хирург"}},
+		clinics:         []*models.Clinic{clinic, {ID: 2, Name: "ВетКлиника Север", Address: "ул. Лесная, 7", CityID: sql.NullInt64{Int64: 1, Valid: true}}},
+		vets: []*models.Veterinarian{{
+			ID: sql.NullInt64{Int64: 1, Valid: true}, FirstName: "Иван", LastName: "Петров", Phone: "+79161234567",
+			ExperienceYears: sql.NullInt64{Int64: 3, Valid: true}, CityID: sql.NullInt64{Int64: 1, Valid: true},
+		}},
+		vetSpecs:   map[int][]*models.Specialization{1: {therapist}},
+		vetClinics: map[int][]*models.Clinic{1: {clinic}},
+		schedules: map[int][]*models.Schedule{1: {
+			{ID: 10, VetID: 1, ClinicID: 1, DayOfWeek: 1, StartTime: "09:00", EndTime: "18:00", IsAvailable: true},
+			{ID: 11, VetID: 1, ClinicID: 1, DayOfWeek: 3, StartTime: "09:00", EndTime: "18:00", IsAvailable: true},
+		}},
+	}
+}
+
+var discardLog = log.New(io.Discard, "", 0)
+
+// planActions возвращает действия плана по номерам строк
+func planActions(plan *ImportPlan) map[int]models.ImportAction {
+	actions := make(map[int]models.ImportAction)
+	for _, row := range plan.Rows {
+		actions[row.RowNumber] = row.Action
+	}
+	return actions
+}
+
+func TestParseSchedule(t *testing.T) {
+	importer := NewCSVImporter(nil)
+
+	schedules, err := importer.parseSchedule("пн:9-18, Ср:9:30-18:15", 7, 3)
+	require.NoError(t, err)
+	require.Len(t, schedules, 2)
+	assert.Equal(t, 1, schedules[0].DayOfWeek)
+	assert.Equal(t, "9:00", schedules[0].StartTime)
+	assert.Equal(t, "18:00", schedules[0].EndTime)
+	assert.Equal(t, 3, schedules[1].DayOfWeek)
+	assert.Equal(t, "9:30", schedules[1].StartTime)
+	assert.Equal(t, "18:15", schedules[1].EndTime)
+	assert.Equal(t, 7, schedules[1].VetID)
+	assert.Equal(t, 3, schedules[1].ClinicID)
+	assert.True(t, schedules[1].IsAvailable)
+
+	disabled, err := importer.parseSchedule("пт:14-20 (ВЫКЛ)", 7, 3)
+	require.NoError(t, err)
+	require.Len(t, disabled, 1)
+	assert.Equal(t, "20:00", disabled[0].EndTime)
+	assert.False(t, disabled[0].IsAvailable)
+
+	for _, invalid := range []string{"xx:9-18", "пт:9", "пн", "пн:25-26"} {
+		_, err := importer.parseSchedule(invalid, 7, 3)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestPlanVeterinarians(t *testing.T) {
+	csvData := strings.Join([]string{
+		"Имя;Фамилия;Телефон;Email;ОпытРаботы;Описание;Город;Специализации;КлиникиИРасписание",
+		"Анна;Смирнова;+79990000002;;5;;Москва;Терапевт, хирург;ВетКлиника Центр:пн:9-18,ср:9:30-18",
+		"Иван;Петров;+79161234567;;;;Москва;;",
+		"Олег;Сидоров;+79990000003;;пять;;;;",
+		"Мария;Иванова;+79990000004;;;;Казань;;",
+		"Петр;Орлов;+79990000005;;;;;Стоматолог;",
+		"Нина;Белова;+79990000006;;;;;;Айболит:пн:9-18",
+		";;;;;;;;",
+		"Анна;Смирнова;+79990000002;;;;;;",
+		"Сергей;;+79990000007",
+	}, "\n")
+
+	plan, err := NewCSVImporter(newStubDirectory()).PlanVeterinarians(strings.NewReader(csvData), "врачи.csv", discardLog, discardLog)
+	require.NoError(t, err)
+
+	assert.Equal(t, 9, plan.TotalRows)
+	assert.Equal(t, map[int]models.ImportAction{
+		2:  models.ImportActionCreate,
+		3:  models.ImportActionSkip, // уже есть в справочнике, изменений нет
+		4:  models.ImportActionError,
+		5:  models.ImportActionError,
+		6:  models.ImportActionError,
+		7:  models.ImportActionError,
+		8:  models.ImportActionSkip, // пустая строка
+		9:  models.ImportActionError,
+		10: models.ImportActionError,
+	}, planActions(plan))
+	assert.True(t, plan.HasChanges())
+
+	result := plan.Result()
+	assert.Equal(t, 1, result.CreatedCount)
+	assert.Equal(t, 2, result.SkippedCount)
+	assert.Equal(t, 6, result.ErrorCount)
+	assert.Equal(t, "Город 'Казань' не найден в базе", result.Errors[1].Message)
+	assert.Equal(t, "Повторяет строку 2", result.Errors[4].Message)
+}
+
+func TestPlanVeterinariansUpdatesExisting(t *testing.T) {
+	csvData := strings.Join([]string{
+		"Имя;Фамилия;Телефон;Email;ОпытРаботы;Описание;Город;Специализации;КлиникиИРасписание",
+		"Иван;Петров;+79161234567;ivan@vet.ru;5;;Химки;Хирург, терапевт;\"ВетКлиника Центр:пн:10-19,ср:9-18;ВетКлиника Север:вт:9-15\"",
+	}, "\n")
+
+	plan, err := NewCSVImporter(newStubDirectory()).PlanVeterinarians(strings.NewReader(csvData), "врачи.csv", discardLog, discardLog)
+	require.NoError(t, err)
+
+	updates := plan.RowsWith(models.ImportActionUpdate)
+	require.Len(t, updates, 1)
+	assert.Equal(t, []string{
+		"email: — → ivan@vet.ru",
+		"опыт: 3 → 5",
+		"город: Москва → Химки",
+		"специализации: +Хирург",
+		"клиника: +ВетКлиника Север",
+		"расписание ВетКлиника Центр пн: 9:00-18:00 → 10:00-19:00",
+		"расписание ВетКлиника Север вт: — → 9:00-15:00",
+	}, updates[0].Changes)
+	assert.Equal(t, 1, plan.Result().UpdatedCount)
+}
+
+func TestPlanClinicsUpdatesFilledFields(t *testing.T) {
+	clinicsData := strings.Join([]string{
+		"Название;Город;Адрес;Телефон;Часы работы;Район;Станция метро;Широта;Долгота",
+		"ВетКлиника Центр;Москва;ул. Ленина, 1;+74950000002;Пн-Пт 9-21;;;;",
+	}, "\n")
+
+	plan, err := NewCSVImporter(newStubDirectory()).PlanClinics(strings.NewReader(clinicsData), "клиники.csv", discardLog, discardLog)
+	require.NoError(t, err)
+
+	updates := plan.RowsWith(models.ImportActionUpdate)
+	require.Len(t, updates, 1)
+	// Пустые координаты в файле не стирают сохраненные
+	assert.Equal(t, []string{"телефон: +74950000001 → +74950000002", "часы работы: — → Пн-Пт 9-21"}, updates[0].Changes)
+}
+
+func TestPlanClinicsAndCities(t *testing.T) {
+	importer := NewCSVImporter(newStubDirectory())
+
+	clinicsData := strings.Join([]string{
+		"Название;Город;Адрес;Телефон;Часы работы;Район;Станция метро;Широта;Долгота",
+		"ВетКлиника Центр;Москва;ул. Ленина, 1;;;;;55.75;37.61",
+		"ВетКлиника Центр;Москва;ул. Ленина, 1;;;;;55.76;37.62",
+		"Айболит;Москва;ул. Мира, 5;;;;;;",
+		"Айболит;Москва;ул. Мира, 5;;;;;;",
+		"Лапки;Казань;ул. Баумана, 2;;;;;;",
+		"Хвост;Москва;ул. Тверская, 3;;;;;55.75;",
+	}, "\n")
+	plan, err := importer.PlanClinics(strings.NewReader(clinicsData), "клиники.csv", discardLog, discardLog)
+	require.NoError(t, err)
+	assert.Equal(t, map[int]models.ImportAction{
+		2: models.ImportActionSkip,   // координаты не изменились
+		3: models.ImportActionError,  // повтор строки 2
+		4: models.ImportActionCreate, // новая клиника
+		5: models.ImportActionError,
+		6: models.ImportActionError,
+		7: models.ImportActionError,
+	}, planActions(plan))
+
+	citiesData := "Название;Регион\nМосква;Центральный ФО\nмосква;\nКазань;Татарстан\n;Регион\n"
+	plan, err = importer.PlanCities(strings.NewReader(citiesData), "города.csv", discardLog, discardLog)
+	require.NoError(t, err)
+	assert.Equal(t, map[int]models.ImportAction{
+		2: models.ImportActionUpdate,
+		3: models.ImportActionError,
+		4: models.ImportActionCreate,
+		5: models.ImportActionError,
+	}, planActions(plan))
+	assert.Equal(t, "регион: Московская область → Центральный ФО", plan.RowsWith(models.ImportActionUpdate)[0].Message)
+}
+
+func TestPlanReportsProgressAndStopsOnCancel(t *testing.T) {
+	citiesData := "Название;Регион\nКазань;Татарстан\nТверь;Тверская область\n"
+
+	var progress [][2]int
+	_, err := NewCSVImporter(newStubDirectory()).
+		WithProgress(func(done, total int) { progress = append(progress, [2]int{done, total}) }).
+		PlanCities(strings.NewReader(citiesData), "города.csv", discardLog, discardLog)
+	require.NoError(t, err)
+	assert.Equal(t, [][2]int{{0, 2}, {1, 2}, {2, 2}}, progress)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = NewCSVImporter(newStubDirectory()).WithContext(ctx).
+		PlanCities(strings.NewReader(citiesData), "города.csv", discardLog, discardLog)
+	assert.ErrorIs(t, err, ErrImportCancelled)
+}
+
+func TestAnnotatedWorkbook(t *testing.T) {
+	csvData := strings.Join([]string{
+		"Имя;Фамилия;Телефон;Email;ОпытРаботы;Описание;Город;Специализации;КлиникиИРасписание",
+		"Анна;Смирнова;+79990000002;;5;;Москва;Терапевт;",
+		"Олег;Сидоров;+79990000003;;пять;;;;",
+		"Сергей;;+79990000007",
+	}, "\n")
+
+	plan, err := NewCSVImporter(newStubDirectory()).PlanVeterinarians(strings.NewReader(csvData), "врачи.csv", discardLog, discardLog)
+	require.NoError(t, err)
+	assert.Equal(t, "врачи_проверка.xlsx", plan.AnnotatedFileName())
+
+	data, err := plan.AnnotatedWorkbook()
+	require.NoError(t, err)
+
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	defer f.Close()
+
+	rows, err := f.GetRows(VetsSheetName)
+	require.NoError(t, err)
+	require.Len(t, rows, 4)
+	assert.Equal(t, statusColumnHeader, rows[0][12])
+	assert.Equal(t, "OK: новая запись", rows[1][12])
+	assert.Equal(t, "Ошибка: Неверный опыт работы 'пять', ожидается число лет", rows[2][12])
+	assert.Equal(t, "Ошибка: Не указаны обязательные поля: имя, фамилия и телефон", rows[3][12])
+
+	// Подсвечена только ячейка с ошибкой
+	assert.Equal(t, []string{fillError}, cellFill(t, f, "E3"))
+	assert.Empty(t, cellFill(t, f, "A3"))
+	assert.Equal(t, []string{fillError}, cellFill(t, f, "B4"))
+	assert.Empty(t, cellFill(t, f, "C4"))
+	assert.Equal(t, []string{fillCreate}, cellFill(t, f, "M2"))
+
+	// Книгу можно загрузить снова: колонка статуса не мешает импорту
+	plan, err = NewCSVImporter(newStubDirectory()).PlanVeterinarians(bytes.NewReader(data), plan.AnnotatedFileName(), discardLog, discardLog)
+	require.NoError(t, err)
+	assert.Equal(t, 1, plan.Count(models.ImportActionCreate))
+	assert.Equal(t, 2, plan.Count(models.ImportActionError))
+}
+
+func TestAnnotatedWorkbookReimportOldLayouts(t *testing.T) {
+	// Файлы старых шаблонов короче нынешних: статус не должен попасть в колонку, которую читает импорт
+	files := []struct {
+		name   string
+		data   string
+		plan   func(importer *CSVImporter, file io.Reader, name string) (*ImportPlan, error)
+		sheet  string
+		status int
+	}{
+		{
+			name: "врачи.csv",
+			data: "Имя;Фамилия;Телефон;Email;ОпытРаботы;Описание;Город;Специализации;КлиникиИРасписание;ПримерыЗаполнения\n" +
+				"Иван;Петров;+79161234567;;3;;Москва;Терапевт;ВетКлиника Центр:пн:9-18,ср:9-18;",
+			plan: func(importer *CSVImporter, file io.Reader, name string) (*ImportPlan, error) {
+				return importer.PlanVeterinarians(file, name, discardLog, discardLog)
+			},
+			sheet:  VetsSheetName,
+			status: 12,
+		},
+		{
+			name: "клиники.csv",
+			data: "Название;Город;Адрес;Телефон;Часы работы;Район;Станция метро\n" +
+				"ВетКлиника Центр;Москва;ул. Ленина, 1;+74950000001;;;",
+			plan: func(importer *CSVImporter, file io.Reader, name string) (*ImportPlan, error) {
+				return importer.PlanClinics(file, name, discardLog, discardLog)
+			},
+			sheet:  ClinicsSheetName,
+			status: 9,
+		},
+	}
+
+	for _, file := range files {
+		t.Run(file.name, func(t *testing.T) {
+			plan, err := file.plan(NewCSVImporter(newStubDirectory()), strings.NewReader(file.data), file.name)
+			require.NoError(t, err)
+			require.Equal(t, models.ImportActionSkip, plan.Rows[0].Action, plan.Rows[0].Message)
+
+			data, err := plan.AnnotatedWorkbook()
+			require.NoError(t, err)
+			f, err := excelize.OpenReader(bytes.NewReader(data))
+			require.NoError(t, err)
+			defer f.Close()
+			rows, err := f.GetRows(file.sheet)
+			require.NoError(t, err)
+			require.Len(t, rows[0], file.status+1)
+			assert.Equal(t, statusColumnHeader, rows[0][file.status])
+
+			// Исправленная книга загружается снова без изменений, а ее статус снова встает на свое место
+			plan, err = file.plan(NewCSVImporter(newStubDirectory()), bytes.NewReader(data), plan.AnnotatedFileName())
+			require.NoError(t, err)
+			require.Len(t, plan.Rows, 1)
+			assert.Equal(t, models.ImportActionSkip, plan.Rows[0].Action, plan.Rows[0].Message)
+
+			data, err = plan.AnnotatedWorkbook()
+			require.NoError(t, err)
+			again, err := excelize.OpenReader(bytes.NewReader(data))
+			require.NoError(t, err)
+			defer again.Close()
+			rows, err = again.GetRows(file.sheet)
+			require.NoError(t, err)
+			assert.Len(t, rows[0], file.status+1)
+		})
+	}
+}
+
+// cellFill возвращает цвет заливки ячейки листа врачей
+func cellFill(t *testing.T, f *excelize.File, cell string) []string {
+	styleID, err := f.GetCellStyle(VetsSheetName, cell)
+	require.NoError(t, err)
+	style, err := f.GetStyle(styleID)
+	require.NoError(t, err)
+	return style.Fill.Color
+}
+
+func TestReadExcelPrefersNamedSheet(t *testing.T) {
+	f := excelize.NewFile()
+	_, err := f.NewSheet(ClinicsSheetName)
+	require.NoError(t, err)
+	require.NoError(t, f.SetCellStr("Sheet1", "A1", "первый лист"))
+	require.NoError(t, f.SetCellStr(ClinicsSheetName, "A1", "клиники"))
+	buf, err := f.WriteToBuffer()
+	require.NoError(t, err)
+
+	importer := NewCSVImporter(nil)
+
+	rows, err := importer.readExcel(bytes.NewReader(buf.Bytes()), ClinicsSheetName)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"клиники"}}, rows)
+
+	rows, err = importer.readExcel(bytes.NewReader(buf.Bytes()), CitiesSheetName)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"первый лист"}}, rows)
+}
@@ -0,0 +1,297 @@
+package imports
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/drerr0r/vetbot/internal/models"
+)
+
+// scheduleDayNames сокращения дней недели для описания изменений расписания
+var scheduleDayNames = map[int]string{
+	1: "пн", 2: "вт", 3: "ср", 4: "чт", 5: "пт", 6: "сб", 7: "вс",
+}
+
+// directoryNames названия записей справочника по ID - для описания изменений в предпросмотре
+type directoryNames struct {
+	cities          map[int]string
+	specializations map[int]string
+	clinics         map[int]string
+}
+
+// vetUpdate изменения существующего врача, найденные при проверке строки файла
+type vetUpdate struct {
+	vet               *models.Veterinarian // поля врача после обновления
+	addSpecIDs        []int
+	addClinicIDs      []int
+	removeScheduleIDs []int
+	addSchedules      []models.Schedule
+	changes           []string
+}
+
+// scheduleSlot прием врача в клинике в определенный день недели
+type scheduleSlot struct {
+	clinicID  int
+	dayOfWeek int
+}
+
+// planVeterinarianUpdate сравнивает строку файла с врачом из справочника.
+// Пустые ячейки не стирают данные; специализации и клиники добавляются к существующим,
+// а расписание заменяется только в тех клиниках и днях, которые указаны в файле.
+// active - значение колонки Активен, NULL если она пустая
+func (i *CSVImporter) planVeterinarianUpdate(existing, incoming *models.Veterinarian, active sql.NullBool,
+	specIDs []int, schedules []models.Schedule, names *directoryNames) (*vetUpdate, error) {
+	vetID := models.GetVetIDAsIntOrZero(existing)
+	merged := *existing
+	update := &vetUpdate{vet: &merged}
+
+	if incoming.Patronymic.Valid && incoming.Patronymic.String != existing.Patronymic.String {
+		update.changes = append(update.changes, fmt.Sprintf("отчество: %s → %s",
+			formatChangeValue(existing.Patronymic.String), incoming.Patronymic.String))
+		merged.Patronymic = incoming.Patronymic
+	}
+
+	if incoming.Email.Valid && incoming.Email.String != existing.Email.String {
+		update.changes = append(update.changes, fmt.Sprintf("email: %s → %s",
+			formatChangeValue(existing.Email.String), incoming.Email.String))
+		merged.Email = incoming.Email
+	}
+	if incoming.ExperienceYears.Valid && (!existing.ExperienceYears.Valid ||
+		incoming.ExperienceYears.Int64 != existing.ExperienceYears.Int64) {
+		old := ""
+		if existing.ExperienceYears.Valid {
+			old = strconv.FormatInt(existing.ExperienceYears.Int64, 10)
+		}
+		update.changes = append(update.changes, fmt.Sprintf("опыт: %s → %d",
+			formatChangeValue(old), incoming.ExperienceYears.Int64))
+		merged.ExperienceYears = incoming.ExperienceYears
+	}
+	if incoming.Description.Valid && incoming.Description.String != existing.Description.String {
+		update.changes = append(update.changes, "описание: обновлено")
+		merged.Description = incoming.Description
+	}
+	if incoming.CityID.Valid && (!existing.CityID.Valid || incoming.CityID.Int64 != existing.CityID.Int64) {
+		old := ""
+		if existing.CityID.Valid {
+			old = names.cities[int(existing.CityID.Int64)]
+		}
+		update.changes = append(update.changes, fmt.Sprintf("город: %s → %s",
+			formatChangeValue(old), names.cities[int(incoming.CityID.Int64)]))
+		merged.CityID = incoming.CityID
+	}
+	if active.Valid && active.Bool != existing.IsActive {
+		update.changes = append(update.changes, fmt.Sprintf("активен: %s → %s",
+			formatActiveFlag(existing.IsActive), formatActiveFlag(active.Bool)))
+		merged.IsActive = active.Bool
+	}
+
+	// Специализации
+	currentSpecs, err := i.db.GetSpecializationsByVetID(vetID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка загрузки специализаций врача: %w", err)
+	}
+	hasSpec := make(map[int]bool, len(currentSpecs))
+	for _, spec := range currentSpecs {
+		hasSpec[spec.ID] = true
+	}
+	for _, specID := range specIDs {
+		if !hasSpec[specID] {
+			update.addSpecIDs = append(update.addSpecIDs, specID)
+			update.changes = append(update.changes, "специализации: +"+names.specializations[specID])
+		}
+	}
+
+	// Клиники из колонки расписания
+	currentClinics, err := i.db.GetClinicsByVetID(vetID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка загрузки клиник врача: %w", err)
+	}
+	hasClinic := make(map[int]bool, len(currentClinics))
+	for _, clinic := range currentClinics {
+		hasClinic[clinic.ID] = true
+	}
+	for _, schedule := range schedules {
+		if !hasClinic[schedule.ClinicID] {
+			hasClinic[schedule.ClinicID] = true
+			update.addClinicIDs = append(update.addClinicIDs, schedule.ClinicID)
+			update.changes = append(update.changes, "клиника: +"+names.clinics[schedule.ClinicID])
+		}
+	}
+
+	// Расписание: интервалы из файла заменяют приемы, включая выключенные, в той же клинике и в тот же день
+	currentSchedules, err := i.db.GetAllSchedulesByVetID(vetID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка загрузки расписания врача: %w", err)
+	}
+	existingBySlot := make(map[scheduleSlot][]*models.Schedule)
+	for _, schedule := range currentSchedules {
+		slot := scheduleSlot{clinicID: schedule.ClinicID, dayOfWeek: schedule.DayOfWeek}
+		existingBySlot[slot] = append(existingBySlot[slot], schedule)
+	}
+
+	var slots []scheduleSlot
+	incomingBySlot := make(map[scheduleSlot][]models.Schedule)
+	for _, schedule := range schedules {
+		slot := scheduleSlot{clinicID: schedule.ClinicID, dayOfWeek: schedule.DayOfWeek}
+		if _, seen := incomingBySlot[slot]; !seen {
+			slots = append(slots, slot)
+		}
+		incomingBySlot[slot] = append(incomingBySlot[slot], schedule)
+	}
+
+	for _, slot := range slots {
+		oldIntervals := make([]string, 0, len(existingBySlot[slot]))
+		for _, schedule := range existingBySlot[slot] {
+			oldIntervals = append(oldIntervals, formatScheduleInterval(schedule.StartTime, schedule.EndTime, schedule.IsAvailable))
+		}
+		newIntervals := make([]string, 0, len(incomingBySlot[slot]))
+		for _, schedule := range incomingBySlot[slot] {
+			newIntervals = append(newIntervals, formatScheduleInterval(schedule.StartTime, schedule.EndTime, schedule.IsAvailable))
+		}
+		sort.Strings(oldIntervals)
+		sort.Strings(newIntervals)
+
+		oldValue, newValue := strings.Join(oldIntervals, ", "), strings.Join(newIntervals, ", ")
+		if oldValue == newValue {
+			continue
+		}
+
+		for _, schedule := range existingBySlot[slot] {
+			update.removeScheduleIDs = append(update.removeScheduleIDs, schedule.ID)
+		}
+		update.addSchedules = append(update.addSchedules, incomingBySlot[slot]...)
+		update.changes = append(update.changes, fmt.Sprintf("расписание %s %s: %s → %s",
+			names.clinics[slot.clinicID], scheduleDayNames[slot.dayOfWeek], formatChangeValue(oldValue), newValue))
+	}
+
+	return update, nil
+}
+
+// applyVeterinarianUpdate сохраняет изменения врача в транзакции импорта
+func applyVeterinarianUpdate(tx *sql.Tx, update *vetUpdate) error {
+	vet := update.vet
+	vetID := models.GetVetIDAsIntOrZero(vet)
+
+	if _, err := tx.Exec(
+		`UPDATE veterinarians SET patronymic = $1, email = $2, experience_years = $3, description = $4,
+              city_id = $5, is_active = $6 WHERE id = $7`,
+		vet.Patronymic, vet.Email, vet.ExperienceYears, vet.Description, vet.CityID, vet.IsActive, vetID,
+	); err != nil {
+		return fmt.Errorf("ошибка обновления врача: %w", err)
+	}
+
+	for _, specID := range update.addSpecIDs {
+		if _, err := tx.Exec(
+			"INSERT INTO vet_specializations (vet_id, specialization_id) VALUES ($1, $2) ON CONFLICT DO NOTHING",
+			vetID, specID,
+		); err != nil {
+			return fmt.Errorf("ошибка добавления специализации: %w", err)
+		}
+	}
+
+	if err := insertVetClinics(tx, vetID, update.addClinicIDs); err != nil {
+		return err
+	}
+
+	for _, scheduleID := range update.removeScheduleIDs {
+		if _, err := tx.Exec("DELETE FROM schedules WHERE id = $1", scheduleID); err != nil {
+			return fmt.Errorf("ошибка удаления расписания: %w", err)
+		}
+	}
+	return insertSchedules(tx, vetID, update.addSchedules)
+}
+
+// diffClinic сравнивает строку файла с клиникой из справочника и переносит в incoming
+// незаполненные в файле поля, чтобы пустые ячейки не стирали данные
+func diffClinic(existing, incoming *models.Clinic, cityNames map[int]string) []string {
+	var changes []string
+
+	if incoming.CityID.Int64 != existing.CityID.Int64 {
+		changes = append(changes, fmt.Sprintf("город: %s → %s",
+			formatChangeValue(cityNames[int(existing.CityID.Int64)]), cityNames[int(incoming.CityID.Int64)]))
+	}
+
+	fields := []struct {
+		title    string
+		existing sql.NullString
+		incoming *sql.NullString
+	}{
+		{"телефон", existing.Phone, &incoming.Phone},
+		{"часы работы", existing.WorkingHours, &incoming.WorkingHours},
+		{"район", existing.District, &incoming.District},
+		{"метро", existing.MetroStation, &incoming.MetroStation},
+	}
+	for _, field := range fields {
+		if !field.incoming.Valid {
+			*field.incoming = field.existing
+			continue
+		}
+		if field.incoming.String != field.existing.String {
+			changes = append(changes, fmt.Sprintf("%s: %s → %s",
+				field.title, formatChangeValue(field.existing.String), field.incoming.String))
+		}
+	}
+
+	if !incoming.HasCoordinates() {
+		incoming.Latitude, incoming.Longitude = existing.Latitude, existing.Longitude
+	} else if !existing.HasCoordinates() || existing.Latitude.Float64 != incoming.Latitude.Float64 ||
+		existing.Longitude.Float64 != incoming.Longitude.Float64 {
+		old := ""
+		if existing.HasCoordinates() {
+			old = formatCoordinates(existing)
+		}
+		changes = append(changes, fmt.Sprintf("координаты: %s → %s", formatChangeValue(old), formatCoordinates(incoming)))
+	}
+
+	return changes
+}
+
+// updateClinic сохраняет изменения клиники в транзакции импорта
+func updateClinic(tx *sql.Tx, clinic *models.Clinic) error {
+	_, err := tx.Exec(`UPDATE clinics SET city_id = $1, phone = $2, working_hours = $3, district = $4,
+              metro_station = $5, latitude = $6, longitude = $7 WHERE id = $8`,
+		clinic.CityID, clinic.Phone, clinic.WorkingHours, clinic.District,
+		clinic.MetroStation, clinic.Latitude, clinic.Longitude, clinic.ID)
+	return err
+}
+
+// formatScheduleInterval записывает интервал приема как "9:00-18:00", выключенный - с пометкой "9:00-18:00(выкл)"
+func formatScheduleInterval(start, end string, available bool) string {
+	interval := formatScheduleClock(start) + "-" + formatScheduleClock(end)
+	if !available {
+		interval += DisabledScheduleMark
+	}
+	return interval
+}
+
+func formatScheduleClock(value string) string {
+	minutes, err := models.ParseClock(value)
+	if err != nil {
+		return strings.TrimSpace(value)
+	}
+	return fmt.Sprintf("%d:%02d", minutes/60, minutes%60)
+}
+
+func formatCoordinates(clinic *models.Clinic) string {
+	return strconv.FormatFloat(clinic.Latitude.Float64, 'f', -1, 64) + ", " +
+		strconv.FormatFloat(clinic.Longitude.Float64, 'f', -1, 64)
+}
+
+// formatActiveFlag записывает активность врача так же, как колонка Активен
+func formatActiveFlag(active bool) string {
+	if active {
+		return "да"
+	}
+	return "нет"
+}
+
+// formatChangeValue подставляет прочерк вместо пустого прежнего значения
+func formatChangeValue(value string) string {
+	if value == "" {
+		return "—"
+	}
+	return value
+}
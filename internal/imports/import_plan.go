@@ -0,0 +1,164 @@
+package imports
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/drerr0r/vetbot/internal/models"
+)
+
+// Типы импорта
+const (
+	ImportTypeVeterinarians = "veterinarians"
+	ImportTypeClinics       = "clinics"
+	ImportTypeCities        = "cities"
+)
+
+// PlannedRow строка файла после проверки: действие и его описание для предпросмотра
+type PlannedRow struct {
+	RowNumber int
+	Action    models.ImportAction
+	Title     string   // кого касается строка: "Иван Петров", название клиники или города
+	Field     string   // для ошибок - колонка с проблемой
+	Message   string   // причина ошибки или пропуска, для обновления - что изменится
+	Changes   []string // для обновления - изменения по полям: "email: old → new"
+
+	apply func(tx *sql.Tx) error
+}
+
+// ImportPlan результат проверки всего файла. До вызова Apply база данных не изменяется
+type ImportPlan struct {
+	Type      string
+	FileName  string
+	TotalRows int
+	Rows      []*PlannedRow
+
+	records [][]string // строки файла вместе с заголовком - для книги с результатами проверки
+}
+
+func newImportPlan(importType, fileName string, records [][]string) *ImportPlan {
+	return &ImportPlan{Type: importType, FileName: fileName, TotalRows: len(records) - 1, records: records}
+}
+
+// addError отмечает строку как ошибочную
+func (p *ImportPlan) addError(rowNum int, title, field, message string) {
+	p.Rows = append(p.Rows, &PlannedRow{RowNumber: rowNum, Action: models.ImportActionError, Title: title, Field: field, Message: message})
+}
+
+// addSkip отмечает строку, которую импортировать не нужно
+func (p *ImportPlan) addSkip(rowNum int, title, message string) {
+	p.Rows = append(p.Rows, &PlannedRow{RowNumber: rowNum, Action: models.ImportActionSkip, Title: title, Message: message})
+}
+
+// addChange добавляет строку, которая создаст или обновит запись при применении плана
+func (p *ImportPlan) addChange(rowNum int, action models.ImportAction, title, message string, apply func(tx *sql.Tx) error) {
+	p.Rows = append(p.Rows, &PlannedRow{RowNumber: rowNum, Action: action, Title: title, Message: message, apply: apply})
+}
+
+// addUpdate добавляет строку, которая обновит существующую запись; changes - изменения по полям
+func (p *ImportPlan) addUpdate(rowNum int, title string, changes []string, apply func(tx *sql.Tx) error) {
+	p.Rows = append(p.Rows, &PlannedRow{
+		RowNumber: rowNum,
+		Action:    models.ImportActionUpdate,
+		Title:     title,
+		Message:   strings.Join(changes, "; "),
+		Changes:   changes,
+		apply:     apply,
+	})
+}
+
+// Count возвращает количество строк с указанным действием
+func (p *ImportPlan) Count(action models.ImportAction) int {
+	count := 0
+	for _, row := range p.Rows {
+		if row.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+// HasChanges проверяет, есть ли в плане строки, меняющие базу данных
+func (p *ImportPlan) HasChanges() bool {
+	return p.Count(models.ImportActionCreate)+p.Count(models.ImportActionUpdate) > 0
+}
+
+// RowsWith возвращает строки с указанным действием в порядке файла
+func (p *ImportPlan) RowsWith(action models.ImportAction) []*PlannedRow {
+	var rows []*PlannedRow
+	for _, row := range p.Rows {
+		if row.Action == action {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// Result возвращает итоги плана в виде результата импорта
+func (p *ImportPlan) Result() *models.ImportResult {
+	result := &models.ImportResult{
+		TotalRows:    p.TotalRows,
+		CreatedCount: p.Count(models.ImportActionCreate),
+		UpdatedCount: p.Count(models.ImportActionUpdate),
+		SkippedCount: p.Count(models.ImportActionSkip),
+		Errors:       []models.ImportError{},
+	}
+	result.SuccessCount = result.CreatedCount + result.UpdatedCount
+
+	for _, row := range p.RowsWith(models.ImportActionError) {
+		result.ErrorCount++
+		result.Errors = append(result.Errors, models.ImportError{
+			RowNumber: row.RowNumber,
+			Field:     row.Field,
+			Message:   row.Message,
+		})
+	}
+	return result
+}
+
+// Apply применяет план одной транзакцией: строки с ошибками и пропуски не трогаются,
+// а сбой любой изменяющей строки или отмена импорта откатывает весь импорт
+func (i *CSVImporter) Apply(plan *ImportPlan, InfoLog, ErrorLog *log.Logger) (*models.ImportResult, error) {
+	InfoLog.Printf("🚀 Применение импорта %s из файла %s", plan.Type, plan.FileName)
+
+	tx, err := i.db.GetDB().BeginTx(i.ctx, nil)
+	if err != nil {
+		ErrorLog.Printf("❌ Ошибка начала транзакции импорта: %v", err)
+		return nil, fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	total := plan.Count(models.ImportActionCreate) + plan.Count(models.ImportActionUpdate)
+	done := 0
+	for _, row := range plan.Rows {
+		if row.apply == nil {
+			continue
+		}
+		if err := i.checkpoint(done, total); err != nil {
+			InfoLog.Printf("⛔ Импорт %s из файла %s отменен, изменения откатываются", plan.Type, plan.FileName)
+			return nil, err
+		}
+		done++
+
+		if err := row.apply(tx); err != nil {
+			ErrorLog.Printf("❌ Строка %d: ошибка сохранения, импорт откатывается: %v", row.RowNumber, err)
+			return nil, fmt.Errorf("строка %d (%s): %w. Импорт отменен, изменения не сохранены", row.RowNumber, row.Title, err)
+		}
+	}
+
+	if err := i.checkpoint(total, total); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		ErrorLog.Printf("❌ Ошибка коммита транзакции импорта: %v", err)
+		return nil, fmt.Errorf("ошибка сохранения импорта: %w", err)
+	}
+
+	result := plan.Result()
+	InfoLog.Printf("🎯 Импорт %s завершен. Создано: %d, обновлено: %d, пропущено: %d, ошибок: %d",
+		plan.Type, result.CreatedCount, result.UpdatedCount, result.SkippedCount, result.ErrorCount)
+	return result, nil
+}
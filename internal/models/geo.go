@@ -0,0 +1,82 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// earthRadiusKm средний радиус Земли
+const earthRadiusKm = 6371.0
+
+// DistanceKm возвращает расстояние между двумя точками по формуле гаверсинусов
+func DistanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// ValidateCoordinates проверяет диапазоны широты и долготы
+func ValidateCoordinates(latitude, longitude float64) error {
+	if math.IsNaN(latitude) || latitude < -90 || latitude > 90 {
+		return fmt.Errorf("широта должна быть от -90 до 90")
+	}
+	if math.IsNaN(longitude) || longitude < -180 || longitude > 180 {
+		return fmt.Errorf("долгота должна быть от -180 до 180")
+	}
+	return nil
+}
+
+// ParseCoordinates разбирает координаты вида "55.7558, 37.6173".
+// Разделитель - запятая, точка с запятой или пробел; допускается десятичная запятая ("55,7558; 37,6173")
+func ParseCoordinates(value string) (float64, float64, error) {
+	parts := splitCoordinates(value)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("ожидается формат «широта, долгота», например 55.7558, 37.6173")
+	}
+
+	latitude, err := ParseCoordinate(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("неверная широта: %s", parts[0])
+	}
+	longitude, err := ParseCoordinate(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("неверная долгота: %s", parts[1])
+	}
+
+	if err := ValidateCoordinates(latitude, longitude); err != nil {
+		return 0, 0, err
+	}
+	return latitude, longitude, nil
+}
+
+// splitCoordinates делит строку на широту и долготу
+func splitCoordinates(value string) []string {
+	value = strings.TrimSpace(value)
+	if strings.Contains(value, ";") {
+		return strings.Split(value, ";")
+	}
+	if parts := strings.Split(value, ","); len(parts) == 2 {
+		return parts
+	}
+	return strings.Fields(strings.ReplaceAll(value, ", ", " "))
+}
+
+// ParseCoordinate разбирает одну координату, допускается десятичная запятая ("55,7558")
+func ParseCoordinate(value string) (float64, error) {
+	return strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(value), ",", "."), 64)
+}
+
+// FormatDistance форматирует расстояние для пользователя: метры до километра, дальше - километры
+func FormatDistance(distanceKm float64) string {
+	if distanceKm < 1 {
+		return fmt.Sprintf("%d м", int(math.Round(distanceKm*1000)))
+	}
+	return fmt.Sprintf("%.1f км", distanceKm)
+}
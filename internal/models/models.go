@@ -2,6 +2,7 @@ package models
 
 import (
 	"database/sql"
+	"errors"
 	"time"
 )
 
@@ -50,26 +51,40 @@ type City struct {
 	ID        int       `json:"id"`
 	Name      string    `json:"name"`
 	Region    string    `json:"region"`
+	Timezone  string    `json:"timezone"` // Часовой пояс IANA, например "Asia/Novosibirsk"
 	CreatedAt time.Time `json:"created_at"`
 }
 
 // Clinic представляет клинику/место приема
 type Clinic struct {
-	ID           int            `json:"id"`
-	Name         string         `json:"name"`
-	Address      string         `json:"address"`
-	Phone        sql.NullString `json:"phone"`         // Может быть NULL
-	WorkingHours sql.NullString `json:"working_hours"` // Может быть NULL
-	IsActive     bool           `json:"is_active"`     // Добавлено поле для деактивации
-	CityID       sql.NullInt64  `json:"city_id"`       // Ссылка на город
-	District     sql.NullString `json:"district"`      // Район города
-	MetroStation sql.NullString `json:"metro_station"` // Станция метро
-	CreatedAt    time.Time      `json:"created_at"`
+	ID           int             `json:"id"`
+	Name         string          `json:"name"`
+	Address      string          `json:"address"`
+	Phone        sql.NullString  `json:"phone"`         // Может быть NULL
+	WorkingHours sql.NullString  `json:"working_hours"` // Может быть NULL
+	IsActive     bool            `json:"is_active"`     // Добавлено поле для деактивации
+	CityID       sql.NullInt64   `json:"city_id"`       // Ссылка на город
+	District     sql.NullString  `json:"district"`      // Район города
+	MetroStation sql.NullString  `json:"metro_station"` // Станция метро
+	Latitude     sql.NullFloat64 `json:"latitude"`      // Широта
+	Longitude    sql.NullFloat64 `json:"longitude"`     // Долгота
+	CreatedAt    time.Time       `json:"created_at"`
 
 	// Для удобства - связанные данные
 	City *City `json:"city,omitempty"`
 }
 
+// HasCoordinates проверяет, заданы ли координаты клиники
+func (c *Clinic) HasCoordinates() bool {
+	return c.Latitude.Valid && c.Longitude.Valid
+}
+
+// ClinicDistance клиника с расстоянием до точки поиска
+type ClinicDistance struct {
+	Clinic     *Clinic `json:"clinic"`
+	DistanceKm float64 `json:"distance_km"`
+}
+
 // Schedule представляет расписание врача
 type Schedule struct {
 	ID          int           `json:"id"`
@@ -84,6 +99,22 @@ type Schedule struct {
 	CreatedAt   time.Time     `json:"created_at"`
 }
 
+// ScheduleException исключение из недельного расписания врача на даты DateFrom-DateTo:
+// отсутствие (отпуск, болезнь) или другие часы приема в клинике
+type ScheduleException struct {
+	ID          int           `json:"id"`
+	VetID       int           `json:"vet_id"`
+	ClinicID    sql.NullInt64 `json:"clinic_id"` // пусто - во всех клиниках врача
+	DateFrom    time.Time     `json:"date_from"`
+	DateTo      time.Time     `json:"date_to"`
+	IsAvailable bool          `json:"is_available"` // true - прием в StartTime-EndTime вместо обычных часов
+	StartTime   string        `json:"start_time"`
+	EndTime     string        `json:"end_time"`
+	Reason      string        `json:"reason"`
+	Clinic      *Clinic       `json:"clinic,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
 // UserRequest представляет запрос пользователя
 type UserRequest struct {
 	ID               int       `json:"id"`
@@ -111,25 +142,58 @@ type ClinicEditData struct {
 // ImportResult представляет результат импорта
 type ImportResult struct {
 	TotalRows    int           `json:"total_rows"`
-	SuccessCount int           `json:"success_count"`
+	SuccessCount int           `json:"success_count"` // создано и обновлено
+	CreatedCount int           `json:"created_count"`
+	UpdatedCount int           `json:"updated_count"`
+	SkippedCount int           `json:"skipped_count"`
 	ErrorCount   int           `json:"error_count"`
 	Errors       []ImportError `json:"errors"`
 }
 
+// ImportAction что импорт сделает со строкой файла
+type ImportAction string
+
+const (
+	ImportActionCreate ImportAction = "create"
+	ImportActionUpdate ImportAction = "update"
+	ImportActionSkip   ImportAction = "skip"
+	ImportActionError  ImportAction = "error"
+)
+
 type ImportError struct {
 	RowNumber int    `json:"row_number"`
 	Field     string `json:"field"`
 	Message   string `json:"message"`
 }
 
-// Добавляем новые структуры для импорта
+// ImportRequest задача импорта файла, загруженного администратором
 type ImportRequest struct {
-	Type      string        `json:"type"` // "cities", "clinics", "veterinarians"
-	FilePath  string        `json:"file_path"`
-	UserID    int64         `json:"user_id"`
-	Status    string        `json:"status"` // "pending", "processing", "completed", "failed"
-	Result    *ImportResult `json:"result,omitempty"`
-	CreatedAt time.Time     `json:"created_at"`
+	ID            int           `json:"id"`
+	Type          string        `json:"type"` // "cities", "clinics", "veterinarians"
+	FileName      string        `json:"file_name"`
+	UserID        int64         `json:"user_id"`
+	Status        string        `json:"status"` // см. ImportStatus*
+	ProcessedRows int           `json:"processed_rows"`
+	TotalRows     int           `json:"total_rows"`
+	Result        *ImportResult `json:"result,omitempty"`
+	Error         string        `json:"error,omitempty"`
+	CreatedAt     time.Time     `json:"created_at"`
+	FinishedAt    sql.NullTime  `json:"finished_at"`
+}
+
+// Статусы задачи импорта
+const (
+	ImportStatusPending    = "pending"    // файл принят, обработка еще не началась
+	ImportStatusProcessing = "processing" // идет проверка или применение
+	ImportStatusReady      = "ready"      // файл проверен, ждет подтверждения администратора
+	ImportStatusCompleted  = "completed"
+	ImportStatusFailed     = "failed"
+	ImportStatusCancelled  = "cancelled"
+)
+
+// IsFinished проверяет, что задача импорта завершена и больше не изменится
+func (r *ImportRequest) IsFinished() bool {
+	return r.Status == ImportStatusCompleted || r.Status == ImportStatusFailed || r.Status == ImportStatusCancelled
 }
 
 // Расширяем SearchCriteria для поиска по городам
@@ -142,6 +206,8 @@ type SearchCriteria struct {
 	CityName         string `json:"city_name"`     // Поиск по названию города
 	District         string `json:"district"`      // Поиск по району
 	MetroStation     string `json:"metro_station"` // Поиск по станции метро
+	Date             string `json:"date"`          // Поиск по дате приема (ГГГГ-ММ-ДД), день недели берется из нее
+	OpenNow          bool   `json:"open_now"`      // Прием идет сейчас по местному времени клиники
 }
 
 // CityEditData временные данные для редактирования города
@@ -151,6 +217,11 @@ type CityEditData struct {
 	CurrentValue string
 }
 
+// SpecializationEditData временные данные для редактирования специализации
+type SpecializationEditData struct {
+	SpecializationID int
+}
+
 // GetVetIDAsInt безопасно конвертирует vet.ID в int
 func GetVetIDAsInt(vet *Veterinarian) (int, bool) {
 	if !vet.ID.Valid {
@@ -199,3 +270,57 @@ type ReviewEditData struct {
 	ReviewID int    `json:"review_id"`
 	Action   string `json:"action"` // approve/reject
 }
+
+// ErrAppointmentSlotTaken возвращается, если выбранное время у врача уже занято
+var ErrAppointmentSlotTaken = errors.New("appointment slot is already booked")
+
+// Статусы записи на прием
+const (
+	AppointmentStatusBooked    = "booked"
+	AppointmentStatusCancelled = "cancelled"
+)
+
+// Appointment представляет запись пользователя на прием к врачу
+type Appointment struct {
+	ID          int          `json:"id"`
+	UserID      int          `json:"user_id"`
+	VetID       int          `json:"vet_id"`
+	ClinicID    int          `json:"clinic_id"`
+	Date        time.Time    `json:"date"`
+	StartTime   string       `json:"start_time"`
+	EndTime     string       `json:"end_time"`
+	Status      string       `json:"status"` // booked/cancelled
+	CreatedAt   time.Time    `json:"created_at"`
+	CancelledAt sql.NullTime `json:"cancelled_at"`
+
+	// Для удобства - связанные данные
+	Vet    *Veterinarian `json:"vet,omitempty"`
+	Clinic *Clinic       `json:"clinic,omitempty"`
+}
+
+// AppointmentSlot представляет свободный интервал для записи
+type AppointmentSlot struct {
+	VetID     int       `json:"vet_id"`
+	ClinicID  int       `json:"clinic_id"`
+	Date      time.Time `json:"date"`
+	StartTime string    `json:"start_time"`
+	EndTime   string    `json:"end_time"`
+}
+
+// UserSession представляет сессию пользователя: состояние диалога, временные данные и историю навигации.
+// Состояние и данные админ-панели хранятся отдельно: пользовательские сценарии очищают Data
+type UserSession struct {
+	UserID     int64                  `json:"user_id"` // Telegram ID
+	State      string                 `json:"state"`
+	Data       map[string]interface{} `json:"data"`
+	History    []string               `json:"history"`
+	AdminState string                 `json:"admin_state"`
+	AdminData  map[string]interface{} `json:"admin_data"`
+	UpdatedAt  time.Time              `json:"updated_at"`
+}
+
+// IsEmpty проверяет, что в сессии нет ни состояния, ни данных, ни истории
+func (s *UserSession) IsEmpty() bool {
+	return s.State == "" && len(s.Data) == 0 && len(s.History) == 0 &&
+		s.AdminState == "" && len(s.AdminData) == 0
+}
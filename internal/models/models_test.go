@@ -3,6 +3,7 @@ package models
 import (
 	"database/sql"
 	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
@@ -499,3 +500,387 @@ func TestTimeFields(t *testing.T) {
 	// Время должно сохраняться с точностью до секунд (из-за JSON формата)
 	assert.WithinDuration(t, user.CreatedAt, decodedUser.CreatedAt, time.Second)
 }
+
+// ============================================================================
+// ТЕСТЫ ДЛЯ КООРДИНАТ
+// ============================================================================
+
+func TestDistanceKm(t *testing.T) {
+	// Москва (Красная площадь) - Санкт-Петербург (Дворцовая площадь)
+	distance := DistanceKm(55.7539, 37.6208, 59.9390, 30.3158)
+	assert.InDelta(t, 634, distance, 5)
+
+	assert.Equal(t, 0.0, DistanceKm(55.75, 37.62, 55.75, 37.62))
+	assert.InDelta(t, DistanceKm(10, 20, 30, 40), DistanceKm(30, 40, 10, 20), 1e-9)
+}
+
+func TestParseCoordinates(t *testing.T) {
+	tests := []struct {
+		input     string
+		latitude  float64
+		longitude float64
+		wantErr   bool
+	}{
+		{input: "55.7558, 37.6173", latitude: 55.7558, longitude: 37.6173},
+		{input: "55.7558 37.6173", latitude: 55.7558, longitude: 37.6173},
+		{input: "55,7558; 37,6173", latitude: 55.7558, longitude: 37.6173},
+		{input: "-33.8688,151.2093", latitude: -33.8688, longitude: 151.2093},
+		{input: "55,7558, 37,6173", latitude: 55.7558, longitude: 37.6173},
+		{input: "55.7558", wantErr: true},
+		{input: "abc, 37.6", wantErr: true},
+		{input: "91, 37.6", wantErr: true},
+		{input: "55.7, 181", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			latitude, longitude, err := ParseCoordinates(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.latitude, latitude)
+			assert.Equal(t, tt.longitude, longitude)
+		})
+	}
+}
+
+func TestFormatDistance(t *testing.T) {
+	assert.Equal(t, "350 м", FormatDistance(0.35))
+	assert.Equal(t, "1.0 км", FormatDistance(1))
+	assert.Equal(t, "12.3 км", FormatDistance(12.34))
+}
+
+// ============================================================================
+// ТЕСТЫ ДЛЯ ВРЕМЕНИ ПРИЕМА
+// ============================================================================
+
+func TestParseClock(t *testing.T) {
+	minutes, err := ParseClock("09:30")
+	assert.NoError(t, err)
+	assert.Equal(t, 570, minutes)
+
+	minutes, err = ParseClock("18:00:00")
+	assert.NoError(t, err)
+	assert.Equal(t, 1080, minutes)
+
+	_, err = ParseClock("25:00")
+	assert.Error(t, err)
+	_, err = ParseClock("abc")
+	assert.Error(t, err)
+
+	assert.Equal(t, "00:30", FormatClock(minutesPerDay+30))
+}
+
+func TestSchedule_IsWorkingAt(t *testing.T) {
+	regular := &Schedule{DayOfWeek: 1, StartTime: "09:00", EndTime: "18:00", IsAvailable: true}
+	overnight := &Schedule{DayOfWeek: 1, StartTime: "20:00", EndTime: "08:00", IsAvailable: true}
+	sundayNight := &Schedule{DayOfWeek: 7, StartTime: "22:00", EndTime: "06:00", IsAvailable: true}
+	disabled := &Schedule{DayOfWeek: 1, StartTime: "09:00", EndTime: "18:00", IsAvailable: false}
+
+	tests := []struct {
+		name     string
+		schedule *Schedule
+		day      int
+		clock    string
+		want     bool
+	}{
+		{"regular inside", regular, 1, "12:00", true},
+		{"regular start inclusive", regular, 1, "09:00", true},
+		{"regular end exclusive", regular, 1, "18:00", false},
+		{"regular other day", regular, 2, "12:00", false},
+		{"regular any day", regular, 0, "12:00", true},
+		{"overnight evening", overnight, 1, "23:00", true},
+		{"overnight next morning", overnight, 2, "03:00", true},
+		{"overnight same day morning", overnight, 1, "03:00", false},
+		{"overnight next day evening", overnight, 2, "21:00", false},
+		{"overnight end exclusive", overnight, 2, "08:00", false},
+		{"overnight any day", overnight, 0, "02:00", true},
+		{"sunday night into monday", sundayNight, 1, "05:00", true},
+		{"invalid clock", regular, 1, "noon", false},
+		{"disabled slot", disabled, 1, "12:00", false},
+		{"disabled slot any day", disabled, 0, "12:00", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.schedule.IsWorkingAt(tt.day, tt.clock))
+		})
+	}
+
+	assert.True(t, overnight.IsOvernight())
+	assert.False(t, regular.IsOvernight())
+}
+
+func TestSchedule_Validate(t *testing.T) {
+	assert.NoError(t, (&Schedule{DayOfWeek: 1, StartTime: "09:00", EndTime: "18:00"}).Validate())
+	assert.NoError(t, (&Schedule{DayOfWeek: 7, StartTime: "20:00", EndTime: "08:00"}).Validate())
+
+	assert.Error(t, (&Schedule{DayOfWeek: 0, StartTime: "09:00", EndTime: "18:00"}).Validate())
+	assert.Error(t, (&Schedule{DayOfWeek: 8, StartTime: "09:00", EndTime: "18:00"}).Validate())
+	assert.Error(t, (&Schedule{DayOfWeek: 1, StartTime: "9", EndTime: "18:00"}).Validate())
+	assert.Error(t, (&Schedule{DayOfWeek: 1, StartTime: "09:00", EndTime: "09:00"}).Validate())
+}
+
+func TestSchedule_Overlaps(t *testing.T) {
+	slot := func(id, day int, start, end string) *Schedule {
+		return &Schedule{ID: id, DayOfWeek: day, StartTime: start, EndTime: end, IsAvailable: true}
+	}
+
+	assert.True(t, slot(1, 1, "09:00", "13:00").Overlaps(slot(2, 1, "12:00", "18:00")))
+	assert.False(t, slot(1, 1, "09:00", "13:00").Overlaps(slot(2, 1, "13:00", "18:00")), "приемы встык")
+	assert.False(t, slot(1, 1, "09:00", "13:00").Overlaps(slot(2, 2, "09:00", "13:00")))
+
+	// Ночная смена заходит в следующий день, в том числе с воскресенья на понедельник
+	assert.True(t, slot(1, 1, "20:00", "08:00").Overlaps(slot(2, 2, "07:00", "12:00")))
+	assert.True(t, slot(1, 7, "22:00", "06:00").Overlaps(slot(2, 1, "05:00", "09:00")))
+	assert.False(t, slot(1, 7, "22:00", "06:00").Overlaps(slot(2, 1, "06:00", "09:00")))
+
+	current := slot(1, 1, "09:00", "18:00")
+	disabled := slot(2, 1, "10:00", "12:00")
+	disabled.IsAvailable = false
+	other := slot(3, 1, "17:00", "20:00")
+	assert.Nil(t, current.FindOverlap([]*Schedule{slot(1, 1, "10:00", "11:00"), disabled}), "сам прием и выключенные не учитываются")
+	assert.Equal(t, other, current.FindOverlap([]*Schedule{disabled, other}))
+}
+
+// ============================================================================
+// ТЕСТЫ ДЛЯ ПОИСКА ПО ФИО
+// ============================================================================
+
+func TestNormalizeName(t *testing.T) {
+	assert.Equal(t, "петрова", NormalizeName("Petrova"))
+	assert.Equal(t, "алексей", NormalizeName("Alexey"))
+	assert.Equal(t, "щукин", NormalizeName("Shchukin"))
+	assert.Equal(t, "федор", NormalizeName("Фёдор"))
+	assert.Equal(t, "наталя", NormalizeName("Наталья"))
+	assert.Equal(t, "дмитрий", NormalizeName("Dmitriy"))
+}
+
+func TestVetNameScore(t *testing.T) {
+	vet := &Veterinarian{
+		FirstName:  "Анна",
+		LastName:   "Петрова",
+		Patronymic: sql.NullString{String: "Сергеевна", Valid: true},
+	}
+
+	exact := VetNameScore(vet, "Петрова")
+	prefix := VetNameScore(vet, "Петр")
+	typo := VetNameScore(vet, "Питрова")
+
+	assert.Greater(t, exact, prefix)
+	assert.Greater(t, prefix, typo)
+	assert.Greater(t, typo, 0)
+
+	assert.Greater(t, VetNameScore(vet, "Petrova"), 0)
+	assert.Greater(t, VetNameScore(vet, "Анна Сергеевна Петрова"), exact)
+	assert.Equal(t, 0, VetNameScore(vet, "Сидорова"))
+	assert.Equal(t, 0, VetNameScore(vet, "Анна Сидорова"))
+	assert.Equal(t, 0, VetNameScore(vet, ""))
+}
+
+func TestNameSearchFragments(t *testing.T) {
+	assert.Equal(t, [][]string{{"кот"}, {"пет", "ров"}, {"сер", "гее", "вна"}},
+		NameSearchFragments("Кот Petrov Сергеевна"))
+	assert.Empty(t, NameSearchFragments(" - "))
+
+	// Врач, которого находит VetNameScore, всегда содержит хотя бы один кусок каждого слова запроса
+	vet := &Veterinarian{
+		FirstName:  "Анна",
+		LastName:   "Петрова",
+		Patronymic: sql.NullString{String: "Сергеевна", Valid: true},
+	}
+	name := NormalizeName(vet.LastName) + NormalizeName(vet.FirstName) + NormalizeName(vet.Patronymic.String)
+	for _, query := range []string{"Петрова", "Питрова", "Птерова", "Petrova", "Анна Сергевна", "Петрв"} {
+		assert.Greater(t, VetNameScore(vet, query), 0, query)
+		for _, pieces := range NameSearchFragments(query) {
+			found := false
+			for _, piece := range pieces {
+				found = found || strings.Contains(name, piece)
+			}
+			assert.True(t, found, "%s: %v", query, pieces)
+		}
+	}
+}
+
+func TestRankVetsByName(t *testing.T) {
+	vets := []*Veterinarian{
+		{FirstName: "Иван", LastName: "Петров"},
+		{FirstName: "Анна", LastName: "Петрова"},
+		{FirstName: "Мария", LastName: "Иванова"},
+	}
+
+	result := RankVetsByName(vets, "петрова", 0)
+	if assert.Len(t, result, 2) {
+		assert.Equal(t, "Петрова", result[0].LastName)
+		assert.Equal(t, "Петров", result[1].LastName)
+	}
+
+	assert.Len(t, RankVetsByName(vets, "петров", 1), 1)
+	assert.Empty(t, RankVetsByName(vets, "Сидоров", 0))
+}
+
+func TestEffectiveSchedules(t *testing.T) {
+	schedules := []*Schedule{
+		{ID: 1, VetID: 1, ClinicID: 1, DayOfWeek: 1, StartTime: "09:00", EndTime: "18:00", IsAvailable: true},
+		{ID: 2, VetID: 1, ClinicID: 2, DayOfWeek: 1, StartTime: "19:00", EndTime: "21:00", IsAvailable: true},
+		{ID: 3, VetID: 1, ClinicID: 1, DayOfWeek: 2, StartTime: "09:00", EndTime: "18:00", IsAvailable: true},
+	}
+	monday := time.Date(2030, 1, 7, 12, 0, 0, 0, time.Local)
+	clinic1 := sql.NullInt64{Int64: 1, Valid: true}
+
+	ids := func(result []*Schedule) []int {
+		var got []int
+		for _, schedule := range result {
+			got = append(got, schedule.ID)
+		}
+		return got
+	}
+
+	assert.Equal(t, []int{1, 2}, ids(EffectiveSchedules(schedules, nil, monday)))
+
+	t.Run("Absence in all clinics", func(t *testing.T) {
+		vacation := &ScheduleException{VetID: 1, DateFrom: monday.AddDate(0, 0, -3), DateTo: monday}
+		assert.Empty(t, EffectiveSchedules(schedules, []*ScheduleException{vacation}, monday))
+		assert.Equal(t, []int{3}, ids(EffectiveSchedules(schedules, []*ScheduleException{vacation}, monday.AddDate(0, 0, 1))))
+	})
+
+	t.Run("Absence in one clinic", func(t *testing.T) {
+		absent := &ScheduleException{VetID: 1, ClinicID: clinic1, DateFrom: monday, DateTo: monday}
+		assert.Equal(t, []int{2}, ids(EffectiveSchedules(schedules, []*ScheduleException{absent}, monday)))
+	})
+
+	t.Run("Other hours replace clinic schedule", func(t *testing.T) {
+		hours := &ScheduleException{VetID: 1, ClinicID: clinic1, DateFrom: monday, DateTo: monday,
+			IsAvailable: true, StartTime: "12:00", EndTime: "15:00"}
+		result := EffectiveSchedules(schedules, []*ScheduleException{hours}, monday)
+
+		assert.Len(t, result, 2)
+		assert.Equal(t, 1, result[0].ClinicID)
+		assert.Equal(t, "12:00", result[0].StartTime)
+		assert.Equal(t, 1, result[0].DayOfWeek)
+		assert.Equal(t, 2, result[1].ID)
+	})
+
+	t.Run("Other hours on a day off", func(t *testing.T) {
+		saturday := monday.AddDate(0, 0, 5)
+		hours := &ScheduleException{VetID: 1, ClinicID: clinic1, DateFrom: saturday, DateTo: saturday,
+			IsAvailable: true, StartTime: "10:00", EndTime: "14:00"}
+		result := EffectiveSchedules(schedules, []*ScheduleException{hours}, saturday)
+
+		assert.Len(t, result, 1)
+		assert.Equal(t, 6, result[0].DayOfWeek)
+
+		// Отсутствие важнее других часов
+		sick := &ScheduleException{VetID: 1, DateFrom: saturday, DateTo: saturday}
+		assert.Empty(t, EffectiveSchedules(schedules, []*ScheduleException{hours, sick}, saturday))
+	})
+}
+
+func TestNearestWorkingDay(t *testing.T) {
+	schedules := []*Schedule{
+		{ID: 1, VetID: 1, ClinicID: 1, DayOfWeek: 1, StartTime: "09:00", EndTime: "18:00", IsAvailable: true},
+	}
+	// Воскресенье
+	now := time.Date(2030, 1, 6, 12, 0, 0, 0, time.Local)
+
+	schedule, date := NearestWorkingDay(schedules, nil, now, 30)
+	assert.Equal(t, 1, schedule.ID)
+	assert.Equal(t, 7, date.Day())
+
+	// Отпуск на две недели переносит прием на понедельник 21.01
+	vacation := &ScheduleException{VetID: 1, DateFrom: now, DateTo: now.AddDate(0, 0, 14)}
+	schedule, date = NearestWorkingDay(schedules, []*ScheduleException{vacation}, now, 30)
+	assert.Equal(t, 1, schedule.ID)
+	assert.Equal(t, 21, date.Day())
+
+	schedule, _ = NearestWorkingDay(schedules, []*ScheduleException{vacation}, now, 7)
+	assert.Nil(t, schedule)
+}
+
+func TestScheduleException_Validate(t *testing.T) {
+	day := time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC)
+	clinic := sql.NullInt64{Int64: 1, Valid: true}
+
+	assert.NoError(t, (&ScheduleException{DateFrom: day, DateTo: day}).Validate())
+	assert.NoError(t, (&ScheduleException{DateFrom: day, DateTo: day, IsAvailable: true, ClinicID: clinic,
+		StartTime: "10:00", EndTime: "14:00"}).Validate())
+
+	assert.Error(t, (&ScheduleException{DateFrom: day, DateTo: day.AddDate(0, 0, -1)}).Validate())
+	assert.Error(t, (&ScheduleException{DateFrom: day, DateTo: day, IsAvailable: true,
+		StartTime: "10:00", EndTime: "14:00"}).Validate())
+	assert.Error(t, (&ScheduleException{DateFrom: day, DateTo: day, IsAvailable: true, ClinicID: clinic,
+		StartTime: "10:00", EndTime: "10:00"}).Validate())
+
+	// Даты сравниваются по календарному дню, без учета времени и часового пояса
+	local := time.Date(2030, 1, 7, 23, 30, 0, 0, time.FixedZone("MSK", 3*60*60))
+	assert.True(t, (&ScheduleException{DateFrom: day, DateTo: day}).Covers(local))
+	assert.False(t, (&ScheduleException{DateFrom: day, DateTo: day}).Covers(local.AddDate(0, 0, 1)))
+}
+
+func TestSearchCriteria_ScheduleWindow(t *testing.T) {
+	now := time.Date(2026, 10, 16, 15, 30, 0, 0, time.UTC) // пятница
+
+	criteria := &SearchCriteria{DayOfWeek: 3}
+	start, day, err := criteria.ScheduleWindow(now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, 3, day)
+
+	// С датой неделя начинается накануне, а день недели берется из даты
+	criteria = &SearchCriteria{DayOfWeek: 3, Date: "2026-10-26"}
+	start, day, err = criteria.ScheduleWindow(now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 25, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, 1, day)
+
+	_, _, err = (&SearchCriteria{Date: "26.10.2026"}).ScheduleWindow(now)
+	assert.Error(t, err)
+}
+
+func TestTimezones(t *testing.T) {
+	now := time.Date(2026, 10, 16, 20, 30, 0, 0, time.UTC)
+
+	t.Run("Parse label and IANA name", func(t *testing.T) {
+		name, err := ParseTimezone("Новосибирск (UTC+7)")
+		assert.NoError(t, err)
+		assert.Equal(t, "Asia/Novosibirsk", name)
+
+		name, err = ParseTimezone(" Europe/Kaliningrad ")
+		assert.NoError(t, err)
+		assert.Equal(t, "Europe/Kaliningrad", name)
+
+		for _, value := range []string{"", "UTC", "Local", "Europe/Nowhere"} {
+			_, err := ParseTimezone(value)
+			assert.Error(t, err, value)
+		}
+	})
+
+	t.Run("Format with offset", func(t *testing.T) {
+		assert.Equal(t, "Asia/Novosibirsk (UTC+7)", FormatTimezone("Asia/Novosibirsk", now))
+		assert.Equal(t, "Europe/Moscow (UTC+3)", FormatTimezone("", now))
+		assert.Equal(t, "Asia/Kolkata (UTC+5:30)", FormatTimezone("Asia/Kolkata", now))
+	})
+
+	t.Run("Local date depends on city", func(t *testing.T) {
+		// 20:30 UTC в пятницу - в Новосибирске уже суббота, в Калининграде еще пятница
+		novosibirsk := &Clinic{City: &City{Timezone: "Asia/Novosibirsk"}}
+		kaliningrad := &Clinic{City: &City{Timezone: "Europe/Kaliningrad"}}
+		assert.Equal(t, time.Saturday, now.In(novosibirsk.Location()).Weekday())
+		assert.Equal(t, time.Friday, now.In(kaliningrad.Location()).Weekday())
+
+		// Без города и с неизвестным поясом - Москва
+		assert.Equal(t, DefaultTimezone, (*Clinic)(nil).Location().String())
+		assert.Equal(t, DefaultTimezone, (&City{Timezone: "Mars/Olympus"}).Location().String())
+	})
+
+	t.Run("Vet location comes from schedule clinics", func(t *testing.T) {
+		schedules := []*Schedule{
+			{ClinicID: 1, Clinic: &Clinic{ID: 1}},
+			{ClinicID: 2, Clinic: &Clinic{ID: 2, City: &City{Timezone: "Asia/Omsk"}}},
+		}
+		assert.Equal(t, "Asia/Omsk", SchedulesLocation(schedules).String())
+		assert.Equal(t, DefaultTimezone, SchedulesLocation(nil).String())
+	})
+}
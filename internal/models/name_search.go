@@ -0,0 +1,262 @@
+package models
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Веса совпадения одного слова запроса со словом ФИО
+const (
+	nameScoreExact       = 100
+	nameScorePrefix      = 80
+	nameScoreTypo        = 60
+	nameScoreTypoPrefix  = 40
+	nameScoreTypoPenalty = 10
+	// nameScoreLastNameBonus - фамилию ищут чаще всего, при равенстве она важнее имени
+	nameScoreLastNameBonus = 5
+)
+
+// latinDigraphs сочетания латинских букв, которые транслитерируются одной русской буквой (длинные первыми)
+var latinDigraphs = []struct {
+	latin    string
+	cyrillic string
+}{
+	{"shch", "щ"}, {"sch", "щ"},
+	{"zh", "ж"}, {"kh", "х"}, {"ch", "ч"}, {"sh", "ш"}, {"ts", "ц"}, {"tz", "ц"},
+	{"yu", "ю"}, {"ju", "ю"}, {"ya", "я"}, {"ja", "я"}, {"yo", "е"}, {"jo", "е"}, {"ye", "е"},
+}
+
+// latinLetters транслитерация одиночных латинских букв
+var latinLetters = map[rune]string{
+	'a': "а", 'b': "б", 'c': "к", 'd': "д", 'e': "е", 'f': "ф", 'g': "г", 'h': "х",
+	'i': "и", 'j': "й", 'k': "к", 'l': "л", 'm': "м", 'n': "н", 'o': "о", 'p': "п",
+	'q': "к", 'r': "р", 's': "с", 't': "т", 'u': "у", 'v': "в", 'w': "в", 'x': "кс",
+	'y': "ы", 'z': "з",
+}
+
+// NormalizeName приводит слово к виду для сравнения: нижний регистр, ё -> е, без ь/ъ и посторонних символов.
+// Латиница транслитерируется в кириллицу, поэтому "Petrova" и "Петрова" совпадают
+func NormalizeName(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	value = transliterateLatin(value)
+
+	var sb strings.Builder
+	for _, r := range value {
+		switch {
+		case r == 'ё':
+			sb.WriteRune('е')
+		case r == 'ь' || r == 'ъ':
+			// Мягкий и твердый знаки в латинице не передаются
+		case unicode.IsLetter(r):
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// transliterateLatin заменяет латинские буквы русскими (ГОСТ-подобная схема с популярными вариантами)
+func transliterateLatin(value string) string {
+	runes := []rune(value)
+	var sb strings.Builder
+
+	for i := 0; i < len(runes); {
+		matched := false
+		for _, digraph := range latinDigraphs {
+			if strings.HasPrefix(string(runes[i:]), digraph.latin) {
+				sb.WriteString(digraph.cyrillic)
+				i += len(digraph.latin)
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+
+		r := runes[i]
+		if r == 'y' && i > 0 && isLatinVowel(runes[i-1]) {
+			// "Sergey", "Dmitriy": y после гласной - это й
+			sb.WriteString("й")
+		} else if cyrillic, ok := latinLetters[r]; ok {
+			sb.WriteString(cyrillic)
+		} else {
+			sb.WriteRune(r)
+		}
+		i++
+	}
+	return sb.String()
+}
+
+// isLatinVowel проверяет, является ли буква латинской гласной
+func isLatinVowel(r rune) bool {
+	return strings.ContainsRune("aeiouy", r)
+}
+
+// levenshtein возвращает редакционное расстояние между строками
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// maxNameTypos сколько опечаток допускается в слове запроса заданной длины
+func maxNameTypos(length int) int {
+	switch {
+	case length <= 3:
+		return 0
+	case length <= 6:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// NameSearchFragments для каждого слова запроса возвращает куски нормализованного слова,
+// хотя бы один из которых точно есть в подходящем слове ФИО. Слово с k допустимыми опечатками
+// делится на k+1 частей: k правок не могут задеть их все. По кускам база отбирает кандидатов,
+// а окончательно их проверяет VetNameScore
+func NameSearchFragments(query string) [][]string {
+	var fragments [][]string
+	for _, queryWord := range strings.Fields(query) {
+		word := []rune(NormalizeName(queryWord))
+		if len(word) == 0 {
+			continue
+		}
+
+		parts := maxNameTypos(len(word)) + 1
+		pieces := make([]string, 0, parts)
+		for i := 0; i < parts; i++ {
+			pieces = append(pieces, string(word[i*len(word)/parts:(i+1)*len(word)/parts]))
+		}
+		fragments = append(fragments, pieces)
+	}
+	return fragments
+}
+
+// nameWordScore оценивает совпадение нормализованного слова запроса со словом ФИО (0 - не совпадает)
+func nameWordScore(query, word []rune) int {
+	if len(query) == 0 || len(word) == 0 {
+		return 0
+	}
+	if string(query) == string(word) {
+		return nameScoreExact
+	}
+	if len(query) >= 2 && len(query) < len(word) && string(word[:len(query)]) == string(query) {
+		return nameScorePrefix
+	}
+
+	typos := maxNameTypos(len(query))
+	if typos == 0 {
+		return 0
+	}
+	if distance := levenshtein(query, word); distance <= typos {
+		return nameScoreTypo - nameScoreTypoPenalty*distance
+	}
+	// Начало слова с опечаткой: "Кузнец" для "Кузнецова"
+	if len(query) < len(word) {
+		if distance := levenshtein(query, word[:len(query)]); distance <= typos {
+			return nameScoreTypoPrefix - nameScoreTypoPenalty*distance
+		}
+	}
+	return 0
+}
+
+// VetNameScore оценивает, насколько ФИО врача соответствует запросу. Каждое слово запроса
+// должно совпасть с фамилией, именем или отчеством (с опечатками); 0 - врач не подходит
+func VetNameScore(vet *Veterinarian, query string) int {
+	if vet == nil {
+		return 0
+	}
+
+	queryWords := strings.Fields(query)
+	if len(queryWords) == 0 {
+		return 0
+	}
+
+	// Фамилия первой: при равной оценке бонус получает она
+	nameWords := [][]rune{[]rune(NormalizeName(vet.LastName)), []rune(NormalizeName(vet.FirstName))}
+	if vet.Patronymic.Valid {
+		nameWords = append(nameWords, []rune(NormalizeName(vet.Patronymic.String)))
+	}
+
+	total := 0
+	used := make([]bool, len(nameWords))
+	for _, queryWord := range queryWords {
+		normalized := []rune(NormalizeName(queryWord))
+		if len(normalized) == 0 {
+			continue
+		}
+
+		best, bestIndex := 0, -1
+		for i, word := range nameWords {
+			if used[i] {
+				continue
+			}
+			if score := nameWordScore(normalized, word); score > best {
+				best, bestIndex = score, i
+			}
+		}
+		if bestIndex < 0 {
+			return 0
+		}
+
+		used[bestIndex] = true
+		total += best
+		if bestIndex == 0 {
+			total += nameScoreLastNameBonus
+		}
+	}
+	return total
+}
+
+// RankVetsByName отбирает врачей, подходящих под запрос, и сортирует по убыванию оценки, затем по ФИО.
+// limit <= 0 - без ограничения
+func RankVetsByName(vets []*Veterinarian, query string, limit int) []*Veterinarian {
+	type scoredVet struct {
+		vet   *Veterinarian
+		score int
+	}
+
+	var scored []scoredVet
+	for _, vet := range vets {
+		if score := VetNameScore(vet, query); score > 0 {
+			scored = append(scored, scoredVet{vet: vet, score: score})
+		}
+	}
+
+	sort.SliceStable(scored, func(i, j int) bool {
+		if scored[i].score != scored[j].score {
+			return scored[i].score > scored[j].score
+		}
+		if scored[i].vet.LastName != scored[j].vet.LastName {
+			return scored[i].vet.LastName < scored[j].vet.LastName
+		}
+		return scored[i].vet.FirstName < scored[j].vet.FirstName
+	})
+
+	if limit > 0 && len(scored) > limit {
+		scored = scored[:limit]
+	}
+
+	result := make([]*Veterinarian, 0, len(scored))
+	for _, item := range scored {
+		result = append(result, item.vet)
+	}
+	return result
+}
@@ -0,0 +1,280 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// minutesPerDay минут в сутках
+const minutesPerDay = 24 * 60
+
+// ParseClock разбирает время в формате HH:MM (допускаются секунды: HH:MM:SS) в минуты от начала суток
+func ParseClock(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Hour()*60 + t.Minute(), nil
+		}
+	}
+	return 0, fmt.Errorf("неверный формат времени '%s', ожидается ЧЧ:ММ", value)
+}
+
+// PreviousDay возвращает предыдущий день недели (1 - понедельник, 7 - воскресенье)
+func PreviousDay(day int) int {
+	if day <= 1 {
+		return 7
+	}
+	return day - 1
+}
+
+// IsOvernight проверяет, переходит ли прием через полночь (окончание раньше начала)
+func (s *Schedule) IsOvernight() bool {
+	start, errStart := ParseClock(s.StartTime)
+	end, errEnd := ParseClock(s.EndTime)
+	return errStart == nil && errEnd == nil && end < start
+}
+
+// IsWorkingAt проверяет, идет ли прием в день недели day (0 - любой день) во время clock (HH:MM).
+// Ночной прием продолжается до EndTime следующего дня: смена пн 20:00-08:00 покрывает и вт 03:00.
+// Выключенный прием не идет никогда
+func (s *Schedule) IsWorkingAt(day int, clock string) bool {
+	if !s.IsAvailable {
+		return false
+	}
+	at, err := ParseClock(clock)
+	if err != nil {
+		return false
+	}
+	start, err := ParseClock(s.StartTime)
+	if err != nil {
+		return false
+	}
+	end, err := ParseClock(s.EndTime)
+	if err != nil || start == end {
+		return false
+	}
+
+	anyDay := day == 0
+	if start < end {
+		return (anyDay || s.DayOfWeek == day) && at >= start && at < end
+	}
+
+	// Ночная смена: часть до полуночи в свой день, часть после - на следующий
+	if at >= start && (anyDay || s.DayOfWeek == day) {
+		return true
+	}
+	return at < end && (anyDay || s.DayOfWeek == PreviousDay(day))
+}
+
+// FormatClock форматирует минуты от начала суток в HH:MM
+func FormatClock(minutes int) string {
+	minutes = (minutes%minutesPerDay + minutesPerDay) % minutesPerDay
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// Validate проверяет день недели и время приема. Окончание раньше начала допустимо - это ночная смена
+func (s *Schedule) Validate() error {
+	if s.DayOfWeek < 1 || s.DayOfWeek > 7 {
+		return fmt.Errorf("день недели должен быть от 1 (понедельник) до 7 (воскресенье)")
+	}
+	start, err := ParseClock(s.StartTime)
+	if err != nil {
+		return err
+	}
+	end, err := ParseClock(s.EndTime)
+	if err != nil {
+		return err
+	}
+	if start == end {
+		return fmt.Errorf("время начала и окончания приема совпадают")
+	}
+	return nil
+}
+
+// minutesPerWeek минут в неделе
+const minutesPerWeek = 7 * minutesPerDay
+
+// weekMinutes возвращает прием как полуинтервал в минутах от начала недели (понедельник 00:00).
+// Ночная смена продолжается в следующий день, поэтому конец может выйти за воскресенье
+func (s *Schedule) weekMinutes() (int, int, bool) {
+	start, err := ParseClock(s.StartTime)
+	if err != nil {
+		return 0, 0, false
+	}
+	end, err := ParseClock(s.EndTime)
+	if err != nil || start == end || s.DayOfWeek < 1 || s.DayOfWeek > 7 {
+		return 0, 0, false
+	}
+	if end < start {
+		end += minutesPerDay
+	}
+	dayStart := (s.DayOfWeek - 1) * minutesPerDay
+	return dayStart + start, dayStart + end, true
+}
+
+// Overlaps проверяет, пересекаются ли два приема по времени с учетом ночных смен
+// и перехода с воскресенья на понедельник. Приемы встык (18:00 и 18:00) не пересекаются
+func (s *Schedule) Overlaps(other *Schedule) bool {
+	start, end, ok := s.weekMinutes()
+	if !ok {
+		return false
+	}
+	otherStart, otherEnd, ok := other.weekMinutes()
+	if !ok {
+		return false
+	}
+	for _, shift := range []int{-minutesPerWeek, 0, minutesPerWeek} {
+		if start < otherEnd+shift && otherStart+shift < end {
+			return true
+		}
+	}
+	return false
+}
+
+// FindOverlap возвращает первый доступный прием из schedules, пересекающийся с s.
+// Сам прием (с тем же ID) не учитывается; врач не может принимать в двух местах одновременно
+func (s *Schedule) FindOverlap(schedules []*Schedule) *Schedule {
+	for _, other := range schedules {
+		if other == s || (s.ID != 0 && other.ID == s.ID) || !other.IsAvailable {
+			continue
+		}
+		if s.Overlaps(other) {
+			return other
+		}
+	}
+	return nil
+}
+
+// ScheduleDateLayout формат дат исключений из расписания
+const ScheduleDateLayout = "02.01.2006"
+
+// ParseScheduleDate разбирает дату в формате ДД.ММ.ГГГГ
+func ParseScheduleDate(value string) (time.Time, error) {
+	date, err := time.Parse(ScheduleDateLayout, strings.TrimSpace(value))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("неверная дата '%s', ожидается ДД.ММ.ГГГГ", strings.TrimSpace(value))
+	}
+	return date, nil
+}
+
+// ScheduleDay возвращает день недели даты в формате расписания (1 - понедельник, 7 - воскресенье)
+func ScheduleDay(date time.Time) int {
+	day := int(date.Weekday())
+	if day == 0 {
+		return 7
+	}
+	return day
+}
+
+// calendarDate отбрасывает время и часовой пояс, чтобы даты из базы и локальные даты сравнивались по дню
+func calendarDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Covers проверяет, попадает ли дата в период исключения
+func (e *ScheduleException) Covers(date time.Time) bool {
+	day := calendarDate(date)
+	return !day.Before(calendarDate(e.DateFrom)) && !day.After(calendarDate(e.DateTo))
+}
+
+// AppliesTo проверяет, относится ли исключение к клинике
+func (e *ScheduleException) AppliesTo(clinicID int) bool {
+	return !e.ClinicID.Valid || int(e.ClinicID.Int64) == clinicID
+}
+
+// Validate проверяет период исключения и часы приема
+func (e *ScheduleException) Validate() error {
+	if calendarDate(e.DateTo).Before(calendarDate(e.DateFrom)) {
+		return fmt.Errorf("дата окончания раньше даты начала")
+	}
+	if !e.IsAvailable {
+		return nil
+	}
+	if !e.ClinicID.Valid {
+		return fmt.Errorf("для других часов приема нужно указать клинику")
+	}
+	schedule := Schedule{DayOfWeek: 1, StartTime: e.StartTime, EndTime: e.EndTime}
+	return schedule.Validate()
+}
+
+// EffectiveSchedules возвращает приемы врача на дату с учетом исключений: отсутствие убирает
+// приемы, а другие часы заменяют обычные приемы в своей клинике
+func EffectiveSchedules(schedules []*Schedule, exceptions []*ScheduleException, date time.Time) []*Schedule {
+	var active []*ScheduleException
+	for _, exception := range exceptions {
+		if exception.Covers(date) {
+			active = append(active, exception)
+		}
+	}
+
+	// affected - есть исключение любого вида, absent - врач не принимает
+	affected := func(clinicID int, absenceOnly bool) bool {
+		for _, exception := range active {
+			if exception.AppliesTo(clinicID) && (!absenceOnly || !exception.IsAvailable) {
+				return true
+			}
+		}
+		return false
+	}
+
+	day := ScheduleDay(date)
+	var result []*Schedule
+	for _, schedule := range schedules {
+		if schedule.IsAvailable && schedule.DayOfWeek == day && !affected(schedule.ClinicID, false) {
+			result = append(result, schedule)
+		}
+	}
+	for _, exception := range active {
+		clinicID := int(exception.ClinicID.Int64)
+		if !exception.IsAvailable || affected(clinicID, true) {
+			continue
+		}
+		result = append(result, &Schedule{
+			VetID:       exception.VetID,
+			ClinicID:    clinicID,
+			DayOfWeek:   day,
+			StartTime:   exception.StartTime,
+			EndTime:     exception.EndTime,
+			IsAvailable: true,
+			Clinic:      exception.Clinic,
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].StartTime < result[j].StartTime })
+	return result
+}
+
+// NearestWorkingDay ищет ближайший прием врача в пределах days дней начиная с from
+// с учетом исключений. Возвращает прием и его дату или nil, если приемов нет
+func NearestWorkingDay(schedules []*Schedule, exceptions []*ScheduleException, from time.Time, days int) (*Schedule, time.Time) {
+	today := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	for i := 0; i < days; i++ {
+		date := today.AddDate(0, 0, i)
+		for _, schedule := range EffectiveSchedules(schedules, exceptions, date) {
+			if schedule.StartTime != "" && schedule.EndTime != "" &&
+				schedule.StartTime != "00:00" && schedule.EndTime != "00:00" {
+				return schedule, date
+			}
+		}
+	}
+	return nil, time.Time{}
+}
+
+// SearchDateLayout формат даты в критериях поиска
+const SearchDateLayout = "2006-01-02"
+
+// ScheduleWindow возвращает начало недели, на которую проверяется расписание при поиске,
+// и искомый день недели. Без даты неделя начинается с now, а день берется из DayOfWeek.
+// С датой неделя начинается накануне, чтобы ночные смены предыдущего дня тоже попадали в поиск
+func (c *SearchCriteria) ScheduleWindow(now time.Time) (time.Time, int, error) {
+	if c.Date == "" {
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()), c.DayOfWeek, nil
+	}
+	date, err := time.ParseInLocation(SearchDateLayout, c.Date, now.Location())
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("неверная дата поиска '%s'", c.Date)
+	}
+	return date.AddDate(0, 0, -1), ScheduleDay(date), nil
+}
@@ -0,0 +1,138 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	_ "time/tzdata" // база часовых поясов встроена: в контейнере может не быть tzdata
+)
+
+// DefaultTimezone часовой пояс для городов и клиник, у которых он не указан
+const DefaultTimezone = "Europe/Moscow"
+
+// TimezoneOption часовой пояс для выбора в админке
+type TimezoneOption struct {
+	Name  string // Имя IANA
+	Label string // Подпись для кнопки
+}
+
+// RussianTimezones часовые пояса России от Калининграда до Камчатки
+var RussianTimezones = []TimezoneOption{
+	{Name: "Europe/Kaliningrad", Label: "Калининград (UTC+2)"},
+	{Name: "Europe/Moscow", Label: "Москва (UTC+3)"},
+	{Name: "Europe/Samara", Label: "Самара (UTC+4)"},
+	{Name: "Asia/Yekaterinburg", Label: "Екатеринбург (UTC+5)"},
+	{Name: "Asia/Omsk", Label: "Омск (UTC+6)"},
+	{Name: "Asia/Novosibirsk", Label: "Новосибирск (UTC+7)"},
+	{Name: "Asia/Krasnoyarsk", Label: "Красноярск (UTC+7)"},
+	{Name: "Asia/Irkutsk", Label: "Иркутск (UTC+8)"},
+	{Name: "Asia/Yakutsk", Label: "Якутск (UTC+9)"},
+	{Name: "Asia/Vladivostok", Label: "Владивосток (UTC+10)"},
+	{Name: "Asia/Magadan", Label: "Магадан (UTC+11)"},
+	{Name: "Asia/Kamchatka", Label: "Камчатка (UTC+12)"},
+}
+
+// locations кэш загруженных часовых поясов по имени
+var locations sync.Map
+
+// LoadTimezone возвращает часовой пояс по имени IANA, пустое имя - DefaultTimezone
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		name = DefaultTimezone
+	}
+	if cached, ok := locations.Load(name); ok {
+		return cached.(*time.Location), nil
+	}
+
+	// "Local" и "UTC" зависят от сервера и не описывают город
+	if !strings.Contains(name, "/") {
+		return nil, fmt.Errorf("неизвестный часовой пояс '%s', ожидается имя вида Europe/Moscow", name)
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("неизвестный часовой пояс '%s', ожидается имя вида Europe/Moscow", name)
+	}
+	locations.Store(name, location)
+	return location, nil
+}
+
+// DefaultLocation возвращает часовой пояс по умолчанию
+func DefaultLocation() *time.Location {
+	location, err := LoadTimezone(DefaultTimezone)
+	if err != nil {
+		// Встроенная база tzdata содержит Europe/Moscow, сюда попасть нельзя
+		return time.FixedZone("MSK", 3*60*60)
+	}
+	return location
+}
+
+// ParseTimezone разбирает часовой пояс, введенный админом: имя IANA или подпись из RussianTimezones.
+// Возвращает имя IANA
+func ParseTimezone(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	for _, option := range RussianTimezones {
+		if strings.EqualFold(value, option.Label) || strings.EqualFold(value, option.Name) {
+			return option.Name, nil
+		}
+	}
+	if value == "" {
+		return "", fmt.Errorf("часовой пояс не указан")
+	}
+	if _, err := LoadTimezone(value); err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+// FormatTimezone описывает часовой пояс со смещением от UTC на момент now: "Asia/Novosibirsk (UTC+7)"
+func FormatTimezone(name string, now time.Time) string {
+	if name == "" {
+		name = DefaultTimezone
+	}
+	location, err := LoadTimezone(name)
+	if err != nil {
+		return name
+	}
+
+	_, offset := now.In(location).Zone()
+	sign := "+"
+	if offset < 0 {
+		sign, offset = "-", -offset
+	}
+	if offset%3600 == 0 {
+		return fmt.Sprintf("%s (UTC%s%d)", name, sign, offset/3600)
+	}
+	return fmt.Sprintf("%s (UTC%s%d:%02d)", name, sign, offset/3600, offset%3600/60)
+}
+
+// Location возвращает часовой пояс города; если он не указан или неизвестен - пояс по умолчанию
+func (c *City) Location() *time.Location {
+	if c == nil {
+		return DefaultLocation()
+	}
+	location, err := LoadTimezone(c.Timezone)
+	if err != nil {
+		return DefaultLocation()
+	}
+	return location
+}
+
+// Location возвращает часовой пояс клиники по ее городу
+func (c *Clinic) Location() *time.Location {
+	if c == nil {
+		return DefaultLocation()
+	}
+	return c.City.Location()
+}
+
+// SchedulesLocation возвращает часовой пояс врача по городу клиники из его расписания.
+// Клиники одного врача находятся в одном городе, поэтому берется первая клиника с городом
+func SchedulesLocation(schedules []*Schedule) *time.Location {
+	for _, schedule := range schedules {
+		if schedule.Clinic != nil && schedule.Clinic.City != nil {
+			return schedule.Clinic.Location()
+		}
+	}
+	return DefaultLocation()
+}
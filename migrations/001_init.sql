@@ -84,7 +84,7 @@ CREATE TABLE IF NOT EXISTS user_requests (
 -- Условное создание constraints с обработкой ошибок:
 DO $$ 
 BEGIN
-    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'unique_vet_identity') THEN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'unique_vet_identity' AND conrelid = 'veterinarians'::regclass) THEN
         ALTER TABLE veterinarians ADD CONSTRAINT unique_vet_identity UNIQUE (first_name, last_name, phone);
     END IF;
 EXCEPTION
@@ -93,7 +93,7 @@ END $$;
 
 DO $$ 
 BEGIN
-    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'unique_city_name') THEN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'unique_city_name' AND conrelid = 'cities'::regclass) THEN
         ALTER TABLE cities ADD CONSTRAINT unique_city_name UNIQUE (name);
     END IF;
 EXCEPTION
@@ -102,7 +102,7 @@ END $$;
 
 DO $$ 
 BEGIN
-    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'unique_clinic_address') THEN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'unique_clinic_address' AND conrelid = 'clinics'::regclass) THEN
         ALTER TABLE clinics ADD CONSTRAINT unique_clinic_address UNIQUE (name, address);
     END IF;
 EXCEPTION
@@ -111,7 +111,7 @@ END $$;
 
 DO $$ 
 BEGIN
-    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'unique_specialization_name') THEN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'unique_specialization_name' AND conrelid = 'specializations'::regclass) THEN
         ALTER TABLE specializations ADD CONSTRAINT unique_specialization_name UNIQUE (name);
     END IF;
 EXCEPTION
@@ -171,7 +171,10 @@ DELETE FROM schedules WHERE clinic_id NOT IN (SELECT id FROM clinics);
 DELETE FROM schedules WHERE vet_id NOT IN (SELECT id FROM veterinarians);
 
 -- Создаем расписания (только для существующих врачей и клиник)
-INSERT INTO schedules (vet_id, clinic_id, day_of_week, start_time, end_time) VALUES 
+-- У schedules нет уникального ключа, поэтому дубликаты отсекаем явно
+INSERT INTO schedules (vet_id, clinic_id, day_of_week, start_time, end_time)
+SELECT v.vet_id, v.clinic_id, v.day_of_week, v.start_time::time, v.end_time::time
+FROM (VALUES 
 (1, 1, 1, '09:00', '15:00'),
 (1, 1, 3, '09:00', '15:00'),
 (1, 1, 5, '09:00', '15:00'),
@@ -182,7 +185,12 @@ INSERT INTO schedules (vet_id, clinic_id, day_of_week, start_time, end_time) VAL
 (3, 1, 5, '10:00', '16:00'),
 (1, 1, 2, '14:00', '20:00'),  
 (2, 1, 5, '10:00', '16:00')
-ON CONFLICT DO NOTHING;
+) AS v(vet_id, clinic_id, day_of_week, start_time, end_time)
+WHERE NOT EXISTS (
+    SELECT 1 FROM schedules s
+    WHERE s.vet_id = v.vet_id AND s.clinic_id = v.clinic_id
+      AND s.day_of_week = v.day_of_week AND s.start_time = v.start_time::time
+);
 
 
 -- Добавляем индексы для улучшения производительности поиска
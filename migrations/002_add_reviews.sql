@@ -29,7 +29,7 @@ WHERE status = 'approved';
 -- Ограничение на длину комментария (с обработкой если уже существует)
 DO $$ 
 BEGIN
-    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'check_comment_length') THEN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'check_comment_length' AND conrelid = 'reviews'::regclass) THEN
         ALTER TABLE reviews ADD CONSTRAINT check_comment_length CHECK (length(comment) <= 500);
     END IF;
 EXCEPTION
@@ -1,2 +1,18 @@
--- Исправляем имя колонки в таблице reviews
-ALTER TABLE reviews RENAME COLUMN moderated_by TO moderator_id;
+-- Исправляем имя колонки в таблице reviews:
+-- 002 создает moderated_by, а код работает с moderator_id (добавлена в 004)
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_schema = current_schema() AND table_name = 'reviews'
+                 AND column_name = 'moderated_by') THEN
+        IF EXISTS (SELECT 1 FROM information_schema.columns
+                   WHERE table_schema = current_schema() AND table_name = 'reviews'
+                     AND column_name = 'moderator_id') THEN
+            UPDATE reviews SET moderator_id = moderated_by
+            WHERE moderator_id IS NULL AND moderated_by IS NOT NULL;
+            ALTER TABLE reviews DROP COLUMN moderated_by;
+        ELSE
+            ALTER TABLE reviews RENAME COLUMN moderated_by TO moderator_id;
+        END IF;
+    END IF;
+END $$;
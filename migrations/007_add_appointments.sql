@@ -0,0 +1,25 @@
+-- Миграция для записи на прием к врачу
+
+-- Таблица записей на прием
+CREATE TABLE IF NOT EXISTS appointments (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    vet_id INTEGER NOT NULL REFERENCES veterinarians(id) ON DELETE CASCADE,
+    clinic_id INTEGER NOT NULL REFERENCES clinics(id) ON DELETE CASCADE,
+    appointment_date DATE NOT NULL,
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'booked' CHECK (status IN ('booked', 'cancelled')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    cancelled_at TIMESTAMP
+);
+
+-- Индексы для улучшения производительности
+CREATE INDEX IF NOT EXISTS idx_appointments_user_id ON appointments(user_id);
+CREATE INDEX IF NOT EXISTS idx_appointments_vet_date ON appointments(vet_id, appointment_date);
+
+-- Защита от двойной записи: у врача не может быть двух активных записей на одно время
+-- Отмененные записи не учитываются, поэтому освободившийся слот можно занять снова
+CREATE UNIQUE INDEX IF NOT EXISTS idx_appointments_vet_slot_unique
+ON appointments(vet_id, appointment_date, start_time)
+WHERE status = 'booked';
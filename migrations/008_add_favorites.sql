@@ -0,0 +1,11 @@
+-- Миграция для избранных врачей пользователя
+
+CREATE TABLE IF NOT EXISTS favorites (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    vet_id INTEGER NOT NULL REFERENCES veterinarians(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, vet_id)
+);
+
+-- Индексы для улучшения производительности
+CREATE INDEX IF NOT EXISTS idx_favorites_user_id ON favorites(user_id);
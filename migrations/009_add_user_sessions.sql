@@ -0,0 +1,12 @@
+-- Миграция для хранения сессий пользователей (состояния диалогов переживают перезапуск бота)
+
+CREATE TABLE IF NOT EXISTS user_sessions (
+    telegram_id BIGINT PRIMARY KEY,
+    state TEXT NOT NULL DEFAULT '',
+    data JSONB NOT NULL DEFAULT '{}',
+    history JSONB NOT NULL DEFAULT '[]',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Индекс для поиска устаревших сессий
+CREATE INDEX IF NOT EXISTS idx_user_sessions_updated_at ON user_sessions(updated_at);
@@ -0,0 +1,4 @@
+-- Колонки is_active для баз, созданных до их появления в 001
+-- (раньше добавлялись при каждом запуске в Database.AddMissingColumns)
+ALTER TABLE clinics ADD COLUMN IF NOT EXISTS is_active BOOLEAN DEFAULT TRUE;
+ALTER TABLE veterinarians ADD COLUMN IF NOT EXISTS is_active BOOLEAN DEFAULT TRUE;
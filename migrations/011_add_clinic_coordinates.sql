@@ -0,0 +1,26 @@
+-- Координаты клиник для поиска ближайших по геопозиции пользователя
+ALTER TABLE clinics ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION;
+ALTER TABLE clinics ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'check_clinic_coordinates' AND conrelid = 'clinics'::regclass) THEN
+        ALTER TABLE clinics ADD CONSTRAINT check_clinic_coordinates CHECK (
+            (latitude IS NULL AND longitude IS NULL) OR
+            (latitude BETWEEN -90 AND 90 AND longitude BETWEEN -180 AND 180)
+        );
+    END IF;
+END $$;
+
+-- Координаты тестовых клиник из 001
+UPDATE clinics SET latitude = 55.7577, longitude = 37.6156
+WHERE name = 'ВетКлиника Центр' AND address = 'ул. Центральная, д. 1' AND latitude IS NULL;
+UPDATE clinics SET latitude = 55.8547, longitude = 37.4761
+WHERE name = 'ВетКлиника Север' AND address = 'ул. Северная, д. 25' AND latitude IS NULL;
+UPDATE clinics SET latitude = 59.9326, longitude = 30.3497
+WHERE name = 'ВетКлиника Петербург' AND address = 'Невский пр-т, д. 100' AND latitude IS NULL;
+UPDATE clinics SET latitude = 55.7306, longitude = 37.4466
+WHERE name = 'ВетКлиника Запад' AND address = 'ул. Западная, д. 50' AND latitude IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_clinics_coordinates ON clinics(latitude, longitude)
+WHERE latitude IS NOT NULL AND longitude IS NOT NULL;
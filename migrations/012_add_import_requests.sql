@@ -0,0 +1,19 @@
+-- Задачи импорта справочника: статус, прогресс и итоги фоновой обработки файлов
+
+CREATE TABLE IF NOT EXISTS import_requests (
+    id SERIAL PRIMARY KEY,
+    type VARCHAR(20) NOT NULL,
+    file_name TEXT NOT NULL,
+    user_id BIGINT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'processing', 'ready', 'completed', 'failed', 'cancelled')),
+    processed_rows INTEGER NOT NULL DEFAULT 0,
+    total_rows INTEGER NOT NULL DEFAULT 0,
+    result JSONB,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+-- Список последних задач в админ-панели
+CREATE INDEX IF NOT EXISTS idx_import_requests_created_at ON import_requests(created_at DESC);
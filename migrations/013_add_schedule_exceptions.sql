@@ -0,0 +1,22 @@
+-- Исключения из недельного расписания врачей: отпуск, болезнь или другие часы приема на даты
+
+CREATE TABLE IF NOT EXISTS schedule_exceptions (
+    id SERIAL PRIMARY KEY,
+    vet_id INTEGER NOT NULL REFERENCES veterinarians(id) ON DELETE CASCADE,
+    -- NULL - исключение действует во всех клиниках врача
+    clinic_id INTEGER REFERENCES clinics(id) ON DELETE CASCADE,
+    date_from DATE NOT NULL,
+    date_to DATE NOT NULL,
+    -- false - врач не принимает, true - принимает в start_time-end_time вместо обычных часов
+    is_available BOOLEAN NOT NULL DEFAULT false,
+    start_time TIME,
+    end_time TIME,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT check_schedule_exception_dates CHECK (date_to >= date_from),
+    CONSTRAINT check_schedule_exception_hours CHECK (
+        NOT is_available OR (clinic_id IS NOT NULL AND start_time IS NOT NULL AND end_time IS NOT NULL)
+    )
+);
+
+CREATE INDEX IF NOT EXISTS idx_schedule_exceptions_vet_dates ON schedule_exceptions(vet_id, date_to, date_from);
@@ -0,0 +1,12 @@
+-- Часовой пояс города: расписание, "сегодня" и "сейчас работают" считаются по местному времени
+ALTER TABLE cities ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'Europe/Moscow';
+
+-- Часовые пояса городов из 001 и других крупных городов, если они уже добавлены
+UPDATE cities SET timezone = 'Europe/Moscow'
+WHERE name IN ('Москва', 'Санкт-Петербург', 'Казань', 'Нижний Новгород', 'Краснодар', 'Воронеж', 'Ростов-на-Дону');
+UPDATE cities SET timezone = 'Europe/Samara' WHERE name = 'Самара';
+UPDATE cities SET timezone = 'Asia/Yekaterinburg' WHERE name IN ('Екатеринбург', 'Челябинск', 'Пермь', 'Уфа', 'Тюмень');
+UPDATE cities SET timezone = 'Asia/Novosibirsk' WHERE name = 'Новосибирск';
+UPDATE cities SET timezone = 'Europe/Kaliningrad' WHERE name = 'Калининград';
+UPDATE cities SET timezone = 'Asia/Omsk' WHERE name = 'Омск';
+UPDATE cities SET timezone = 'Asia/Krasnoyarsk' WHERE name = 'Красноярск';
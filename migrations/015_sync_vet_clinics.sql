@@ -0,0 +1,7 @@
+-- Связь врача с клиникой следует из его расписания: приемы, добавленные через админку,
+-- раньше не создавали связь в vet_clinics. Восстанавливаем недостающие связи
+INSERT INTO vet_clinics (vet_id, clinic_id)
+SELECT DISTINCT vet_id, clinic_id
+FROM schedules
+WHERE vet_id IS NOT NULL AND clinic_id IS NOT NULL
+ON CONFLICT (vet_id, clinic_id) DO NOTHING;
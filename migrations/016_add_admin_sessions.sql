@@ -0,0 +1,4 @@
+-- Состояние и временные данные админ-панели хранятся в сессии пользователя,
+-- чтобы незавершенные действия администратора переживали перезапуск бота
+ALTER TABLE user_sessions ADD COLUMN IF NOT EXISTS admin_state TEXT NOT NULL DEFAULT '';
+ALTER TABLE user_sessions ADD COLUMN IF NOT EXISTS admin_data JSONB NOT NULL DEFAULT '{}';
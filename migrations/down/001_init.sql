@@ -0,0 +1,9 @@
+-- Откат 001: удаляем базовые таблицы вместе с данными
+DROP TABLE IF EXISTS user_requests CASCADE;
+DROP TABLE IF EXISTS users CASCADE;
+DROP TABLE IF EXISTS schedules CASCADE;
+DROP TABLE IF EXISTS clinics CASCADE;
+DROP TABLE IF EXISTS vet_specializations CASCADE;
+DROP TABLE IF EXISTS veterinarians CASCADE;
+DROP TABLE IF EXISTS cities CASCADE;
+DROP TABLE IF EXISTS specializations CASCADE;
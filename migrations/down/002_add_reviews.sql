@@ -0,0 +1,2 @@
+-- Откат 002: удаляем систему отзывов
+DROP TABLE IF EXISTS reviews;
@@ -0,0 +1,2 @@
+-- Откат 003: удаляем поле отчества
+ALTER TABLE veterinarians DROP COLUMN IF EXISTS patronymic;
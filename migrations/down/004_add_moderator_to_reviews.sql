@@ -0,0 +1,3 @@
+-- Откат 004: moderated_at и idx_reviews_status создаются в 002, их не трогаем
+DROP INDEX IF EXISTS idx_reviews_moderated_at;
+ALTER TABLE reviews DROP COLUMN IF EXISTS moderator_id;
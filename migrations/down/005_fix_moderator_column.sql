@@ -0,0 +1,3 @@
+-- Откат 005: возвращаем колонку moderated_by из 002 (moderator_id остается за 004)
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS moderated_by INTEGER REFERENCES users(id) ON DELETE SET NULL;
+UPDATE reviews SET moderated_by = moderator_id WHERE moderated_by IS NULL;
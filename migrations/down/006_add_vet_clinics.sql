@@ -0,0 +1,2 @@
+-- Откат 006: удаляем связь врачей и клиник (расписание остается)
+DROP TABLE IF EXISTS vet_clinics;
@@ -0,0 +1,2 @@
+-- Откат 007: удаляем записи на прием
+DROP TABLE IF EXISTS appointments;
@@ -0,0 +1,2 @@
+-- Откат 008: удаляем избранных врачей
+DROP TABLE IF EXISTS favorites;
@@ -0,0 +1,2 @@
+-- Откат 009: удаляем сохраненные сессии пользователей
+DROP TABLE IF EXISTS user_sessions;
@@ -0,0 +1 @@
+-- Откат 010: is_active входит в схему 001, поэтому колонки не удаляем
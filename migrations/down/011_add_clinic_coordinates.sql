@@ -0,0 +1,5 @@
+-- Откат 011: удаляем координаты клиник
+DROP INDEX IF EXISTS idx_clinics_coordinates;
+ALTER TABLE clinics DROP CONSTRAINT IF EXISTS check_clinic_coordinates;
+ALTER TABLE clinics DROP COLUMN IF EXISTS latitude;
+ALTER TABLE clinics DROP COLUMN IF EXISTS longitude;
@@ -0,0 +1,2 @@
+-- Откат 012: удаляем задачи импорта
+DROP TABLE IF EXISTS import_requests;
@@ -0,0 +1,2 @@
+-- Откат 013: удаляем исключения из расписания
+DROP TABLE IF EXISTS schedule_exceptions;
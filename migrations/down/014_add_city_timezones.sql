@@ -0,0 +1,2 @@
+-- Откат 014: удаляем часовые пояса городов
+ALTER TABLE cities DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1 @@
+-- Откат 015: восстановленные связи остаются - их нельзя отличить от добавленных вручную
@@ -0,0 +1,3 @@
+-- Откат 016: удаляем состояние админ-панели из сессий
+ALTER TABLE user_sessions DROP COLUMN IF EXISTS admin_data;
+ALTER TABLE user_sessions DROP COLUMN IF EXISTS admin_state;
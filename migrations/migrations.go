@@ -0,0 +1,10 @@
+// Package migrations содержит SQL миграции базы данных, встроенные в бинарник.
+// Файлы NNN_name.sql применяются по возрастанию номера, down/NNN_name.sql откатывают их
+package migrations
+
+import "embed"
+
+// FS встроенные файлы миграций
+//
+//go:embed *.sql down/*.sql
+var FS embed.FS
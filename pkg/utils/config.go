@@ -6,6 +6,13 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
+)
+
+// Режимы получения обновлений от Telegram
+const (
+	UpdateModePolling = "polling"
+	UpdateModeWebhook = "webhook"
 )
 
 // Config содержит все конфигурационные параметры приложения
@@ -14,6 +21,31 @@ type Config struct {
 	DatabaseURL   string
 	Debug         bool
 	AdminIDs      []int64
+
+	// Параметры получения обновлений
+	UpdateMode        string
+	WebhookURL        string
+	WebhookSecret     string
+	WebhookListenAddr string
+
+	// Параллельная обработка обновлений: число воркеров и размер очереди каждого из них
+	UpdateWorkers   int
+	UpdateQueueSize int
+
+	// Время жизни незавершенных сессий по семействам состояний (0 - без ограничения)
+	SessionTTLReview     time.Duration
+	SessionTTLAdmin      time.Duration
+	SessionTTLSearch     time.Duration
+	SessionSweepInterval time.Duration
+
+	// Публичный JSON API справочника (пустой адрес - API выключен)
+	APIListenAddr  string
+	APICacheMaxAge time.Duration
+
+	// Админский API управления справочником (пустой адрес - API выключен).
+	// AdminAPITokens - соответствие токена Telegram ID администратора
+	AdminAPIListenAddr string
+	AdminAPITokens     map[string]int64
 }
 
 // LoadConfig загружает конфигурацию из переменных окружения
@@ -56,9 +88,221 @@ func LoadConfig() (*Config, error) {
 		log.Printf("No admin IDs configured")
 	}
 
+	// Режим получения обновлений (опционально, по умолчанию long polling)
+	if err := loadUpdateModeConfig(config); err != nil {
+		return nil, err
+	}
+
+	// Пул обработчиков обновлений (опционально)
+	if err := loadDispatcherConfig(config); err != nil {
+		return nil, err
+	}
+
+	// Время жизни сессий (опционально)
+	if err := loadSessionConfig(config); err != nil {
+		return nil, err
+	}
+
+	// Публичный API (опционально)
+	if err := loadAPIConfig(config); err != nil {
+		return nil, err
+	}
+
+	// Админский API (опционально)
+	if err := loadAdminAPIConfig(config); err != nil {
+		return nil, err
+	}
+
 	return config, nil
 }
 
+// loadDispatcherConfig загружает размер пула обработчиков обновлений
+func loadDispatcherConfig(config *Config) error {
+	values := []struct {
+		key          string
+		defaultValue string
+		target       *int
+	}{
+		{"UPDATE_WORKERS", "8", &config.UpdateWorkers},
+		{"UPDATE_QUEUE_SIZE", "100", &config.UpdateQueueSize},
+	}
+
+	for _, v := range values {
+		value, err := strconv.Atoi(getEnv(v.key, v.defaultValue))
+		if err != nil || value <= 0 {
+			return fmt.Errorf("invalid %s: expected positive integer", v.key)
+		}
+		*v.target = value
+	}
+
+	log.Printf("Update workers: %d (queue size %d per worker)", config.UpdateWorkers, config.UpdateQueueSize)
+	return nil
+}
+
+// loadSessionConfig загружает время жизни сессий и интервал их очистки
+func loadSessionConfig(config *Config) error {
+	durations := []struct {
+		key          string
+		defaultValue string
+		target       *time.Duration
+	}{
+		{"SESSION_TTL_REVIEW", "30m", &config.SessionTTLReview},
+		{"SESSION_TTL_ADMIN", "2h", &config.SessionTTLAdmin},
+		{"SESSION_TTL_SEARCH", "24h", &config.SessionTTLSearch},
+		{"SESSION_SWEEP_INTERVAL", "1m", &config.SessionSweepInterval},
+	}
+
+	for _, d := range durations {
+		value, err := time.ParseDuration(getEnv(d.key, d.defaultValue))
+		if err != nil || value < 0 {
+			return fmt.Errorf("invalid %s: expected duration like 30m or 2h", d.key)
+		}
+		*d.target = value
+	}
+
+	if config.SessionSweepInterval == 0 {
+		return fmt.Errorf("SESSION_SWEEP_INTERVAL must be positive")
+	}
+
+	log.Printf("Session TTL: review %v, admin %v, search %v (sweep every %v)",
+		config.SessionTTLReview, config.SessionTTLAdmin, config.SessionTTLSearch, config.SessionSweepInterval)
+	return nil
+}
+
+// loadAPIConfig загружает адрес публичного API и время кэширования его ответов
+func loadAPIConfig(config *Config) error {
+	config.APIListenAddr = getEnv("API_LISTEN_ADDR", "")
+	if config.APIListenAddr == "" {
+		log.Printf("Public API disabled")
+		return nil
+	}
+
+	if config.UpdateMode == UpdateModeWebhook && config.APIListenAddr == config.WebhookListenAddr {
+		return fmt.Errorf("API_LISTEN_ADDR must differ from the webhook listen address %s", config.WebhookListenAddr)
+	}
+
+	maxAge, err := time.ParseDuration(getEnv("API_CACHE_MAX_AGE", "1m"))
+	if err != nil || maxAge < 0 {
+		return fmt.Errorf("invalid API_CACHE_MAX_AGE: expected duration like 30s or 5m")
+	}
+	config.APICacheMaxAge = maxAge
+
+	log.Printf("Public API listen address: %s (cache max-age %v)", config.APIListenAddr, config.APICacheMaxAge)
+	return nil
+}
+
+// minAdminAPITokenLength минимальная длина токена админского API
+const minAdminAPITokenLength = 16
+
+// loadAdminAPIConfig загружает адрес админского API и токены в формате ADMIN_API_TOKENS=telegramID:token,...
+func loadAdminAPIConfig(config *Config) error {
+	config.AdminAPIListenAddr = getEnv("ADMIN_API_LISTEN_ADDR", "")
+	if config.AdminAPIListenAddr == "" {
+		log.Printf("Admin API disabled")
+		return nil
+	}
+
+	if config.UpdateMode == UpdateModeWebhook && config.AdminAPIListenAddr == config.WebhookListenAddr {
+		return fmt.Errorf("ADMIN_API_LISTEN_ADDR must differ from the webhook listen address %s", config.WebhookListenAddr)
+	}
+	if config.AdminAPIListenAddr == config.APIListenAddr {
+		return fmt.Errorf("ADMIN_API_LISTEN_ADDR must differ from API_LISTEN_ADDR")
+	}
+
+	config.AdminAPITokens = make(map[string]int64)
+	for _, entry := range strings.Split(getEnv("ADMIN_API_TOKENS", ""), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		idStr, token, found := strings.Cut(entry, ":")
+		adminID, err := strconv.ParseInt(strings.TrimSpace(idStr), 10, 64)
+		if !found || err != nil {
+			return fmt.Errorf("invalid ADMIN_API_TOKENS entry: expected telegramID:token")
+		}
+		token = strings.TrimSpace(token)
+		if len(token) < minAdminAPITokenLength {
+			return fmt.Errorf("ADMIN_API_TOKENS token for %d is shorter than %d characters", adminID, minAdminAPITokenLength)
+		}
+		if !containsID(config.AdminIDs, adminID) {
+			return fmt.Errorf("ADMIN_API_TOKENS token owner %d is not listed in ADMIN_IDS", adminID)
+		}
+		if _, exists := config.AdminAPITokens[token]; exists {
+			return fmt.Errorf("ADMIN_API_TOKENS contains a duplicate token")
+		}
+		config.AdminAPITokens[token] = adminID
+	}
+
+	if len(config.AdminAPITokens) == 0 {
+		return fmt.Errorf("ADMIN_API_TOKENS is required when ADMIN_API_LISTEN_ADDR is set")
+	}
+
+	log.Printf("Admin API listen address: %s (%d tokens)", config.AdminAPIListenAddr, len(config.AdminAPITokens))
+	return nil
+}
+
+// containsID проверяет, есть ли ID в списке
+func containsID(ids []int64, id int64) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+// loadUpdateModeConfig загружает настройки режима получения обновлений
+func loadUpdateModeConfig(config *Config) error {
+	config.UpdateMode = strings.ToLower(getEnv("UPDATE_MODE", UpdateModePolling))
+	log.Printf("Update mode: %s", config.UpdateMode)
+
+	switch config.UpdateMode {
+	case UpdateModePolling:
+		return nil
+	case UpdateModeWebhook:
+	default:
+		return fmt.Errorf("unknown UPDATE_MODE %q, expected %q or %q",
+			config.UpdateMode, UpdateModePolling, UpdateModeWebhook)
+	}
+
+	config.WebhookURL = getEnv("WEBHOOK_URL", "")
+	if config.WebhookURL == "" {
+		return fmt.Errorf("WEBHOOK_URL is required in webhook mode")
+	}
+	if !strings.HasPrefix(config.WebhookURL, "https://") {
+		return fmt.Errorf("WEBHOOK_URL must use https")
+	}
+
+	// Telegram допускает в секрете только A-Z, a-z, 0-9, _ и -, длиной 1-256
+	config.WebhookSecret = getEnv("WEBHOOK_SECRET", "")
+	if !isValidWebhookSecret(config.WebhookSecret) {
+		return fmt.Errorf("WEBHOOK_SECRET is required in webhook mode (1-256 chars: A-Z, a-z, 0-9, _ and -)")
+	}
+
+	// PaaS обычно передают порт через PORT
+	config.WebhookListenAddr = getEnv("WEBHOOK_LISTEN_ADDR", "")
+	if config.WebhookListenAddr == "" {
+		config.WebhookListenAddr = ":" + getEnv("PORT", "8080")
+	}
+	log.Printf("Webhook URL: %s, listen address: %s", config.WebhookURL, config.WebhookListenAddr)
+
+	return nil
+}
+
+// isValidWebhookSecret проверяет секрет webhook по правилам Telegram
+func isValidWebhookSecret(secret string) bool {
+	if len(secret) == 0 || len(secret) > 256 {
+		return false
+	}
+	for _, r := range secret {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
 // parseAdminIDs парсит строку с ID администраторов
 func parseAdminIDs(adminIDsStr string) []int64 {
 	var adminIDs []int64
@@ -3,6 +3,7 @@ package utils
 import (
 	"os"
 	"testing"
+	"time"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -266,3 +267,268 @@ func TestGetEnv(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadConfigUpdateMode(t *testing.T) {
+	t.Setenv("TELEGRAM_TOKEN", "token")
+	t.Setenv("DATABASE_URL", "url")
+
+	tests := []struct {
+		name         string
+		env          map[string]string
+		wantErr      bool
+		expectMode   string
+		expectListen string
+	}{
+		{
+			name:       "Polling by default",
+			env:        map[string]string{},
+			expectMode: UpdateModePolling,
+		},
+		{
+			name: "Webhook with PORT",
+			env: map[string]string{
+				"UPDATE_MODE":    "webhook",
+				"WEBHOOK_URL":    "https://example.com/hook",
+				"WEBHOOK_SECRET": "s3cret_token-1",
+				"PORT":           "9000",
+			},
+			expectMode:   UpdateModeWebhook,
+			expectListen: ":9000",
+		},
+		{
+			name: "Explicit listen address",
+			env: map[string]string{
+				"UPDATE_MODE":         "WEBHOOK",
+				"WEBHOOK_URL":         "https://example.com/hook",
+				"WEBHOOK_SECRET":      "secret",
+				"WEBHOOK_LISTEN_ADDR": "127.0.0.1:8443",
+			},
+			expectMode:   UpdateModeWebhook,
+			expectListen: "127.0.0.1:8443",
+		},
+		{
+			name:    "Webhook without URL",
+			env:     map[string]string{"UPDATE_MODE": "webhook", "WEBHOOK_SECRET": "secret"},
+			wantErr: true,
+		},
+		{
+			name:    "Webhook without https",
+			env:     map[string]string{"UPDATE_MODE": "webhook", "WEBHOOK_URL": "http://example.com", "WEBHOOK_SECRET": "secret"},
+			wantErr: true,
+		},
+		{
+			name:    "Webhook with invalid secret",
+			env:     map[string]string{"UPDATE_MODE": "webhook", "WEBHOOK_URL": "https://example.com", "WEBHOOK_SECRET": "bad secret!"},
+			wantErr: true,
+		},
+		{
+			name:    "Unknown mode",
+			env:     map[string]string{"UPDATE_MODE": "carrier_pigeon"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"UPDATE_MODE", "WEBHOOK_URL", "WEBHOOK_SECRET", "WEBHOOK_LISTEN_ADDR", "PORT"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			config, err := LoadConfig()
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("LoadConfig() expected error, but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadConfig() unexpected error: %v", err)
+			}
+			if config.UpdateMode != tt.expectMode {
+				t.Errorf("UpdateMode = %v, want %v", config.UpdateMode, tt.expectMode)
+			}
+			if config.WebhookListenAddr != tt.expectListen {
+				t.Errorf("WebhookListenAddr = %v, want %v", config.WebhookListenAddr, tt.expectListen)
+			}
+		})
+	}
+}
+
+func TestLoadConfigSessionTTL(t *testing.T) {
+	t.Setenv("TELEGRAM_TOKEN", "token")
+	t.Setenv("DATABASE_URL", "url")
+
+	t.Run("Defaults", func(t *testing.T) {
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("LoadConfig() unexpected error: %v", err)
+		}
+		if config.SessionTTLReview != 30*time.Minute || config.SessionTTLAdmin != 2*time.Hour ||
+			config.SessionTTLSearch != 24*time.Hour || config.SessionSweepInterval != time.Minute {
+			t.Errorf("unexpected default TTLs: %+v", config)
+		}
+	})
+
+	t.Run("Custom values and disabled expiry", func(t *testing.T) {
+		t.Setenv("SESSION_TTL_REVIEW", "15m")
+		t.Setenv("SESSION_TTL_SEARCH", "0")
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("LoadConfig() unexpected error: %v", err)
+		}
+		if config.SessionTTLReview != 15*time.Minute {
+			t.Errorf("SessionTTLReview = %v, want 15m", config.SessionTTLReview)
+		}
+		if config.SessionTTLSearch != 0 {
+			t.Errorf("SessionTTLSearch = %v, want 0", config.SessionTTLSearch)
+		}
+	})
+
+	t.Run("Invalid duration", func(t *testing.T) {
+		t.Setenv("SESSION_TTL_ADMIN", "two hours")
+
+		if _, err := LoadConfig(); err == nil {
+			t.Errorf("LoadConfig() expected error, but got none")
+		}
+	})
+
+	t.Run("Zero sweep interval", func(t *testing.T) {
+		t.Setenv("SESSION_SWEEP_INTERVAL", "0s")
+
+		if _, err := LoadConfig(); err == nil {
+			t.Errorf("LoadConfig() expected error, but got none")
+		}
+	})
+}
+
+func TestLoadConfigUpdateWorkers(t *testing.T) {
+	t.Setenv("TELEGRAM_TOKEN", "token")
+	t.Setenv("DATABASE_URL", "url")
+
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() unexpected error: %v", err)
+	}
+	if config.UpdateWorkers != 8 || config.UpdateQueueSize != 100 {
+		t.Errorf("unexpected defaults: workers %d, queue %d", config.UpdateWorkers, config.UpdateQueueSize)
+	}
+
+	t.Setenv("UPDATE_WORKERS", "16")
+	config, err = LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() unexpected error: %v", err)
+	}
+	if config.UpdateWorkers != 16 {
+		t.Errorf("UpdateWorkers = %d, want 16", config.UpdateWorkers)
+	}
+
+	for _, value := range []string{"0", "-1", "many"} {
+		t.Setenv("UPDATE_WORKERS", value)
+		if _, err := LoadConfig(); err == nil {
+			t.Errorf("LoadConfig() with UPDATE_WORKERS=%q expected error", value)
+		}
+	}
+}
+
+func TestLoadConfigAPI(t *testing.T) {
+	t.Setenv("TELEGRAM_TOKEN", "token")
+	t.Setenv("DATABASE_URL", "url")
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("LoadConfig() unexpected error: %v", err)
+		}
+		if config.APIListenAddr != "" {
+			t.Errorf("APIListenAddr = %q, want empty", config.APIListenAddr)
+		}
+	})
+
+	t.Run("Enabled with default cache", func(t *testing.T) {
+		t.Setenv("API_LISTEN_ADDR", ":8081")
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("LoadConfig() unexpected error: %v", err)
+		}
+		if config.APIListenAddr != ":8081" || config.APICacheMaxAge != time.Minute {
+			t.Errorf("unexpected API config: %q, %v", config.APIListenAddr, config.APICacheMaxAge)
+		}
+	})
+
+	t.Run("Invalid cache duration", func(t *testing.T) {
+		t.Setenv("API_LISTEN_ADDR", ":8081")
+		t.Setenv("API_CACHE_MAX_AGE", "forever")
+
+		if _, err := LoadConfig(); err == nil {
+			t.Errorf("LoadConfig() expected error, but got none")
+		}
+	})
+
+	t.Run("Same address as webhook", func(t *testing.T) {
+		t.Setenv("UPDATE_MODE", "webhook")
+		t.Setenv("WEBHOOK_URL", "https://example.com/hook")
+		t.Setenv("WEBHOOK_SECRET", "secret")
+		t.Setenv("PORT", "9000")
+		t.Setenv("API_LISTEN_ADDR", ":9000")
+
+		if _, err := LoadConfig(); err == nil {
+			t.Errorf("LoadConfig() expected error, but got none")
+		}
+	})
+}
+
+func TestLoadConfigAdminAPI(t *testing.T) {
+	t.Setenv("TELEGRAM_TOKEN", "token")
+	t.Setenv("DATABASE_URL", "url")
+	t.Setenv("ADMIN_IDS", "111,222")
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("LoadConfig() unexpected error: %v", err)
+		}
+		if config.AdminAPIListenAddr != "" || config.AdminAPITokens != nil {
+			t.Errorf("unexpected admin API config: %q, %v", config.AdminAPIListenAddr, config.AdminAPITokens)
+		}
+	})
+
+	t.Run("Tokens mapped to admins", func(t *testing.T) {
+		t.Setenv("ADMIN_API_LISTEN_ADDR", ":8082")
+		t.Setenv("ADMIN_API_TOKENS", "111:first-token-0123456, 222:second-token-012345")
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("LoadConfig() unexpected error: %v", err)
+		}
+		if config.AdminAPITokens["first-token-0123456"] != 111 || config.AdminAPITokens["second-token-012345"] != 222 {
+			t.Errorf("unexpected tokens: %v", config.AdminAPITokens)
+		}
+	})
+
+	invalid := []struct {
+		name   string
+		tokens string
+		addr   string
+	}{
+		{"Missing tokens", "", ":8082"},
+		{"Malformed entry", "first-token-0123456", ":8082"},
+		{"Short token", "111:short", ":8082"},
+		{"Owner is not admin", "333:first-token-0123456", ":8082"},
+		{"Duplicate token", "111:first-token-0123456,222:first-token-0123456", ":8082"},
+		{"Same address as public API", "111:first-token-0123456", ":8081"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("API_LISTEN_ADDR", ":8081")
+			t.Setenv("ADMIN_API_LISTEN_ADDR", tt.addr)
+			t.Setenv("ADMIN_API_TOKENS", tt.tokens)
+
+			if _, err := LoadConfig(); err == nil {
+				t.Errorf("LoadConfig() expected error, but got none")
+			}
+		})
+	}
+}
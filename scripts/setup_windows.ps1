@@ -50,19 +50,8 @@ if (-not $dbHealthy) {
     Start-Sleep -Seconds 10
 }
 
-# Run migrations manually (since make might not work well on Windows)
-Write-Host "🗃️ Running database migrations..." -ForegroundColor Yellow
-$migrationFiles = Get-ChildItem "migrations" -Filter "*.sql" | Sort-Object Name
-foreach ($file in $migrationFiles) {
-    Write-Host "Applying $($file.Name)..." -ForegroundColor Cyan
-    docker exec -i vetbot_db psql -U vetbot_user -d vetbot -c "SELECT 1;" 2>$null
-    if ($LASTEXITCODE -eq 0) {
-        Get-Content $file.FullName | docker exec -i vetbot_db psql -U vetbot_user -d vetbot
-    } else {
-        Write-Host "❌ Database is not ready yet. Please run migrations manually later." -ForegroundColor Red
-        break
-    }
-}
+# Migrations are embedded into the binary and applied when the bot starts
+Write-Host "🗃️ Database migrations are applied automatically on bot start (vetbot migrate status)" -ForegroundColor Yellow
 
 # Build application
 Write-Host "🔨 Building application..." -ForegroundColor Yellow
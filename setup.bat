@@ -39,15 +39,11 @@ docker-compose -f docker-compose.windows.yml up -d postgres
 echo ⏳ Waiting for database to be ready...
 timeout /t 15 /nobreak
 
-echo 🗃️ Running database migrations...
-for %%f in (migrations\*.sql) do (
-    echo Applying %%~nxf...
-    type "%%f" | docker exec -i vetbot_db psql -U vetbot_user -d vetbot
-)
-
 echo 🔨 Building application...
 go build -o vetbot.exe ./cmd/vetbot
 
+echo 🗃️ Database migrations are applied automatically when the bot starts (vetbot.exe migrate status)
+
 if exist "vetbot.exe" (
     echo ✅ Application built successfully!
 ) else (